@@ -17,10 +17,14 @@ limitations under the License.
 package v1
 
 import (
+	"strings"
+
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 
@@ -89,7 +93,10 @@ type ComposedTemplate struct {
 	// +optional
 	Name *string `json:"name,omitempty"`
 
-	// Base is the target resource that the patches will be applied on.
+	// Base is the target resource that the patches will be applied on. It
+	// may be a managed resource, or a built-in Kubernetes object such as a
+	// ConfigMap, Namespace, or NetworkPolicy that Crossplane is permitted to
+	// manage.
 	// +kubebuilder:pruning:PreserveUnknownFields
 	// +kubebuilder:validation:EmbeddedResource
 	Base runtime.RawExtension `json:"base"`
@@ -106,9 +113,26 @@ type ComposedTemplate struct {
 	// ReadinessChecks allows users to define custom readiness checks. All checks
 	// have to return true in order for resource to be considered ready. The
 	// default readiness check is to have the "Ready" condition to be "True".
+	// Built-in Kubernetes objects that don't have a "Ready" condition pass
+	// this check once they exist.
 	// +optional
 	// +kubebuilder:default={{type:"MatchCondition",matchCondition:{type:"Ready",status:"True"}}}
 	ReadinessChecks []ReadinessCheck `json:"readinessChecks,omitempty"`
+
+	// ProgressDeadline is how long the composed resource may take to become
+	// ready after it's created. The composite resource's
+	// ComposedResourceTimeout condition names the composed resources that
+	// aren't ready within their progress deadline. Composed resources are
+	// given as long as they need by default.
+	// +optional
+	ProgressDeadline *metav1.Duration `json:"progressDeadline,omitempty"`
+
+	// Paused stops Crossplane from creating or updating the composed
+	// resource. An existing composed resource is left untouched, but its
+	// readiness and connection details are still observed. This is useful
+	// to stop reconciling one composed resource without deleting it.
+	// +optional
+	Paused *bool `json:"paused,omitempty"`
 }
 
 // GetName returns the name of the composed template or an empty string if it is nil.
@@ -119,6 +143,11 @@ func (ct *ComposedTemplate) GetName() string {
 	return ""
 }
 
+// IsPaused returns true if the composed template is paused.
+func (ct *ComposedTemplate) IsPaused() bool {
+	return ptr.Deref(ct.Paused, false)
+}
+
 // ReadinessCheckType is used for readiness check types.
 type ReadinessCheckType string
 
@@ -229,6 +258,57 @@ func (r *ReadinessCheck) Validate() *field.Error {
 	return nil
 }
 
+// A RemovedResourcePolicy specifies what happens to a composed resource that
+// is no longer composed.
+type RemovedResourcePolicy string
+
+// Removed resource policies.
+const (
+	// RemovedResourcePolicyDelete deletes composed resources that are no
+	// longer composed.
+	RemovedResourcePolicyDelete RemovedResourcePolicy = "Delete"
+
+	// RemovedResourcePolicyOrphan leaves composed resources that are no
+	// longer composed in place, and stops controlling them.
+	RemovedResourcePolicyOrphan RemovedResourcePolicy = "Orphan"
+)
+
+// MetadataPropagation configures which labels and annotations of a composite
+// resource are propagated to its composed resources. Each entry is either a
+// key, or a key prefix followed by "*", for example "example.org/*".
+// Labels and annotations set by a composed resource's template take
+// precedence over propagated ones.
+type MetadataPropagation struct {
+	// Labels to propagate.
+	// +optional
+	Labels []string `json:"labels,omitempty"`
+
+	// Annotations to propagate.
+	// +optional
+	Annotations []string `json:"annotations,omitempty"`
+}
+
+// Validate checks that each key is either a key or a key prefix followed by a
+// single trailing "*".
+func (m *MetadataPropagation) Validate() field.ErrorList {
+	errs := validatePropagatedKeys(field.NewPath("labels"), m.Labels)
+	return append(errs, validatePropagatedKeys(field.NewPath("annotations"), m.Annotations)...)
+}
+
+func validatePropagatedKeys(p *field.Path, keys []string) field.ErrorList {
+	var errs field.ErrorList
+	for i, k := range keys {
+		if k == "" {
+			errs = append(errs, field.Required(p.Index(i), "cannot be empty"))
+			continue
+		}
+		if strings.Contains(strings.TrimSuffix(k, "*"), "*") {
+			errs = append(errs, field.Invalid(p.Index(i), k, "\"*\" is only allowed at the end of a key"))
+		}
+	}
+	return errs
+}
+
 // A ConnectionDetailType is a type of connection detail.
 type ConnectionDetailType string
 
@@ -35,7 +35,22 @@ func (c Composition) Hash() string {
 		return "unknown"
 	}
 
-	a, err := yaml.Marshal(c.ObjectMeta.Annotations)
+	// The revision history limit doesn't affect how resources are composed, so
+	// changing it shouldn't produce a new revision.
+	annotations := c.ObjectMeta.Annotations
+	if _, ok := annotations[AnnotationRevisionHistoryLimit]; ok {
+		annotations = make(map[string]string, len(c.ObjectMeta.Annotations))
+		for k, v := range c.ObjectMeta.Annotations {
+			if k != AnnotationRevisionHistoryLimit {
+				annotations[k] = v
+			}
+		}
+		if len(annotations) == 0 {
+			annotations = nil
+		}
+	}
+
+	a, err := yaml.Marshal(annotations)
 	if err != nil {
 		return "unknown"
 	}
@@ -21,8 +21,11 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/ptr"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 )
 
 func TestPatchValidate(t *testing.T) {
@@ -149,6 +152,77 @@ func TestPatchValidate(t *testing.T) {
 				},
 			},
 		},
+		"InvalidToFieldPathPolicyWithMergeOptions": {
+			reason: "A patch policy can't set both toFieldPath and mergeOptions",
+			args: args{
+				patch: &Patch{
+					Type:          PatchTypeToCompositeFieldPath,
+					FromFieldPath: ptr.To("status.atProvider.endpoints"),
+					Policy: &PatchPolicy{
+						ToFieldPath:  ptr.To(ToFieldPathPolicyMergeObjects),
+						MergeOptions: &xpv1.MergeOptions{AppendSlice: ptr.To(true)},
+					},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeForbidden,
+					Field: "policy.mergeOptions",
+				},
+			},
+		},
+		"ValidCondition": {
+			reason: "A patch with a valid condition should be valid",
+			args: args{
+				patch: &Patch{
+					Type:          PatchTypeFromCompositeFieldPath,
+					FromFieldPath: ptr.To("spec.parameters.size"),
+					Condition: &PatchCondition{
+						FieldPath: "spec.parameters.tier",
+						Values:    []extv1.JSON{{Raw: []byte(`"prod"`)}},
+					},
+				},
+			},
+		},
+		"InvalidConditionMissingValues": {
+			reason: "A patch condition using the In operator must specify values",
+			args: args{
+				patch: &Patch{
+					Type:          PatchTypeFromCompositeFieldPath,
+					FromFieldPath: ptr.To("spec.parameters.size"),
+					Condition: &PatchCondition{
+						FieldPath: "spec.parameters.tier",
+						Operator:  PatchConditionOperatorIn,
+					},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "condition.values",
+				},
+			},
+		},
+		"InvalidConditionExistsWithValues": {
+			reason: "A patch condition using the Exists operator can't specify values",
+			args: args{
+				patch: &Patch{
+					Type:          PatchTypeFromCompositeFieldPath,
+					FromFieldPath: ptr.To("spec.parameters.size"),
+					Condition: &PatchCondition{
+						FieldPath: "spec.parameters.tier",
+						Operator:  PatchConditionOperatorExists,
+						Values:    []extv1.JSON{{Raw: []byte(`"prod"`)}},
+					},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeForbidden,
+					Field: "condition.values",
+				},
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -159,3 +233,53 @@ func TestPatchValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestPatchPolicyGetMergeOptions(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		pp     *PatchPolicy
+		want   *xpv1.MergeOptions
+	}{
+		"NilPolicy": {
+			reason: "A nil policy should replace.",
+			pp:     nil,
+		},
+		"MergeOptions": {
+			reason: "MergeOptions should be used as is if no toFieldPath policy is set.",
+			pp:     &PatchPolicy{MergeOptions: &xpv1.MergeOptions{KeepMapValues: ptr.To(true)}},
+			want:   &xpv1.MergeOptions{KeepMapValues: ptr.To(true)},
+		},
+		"Replace": {
+			reason: "The Replace policy should replace.",
+			pp:     &PatchPolicy{ToFieldPath: ptr.To(ToFieldPathPolicyReplace)},
+		},
+		"MergeObjects": {
+			reason: "The MergeObjects policy should keep map values that are already set.",
+			pp:     &PatchPolicy{ToFieldPath: ptr.To(ToFieldPathPolicyMergeObjects)},
+			want:   &xpv1.MergeOptions{KeepMapValues: ptr.To(true)},
+		},
+		"MergeObjectsAppendArrays": {
+			reason: "The MergeObjectsAppendArrays policy should keep map values that are already set, and append slices.",
+			pp:     &PatchPolicy{ToFieldPath: ptr.To(ToFieldPathPolicyMergeObjectsAppendArrays)},
+			want:   &xpv1.MergeOptions{KeepMapValues: ptr.To(true), AppendSlice: ptr.To(true)},
+		},
+		"ForceMergeObjects": {
+			reason: "The ForceMergeObjects policy should overwrite map values that are already set.",
+			pp:     &PatchPolicy{ToFieldPath: ptr.To(ToFieldPathPolicyForceMergeObjects)},
+			want:   &xpv1.MergeOptions{},
+		},
+		"ForceMergeObjectsAppendArrays": {
+			reason: "The ForceMergeObjectsAppendArrays policy should overwrite map values that are already set, and append slices.",
+			pp:     &PatchPolicy{ToFieldPath: ptr.To(ToFieldPathPolicyForceMergeObjectsAppendArrays)},
+			want:   &xpv1.MergeOptions{AppendSlice: ptr.To(true)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.pp.GetMergeOptions()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("%s\nGetMergeOptions(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -221,6 +221,29 @@ func TestTransformValidate(t *testing.T) {
 				},
 			},
 		},
+		"InvalidMatchTransformLiteralExpandCaptureGroups": {
+			reason: "Match transform that expands capture groups of a pattern of type literal should be invalid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeMatch,
+					Match: &MatchTransform{
+						Patterns: []MatchTransformPattern{
+							{
+								Type:                MatchTransformPatternTypeLiteral,
+								Literal:             ptr.To("foo"),
+								ExpandCaptureGroups: ptr.To(true),
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeForbidden,
+					Field: "match.patterns[0].expandCaptureGroups",
+				},
+			},
+		},
 		"ValidMatchTransformString": {
 			reason: "Match transform with valid MatchTransform of type literal should be valid",
 			args: args{
@@ -327,6 +350,128 @@ func TestTransformValidate(t *testing.T) {
 				},
 			},
 		},
+		"ValidTemplate": {
+			reason: "Template transform with valid text should be valid",
+			args: args{
+				transform: &Transform{
+					Type:     TransformTypeTemplate,
+					Template: &TemplateTransform{Text: "{{ . | lower | trunc 63 }}"},
+				},
+			},
+		},
+		"InvalidTemplateMissingConfig": {
+			reason: "Template transform without configuration should be invalid",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeTemplate,
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "template",
+				},
+			},
+		},
+		"InvalidTemplateMissingText": {
+			reason: "Template transform without text should be invalid",
+			args: args{
+				transform: &Transform{
+					Type:     TransformTypeTemplate,
+					Template: &TemplateTransform{},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "template.text",
+				},
+			},
+		},
+		"InvalidTemplateSyntax": {
+			reason: "Template transform with text that isn't a valid template should be invalid",
+			args: args{
+				transform: &Transform{
+					Type:     TransformTypeTemplate,
+					Template: &TemplateTransform{Text: "{{ . | lower "},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "template.text",
+				},
+			},
+		},
+		"InvalidTemplateRange": {
+			reason: "Template transform that ranges, e.g. over a large integer, should be invalid",
+			args: args{
+				transform: &Transform{
+					Type:     TransformTypeTemplate,
+					Template: &TemplateTransform{Text: `{{ range 2000000000 }}{{ end }}`},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "template.text",
+				},
+			},
+		},
+		"InvalidTemplateNestedRange": {
+			reason: "Template transform that ranges within a conditional should be invalid",
+			args: args{
+				transform: &Transform{
+					Type:     TransformTypeTemplate,
+					Template: &TemplateTransform{Text: `{{ if . }}{{ range . }}{{ . }}{{ end }}{{ end }}`},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "template.text",
+				},
+			},
+		},
+		"InvalidTemplateDefine": {
+			reason: "Template transform that defines and invokes templates should be invalid",
+			args: args{
+				transform: &Transform{
+					Type:     TransformTypeTemplate,
+					Template: &TemplateTransform{Text: `{{ define "loop" }}{{ template "loop" . }}{{ end }}{{ template "loop" . }}`},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "template.text",
+				},
+			},
+		},
+		"InvalidTemplateUnknownFunction": {
+			reason: "Template transform that calls a function that isn't available should be invalid",
+			args: args{
+				transform: &Transform{
+					Type:     TransformTypeTemplate,
+					Template: &TemplateTransform{Text: "{{ . | upper }}"},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "template.text",
+				},
+			},
+		},
+		"ValidTemplateBuiltinFunction": {
+			reason: "Template transform that calls one of Go's builtin template functions should be valid",
+			args: args{
+				transform: &Transform{
+					Type:     TransformTypeTemplate,
+					Template: &TemplateTransform{Text: "{{ printf \"%s-%s\" . (sha1sum .) }}"},
+				},
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -374,6 +519,17 @@ func TestTransformGetOutputType(t *testing.T) {
 				output: &[]TransformIOType{"fakeType"}[0],
 			},
 		},
+		"TemplateTransform": {
+			reason: "Output of Template transform should be string",
+			args: args{
+				transform: &Transform{
+					Type: TransformTypeTemplate,
+				},
+			},
+			want: want{
+				output: &[]TransformIOType{TransformIOTypeString}[0],
+			},
+		},
 		"ErrorUnknownType": {
 			reason: "Output of Unknown transform type returns an error",
 			args: args{
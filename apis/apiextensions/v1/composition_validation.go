@@ -33,6 +33,8 @@ func (c *Composition) Validate() (warns []string, errs field.ErrorList) {
 		c.validateResources,
 		c.validatePipeline,
 		c.validateEnvironment,
+		c.validateReadinessChecks,
+		c.validatePropagateMetadata,
 	}
 	for _, f := range validations {
 		errs = append(errs, f()...)
@@ -126,6 +128,25 @@ func (c *Composition) validatePatchSets() (errs field.ErrorList) {
 	return errs
 }
 
+func (c *Composition) validateReadinessChecks() (errs field.ErrorList) {
+	for i, rd := range c.Spec.ReadinessChecks {
+		if err := rd.Validate(); err != nil {
+			errs = append(errs, verrors.WrapFieldError(err, field.NewPath("spec", "readinessChecks").Index(i)))
+		}
+	}
+	return errs
+}
+
+func (c *Composition) validatePropagateMetadata() (errs field.ErrorList) {
+	if c.Spec.PropagateMetadata == nil {
+		return nil
+	}
+	for _, err := range c.Spec.PropagateMetadata.Validate() {
+		errs = append(errs, verrors.WrapFieldError(err, field.NewPath("spec", "propagateMetadata")))
+	}
+	return errs
+}
+
 func (c *Composition) validateResources() (errs field.ErrorList) {
 	if err := c.validateResourceNames(); err != nil {
 		errs = append(errs, err...)
@@ -582,6 +582,147 @@ func TestCompositionValidatePipeline(t *testing.T) {
 	}
 }
 
+func TestCompositionValidateReadinessChecks(t *testing.T) {
+	type args struct {
+		comp *Composition
+	}
+	type want struct {
+		output field.ErrorList
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ValidNoReadinessChecks": {
+			reason: "no readiness checks should be valid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{},
+				},
+			},
+		},
+		"ValidReadinessChecks": {
+			reason: "readiness checks with valid configuration should be valid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						ReadinessChecks: []ReadinessCheck{
+							{
+								Type:      ReadinessCheckTypeNonEmpty,
+								FieldPath: "status.endpoint",
+							},
+						},
+					},
+				},
+			},
+		},
+		"InvalidMissingFieldPath": {
+			reason: "A NonEmpty readiness check must specify a field path",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						ReadinessChecks: []ReadinessCheck{
+							{
+								Type:      ReadinessCheckTypeNonEmpty,
+								FieldPath: "status.endpoint",
+							},
+							{
+								Type: ReadinessCheckTypeNonEmpty,
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeRequired,
+						Field: "spec.readinessChecks[1].fieldPath",
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			gotErrs := tc.args.comp.validateReadinessChecks()
+			if diff := cmp.Diff(tc.want.output, gotErrs, sortFieldErrors(), cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("%s\nvalidateReadinessChecks(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCompositionValidatePropagateMetadata(t *testing.T) {
+	type args struct {
+		comp *Composition
+	}
+	type want struct {
+		output field.ErrorList
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ValidNoPropagation": {
+			reason: "no metadata propagation should be valid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{},
+				},
+			},
+		},
+		"ValidKeys": {
+			reason: "keys and key prefixes ending in * should be valid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						PropagateMetadata: &MetadataPropagation{
+							Labels:      []string{"team", "billing.example.org/*"},
+							Annotations: []string{"*"},
+						},
+					},
+				},
+			},
+		},
+		"InvalidKeys": {
+			reason: "empty keys and keys with a * anywhere but the end should be invalid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						PropagateMetadata: &MetadataPropagation{
+							Labels:      []string{"team", ""},
+							Annotations: []string{"example.org/*-owner"},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeRequired,
+						Field: "spec.propagateMetadata.labels[1]",
+					},
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.propagateMetadata.annotations[0]",
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			gotErrs := tc.args.comp.validatePropagateMetadata()
+			if diff := cmp.Diff(tc.want.output, gotErrs, sortFieldErrors(), cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("%s\nvalidatePropagateMetadata(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestCompositionValidateResources(t *testing.T) {
 	type args struct {
 		comp *Composition
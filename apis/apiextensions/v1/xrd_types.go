@@ -56,6 +56,12 @@ type CompositeResourceDefinitionSpec struct {
 	// +optional
 	ConnectionSecretKeys []string `json:"connectionSecretKeys,omitempty"`
 
+	// ClaimConnectionSecretPolicy configures how connection details are
+	// propagated from composite resources to their claims. By default they're
+	// propagated only to claims that specify writeConnectionSecretToRef.
+	// +optional
+	ClaimConnectionSecretPolicy *ClaimConnectionSecretPolicy `json:"claimConnectionSecretPolicy,omitempty"`
+
 	// DefaultCompositeDeletePolicy is the policy used when deleting the Composite
 	// that is associated with the Claim if no policy has been specified.
 	// +optional
@@ -79,6 +85,13 @@ type CompositeResourceDefinitionSpec struct {
 	// +kubebuilder:default=Automatic
 	DefaultCompositionUpdatePolicy *xpv1.UpdatePolicy `json:"defaultCompositionUpdatePolicy,omitempty"`
 
+	// ImmutableFields is a list of field paths, e.g. spec.parameters.region,
+	// that can't be changed once a composite resource or claim of this kind
+	// is created. Use it to protect parameters that would force destructive
+	// re-creation of composed resources. It's enforced by a webhook.
+	// +optional
+	ImmutableFields []string `json:"immutableFields,omitempty"`
+
 	// Versions is the list of all API versions of the defined composite
 	// resource. Version names are used to compute the order in which served
 	// versions are listed in API discovery. If the version string is
@@ -101,6 +114,45 @@ type CompositeResourceDefinitionSpec struct {
 	Metadata *CompositeResourceDefinitionSpecMetadata `json:"metadata,omitempty"`
 }
 
+// A ClaimConnectionSecretMode determines which claims receive the connection
+// details of their composite resource.
+type ClaimConnectionSecretMode string
+
+// Claim connection secret modes.
+const (
+	// ClaimConnectionSecretExplicit propagates connection details only to
+	// claims that specify writeConnectionSecretToRef.
+	ClaimConnectionSecretExplicit ClaimConnectionSecretMode = "Explicit"
+
+	// ClaimConnectionSecretAutomatic propagates connection details to every
+	// claim. Claims that don't specify writeConnectionSecretToRef receive
+	// them in a Secret with the same name as the claim.
+	ClaimConnectionSecretAutomatic ClaimConnectionSecretMode = "Automatic"
+
+	// ClaimConnectionSecretNone never propagates connection details to
+	// claims, even if they specify writeConnectionSecretToRef.
+	ClaimConnectionSecretNone ClaimConnectionSecretMode = "None"
+)
+
+// A ClaimConnectionSecretPolicy configures how connection details are
+// propagated from composite resources to their claims.
+type ClaimConnectionSecretPolicy struct {
+	// Mode determines which claims receive connection details. Explicit
+	// claims receive them only if they specify writeConnectionSecretToRef.
+	// Automatic claims always receive them, in a Secret with the same name
+	// as the claim unless they specify writeConnectionSecretToRef. Claims
+	// never receive them if the mode is None.
+	// +optional
+	// +kubebuilder:validation:Enum=Explicit;Automatic;None
+	// +kubebuilder:default=Explicit
+	Mode ClaimConnectionSecretMode `json:"mode,omitempty"`
+
+	// Keys is the list of connection secret keys claims receive. If the list
+	// is empty, claims receive every key their composite resource exposes.
+	// +optional
+	Keys []string `json:"keys,omitempty"`
+}
+
 // A CompositionReference references a Composition.
 type CompositionReference struct {
 	// Name of the Composition.
@@ -277,6 +329,24 @@ func (c CompositeResourceDefinition) GetClaimGroupVersionKind() schema.GroupVers
 	return schema.GroupVersionKind{Group: c.Spec.Group, Version: v, Kind: c.Spec.ClaimNames.Kind}
 }
 
+// GetClaimConnectionSecretMode returns the mode that determines which claims
+// receive connection details. It defaults to Explicit.
+func (c *CompositeResourceDefinition) GetClaimConnectionSecretMode() ClaimConnectionSecretMode {
+	if c.Spec.ClaimConnectionSecretPolicy == nil || c.Spec.ClaimConnectionSecretPolicy.Mode == "" {
+		return ClaimConnectionSecretExplicit
+	}
+	return c.Spec.ClaimConnectionSecretPolicy.Mode
+}
+
+// GetClaimConnectionSecretKeys returns the connection secret keys claims
+// receive. All keys are received if it's empty.
+func (c *CompositeResourceDefinition) GetClaimConnectionSecretKeys() []string {
+	if c.Spec.ClaimConnectionSecretPolicy == nil {
+		return nil
+	}
+	return c.Spec.ClaimConnectionSecretPolicy.Keys
+}
+
 // GetConnectionSecretKeys returns the set of allowed keys to filter the connection
 // secret.
 func (c *CompositeResourceDefinition) GetConnectionSecretKeys() []string {
@@ -5,6 +5,8 @@ import (
 
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 )
 
 // Validate checks that the supplied CompositeResourceDefinition spec is logically valid.
@@ -12,6 +14,7 @@ func (c *CompositeResourceDefinition) Validate() (warns []string, errs field.Err
 	type validationFunc func() field.ErrorList
 	validations := []validationFunc{
 		c.validateConversion,
+		c.validateImmutableFields,
 	}
 	for _, f := range validations {
 		errs = append(errs, f()...)
@@ -29,6 +32,17 @@ func (c *CompositeResourceDefinition) validateConversion() (errs field.ErrorList
 	return errs
 }
 
+// validateImmutableFields checks that the supplied CompositeResourceDefinition's
+// immutable fields are valid field paths.
+func (c *CompositeResourceDefinition) validateImmutableFields() (errs field.ErrorList) {
+	for i, p := range c.Spec.ImmutableFields {
+		if _, err := fieldpath.Parse(p); err != nil {
+			errs = append(errs, field.Invalid(field.NewPath("spec", "immutableFields").Index(i), p, err.Error()))
+		}
+	}
+	return errs
+}
+
 // ValidateUpdate checks that the supplied CompositeResourceDefinition update is valid w.r.t. the old one.
 func (c *CompositeResourceDefinition) ValidateUpdate(old *CompositeResourceDefinition) (warns []string, errs field.ErrorList) {
 	// Validate the update
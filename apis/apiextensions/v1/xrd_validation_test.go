@@ -76,6 +76,42 @@ func TestValidateConversion(t *testing.T) {
 	}
 }
 
+func TestValidateImmutableFields(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		c      *CompositeResourceDefinition
+		want   field.ErrorList
+	}{
+		"Valid": {
+			reason: "A CompositeResourceDefinition with valid immutable field paths should be accepted",
+			c: &CompositeResourceDefinition{
+				Spec: CompositeResourceDefinitionSpec{
+					ImmutableFields: []string{"spec.parameters.region", "spec.parameters.zones[0]"},
+				},
+			},
+		},
+		"Invalid": {
+			reason: "A CompositeResourceDefinition with an invalid immutable field path should be rejected",
+			c: &CompositeResourceDefinition{
+				Spec: CompositeResourceDefinitionSpec{
+					ImmutableFields: []string{"spec.parameters.region", "spec.parameters[region"},
+				},
+			},
+			want: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "immutableFields").Index(1), "spec.parameters[region", ""),
+			},
+		},
+	}
+	for tcName, tc := range cases {
+		t.Run(tcName, func(t *testing.T) {
+			got := tc.c.validateImmutableFields()
+			if diff := cmp.Diff(tc.want, got, sortFieldErrors(), cmpopts.IgnoreFields(field.Error{}, "Detail")); diff != "" {
+				t.Errorf("\n%s\nValidateImmutableFields(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestValidateUpdate(t *testing.T) {
 	type args struct {
 		old *CompositeResourceDefinition
@@ -7,7 +7,9 @@ import (
 	v11 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	v12 "k8s.io/api/core/v1"
 	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	v13 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"time"
 )
 
 type GeneratedRevisionSpecConverter struct{}
@@ -46,6 +48,21 @@ func (c *GeneratedRevisionSpecConverter) FromRevisionSpec(source CompositionRevi
 		}
 	}
 	v1CompositionSpec.Pipeline = v1PipelineStepList
+	var v1ReadinessCheckList []ReadinessCheck
+	if source.ReadinessChecks != nil {
+		v1ReadinessCheckList = make([]ReadinessCheck, len(source.ReadinessChecks))
+		for l := 0; l < len(source.ReadinessChecks); l++ {
+			v1ReadinessCheckList[l] = c.v1ReadinessCheckToV1ReadinessCheck(source.ReadinessChecks[l])
+		}
+	}
+	v1CompositionSpec.ReadinessChecks = v1ReadinessCheckList
+	v1CompositionSpec.PropagateMetadata = c.pV1MetadataPropagationToPV1MetadataPropagation(source.PropagateMetadata)
+	var pV1RemovedResourcePolicy *RemovedResourcePolicy
+	if source.RemovedResourcePolicy != nil {
+		v1RemovedResourcePolicy := RemovedResourcePolicy(*source.RemovedResourcePolicy)
+		pV1RemovedResourcePolicy = &v1RemovedResourcePolicy
+	}
+	v1CompositionSpec.RemovedResourcePolicy = pV1RemovedResourcePolicy
 	var pString *string
 	if source.WriteConnectionSecretsToNamespace != nil {
 		xstring := *source.WriteConnectionSecretsToNamespace
@@ -89,6 +106,21 @@ func (c *GeneratedRevisionSpecConverter) ToRevisionSpec(source CompositionSpec)
 		}
 	}
 	v1CompositionRevisionSpec.Pipeline = v1PipelineStepList
+	var v1ReadinessCheckList []ReadinessCheck
+	if source.ReadinessChecks != nil {
+		v1ReadinessCheckList = make([]ReadinessCheck, len(source.ReadinessChecks))
+		for l := 0; l < len(source.ReadinessChecks); l++ {
+			v1ReadinessCheckList[l] = c.v1ReadinessCheckToV1ReadinessCheck(source.ReadinessChecks[l])
+		}
+	}
+	v1CompositionRevisionSpec.ReadinessChecks = v1ReadinessCheckList
+	v1CompositionRevisionSpec.PropagateMetadata = c.pV1MetadataPropagationToPV1MetadataPropagation(source.PropagateMetadata)
+	var pV1RemovedResourcePolicy *RemovedResourcePolicy
+	if source.RemovedResourcePolicy != nil {
+		v1RemovedResourcePolicy := RemovedResourcePolicy(*source.RemovedResourcePolicy)
+		pV1RemovedResourcePolicy = &v1RemovedResourcePolicy
+	}
+	v1CompositionRevisionSpec.RemovedResourcePolicy = pV1RemovedResourcePolicy
 	var pString *string
 	if source.WriteConnectionSecretsToNamespace != nil {
 		xstring := *source.WriteConnectionSecretsToNamespace
@@ -139,6 +171,14 @@ func (c *GeneratedRevisionSpecConverter) pV1ConvertTransformToPV1ConvertTransfor
 	}
 	return pV1ConvertTransform
 }
+func (c *GeneratedRevisionSpecConverter) pV1DurationToPV1Duration(source *v13.Duration) *v13.Duration {
+	var pV1Duration *v13.Duration
+	if source != nil {
+		v1Duration := c.v1DurationToV1Duration((*source))
+		pV1Duration = &v1Duration
+	}
+	return pV1Duration
+}
 func (c *GeneratedRevisionSpecConverter) pV1EnvironmentConfigurationToPV1EnvironmentConfiguration(source *EnvironmentConfiguration) *EnvironmentConfiguration {
 	var pV1EnvironmentConfiguration *EnvironmentConfiguration
 	if source != nil {
@@ -302,6 +342,38 @@ func (c *GeneratedRevisionSpecConverter) pV1MergeOptionsToPV1MergeOptions(source
 	}
 	return pV1MergeOptions
 }
+func (c *GeneratedRevisionSpecConverter) pV1MetadataPropagationToPV1MetadataPropagation(source *MetadataPropagation) *MetadataPropagation {
+	var pV1MetadataPropagation *MetadataPropagation
+	if source != nil {
+		var v1MetadataPropagation MetadataPropagation
+		var stringList []string
+		if (*source).Labels != nil {
+			stringList = make([]string, len((*source).Labels))
+			for i := 0; i < len((*source).Labels); i++ {
+				stringList[i] = (*source).Labels[i]
+			}
+		}
+		v1MetadataPropagation.Labels = stringList
+		var stringList2 []string
+		if (*source).Annotations != nil {
+			stringList2 = make([]string, len((*source).Annotations))
+			for j := 0; j < len((*source).Annotations); j++ {
+				stringList2[j] = (*source).Annotations[j]
+			}
+		}
+		v1MetadataPropagation.Annotations = stringList2
+		pV1MetadataPropagation = &v1MetadataPropagation
+	}
+	return pV1MetadataPropagation
+}
+func (c *GeneratedRevisionSpecConverter) pV1PatchConditionToPV1PatchCondition(source *PatchCondition) *PatchCondition {
+	var pV1PatchCondition *PatchCondition
+	if source != nil {
+		v1PatchCondition := c.v1PatchConditionToV1PatchCondition((*source))
+		pV1PatchCondition = &v1PatchCondition
+	}
+	return pV1PatchCondition
+}
 func (c *GeneratedRevisionSpecConverter) pV1PatchPolicyToPV1PatchPolicy(source *PatchPolicy) *PatchPolicy {
 	var pV1PatchPolicy *PatchPolicy
 	if source != nil {
@@ -312,6 +384,12 @@ func (c *GeneratedRevisionSpecConverter) pV1PatchPolicyToPV1PatchPolicy(source *
 			pV1FromFieldPathPolicy = &v1FromFieldPathPolicy
 		}
 		v1PatchPolicy.FromFieldPath = pV1FromFieldPathPolicy
+		var pV1ToFieldPathPolicy *ToFieldPathPolicy
+		if (*source).ToFieldPath != nil {
+			v1ToFieldPathPolicy := ToFieldPathPolicy(*(*source).ToFieldPath)
+			pV1ToFieldPathPolicy = &v1ToFieldPathPolicy
+		}
+		v1PatchPolicy.ToFieldPath = pV1ToFieldPathPolicy
 		v1PatchPolicy.MergeOptions = c.pV1MergeOptionsToPV1MergeOptions((*source).MergeOptions)
 		pV1PatchPolicy = &v1PatchPolicy
 	}
@@ -418,6 +496,15 @@ func (c *GeneratedRevisionSpecConverter) pV1StringTransformToPV1StringTransform(
 	}
 	return pV1StringTransform
 }
+func (c *GeneratedRevisionSpecConverter) pV1TemplateTransformToPV1TemplateTransform(source *TemplateTransform) *TemplateTransform {
+	var pV1TemplateTransform *TemplateTransform
+	if source != nil {
+		var v1TemplateTransform TemplateTransform
+		v1TemplateTransform.Text = (*source).Text
+		pV1TemplateTransform = &v1TemplateTransform
+	}
+	return pV1TemplateTransform
+}
 func (c *GeneratedRevisionSpecConverter) v1CombineVariableToV1CombineVariable(source CombineVariable) CombineVariable {
 	var v1CombineVariable CombineVariable
 	v1CombineVariable.FromFieldPath = source.FromFieldPath
@@ -456,6 +543,13 @@ func (c *GeneratedRevisionSpecConverter) v1ComposedTemplateToV1ComposedTemplate(
 		}
 	}
 	v1ComposedTemplate.ReadinessChecks = v1ReadinessCheckList
+	v1ComposedTemplate.ProgressDeadline = c.pV1DurationToPV1Duration(source.ProgressDeadline)
+	var pBool *bool
+	if source.Paused != nil {
+		xbool := *source.Paused
+		pBool = &xbool
+	}
+	v1ComposedTemplate.Paused = pBool
 	return v1ComposedTemplate
 }
 func (c *GeneratedRevisionSpecConverter) v1ConnectionDetailToV1ConnectionDetail(source ConnectionDetail) ConnectionDetail {
@@ -492,6 +586,11 @@ func (c *GeneratedRevisionSpecConverter) v1ConnectionDetailToV1ConnectionDetail(
 	v1ConnectionDetail.Value = pString4
 	return v1ConnectionDetail
 }
+func (c *GeneratedRevisionSpecConverter) v1DurationToV1Duration(source v13.Duration) v13.Duration {
+	var v1Duration v13.Duration
+	v1Duration.Duration = time.Duration(source.Duration)
+	return v1Duration
+}
 func (c *GeneratedRevisionSpecConverter) v1EnvironmentPatchToV1EnvironmentPatch(source EnvironmentPatch) EnvironmentPatch {
 	var v1EnvironmentPatch EnvironmentPatch
 	v1EnvironmentPatch.Type = PatchType(source.Type)
@@ -590,8 +689,28 @@ func (c *GeneratedRevisionSpecConverter) v1MatchTransformPatternToV1MatchTransfo
 	}
 	v1MatchTransformPattern.Regexp = pString2
 	v1MatchTransformPattern.Result = c.v1JSONToV1JSON(source.Result)
+	var pBool *bool
+	if source.ExpandCaptureGroups != nil {
+		xbool := *source.ExpandCaptureGroups
+		pBool = &xbool
+	}
+	v1MatchTransformPattern.ExpandCaptureGroups = pBool
 	return v1MatchTransformPattern
 }
+func (c *GeneratedRevisionSpecConverter) v1PatchConditionToV1PatchCondition(source PatchCondition) PatchCondition {
+	var v1PatchCondition PatchCondition
+	v1PatchCondition.FieldPath = source.FieldPath
+	v1PatchCondition.Operator = PatchConditionOperator(source.Operator)
+	var v1JSONList []v1.JSON
+	if source.Values != nil {
+		v1JSONList = make([]v1.JSON, len(source.Values))
+		for i := 0; i < len(source.Values); i++ {
+			v1JSONList[i] = c.v1JSONToV1JSON(source.Values[i])
+		}
+	}
+	v1PatchCondition.Values = v1JSONList
+	return v1PatchCondition
+}
 func (c *GeneratedRevisionSpecConverter) v1PatchSetToV1PatchSet(source PatchSet) PatchSet {
 	var v1PatchSet PatchSet
 	v1PatchSet.Name = source.Name
@@ -636,6 +755,7 @@ func (c *GeneratedRevisionSpecConverter) v1PatchToV1Patch(source Patch) Patch {
 	}
 	v1Patch.Transforms = v1TransformList
 	v1Patch.Policy = c.pV1PatchPolicyToPV1PatchPolicy(source.Policy)
+	v1Patch.Condition = c.pV1PatchConditionToPV1PatchCondition(source.Condition)
 	return v1Patch
 }
 func (c *GeneratedRevisionSpecConverter) v1PipelineStepToV1PipelineStep(source PipelineStep) PipelineStep {
@@ -670,6 +790,7 @@ func (c *GeneratedRevisionSpecConverter) v1TransformToV1Transform(source Transfo
 	v1Transform.Match = c.pV1MatchTransformToPV1MatchTransform(source.Match)
 	v1Transform.String = c.pV1StringTransformToPV1StringTransform(source.String)
 	v1Transform.Convert = c.pV1ConvertTransformToPV1ConvertTransform(source.Convert)
+	v1Transform.Template = c.pV1TemplateTransformToPV1TemplateTransform(source.Template)
 	return v1Transform
 }
 func (c *GeneratedRevisionSpecConverter) v1TypeReferenceToV1TypeReference(source TypeReference) TypeReference {
@@ -0,0 +1,67 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CompositionRevisionOverrideSpec defines the desired state of a
+// CompositionRevisionOverride.
+type CompositionRevisionOverrideSpec struct {
+	// Selector selects the composite resources to pin by their labels. A
+	// claim's labels are propagated to its composite resource, so claims can
+	// be selected by their labels too, or by the crossplane.io/claim-name and
+	// crossplane.io/claim-namespace labels.
+	Selector metav1.LabelSelector `json:"selector"`
+
+	// CompositionRevisionRef references the CompositionRevision that the
+	// selected composite resources are pinned to. Only composite resources of
+	// the revision's composite type are pinned.
+	CompositionRevisionRef ResourceRef `json:"compositionRevisionRef"`
+}
+
+// A CompositionRevisionOverride pins the composite resources and claims it
+// selects to a CompositionRevision, regardless of the Composition, revision
+// reference, or update policy they specify. It's intended for administrators
+// to roll out targeted hotfixes while a broader rollout is in progress.
+//
+// An override doesn't change the composite resource's composition references.
+// Deleting the override returns the composite resource to the revision it
+// would otherwise use. If several overrides select a composite resource the
+// first, ordered by name, applies.
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="REVISION",type="string",JSONPath=".spec.compositionRevisionRef.name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories=crossplane
+type CompositionRevisionOverride struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec CompositionRevisionOverrideSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// CompositionRevisionOverrideList contains a list of
+// CompositionRevisionOverrides.
+type CompositionRevisionOverrideList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CompositionRevisionOverride `json:"items"`
+}
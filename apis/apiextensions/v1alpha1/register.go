@@ -56,7 +56,16 @@ var (
 	UsageGroupVersionKind = SchemeGroupVersion.WithKind(UsageKind)
 )
 
+// CompositionRevisionOverride type metadata.
+var (
+	CompositionRevisionOverrideKind             = reflect.TypeOf(CompositionRevisionOverride{}).Name()
+	CompositionRevisionOverrideGroupKind        = schema.GroupKind{Group: Group, Kind: CompositionRevisionOverrideKind}.String()
+	CompositionRevisionOverrideKindAPIVersion   = CompositionRevisionOverrideKind + "." + SchemeGroupVersion.String()
+	CompositionRevisionOverrideGroupVersionKind = SchemeGroupVersion.WithKind(CompositionRevisionOverrideKind)
+)
+
 func init() {
 	SchemeBuilder.Register(&EnvironmentConfig{}, &EnvironmentConfigList{})
 	SchemeBuilder.Register(&Usage{}, &UsageList{})
+	SchemeBuilder.Register(&CompositionRevisionOverride{}, &CompositionRevisionOverrideList{})
 }
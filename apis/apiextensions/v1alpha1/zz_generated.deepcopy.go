@@ -25,6 +25,81 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompositionRevisionOverride) DeepCopyInto(out *CompositionRevisionOverride) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositionRevisionOverride.
+func (in *CompositionRevisionOverride) DeepCopy() *CompositionRevisionOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(CompositionRevisionOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CompositionRevisionOverride) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompositionRevisionOverrideList) DeepCopyInto(out *CompositionRevisionOverrideList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CompositionRevisionOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositionRevisionOverrideList.
+func (in *CompositionRevisionOverrideList) DeepCopy() *CompositionRevisionOverrideList {
+	if in == nil {
+		return nil
+	}
+	out := new(CompositionRevisionOverrideList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CompositionRevisionOverrideList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompositionRevisionOverrideSpec) DeepCopyInto(out *CompositionRevisionOverrideSpec) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+	out.CompositionRevisionRef = in.CompositionRevisionRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositionRevisionOverrideSpec.
+func (in *CompositionRevisionOverrideSpec) DeepCopy() *CompositionRevisionOverrideSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CompositionRevisionOverrideSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EnvironmentConfig) DeepCopyInto(out *EnvironmentConfig) {
 	*out = *in
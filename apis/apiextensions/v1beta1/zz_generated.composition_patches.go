@@ -21,7 +21,9 @@ package v1beta1
 import (
 	"fmt"
 
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 
@@ -53,6 +55,88 @@ const (
 	FromFieldPathPolicyRequired FromFieldPathPolicy = "Required"
 )
 
+// A ToFieldPathPolicy determines how to patch to a field path.
+type ToFieldPathPolicy string
+
+// ToFieldPath patch policies.
+const (
+	ToFieldPathPolicyReplace                       ToFieldPathPolicy = "Replace"
+	ToFieldPathPolicyMergeObjects                  ToFieldPathPolicy = "MergeObjects"
+	ToFieldPathPolicyMergeObjectsAppendArrays      ToFieldPathPolicy = "MergeObjectsAppendArrays"
+	ToFieldPathPolicyForceMergeObjects             ToFieldPathPolicy = "ForceMergeObjects"
+	ToFieldPathPolicyForceMergeObjectsAppendArrays ToFieldPathPolicy = "ForceMergeObjectsAppendArrays"
+)
+
+// A PatchConditionOperator determines how a PatchCondition matches a field.
+type PatchConditionOperator string
+
+// Patch condition operators.
+const (
+	PatchConditionOperatorIn           PatchConditionOperator = "In"
+	PatchConditionOperatorNotIn        PatchConditionOperator = "NotIn"
+	PatchConditionOperatorExists       PatchConditionOperator = "Exists"
+	PatchConditionOperatorDoesNotExist PatchConditionOperator = "DoesNotExist"
+)
+
+// A PatchCondition determines whether a patch is applied, by matching a field
+// of the composite resource.
+type PatchCondition struct {
+	// FieldPath is the path of the field on the composite resource whose
+	// value is matched, for example spec.parameters.tier.
+	FieldPath string `json:"fieldPath"`
+
+	// Operator specifies how the field is matched.
+	//
+	// * `In` - the field must exist and equal one of the values. This is the
+	// default.
+	//
+	// * `NotIn` - the field must not exist, or not equal any of the values.
+	//
+	// * `Exists` - the field must exist.
+	//
+	// * `DoesNotExist` - the field must not exist.
+	//
+	// +optional
+	// +kubebuilder:validation:Enum=In;NotIn;Exists;DoesNotExist
+	// +kubebuilder:default=In
+	Operator PatchConditionOperator `json:"operator,omitempty"`
+
+	// Values the field is matched against. Required when operator is In or
+	// NotIn. A value may be any JSON value, for example a string, a number, or
+	// an object.
+	// +optional
+	Values []extv1.JSON `json:"values,omitempty"`
+}
+
+// GetOperator returns the operator of this PatchCondition, defaulting to
+// PatchConditionOperatorIn if not specified.
+func (c *PatchCondition) GetOperator() PatchConditionOperator {
+	if c.Operator == "" {
+		return PatchConditionOperatorIn
+	}
+	return c.Operator
+}
+
+// Validate the PatchCondition.
+func (c *PatchCondition) Validate() *field.Error {
+	if c.FieldPath == "" {
+		return field.Required(field.NewPath("fieldPath"), "fieldPath must be set")
+	}
+	switch c.GetOperator() {
+	case PatchConditionOperatorIn, PatchConditionOperatorNotIn:
+		if len(c.Values) == 0 {
+			return field.Required(field.NewPath("values"), fmt.Sprintf("values must be set for operator %s", c.GetOperator()))
+		}
+	case PatchConditionOperatorExists, PatchConditionOperatorDoesNotExist:
+		if len(c.Values) != 0 {
+			return field.Forbidden(field.NewPath("values"), fmt.Sprintf("values cannot be set for operator %s", c.GetOperator()))
+		}
+	default:
+		return field.Invalid(field.NewPath("operator"), c.Operator, "unknown patch condition operator")
+	}
+	return nil
+}
+
 // A PatchPolicy configures the specifics of patching behaviour.
 type PatchPolicy struct {
 	// FromFieldPath specifies how to patch from a field path. The default is
@@ -62,7 +146,24 @@ type PatchPolicy struct {
 	// +kubebuilder:validation:Enum=Optional;Required
 	// +optional
 	FromFieldPath *FromFieldPathPolicy `json:"fromFieldPath,omitempty"`
-	MergeOptions  *xpv1.MergeOptions   `json:"mergeOptions,omitempty"`
+
+	// ToFieldPath specifies how to patch to a field path. The default is
+	// 'Replace', which means the patch will replace the value at the
+	// toFieldPath. 'MergeObjects' merges an object into the object at the
+	// toFieldPath, keeping fields that are already set. 'ForceMergeObjects'
+	// merges an object, overwriting fields that are already set. The
+	// 'AppendArrays' variants also append an array's elements to the array at
+	// the toFieldPath, skipping elements that are already present. This is
+	// useful to collect values, like connection endpoints, from several
+	// composed resources into an array on the composite resource.
+	// +kubebuilder:validation:Enum=Replace;MergeObjects;MergeObjectsAppendArrays;ForceMergeObjects;ForceMergeObjectsAppendArrays
+	// +optional
+	ToFieldPath *ToFieldPathPolicy `json:"toFieldPath,omitempty"`
+
+	// MergeOptions specifies merge options on a field path. Use toFieldPath
+	// instead. MergeOptions can't be set if toFieldPath is set.
+	// +optional
+	MergeOptions *xpv1.MergeOptions `json:"mergeOptions,omitempty"`
 }
 
 // GetFromFieldPathPolicy returns the FromFieldPathPolicy for this PatchPolicy, defaulting to FromFieldPathPolicyOptional if not specified.
@@ -73,6 +174,31 @@ func (pp *PatchPolicy) GetFromFieldPathPolicy() FromFieldPathPolicy {
 	return *pp.FromFieldPath
 }
 
+// GetMergeOptions returns the MergeOptions that implement this PatchPolicy's
+// ToFieldPath policy, or its MergeOptions if no ToFieldPath policy is
+// specified. It returns nil, meaning replace, if neither are specified.
+func (pp *PatchPolicy) GetMergeOptions() *xpv1.MergeOptions {
+	if pp == nil {
+		return nil
+	}
+	if pp.ToFieldPath == nil {
+		return pp.MergeOptions
+	}
+	switch *pp.ToFieldPath {
+	case ToFieldPathPolicyMergeObjects:
+		return &xpv1.MergeOptions{KeepMapValues: ptr.To(true)}
+	case ToFieldPathPolicyMergeObjectsAppendArrays:
+		return &xpv1.MergeOptions{KeepMapValues: ptr.To(true), AppendSlice: ptr.To(true)}
+	case ToFieldPathPolicyForceMergeObjects:
+		return &xpv1.MergeOptions{}
+	case ToFieldPathPolicyForceMergeObjectsAppendArrays:
+		return &xpv1.MergeOptions{AppendSlice: ptr.To(true)}
+	case ToFieldPathPolicyReplace:
+		return nil
+	}
+	return nil
+}
+
 // Patch objects are applied between composite and composed resources. Their
 // behaviour depends on the Type selected. The default Type,
 // FromCompositeFieldPath, copies a value from the composite resource to
@@ -114,6 +240,11 @@ type Patch struct {
 	// Policy configures the specifics of patching behaviour.
 	// +optional
 	Policy *PatchPolicy `json:"policy,omitempty"`
+
+	// Condition configures when the patch is applied. The patch is always
+	// applied if no condition is specified.
+	// +optional
+	Condition *PatchCondition `json:"condition,omitempty"`
 }
 
 // GetFromFieldPath returns the FromFieldPath for this Patch, or an empty string if it is nil.
@@ -162,6 +293,14 @@ func (p *Patch) Validate() *field.Error {
 		// Should never happen
 		return field.Invalid(field.NewPath("type"), p.Type, "unknown patch type")
 	}
+	if p.Policy != nil && p.Policy.ToFieldPath != nil && p.Policy.MergeOptions != nil {
+		return field.Forbidden(field.NewPath("policy", "mergeOptions"), "mergeOptions cannot be set when toFieldPath is set")
+	}
+	if p.Condition != nil {
+		if err := p.Condition.Validate(); err != nil {
+			return verrors.WrapFieldError(err, field.NewPath("condition"))
+		}
+	}
 	for i, transform := range p.Transforms {
 		if err := transform.Validate(); err != nil {
 			return verrors.WrapFieldError(err, field.NewPath("transforms").Index(i))
@@ -105,6 +105,30 @@ type CompositionRevisionSpec struct {
 	// +listMapKey=step
 	Pipeline []PipelineStep `json:"pipeline,omitempty"`
 
+	// ReadinessChecks are checked against the composite resource itself, once
+	// all of its composed resources are ready. The composite resource isn't
+	// ready until all of them pass, for example until a status field that is
+	// patched from a composed resource isn't empty.
+	// +optional
+	ReadinessChecks []ReadinessCheck `json:"readinessChecks,omitempty"`
+
+	// PropagateMetadata configures which labels and annotations of composite
+	// resources are propagated to their composed resources each time they're
+	// composed. A claim's labels and annotations are propagated to its
+	// composite resource, so this also propagates them from a claim to the
+	// resources composed for it.
+	// +optional
+	PropagateMetadata *MetadataPropagation `json:"propagateMetadata,omitempty"`
+
+	// RemovedResourcePolicy specifies what happens to a composed resource
+	// that is no longer composed, for example because its template was
+	// removed from the Composition. Such resources are deleted by default.
+	// Orphaned resources are no longer controlled by their composite resource,
+	// so they aren't deleted when it is.
+	// +optional
+	// +kubebuilder:validation:Enum=Delete;Orphan
+	RemovedResourcePolicy *RemovedResourcePolicy `json:"removedResourcePolicy,omitempty"`
+
 	// WriteConnectionSecretsToNamespace specifies the namespace in which the
 	// connection secrets of composite resource dynamically provisioned using
 	// this composition will be created.
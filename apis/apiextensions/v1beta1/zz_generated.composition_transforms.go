@@ -19,11 +19,19 @@ limitations under the License.
 package v1beta1
 
 import (
+	"crypto/sha1" //nolint:gosec // Not used for secure hashing.
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
 
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 
@@ -37,18 +45,19 @@ type TransformType string
 const (
 	ErrFmtConvertFormatPairNotSupported = "conversion from %s to %s is not supported with format %s"
 
-	TransformTypeMap     TransformType = "map"
-	TransformTypeMatch   TransformType = "match"
-	TransformTypeMath    TransformType = "math"
-	TransformTypeString  TransformType = "string"
-	TransformTypeConvert TransformType = "convert"
+	TransformTypeMap      TransformType = "map"
+	TransformTypeMatch    TransformType = "match"
+	TransformTypeMath     TransformType = "math"
+	TransformTypeString   TransformType = "string"
+	TransformTypeConvert  TransformType = "convert"
+	TransformTypeTemplate TransformType = "template"
 )
 
 // Transform is a unit of process whose input is transformed into an output with
 // the supplied configuration.
 type Transform struct {
 	// Type of the transform to be run.
-	// +kubebuilder:validation:Enum=map;match;math;string;convert
+	// +kubebuilder:validation:Enum=map;match;math;string;convert;template
 	Type TransformType `json:"type"`
 
 	// Math is used to transform the input via mathematical operations such as
@@ -72,6 +81,11 @@ type Transform struct {
 	// Convert is used to cast the input into the given output type.
 	// +optional
 	Convert *ConvertTransform `json:"convert,omitempty"`
+
+	// Template is used to transform the input into a string using a Go
+	// template.
+	// +optional
+	Template *TemplateTransform `json:"template,omitempty"`
 }
 
 // Validate this Transform is valid.
@@ -104,6 +118,11 @@ func (t *Transform) Validate() *field.Error {
 		if err := t.Convert.Validate(); err != nil {
 			return verrors.WrapFieldError(err, field.NewPath("convert"))
 		}
+	case TransformTypeTemplate:
+		if t.Template == nil {
+			return field.Required(field.NewPath("template"), "given transform type template requires configuration")
+		}
+		return verrors.WrapFieldError(t.Template.Validate(), field.NewPath("template"))
 	default:
 		// Should never happen
 		return field.Invalid(field.NewPath("type"), t.Type, "unknown transform type")
@@ -130,7 +149,7 @@ func (t *Transform) GetOutputType() (*TransformIOType, error) {
 		return nil, nil
 	case TransformTypeMath:
 		out = TransformIOTypeFloat64
-	case TransformTypeString:
+	case TransformTypeString, TransformTypeTemplate:
 		out = TransformIOTypeString
 	case TransformTypeConvert:
 		out = t.Convert.ToType
@@ -306,6 +325,13 @@ type MatchTransformPattern struct {
 
 	// The value that is used as result of the transform if the pattern matches.
 	Result extv1.JSON `json:"result"`
+
+	// ExpandCaptureGroups replaces references to the capture groups of the
+	// regexp, for example $1 or ${region}, in the result with the text they
+	// matched. The result must be a string. Only supported if `type` is
+	// `regexp`.
+	// +optional
+	ExpandCaptureGroups *bool `json:"expandCaptureGroups,omitempty"`
 }
 
 // Validate checks this MatchTransformPattern is valid.
@@ -322,9 +348,13 @@ func (m *MatchTransformPattern) Validate() *field.Error {
 		if _, err := regexp.Compile(*m.Regexp); err != nil {
 			return field.Invalid(field.NewPath("regexp"), *m.Regexp, "invalid regexp")
 		}
+		return nil
 	default:
 		return field.Invalid(field.NewPath("type"), m.Type, "unknown pattern type")
 	}
+	if ptr.Deref(m.ExpandCaptureGroups, false) {
+		return field.Forbidden(field.NewPath("expandCaptureGroups"), "capture groups can only be expanded for the regexp pattern type")
+	}
 	return nil
 }
 
@@ -522,3 +552,146 @@ func (t ConvertTransform) Validate() *field.Error {
 	}
 	return nil
 }
+
+// A TemplateTransform returns a string given the supplied input, by executing
+// a Go template.
+type TemplateTransform struct {
+	// Text of the template. The input is available as the template's data,
+	// i.e. as {{ . }}. In addition to Go's builtin template functions the
+	// lower, trunc, and sha1sum functions are available. They behave like
+	// their Sprig equivalents. See https://pkg.go.dev/text/template and
+	// https://masterminds.github.io/sprig/ for details. A template's output
+	// may be at most 4KiB, and printf widths and precisions at most 1024.
+	// The range, define, block, and template actions aren't supported.
+	Text string `json:"text"`
+}
+
+// TemplateTransformFuncs are the functions available to Template transforms,
+// in addition to Go's builtin template functions. They're a subset of Sprig's
+// functions that can't read from or affect anything outside the template.
+var TemplateTransformFuncs = template.FuncMap{
+	"lower":   strings.ToLower,
+	"trunc":   templateTrunc,
+	"sha1sum": templateSHA1Sum,
+
+	// Replaces the builtin printf, which would allocate a string as wide as
+	// any width or precision it's passed.
+	"printf": templatePrintf,
+}
+
+// maxTemplatePrintfWidth is the largest width or precision that may be passed
+// to a Template transform's printf function.
+const maxTemplatePrintfWidth = 1024
+
+// templatePrintf is like fmt.Sprintf, but returns an error if the format
+// specifies a width or precision larger than maxTemplatePrintfWidth, or one
+// supplied as an argument.
+func templatePrintf(format string, args ...any) (string, error) {
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+	directive:
+		for i++; i < len(format); i++ {
+			switch c := format[i]; {
+			case c == '*':
+				return "", errors.New("printf widths and precisions must not be supplied as arguments")
+			case c >= '0' && c <= '9':
+				j := i
+				for j < len(format) && format[j] >= '0' && format[j] <= '9' {
+					j++
+				}
+				if n, err := strconv.Atoi(format[i:j]); err != nil || n > maxTemplatePrintfWidth {
+					return "", errors.Errorf("printf widths and precisions must be at most %d", maxTemplatePrintfWidth)
+				}
+				i = j - 1
+			case strings.IndexByte("+-# .[]", c) >= 0:
+			default:
+				// This is the verb, which ends the directive.
+				break directive
+			}
+		}
+	}
+	return fmt.Sprintf(format, args...), nil
+}
+
+// templateTrunc truncates a string to c characters. A negative c truncates it
+// to its last -c characters. Characters are runes, not bytes, so a multi-byte
+// character is never split.
+func templateTrunc(c int, s string) string {
+	r := []rune(s)
+	if c < 0 && len(r)+c > 0 {
+		return string(r[len(r)+c:])
+	}
+	if c >= 0 && len(r) > c {
+		return string(r[:c])
+	}
+	return s
+}
+
+func templateSHA1Sum(s string) string {
+	h := sha1.Sum([]byte(s)) //nolint:gosec // Not used for secure hashing.
+	return hex.EncodeToString(h[:])
+}
+
+// Parse this TemplateTransform's text into a template that may be executed.
+// Templates that use range, define, block, or template actions are rejected;
+// they could run for an unbounded time.
+func (t *TemplateTransform) Parse() (*template.Template, error) {
+	tmpl, err := template.New("template").Option("missingkey=error").Funcs(TemplateTransformFuncs).Parse(t.Text)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range tmpl.Templates() {
+		if d.Name() != tmpl.Name() {
+			return nil, errors.New("define and block actions are not supported")
+		}
+	}
+	if tmpl.Tree == nil {
+		return tmpl, nil
+	}
+	return tmpl, checkTemplateNode(tmpl.Tree.Root)
+}
+
+// checkTemplateNode returns an error if the supplied template node, or any
+// node nested within it, is an action that could run for an unbounded time.
+func checkTemplateNode(n parse.Node) error {
+	switch n := n.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, c := range n.Nodes {
+			if err := checkTemplateNode(c); err != nil {
+				return err
+			}
+		}
+	case *parse.IfNode:
+		return checkTemplateBranch(&n.BranchNode)
+	case *parse.WithNode:
+		return checkTemplateBranch(&n.BranchNode)
+	case *parse.RangeNode:
+		return errors.New("range actions are not supported")
+	case *parse.TemplateNode:
+		return errors.New("template actions are not supported")
+	}
+	return nil
+}
+
+func checkTemplateBranch(b *parse.BranchNode) error {
+	if err := checkTemplateNode(b.List); err != nil {
+		return err
+	}
+	return checkTemplateNode(b.ElseList)
+}
+
+// Validate checks this TemplateTransform is valid.
+func (t *TemplateTransform) Validate() *field.Error {
+	if t.Text == "" {
+		return field.Required(field.NewPath("text"), "template transform requires text")
+	}
+	if _, err := t.Parse(); err != nil {
+		return field.Invalid(field.NewPath("text"), t.Text, err.Error())
+	}
+	return nil
+}
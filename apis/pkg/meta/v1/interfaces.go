@@ -27,6 +27,7 @@ var (
 type Pkg interface {
 	GetCrossplaneConstraints() *CrossplaneConstraints
 	GetDependencies() []Dependency
+	GetRequiredFeatures() []string
 }
 
 // GetCrossplaneConstraints gets the Configuration package's Crossplane version
@@ -40,6 +41,11 @@ func (c *Configuration) GetDependencies() []Dependency {
 	return c.Spec.MetaSpec.DependsOn
 }
 
+// GetRequiredFeatures gets the Configuration package's required feature flags.
+func (c *Configuration) GetRequiredFeatures() []string {
+	return c.Spec.MetaSpec.RequiredFeatures
+}
+
 // GetCrossplaneConstraints gets the Provider package's Crossplane version
 // constraints.
 func (p *Provider) GetCrossplaneConstraints() *CrossplaneConstraints {
@@ -51,6 +57,11 @@ func (p *Provider) GetDependencies() []Dependency {
 	return p.Spec.MetaSpec.DependsOn
 }
 
+// GetRequiredFeatures gets the Provider package's required feature flags.
+func (p *Provider) GetRequiredFeatures() []string {
+	return p.Spec.MetaSpec.RequiredFeatures
+}
+
 // GetCrossplaneConstraints gets the Function package's Crossplane version constraints.
 func (f *Function) GetCrossplaneConstraints() *CrossplaneConstraints {
 	return f.Spec.MetaSpec.Crossplane
@@ -60,3 +71,8 @@ func (f *Function) GetCrossplaneConstraints() *CrossplaneConstraints {
 func (f *Function) GetDependencies() []Dependency {
 	return f.Spec.DependsOn
 }
+
+// GetRequiredFeatures gets the Function package's required feature flags.
+func (f *Function) GetRequiredFeatures() []string {
+	return f.Spec.RequiredFeatures
+}
@@ -19,6 +19,7 @@ package v1alpha1
 import (
 	"errors"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 
@@ -38,6 +39,7 @@ const (
 // goverter:converter
 // goverter:name GeneratedToHubConverter
 // goverter:extend ConvertObjectMeta
+// goverter:extend ConvertContainer
 // goverter:output:file ./zz_generated.conversion.go
 // goverter:output:package github.com/crossplane/crossplane/apis/pkg/meta/v1alpha1
 // +k8s:deepcopy-gen=false
@@ -51,6 +53,7 @@ type ToHubConverter interface {
 // goverter:converter
 // goverter:name GeneratedFromHubConverter
 // goverter:extend ConvertObjectMeta
+// goverter:extend ConvertContainer
 // goverter:output:file ./zz_generated.conversion.go
 // goverter:output:package github.com/crossplane/crossplane/apis/pkg/meta/v1alpha1
 // +k8s:deepcopy-gen=false
@@ -67,6 +70,14 @@ func ConvertObjectMeta(in metav1.ObjectMeta) metav1.ObjectMeta {
 	return *out
 }
 
+// ConvertContainer 'converts' a Container by producing a deepcopy. This
+// prevents goverter generating code that is functionally identical to
+// deepcopygen's for the many types a Container consists of.
+func ConvertContainer(in corev1.Container) corev1.Container {
+	out := in.DeepCopy()
+	return *out
+}
+
 // ConvertTo converts this Configuration to the Hub version.
 func (c *Configuration) ConvertTo(hub conversion.Hub) error {
 	out, ok := hub.(*v1.Configuration)
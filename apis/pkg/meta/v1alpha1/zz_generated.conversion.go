@@ -5,6 +5,7 @@ package v1alpha1
 
 import (
 	v1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+	v13 "k8s.io/api/core/v1"
 	v11 "k8s.io/api/rbac/v1"
 	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -38,15 +39,90 @@ func (c *GeneratedFromHubConverter) pV1CrossplaneConstraintsToPV1alpha1Crossplan
 	if source != nil {
 		var v1alpha1CrossplaneConstraints CrossplaneConstraints
 		v1alpha1CrossplaneConstraints.Version = (*source).Version
+		v1alpha1CrossplaneConstraints.Runtime = (*source).Runtime
 		pV1alpha1CrossplaneConstraints = &v1alpha1CrossplaneConstraints
 	}
 	return pV1alpha1CrossplaneConstraints
 }
+func (c *GeneratedFromHubConverter) pV1RuntimeSpecToPV1alpha1RuntimeSpec(source *v1.RuntimeSpec) *RuntimeSpec {
+	var pV1alpha1RuntimeSpec *RuntimeSpec
+	if source != nil {
+		var v1alpha1RuntimeSpec RuntimeSpec
+		var v1ContainerList []v13.Container
+		if (*source).Containers != nil {
+			v1ContainerList = make([]v13.Container, len((*source).Containers))
+			for i := 0; i < len((*source).Containers); i++ {
+				v1ContainerList[i] = ConvertContainer((*source).Containers[i])
+			}
+		}
+		v1alpha1RuntimeSpec.Containers = v1ContainerList
+		var v1ContainerPortList []v13.ContainerPort
+		if (*source).Ports != nil {
+			v1ContainerPortList = make([]v13.ContainerPort, len((*source).Ports))
+			for i := 0; i < len((*source).Ports); i++ {
+				v1ContainerPortList[i] = c.v1ContainerPortToV1ContainerPort((*source).Ports[i])
+			}
+		}
+		v1alpha1RuntimeSpec.Ports = v1ContainerPortList
+		pV1alpha1RuntimeSpec = &v1alpha1RuntimeSpec
+	}
+	return pV1alpha1RuntimeSpec
+}
+func (c *GeneratedFromHubConverter) v1AlternativeDependencyToV1alpha1AlternativeDependency(source v1.AlternativeDependency) AlternativeDependency {
+	var v1alpha1AlternativeDependency AlternativeDependency
+	var pString *string
+	if source.APIVersion != nil {
+		xstring := *source.APIVersion
+		pString = &xstring
+	}
+	v1alpha1AlternativeDependency.APIVersion = pString
+	var pString2 *string
+	if source.Kind != nil {
+		xstring2 := *source.Kind
+		pString2 = &xstring2
+	}
+	v1alpha1AlternativeDependency.Kind = pString2
+	var pString3 *string
+	if source.Package != nil {
+		xstring3 := *source.Package
+		pString3 = &xstring3
+	}
+	v1alpha1AlternativeDependency.Package = pString3
+	var pString4 *string
+	if source.Provider != nil {
+		xstring4 := *source.Provider
+		pString4 = &xstring4
+	}
+	v1alpha1AlternativeDependency.Provider = pString4
+	var pString5 *string
+	if source.Configuration != nil {
+		xstring5 := *source.Configuration
+		pString5 = &xstring5
+	}
+	v1alpha1AlternativeDependency.Configuration = pString5
+	var pString6 *string
+	if source.Function != nil {
+		xstring6 := *source.Function
+		pString6 = &xstring6
+	}
+	v1alpha1AlternativeDependency.Function = pString6
+	v1alpha1AlternativeDependency.Version = source.Version
+	return v1alpha1AlternativeDependency
+}
 func (c *GeneratedFromHubConverter) v1ConfigurationSpecToV1alpha1ConfigurationSpec(source v1.ConfigurationSpec) ConfigurationSpec {
 	var v1alpha1ConfigurationSpec ConfigurationSpec
 	v1alpha1ConfigurationSpec.MetaSpec = c.v1MetaSpecToV1alpha1MetaSpec(source.MetaSpec)
 	return v1alpha1ConfigurationSpec
 }
+func (c *GeneratedFromHubConverter) v1ContainerPortToV1ContainerPort(source v13.ContainerPort) v13.ContainerPort {
+	var v1ContainerPort v13.ContainerPort
+	v1ContainerPort.Name = source.Name
+	v1ContainerPort.HostPort = source.HostPort
+	v1ContainerPort.ContainerPort = source.ContainerPort
+	v1ContainerPort.Protocol = v13.Protocol(source.Protocol)
+	v1ContainerPort.HostIP = source.HostIP
+	return v1ContainerPort
+}
 func (c *GeneratedFromHubConverter) v1ControllerSpecToV1alpha1ControllerSpec(source v1.ControllerSpec) ControllerSpec {
 	var v1alpha1ControllerSpec ControllerSpec
 	var pString *string
@@ -55,6 +131,12 @@ func (c *GeneratedFromHubConverter) v1ControllerSpecToV1alpha1ControllerSpec(sou
 		pString = &xstring
 	}
 	v1alpha1ControllerSpec.Image = pString
+	var pString2 *string
+	if source.ImageDigest != nil {
+		xstring2 := *source.ImageDigest
+		pString2 = &xstring2
+	}
+	v1alpha1ControllerSpec.ImageDigest = pString2
 	var v1PolicyRuleList []v11.PolicyRule
 	if source.PermissionRequests != nil {
 		v1PolicyRuleList = make([]v11.PolicyRule, len(source.PermissionRequests))
@@ -63,28 +145,67 @@ func (c *GeneratedFromHubConverter) v1ControllerSpecToV1alpha1ControllerSpec(sou
 		}
 	}
 	v1alpha1ControllerSpec.PermissionRequests = v1PolicyRuleList
+	v1alpha1ControllerSpec.Runtime = c.pV1RuntimeSpecToPV1alpha1RuntimeSpec(source.Runtime)
 	return v1alpha1ControllerSpec
 }
 func (c *GeneratedFromHubConverter) v1DependencyToV1alpha1Dependency(source v1.Dependency) Dependency {
 	var v1alpha1Dependency Dependency
 	var pString *string
-	if source.Provider != nil {
-		xstring := *source.Provider
+	if source.APIVersion != nil {
+		xstring := *source.APIVersion
 		pString = &xstring
 	}
-	v1alpha1Dependency.Provider = pString
+	v1alpha1Dependency.APIVersion = pString
 	var pString2 *string
-	if source.Configuration != nil {
-		xstring2 := *source.Configuration
+	if source.Kind != nil {
+		xstring2 := *source.Kind
 		pString2 = &xstring2
 	}
-	v1alpha1Dependency.Configuration = pString2
+	v1alpha1Dependency.Kind = pString2
 	var pString3 *string
-	if source.Function != nil {
-		xstring3 := *source.Function
+	if source.Package != nil {
+		xstring3 := *source.Package
 		pString3 = &xstring3
 	}
-	v1alpha1Dependency.Function = pString3
+	v1alpha1Dependency.Package = pString3
+	var pString4 *string
+	if source.Provider != nil {
+		xstring4 := *source.Provider
+		pString4 = &xstring4
+	}
+	v1alpha1Dependency.Provider = pString4
+	var pString5 *string
+	if source.Configuration != nil {
+		xstring5 := *source.Configuration
+		pString5 = &xstring5
+	}
+	v1alpha1Dependency.Configuration = pString5
+	var pString6 *string
+	if source.Function != nil {
+		xstring6 := *source.Function
+		pString6 = &xstring6
+	}
+	v1alpha1Dependency.Function = pString6
+	var pString7 *string
+	if source.API != nil {
+		xstring7 := *source.API
+		pString7 = &xstring7
+	}
+	v1alpha1Dependency.API = pString7
+	var pString8 *string
+	if source.Channel != nil {
+		xstring8 := *source.Channel
+		pString8 = &xstring8
+	}
+	v1alpha1Dependency.Channel = pString8
+	var v1alpha1AlternativeDependencyList []AlternativeDependency
+	if source.AnyOf != nil {
+		v1alpha1AlternativeDependencyList = make([]AlternativeDependency, len(source.AnyOf))
+		for i := 0; i < len(source.AnyOf); i++ {
+			v1alpha1AlternativeDependencyList[i] = c.v1AlternativeDependencyToV1alpha1AlternativeDependency(source.AnyOf[i])
+		}
+	}
+	v1alpha1Dependency.AnyOf = v1alpha1AlternativeDependencyList
 	v1alpha1Dependency.Version = source.Version
 	return v1alpha1Dependency
 }
@@ -99,6 +220,14 @@ func (c *GeneratedFromHubConverter) v1MetaSpecToV1alpha1MetaSpec(source v1.MetaS
 		}
 	}
 	v1alpha1MetaSpec.DependsOn = v1alpha1DependencyList
+	var stringList []string
+	if source.RequiredFeatures != nil {
+		stringList = make([]string, len(source.RequiredFeatures))
+		for i := 0; i < len(source.RequiredFeatures); i++ {
+			stringList[i] = source.RequiredFeatures[i]
+		}
+	}
+	v1alpha1MetaSpec.RequiredFeatures = stringList
 	return v1alpha1MetaSpec
 }
 func (c *GeneratedFromHubConverter) v1PolicyRuleToV1PolicyRule(source v11.PolicyRule) v11.PolicyRule {
@@ -187,10 +316,44 @@ func (c *GeneratedToHubConverter) pV1alpha1CrossplaneConstraintsToPV1CrossplaneC
 	if source != nil {
 		var v1CrossplaneConstraints v1.CrossplaneConstraints
 		v1CrossplaneConstraints.Version = (*source).Version
+		v1CrossplaneConstraints.Runtime = (*source).Runtime
 		pV1CrossplaneConstraints = &v1CrossplaneConstraints
 	}
 	return pV1CrossplaneConstraints
 }
+func (c *GeneratedToHubConverter) pV1alpha1RuntimeSpecToPV1RuntimeSpec(source *RuntimeSpec) *v1.RuntimeSpec {
+	var pV1RuntimeSpec *v1.RuntimeSpec
+	if source != nil {
+		var v1RuntimeSpec v1.RuntimeSpec
+		var v1ContainerList []v13.Container
+		if (*source).Containers != nil {
+			v1ContainerList = make([]v13.Container, len((*source).Containers))
+			for i := 0; i < len((*source).Containers); i++ {
+				v1ContainerList[i] = ConvertContainer((*source).Containers[i])
+			}
+		}
+		v1RuntimeSpec.Containers = v1ContainerList
+		var v1ContainerPortList []v13.ContainerPort
+		if (*source).Ports != nil {
+			v1ContainerPortList = make([]v13.ContainerPort, len((*source).Ports))
+			for i := 0; i < len((*source).Ports); i++ {
+				v1ContainerPortList[i] = c.v1ContainerPortToV1ContainerPort((*source).Ports[i])
+			}
+		}
+		v1RuntimeSpec.Ports = v1ContainerPortList
+		pV1RuntimeSpec = &v1RuntimeSpec
+	}
+	return pV1RuntimeSpec
+}
+func (c *GeneratedToHubConverter) v1ContainerPortToV1ContainerPort(source v13.ContainerPort) v13.ContainerPort {
+	var v1ContainerPort v13.ContainerPort
+	v1ContainerPort.Name = source.Name
+	v1ContainerPort.HostPort = source.HostPort
+	v1ContainerPort.ContainerPort = source.ContainerPort
+	v1ContainerPort.Protocol = v13.Protocol(source.Protocol)
+	v1ContainerPort.HostIP = source.HostIP
+	return v1ContainerPort
+}
 func (c *GeneratedToHubConverter) v1PolicyRuleToV1PolicyRule(source v11.PolicyRule) v11.PolicyRule {
 	var v1PolicyRule v11.PolicyRule
 	var stringList []string
@@ -241,6 +404,47 @@ func (c *GeneratedToHubConverter) v1TypeMetaToV1TypeMeta(source v12.TypeMeta) v1
 	v1TypeMeta.APIVersion = source.APIVersion
 	return v1TypeMeta
 }
+func (c *GeneratedToHubConverter) v1alpha1AlternativeDependencyToV1AlternativeDependency(source AlternativeDependency) v1.AlternativeDependency {
+	var v1AlternativeDependency v1.AlternativeDependency
+	var pString *string
+	if source.APIVersion != nil {
+		xstring := *source.APIVersion
+		pString = &xstring
+	}
+	v1AlternativeDependency.APIVersion = pString
+	var pString2 *string
+	if source.Kind != nil {
+		xstring2 := *source.Kind
+		pString2 = &xstring2
+	}
+	v1AlternativeDependency.Kind = pString2
+	var pString3 *string
+	if source.Package != nil {
+		xstring3 := *source.Package
+		pString3 = &xstring3
+	}
+	v1AlternativeDependency.Package = pString3
+	var pString4 *string
+	if source.Provider != nil {
+		xstring4 := *source.Provider
+		pString4 = &xstring4
+	}
+	v1AlternativeDependency.Provider = pString4
+	var pString5 *string
+	if source.Configuration != nil {
+		xstring5 := *source.Configuration
+		pString5 = &xstring5
+	}
+	v1AlternativeDependency.Configuration = pString5
+	var pString6 *string
+	if source.Function != nil {
+		xstring6 := *source.Function
+		pString6 = &xstring6
+	}
+	v1AlternativeDependency.Function = pString6
+	v1AlternativeDependency.Version = source.Version
+	return v1AlternativeDependency
+}
 func (c *GeneratedToHubConverter) v1alpha1ConfigurationSpecToV1ConfigurationSpec(source ConfigurationSpec) v1.ConfigurationSpec {
 	var v1ConfigurationSpec v1.ConfigurationSpec
 	v1ConfigurationSpec.MetaSpec = c.v1alpha1MetaSpecToV1MetaSpec(source.MetaSpec)
@@ -254,6 +458,12 @@ func (c *GeneratedToHubConverter) v1alpha1ControllerSpecToV1ControllerSpec(sourc
 		pString = &xstring
 	}
 	v1ControllerSpec.Image = pString
+	var pString2 *string
+	if source.ImageDigest != nil {
+		xstring2 := *source.ImageDigest
+		pString2 = &xstring2
+	}
+	v1ControllerSpec.ImageDigest = pString2
 	var v1PolicyRuleList []v11.PolicyRule
 	if source.PermissionRequests != nil {
 		v1PolicyRuleList = make([]v11.PolicyRule, len(source.PermissionRequests))
@@ -262,28 +472,67 @@ func (c *GeneratedToHubConverter) v1alpha1ControllerSpecToV1ControllerSpec(sourc
 		}
 	}
 	v1ControllerSpec.PermissionRequests = v1PolicyRuleList
+	v1ControllerSpec.Runtime = c.pV1alpha1RuntimeSpecToPV1RuntimeSpec(source.Runtime)
 	return v1ControllerSpec
 }
 func (c *GeneratedToHubConverter) v1alpha1DependencyToV1Dependency(source Dependency) v1.Dependency {
 	var v1Dependency v1.Dependency
 	var pString *string
-	if source.Provider != nil {
-		xstring := *source.Provider
+	if source.APIVersion != nil {
+		xstring := *source.APIVersion
 		pString = &xstring
 	}
-	v1Dependency.Provider = pString
+	v1Dependency.APIVersion = pString
 	var pString2 *string
-	if source.Configuration != nil {
-		xstring2 := *source.Configuration
+	if source.Kind != nil {
+		xstring2 := *source.Kind
 		pString2 = &xstring2
 	}
-	v1Dependency.Configuration = pString2
+	v1Dependency.Kind = pString2
 	var pString3 *string
-	if source.Function != nil {
-		xstring3 := *source.Function
+	if source.Package != nil {
+		xstring3 := *source.Package
 		pString3 = &xstring3
 	}
-	v1Dependency.Function = pString3
+	v1Dependency.Package = pString3
+	var pString4 *string
+	if source.Provider != nil {
+		xstring4 := *source.Provider
+		pString4 = &xstring4
+	}
+	v1Dependency.Provider = pString4
+	var pString5 *string
+	if source.Configuration != nil {
+		xstring5 := *source.Configuration
+		pString5 = &xstring5
+	}
+	v1Dependency.Configuration = pString5
+	var pString6 *string
+	if source.Function != nil {
+		xstring6 := *source.Function
+		pString6 = &xstring6
+	}
+	v1Dependency.Function = pString6
+	var pString7 *string
+	if source.API != nil {
+		xstring7 := *source.API
+		pString7 = &xstring7
+	}
+	v1Dependency.API = pString7
+	var pString8 *string
+	if source.Channel != nil {
+		xstring8 := *source.Channel
+		pString8 = &xstring8
+	}
+	v1Dependency.Channel = pString8
+	var v1AlternativeDependencyList []v1.AlternativeDependency
+	if source.AnyOf != nil {
+		v1AlternativeDependencyList = make([]v1.AlternativeDependency, len(source.AnyOf))
+		for i := 0; i < len(source.AnyOf); i++ {
+			v1AlternativeDependencyList[i] = c.v1alpha1AlternativeDependencyToV1AlternativeDependency(source.AnyOf[i])
+		}
+	}
+	v1Dependency.AnyOf = v1AlternativeDependencyList
 	v1Dependency.Version = source.Version
 	return v1Dependency
 }
@@ -298,6 +547,14 @@ func (c *GeneratedToHubConverter) v1alpha1MetaSpecToV1MetaSpec(source MetaSpec)
 		}
 	}
 	v1MetaSpec.DependsOn = v1DependencyList
+	var stringList []string
+	if source.RequiredFeatures != nil {
+		stringList = make([]string, len(source.RequiredFeatures))
+		for i := 0; i < len(source.RequiredFeatures); i++ {
+			stringList[i] = source.RequiredFeatures[i]
+		}
+	}
+	v1MetaSpec.RequiredFeatures = stringList
 	return v1MetaSpec
 }
 func (c *GeneratedToHubConverter) v1alpha1ProviderSpecToV1ProviderSpec(source ProviderSpec) v1.ProviderSpec {
@@ -19,6 +19,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -37,11 +38,43 @@ type ControllerSpec struct {
 	// Image is the packaged Provider controller image.
 	Image *string `json:"image,omitempty"`
 
+	// ImageDigest pins the packaged Provider controller image by digest,
+	// independently of the package image, for example
+	// sha256:0123456789abcdef... The Deployment that runs the controller must
+	// reference this digest.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^sha256:[a-f0-9]{64}$`
+	ImageDigest *string `json:"imageDigest,omitempty"`
+
 	// PermissionRequests for RBAC rules required for this provider's controller
 	// to function. The RBAC manager is responsible for assessing the requested
 	// permissions.
 	// +optional
 	PermissionRequests []rbacv1.PolicyRule `json:"permissionRequests,omitempty"`
+
+	// Runtime configures additional containers and ports that are merged into
+	// the Deployment that runs the packaged Provider's controller. This allows
+	// a provider that consists of more than one process to be packaged without
+	// requiring a DeploymentRuntimeConfig in every cluster it's installed to.
+	// +optional
+	Runtime *RuntimeSpec `json:"runtime,omitempty"`
+}
+
+// RuntimeSpec specifies additions to the Deployment that runs a packaged
+// Provider's controller.
+type RuntimeSpec struct {
+	// Containers to run alongside the Provider's controller. A container is
+	// not added if the Deployment already has a container of the same name,
+	// for example because a DeploymentRuntimeConfig specifies it. Only a
+	// container's name, image, command, args, workingDir, ports, resources,
+	// probes, and literal env values are used. Containers always run with a
+	// restricted, non-root security context.
+	// +optional
+	Containers []corev1.Container `json:"containers,omitempty"`
+
+	// Ports to expose on the Provider's controller container.
+	// +optional
+	Ports []corev1.ContainerPort `json:"ports,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -26,30 +26,110 @@ func (c *GeneratedFromHubConverter) pV1CrossplaneConstraintsToPV1beta1Crossplane
 	if source != nil {
 		var v1beta1CrossplaneConstraints CrossplaneConstraints
 		v1beta1CrossplaneConstraints.Version = (*source).Version
+		v1beta1CrossplaneConstraints.Runtime = (*source).Runtime
 		pV1beta1CrossplaneConstraints = &v1beta1CrossplaneConstraints
 	}
 	return pV1beta1CrossplaneConstraints
 }
+func (c *GeneratedFromHubConverter) v1AlternativeDependencyToV1beta1AlternativeDependency(source v1.AlternativeDependency) AlternativeDependency {
+	var v1beta1AlternativeDependency AlternativeDependency
+	var pString *string
+	if source.APIVersion != nil {
+		xstring := *source.APIVersion
+		pString = &xstring
+	}
+	v1beta1AlternativeDependency.APIVersion = pString
+	var pString2 *string
+	if source.Kind != nil {
+		xstring2 := *source.Kind
+		pString2 = &xstring2
+	}
+	v1beta1AlternativeDependency.Kind = pString2
+	var pString3 *string
+	if source.Package != nil {
+		xstring3 := *source.Package
+		pString3 = &xstring3
+	}
+	v1beta1AlternativeDependency.Package = pString3
+	var pString4 *string
+	if source.Provider != nil {
+		xstring4 := *source.Provider
+		pString4 = &xstring4
+	}
+	v1beta1AlternativeDependency.Provider = pString4
+	var pString5 *string
+	if source.Configuration != nil {
+		xstring5 := *source.Configuration
+		pString5 = &xstring5
+	}
+	v1beta1AlternativeDependency.Configuration = pString5
+	var pString6 *string
+	if source.Function != nil {
+		xstring6 := *source.Function
+		pString6 = &xstring6
+	}
+	v1beta1AlternativeDependency.Function = pString6
+	v1beta1AlternativeDependency.Version = source.Version
+	return v1beta1AlternativeDependency
+}
 func (c *GeneratedFromHubConverter) v1DependencyToV1beta1Dependency(source v1.Dependency) Dependency {
 	var v1beta1Dependency Dependency
 	var pString *string
-	if source.Provider != nil {
-		xstring := *source.Provider
+	if source.APIVersion != nil {
+		xstring := *source.APIVersion
 		pString = &xstring
 	}
-	v1beta1Dependency.Provider = pString
+	v1beta1Dependency.APIVersion = pString
 	var pString2 *string
-	if source.Configuration != nil {
-		xstring2 := *source.Configuration
+	if source.Kind != nil {
+		xstring2 := *source.Kind
 		pString2 = &xstring2
 	}
-	v1beta1Dependency.Configuration = pString2
+	v1beta1Dependency.Kind = pString2
 	var pString3 *string
-	if source.Function != nil {
-		xstring3 := *source.Function
+	if source.Package != nil {
+		xstring3 := *source.Package
 		pString3 = &xstring3
 	}
-	v1beta1Dependency.Function = pString3
+	v1beta1Dependency.Package = pString3
+	var pString4 *string
+	if source.Provider != nil {
+		xstring4 := *source.Provider
+		pString4 = &xstring4
+	}
+	v1beta1Dependency.Provider = pString4
+	var pString5 *string
+	if source.Configuration != nil {
+		xstring5 := *source.Configuration
+		pString5 = &xstring5
+	}
+	v1beta1Dependency.Configuration = pString5
+	var pString6 *string
+	if source.Function != nil {
+		xstring6 := *source.Function
+		pString6 = &xstring6
+	}
+	v1beta1Dependency.Function = pString6
+	var pString7 *string
+	if source.API != nil {
+		xstring7 := *source.API
+		pString7 = &xstring7
+	}
+	v1beta1Dependency.API = pString7
+	var pString8 *string
+	if source.Channel != nil {
+		xstring8 := *source.Channel
+		pString8 = &xstring8
+	}
+	v1beta1Dependency.Channel = pString8
+	var v1beta1AlternativeDependencyList []AlternativeDependency
+	if source.AnyOf != nil {
+		v1beta1AlternativeDependencyList = make([]AlternativeDependency, len(source.AnyOf))
+		for i := 0; i < len(source.AnyOf); i++ {
+			v1beta1AlternativeDependencyList[i] = c.v1AlternativeDependencyToV1beta1AlternativeDependency(source.AnyOf[i])
+		}
+	}
+	v1beta1Dependency.AnyOf = v1beta1AlternativeDependencyList
 	v1beta1Dependency.Version = source.Version
 	return v1beta1Dependency
 }
@@ -75,6 +155,14 @@ func (c *GeneratedFromHubConverter) v1MetaSpecToV1beta1MetaSpec(source v1.MetaSp
 		}
 	}
 	v1beta1MetaSpec.DependsOn = v1beta1DependencyList
+	var stringList []string
+	if source.RequiredFeatures != nil {
+		stringList = make([]string, len(source.RequiredFeatures))
+		for i := 0; i < len(source.RequiredFeatures); i++ {
+			stringList[i] = source.RequiredFeatures[i]
+		}
+	}
+	v1beta1MetaSpec.RequiredFeatures = stringList
 	return v1beta1MetaSpec
 }
 func (c *GeneratedFromHubConverter) v1TypeMetaToV1TypeMeta(source v11.TypeMeta) v11.TypeMeta {
@@ -102,6 +190,7 @@ func (c *GeneratedToHubConverter) pV1beta1CrossplaneConstraintsToPV1CrossplaneCo
 	if source != nil {
 		var v1CrossplaneConstraints v1.CrossplaneConstraints
 		v1CrossplaneConstraints.Version = (*source).Version
+		v1CrossplaneConstraints.Runtime = (*source).Runtime
 		pV1CrossplaneConstraints = &v1CrossplaneConstraints
 	}
 	return pV1CrossplaneConstraints
@@ -112,26 +201,105 @@ func (c *GeneratedToHubConverter) v1TypeMetaToV1TypeMeta(source v11.TypeMeta) v1
 	v1TypeMeta.APIVersion = source.APIVersion
 	return v1TypeMeta
 }
+func (c *GeneratedToHubConverter) v1beta1AlternativeDependencyToV1AlternativeDependency(source AlternativeDependency) v1.AlternativeDependency {
+	var v1AlternativeDependency v1.AlternativeDependency
+	var pString *string
+	if source.APIVersion != nil {
+		xstring := *source.APIVersion
+		pString = &xstring
+	}
+	v1AlternativeDependency.APIVersion = pString
+	var pString2 *string
+	if source.Kind != nil {
+		xstring2 := *source.Kind
+		pString2 = &xstring2
+	}
+	v1AlternativeDependency.Kind = pString2
+	var pString3 *string
+	if source.Package != nil {
+		xstring3 := *source.Package
+		pString3 = &xstring3
+	}
+	v1AlternativeDependency.Package = pString3
+	var pString4 *string
+	if source.Provider != nil {
+		xstring4 := *source.Provider
+		pString4 = &xstring4
+	}
+	v1AlternativeDependency.Provider = pString4
+	var pString5 *string
+	if source.Configuration != nil {
+		xstring5 := *source.Configuration
+		pString5 = &xstring5
+	}
+	v1AlternativeDependency.Configuration = pString5
+	var pString6 *string
+	if source.Function != nil {
+		xstring6 := *source.Function
+		pString6 = &xstring6
+	}
+	v1AlternativeDependency.Function = pString6
+	v1AlternativeDependency.Version = source.Version
+	return v1AlternativeDependency
+}
 func (c *GeneratedToHubConverter) v1beta1DependencyToV1Dependency(source Dependency) v1.Dependency {
 	var v1Dependency v1.Dependency
 	var pString *string
-	if source.Provider != nil {
-		xstring := *source.Provider
+	if source.APIVersion != nil {
+		xstring := *source.APIVersion
 		pString = &xstring
 	}
-	v1Dependency.Provider = pString
+	v1Dependency.APIVersion = pString
 	var pString2 *string
-	if source.Configuration != nil {
-		xstring2 := *source.Configuration
+	if source.Kind != nil {
+		xstring2 := *source.Kind
 		pString2 = &xstring2
 	}
-	v1Dependency.Configuration = pString2
+	v1Dependency.Kind = pString2
 	var pString3 *string
-	if source.Function != nil {
-		xstring3 := *source.Function
+	if source.Package != nil {
+		xstring3 := *source.Package
 		pString3 = &xstring3
 	}
-	v1Dependency.Function = pString3
+	v1Dependency.Package = pString3
+	var pString4 *string
+	if source.Provider != nil {
+		xstring4 := *source.Provider
+		pString4 = &xstring4
+	}
+	v1Dependency.Provider = pString4
+	var pString5 *string
+	if source.Configuration != nil {
+		xstring5 := *source.Configuration
+		pString5 = &xstring5
+	}
+	v1Dependency.Configuration = pString5
+	var pString6 *string
+	if source.Function != nil {
+		xstring6 := *source.Function
+		pString6 = &xstring6
+	}
+	v1Dependency.Function = pString6
+	var pString7 *string
+	if source.API != nil {
+		xstring7 := *source.API
+		pString7 = &xstring7
+	}
+	v1Dependency.API = pString7
+	var pString8 *string
+	if source.Channel != nil {
+		xstring8 := *source.Channel
+		pString8 = &xstring8
+	}
+	v1Dependency.Channel = pString8
+	var v1AlternativeDependencyList []v1.AlternativeDependency
+	if source.AnyOf != nil {
+		v1AlternativeDependencyList = make([]v1.AlternativeDependency, len(source.AnyOf))
+		for i := 0; i < len(source.AnyOf); i++ {
+			v1AlternativeDependencyList[i] = c.v1beta1AlternativeDependencyToV1AlternativeDependency(source.AnyOf[i])
+		}
+	}
+	v1Dependency.AnyOf = v1AlternativeDependencyList
 	v1Dependency.Version = source.Version
 	return v1Dependency
 }
@@ -157,5 +325,13 @@ func (c *GeneratedToHubConverter) v1beta1MetaSpecToV1MetaSpec(source MetaSpec) v
 		}
 	}
 	v1MetaSpec.DependsOn = v1DependencyList
+	var stringList []string
+	if source.RequiredFeatures != nil {
+		stringList = make([]string, len(source.RequiredFeatures))
+		for i := 0; i < len(source.RequiredFeatures); i++ {
+			stringList[i] = source.RequiredFeatures[i]
+		}
+	}
+	v1MetaSpec.RequiredFeatures = stringList
 	return v1MetaSpec
 }
@@ -25,16 +25,94 @@ type MetaSpec struct {
 
 	// Dependencies on other packages.
 	DependsOn []Dependency `json:"dependsOn,omitempty"`
+
+	// RequiredFeatures are the Crossplane feature flags the package needs,
+	// e.g. EnableAlphaExternalSecretStores. The package manager won't
+	// activate the package unless all of them are enabled.
+	// +optional
+	RequiredFeatures []string `json:"requiredFeatures,omitempty"`
 }
 
 // CrossplaneConstraints specifies a packages compatibility with Crossplane versions.
 type CrossplaneConstraints struct {
 	// Semantic version constraints of Crossplane that package is compatible with.
 	Version string `json:"version"`
+
+	// Runtime is the minimum version of crossplane-runtime the package was
+	// built with, e.g. v1.14.0. The package manager won't activate a package
+	// whose crossplane-runtime version is known not to work with the running
+	// version of Crossplane.
+	// +optional
+	Runtime string `json:"runtime,omitempty"`
 }
 
-// Dependency is a dependency on another package. One of Provider or Configuration may be supplied.
+// Dependency is a dependency on another package. A dependency should either
+// specify an APIVersion, Kind, and Package, or one of Provider, Configuration,
+// or Function.
 type Dependency struct {
+	// APIVersion of the dependency, e.g. pkg.crossplane.io/v1.
+	// +optional
+	APIVersion *string `json:"apiVersion,omitempty"`
+
+	// Kind of the dependency, e.g. Provider.
+	// +optional
+	Kind *string `json:"kind,omitempty"`
+
+	// Package is the name of the dependency's package image. Only used when
+	// APIVersion and Kind are set.
+	// +optional
+	Package *string `json:"package,omitempty"`
+
+	// Provider is the name of a Provider package image.
+	Provider *string `json:"provider,omitempty"`
+
+	// Configuration is the name of a Configuration package image.
+	Configuration *string `json:"configuration,omitempty"`
+
+	// Function is the name of a Function package image.
+	Function *string `json:"function,omitempty"`
+
+	// API is an API group and version, e.g. databases.example.org/v1alpha1.
+	// The dependency is satisfied by any installed package that defines the
+	// API, rather than by a particular package image. Version is ignored.
+	// +optional
+	API *string `json:"api,omitempty"`
+
+	// Channel is a tag the dependency's authors move from release to
+	// release, e.g. stable or beta. The dependency tracks the version the
+	// channel points to, and is updated when the channel moves. Version is
+	// ignored if a channel is set.
+	// +optional
+	Channel *string `json:"channel,omitempty"`
+
+	// AnyOf is a group of alternative dependencies, any one of which
+	// satisfies the dependency. If none is installed the package manager
+	// installs the first it can resolve. The dependency's other fields are
+	// ignored if AnyOf is set.
+	// +optional
+	AnyOf []AlternativeDependency `json:"anyOf,omitempty"`
+
+	// Version is the semantic version constraints of the dependency image.
+	Version string `json:"version"`
+}
+
+// An AlternativeDependency is one of a group of alternative dependencies. It
+// should either specify an APIVersion, Kind, and Package, or one of Provider,
+// Configuration, or Function.
+type AlternativeDependency struct {
+	// APIVersion of the dependency, e.g. pkg.crossplane.io/v1.
+	// +optional
+	APIVersion *string `json:"apiVersion,omitempty"`
+
+	// Kind of the dependency, e.g. Provider.
+	// +optional
+	Kind *string `json:"kind,omitempty"`
+
+	// Package is the name of the dependency's package image. Only used when
+	// APIVersion and Kind are set.
+	// +optional
+	Package *string `json:"package,omitempty"`
+
 	// Provider is the name of a Provider package image.
 	Provider *string `json:"provider,omitempty"`
 
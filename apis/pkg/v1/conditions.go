@@ -17,6 +17,9 @@ limitations under the License.
 package v1
 
 import (
+	"fmt"
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -30,6 +33,31 @@ const (
 
 	// A TypeHealthy indicates whether a package is healthy.
 	TypeHealthy xpv1.ConditionType = "Healthy"
+
+	// A TypeVerified indicates whether a package's signature has been
+	// verified.
+	TypeVerified xpv1.ConditionType = "Verified"
+
+	// A TypeScanned indicates whether a package's image has been scanned
+	// for vulnerabilities, and whether vulnerabilities that block activation
+	// were found.
+	TypeScanned xpv1.ConditionType = "Scanned"
+
+	// A TypeFetched indicates whether a package revision's image has been
+	// fetched.
+	TypeFetched xpv1.ConditionType = "Fetched"
+
+	// A TypeRuntimeHealthy indicates whether a package revision's runtime,
+	// e.g. a provider's Deployment, is healthy.
+	TypeRuntimeHealthy xpv1.ConditionType = "RuntimeHealthy"
+
+	// A TypeAPIsEstablished indicates whether the package manager established
+	// the objects, e.g. CRDs, contained in a package revision.
+	TypeAPIsEstablished xpv1.ConditionType = "APIsEstablished"
+
+	// A TypeDependenciesSatisfied indicates whether a package revision's
+	// dependencies are installed and healthy.
+	TypeDependenciesSatisfied xpv1.ConditionType = "DependenciesSatisfied"
 )
 
 // Reasons a package is or is not installed.
@@ -40,6 +68,45 @@ const (
 	ReasonUnhealthy     xpv1.ConditionReason = "UnhealthyPackageRevision"
 	ReasonHealthy       xpv1.ConditionReason = "HealthyPackageRevision"
 	ReasonUnknownHealth xpv1.ConditionReason = "UnknownPackageRevisionHealth"
+
+	ReasonContentPolicyViolation        xpv1.ConditionReason = "ContentPolicyViolation"
+	ReasonRequiredFeaturesDisabled      xpv1.ConditionReason = "RequiredFeaturesDisabled"
+	ReasonIncompatibleCrossplaneVersion xpv1.ConditionReason = "IncompatibleCrossplaneVersion"
+)
+
+// Reasons a package revision's runtime is or is not healthy, its APIs are or
+// are not established, and its dependencies are or are not satisfied.
+const (
+	ReasonRuntimeHealthy          xpv1.ConditionReason = "RuntimeHealthy"
+	ReasonRuntimeUnhealthy        xpv1.ConditionReason = "RuntimeUnhealthy"
+	ReasonRuntimeDraining         xpv1.ConditionReason = "RuntimeDraining"
+	ReasonRuntimeDrained          xpv1.ConditionReason = "RuntimeDrained"
+	ReasonAPIsEstablished         xpv1.ConditionReason = "APIsEstablished"
+	ReasonAPIsNotEstablished      xpv1.ConditionReason = "APIsNotEstablished"
+	ReasonDependenciesSatisfied   xpv1.ConditionReason = "DependenciesSatisfied"
+	ReasonDependenciesUnsatisfied xpv1.ConditionReason = "DependenciesUnsatisfied"
+	ReasonDependencyCycle         xpv1.ConditionReason = "DependencyCycle"
+)
+
+// Reasons a package is or is not verified.
+const (
+	ReasonSignatureVerified            xpv1.ConditionReason = "SignatureVerified"
+	ReasonSignatureVerificationFailed  xpv1.ConditionReason = "SignatureVerificationFailed"
+	ReasonSignatureVerificationSkipped xpv1.ConditionReason = "SignatureVerificationSkipped"
+)
+
+// Reasons a package is or is not scanned.
+const (
+	ReasonVulnerabilityScanPassed xpv1.ConditionReason = "VulnerabilityScanPassed"
+	ReasonVulnerabilitiesFound    xpv1.ConditionReason = "VulnerabilitiesFound"
+	ReasonVulnerabilityScanFailed xpv1.ConditionReason = "VulnerabilityScanFailed"
+)
+
+// Reasons a package revision's image is or is not fetched.
+const (
+	ReasonFetched           xpv1.ConditionReason = "Fetched"
+	ReasonFetchFailed       xpv1.ConditionReason = "FetchFailed"
+	ReasonSourceUnavailable xpv1.ConditionReason = "SourceUnavailable"
 )
 
 // Unpacking indicates that the package manager is waiting for a package
@@ -104,3 +171,243 @@ func UnknownHealth() xpv1.Condition {
 		Reason:             ReasonUnknownHealth,
 	}
 }
+
+// ContentPolicyViolation indicates that the current revision is unhealthy
+// because its package contains objects the package manager doesn't allow.
+func ContentPolicyViolation() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeHealthy,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonContentPolicyViolation,
+	}
+}
+
+// RequiredFeaturesDisabled indicates that the current revision is unhealthy
+// because its package requires Crossplane features that aren't enabled.
+func RequiredFeaturesDisabled() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeHealthy,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRequiredFeaturesDisabled,
+	}
+}
+
+// IncompatibleCrossplaneVersion indicates that the current revision is
+// unhealthy because the running version of Crossplane doesn't satisfy its
+// package's Crossplane version constraint.
+func IncompatibleCrossplaneVersion() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeHealthy,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonIncompatibleCrossplaneVersion,
+	}
+}
+
+// RuntimeHealthy indicates that a package revision's runtime is healthy.
+func RuntimeHealthy() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeRuntimeHealthy,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRuntimeHealthy,
+	}
+}
+
+// RuntimeUnhealthy indicates that a package revision's runtime is unhealthy.
+func RuntimeUnhealthy() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeRuntimeHealthy,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRuntimeUnhealthy,
+	}
+}
+
+// RuntimeDraining indicates that an inactive package revision's runtime is
+// still running while the active package revision's runtime takes over. The
+// runtime is going away, so it isn't considered healthy.
+func RuntimeDraining() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeRuntimeHealthy,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRuntimeDraining,
+	}
+}
+
+// RuntimeDrained indicates that an inactive package revision's runtime was
+// stopped after the active package revision's runtime took over.
+func RuntimeDrained() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeRuntimeHealthy,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRuntimeDrained,
+	}
+}
+
+// APIsEstablished indicates that the package manager established the objects
+// contained in a package revision.
+func APIsEstablished() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeAPIsEstablished,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonAPIsEstablished,
+	}
+}
+
+// APIsNotEstablished indicates that the package manager couldn't establish the
+// objects contained in a package revision.
+func APIsNotEstablished() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeAPIsEstablished,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonAPIsNotEstablished,
+	}
+}
+
+// DependenciesSatisfied indicates that a package revision's dependencies are
+// installed and healthy.
+func DependenciesSatisfied() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDependenciesSatisfied,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDependenciesSatisfied,
+	}
+}
+
+// DependenciesUnsatisfied indicates that some of a package revision's
+// dependencies are missing, invalid, or unhealthy.
+func DependenciesUnsatisfied() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDependenciesSatisfied,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDependenciesUnsatisfied,
+	}
+}
+
+// DependencyCycle indicates that a package revision's dependencies can't be
+// satisfied because the package depends on itself, directly or transitively.
+func DependencyCycle() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDependenciesSatisfied,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDependencyCycle,
+	}
+}
+
+// SignatureVerified indicates that the package revision's signature was
+// verified.
+func SignatureVerified() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeVerified,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonSignatureVerified,
+	}
+}
+
+// SignatureVerificationFailed indicates that the package revision's signature
+// could not be verified.
+func SignatureVerificationFailed() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeVerified,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonSignatureVerificationFailed,
+	}
+}
+
+// SignatureVerificationSkipped indicates that the package revision's signature
+// was not verified, because no ImageConfig configures its verification.
+func SignatureVerificationSkipped() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeVerified,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonSignatureVerificationSkipped,
+		Message:            "No ImageConfig configures verification of this package's signature",
+	}
+}
+
+// VulnerabilityScanPassed indicates that the package revision's image was
+// scanned, and no vulnerabilities that block activation were found.
+func VulnerabilityScanPassed() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeScanned,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonVulnerabilityScanPassed,
+	}
+}
+
+// VulnerabilitiesFound indicates that the package revision's image was
+// scanned, and vulnerabilities that block activation were found.
+func VulnerabilitiesFound() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeScanned,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonVulnerabilitiesFound,
+	}
+}
+
+// VulnerabilityScanFailed indicates that the package revision's image could
+// not be scanned.
+func VulnerabilityScanFailed() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeScanned,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonVulnerabilityScanFailed,
+	}
+}
+
+// Fetched indicates that the package manager fetched a package revision's
+// image.
+func Fetched() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeFetched,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonFetched,
+	}
+}
+
+// FetchFailed indicates that the package manager failed to fetch a package
+// revision's image on the supplied attempt. A nil retry time indicates that the
+// failure isn't considered transient, or that retries are exhausted.
+func FetchFailed(attempt int64, retry *metav1.Time, err error) xpv1.Condition {
+	msg := fmt.Sprintf("Attempt %d failed: %s", attempt, err)
+	if retry != nil {
+		msg = fmt.Sprintf("Attempt %d failed, retrying at %s: %s", attempt, retry.UTC().Format(time.RFC3339), err)
+	}
+	return xpv1.Condition{
+		Type:               TypeFetched,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonFetchFailed,
+		Message:            msg,
+	}
+}
+
+// SourceUnavailable indicates that the package manager couldn't fetch an
+// established package revision's image because neither the package cache nor
+// the registry has it any more. The revision's objects and runtime are left
+// as they are.
+func SourceUnavailable() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeFetched,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonSourceUnavailable,
+	}
+}
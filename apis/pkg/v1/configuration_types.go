@@ -36,6 +36,8 @@ import (
 // +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="INSTALLED",type="string",JSONPath=".status.conditions[?(@.type=='Installed')].status"
 // +kubebuilder:printcolumn:name="HEALTHY",type="string",JSONPath=".status.conditions[?(@.type=='Healthy')].status"
+// +kubebuilder:printcolumn:name="APIS-ESTABLISHED",type="string",JSONPath=".status.conditions[?(@.type=='APIsEstablished')].status",priority=1
+// +kubebuilder:printcolumn:name="DEPS-SATISFIED",type="string",JSONPath=".status.conditions[?(@.type=='DependenciesSatisfied')].status",priority=1
 // +kubebuilder:printcolumn:name="PACKAGE",type="string",JSONPath=".spec.package"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,pkg}
@@ -51,6 +53,13 @@ type Configuration struct {
 // configuration to Crossplane.
 type ConfigurationSpec struct {
 	PackageSpec `json:",inline"`
+
+	// Values used to render the credentials of any ProviderConfigs the
+	// package includes. String fields under a ProviderConfig's
+	// spec.credentials are rendered as Go templates, so a field like
+	// {{ .Values.secretName }} is replaced with the secretName value.
+	// +optional
+	Values map[string]string `json:"values,omitempty"`
 }
 
 // ConfigurationStatus represents the observed state of a Configuration.
@@ -91,8 +100,19 @@ type ConfigurationRevision struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec   PackageRevisionSpec   `json:"spec,omitempty"`
-	Status PackageRevisionStatus `json:"status,omitempty"`
+	Spec   ConfigurationRevisionSpec `json:"spec,omitempty"`
+	Status PackageRevisionStatus     `json:"status,omitempty"`
+}
+
+// ConfigurationRevisionSpec specifies configuration for a
+// ConfigurationRevision.
+type ConfigurationRevisionSpec struct {
+	PackageRevisionSpec `json:",inline"`
+
+	// Values used to render the credentials of any ProviderConfigs the
+	// package includes.
+	// +optional
+	Values map[string]string `json:"values,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -35,6 +35,9 @@ import (
 // +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="INSTALLED",type="string",JSONPath=".status.conditions[?(@.type=='Installed')].status"
 // +kubebuilder:printcolumn:name="HEALTHY",type="string",JSONPath=".status.conditions[?(@.type=='Healthy')].status"
+// +kubebuilder:printcolumn:name="RUNTIME-HEALTHY",type="string",JSONPath=".status.conditions[?(@.type=='RuntimeHealthy')].status",priority=1
+// +kubebuilder:printcolumn:name="APIS-ESTABLISHED",type="string",JSONPath=".status.conditions[?(@.type=='APIsEstablished')].status",priority=1
+// +kubebuilder:printcolumn:name="DEPS-SATISFIED",type="string",JSONPath=".status.conditions[?(@.type=='DependenciesSatisfied')].status",priority=1
 // +kubebuilder:printcolumn:name="PACKAGE",type="string",JSONPath=".spec.package"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,pkg}
@@ -17,6 +17,8 @@ limitations under the License.
 package v1
 
 import (
+	"sort"
+
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -41,6 +43,23 @@ const (
 	// revisions, and can be used to select all provider revisions that belong
 	// to a particular family. It is not added to providers, only revisions.
 	LabelProviderFamily = "pkg.crossplane.io/provider-family"
+
+	// AnnotationOverrides may be set on a Composition in a Configuration
+	// package to replace the Composition of the same name installed by another
+	// Configuration. Its value must be the name of the Configuration whose
+	// Composition is replaced.
+	AnnotationOverrides = "pkg.crossplane.io/overrides"
+
+	// AnnotationRequiresAPIs may be set on an object in a package to install
+	// it only if the API server serves all of the APIs it requires. Its value
+	// is a comma separated list of API versions, for example
+	// monitoring.coreos.com/v1, or kinds, for example
+	// monitoring.coreos.com/v1/ServiceMonitor.
+	AnnotationRequiresAPIs = "pkg.crossplane.io/requires-apis"
+
+	// AnnotationForceDelete may be set to "true" on a package to allow it to
+	// be deleted while other packages depend on it.
+	AnnotationForceDelete = "pkg.crossplane.io/force-delete"
 )
 
 var (
@@ -52,6 +71,16 @@ var (
 	ManualActivation RevisionActivationPolicy = "Manual"
 )
 
+var (
+	// ManualDependencyUpgrade indicates that a user will manually upgrade a
+	// package's dependencies.
+	ManualDependencyUpgrade DependencyUpgradePolicy = "Manual"
+	// AutomaticDependencyUpgrade indicates that the package manager should
+	// automatically upgrade a package's dependencies to satisfy its version
+	// constraints.
+	AutomaticDependencyUpgrade DependencyUpgradePolicy = "Automatic"
+)
+
 // RefNames converts a slice of LocalObjectReferences to a slice of strings.
 func RefNames(refs []corev1.LocalObjectReference) []string {
 	stringRefs := make([]string, len(refs))
@@ -97,6 +126,12 @@ type Package interface { //nolint:interfacebloat // TODO(negz): Could we break t
 	GetPackagePullPolicy() *corev1.PullPolicy
 	SetPackagePullPolicy(i *corev1.PullPolicy)
 
+	GetImageSource() *ImageSource
+	SetImageSource(s *ImageSource)
+
+	GetVersionConstraint() *string
+	SetVersionConstraint(c *string)
+
 	GetRevisionHistoryLimit() *int64
 	SetRevisionHistoryLimit(l *int64)
 
@@ -112,8 +147,20 @@ type Package interface { //nolint:interfacebloat // TODO(negz): Could we break t
 	GetSkipDependencyResolution() *bool
 	SetSkipDependencyResolution(skip *bool)
 
+	GetDependencyUpgradePolicy() *DependencyUpgradePolicy
+	SetDependencyUpgradePolicy(d *DependencyUpgradePolicy)
+
+	GetPaused() *bool
+	SetPaused(paused *bool)
+
 	GetCommonLabels() map[string]string
 	SetCommonLabels(l map[string]string)
+
+	GetFootprint() *PackageFootprint
+	SetFootprint(f *PackageFootprint)
+
+	GetDependencyEdges() (dependsOn, installedBy []DependencyEdge)
+	SetDependencyEdges(dependsOn, installedBy []DependencyEdge)
 }
 
 // GetCondition of this Provider.
@@ -171,6 +218,26 @@ func (p *Provider) SetPackagePullPolicy(i *corev1.PullPolicy) {
 	p.Spec.PackagePullPolicy = i
 }
 
+// GetImageSource of this Provider.
+func (p *Provider) GetImageSource() *ImageSource {
+	return p.Spec.ImageSource
+}
+
+// SetImageSource of this Provider.
+func (p *Provider) SetImageSource(s *ImageSource) {
+	p.Spec.ImageSource = s
+}
+
+// GetVersionConstraint of this Provider.
+func (p *Provider) GetVersionConstraint() *string {
+	return p.Spec.VersionConstraint
+}
+
+// SetVersionConstraint of this Provider.
+func (p *Provider) SetVersionConstraint(c *string) {
+	p.Spec.VersionConstraint = c
+}
+
 // GetRevisionHistoryLimit of this Provider.
 func (p *Provider) GetRevisionHistoryLimit() *int64 {
 	return p.Spec.RevisionHistoryLimit
@@ -231,6 +298,26 @@ func (p *Provider) SetSkipDependencyResolution(b *bool) {
 	p.Spec.SkipDependencyResolution = b
 }
 
+// GetPaused of this Provider.
+func (p *Provider) GetPaused() *bool {
+	return p.Spec.Paused
+}
+
+// SetPaused of this Provider.
+func (p *Provider) SetPaused(b *bool) {
+	p.Spec.Paused = b
+}
+
+// GetDependencyUpgradePolicy of this Provider.
+func (p *Provider) GetDependencyUpgradePolicy() *DependencyUpgradePolicy {
+	return p.Spec.DependencyUpgradePolicy
+}
+
+// SetDependencyUpgradePolicy of this Provider.
+func (p *Provider) SetDependencyUpgradePolicy(d *DependencyUpgradePolicy) {
+	p.Spec.DependencyUpgradePolicy = d
+}
+
 // GetCurrentIdentifier of this Provider.
 func (p *Provider) GetCurrentIdentifier() string {
 	return p.Status.CurrentIdentifier
@@ -251,6 +338,27 @@ func (p *Provider) SetCommonLabels(l map[string]string) {
 	p.Spec.CommonLabels = l
 }
 
+// GetFootprint of this Provider.
+func (p *Provider) GetFootprint() *PackageFootprint {
+	return p.Status.Footprint
+}
+
+// SetFootprint of this Provider.
+func (p *Provider) SetFootprint(f *PackageFootprint) {
+	p.Status.Footprint = f
+}
+
+// GetDependencyEdges of this Provider.
+func (p *Provider) GetDependencyEdges() (dependsOn, installedBy []DependencyEdge) {
+	return p.Status.DependsOn, p.Status.InstalledBy
+}
+
+// SetDependencyEdges of this Provider.
+func (p *Provider) SetDependencyEdges(dependsOn, installedBy []DependencyEdge) {
+	p.Status.DependsOn = dependsOn
+	p.Status.InstalledBy = installedBy
+}
+
 // GetTLSServerSecretName of this Provider.
 func (p *Provider) GetTLSServerSecretName() *string {
 	return GetSecretNameWithSuffix(p.GetName(), TLSServerSecretNameSuffix)
@@ -316,6 +424,26 @@ func (p *Configuration) SetPackagePullPolicy(i *corev1.PullPolicy) {
 	p.Spec.PackagePullPolicy = i
 }
 
+// GetImageSource of this Configuration.
+func (p *Configuration) GetImageSource() *ImageSource {
+	return p.Spec.ImageSource
+}
+
+// SetImageSource of this Configuration.
+func (p *Configuration) SetImageSource(s *ImageSource) {
+	p.Spec.ImageSource = s
+}
+
+// GetVersionConstraint of this Configuration.
+func (p *Configuration) GetVersionConstraint() *string {
+	return p.Spec.VersionConstraint
+}
+
+// SetVersionConstraint of this Configuration.
+func (p *Configuration) SetVersionConstraint(c *string) {
+	p.Spec.VersionConstraint = c
+}
+
 // GetRevisionHistoryLimit of this Configuration.
 func (p *Configuration) GetRevisionHistoryLimit() *int64 {
 	return p.Spec.RevisionHistoryLimit
@@ -356,6 +484,26 @@ func (p *Configuration) SetSkipDependencyResolution(b *bool) {
 	p.Spec.SkipDependencyResolution = b
 }
 
+// GetPaused of this Configuration.
+func (p *Configuration) GetPaused() *bool {
+	return p.Spec.Paused
+}
+
+// SetPaused of this Configuration.
+func (p *Configuration) SetPaused(b *bool) {
+	p.Spec.Paused = b
+}
+
+// GetDependencyUpgradePolicy of this Configuration.
+func (p *Configuration) GetDependencyUpgradePolicy() *DependencyUpgradePolicy {
+	return p.Spec.DependencyUpgradePolicy
+}
+
+// SetDependencyUpgradePolicy of this Configuration.
+func (p *Configuration) SetDependencyUpgradePolicy(d *DependencyUpgradePolicy) {
+	p.Spec.DependencyUpgradePolicy = d
+}
+
 // GetCurrentIdentifier of this Configuration.
 func (p *Configuration) GetCurrentIdentifier() string {
 	return p.Status.CurrentIdentifier
@@ -376,6 +524,57 @@ func (p *Configuration) SetCommonLabels(l map[string]string) {
 	p.Spec.CommonLabels = l
 }
 
+// GetFootprint of this Configuration.
+func (p *Configuration) GetFootprint() *PackageFootprint {
+	return p.Status.Footprint
+}
+
+// SetFootprint of this Configuration.
+func (p *Configuration) SetFootprint(f *PackageFootprint) {
+	p.Status.Footprint = f
+}
+
+// GetDependencyEdges of this Configuration.
+func (p *Configuration) GetDependencyEdges() (dependsOn, installedBy []DependencyEdge) {
+	return p.Status.DependsOn, p.Status.InstalledBy
+}
+
+// SetDependencyEdges of this Configuration.
+func (p *Configuration) SetDependencyEdges(dependsOn, installedBy []DependencyEdge) {
+	p.Status.DependsOn = dependsOn
+	p.Status.InstalledBy = installedBy
+}
+
+// GetValues of this Configuration.
+func (p *Configuration) GetValues() map[string]string {
+	return p.Spec.Values
+}
+
+// SetValues of this Configuration.
+func (p *Configuration) SetValues(v map[string]string) {
+	p.Spec.Values = v
+}
+
+// PackageWithValues is the interface satisfied by packages whose objects may
+// be rendered using values.
+// +k8s:deepcopy-gen=false
+type PackageWithValues interface {
+	Package
+
+	GetValues() map[string]string
+	SetValues(v map[string]string)
+}
+
+// PackageRevisionWithValues is the interface satisfied by revisions of
+// packages whose objects may be rendered using values.
+// +k8s:deepcopy-gen=false
+type PackageRevisionWithValues interface {
+	PackageRevision
+
+	GetValues() map[string]string
+	SetValues(v map[string]string)
+}
+
 // PackageRevisionWithRuntime is the interface satisfied by revision of packages
 // with runtime types.
 // +k8s:deepcopy-gen=false
@@ -393,6 +592,9 @@ type PackageRevisionWithRuntime interface { //nolint:interfacebloat // TODO(negz
 
 	GetTLSClientSecretName() *string
 	SetTLSClientSecretName(n *string)
+
+	GetRuntimeStatus() *PackageRevisionRuntimeStatus
+	SetRuntimeStatus(s *PackageRevisionRuntimeStatus)
 }
 
 // PackageRevision is the interface satisfied by package revision types.
@@ -415,6 +617,9 @@ type PackageRevision interface { //nolint:interfacebloat // TODO(negz): Could we
 	GetPackagePullPolicy() *corev1.PullPolicy
 	SetPackagePullPolicy(i *corev1.PullPolicy)
 
+	GetImageSource() *ImageSource
+	SetImageSource(s *ImageSource)
+
 	GetDesiredState() PackageRevisionDesiredState
 	SetDesiredState(d PackageRevisionDesiredState)
 
@@ -427,11 +632,41 @@ type PackageRevision interface { //nolint:interfacebloat // TODO(negz): Could we
 	GetSkipDependencyResolution() *bool
 	SetSkipDependencyResolution(skip *bool)
 
+	GetDependencyUpgradePolicy() *DependencyUpgradePolicy
+	SetDependencyUpgradePolicy(d *DependencyUpgradePolicy)
+
+	GetPaused() *bool
+	SetPaused(paused *bool)
+
 	GetDependencyStatus() (found, installed, invalid int64)
 	SetDependencyStatus(found, installed, invalid int64)
 
+	GetContents() *PackageContents
+	SetContents(c *PackageContents)
+
+	GetFetchAttempts() int64
+	SetFetchAttempts(n int64)
+
+	GetCrossplaneVersion() string
+	SetCrossplaneVersion(v string)
+
+	GetPackageImage() *PackageImage
+	SetPackageImage(i *PackageImage)
+
+	GetPackageBuild() *PackageBuild
+	SetPackageBuild(b *PackageBuild)
+
 	GetCommonLabels() map[string]string
 	SetCommonLabels(l map[string]string)
+
+	GetFootprint() *PackageFootprint
+	SetFootprint(f *PackageFootprint)
+
+	GetDependencyEdges() (dependsOn, installedBy []DependencyEdge)
+	SetDependencyEdges(dependsOn, installedBy []DependencyEdge)
+
+	GetVulnerabilities() *PackageVulnerabilities
+	SetVulnerabilities(v *PackageVulnerabilities)
 }
 
 // GetCondition of this ProviderRevision.
@@ -451,12 +686,12 @@ func (p *ProviderRevision) CleanConditions() {
 
 // GetObjects of this ProviderRevision.
 func (p *ProviderRevision) GetObjects() []xpv1.TypedReference {
-	return p.Status.ObjectRefs
+	return getObjectRefs(&p.Status)
 }
 
 // SetObjects of this ProviderRevision.
 func (p *ProviderRevision) SetObjects(c []xpv1.TypedReference) {
-	p.Status.ObjectRefs = c
+	setObjectRefs(&p.Status, c)
 }
 
 // GetSource of this ProviderRevision.
@@ -489,6 +724,16 @@ func (p *ProviderRevision) SetPackagePullPolicy(i *corev1.PullPolicy) {
 	p.Spec.PackagePullPolicy = i
 }
 
+// GetImageSource of this ProviderRevision.
+func (p *ProviderRevision) GetImageSource() *ImageSource {
+	return p.Spec.ImageSource
+}
+
+// SetImageSource of this ProviderRevision.
+func (p *ProviderRevision) SetImageSource(s *ImageSource) {
+	p.Spec.ImageSource = s
+}
+
 // GetDesiredState of this ProviderRevision.
 func (p *ProviderRevision) GetDesiredState() PackageRevisionDesiredState {
 	return p.Spec.DesiredState
@@ -521,6 +766,56 @@ func (p *ProviderRevision) SetDependencyStatus(found, installed, invalid int64)
 	p.Status.InvalidDependencies = invalid
 }
 
+// GetContents of this ProviderRevision.
+func (p *ProviderRevision) GetContents() *PackageContents {
+	return p.Status.Contents
+}
+
+// SetContents of this ProviderRevision.
+func (p *ProviderRevision) SetContents(c *PackageContents) {
+	p.Status.Contents = c
+}
+
+// GetFetchAttempts of this ProviderRevision.
+func (p *ProviderRevision) GetFetchAttempts() int64 {
+	return p.Status.FetchAttempts
+}
+
+// SetFetchAttempts of this ProviderRevision.
+func (p *ProviderRevision) SetFetchAttempts(n int64) {
+	p.Status.FetchAttempts = n
+}
+
+// GetCrossplaneVersion of this ProviderRevision.
+func (p *ProviderRevision) GetCrossplaneVersion() string {
+	return p.Status.CrossplaneVersion
+}
+
+// SetCrossplaneVersion of this ProviderRevision.
+func (p *ProviderRevision) SetCrossplaneVersion(v string) {
+	p.Status.CrossplaneVersion = v
+}
+
+// GetPackageImage of this ProviderRevision.
+func (p *ProviderRevision) GetPackageImage() *PackageImage {
+	return p.Status.Image
+}
+
+// SetPackageImage of this ProviderRevision.
+func (p *ProviderRevision) SetPackageImage(i *PackageImage) {
+	p.Status.Image = i
+}
+
+// GetPackageBuild of this ProviderRevision.
+func (p *ProviderRevision) GetPackageBuild() *PackageBuild {
+	return p.Status.Build
+}
+
+// SetPackageBuild of this ProviderRevision.
+func (p *ProviderRevision) SetPackageBuild(b *PackageBuild) {
+	p.Status.Build = b
+}
+
 // GetIgnoreCrossplaneConstraints of this ProviderRevision.
 func (p *ProviderRevision) GetIgnoreCrossplaneConstraints() *bool {
 	return p.Spec.IgnoreCrossplaneConstraints
@@ -561,6 +856,26 @@ func (p *ProviderRevision) SetSkipDependencyResolution(b *bool) {
 	p.Spec.SkipDependencyResolution = b
 }
 
+// GetPaused of this ProviderRevision.
+func (p *ProviderRevision) GetPaused() *bool {
+	return p.Spec.Paused
+}
+
+// SetPaused of this ProviderRevision.
+func (p *ProviderRevision) SetPaused(b *bool) {
+	p.Spec.Paused = b
+}
+
+// GetDependencyUpgradePolicy of this ProviderRevision.
+func (p *ProviderRevision) GetDependencyUpgradePolicy() *DependencyUpgradePolicy {
+	return p.Spec.DependencyUpgradePolicy
+}
+
+// SetDependencyUpgradePolicy of this ProviderRevision.
+func (p *ProviderRevision) SetDependencyUpgradePolicy(d *DependencyUpgradePolicy) {
+	p.Spec.DependencyUpgradePolicy = d
+}
+
 // GetTLSServerSecretName of this ProviderRevision.
 func (p *ProviderRevision) GetTLSServerSecretName() *string {
 	return p.Spec.TLSServerSecretName
@@ -581,6 +896,16 @@ func (p *ProviderRevision) SetTLSClientSecretName(s *string) {
 	p.Spec.TLSClientSecretName = s
 }
 
+// GetRuntimeStatus of this ProviderRevision.
+func (p *ProviderRevision) GetRuntimeStatus() *PackageRevisionRuntimeStatus {
+	return p.Status.Runtime
+}
+
+// SetRuntimeStatus of this ProviderRevision.
+func (p *ProviderRevision) SetRuntimeStatus(s *PackageRevisionRuntimeStatus) {
+	p.Status.Runtime = s
+}
+
 // GetCommonLabels of this ProviderRevision.
 func (p *ProviderRevision) GetCommonLabels() map[string]string {
 	return p.Spec.CommonLabels
@@ -591,6 +916,37 @@ func (p *ProviderRevision) SetCommonLabels(l map[string]string) {
 	p.Spec.CommonLabels = l
 }
 
+// GetFootprint of this ProviderRevision.
+func (p *ProviderRevision) GetFootprint() *PackageFootprint {
+	return p.Status.Footprint
+}
+
+// SetFootprint of this ProviderRevision.
+func (p *ProviderRevision) SetFootprint(f *PackageFootprint) {
+	p.Status.Footprint = f
+}
+
+// GetDependencyEdges of this ProviderRevision.
+func (p *ProviderRevision) GetDependencyEdges() (dependsOn, installedBy []DependencyEdge) {
+	return p.Status.DependsOn, p.Status.InstalledBy
+}
+
+// SetDependencyEdges of this ProviderRevision.
+func (p *ProviderRevision) SetDependencyEdges(dependsOn, installedBy []DependencyEdge) {
+	p.Status.DependsOn = dependsOn
+	p.Status.InstalledBy = installedBy
+}
+
+// GetVulnerabilities of this ProviderRevision.
+func (p *ProviderRevision) GetVulnerabilities() *PackageVulnerabilities {
+	return p.Status.Vulnerabilities
+}
+
+// SetVulnerabilities of this ProviderRevision.
+func (p *ProviderRevision) SetVulnerabilities(v *PackageVulnerabilities) {
+	p.Status.Vulnerabilities = v
+}
+
 // GetCondition of this ConfigurationRevision.
 func (p *ConfigurationRevision) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
 	return p.Status.GetCondition(ct)
@@ -608,12 +964,12 @@ func (p *ConfigurationRevision) CleanConditions() {
 
 // GetObjects of this ConfigurationRevision.
 func (p *ConfigurationRevision) GetObjects() []xpv1.TypedReference {
-	return p.Status.ObjectRefs
+	return getObjectRefs(&p.Status)
 }
 
 // SetObjects of this ConfigurationRevision.
 func (p *ConfigurationRevision) SetObjects(c []xpv1.TypedReference) {
-	p.Status.ObjectRefs = c
+	setObjectRefs(&p.Status, c)
 }
 
 // GetSource of this ConfigurationRevision.
@@ -646,6 +1002,16 @@ func (p *ConfigurationRevision) SetPackagePullPolicy(i *corev1.PullPolicy) {
 	p.Spec.PackagePullPolicy = i
 }
 
+// GetImageSource of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetImageSource() *ImageSource {
+	return p.Spec.ImageSource
+}
+
+// SetImageSource of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetImageSource(s *ImageSource) {
+	p.Spec.ImageSource = s
+}
+
 // GetDesiredState of this ConfigurationRevision.
 func (p *ConfigurationRevision) GetDesiredState() PackageRevisionDesiredState {
 	return p.Spec.DesiredState
@@ -678,6 +1044,56 @@ func (p *ConfigurationRevision) SetDependencyStatus(found, installed, invalid in
 	p.Status.InvalidDependencies = invalid
 }
 
+// GetContents of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetContents() *PackageContents {
+	return p.Status.Contents
+}
+
+// SetContents of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetContents(c *PackageContents) {
+	p.Status.Contents = c
+}
+
+// GetFetchAttempts of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetFetchAttempts() int64 {
+	return p.Status.FetchAttempts
+}
+
+// SetFetchAttempts of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetFetchAttempts(n int64) {
+	p.Status.FetchAttempts = n
+}
+
+// GetCrossplaneVersion of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetCrossplaneVersion() string {
+	return p.Status.CrossplaneVersion
+}
+
+// SetCrossplaneVersion of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetCrossplaneVersion(v string) {
+	p.Status.CrossplaneVersion = v
+}
+
+// GetPackageImage of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetPackageImage() *PackageImage {
+	return p.Status.Image
+}
+
+// SetPackageImage of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetPackageImage(i *PackageImage) {
+	p.Status.Image = i
+}
+
+// GetPackageBuild of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetPackageBuild() *PackageBuild {
+	return p.Status.Build
+}
+
+// SetPackageBuild of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetPackageBuild(b *PackageBuild) {
+	p.Status.Build = b
+}
+
 // GetIgnoreCrossplaneConstraints of this ConfigurationRevision.
 func (p *ConfigurationRevision) GetIgnoreCrossplaneConstraints() *bool {
 	return p.Spec.IgnoreCrossplaneConstraints
@@ -698,6 +1114,26 @@ func (p *ConfigurationRevision) SetSkipDependencyResolution(b *bool) {
 	p.Spec.SkipDependencyResolution = b
 }
 
+// GetPaused of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetPaused() *bool {
+	return p.Spec.Paused
+}
+
+// SetPaused of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetPaused(b *bool) {
+	p.Spec.Paused = b
+}
+
+// GetDependencyUpgradePolicy of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetDependencyUpgradePolicy() *DependencyUpgradePolicy {
+	return p.Spec.DependencyUpgradePolicy
+}
+
+// SetDependencyUpgradePolicy of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetDependencyUpgradePolicy(d *DependencyUpgradePolicy) {
+	p.Spec.DependencyUpgradePolicy = d
+}
+
 // GetCommonLabels of this ConfigurationRevision.
 func (p *ConfigurationRevision) GetCommonLabels() map[string]string {
 	return p.Spec.CommonLabels
@@ -708,6 +1144,47 @@ func (p *ConfigurationRevision) SetCommonLabels(l map[string]string) {
 	p.Spec.CommonLabels = l
 }
 
+// GetFootprint of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetFootprint() *PackageFootprint {
+	return p.Status.Footprint
+}
+
+// SetFootprint of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetFootprint(f *PackageFootprint) {
+	p.Status.Footprint = f
+}
+
+// GetDependencyEdges of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetDependencyEdges() (dependsOn, installedBy []DependencyEdge) {
+	return p.Status.DependsOn, p.Status.InstalledBy
+}
+
+// SetDependencyEdges of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetDependencyEdges(dependsOn, installedBy []DependencyEdge) {
+	p.Status.DependsOn = dependsOn
+	p.Status.InstalledBy = installedBy
+}
+
+// GetVulnerabilities of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetVulnerabilities() *PackageVulnerabilities {
+	return p.Status.Vulnerabilities
+}
+
+// SetVulnerabilities of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetVulnerabilities(v *PackageVulnerabilities) {
+	p.Status.Vulnerabilities = v
+}
+
+// GetValues of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetValues() map[string]string {
+	return p.Spec.Values
+}
+
+// SetValues of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetValues(v map[string]string) {
+	p.Spec.Values = v
+}
+
 // PackageRevisionList is the interface satisfied by package revision list
 // types.
 // +k8s:deepcopy-gen=false
@@ -816,6 +1293,26 @@ func (f *Function) SetPackagePullPolicy(i *corev1.PullPolicy) {
 	f.Spec.PackagePullPolicy = i
 }
 
+// GetImageSource of this Function.
+func (f *Function) GetImageSource() *ImageSource {
+	return f.Spec.ImageSource
+}
+
+// SetImageSource of this Function.
+func (f *Function) SetImageSource(s *ImageSource) {
+	f.Spec.ImageSource = s
+}
+
+// GetVersionConstraint of this Function.
+func (f *Function) GetVersionConstraint() *string {
+	return f.Spec.VersionConstraint
+}
+
+// SetVersionConstraint of this Function.
+func (f *Function) SetVersionConstraint(c *string) {
+	f.Spec.VersionConstraint = c
+}
+
 // GetRevisionHistoryLimit of this Function.
 func (f *Function) GetRevisionHistoryLimit() *int64 {
 	return f.Spec.RevisionHistoryLimit
@@ -874,6 +1371,26 @@ func (f *Function) SetSkipDependencyResolution(b *bool) {
 	f.Spec.SkipDependencyResolution = b
 }
 
+// GetPaused of this Function.
+func (f *Function) GetPaused() *bool {
+	return f.Spec.Paused
+}
+
+// SetPaused of this Function.
+func (f *Function) SetPaused(b *bool) {
+	f.Spec.Paused = b
+}
+
+// GetDependencyUpgradePolicy of this Function.
+func (f *Function) GetDependencyUpgradePolicy() *DependencyUpgradePolicy {
+	return f.Spec.DependencyUpgradePolicy
+}
+
+// SetDependencyUpgradePolicy of this Function.
+func (f *Function) SetDependencyUpgradePolicy(d *DependencyUpgradePolicy) {
+	f.Spec.DependencyUpgradePolicy = d
+}
+
 // GetCurrentIdentifier of this Function.
 func (f *Function) GetCurrentIdentifier() string {
 	return f.Status.CurrentIdentifier
@@ -894,6 +1411,27 @@ func (f *Function) SetCommonLabels(l map[string]string) {
 	f.Spec.CommonLabels = l
 }
 
+// GetFootprint of this Function.
+func (f *Function) GetFootprint() *PackageFootprint {
+	return f.Status.Footprint
+}
+
+// SetFootprint of this Function.
+func (f *Function) SetFootprint(fp *PackageFootprint) {
+	f.Status.Footprint = fp
+}
+
+// GetDependencyEdges of this Function.
+func (f *Function) GetDependencyEdges() (dependsOn, installedBy []DependencyEdge) {
+	return f.Status.DependsOn, f.Status.InstalledBy
+}
+
+// SetDependencyEdges of this Function.
+func (f *Function) SetDependencyEdges(dependsOn, installedBy []DependencyEdge) {
+	f.Status.DependsOn = dependsOn
+	f.Status.InstalledBy = installedBy
+}
+
 // GetTLSServerSecretName of this Function.
 func (f *Function) GetTLSServerSecretName() *string {
 	return GetSecretNameWithSuffix(f.GetName(), TLSServerSecretNameSuffix)
@@ -921,12 +1459,12 @@ func (r *FunctionRevision) CleanConditions() {
 
 // GetObjects of this FunctionRevision.
 func (r *FunctionRevision) GetObjects() []xpv1.TypedReference {
-	return r.Status.ObjectRefs
+	return getObjectRefs(&r.Status.PackageRevisionStatus)
 }
 
 // SetObjects of this FunctionRevision.
 func (r *FunctionRevision) SetObjects(c []xpv1.TypedReference) {
-	r.Status.ObjectRefs = c
+	setObjectRefs(&r.Status.PackageRevisionStatus, c)
 }
 
 // GetSource of this FunctionRevision.
@@ -959,6 +1497,16 @@ func (r *FunctionRevision) SetPackagePullPolicy(i *corev1.PullPolicy) {
 	r.Spec.PackagePullPolicy = i
 }
 
+// GetImageSource of this FunctionRevision.
+func (r *FunctionRevision) GetImageSource() *ImageSource {
+	return r.Spec.ImageSource
+}
+
+// SetImageSource of this FunctionRevision.
+func (r *FunctionRevision) SetImageSource(s *ImageSource) {
+	r.Spec.ImageSource = s
+}
+
 // GetDesiredState of this FunctionRevision.
 func (r *FunctionRevision) GetDesiredState() PackageRevisionDesiredState {
 	return r.Spec.DesiredState
@@ -991,6 +1539,56 @@ func (r *FunctionRevision) SetDependencyStatus(found, installed, invalid int64)
 	r.Status.InvalidDependencies = invalid
 }
 
+// GetContents of this FunctionRevision.
+func (r *FunctionRevision) GetContents() *PackageContents {
+	return r.Status.Contents
+}
+
+// SetContents of this FunctionRevision.
+func (r *FunctionRevision) SetContents(c *PackageContents) {
+	r.Status.Contents = c
+}
+
+// GetFetchAttempts of this FunctionRevision.
+func (r *FunctionRevision) GetFetchAttempts() int64 {
+	return r.Status.FetchAttempts
+}
+
+// SetFetchAttempts of this FunctionRevision.
+func (r *FunctionRevision) SetFetchAttempts(n int64) {
+	r.Status.FetchAttempts = n
+}
+
+// GetCrossplaneVersion of this FunctionRevision.
+func (r *FunctionRevision) GetCrossplaneVersion() string {
+	return r.Status.CrossplaneVersion
+}
+
+// SetCrossplaneVersion of this FunctionRevision.
+func (r *FunctionRevision) SetCrossplaneVersion(v string) {
+	r.Status.CrossplaneVersion = v
+}
+
+// GetPackageImage of this FunctionRevision.
+func (r *FunctionRevision) GetPackageImage() *PackageImage {
+	return r.Status.Image
+}
+
+// SetPackageImage of this FunctionRevision.
+func (r *FunctionRevision) SetPackageImage(i *PackageImage) {
+	r.Status.Image = i
+}
+
+// GetPackageBuild of this FunctionRevision.
+func (r *FunctionRevision) GetPackageBuild() *PackageBuild {
+	return r.Status.Build
+}
+
+// SetPackageBuild of this FunctionRevision.
+func (r *FunctionRevision) SetPackageBuild(b *PackageBuild) {
+	r.Status.Build = b
+}
+
 // GetIgnoreCrossplaneConstraints of this FunctionRevision.
 func (r *FunctionRevision) GetIgnoreCrossplaneConstraints() *bool {
 	return r.Spec.IgnoreCrossplaneConstraints
@@ -1031,6 +1629,26 @@ func (r *FunctionRevision) SetSkipDependencyResolution(b *bool) {
 	r.Spec.SkipDependencyResolution = b
 }
 
+// GetPaused of this FunctionRevision.
+func (r *FunctionRevision) GetPaused() *bool {
+	return r.Spec.Paused
+}
+
+// SetPaused of this FunctionRevision.
+func (r *FunctionRevision) SetPaused(b *bool) {
+	r.Spec.Paused = b
+}
+
+// GetDependencyUpgradePolicy of this FunctionRevision.
+func (r *FunctionRevision) GetDependencyUpgradePolicy() *DependencyUpgradePolicy {
+	return r.Spec.DependencyUpgradePolicy
+}
+
+// SetDependencyUpgradePolicy of this FunctionRevision.
+func (r *FunctionRevision) SetDependencyUpgradePolicy(d *DependencyUpgradePolicy) {
+	r.Spec.DependencyUpgradePolicy = d
+}
+
 // GetTLSServerSecretName of this FunctionRevision.
 func (r *FunctionRevision) GetTLSServerSecretName() *string {
 	return r.Spec.TLSServerSecretName
@@ -1051,6 +1669,16 @@ func (r *FunctionRevision) SetTLSClientSecretName(s *string) {
 	r.Spec.TLSClientSecretName = s
 }
 
+// GetRuntimeStatus of this FunctionRevision.
+func (r *FunctionRevision) GetRuntimeStatus() *PackageRevisionRuntimeStatus {
+	return r.Status.Runtime
+}
+
+// SetRuntimeStatus of this FunctionRevision.
+func (r *FunctionRevision) SetRuntimeStatus(s *PackageRevisionRuntimeStatus) {
+	r.Status.Runtime = s
+}
+
 // GetCommonLabels of this FunctionRevision.
 func (r *FunctionRevision) GetCommonLabels() map[string]string {
 	return r.Spec.CommonLabels
@@ -1061,6 +1689,37 @@ func (r *FunctionRevision) SetCommonLabels(l map[string]string) {
 	r.Spec.CommonLabels = l
 }
 
+// GetFootprint of this FunctionRevision.
+func (r *FunctionRevision) GetFootprint() *PackageFootprint {
+	return r.Status.Footprint
+}
+
+// SetFootprint of this FunctionRevision.
+func (r *FunctionRevision) SetFootprint(f *PackageFootprint) {
+	r.Status.Footprint = f
+}
+
+// GetDependencyEdges of this FunctionRevision.
+func (r *FunctionRevision) GetDependencyEdges() (dependsOn, installedBy []DependencyEdge) {
+	return r.Status.DependsOn, r.Status.InstalledBy
+}
+
+// SetDependencyEdges of this FunctionRevision.
+func (r *FunctionRevision) SetDependencyEdges(dependsOn, installedBy []DependencyEdge) {
+	r.Status.DependsOn = dependsOn
+	r.Status.InstalledBy = installedBy
+}
+
+// GetVulnerabilities of this FunctionRevision.
+func (r *FunctionRevision) GetVulnerabilities() *PackageVulnerabilities {
+	return r.Status.Vulnerabilities
+}
+
+// SetVulnerabilities of this FunctionRevision.
+func (r *FunctionRevision) SetVulnerabilities(v *PackageVulnerabilities) {
+	r.Status.Vulnerabilities = v
+}
+
 // GetRevisions of this ConfigurationRevisionList.
 func (p *FunctionRevisionList) GetRevisions() []PackageRevision {
 	prs := make([]PackageRevision, len(p.Items))
@@ -1069,3 +1728,69 @@ func (p *FunctionRevisionList) GetRevisions() []PackageRevision {
 	}
 	return prs
 }
+
+// getObjectRefs returns the objects referenced by the supplied status. The
+// grouped references are authoritative; the deprecated per object references
+// are only used for revisions whose status predates the groups.
+func getObjectRefs(s *PackageRevisionStatus) []xpv1.TypedReference {
+	if len(s.ObjectRefGroups) == 0 {
+		return s.ObjectRefs
+	}
+	refs := make([]xpv1.TypedReference, 0, len(s.ObjectRefs))
+	for _, g := range s.ObjectRefGroups {
+		for _, n := range g.Names {
+			refs = append(refs, xpv1.TypedReference{APIVersion: g.APIVersion, Kind: g.Kind, Name: n})
+		}
+	}
+	return refs
+}
+
+// setObjectRefs sets the objects referenced by the supplied status, grouped
+// by API version and kind. Groups are sorted by API version then kind, and
+// names are sorted within a group, so the status doesn't change when the
+// references are supplied in a different order. The deprecated per object
+// references are written too, in the same order, so tooling that reads them
+// keeps working until they're removed.
+func setObjectRefs(s *PackageRevisionStatus, refs []xpv1.TypedReference) {
+	s.ObjectRefs = nil
+	s.ObjectRefGroups = nil
+	if len(refs) == 0 {
+		return
+	}
+
+	s.ObjectRefs = make([]xpv1.TypedReference, len(refs))
+	copy(s.ObjectRefs, refs)
+	sort.SliceStable(s.ObjectRefs, func(i, j int) bool {
+		ri, rj := s.ObjectRefs[i], s.ObjectRefs[j]
+		if ri.APIVersion != rj.APIVersion {
+			return ri.APIVersion < rj.APIVersion
+		}
+		if ri.Kind != rj.Kind {
+			return ri.Kind < rj.Kind
+		}
+		return ri.Name < rj.Name
+	})
+
+	idx := map[[2]string]int{}
+	for _, ref := range refs {
+		k := [2]string{ref.APIVersion, ref.Kind}
+		i, ok := idx[k]
+		if !ok {
+			i = len(s.ObjectRefGroups)
+			idx[k] = i
+			s.ObjectRefGroups = append(s.ObjectRefGroups, ObjectRefGroup{APIVersion: ref.APIVersion, Kind: ref.Kind})
+		}
+		s.ObjectRefGroups[i].Names = append(s.ObjectRefGroups[i].Names, ref.Name)
+	}
+
+	sort.Slice(s.ObjectRefGroups, func(i, j int) bool {
+		gi, gj := s.ObjectRefGroups[i], s.ObjectRefGroups[j]
+		if gi.APIVersion != gj.APIVersion {
+			return gi.APIVersion < gj.APIVersion
+		}
+		return gi.Kind < gj.Kind
+	})
+	for i := range s.ObjectRefGroups {
+		sort.Strings(s.ObjectRefGroups[i].Names)
+	}
+}
@@ -16,6 +16,14 @@ limitations under the License.
 
 package v1
 
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
 var (
 	_ Package = &Provider{}
 	_ Package = &Configuration{}
@@ -33,3 +41,81 @@ var (
 	_ PackageRevisionList = &ConfigurationRevisionList{}
 	_ PackageRevisionList = &FunctionRevisionList{}
 )
+
+func TestObjectRefs(t *testing.T) {
+	type want struct {
+		status PackageRevisionStatus
+		refs   []xpv1.TypedReference
+	}
+	cases := map[string]struct {
+		reason string
+		status PackageRevisionStatus
+		set    []xpv1.TypedReference
+		want   want
+	}{
+		"Grouped": {
+			reason: "References should be grouped by API version and kind, with groups and names sorted, and also written as deprecated per object references.",
+			set: []xpv1.TypedReference{
+				{APIVersion: "v1", Kind: "ServiceAccount", Name: "b"},
+				{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "b"},
+				{APIVersion: "v1", Kind: "ServiceAccount", Name: "a", UID: "cool-uid"},
+				{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "a"},
+			},
+			want: want{
+				status: PackageRevisionStatus{
+					ObjectRefs: []xpv1.TypedReference{
+						{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "a"},
+						{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "b"},
+						{APIVersion: "v1", Kind: "ServiceAccount", Name: "a", UID: "cool-uid"},
+						{APIVersion: "v1", Kind: "ServiceAccount", Name: "b"},
+					},
+					ObjectRefGroups: []ObjectRefGroup{
+						{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Names: []string{"a", "b"}},
+						{APIVersion: "v1", Kind: "ServiceAccount", Names: []string{"a", "b"}},
+					},
+				},
+				refs: []xpv1.TypedReference{
+					{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "a"},
+					{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "b"},
+					{APIVersion: "v1", Kind: "ServiceAccount", Name: "a"},
+					{APIVersion: "v1", Kind: "ServiceAccount", Name: "b"},
+				},
+			},
+		},
+		"ReplacesLegacyRefs": {
+			reason: "Setting references should replace any references from before they were grouped.",
+			status: PackageRevisionStatus{
+				ObjectRefs: []xpv1.TypedReference{{APIVersion: "v1", Kind: "Secret", Name: "old"}},
+			},
+			set: []xpv1.TypedReference{{APIVersion: "v1", Kind: "Secret", Name: "new"}},
+			want: want{
+				status: PackageRevisionStatus{
+					ObjectRefs:      []xpv1.TypedReference{{APIVersion: "v1", Kind: "Secret", Name: "new"}},
+					ObjectRefGroups: []ObjectRefGroup{{APIVersion: "v1", Kind: "Secret", Names: []string{"new"}}},
+				},
+				refs: []xpv1.TypedReference{{APIVersion: "v1", Kind: "Secret", Name: "new"}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pr := &ProviderRevision{Status: tc.status}
+			pr.SetObjects(tc.set)
+			if diff := cmp.Diff(tc.want.status, pr.Status); diff != "" {
+				t.Errorf("\n%s\nSetObjects(...): -want status, +got status:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.refs, pr.GetObjects()); diff != "" {
+				t.Errorf("\n%s\nGetObjects(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGetLegacyObjectRefs(t *testing.T) {
+	legacy := []xpv1.TypedReference{{APIVersion: "v1", Kind: "Secret", Name: "old", UID: "cool-uid"}}
+	pr := &ProviderRevision{Status: PackageRevisionStatus{ObjectRefs: legacy}}
+	if diff := cmp.Diff(legacy, pr.GetObjects()); diff != "" {
+		t.Errorf("GetObjects(): -want, +got:\n%s", diff)
+	}
+}
@@ -22,14 +22,35 @@ import corev1 "k8s.io/api/core/v1"
 // revisions.
 type RevisionActivationPolicy string
 
+// DependencyUpgradePolicy indicates whether the package manager may upgrade a
+// package's dependencies.
+type DependencyUpgradePolicy string
+
 // PackageSpec specifies the desired state of a Package.
 type PackageSpec struct {
-	// Package is the name of the package that is being requested.
+	// Package is the name of the package that is being requested. It's
+	// usually an OCI reference, but may also be an https:// URL, or a file://
+	// URL relative to the package manager's package image directory, from
+	// which a package image tarball is loaded. URLs are intended for
+	// development. Packages loaded from a URL can't have a version
+	// constraint, and are only revisioned again when their URL changes
+	// unless their pull policy is Always.
 	Package string `json:"package"`
 
+	// VersionConstraint is a semantic version constraint, e.g. ">=0.20, <0.22".
+	// If set, the package manager periodically resolves it against the tags
+	// of the package's repository, and installs the highest matching version.
+	// Any tag or digest of the package field is ignored. The constraint is
+	// ignored if the package's pull policy is Never.
+	// +optional
+	VersionConstraint *string `json:"versionConstraint,omitempty"`
+
 	// RevisionActivationPolicy specifies how the package controller should
 	// update from one revision to the next. Options are Automatic or Manual.
-	// Default is Automatic.
+	// Default is Automatic. When Manual, a new revision is staged - fetched,
+	// parsed, and validated - while inactive, and the previously active
+	// revision stays active until the new revision is activated by setting
+	// its desiredState to Active.
 	// +optional
 	// +kubebuilder:default=Automatic
 	RevisionActivationPolicy *RevisionActivationPolicy `json:"revisionActivationPolicy,omitempty"`
@@ -52,21 +73,53 @@ type PackageSpec struct {
 	// +kubebuilder:default=IfNotPresent
 	PackagePullPolicy *corev1.PullPolicy `json:"packagePullPolicy,omitempty"`
 
+	// ImageSource is an alternative source of the package image, for clusters
+	// that can't pull the package from its registry. The package is still
+	// identified by its package field.
+	// +optional
+	ImageSource *ImageSource `json:"imageSource,omitempty"`
+
 	// IgnoreCrossplaneConstraints indicates to the package manager whether to
-	// honor Crossplane version constrains specified by the package.
+	// honor Crossplane version constrains specified by the package. Packages
+	// built with crossplane-runtime versions known not to work with Crossplane,
+	// or that require disabled features, are refused regardless.
 	// Default is false.
 	// +optional
 	// +kubebuilder:default=false
 	IgnoreCrossplaneConstraints *bool `json:"ignoreCrossplaneConstraints,omitempty"`
 
 	// SkipDependencyResolution indicates to the package manager whether to skip
-	// resolving dependencies for a package. Setting this value to true may have
-	// unintended consequences.
+	// resolving dependencies for a package. When true the package manager
+	// doesn't install the package's missing dependencies. It still checks that
+	// they're installed and satisfy the package's constraints, so each
+	// dependency must be installed explicitly.
 	// Default is false.
 	// +optional
 	// +kubebuilder:default=false
 	SkipDependencyResolution *bool `json:"skipDependencyResolution,omitempty"`
 
+	// DependencyUpgradePolicy specifies what the package manager does when an
+	// installed dependency doesn't satisfy the package's version constraint
+	// on it. Options are Manual or Automatic. Default is Manual. When Manual,
+	// the package reports that its dependencies aren't satisfied until the
+	// dependency is upgraded by hand. When Automatic, the package manager
+	// upgrades the dependency to the highest version that satisfies the
+	// constraints of every package that depends on it. Dependencies are
+	// never downgraded, and a dependency with a Manual revision activation
+	// policy is never upgraded.
+	// +optional
+	// +kubebuilder:default=Manual
+	// +kubebuilder:validation:Enum=Manual;Automatic
+	DependencyUpgradePolicy *DependencyUpgradePolicy `json:"dependencyUpgradePolicy,omitempty"`
+
+	// Paused stops the package manager from resolving the package's version,
+	// creating new revisions, and reconciling the current revision's runtime.
+	// The current revision's runtime (e.g. its Deployment) is left untouched.
+	// This is useful during incident response and maintenance freezes.
+	// Unlike the crossplane.io/paused annotation it doesn't block deletion.
+	// +optional
+	Paused *bool `json:"paused,omitempty"`
+
 	// Map of string keys and values that can be used to organize and categorize
 	// (scope and select) objects. May match selectors of replication controllers
 	// and services.
@@ -75,6 +128,42 @@ type PackageSpec struct {
 	CommonLabels map[string]string `json:"commonLabels,omitempty"`
 }
 
+// An ImageSource is an alternative source of a package image. Exactly one
+// source must be specified.
+// +kubebuilder:validation:XValidation:rule="[has(self.secret), has(self.proxy), has(self.path)].filter(x, x).size() == 1",message="exactly one of secret, proxy, or path must be set"
+type ImageSource struct {
+	// Secret loads the package image from a Secret in Crossplane's namespace
+	// that contains the image as a tarball, e.g. an .xpkg file. Secrets are
+	// limited to 1MiB, so this is only suitable for small packages.
+	// +optional
+	Secret *ImageSourceSecret `json:"secret,omitempty"`
+
+	// Proxy pulls the package image from an in-cluster OCI registry that
+	// mirrors the package's registry, for example
+	// registry.crossplane-system:5000. The image is pulled from a repository
+	// prefixed with its upstream registry, e.g.
+	// registry.crossplane-system:5000/xpkg.upbound.io/crossplane/provider-nop.
+	// +optional
+	Proxy *string `json:"proxy,omitempty"`
+
+	// Path loads the package image from a tarball, e.g. an .xpkg file, in a
+	// volume mounted into the Crossplane pod. The path is relative to
+	// Crossplane's package image directory.
+	// +optional
+	Path *string `json:"path,omitempty"`
+}
+
+// An ImageSourceSecret references a Secret that contains a package image.
+type ImageSourceSecret struct {
+	// Name of the Secret.
+	Name string `json:"name"`
+
+	// Key of the Secret's data that contains the package image tarball.
+	// +optional
+	// +kubebuilder:default=package.xpkg
+	Key string `json:"key,omitempty"`
+}
+
 // PackageStatus represents the observed state of a Package.
 type PackageStatus struct {
 	// CurrentRevision is the name of the current package revision. It will
@@ -89,4 +178,18 @@ type PackageStatus struct {
 	// will cause the package manager to check that the current revision is
 	// correct for the given package source.
 	CurrentIdentifier string `json:"currentIdentifier,omitempty"`
+
+	// Footprint estimates how many objects, and how much etcd storage, the
+	// package's active revision is responsible for.
+	// +optional
+	Footprint *PackageFootprint `json:"footprint,omitempty"`
+
+	// DependsOn lists the packages the package's active revision depends on,
+	// and the versions of them that are installed.
+	// +optional
+	DependsOn []DependencyEdge `json:"dependsOn,omitempty"`
+
+	// InstalledBy lists the installed packages that depend on this package.
+	// +optional
+	InstalledBy []DependencyEdge `json:"installedBy,omitempty"`
 }
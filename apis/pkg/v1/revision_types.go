@@ -19,6 +19,7 @@ package v1
 import (
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 )
@@ -56,25 +57,47 @@ type PackageRevisionSpec struct {
 	// +kubebuilder:default=IfNotPresent
 	PackagePullPolicy *corev1.PullPolicy `json:"packagePullPolicy,omitempty"`
 
+	// ImageSource is an alternative source of the package image, for clusters
+	// that can't pull the package from its registry.
+	// +optional
+	ImageSource *ImageSource `json:"imageSource,omitempty"`
+
 	// Revision number. Indicates when the revision will be garbage collected
 	// based on the parent's RevisionHistoryLimit.
 	Revision int64 `json:"revision"`
 
 	// IgnoreCrossplaneConstraints indicates to the package manager whether to
-	// honor Crossplane version constrains specified by the package.
+	// honor Crossplane version constrains specified by the package. Packages
+	// built with crossplane-runtime versions known not to work with Crossplane,
+	// or that require disabled features, are refused regardless.
 	// Default is false.
 	// +optional
 	// +kubebuilder:default=false
 	IgnoreCrossplaneConstraints *bool `json:"ignoreCrossplaneConstraints,omitempty"`
 
 	// SkipDependencyResolution indicates to the package manager whether to skip
-	// resolving dependencies for a package. Setting this value to true may have
-	// unintended consequences.
+	// resolving dependencies for a package. When true the package manager
+	// doesn't install the package's missing dependencies. It still checks that
+	// they're installed and satisfy the package's constraints.
 	// Default is false.
 	// +optional
 	// +kubebuilder:default=false
 	SkipDependencyResolution *bool `json:"skipDependencyResolution,omitempty"`
 
+	// DependencyUpgradePolicy specifies whether the package manager may
+	// upgrade the revision's dependencies to satisfy its version
+	// constraints. It's set by the package manager from the revision's
+	// parent package.
+	// +optional
+	// +kubebuilder:validation:Enum=Manual;Automatic
+	DependencyUpgradePolicy *DependencyUpgradePolicy `json:"dependencyUpgradePolicy,omitempty"`
+
+	// Paused stops the package manager from reconciling the revision's
+	// runtime. It's set by the package manager when the revision's parent
+	// package is paused.
+	// +optional
+	Paused *bool `json:"paused,omitempty"`
+
 	// Map of string keys and values that can be used to organize and categorize
 	// (scope and select) objects. May match selectors of replication controllers
 	// and services.
@@ -83,22 +106,204 @@ type PackageRevisionSpec struct {
 	CommonLabels map[string]string `json:"commonLabels,omitempty"`
 }
 
+// An ObjectRefGroup references objects of the same API version and kind.
+type ObjectRefGroup struct {
+	// APIVersion of the referenced objects.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the referenced objects.
+	Kind string `json:"kind"`
+
+	// Names of the referenced objects.
+	Names []string `json:"names"`
+}
+
+// A DependencyEdge relates a package to another package in the package
+// manager's dependency graph.
+type DependencyEdge struct {
+	// Package is the OCI image name of the related package, without a tag or
+	// digest.
+	Package string `json:"package"`
+
+	// Type of the related package.
+	// +kubebuilder:validation:Enum=Provider;Configuration;Function
+	Type string `json:"type"`
+
+	// Version of the related package that is installed. It's empty if no
+	// version of the related package is installed.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Constraints the dependent package places on the version of the
+	// dependency.
+	// +optional
+	Constraints string `json:"constraints,omitempty"`
+}
+
 // PackageRevisionStatus represents the observed state of a PackageRevision.
 type PackageRevisionStatus struct {
 	xpv1.ConditionedStatus `json:",inline"`
 
 	// References to objects owned by PackageRevision.
+	//
+	// Deprecated: Use objectRefGroups. The package manager still writes these
+	// references alongside objectRefGroups, but will stop doing so in a
+	// future release. They're only read for revisions whose status predates
+	// objectRefGroups.
 	ObjectRefs []xpv1.TypedReference `json:"objectRefs,omitempty"`
 
+	// ObjectRefGroups references the objects owned by the PackageRevision,
+	// grouped by API version and kind. This is more compact than a reference
+	// per object, so packages that own thousands of objects fit within the
+	// object size limit.
+	// +optional
+	ObjectRefGroups []ObjectRefGroup `json:"objectRefGroups,omitempty"`
+
 	// Dependency information.
 	FoundDependencies     int64 `json:"foundDependencies,omitempty"`
 	InstalledDependencies int64 `json:"installedDependencies,omitempty"`
 	InvalidDependencies   int64 `json:"invalidDependencies,omitempty"`
 
+	// DependsOn lists the packages this package revision depends on, and the
+	// versions of them that are installed.
+	// +optional
+	DependsOn []DependencyEdge `json:"dependsOn,omitempty"`
+
+	// InstalledBy lists the installed packages that depend on this package
+	// revision's package.
+	// +optional
+	InstalledBy []DependencyEdge `json:"installedBy,omitempty"`
+
 	// PermissionRequests made by this package. The package declares that its
 	// controller needs these permissions to run. The RBAC manager is
 	// responsible for granting them.
 	PermissionRequests []rbacv1.PolicyRule `json:"permissionRequests,omitempty"`
+
+	// Runtime references the objects that run this package revision's
+	// runtime. Only set for packages with a runtime, i.e. providers and
+	// functions.
+	// +optional
+	Runtime *PackageRevisionRuntimeStatus `json:"runtime,omitempty"`
+
+	// Contents summarizes what the package manager found when it parsed and
+	// linted the package.
+	// +optional
+	Contents *PackageContents `json:"contents,omitempty"`
+
+	// FetchAttempts is the number of consecutive failed attempts to fetch the
+	// package image. It's reset when the image is fetched.
+	// +optional
+	FetchAttempts int64 `json:"fetchAttempts,omitempty"`
+
+	// CrossplaneVersion is the version of Crossplane that last found the
+	// revision compatible with its Crossplane version constraint. It's used to
+	// detect that Crossplane was downgraded.
+	// +optional
+	CrossplaneVersion string `json:"crossplaneVersion,omitempty"`
+
+	// Image describes the package image the revision was pulled from. It's
+	// set before the image's layers are pulled, which may take a while.
+	// +optional
+	Image *PackageImage `json:"image,omitempty"`
+
+	// Build describes how the package image was built, according to the
+	// labels of its config. It's unset if the image has none of the labels.
+	// +optional
+	Build *PackageBuild `json:"build,omitempty"`
+
+	// Footprint estimates how many objects, and how much etcd storage, the
+	// package revision is responsible for. Only active revisions are sampled.
+	// +optional
+	Footprint *PackageFootprint `json:"footprint,omitempty"`
+
+	// Vulnerabilities summarizes the vulnerabilities found when the package
+	// image was scanned. It's only set if an ImageConfig configures scanning
+	// for the package image.
+	// +optional
+	Vulnerabilities *PackageVulnerabilities `json:"vulnerabilities,omitempty"`
+}
+
+// PackageImage describes a package image.
+type PackageImage struct {
+	// Layers is the number of layers in the image.
+	Layers int64 `json:"layers"`
+
+	// Size is the total compressed size of the image's layers, in bytes.
+	Size int64 `json:"size"`
+}
+
+// PackageBuild describes how a package image was built.
+type PackageBuild struct {
+	// BuilderVersion is the version of the tool that built the package, e.g.
+	// the Crossplane CLI.
+	// +optional
+	BuilderVersion string `json:"builderVersion,omitempty"`
+
+	// Created is when the package was built, as recorded by its builder.
+	// +optional
+	Created string `json:"created,omitempty"`
+
+	// Source is the URL of the source code the package was built from.
+	// +optional
+	Source string `json:"source,omitempty"`
+
+	// Revision is the version control revision, e.g. the git commit, the
+	// package was built from.
+	// +optional
+	Revision string `json:"revision,omitempty"`
+
+	// Version of the package, as recorded by its builder.
+	// +optional
+	Version string `json:"version,omitempty"`
+}
+
+// PackageFootprint estimates how many objects, and how much etcd storage, a
+// package is responsible for. It's periodically sampled, so it may be stale.
+type PackageFootprint struct {
+	// CustomResourceDefinitions is the number of CRDs the package defines,
+	// including CRDs defined by its composite resource definitions.
+	CustomResourceDefinitions int64 `json:"customResourceDefinitions"`
+
+	// CustomResources is the number of custom resources of the types the
+	// package defines.
+	CustomResources int64 `json:"customResources"`
+
+	// EstimatedBytes is an estimate of how many bytes of etcd storage the
+	// package's CRDs and custom resources use. It's extrapolated from the
+	// size of a sample of each type of custom resource.
+	EstimatedBytes int64 `json:"estimatedBytes"`
+
+	// SampleTime is when the footprint was sampled.
+	// +optional
+	SampleTime *metav1.Time `json:"sampleTime,omitempty"`
+}
+
+// PackageContents summarizes the contents of a parsed package.
+type PackageContents struct {
+	// Objects counts the objects in the package, by kind.
+	// +optional
+	Objects []PackageObjectCount `json:"objects,omitempty"`
+
+	// Warnings about the package's objects. Warnings don't prevent a package
+	// from being installed.
+	// +optional
+	Warnings []string `json:"warnings,omitempty"`
+
+	// LintError is set if the package failed linting.
+	// +optional
+	LintError string `json:"lintError,omitempty"`
+}
+
+// PackageObjectCount is the number of objects of a kind a package contains.
+type PackageObjectCount struct {
+	// APIVersion of the objects.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the objects.
+	Kind string `json:"kind"`
+
+	// Count of objects of this kind.
+	Count int64 `json:"count"`
 }
 
 // A ControllerReference references the controller (e.g. Deployment), if any,
@@ -107,3 +312,53 @@ type ControllerReference struct {
 	// Name of the controller.
 	Name string `json:"name"`
 }
+
+// PackageVulnerabilities summarizes the vulnerabilities found in a package
+// image.
+type PackageVulnerabilities struct {
+	// Critical is the number of critical severity vulnerabilities.
+	Critical int64 `json:"critical"`
+
+	// High is the number of high severity vulnerabilities.
+	High int64 `json:"high"`
+
+	// Medium is the number of medium severity vulnerabilities.
+	Medium int64 `json:"medium"`
+
+	// Low is the number of low severity vulnerabilities.
+	Low int64 `json:"low"`
+
+	// Unknown is the number of vulnerabilities of unknown severity.
+	Unknown int64 `json:"unknown"`
+
+	// Findings lists the most severe vulnerabilities found, most severe
+	// first. At most 20 vulnerabilities are listed.
+	// +optional
+	Findings []PackageVulnerability `json:"findings,omitempty"`
+
+	// ScanTime is when the package image was scanned.
+	// +optional
+	ScanTime *metav1.Time `json:"scanTime,omitempty"`
+}
+
+// PackageVulnerability is a vulnerability found in a package image.
+type PackageVulnerability struct {
+	// ID of the vulnerability, e.g. a CVE ID.
+	ID string `json:"id"`
+
+	// Severity of the vulnerability.
+	Severity string `json:"severity"`
+
+	// Package that is vulnerable.
+	// +optional
+	Package string `json:"package,omitempty"`
+
+	// Version of the package that is vulnerable.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// FixVersion is the version of the package that fixes the
+	// vulnerability, if any.
+	// +optional
+	FixVersion string `json:"fixVersion,omitempty"`
+}
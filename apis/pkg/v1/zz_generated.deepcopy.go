@@ -145,10 +145,40 @@ func (in *ConfigurationRevisionList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigurationRevisionSpec) DeepCopyInto(out *ConfigurationRevisionSpec) {
+	*out = *in
+	in.PackageRevisionSpec.DeepCopyInto(&out.PackageRevisionSpec)
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationRevisionSpec.
+func (in *ConfigurationRevisionSpec) DeepCopy() *ConfigurationRevisionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigurationRevisionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigurationSpec) DeepCopyInto(out *ConfigurationSpec) {
 	*out = *in
 	in.PackageSpec.DeepCopyInto(&out.PackageSpec)
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationSpec.
@@ -165,7 +195,7 @@ func (in *ConfigurationSpec) DeepCopy() *ConfigurationSpec {
 func (in *ConfigurationStatus) DeepCopyInto(out *ConfigurationStatus) {
 	*out = *in
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
-	out.PackageStatus = in.PackageStatus
+	in.PackageStatus.DeepCopyInto(&out.PackageStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationStatus.
@@ -208,6 +238,21 @@ func (in *ControllerReference) DeepCopy() *ControllerReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DependencyEdge) DeepCopyInto(out *DependencyEdge) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DependencyEdge.
+func (in *DependencyEdge) DeepCopy() *DependencyEdge {
+	if in == nil {
+		return nil
+	}
+	out := new(DependencyEdge)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Function) DeepCopyInto(out *Function) {
 	*out = *in
@@ -380,7 +425,7 @@ func (in *FunctionSpec) DeepCopy() *FunctionSpec {
 func (in *FunctionStatus) DeepCopyInto(out *FunctionStatus) {
 	*out = *in
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
-	out.PackageStatus = in.PackageStatus
+	in.PackageStatus.DeepCopyInto(&out.PackageStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FunctionStatus.
@@ -393,6 +438,160 @@ func (in *FunctionStatus) DeepCopy() *FunctionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageSource) DeepCopyInto(out *ImageSource) {
+	*out = *in
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(ImageSourceSecret)
+		**out = **in
+	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(string)
+		**out = **in
+	}
+	if in.Path != nil {
+		in, out := &in.Path, &out.Path
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSource.
+func (in *ImageSource) DeepCopy() *ImageSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageSourceSecret) DeepCopyInto(out *ImageSourceSecret) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSourceSecret.
+func (in *ImageSourceSecret) DeepCopy() *ImageSourceSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageSourceSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectRefGroup) DeepCopyInto(out *ObjectRefGroup) {
+	*out = *in
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectRefGroup.
+func (in *ObjectRefGroup) DeepCopy() *ObjectRefGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectRefGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageBuild) DeepCopyInto(out *PackageBuild) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageBuild.
+func (in *PackageBuild) DeepCopy() *PackageBuild {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageBuild)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageContents) DeepCopyInto(out *PackageContents) {
+	*out = *in
+	if in.Objects != nil {
+		in, out := &in.Objects, &out.Objects
+		*out = make([]PackageObjectCount, len(*in))
+		copy(*out, *in)
+	}
+	if in.Warnings != nil {
+		in, out := &in.Warnings, &out.Warnings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageContents.
+func (in *PackageContents) DeepCopy() *PackageContents {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageContents)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageFootprint) DeepCopyInto(out *PackageFootprint) {
+	*out = *in
+	if in.SampleTime != nil {
+		in, out := &in.SampleTime, &out.SampleTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageFootprint.
+func (in *PackageFootprint) DeepCopy() *PackageFootprint {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageFootprint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageImage) DeepCopyInto(out *PackageImage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageImage.
+func (in *PackageImage) DeepCopy() *PackageImage {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageObjectCount) DeepCopyInto(out *PackageObjectCount) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageObjectCount.
+func (in *PackageObjectCount) DeepCopy() *PackageObjectCount {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageObjectCount)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PackageRevisionRuntimeSpec) DeepCopyInto(out *PackageRevisionRuntimeSpec) {
 	*out = *in
@@ -419,6 +618,36 @@ func (in *PackageRevisionRuntimeSpec) DeepCopy() *PackageRevisionRuntimeSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageRevisionRuntimeStatus) DeepCopyInto(out *PackageRevisionRuntimeStatus) {
+	*out = *in
+	if in.DeploymentRef != nil {
+		in, out := &in.DeploymentRef, &out.DeploymentRef
+		*out = new(RuntimeObjectReference)
+		**out = **in
+	}
+	if in.ServiceAccountRef != nil {
+		in, out := &in.ServiceAccountRef, &out.ServiceAccountRef
+		*out = new(RuntimeObjectReference)
+		**out = **in
+	}
+	if in.ServiceRef != nil {
+		in, out := &in.ServiceRef, &out.ServiceRef
+		*out = new(RuntimeObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionRuntimeStatus.
+func (in *PackageRevisionRuntimeStatus) DeepCopy() *PackageRevisionRuntimeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageRevisionRuntimeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PackageRevisionSpec) DeepCopyInto(out *PackageRevisionSpec) {
 	*out = *in
@@ -432,6 +661,11 @@ func (in *PackageRevisionSpec) DeepCopyInto(out *PackageRevisionSpec) {
 		*out = new(corev1.PullPolicy)
 		**out = **in
 	}
+	if in.ImageSource != nil {
+		in, out := &in.ImageSource, &out.ImageSource
+		*out = new(ImageSource)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.IgnoreCrossplaneConstraints != nil {
 		in, out := &in.IgnoreCrossplaneConstraints, &out.IgnoreCrossplaneConstraints
 		*out = new(bool)
@@ -442,6 +676,16 @@ func (in *PackageRevisionSpec) DeepCopyInto(out *PackageRevisionSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.DependencyUpgradePolicy != nil {
+		in, out := &in.DependencyUpgradePolicy, &out.DependencyUpgradePolicy
+		*out = new(DependencyUpgradePolicy)
+		**out = **in
+	}
+	if in.Paused != nil {
+		in, out := &in.Paused, &out.Paused
+		*out = new(bool)
+		**out = **in
+	}
 	if in.CommonLabels != nil {
 		in, out := &in.CommonLabels, &out.CommonLabels
 		*out = make(map[string]string, len(*in))
@@ -470,6 +714,23 @@ func (in *PackageRevisionStatus) DeepCopyInto(out *PackageRevisionStatus) {
 		*out = make([]commonv1.TypedReference, len(*in))
 		copy(*out, *in)
 	}
+	if in.ObjectRefGroups != nil {
+		in, out := &in.ObjectRefGroups, &out.ObjectRefGroups
+		*out = make([]ObjectRefGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]DependencyEdge, len(*in))
+		copy(*out, *in)
+	}
+	if in.InstalledBy != nil {
+		in, out := &in.InstalledBy, &out.InstalledBy
+		*out = make([]DependencyEdge, len(*in))
+		copy(*out, *in)
+	}
 	if in.PermissionRequests != nil {
 		in, out := &in.PermissionRequests, &out.PermissionRequests
 		*out = make([]rbacv1.PolicyRule, len(*in))
@@ -477,6 +738,36 @@ func (in *PackageRevisionStatus) DeepCopyInto(out *PackageRevisionStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Runtime != nil {
+		in, out := &in.Runtime, &out.Runtime
+		*out = new(PackageRevisionRuntimeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Contents != nil {
+		in, out := &in.Contents, &out.Contents
+		*out = new(PackageContents)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(PackageImage)
+		**out = **in
+	}
+	if in.Build != nil {
+		in, out := &in.Build, &out.Build
+		*out = new(PackageBuild)
+		**out = **in
+	}
+	if in.Footprint != nil {
+		in, out := &in.Footprint, &out.Footprint
+		*out = new(PackageFootprint)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Vulnerabilities != nil {
+		in, out := &in.Vulnerabilities, &out.Vulnerabilities
+		*out = new(PackageVulnerabilities)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionStatus.
@@ -517,6 +808,11 @@ func (in *PackageRuntimeSpec) DeepCopy() *PackageRuntimeSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PackageSpec) DeepCopyInto(out *PackageSpec) {
 	*out = *in
+	if in.VersionConstraint != nil {
+		in, out := &in.VersionConstraint, &out.VersionConstraint
+		*out = new(string)
+		**out = **in
+	}
 	if in.RevisionActivationPolicy != nil {
 		in, out := &in.RevisionActivationPolicy, &out.RevisionActivationPolicy
 		*out = new(RevisionActivationPolicy)
@@ -537,6 +833,11 @@ func (in *PackageSpec) DeepCopyInto(out *PackageSpec) {
 		*out = new(corev1.PullPolicy)
 		**out = **in
 	}
+	if in.ImageSource != nil {
+		in, out := &in.ImageSource, &out.ImageSource
+		*out = new(ImageSource)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.IgnoreCrossplaneConstraints != nil {
 		in, out := &in.IgnoreCrossplaneConstraints, &out.IgnoreCrossplaneConstraints
 		*out = new(bool)
@@ -547,6 +848,16 @@ func (in *PackageSpec) DeepCopyInto(out *PackageSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.DependencyUpgradePolicy != nil {
+		in, out := &in.DependencyUpgradePolicy, &out.DependencyUpgradePolicy
+		*out = new(DependencyUpgradePolicy)
+		**out = **in
+	}
+	if in.Paused != nil {
+		in, out := &in.Paused, &out.Paused
+		*out = new(bool)
+		**out = **in
+	}
 	if in.CommonLabels != nil {
 		in, out := &in.CommonLabels, &out.CommonLabels
 		*out = make(map[string]string, len(*in))
@@ -569,6 +880,21 @@ func (in *PackageSpec) DeepCopy() *PackageSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PackageStatus) DeepCopyInto(out *PackageStatus) {
 	*out = *in
+	if in.Footprint != nil {
+		in, out := &in.Footprint, &out.Footprint
+		*out = new(PackageFootprint)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]DependencyEdge, len(*in))
+		copy(*out, *in)
+	}
+	if in.InstalledBy != nil {
+		in, out := &in.InstalledBy, &out.InstalledBy
+		*out = make([]DependencyEdge, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageStatus.
@@ -581,6 +907,45 @@ func (in *PackageStatus) DeepCopy() *PackageStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageVulnerabilities) DeepCopyInto(out *PackageVulnerabilities) {
+	*out = *in
+	if in.Findings != nil {
+		in, out := &in.Findings, &out.Findings
+		*out = make([]PackageVulnerability, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScanTime != nil {
+		in, out := &in.ScanTime, &out.ScanTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageVulnerabilities.
+func (in *PackageVulnerabilities) DeepCopy() *PackageVulnerabilities {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageVulnerabilities)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageVulnerability) DeepCopyInto(out *PackageVulnerability) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageVulnerability.
+func (in *PackageVulnerability) DeepCopy() *PackageVulnerability {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageVulnerability)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Provider) DeepCopyInto(out *Provider) {
 	*out = *in
@@ -737,7 +1102,7 @@ func (in *ProviderSpec) DeepCopy() *ProviderSpec {
 func (in *ProviderStatus) DeepCopyInto(out *ProviderStatus) {
 	*out = *in
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
-	out.PackageStatus = in.PackageStatus
+	in.PackageStatus.DeepCopyInto(&out.PackageStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderStatus.
@@ -774,3 +1139,18 @@ func (in *RuntimeConfigReference) DeepCopy() *RuntimeConfigReference {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeObjectReference) DeepCopyInto(out *RuntimeObjectReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeObjectReference.
+func (in *RuntimeObjectReference) DeepCopy() *RuntimeObjectReference {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// A PlannedAction is what installing a package would do to one of the
+// packages in its dependency tree.
+type PlannedAction string
+
+// Planned actions.
+const (
+	// PlannedActionInstall indicates the package would be installed.
+	PlannedActionInstall PlannedAction = "Install"
+
+	// PlannedActionUpgrade indicates an installed package would be upgraded
+	// or downgraded to a different version.
+	PlannedActionUpgrade PlannedAction = "Upgrade"
+
+	// PlannedActionNone indicates an installed package already satisfies
+	// every constraint on it.
+	PlannedActionNone PlannedAction = "None"
+
+	// PlannedActionUnsatisfiable indicates no version of the package
+	// satisfies every constraint on it.
+	PlannedActionUnsatisfiable PlannedAction = "Unsatisfiable"
+)
+
+// DependencyPlanSpec specifies the package to plan.
+type DependencyPlanSpec struct {
+	// Type of the package to plan.
+	// +optional
+	// +kubebuilder:default=Configuration
+	// +kubebuilder:validation:Enum=Configuration;Provider;Function
+	Type string `json:"type,omitempty"`
+
+	// Package is the OCI image of the package to plan.
+	Package string `json:"package"`
+
+	// PackagePullSecrets are named Secrets in the Crossplane namespace that
+	// are used to pull the package. They're not used to pull its
+	// dependencies.
+	// +optional
+	PackagePullSecrets []corev1.LocalObjectReference `json:"packagePullSecrets,omitempty"`
+}
+
+// A PlannedPackage is a package that would be installed, upgraded, or left as
+// is by installing the planned package.
+type PlannedPackage struct {
+	// Type of the package.
+	Type string `json:"type"`
+
+	// Package is the OCI repository of the package.
+	Package string `json:"package"`
+
+	// Version of the package that would be installed, i.e. a tag or digest.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// InstalledVersion is the version of the package that's currently
+	// installed, if any.
+	// +optional
+	InstalledVersion string `json:"installedVersion,omitempty"`
+
+	// Action that installing the planned package would take.
+	Action PlannedAction `json:"action"`
+
+	// RequiredBy lists the packages that depend on this package.
+	// +optional
+	RequiredBy []string `json:"requiredBy,omitempty"`
+
+	// Message explains why the package is unsatisfiable.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// DependencyPlanStatus represents the observed state of a DependencyPlan.
+type DependencyPlanStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+
+	// Packages that installing the planned package would install, upgrade,
+	// or leave as is. The planned package is listed first, followed by its
+	// dependencies.
+	// +optional
+	Packages []PlannedPackage `json:"packages,omitempty"`
+}
+
+// A DependencyPlan computes what installing a package would install, without
+// installing anything. It resolves the package's full dependency tree against
+// the packages that are already installed.
+//
+// A DependencyPlan is computed once. Create a new DependencyPlan to plan again.
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="PACKAGE",type="string",JSONPath=".spec.package"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories=crossplane
+// +kubebuilder:subresource:status
+type DependencyPlan struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="spec is immutable"
+	Spec   DependencyPlanSpec   `json:"spec"`
+	Status DependencyPlanStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DependencyPlanList contains a list of DependencyPlan.
+type DependencyPlanList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DependencyPlan `json:"items"`
+}
+
+// GetCondition of this DependencyPlan.
+func (p *DependencyPlan) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return p.Status.GetCondition(ct)
+}
+
+// SetConditions of this DependencyPlan.
+func (p *DependencyPlan) SetConditions(c ...xpv1.Condition) {
+	p.Status.SetConditions(c...)
+}
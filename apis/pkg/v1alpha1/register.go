@@ -48,6 +48,24 @@ var (
 	ControllerConfigGroupVersionKind = SchemeGroupVersion.WithKind(ControllerConfigKind)
 )
 
+// PackageUpgrade type metadata.
+var (
+	PackageUpgradeKind             = reflect.TypeOf(PackageUpgrade{}).Name()
+	PackageUpgradeGroupKind        = schema.GroupKind{Group: Group, Kind: PackageUpgradeKind}.String()
+	PackageUpgradeKindAPIVersion   = PackageUpgradeKind + "." + SchemeGroupVersion.String()
+	PackageUpgradeGroupVersionKind = SchemeGroupVersion.WithKind(PackageUpgradeKind)
+)
+
+// DependencyPlan type metadata.
+var (
+	DependencyPlanKind             = reflect.TypeOf(DependencyPlan{}).Name()
+	DependencyPlanGroupKind        = schema.GroupKind{Group: Group, Kind: DependencyPlanKind}.String()
+	DependencyPlanKindAPIVersion   = DependencyPlanKind + "." + SchemeGroupVersion.String()
+	DependencyPlanGroupVersionKind = SchemeGroupVersion.WithKind(DependencyPlanKind)
+)
+
 func init() {
 	SchemeBuilder.Register(&ControllerConfig{}, &ControllerConfigList{})
+	SchemeBuilder.Register(&PackageUpgrade{}, &PackageUpgradeList{})
+	SchemeBuilder.Register(&DependencyPlan{}, &DependencyPlanList{})
 }
@@ -0,0 +1,217 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// A TypeUpgraded condition indicates whether a PackageUpgrade has upgraded all
+// of its packages. Its reason is the stage the upgrade has reached.
+const TypeUpgraded xpv1.ConditionType = "Upgraded"
+
+// Reasons a PackageUpgrade has or has not upgraded its packages.
+const (
+	// ReasonStaging indicates new revisions of the upgraded packages are
+	// being created, but not yet activated.
+	ReasonStaging xpv1.ConditionReason = "StagingRevisions"
+
+	// ReasonActivating indicates the staged revisions are being activated
+	// one at a time, in dependency order.
+	ReasonActivating xpv1.ConditionReason = "ActivatingRevisions"
+
+	// ReasonSucceeded indicates all staged revisions were activated and
+	// became healthy.
+	ReasonSucceeded xpv1.ConditionReason = "UpgradeSucceeded"
+
+	// ReasonRollingBack indicates a staged revision failed to stage or
+	// become healthy, and all packages are being returned to their previous
+	// revisions.
+	ReasonRollingBack xpv1.ConditionReason = "RollingBack"
+
+	// ReasonRolledBack indicates all packages were returned to their
+	// previous revisions.
+	ReasonRolledBack xpv1.ConditionReason = "RolledBack"
+)
+
+// Staging indicates a PackageUpgrade is staging new revisions.
+func Staging() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeUpgraded,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonStaging,
+	}
+}
+
+// Activating indicates a PackageUpgrade is activating its staged revisions.
+func Activating() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeUpgraded,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonActivating,
+	}
+}
+
+// Succeeded indicates a PackageUpgrade activated all of its staged revisions.
+func Succeeded() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeUpgraded,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonSucceeded,
+	}
+}
+
+// RollingBack indicates a PackageUpgrade is returning its packages to their
+// previous revisions.
+func RollingBack() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeUpgraded,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRollingBack,
+	}
+}
+
+// RolledBack indicates a PackageUpgrade returned its packages to their
+// previous revisions.
+func RolledBack() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeUpgraded,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRolledBack,
+	}
+}
+
+// A PackageUpgradeTarget is a package to upgrade.
+type PackageUpgradeTarget struct {
+	// Kind of the package to upgrade.
+	// +kubebuilder:validation:Enum=Provider;Configuration;Function
+	Kind string `json:"kind"`
+
+	// Name of the package to upgrade.
+	Name string `json:"name"`
+
+	// Package is the OCI image the package should be upgraded to.
+	Package string `json:"package"`
+}
+
+// PackageUpgradeSpec specifies the packages to upgrade.
+type PackageUpgradeSpec struct {
+	// Packages to upgrade together. A package is activated after the
+	// packages it depends on, according to the dependencies recorded in the
+	// package Lock. Otherwise packages are activated in the order they're
+	// listed.
+	// +kubebuilder:validation:MinItems=1
+	Packages []PackageUpgradeTarget `json:"packages"`
+
+	// HealthTimeout is how long to wait for each package's new revision to be
+	// staged, and to become healthy once activated. All packages are rolled
+	// back to their previous revisions if any takes longer. Defaults to 5m.
+	// +optional
+	HealthTimeout *metav1.Duration `json:"healthTimeout,omitempty"`
+}
+
+// A PackageUpgradeState records the progress of a package's upgrade.
+type PackageUpgradeState struct {
+	// Kind of the package.
+	Kind string `json:"kind"`
+
+	// Name of the package.
+	Name string `json:"name"`
+
+	// PreviousPackage is the OCI image the package used before the upgrade.
+	// +optional
+	PreviousPackage string `json:"previousPackage,omitempty"`
+
+	// PreviousRevision is the revision that was active before the upgrade.
+	// +optional
+	PreviousRevision string `json:"previousRevision,omitempty"`
+
+	// PreviousActivationPolicy is the package's revision activation policy
+	// before the upgrade.
+	// +optional
+	PreviousActivationPolicy string `json:"previousActivationPolicy,omitempty"`
+
+	// Revision is the package's staged revision.
+	// +optional
+	Revision string `json:"revision,omitempty"`
+
+	// ActivationTime is when the staged revision was activated.
+	// +optional
+	ActivationTime *metav1.Time `json:"activationTime,omitempty"`
+
+	// Healthy is true once the staged revision is active and healthy.
+	// +optional
+	Healthy bool `json:"healthy,omitempty"`
+}
+
+// PackageUpgradeStatus represents the observed state of a PackageUpgrade.
+type PackageUpgradeStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+
+	// Packages records the progress of each package's upgrade.
+	// +optional
+	Packages []PackageUpgradeState `json:"packages,omitempty"`
+}
+
+// A PackageUpgrade upgrades several packages together. It stages new revisions
+// of all packages, then activates them in dependency order, waiting for each
+// to become healthy. If any revision fails to stage or become healthy, all
+// packages are rolled back to their previous revisions.
+//
+// A PackageUpgrade runs once. Create a new PackageUpgrade to upgrade again.
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.conditions[?(@.type=='Upgraded')].reason"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories=crossplane
+// +kubebuilder:subresource:status
+type PackageUpgrade struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="spec is immutable"
+	Spec   PackageUpgradeSpec   `json:"spec"`
+	Status PackageUpgradeStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PackageUpgradeList contains a list of PackageUpgrade.
+type PackageUpgradeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PackageUpgrade `json:"items"`
+}
+
+// GetCondition of this PackageUpgrade.
+func (u *PackageUpgrade) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return u.Status.GetCondition(ct)
+}
+
+// SetConditions of this PackageUpgrade.
+func (u *PackageUpgrade) SetConditions(c ...xpv1.Condition) {
+	u.Status.SetConditions(c...)
+}
@@ -18,6 +18,7 @@ package v1beta1
 
 import (
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -59,6 +60,16 @@ type ServiceTemplate struct {
 	// Metadata contains the configurable metadata fields for the Service.
 	// +optional
 	Metadata *ObjectMeta `json:"metadata,omitempty"`
+
+	// Ports the Service exposes in addition to those the package manager
+	// configures, for example the API of a plugin server that other
+	// components in the cluster consume. The Service's name is stable across
+	// package revisions.
+	// +optional
+	// +listType=map
+	// +listMapKey=port
+	// +listMapKey=protocol
+	Ports []corev1.ServicePort `json:"ports,omitempty"`
 }
 
 // ServiceAccountTemplate is the template for the ServiceAccount object.
@@ -81,6 +92,17 @@ type DeploymentRuntimeConfigSpec struct {
 	// ServiceAccountTemplate is the template for the ServiceAccount object.
 	// +optional
 	ServiceAccountTemplate *ServiceAccountTemplate `json:"serviceAccountTemplate,omitempty"`
+	// AdditionalArgs are appended to the arguments of the package runtime
+	// container, for example to pass --poll-interval to a provider.
+	// +optional
+	AdditionalArgs []string `json:"additionalArgs,omitempty"`
+	// KubeconfigSecretRef references a key of a Secret in Crossplane's
+	// namespace containing a kubeconfig. The kubeconfig is mounted into the
+	// package runtime container and the KUBECONFIG environment variable points
+	// to it, so that a provider talks to the API server it specifies rather
+	// than the one it runs in.
+	// +optional
+	KubeconfigSecretRef *SecretKeySelector `json:"kubeconfigSecretRef,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,234 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MatchType is the method used to match the image.
+type MatchType string
+
+const (
+	// Prefix is used to match the prefix of the image.
+	Prefix MatchType = "Prefix"
+)
+
+// ImageMatch defines a rule for matching image.
+type ImageMatch struct {
+	// Type is the type of match.
+	// +optional
+	// +kubebuilder:validation:Enum=Prefix
+	// +kubebuilder:default=Prefix
+	Type MatchType `json:"type,omitempty"`
+
+	// Prefix is used to match the prefix of the image, including its
+	// registry, e.g. xpkg.upbound.io/crossplane-contrib. Packages whose
+	// source is a URL are matched by their URL, e.g. https://example.org/.
+	Prefix string `json:"prefix"`
+}
+
+// ImageVerificationProvider is the provider used to verify images.
+type ImageVerificationProvider string
+
+const (
+	// ImageVerificationProviderCosign verifies images using cosign.
+	ImageVerificationProviderCosign ImageVerificationProvider = "Cosign"
+)
+
+// ImageVerification configures how package images are verified.
+type ImageVerification struct {
+	// Provider is the provider that should be used to verify the image.
+	// +kubebuilder:validation:Enum=Cosign
+	Provider ImageVerificationProvider `json:"provider"`
+
+	// Cosign is the configuration for verifying the image using cosign.
+	// +optional
+	Cosign *CosignVerificationConfig `json:"cosign,omitempty"`
+}
+
+// CosignVerificationConfig is the configuration for verifying images using
+// cosign.
+type CosignVerificationConfig struct {
+	// Authorities defines the rules for discovering and validating signatures.
+	// An image is verified if any authority verifies any of its signatures.
+	// +kubebuilder:validation:MinItems=1
+	Authorities []CosignAuthority `json:"authorities"`
+}
+
+// CosignAuthority defines the rules for discovering and validating signatures.
+// Exactly one of key or keyless must be set.
+// +kubebuilder:validation:XValidation:rule="has(self.key) != has(self.keyless)",message="exactly one of key or keyless must be set"
+type CosignAuthority struct {
+	// Name is the name for this authority.
+	Name string `json:"name"`
+
+	// Key verifies signatures made with a key pair.
+	// +optional
+	Key *KeyRef `json:"key,omitempty"`
+
+	// Keyless verifies signatures made with short-lived certificates issued
+	// to an OIDC identity, e.g. by Fulcio. Signatures must be recorded in a
+	// Rekor transparency log.
+	// +optional
+	Keyless *KeylessRef `json:"keyless,omitempty"`
+}
+
+// A SecretKeySelector references a key of a Secret in the Crossplane
+// namespace.
+type SecretKeySelector struct {
+	// Name of the secret.
+	Name string `json:"name"`
+
+	// Key of the secret.
+	Key string `json:"key"`
+}
+
+// KeyRef references the public key that verifies a signature.
+type KeyRef struct {
+	// SecretRef references a Secret containing a PEM encoded public key.
+	SecretRef SecretKeySelector `json:"secretRef"`
+}
+
+// KeylessRef configures which keyless signing identities are trusted.
+type KeylessRef struct {
+	// CertificateAuthoritySecretRef references a Secret containing the PEM
+	// encoded root certificates that signing certificates must chain to.
+	CertificateAuthoritySecretRef SecretKeySelector `json:"certificateAuthoritySecretRef"`
+
+	// Identities that are trusted to sign images.
+	// +kubebuilder:validation:MinItems=1
+	Identities []CosignIdentity `json:"identities"`
+
+	// TransparencyLogSecretRef references a Secret containing the PEM
+	// encoded public key of the Rekor transparency log that signatures must
+	// be recorded in. Signing certificates are only valid for a few minutes;
+	// the log records when a signature was made.
+	TransparencyLogSecretRef SecretKeySelector `json:"transparencyLogSecretRef"`
+}
+
+// CosignIdentity is a trusted keyless signing identity.
+type CosignIdentity struct {
+	// Issuer is the URL of the OIDC issuer that authenticated the signer, e.g.
+	// https://token.actions.githubusercontent.com.
+	Issuer string `json:"issuer"`
+
+	// Subject is the identity of the signer, e.g. an email address or a
+	// GitHub Actions workflow URI.
+	Subject string `json:"subject"`
+}
+
+// ImageScanningProvider is the provider used to scan images for
+// vulnerabilities.
+type ImageScanningProvider string
+
+const (
+	// ImageScanningProviderHarbor reads the vulnerability report produced by
+	// a Harbor registry's scanner, e.g. Trivy.
+	ImageScanningProviderHarbor ImageScanningProvider = "Harbor"
+)
+
+// VulnerabilitySeverity is the severity of a vulnerability.
+type VulnerabilitySeverity string
+
+// Vulnerability severities.
+const (
+	VulnerabilitySeverityLow      VulnerabilitySeverity = "Low"
+	VulnerabilitySeverityMedium   VulnerabilitySeverity = "Medium"
+	VulnerabilitySeverityHigh     VulnerabilitySeverity = "High"
+	VulnerabilitySeverityCritical VulnerabilitySeverity = "Critical"
+)
+
+// ImageScanning configures how package images are scanned for
+// vulnerabilities.
+type ImageScanning struct {
+	// Provider is the provider that should be used to scan the image.
+	// +kubebuilder:validation:Enum=Harbor
+	Provider ImageScanningProvider `json:"provider"`
+
+	// Harbor is the configuration for scanning the image using a Harbor
+	// registry's vulnerability report.
+	// +optional
+	Harbor *HarborScanningConfig `json:"harbor,omitempty"`
+
+	// FailOnSeverity blocks activation of a package revision whose image has
+	// vulnerabilities of this severity or higher. Vulnerabilities are only
+	// recorded if it's not set.
+	// +optional
+	// +kubebuilder:validation:Enum=Low;Medium;High;Critical
+	FailOnSeverity *VulnerabilitySeverity `json:"failOnSeverity,omitempty"`
+}
+
+// HarborScanningConfig is the configuration for scanning images using a
+// Harbor registry's vulnerability report.
+type HarborScanningConfig struct {
+	// URL of the Harbor API, e.g. https://harbor.example.org. Defaults to the
+	// image's registry, over HTTPS.
+	// +optional
+	URL *string `json:"url,omitempty"`
+
+	// CredentialsSecretRef references a Secret of type
+	// kubernetes.io/basic-auth in the Crossplane namespace, containing the
+	// username and password used to read vulnerability reports, e.g. of a
+	// Harbor robot account.
+	// +optional
+	CredentialsSecretRef *corev1.LocalObjectReference `json:"credentialsSecretRef,omitempty"`
+}
+
+// ImageConfigSpec contains the configuration for matching images.
+type ImageConfigSpec struct {
+	// MatchImages is a list of image matching rules. The ImageConfig applies
+	// to images that match any rule.
+	// +kubebuilder:validation:MinItems=1
+	MatchImages []ImageMatch `json:"matchImages"`
+
+	// Verification contains the configuration for verifying the image.
+	// +optional
+	Verification *ImageVerification `json:"verification,omitempty"`
+
+	// Scanning contains the configuration for scanning the image for
+	// vulnerabilities before a package revision that uses it is activated.
+	// +optional
+	Scanning *ImageScanning `json:"scanning,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +genclient
+// +genclient:nonNamespaced
+
+// The ImageConfig resource is used to configure settings for package images.
+// When several ImageConfigs match an image, the one with the longest matching
+// prefix is used.
+//
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane}
+type ImageConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ImageConfigSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ImageConfigList contains a list of ImageConfig.
+type ImageConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ImageConfig `json:"items"`
+}
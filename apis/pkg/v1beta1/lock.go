@@ -51,9 +51,31 @@ type LockPackage struct {
 	// Version is the tag or digest of the OCI image.
 	Version string `json:"version"`
 
+	// Digest of the OCI image, if the package revision is pinned to one.
+	// Together with the source it identifies exactly what was installed.
+	// +optional
+	Digest string `json:"digest,omitempty"`
+
 	// Dependencies are the list of dependencies of this package. The order of
 	// the dependencies will dictate the order in which they are resolved.
 	Dependencies []Dependency `json:"dependencies"`
+
+	// APIs the package depends on, in the form group/version. Any installed
+	// package that defines an API satisfies a dependency on it.
+	// +optional
+	APIs []string `json:"apis,omitempty"`
+
+	// DependencyUpgradePolicy of the package revision. The package manager
+	// only upgrades installed dependencies that don't satisfy the package's
+	// constraints if it's Automatic.
+	// +optional
+	DependencyUpgradePolicy DependencyUpgradePolicy `json:"dependencyUpgradePolicy,omitempty"`
+
+	// SkipDependencyResolution of the package revision. The package manager
+	// doesn't install missing dependencies that only packages that skip
+	// dependency resolution depend on.
+	// +optional
+	SkipDependencyResolution bool `json:"skipDependencyResolution,omitempty"`
 }
 
 // ToNodes converts LockPackages to DAG nodes.
@@ -97,6 +119,31 @@ type Dependency struct {
 	// Constraints is a valid semver range or a digest, which will be used to select a valid
 	// dependency version.
 	Constraints string `json:"constraints"`
+
+	// Channel is the tag the dependency tracks, if any. Constraints are
+	// ignored if a channel is set.
+	// +optional
+	Channel string `json:"channel,omitempty"`
+
+	// Alternatives are other packages that satisfy the dependency. The
+	// package manager installs the first of the dependency's package and its
+	// alternatives that it can resolve.
+	// +optional
+	Alternatives []DependencyAlternative `json:"alternatives,omitempty"`
+}
+
+// A DependencyAlternative is a package that satisfies a dependency in place of
+// the package the dependency names.
+type DependencyAlternative struct {
+	// Package is the OCI image name without a tag or digest.
+	Package string `json:"package"`
+
+	// Type is the type of package.
+	Type PackageType `json:"type"`
+
+	// Constraints is a valid semver range or a digest, which will be used to
+	// select a valid version of the alternative.
+	Constraints string `json:"constraints"`
 }
 
 // Identifier returns a dependency's source.
@@ -104,6 +151,14 @@ func (d *Dependency) Identifier() string {
 	return d.Package
 }
 
+// Candidates returns the packages that satisfy the dependency; its package
+// followed by its alternatives.
+func (d *Dependency) Candidates() []DependencyAlternative {
+	cs := make([]DependencyAlternative, 0, len(d.Alternatives)+1)
+	cs = append(cs, DependencyAlternative{Package: d.Package, Type: d.Type, Constraints: d.Constraints})
+	return append(cs, d.Alternatives...)
+}
+
 // Neighbors in is a no-op for dependencies because we are not yet aware of its
 // dependencies.
 func (d *Dependency) Neighbors() []dag.Node {
@@ -116,6 +171,24 @@ func (d *Dependency) AddNeighbors(...dag.Node) error {
 	return nil
 }
 
+// A DependencyResolution records the version and digest that the package
+// manager resolved a dependency's constraints to when it installed it.
+type DependencyResolution struct {
+	// Package is the OCI image name without a tag or digest.
+	Package string `json:"package"`
+
+	// Type is the type of package.
+	Type PackageType `json:"type"`
+
+	// Version the dependency's constraints resolved to. It's empty if the
+	// dependency was pinned to a digest.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Digest of the OCI image the version resolved to.
+	Digest string `json:"digest"`
+}
+
 // +kubebuilder:object:root=true
 // +genclient
 // +genclient:nonNamespaced
@@ -130,6 +203,51 @@ type Lock struct {
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
 	Packages []LockPackage `json:"packages,omitempty"`
+
+	// Resolutions of the dependencies the package manager installed. A
+	// dependency that must be installed again is installed at the same digest,
+	// as long as it still satisfies the constraints of the packages that
+	// depend on it.
+	// +optional
+	Resolutions []DependencyResolution `json:"resolutions,omitempty"`
+}
+
+// GetPackage returns the package with the supplied source, if it's in the
+// lock.
+func (l *Lock) GetPackage(source string) (LockPackage, bool) {
+	for _, p := range l.Packages {
+		if p.Source == source {
+			return p, true
+		}
+	}
+	return LockPackage{}, false
+}
+
+// A Dependent is a package in the lock that depends on another package.
+type Dependent struct {
+	// Package that depends on another package.
+	Package LockPackage
+
+	// Dependency that the package satisfies.
+	Dependency Dependency
+}
+
+// Dependents returns the packages in the lock that directly depend on the
+// package with the supplied source, either as a dependency or as one of a
+// dependency's alternatives.
+func (l *Lock) Dependents(source string) []Dependent {
+	var out []Dependent
+	for _, p := range l.Packages {
+		for _, d := range p.Dependencies {
+			for _, c := range d.Candidates() {
+				if c.Package == source {
+					out = append(out, Dependent{Package: p, Dependency: d})
+					break
+				}
+			}
+		}
+	}
+	return out
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PackageRepositoryTLS configures how the package manager connects to a
+// package repository.
+type PackageRepositoryTLS struct {
+	// CABundleSecretRef references a Secret containing the PEM encoded
+	// certificates of the certificate authorities the repository's
+	// certificate must chain to.
+	// +optional
+	CABundleSecretRef *SecretKeySelector `json:"caBundleSecretRef,omitempty"`
+
+	// ClientCertSecretRef references a kubernetes.io/tls Secret containing
+	// the client certificate to present to a repository that requires mutual
+	// TLS.
+	// +optional
+	ClientCertSecretRef *corev1.LocalObjectReference `json:"clientCertSecretRef,omitempty"`
+
+	// InsecureSkipVerify disables verification of the repository's
+	// certificate. It should only be used for testing.
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// PackageRepositorySpec specifies how packages in a repository are fetched.
+type PackageRepositorySpec struct {
+	// Prefix of the package references that belong to this repository,
+	// including their registry, e.g. xpkg.upbound.io/crossplane-contrib.
+	// +kubebuilder:validation:MinLength=1
+	Prefix string `json:"prefix"`
+
+	// Mirror replaces the prefix of package references that belong to this
+	// repository, e.g. registry.example.org/crossplane-contrib. Packages are
+	// fetched from the mirror, but are still identified by their original
+	// reference.
+	// +optional
+	Mirror *string `json:"mirror,omitempty"`
+
+	// PullSecrets are named Secrets in Crossplane's namespace that are used
+	// to fetch packages from this repository, in addition to any pull
+	// secrets of the package itself.
+	// +optional
+	PullSecrets []corev1.LocalObjectReference `json:"pullSecrets,omitempty"`
+
+	// TLS configures how the package manager connects to this repository.
+	// +optional
+	TLS *PackageRepositoryTLS `json:"tls,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +genclient
+// +genclient:nonNamespaced
+
+// A PackageRepository configures how the package manager fetches packages
+// whose references match its prefix. When several PackageRepositories match a
+// package, the one with the longest matching prefix is used.
+//
+// +kubebuilder:printcolumn:name="PREFIX",type="string",JSONPath=".spec.prefix"
+// +kubebuilder:printcolumn:name="MIRROR",type="string",JSONPath=".spec.mirror"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane}
+type PackageRepository struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PackageRepositorySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PackageRepositoryList contains a list of PackageRepository.
+type PackageRepositoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PackageRepository `json:"items"`
+}
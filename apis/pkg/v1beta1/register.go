@@ -72,9 +72,27 @@ var (
 	DeploymentRuntimeConfigGroupVersionKind = SchemeGroupVersion.WithKind(DeploymentRuntimeConfigKind)
 )
 
+// ImageConfig type metadata.
+var (
+	ImageConfigKind             = reflect.TypeOf(ImageConfig{}).Name()
+	ImageConfigGroupKind        = schema.GroupKind{Group: Group, Kind: ImageConfigKind}.String()
+	ImageConfigKindAPIVersion   = ImageConfigKind + "." + SchemeGroupVersion.String()
+	ImageConfigGroupVersionKind = SchemeGroupVersion.WithKind(ImageConfigKind)
+)
+
+// PackageRepository type metadata.
+var (
+	PackageRepositoryKind             = reflect.TypeOf(PackageRepository{}).Name()
+	PackageRepositoryGroupKind        = schema.GroupKind{Group: Group, Kind: PackageRepositoryKind}.String()
+	PackageRepositoryKindAPIVersion   = PackageRepositoryKind + "." + SchemeGroupVersion.String()
+	PackageRepositoryGroupVersionKind = SchemeGroupVersion.WithKind(PackageRepositoryKind)
+)
+
 func init() {
 	SchemeBuilder.Register(&Lock{}, &LockList{})
 	SchemeBuilder.Register(&Function{}, &FunctionList{})
 	SchemeBuilder.Register(&FunctionRevision{}, &FunctionRevisionList{})
 	SchemeBuilder.Register(&DeploymentRuntimeConfig{}, &DeploymentRuntimeConfigList{})
+	SchemeBuilder.Register(&ImageConfig{}, &ImageConfigList{})
+	SchemeBuilder.Register(&PackageRepository{}, &PackageRepositoryList{})
 }
@@ -58,9 +58,76 @@ func (in *ControllerReference) DeepCopy() *ControllerReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CosignAuthority) DeepCopyInto(out *CosignAuthority) {
+	*out = *in
+	if in.Key != nil {
+		in, out := &in.Key, &out.Key
+		*out = new(KeyRef)
+		**out = **in
+	}
+	if in.Keyless != nil {
+		in, out := &in.Keyless, &out.Keyless
+		*out = new(KeylessRef)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CosignAuthority.
+func (in *CosignAuthority) DeepCopy() *CosignAuthority {
+	if in == nil {
+		return nil
+	}
+	out := new(CosignAuthority)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CosignIdentity) DeepCopyInto(out *CosignIdentity) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CosignIdentity.
+func (in *CosignIdentity) DeepCopy() *CosignIdentity {
+	if in == nil {
+		return nil
+	}
+	out := new(CosignIdentity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CosignVerificationConfig) DeepCopyInto(out *CosignVerificationConfig) {
+	*out = *in
+	if in.Authorities != nil {
+		in, out := &in.Authorities, &out.Authorities
+		*out = make([]CosignAuthority, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CosignVerificationConfig.
+func (in *CosignVerificationConfig) DeepCopy() *CosignVerificationConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CosignVerificationConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Dependency) DeepCopyInto(out *Dependency) {
 	*out = *in
+	if in.Alternatives != nil {
+		in, out := &in.Alternatives, &out.Alternatives
+		*out = make([]DependencyAlternative, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Dependency.
@@ -73,6 +140,68 @@ func (in *Dependency) DeepCopy() *Dependency {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DependencyAlternative) DeepCopyInto(out *DependencyAlternative) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DependencyAlternative.
+func (in *DependencyAlternative) DeepCopy() *DependencyAlternative {
+	if in == nil {
+		return nil
+	}
+	out := new(DependencyAlternative)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DependencyEdge) DeepCopyInto(out *DependencyEdge) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DependencyEdge.
+func (in *DependencyEdge) DeepCopy() *DependencyEdge {
+	if in == nil {
+		return nil
+	}
+	out := new(DependencyEdge)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DependencyResolution) DeepCopyInto(out *DependencyResolution) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DependencyResolution.
+func (in *DependencyResolution) DeepCopy() *DependencyResolution {
+	if in == nil {
+		return nil
+	}
+	out := new(DependencyResolution)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Dependent) DeepCopyInto(out *Dependent) {
+	*out = *in
+	in.Package.DeepCopyInto(&out.Package)
+	in.Dependency.DeepCopyInto(&out.Dependency)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Dependent.
+func (in *Dependent) DeepCopy() *Dependent {
+	if in == nil {
+		return nil
+	}
+	out := new(Dependent)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeploymentRuntimeConfig) DeepCopyInto(out *DeploymentRuntimeConfig) {
 	*out = *in
@@ -149,6 +278,16 @@ func (in *DeploymentRuntimeConfigSpec) DeepCopyInto(out *DeploymentRuntimeConfig
 		*out = new(ServiceAccountTemplate)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AdditionalArgs != nil {
+		in, out := &in.AdditionalArgs, &out.AdditionalArgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.KubeconfigSecretRef != nil {
+		in, out := &in.KubeconfigSecretRef, &out.KubeconfigSecretRef
+		*out = new(SecretKeySelector)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentRuntimeConfigSpec.
@@ -358,7 +497,7 @@ func (in *FunctionSpec) DeepCopy() *FunctionSpec {
 func (in *FunctionStatus) DeepCopyInto(out *FunctionStatus) {
 	*out = *in
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
-	out.PackageStatus = in.PackageStatus
+	in.PackageStatus.DeepCopyInto(&out.PackageStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FunctionStatus.
@@ -372,31 +511,50 @@ func (in *FunctionStatus) DeepCopy() *FunctionStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Lock) DeepCopyInto(out *Lock) {
+func (in *HarborScanningConfig) DeepCopyInto(out *HarborScanningConfig) {
+	*out = *in
+	if in.URL != nil {
+		in, out := &in.URL, &out.URL
+		*out = new(string)
+		**out = **in
+	}
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborScanningConfig.
+func (in *HarborScanningConfig) DeepCopy() *HarborScanningConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborScanningConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageConfig) DeepCopyInto(out *ImageConfig) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	if in.Packages != nil {
-		in, out := &in.Packages, &out.Packages
-		*out = make([]LockPackage, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Lock.
-func (in *Lock) DeepCopy() *Lock {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageConfig.
+func (in *ImageConfig) DeepCopy() *ImageConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(Lock)
+	out := new(ImageConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Lock) DeepCopyObject() runtime.Object {
+func (in *ImageConfig) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -404,31 +562,31 @@ func (in *Lock) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LockList) DeepCopyInto(out *LockList) {
+func (in *ImageConfigList) DeepCopyInto(out *ImageConfigList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Lock, len(*in))
+		*out = make([]ImageConfig, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LockList.
-func (in *LockList) DeepCopy() *LockList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageConfigList.
+func (in *ImageConfigList) DeepCopy() *ImageConfigList {
 	if in == nil {
 		return nil
 	}
-	out := new(LockList)
+	out := new(ImageConfigList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *LockList) DeepCopyObject() runtime.Object {
+func (in *ImageConfigList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -436,202 +594,775 @@ func (in *LockList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LockPackage) DeepCopyInto(out *LockPackage) {
+func (in *ImageConfigSpec) DeepCopyInto(out *ImageConfigSpec) {
 	*out = *in
-	if in.Dependencies != nil {
-		in, out := &in.Dependencies, &out.Dependencies
-		*out = make([]Dependency, len(*in))
+	if in.MatchImages != nil {
+		in, out := &in.MatchImages, &out.MatchImages
+		*out = make([]ImageMatch, len(*in))
 		copy(*out, *in)
 	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(ImageVerification)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scanning != nil {
+		in, out := &in.Scanning, &out.Scanning
+		*out = new(ImageScanning)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LockPackage.
-func (in *LockPackage) DeepCopy() *LockPackage {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageConfigSpec.
+func (in *ImageConfigSpec) DeepCopy() *ImageConfigSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(LockPackage)
+	out := new(ImageConfigSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ObjectMeta) DeepCopyInto(out *ObjectMeta) {
+func (in *ImageMatch) DeepCopyInto(out *ImageMatch) {
 	*out = *in
-	if in.Name != nil {
-		in, out := &in.Name, &out.Name
-		*out = new(string)
-		**out = **in
-	}
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectMeta.
-func (in *ObjectMeta) DeepCopy() *ObjectMeta {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageMatch.
+func (in *ImageMatch) DeepCopy() *ImageMatch {
 	if in == nil {
 		return nil
 	}
-	out := new(ObjectMeta)
+	out := new(ImageMatch)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PackageRevisionRuntimeSpec) DeepCopyInto(out *PackageRevisionRuntimeSpec) {
+func (in *ImageScanning) DeepCopyInto(out *ImageScanning) {
 	*out = *in
-	in.PackageRuntimeSpec.DeepCopyInto(&out.PackageRuntimeSpec)
-	if in.TLSServerSecretName != nil {
-		in, out := &in.TLSServerSecretName, &out.TLSServerSecretName
-		*out = new(string)
-		**out = **in
+	if in.Harbor != nil {
+		in, out := &in.Harbor, &out.Harbor
+		*out = new(HarborScanningConfig)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.TLSClientSecretName != nil {
-		in, out := &in.TLSClientSecretName, &out.TLSClientSecretName
-		*out = new(string)
+	if in.FailOnSeverity != nil {
+		in, out := &in.FailOnSeverity, &out.FailOnSeverity
+		*out = new(VulnerabilitySeverity)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionRuntimeSpec.
-func (in *PackageRevisionRuntimeSpec) DeepCopy() *PackageRevisionRuntimeSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageScanning.
+func (in *ImageScanning) DeepCopy() *ImageScanning {
 	if in == nil {
 		return nil
 	}
-	out := new(PackageRevisionRuntimeSpec)
+	out := new(ImageScanning)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PackageRevisionSpec) DeepCopyInto(out *PackageRevisionSpec) {
+func (in *ImageSource) DeepCopyInto(out *ImageSource) {
 	*out = *in
-	if in.PackagePullSecrets != nil {
-		in, out := &in.PackagePullSecrets, &out.PackagePullSecrets
-		*out = make([]corev1.LocalObjectReference, len(*in))
-		copy(*out, *in)
-	}
-	if in.PackagePullPolicy != nil {
-		in, out := &in.PackagePullPolicy, &out.PackagePullPolicy
-		*out = new(corev1.PullPolicy)
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(ImageSourceSecret)
 		**out = **in
 	}
-	if in.IgnoreCrossplaneConstraints != nil {
-		in, out := &in.IgnoreCrossplaneConstraints, &out.IgnoreCrossplaneConstraints
-		*out = new(bool)
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(string)
 		**out = **in
 	}
-	if in.SkipDependencyResolution != nil {
-		in, out := &in.SkipDependencyResolution, &out.SkipDependencyResolution
-		*out = new(bool)
+	if in.Path != nil {
+		in, out := &in.Path, &out.Path
+		*out = new(string)
 		**out = **in
 	}
-	if in.CommonLabels != nil {
-		in, out := &in.CommonLabels, &out.CommonLabels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionSpec.
-func (in *PackageRevisionSpec) DeepCopy() *PackageRevisionSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSource.
+func (in *ImageSource) DeepCopy() *ImageSource {
 	if in == nil {
 		return nil
 	}
-	out := new(PackageRevisionSpec)
+	out := new(ImageSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PackageRevisionStatus) DeepCopyInto(out *PackageRevisionStatus) {
+func (in *ImageSourceSecret) DeepCopyInto(out *ImageSourceSecret) {
 	*out = *in
-	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
-	if in.ObjectRefs != nil {
-		in, out := &in.ObjectRefs, &out.ObjectRefs
-		*out = make([]commonv1.TypedReference, len(*in))
-		copy(*out, *in)
-	}
-	if in.PermissionRequests != nil {
-		in, out := &in.PermissionRequests, &out.PermissionRequests
-		*out = make([]rbacv1.PolicyRule, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionStatus.
-func (in *PackageRevisionStatus) DeepCopy() *PackageRevisionStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSourceSecret.
+func (in *ImageSourceSecret) DeepCopy() *ImageSourceSecret {
 	if in == nil {
 		return nil
 	}
-	out := new(PackageRevisionStatus)
+	out := new(ImageSourceSecret)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PackageRuntimeSpec) DeepCopyInto(out *PackageRuntimeSpec) {
+func (in *ImageVerification) DeepCopyInto(out *ImageVerification) {
 	*out = *in
-	if in.ControllerConfigReference != nil {
-		in, out := &in.ControllerConfigReference, &out.ControllerConfigReference
-		*out = new(ControllerConfigReference)
-		**out = **in
-	}
-	if in.RuntimeConfigReference != nil {
-		in, out := &in.RuntimeConfigReference, &out.RuntimeConfigReference
-		*out = new(RuntimeConfigReference)
+	if in.Cosign != nil {
+		in, out := &in.Cosign, &out.Cosign
+		*out = new(CosignVerificationConfig)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRuntimeSpec.
-func (in *PackageRuntimeSpec) DeepCopy() *PackageRuntimeSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageVerification.
+func (in *ImageVerification) DeepCopy() *ImageVerification {
 	if in == nil {
 		return nil
 	}
-	out := new(PackageRuntimeSpec)
+	out := new(ImageVerification)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PackageSpec) DeepCopyInto(out *PackageSpec) {
+func (in *KeyRef) DeepCopyInto(out *KeyRef) {
 	*out = *in
-	if in.RevisionActivationPolicy != nil {
-		in, out := &in.RevisionActivationPolicy, &out.RevisionActivationPolicy
-		*out = new(RevisionActivationPolicy)
-		**out = **in
-	}
-	if in.RevisionHistoryLimit != nil {
-		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
-		*out = new(int64)
-		**out = **in
+	out.SecretRef = in.SecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyRef.
+func (in *KeyRef) DeepCopy() *KeyRef {
+	if in == nil {
+		return nil
 	}
-	if in.PackagePullSecrets != nil {
-		in, out := &in.PackagePullSecrets, &out.PackagePullSecrets
-		*out = make([]corev1.LocalObjectReference, len(*in))
+	out := new(KeyRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeylessRef) DeepCopyInto(out *KeylessRef) {
+	*out = *in
+	out.CertificateAuthoritySecretRef = in.CertificateAuthoritySecretRef
+	if in.Identities != nil {
+		in, out := &in.Identities, &out.Identities
+		*out = make([]CosignIdentity, len(*in))
 		copy(*out, *in)
 	}
-	if in.PackagePullPolicy != nil {
-		in, out := &in.PackagePullPolicy, &out.PackagePullPolicy
-		*out = new(corev1.PullPolicy)
-		**out = **in
+	out.TransparencyLogSecretRef = in.TransparencyLogSecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeylessRef.
+func (in *KeylessRef) DeepCopy() *KeylessRef {
+	if in == nil {
+		return nil
+	}
+	out := new(KeylessRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Lock) DeepCopyInto(out *Lock) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Packages != nil {
+		in, out := &in.Packages, &out.Packages
+		*out = make([]LockPackage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Resolutions != nil {
+		in, out := &in.Resolutions, &out.Resolutions
+		*out = make([]DependencyResolution, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Lock.
+func (in *Lock) DeepCopy() *Lock {
+	if in == nil {
+		return nil
+	}
+	out := new(Lock)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Lock) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LockList) DeepCopyInto(out *LockList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Lock, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LockList.
+func (in *LockList) DeepCopy() *LockList {
+	if in == nil {
+		return nil
+	}
+	out := new(LockList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LockList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LockPackage) DeepCopyInto(out *LockPackage) {
+	*out = *in
+	if in.Dependencies != nil {
+		in, out := &in.Dependencies, &out.Dependencies
+		*out = make([]Dependency, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.APIs != nil {
+		in, out := &in.APIs, &out.APIs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LockPackage.
+func (in *LockPackage) DeepCopy() *LockPackage {
+	if in == nil {
+		return nil
+	}
+	out := new(LockPackage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectMeta) DeepCopyInto(out *ObjectMeta) {
+	*out = *in
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectMeta.
+func (in *ObjectMeta) DeepCopy() *ObjectMeta {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectMeta)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectRefGroup) DeepCopyInto(out *ObjectRefGroup) {
+	*out = *in
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectRefGroup.
+func (in *ObjectRefGroup) DeepCopy() *ObjectRefGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectRefGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageBuild) DeepCopyInto(out *PackageBuild) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageBuild.
+func (in *PackageBuild) DeepCopy() *PackageBuild {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageBuild)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageContents) DeepCopyInto(out *PackageContents) {
+	*out = *in
+	if in.Objects != nil {
+		in, out := &in.Objects, &out.Objects
+		*out = make([]PackageObjectCount, len(*in))
+		copy(*out, *in)
+	}
+	if in.Warnings != nil {
+		in, out := &in.Warnings, &out.Warnings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageContents.
+func (in *PackageContents) DeepCopy() *PackageContents {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageContents)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageFootprint) DeepCopyInto(out *PackageFootprint) {
+	*out = *in
+	if in.SampleTime != nil {
+		in, out := &in.SampleTime, &out.SampleTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageFootprint.
+func (in *PackageFootprint) DeepCopy() *PackageFootprint {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageFootprint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageImage) DeepCopyInto(out *PackageImage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageImage.
+func (in *PackageImage) DeepCopy() *PackageImage {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageObjectCount) DeepCopyInto(out *PackageObjectCount) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageObjectCount.
+func (in *PackageObjectCount) DeepCopy() *PackageObjectCount {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageObjectCount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageRepository) DeepCopyInto(out *PackageRepository) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRepository.
+func (in *PackageRepository) DeepCopy() *PackageRepository {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageRepository)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PackageRepository) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageRepositoryList) DeepCopyInto(out *PackageRepositoryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PackageRepository, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRepositoryList.
+func (in *PackageRepositoryList) DeepCopy() *PackageRepositoryList {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageRepositoryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PackageRepositoryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageRepositorySpec) DeepCopyInto(out *PackageRepositorySpec) {
+	*out = *in
+	if in.Mirror != nil {
+		in, out := &in.Mirror, &out.Mirror
+		*out = new(string)
+		**out = **in
+	}
+	if in.PullSecrets != nil {
+		in, out := &in.PullSecrets, &out.PullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(PackageRepositoryTLS)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRepositorySpec.
+func (in *PackageRepositorySpec) DeepCopy() *PackageRepositorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageRepositorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageRepositoryTLS) DeepCopyInto(out *PackageRepositoryTLS) {
+	*out = *in
+	if in.CABundleSecretRef != nil {
+		in, out := &in.CABundleSecretRef, &out.CABundleSecretRef
+		*out = new(SecretKeySelector)
+		**out = **in
+	}
+	if in.ClientCertSecretRef != nil {
+		in, out := &in.ClientCertSecretRef, &out.ClientCertSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRepositoryTLS.
+func (in *PackageRepositoryTLS) DeepCopy() *PackageRepositoryTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageRepositoryTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageRevisionRuntimeSpec) DeepCopyInto(out *PackageRevisionRuntimeSpec) {
+	*out = *in
+	in.PackageRuntimeSpec.DeepCopyInto(&out.PackageRuntimeSpec)
+	if in.TLSServerSecretName != nil {
+		in, out := &in.TLSServerSecretName, &out.TLSServerSecretName
+		*out = new(string)
+		**out = **in
+	}
+	if in.TLSClientSecretName != nil {
+		in, out := &in.TLSClientSecretName, &out.TLSClientSecretName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionRuntimeSpec.
+func (in *PackageRevisionRuntimeSpec) DeepCopy() *PackageRevisionRuntimeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageRevisionRuntimeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageRevisionRuntimeStatus) DeepCopyInto(out *PackageRevisionRuntimeStatus) {
+	*out = *in
+	if in.DeploymentRef != nil {
+		in, out := &in.DeploymentRef, &out.DeploymentRef
+		*out = new(RuntimeObjectReference)
+		**out = **in
+	}
+	if in.ServiceAccountRef != nil {
+		in, out := &in.ServiceAccountRef, &out.ServiceAccountRef
+		*out = new(RuntimeObjectReference)
+		**out = **in
+	}
+	if in.ServiceRef != nil {
+		in, out := &in.ServiceRef, &out.ServiceRef
+		*out = new(RuntimeObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionRuntimeStatus.
+func (in *PackageRevisionRuntimeStatus) DeepCopy() *PackageRevisionRuntimeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageRevisionRuntimeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageRevisionSpec) DeepCopyInto(out *PackageRevisionSpec) {
+	*out = *in
+	if in.PackagePullSecrets != nil {
+		in, out := &in.PackagePullSecrets, &out.PackagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.PackagePullPolicy != nil {
+		in, out := &in.PackagePullPolicy, &out.PackagePullPolicy
+		*out = new(corev1.PullPolicy)
+		**out = **in
+	}
+	if in.ImageSource != nil {
+		in, out := &in.ImageSource, &out.ImageSource
+		*out = new(ImageSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IgnoreCrossplaneConstraints != nil {
+		in, out := &in.IgnoreCrossplaneConstraints, &out.IgnoreCrossplaneConstraints
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SkipDependencyResolution != nil {
+		in, out := &in.SkipDependencyResolution, &out.SkipDependencyResolution
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DependencyUpgradePolicy != nil {
+		in, out := &in.DependencyUpgradePolicy, &out.DependencyUpgradePolicy
+		*out = new(DependencyUpgradePolicy)
+		**out = **in
+	}
+	if in.Paused != nil {
+		in, out := &in.Paused, &out.Paused
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CommonLabels != nil {
+		in, out := &in.CommonLabels, &out.CommonLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionSpec.
+func (in *PackageRevisionSpec) DeepCopy() *PackageRevisionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageRevisionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageRevisionStatus) DeepCopyInto(out *PackageRevisionStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	if in.ObjectRefs != nil {
+		in, out := &in.ObjectRefs, &out.ObjectRefs
+		*out = make([]commonv1.TypedReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.ObjectRefGroups != nil {
+		in, out := &in.ObjectRefGroups, &out.ObjectRefGroups
+		*out = make([]ObjectRefGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]DependencyEdge, len(*in))
+		copy(*out, *in)
+	}
+	if in.InstalledBy != nil {
+		in, out := &in.InstalledBy, &out.InstalledBy
+		*out = make([]DependencyEdge, len(*in))
+		copy(*out, *in)
+	}
+	if in.PermissionRequests != nil {
+		in, out := &in.PermissionRequests, &out.PermissionRequests
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Runtime != nil {
+		in, out := &in.Runtime, &out.Runtime
+		*out = new(PackageRevisionRuntimeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Contents != nil {
+		in, out := &in.Contents, &out.Contents
+		*out = new(PackageContents)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(PackageImage)
+		**out = **in
+	}
+	if in.Build != nil {
+		in, out := &in.Build, &out.Build
+		*out = new(PackageBuild)
+		**out = **in
+	}
+	if in.Footprint != nil {
+		in, out := &in.Footprint, &out.Footprint
+		*out = new(PackageFootprint)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Vulnerabilities != nil {
+		in, out := &in.Vulnerabilities, &out.Vulnerabilities
+		*out = new(PackageVulnerabilities)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionStatus.
+func (in *PackageRevisionStatus) DeepCopy() *PackageRevisionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageRevisionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageRuntimeSpec) DeepCopyInto(out *PackageRuntimeSpec) {
+	*out = *in
+	if in.ControllerConfigReference != nil {
+		in, out := &in.ControllerConfigReference, &out.ControllerConfigReference
+		*out = new(ControllerConfigReference)
+		**out = **in
+	}
+	if in.RuntimeConfigReference != nil {
+		in, out := &in.RuntimeConfigReference, &out.RuntimeConfigReference
+		*out = new(RuntimeConfigReference)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRuntimeSpec.
+func (in *PackageRuntimeSpec) DeepCopy() *PackageRuntimeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageRuntimeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageSpec) DeepCopyInto(out *PackageSpec) {
+	*out = *in
+	if in.VersionConstraint != nil {
+		in, out := &in.VersionConstraint, &out.VersionConstraint
+		*out = new(string)
+		**out = **in
+	}
+	if in.RevisionActivationPolicy != nil {
+		in, out := &in.RevisionActivationPolicy, &out.RevisionActivationPolicy
+		*out = new(RevisionActivationPolicy)
+		**out = **in
+	}
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PackagePullSecrets != nil {
+		in, out := &in.PackagePullSecrets, &out.PackagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.PackagePullPolicy != nil {
+		in, out := &in.PackagePullPolicy, &out.PackagePullPolicy
+		*out = new(corev1.PullPolicy)
+		**out = **in
+	}
+	if in.ImageSource != nil {
+		in, out := &in.ImageSource, &out.ImageSource
+		*out = new(ImageSource)
+		(*in).DeepCopyInto(*out)
 	}
 	if in.IgnoreCrossplaneConstraints != nil {
 		in, out := &in.IgnoreCrossplaneConstraints, &out.IgnoreCrossplaneConstraints
@@ -643,6 +1374,16 @@ func (in *PackageSpec) DeepCopyInto(out *PackageSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.DependencyUpgradePolicy != nil {
+		in, out := &in.DependencyUpgradePolicy, &out.DependencyUpgradePolicy
+		*out = new(DependencyUpgradePolicy)
+		**out = **in
+	}
+	if in.Paused != nil {
+		in, out := &in.Paused, &out.Paused
+		*out = new(bool)
+		**out = **in
+	}
 	if in.CommonLabels != nil {
 		in, out := &in.CommonLabels, &out.CommonLabels
 		*out = make(map[string]string, len(*in))
@@ -665,6 +1406,21 @@ func (in *PackageSpec) DeepCopy() *PackageSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PackageStatus) DeepCopyInto(out *PackageStatus) {
 	*out = *in
+	if in.Footprint != nil {
+		in, out := &in.Footprint, &out.Footprint
+		*out = new(PackageFootprint)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]DependencyEdge, len(*in))
+		copy(*out, *in)
+	}
+	if in.InstalledBy != nil {
+		in, out := &in.InstalledBy, &out.InstalledBy
+		*out = make([]DependencyEdge, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageStatus.
@@ -677,6 +1433,45 @@ func (in *PackageStatus) DeepCopy() *PackageStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageVulnerabilities) DeepCopyInto(out *PackageVulnerabilities) {
+	*out = *in
+	if in.Findings != nil {
+		in, out := &in.Findings, &out.Findings
+		*out = make([]PackageVulnerability, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScanTime != nil {
+		in, out := &in.ScanTime, &out.ScanTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageVulnerabilities.
+func (in *PackageVulnerabilities) DeepCopy() *PackageVulnerabilities {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageVulnerabilities)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageVulnerability) DeepCopyInto(out *PackageVulnerability) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageVulnerability.
+func (in *PackageVulnerability) DeepCopy() *PackageVulnerability {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageVulnerability)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RuntimeConfigReference) DeepCopyInto(out *RuntimeConfigReference) {
 	*out = *in
@@ -702,6 +1497,36 @@ func (in *RuntimeConfigReference) DeepCopy() *RuntimeConfigReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeObjectReference) DeepCopyInto(out *RuntimeObjectReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeObjectReference.
+func (in *RuntimeObjectReference) DeepCopy() *RuntimeObjectReference {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretKeySelector) DeepCopyInto(out *SecretKeySelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeySelector.
+func (in *SecretKeySelector) DeepCopy() *SecretKeySelector {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretKeySelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceAccountTemplate) DeepCopyInto(out *ServiceAccountTemplate) {
 	*out = *in
@@ -730,6 +1555,13 @@ func (in *ServiceTemplate) DeepCopyInto(out *ServiceTemplate) {
 		*out = new(ObjectMeta)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]corev1.ServicePort, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceTemplate.
@@ -71,3 +71,37 @@ type RuntimeConfigReference struct {
 	// Name of the RuntimeConfig.
 	Name string `json:"name"`
 }
+
+// PackageRevisionRuntimeStatus represents the observed state of the runtime of
+// a package revision.
+type PackageRevisionRuntimeStatus struct {
+	// DeploymentRef references the Deployment that runs the package revision's
+	// runtime.
+	// +optional
+	DeploymentRef *RuntimeObjectReference `json:"deploymentRef,omitempty"`
+
+	// ServiceAccountRef references the ServiceAccount used by the package
+	// revision's runtime.
+	// +optional
+	ServiceAccountRef *RuntimeObjectReference `json:"serviceAccountRef,omitempty"`
+
+	// ServiceRef references the Service that fronts the package revision's
+	// runtime. Its name is stable across package revisions.
+	// +optional
+	ServiceRef *RuntimeObjectReference `json:"serviceRef,omitempty"`
+
+	// ServiceDNSName is the DNS name other components in the cluster may use
+	// to reach the package revision's runtime through its Service.
+	// +optional
+	ServiceDNSName string `json:"serviceDNSName,omitempty"`
+}
+
+// A RuntimeObjectReference references an object created to run a package
+// revision's runtime.
+type RuntimeObjectReference struct {
+	// Name of the referenced object.
+	Name string `json:"name"`
+
+	// Namespace of the referenced object.
+	Namespace string `json:"namespace"`
+}
@@ -20,7 +20,9 @@ limitations under the License.
 package beta
 
 import (
+	"github.com/crossplane/crossplane/cmd/crank/beta/composition"
 	"github.com/crossplane/crossplane/cmd/crank/beta/convert"
+	"github.com/crossplane/crossplane/cmd/crank/beta/dependencies"
 	"github.com/crossplane/crossplane/cmd/crank/beta/top"
 	"github.com/crossplane/crossplane/cmd/crank/beta/trace"
 	"github.com/crossplane/crossplane/cmd/crank/beta/validate"
@@ -30,10 +32,12 @@ import (
 type Cmd struct {
 	// Subcommands and flags will appear in the CLI help output in the same
 	// order they're specified here. Keep them in alphabetical order.
-	Convert  convert.Cmd  `cmd:"" help:"Convert a Crossplane resource to a newer version or kind."`
-	Top      top.Cmd      `cmd:"" help:"Display resource (CPU/memory) usage by Crossplane related pods."`
-	Trace    trace.Cmd    `cmd:"" help:"Trace a Crossplane resource to get a detailed output of its relationships, helpful for troubleshooting."`
-	Validate validate.Cmd `cmd:"" help:"Validate Crossplane resources."`
+	Convert              convert.Cmd      `cmd:"" help:"Convert a Crossplane resource to a newer version or kind."`
+	Dependencies         dependencies.Cmd `cmd:"" help:"Explain why a package is installed."`
+	EffectiveComposition composition.Cmd  `cmd:"" help:"Print the composition a composite resource or claim was last composed with."`
+	Top                  top.Cmd          `cmd:"" help:"Display resource (CPU/memory) usage by Crossplane related pods."`
+	Trace                trace.Cmd        `cmd:"" help:"Trace a Crossplane resource to get a detailed output of its relationships, helpful for troubleshooting."`
+	Validate             validate.Cmd     `cmd:"" help:"Validate Crossplane resources."`
 }
 
 // Help output for crossplane beta.
@@ -0,0 +1,239 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package composition contains the effective-composition command.
+package composition
+
+import (
+	"context"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+const (
+	errKubeConfig         = "failed to get kubeconfig"
+	errKubeNamespace      = "failed to get namespace from kubeconfig"
+	errInitKubeClient     = "cannot init kubeclient"
+	errGetDiscoveryClient = "cannot get discovery client"
+	errGetMapping         = "cannot get mapping for resource"
+	errGetResource        = "cannot get requested resource"
+	errGetComposite       = "cannot get composite resource referenced by claim"
+	errGetRevision        = "cannot get composition revision"
+	errNotComposed        = "resource has not been composed yet - it has no status.compositionRevisionRef"
+	errWriteOutput        = "cannot write composition"
+	errNameDoubled        = "name provided twice, must be provided separately 'TYPE[.VERSION][.GROUP] [NAME]' or in the 'TYPE[.VERSION][.GROUP][/NAME]' format"
+	errMissingName        = "missing name, must be provided separately 'TYPE[.VERSION][.GROUP] [NAME]' or in the 'TYPE[.VERSION][.GROUP][/NAME]' format"
+	errInvalidResource    = "invalid resource, must be provided in the 'TYPE[.VERSION][.GROUP][/NAME]' format"
+)
+
+// AnnotationKeyCompositionRevision is added to the composition printed by the
+// effective-composition command, to record which revision it was built from.
+const AnnotationKeyCompositionRevision = "crossplane.io/composition-revision"
+
+// Cmd prints the composition a composite resource was last composed with.
+type Cmd struct {
+	Resource string `arg:"" help:"Kind of the composite resource or claim, accepts the 'TYPE[.VERSION][.GROUP][/NAME]' format."`
+	Name     string `arg:"" help:"Name of the composite resource or claim, can be passed as part of the resource too." optional:""`
+
+	Context   string `default:"" help:"Kubernetes context."        name:"context"   short:"c"`
+	Namespace string `default:"" help:"Namespace of the resource." name:"namespace" short:"n"`
+}
+
+// Help returns help message for the effective-composition command.
+func (c *Cmd) Help() string {
+	return `
+This command prints the exact composition a composite resource (XR) was last
+composed with. It's built from the composition revision recorded in the XR's
+status.compositionRevisionRef, so it doesn't change when the composition is
+updated. Claims are followed to the XR they reference.
+
+If needed the resource kind can be also specified further,
+'TYPE[.VERSION][.GROUP]', e.g. mykind.example.org or
+mykind.v1alpha1.example.org.
+
+Examples:
+  # Print the composition the XR 'my-xr' of kind XMyKind was composed with.
+  crossplane beta effective-composition xmykind my-xr
+
+  # Print the composition the claim 'my-claim' in the namespace 'my-ns' was
+  # composed with.
+  crossplane beta effective-composition mykind my-claim -n my-ns
+`
+}
+
+// Run runs the effective-composition command.
+func (c *Cmd) Run(k *kong.Context, logger logging.Logger) error {
+	ctx := context.Background()
+	logger = logger.WithValues("Resource", c.Resource, "Name", c.Name)
+
+	res, name, err := c.getResourceAndName()
+	if err != nil {
+		return err
+	}
+
+	clientconfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(),
+		&clientcmd.ConfigOverrides{CurrentContext: c.Context},
+	)
+	kubeconfig, err := clientconfig.ClientConfig()
+	if err != nil {
+		return errors.Wrap(err, errKubeConfig)
+	}
+
+	s := runtime.NewScheme()
+	_ = v1.AddToScheme(s)
+	kube, err := client.New(kubeconfig, client.Options{Scheme: s})
+	if err != nil {
+		return errors.Wrap(err, errInitKubeClient)
+	}
+
+	dc, err := discovery.NewDiscoveryClientForConfig(kubeconfig)
+	if err != nil {
+		return errors.Wrap(err, errGetDiscoveryClient)
+	}
+	d := memory.NewMemCacheClient(dc)
+	rmapper := restmapper.NewShortcutExpander(restmapper.NewDeferredDiscoveryRESTMapper(d), d, nil)
+
+	gvk, err := kindFor(rmapper, res)
+	if err != nil {
+		return errors.Wrap(err, errGetMapping)
+	}
+	mapping, err := rmapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return errors.Wrap(err, errGetMapping)
+	}
+
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvk)
+	nn := types.NamespacedName{Name: name}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		nn.Namespace = c.Namespace
+		if nn.Namespace == "" {
+			nn.Namespace, _, err = clientconfig.Namespace()
+			if err != nil {
+				return errors.Wrap(err, errKubeNamespace)
+			}
+		}
+	}
+	if err := kube.Get(ctx, nn, u); err != nil {
+		return errors.Wrap(err, errGetResource)
+	}
+	logger.Debug("Found resource", "gvk", gvk.String(), "namespace", nn.Namespace)
+
+	comp, err := EffectiveComposition(ctx, kube, u)
+	if err != nil {
+		return err
+	}
+
+	ser := json.NewSerializerWithOptions(json.DefaultMetaFactory, nil, nil, json.SerializerOptions{Yaml: true})
+	return errors.Wrap(ser.Encode(comp, k.Stdout), errWriteOutput)
+}
+
+// EffectiveComposition returns the composition the supplied composite resource
+// was last composed with, built from the composition revision recorded in its
+// status. If the supplied resource is a claim the composite resource it
+// references is used.
+func EffectiveComposition(ctx context.Context, c client.Reader, u *unstructured.Unstructured) (*v1.Composition, error) {
+	p := fieldpath.Pave(u.Object)
+
+	// Claims don't have a status.compositionRevisionRef, but reference the
+	// XR that does.
+	if _, err := p.GetString("status.compositionRevisionRef.name"); fieldpath.IsNotFound(err) {
+		ref := &corev1.ObjectReference{}
+		if err := p.GetValueInto("spec.resourceRef", ref); err == nil {
+			xr := &unstructured.Unstructured{}
+			xr.SetAPIVersion(ref.APIVersion)
+			xr.SetKind(ref.Kind)
+			if err := c.Get(ctx, types.NamespacedName{Name: ref.Name}, xr); err != nil {
+				return nil, errors.Wrap(err, errGetComposite)
+			}
+			p = fieldpath.Pave(xr.Object)
+		}
+	}
+
+	name, err := p.GetString("status.compositionRevisionRef.name")
+	if err != nil {
+		return nil, errors.New(errNotComposed)
+	}
+
+	rev := &v1.CompositionRevision{}
+	if err := c.Get(ctx, types.NamespacedName{Name: name}, rev); err != nil {
+		return nil, errors.Wrap(err, errGetRevision)
+	}
+
+	conv := v1.GeneratedRevisionSpecConverter{}
+	comp := &v1.Composition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1.CompositionGroupVersionKind.GroupVersion().String(),
+			Kind:       v1.CompositionKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: rev.GetLabels()[v1.LabelCompositionName],
+			Annotations: map[string]string{
+				AnnotationKeyCompositionRevision: rev.GetName(),
+			},
+		},
+		Spec: conv.FromRevisionSpec(rev.Spec),
+	}
+	return comp, nil
+}
+
+// kindFor returns the GroupVersionKind for the supplied 'TYPE[.VERSION][.GROUP]'
+// argument.
+func kindFor(rmapper meta.RESTMapper, arg string) (schema.GroupVersionKind, error) {
+	gvr, gr := schema.ParseResourceArg(arg)
+	if gvr != nil {
+		if gvk, err := rmapper.KindFor(*gvr); err == nil {
+			return gvk, nil
+		}
+	}
+	return rmapper.KindFor(gr.WithVersion(""))
+}
+
+func (c *Cmd) getResourceAndName() (string, string, error) {
+	res, name, found := strings.Cut(c.Resource, "/")
+	switch {
+	case res == "" || strings.Contains(name, "/"):
+		return "", "", errors.New(errInvalidResource)
+	case found && c.Name != "":
+		return "", "", errors.New(errNameDoubled)
+	case found:
+		return res, name, nil
+	case c.Name == "":
+		return "", "", errors.New(errMissingName)
+	}
+	return res, c.Name, nil
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composition
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestEffectiveComposition(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	xr := func() *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "example.org/v1",
+			"kind":       "XCoolComposite",
+			"metadata":   map[string]any{"name": "cool-xr"},
+			"status": map[string]any{
+				"compositionRevisionRef": map[string]any{"name": "cool-composition-abc1234"},
+			},
+		}}
+	}
+	claim := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "example.org/v1",
+		"kind":       "CoolClaim",
+		"metadata":   map[string]any{"namespace": "default", "name": "cool-claim"},
+		"spec": map[string]any{
+			"resourceRef": map[string]any{"apiVersion": "example.org/v1", "kind": "XCoolComposite", "name": "cool-xr"},
+		},
+	}}
+	rev := &v1.CompositionRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "cool-composition-abc1234",
+			Labels: map[string]string{v1.LabelCompositionName: "cool-composition"},
+		},
+		Spec: v1.CompositionRevisionSpec{
+			CompositeTypeRef: v1.TypeReference{APIVersion: "example.org/v1", Kind: "XCoolComposite"},
+			Revision:         2,
+		},
+	}
+	comp := &v1.Composition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apiextensions.crossplane.io/v1",
+			Kind:       "Composition",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "cool-composition",
+			Annotations: map[string]string{AnnotationKeyCompositionRevision: "cool-composition-abc1234"},
+		},
+		Spec: v1.CompositionSpec{
+			CompositeTypeRef: v1.TypeReference{APIVersion: "example.org/v1", Kind: "XCoolComposite"},
+		},
+	}
+
+	get := func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+		switch o := obj.(type) {
+		case *v1.CompositionRevision:
+			rev.DeepCopyInto(o)
+		case *unstructured.Unstructured:
+			o.Object = xr().Object
+		}
+		return nil
+	}
+
+	type args struct {
+		c client.Reader
+		u *unstructured.Unstructured
+	}
+	type want struct {
+		comp *v1.Composition
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"CompositeResource": {
+			reason: "We should return the composition built from the revision the XR was composed with.",
+			args: args{
+				c: &test.MockClient{MockGet: get},
+				u: xr(),
+			},
+			want: want{
+				comp: comp,
+			},
+		},
+		"Claim": {
+			reason: "We should follow a claim to its XR, and return the composition built from the revision it was composed with.",
+			args: args{
+				c: &test.MockClient{MockGet: get},
+				u: claim,
+			},
+			want: want{
+				comp: comp,
+			},
+		},
+		"NotComposed": {
+			reason: "We should return an error if the XR hasn't been composed yet.",
+			args: args{
+				c: &test.MockClient{MockGet: get},
+				u: &unstructured.Unstructured{Object: map[string]any{}},
+			},
+			want: want{
+				err: errors.New(errNotComposed),
+			},
+		},
+		"GetCompositeError": {
+			reason: "We should return an error if we can't get the XR referenced by a claim.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				u: claim,
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetComposite),
+			},
+		},
+		"GetRevisionError": {
+			reason: "We should return an error if we can't get the composition revision.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				u: xr(),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetRevision),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := EffectiveComposition(context.Background(), tc.args.c, tc.args.u)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nEffectiveComposition(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.comp, got); diff != "" {
+				t.Errorf("\n%s\nEffectiveComposition(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package compositeresourcedefinition contains the logic for converting a
+// CustomResourceDefinition to a CompositeResourceDefinition that adopts it.
+package compositeresourcedefinition
+
+import (
+	"github.com/spf13/afero"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/cmd/crank/beta/convert/io"
+)
+
+// Cmd arguments and flags for convert composite-resource-definition subcommand.
+type Cmd struct {
+	// Arguments.
+	InputFile string `arg:"" default:"-" help:"The CustomResourceDefinition file to be converted. If not specified or '-', stdin will be used." optional:"" type:"path"`
+
+	// Flags.
+	OutputFile         string `help:"The file to write the generated CompositeResourceDefinition to. If not specified, stdout will be used." placeholder:"PATH" short:"o" type:"path"`
+	DefaultComposition string `help:"The name of the Composition that composes resources for the adopted custom resources by default."       placeholder:"NAME" short:"c" type:"string"`
+
+	fs afero.Fs
+}
+
+// Help returns help message for the convert composite-resource-definition
+// command.
+func (c *Cmd) Help() string {
+	return `
+This command converts a CustomResourceDefinition to a CompositeResourceDefinition
+(XRD) that adopts it.
+
+Crossplane adopts an existing CustomResourceDefinition that isn't controlled by
+another controller when an XRD defines a type with the same name. The existing
+custom resources then become composite resources, or claims, without being
+recreated. This allows a platform built on plain CustomResourceDefinitions to
+move onto Compositions.
+
+A cluster scoped CustomResourceDefinition becomes a composite resource type. A
+namespaced CustomResourceDefinition becomes a claim type, and its composite
+resource type is named by prefixing its names with 'x', for example XDatabase.
+
+The schema and versions of the CustomResourceDefinition are kept. Fields that
+Crossplane adds to every composite resource or claim, for example
+spec.compositionRef, override fields of the same name.
+
+Examples:
+
+  # Write out an XRD that adopts a CustomResourceDefinition
+  crossplane beta convert composite-resource-definition crd.yaml -o xrd.yaml
+
+  # Adopt a CustomResourceDefinition in the cluster, composing its custom
+  # resources using the Composition named 'databases' by default
+  kubectl get crd databases.example.org -o yaml | crossplane beta convert composite-resource-definition -c databases | kubectl apply -f -
+
+`
+}
+
+// AfterApply implements kong.AfterApply.
+func (c *Cmd) AfterApply() error {
+	c.fs = afero.NewOsFs()
+	return nil
+}
+
+// Run converts a CustomResourceDefinition to a CompositeResourceDefinition.
+func (c *Cmd) Run() error {
+	data, err := io.Read(c.fs, c.InputFile)
+	if err != nil {
+		return err
+	}
+
+	// Set up schemes for our API types
+	sch := runtime.NewScheme()
+	_ = extv1.AddToScheme(sch)
+	_ = v1.AddToScheme(sch)
+
+	decode := serializer.NewCodecFactory(sch).UniversalDeserializer().Decode
+
+	crd := &extv1.CustomResourceDefinition{}
+	gvk := extv1.SchemeGroupVersion.WithKind("CustomResourceDefinition")
+	_, _, err = decode(data, &gvk, crd)
+	if err != nil {
+		return errors.Wrap(err, "Decode Error")
+	}
+
+	xrd, err := convertCRDToXRD(crd, c.DefaultComposition)
+	if err != nil {
+		return errors.Wrap(err, "Cannot convert to CompositeResourceDefinition")
+	}
+
+	return io.WriteObjectYAML(c.fs, c.OutputFile, xrd)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compositeresourcedefinition
+
+import (
+	"encoding/json"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+const (
+	errNilCRD           = "CustomResourceDefinition is nil"
+	errFmtMarshalSchema = "cannot marshal schema of version %q"
+	errFmtNoSchema      = "version %q has no schema"
+)
+
+// compositePrefix is prepended to the names of a namespaced
+// CustomResourceDefinition to name the composite resources of its claims.
+const compositePrefix = "x"
+
+// convertCRDToXRD converts a CustomResourceDefinition to a
+// CompositeResourceDefinition that adopts it. Crossplane adopts an existing
+// CustomResourceDefinition that no other controller controls when it renders
+// one with the same name, so the existing custom resources become composite
+// resources, or claims, without being recreated.
+//
+// A cluster scoped CustomResourceDefinition becomes the composite resource
+// CustomResourceDefinition. A namespaced CustomResourceDefinition becomes the
+// claim CustomResourceDefinition, and its composite resources are named by
+// prefixing its names with "x".
+func convertCRDToXRD(crd *extv1.CustomResourceDefinition, defaultComposition string) (*v1.CompositeResourceDefinition, error) {
+	if crd == nil {
+		return nil, errors.New(errNilCRD)
+	}
+
+	xrd := &v1.CompositeResourceDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1.SchemeGroupVersion.String(),
+			Kind:       v1.CompositeResourceDefinitionKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: crd.GetName(),
+		},
+		Spec: v1.CompositeResourceDefinitionSpec{
+			Group:      crd.Spec.Group,
+			Names:      crd.Spec.Names,
+			Conversion: crd.Spec.Conversion,
+			Versions:   make([]v1.CompositeResourceDefinitionVersion, len(crd.Spec.Versions)),
+		},
+	}
+
+	if crd.Spec.Scope == extv1.NamespaceScoped {
+		names := crd.Spec.Names
+		xrd.Spec.ClaimNames = &names
+		xrd.Spec.Names = compositeNames(names)
+		xrd.SetName(xrd.Spec.Names.Plural + "." + xrd.Spec.Group)
+	}
+
+	if defaultComposition != "" {
+		xrd.Spec.DefaultCompositionRef = &v1.CompositionReference{Name: defaultComposition}
+	}
+
+	for i, cv := range crd.Spec.Versions {
+		if cv.Schema == nil || cv.Schema.OpenAPIV3Schema == nil {
+			return nil, errors.Errorf(errFmtNoSchema, cv.Name)
+		}
+		raw, err := json.Marshal(cv.Schema.OpenAPIV3Schema)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtMarshalSchema, cv.Name)
+		}
+		xrd.Spec.Versions[i] = v1.CompositeResourceDefinitionVersion{
+			Name:                     cv.Name,
+			Referenceable:            cv.Storage,
+			Served:                   cv.Served,
+			DeprecationWarning:       cv.DeprecationWarning,
+			Schema:                   &v1.CompositeResourceValidation{OpenAPIV3Schema: runtime.RawExtension{Raw: raw}},
+			AdditionalPrinterColumns: cv.AdditionalPrinterColumns,
+		}
+		if cv.Deprecated {
+			xrd.Spec.Versions[i].Deprecated = ptr.To(true)
+		}
+	}
+
+	return xrd, nil
+}
+
+// compositeNames derives the names of the composite resources of a claim from
+// the claim's names. Short names and categories aren't derived, because they'd
+// be ambiguous.
+func compositeNames(claim extv1.CustomResourceDefinitionNames) extv1.CustomResourceDefinitionNames {
+	n := extv1.CustomResourceDefinitionNames{
+		Plural: compositePrefix + claim.Plural,
+		Kind:   "X" + claim.Kind,
+	}
+	if claim.Singular != "" {
+		n.Singular = compositePrefix + claim.Singular
+	}
+	if claim.ListKind != "" {
+		n.ListKind = "X" + claim.ListKind
+	}
+	return n
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compositeresourcedefinition
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestConvertCRDToXRD(t *testing.T) {
+	schema := &extv1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]extv1.JSONSchemaProps{
+			"spec": {
+				Type:       "object",
+				Properties: map[string]extv1.JSONSchemaProps{"size": {Type: "string"}},
+			},
+		},
+	}
+	rawSchema := runtime.RawExtension{Raw: []byte(`{"type":"object","properties":{"spec":{"type":"object","properties":{"size":{"type":"string"}}}}}`)}
+
+	versions := []extv1.CustomResourceDefinitionVersion{
+		{
+			Name:    "v1",
+			Served:  true,
+			Storage: true,
+			Schema:  &extv1.CustomResourceValidation{OpenAPIV3Schema: schema},
+		},
+		{
+			Name:               "v1beta1",
+			Served:             true,
+			Deprecated:         true,
+			DeprecationWarning: ptr.To("use v1"),
+			Schema:             &extv1.CustomResourceValidation{OpenAPIV3Schema: schema},
+		},
+	}
+	wantVersions := []v1.CompositeResourceDefinitionVersion{
+		{
+			Name:          "v1",
+			Served:        true,
+			Referenceable: true,
+			Schema:        &v1.CompositeResourceValidation{OpenAPIV3Schema: rawSchema},
+		},
+		{
+			Name:               "v1beta1",
+			Served:             true,
+			Deprecated:         ptr.To(true),
+			DeprecationWarning: ptr.To("use v1"),
+			Schema:             &v1.CompositeResourceValidation{OpenAPIV3Schema: rawSchema},
+		},
+	}
+	names := extv1.CustomResourceDefinitionNames{
+		Plural:     "databases",
+		Singular:   "database",
+		Kind:       "Database",
+		ListKind:   "DatabaseList",
+		ShortNames: []string{"db"},
+	}
+	typeMeta := metav1.TypeMeta{
+		APIVersion: v1.SchemeGroupVersion.String(),
+		Kind:       v1.CompositeResourceDefinitionKind,
+	}
+
+	type args struct {
+		crd                *extv1.CustomResourceDefinition
+		defaultComposition string
+	}
+	type want struct {
+		xrd *v1.CompositeResourceDefinition
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NilCRD": {
+			reason: "We should return an error if the CustomResourceDefinition is nil.",
+			args:   args{},
+			want: want{
+				err: errors.New(errNilCRD),
+			},
+		},
+		"ClusterScoped": {
+			reason: "A cluster scoped CustomResourceDefinition should become a composite resource type with the same names.",
+			args: args{
+				crd: &extv1.CustomResourceDefinition{
+					ObjectMeta: metav1.ObjectMeta{Name: "databases.example.org"},
+					Spec: extv1.CustomResourceDefinitionSpec{
+						Group:    "example.org",
+						Names:    names,
+						Scope:    extv1.ClusterScoped,
+						Versions: versions,
+					},
+				},
+			},
+			want: want{
+				xrd: &v1.CompositeResourceDefinition{
+					TypeMeta:   typeMeta,
+					ObjectMeta: metav1.ObjectMeta{Name: "databases.example.org"},
+					Spec: v1.CompositeResourceDefinitionSpec{
+						Group:    "example.org",
+						Names:    names,
+						Versions: wantVersions,
+					},
+				},
+			},
+		},
+		"Namespaced": {
+			reason: "A namespaced CustomResourceDefinition should become a claim type, with a prefixed composite resource type.",
+			args: args{
+				crd: &extv1.CustomResourceDefinition{
+					ObjectMeta: metav1.ObjectMeta{Name: "databases.example.org"},
+					Spec: extv1.CustomResourceDefinitionSpec{
+						Group:    "example.org",
+						Names:    names,
+						Scope:    extv1.NamespaceScoped,
+						Versions: versions,
+					},
+				},
+				defaultComposition: "databases",
+			},
+			want: want{
+				xrd: &v1.CompositeResourceDefinition{
+					TypeMeta:   typeMeta,
+					ObjectMeta: metav1.ObjectMeta{Name: "xdatabases.example.org"},
+					Spec: v1.CompositeResourceDefinitionSpec{
+						Group: "example.org",
+						Names: extv1.CustomResourceDefinitionNames{
+							Plural:   "xdatabases",
+							Singular: "xdatabase",
+							Kind:     "XDatabase",
+							ListKind: "XDatabaseList",
+						},
+						ClaimNames:            &names,
+						DefaultCompositionRef: &v1.CompositionReference{Name: "databases"},
+						Versions:              wantVersions,
+					},
+				},
+			},
+		},
+		"NoSchema": {
+			reason: "We should return an error if a version has no schema.",
+			args: args{
+				crd: &extv1.CustomResourceDefinition{
+					Spec: extv1.CustomResourceDefinitionSpec{
+						Versions: []extv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+					},
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtNoSchema, "v1"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			xrd, err := convertCRDToXRD(tc.args.crd, tc.args.defaultComposition)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nconvertCRDToXRD(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.xrd, xrd); diff != "" {
+				t.Errorf("\n%s\nconvertCRDToXRD(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
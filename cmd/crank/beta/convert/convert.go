@@ -19,6 +19,7 @@ limitations under the License.
 package convert
 
 import (
+	"github.com/crossplane/crossplane/cmd/crank/beta/convert/compositeresourcedefinition"
 	"github.com/crossplane/crossplane/cmd/crank/beta/convert/deploymentruntime"
 	"github.com/crossplane/crossplane/cmd/crank/beta/convert/pipelinecomposition"
 )
@@ -27,6 +28,8 @@ import (
 type Cmd struct {
 	DeploymentRuntime   deploymentruntime.Cmd   `cmd:"" help:"Convert a ControllerConfig to a DeploymentRuntimeConfig."`
 	PipelineComposition pipelinecomposition.Cmd `cmd:"" help:"Convert a Patch-and-Transform Composition to a Function Pipeline Composition."`
+
+	CompositeResourceDefinition compositeresourcedefinition.Cmd `cmd:"" help:"Convert a CustomResourceDefinition to a CompositeResourceDefinition that adopts it."`
 }
 
 // Help returns help message for the migrate command.
@@ -37,6 +40,7 @@ This command converts a Crossplane resource to a newer version or a different ki
 Currently supported conversions:
 * ControllerConfig -> DeploymentRuntimeConfig
 * Classic Compositions -> Function Pipeline Compositions
+* CustomResourceDefinition -> CompositeResourceDefinition
 
 Examples:
   # Write out a DeploymentRuntimeConfigFile from a ControllerConfig
@@ -45,5 +49,8 @@ Examples:
   # Convert an existing Composition to use Pipelines
   crossplane beta convert pipeline-composition composition.yaml -o pipeline-composition.yaml
 
+  # Adopt a CustomResourceDefinition and its custom resources using an XRD
+  crossplane beta convert composite-resource-definition crd.yaml -o xrd.yaml
+
 `
 }
@@ -246,6 +246,9 @@ func migrateEnvPatches(envPatches []v1.EnvironmentPatch) []EnvironmentPatch {
 
 func migratePatchPolicy(policy *v1.PatchPolicy) *PatchPolicy {
 	to := migrateMergeOptions(policy.MergeOptions)
+	if policy.ToFieldPath != nil {
+		to = ptr.To(ToFieldPathPolicy(*policy.ToFieldPath))
+	}
 
 	if to == nil && policy.FromFieldPath == nil {
 		// neither To nor From has been set, just return nil to use defaults for
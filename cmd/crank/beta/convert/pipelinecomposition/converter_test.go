@@ -1394,6 +1394,15 @@ func TestPatchPolicy(t *testing.T) {
 				ToFieldPath:   ptr.To(ToFieldPathPolicyMergeObjectsAppendArrays),
 			},
 		},
+		"PatchPolicyWithToFieldPath": {
+			reason: "ToFieldPath is used as is",
+			args: &v1.PatchPolicy{
+				ToFieldPath: ptr.To(v1.ToFieldPathPolicyForceMergeObjectsAppendArrays),
+			},
+			want: &PatchPolicy{
+				ToFieldPath: ptr.To(ToFieldPathPolicyForceMergeObjectsAppendArrays),
+			},
+		},
 	}
 
 	for name, tc := range cases {
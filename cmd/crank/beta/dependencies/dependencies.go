@@ -0,0 +1,197 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dependencies contains the dependencies command.
+package dependencies
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+const (
+	errKubeConfig     = "failed to get kubeconfig"
+	errInitKubeClient = "cannot init kubeclient"
+	errGetLock        = "cannot get package lock"
+	errWriteOutput    = "cannot write output"
+
+	errFmtNotInLock = "package %s is not in the package lock"
+)
+
+// lockName is the name of the package manager's lock.
+const lockName = "lock"
+
+// Cmd explains why a package is installed.
+type Cmd struct {
+	Package string `arg:"" help:"Source of the package, without a tag or digest, e.g. xpkg.upbound.io/crossplane-contrib/provider-aws."`
+
+	Context string `default:"" help:"Kubernetes context." name:"context" short:"c"`
+}
+
+// Help returns help message for the dependencies command.
+func (c *Cmd) Help() string {
+	return `
+This command explains why a package is installed, similar to 'go mod why'. It
+reads the package manager's lock, and prints the shortest chain of
+dependencies from each package that no other package depends on - i.e. each
+package that was installed directly - to the supplied package. Each
+dependency is printed with the constraints that caused it to be installed.
+
+Examples:
+  # Explain why provider-aws is installed.
+  crossplane beta dependencies xpkg.upbound.io/crossplane-contrib/provider-aws
+`
+}
+
+// Run runs the dependencies command.
+func (c *Cmd) Run(k *kong.Context, logger logging.Logger) error {
+	ctx := context.Background()
+	logger = logger.WithValues("Package", c.Package)
+
+	kubeconfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(),
+		&clientcmd.ConfigOverrides{CurrentContext: c.Context},
+	).ClientConfig()
+	if err != nil {
+		return errors.Wrap(err, errKubeConfig)
+	}
+
+	s := runtime.NewScheme()
+	_ = v1beta1.AddToScheme(s)
+	kube, err := client.New(kubeconfig, client.Options{Scheme: s})
+	if err != nil {
+		return errors.Wrap(err, errInitKubeClient)
+	}
+
+	l := &v1beta1.Lock{}
+	if err := kube.Get(ctx, types.NamespacedName{Name: lockName}, l); err != nil {
+		return errors.Wrap(err, errGetLock)
+	}
+	logger.Debug("Found package lock", "packages", len(l.Packages))
+
+	chains, err := Why(l, c.Package)
+	if err != nil {
+		return err
+	}
+	return errors.Wrap(Print(k.Stdout, c.Package, chains), errWriteOutput)
+}
+
+// A Step in a chain of dependencies.
+type Step struct {
+	// Source of the package.
+	Source string
+
+	// Constraints the previous package in the chain placed on this package.
+	// Empty for the first package in a chain.
+	Constraints string
+}
+
+// Why returns the shortest chain of dependencies from each package that no
+// other package depends on to the package with the supplied source. If no
+// other package depends on the supplied package, the only chain returned
+// consists of the package itself.
+func Why(l *v1beta1.Lock, source string) ([][]Step, error) {
+	if _, ok := l.GetPackage(source); !ok {
+		return nil, errors.Errorf(errFmtNotInLock, source)
+	}
+
+	// Walk the lock breadth first from the supplied package to the packages
+	// that depend on it, recording the next step toward the supplied package
+	// from each package we find. Breadth first means we find the shortest
+	// chain to each package first.
+	next := map[string]Step{}
+	var roots []string
+	queue := []string{source}
+	seen := map[string]bool{source: true}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		deps := l.Dependents(cur)
+		if len(deps) == 0 {
+			roots = append(roots, cur)
+			continue
+		}
+		for _, d := range deps {
+			src := d.Package.Source
+			if seen[src] {
+				continue
+			}
+			seen[src] = true
+			next[src] = Step{Source: cur, Constraints: constraints(d.Dependency, cur)}
+			queue = append(queue, src)
+		}
+	}
+
+	chains := make([][]Step, 0, len(roots))
+	for _, r := range roots {
+		chain := []Step{{Source: r}}
+		for s := r; s != source; {
+			step := next[s]
+			chain = append(chain, step)
+			s = step.Source
+		}
+		chains = append(chains, chain)
+	}
+	return chains, nil
+}
+
+// constraints returns the constraints the supplied dependency places on the
+// supplied candidate package.
+func constraints(d v1beta1.Dependency, source string) string {
+	if d.Channel != "" {
+		return "channel " + d.Channel
+	}
+	for _, c := range d.Candidates() {
+		if c.Package == source {
+			return c.Constraints
+		}
+	}
+	return ""
+}
+
+// Print writes the supplied chains of dependencies to the supplied writer as
+// trees.
+func Print(w io.Writer, source string, chains [][]Step) error {
+	if len(chains) == 1 && len(chains[0]) == 1 {
+		_, err := fmt.Fprintf(w, "%s is not required by any other package. It was installed directly.\n", source)
+		return err
+	}
+
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "%s is required by:\n", source)
+	for _, chain := range chains {
+		fmt.Fprintf(b, "\n%s\n", chain[0].Source)
+		for i, s := range chain[1:] {
+			fmt.Fprintf(b, "%s└─ %s (%s)\n", strings.Repeat("   ", i), s.Source, s.Constraints)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
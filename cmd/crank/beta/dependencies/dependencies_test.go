@@ -0,0 +1,173 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dependencies
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+func TestWhy(t *testing.T) {
+	// platform -> networking -> provider-aws
+	// platform -> provider-aws
+	// database -> provider-aws (as an alternative to provider-upjet-aws)
+	lock := &v1beta1.Lock{
+		Packages: []v1beta1.LockPackage{
+			{
+				Source: "acme/platform",
+				Dependencies: []v1beta1.Dependency{
+					{Package: "acme/networking", Constraints: ">=v1.0.0"},
+					{Package: "acme/provider-aws", Constraints: ">=v0.40.0"},
+				},
+			},
+			{
+				Source: "acme/networking",
+				Dependencies: []v1beta1.Dependency{
+					{Package: "acme/provider-aws", Constraints: ">=v0.38.0"},
+				},
+			},
+			{
+				Source: "acme/database",
+				Dependencies: []v1beta1.Dependency{
+					{
+						Package:      "acme/provider-upjet-aws",
+						Constraints:  ">=v1.0.0",
+						Alternatives: []v1beta1.DependencyAlternative{{Package: "acme/provider-aws", Constraints: ">=v0.45.0"}},
+					},
+				},
+			},
+			{Source: "acme/provider-aws"},
+		},
+	}
+
+	type want struct {
+		chains [][]Step
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		source string
+		want   want
+	}{
+		"NotInLock": {
+			reason: "We should return an error if the package isn't in the lock.",
+			source: "acme/nope",
+			want: want{
+				err: errors.Errorf(errFmtNotInLock, "acme/nope"),
+			},
+		},
+		"InstalledDirectly": {
+			reason: "A package that no other package depends on should be its own only chain.",
+			source: "acme/platform",
+			want: want{
+				chains: [][]Step{{{Source: "acme/platform"}}},
+			},
+		},
+		"Transitive": {
+			reason: "We should return the shortest chain from every package that was installed directly.",
+			source: "acme/provider-aws",
+			want: want{
+				chains: [][]Step{
+					{{Source: "acme/platform"}, {Source: "acme/provider-aws", Constraints: ">=v0.40.0"}},
+					{{Source: "acme/database"}, {Source: "acme/provider-aws", Constraints: ">=v0.45.0"}},
+				},
+			},
+		},
+		"Intermediate": {
+			reason: "We should return chains for packages that are both depended on and depend on others.",
+			source: "acme/networking",
+			want: want{
+				chains: [][]Step{
+					{{Source: "acme/platform"}, {Source: "acme/networking", Constraints: ">=v1.0.0"}},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			chains, err := Why(lock, tc.source)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nWhy(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.chains, chains); diff != "" {
+				t.Errorf("\n%s\nWhy(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPrint(t *testing.T) {
+	type args struct {
+		source string
+		chains [][]Step
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   string
+	}{
+		"InstalledDirectly": {
+			reason: "We should explain that a package no other package depends on was installed directly.",
+			args: args{
+				source: "acme/platform",
+				chains: [][]Step{{{Source: "acme/platform"}}},
+			},
+			want: "acme/platform is not required by any other package. It was installed directly.\n",
+		},
+		"Chains": {
+			reason: "We should print each chain as a tree.",
+			args: args{
+				source: "acme/provider-aws",
+				chains: [][]Step{
+					{{Source: "acme/platform"}, {Source: "acme/networking", Constraints: ">=v1.0.0"}, {Source: "acme/provider-aws", Constraints: ">=v0.38.0"}},
+					{{Source: "acme/database"}, {Source: "acme/provider-aws", Constraints: ">=v0.45.0"}},
+				},
+			},
+			want: `acme/provider-aws is required by:
+
+acme/platform
+└─ acme/networking (>=v1.0.0)
+   └─ acme/provider-aws (>=v0.38.0)
+
+acme/database
+└─ acme/provider-aws (>=v0.45.0)
+`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			b := &strings.Builder{}
+			if err := Print(b, tc.args.source, tc.args.chains); err != nil {
+				t.Fatalf("Print(...): %s", err)
+			}
+			if diff := cmp.Diff(tc.want, b.String()); diff != "" {
+				t.Errorf("\n%s\nPrint(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
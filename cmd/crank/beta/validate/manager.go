@@ -31,7 +31,9 @@ import (
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	metav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
 	"github.com/crossplane/crossplane/internal/xcrd"
+	"github.com/crossplane/crossplane/internal/xpkg"
 )
 
 const (
@@ -210,22 +212,22 @@ func (m *Manager) addDependencies(confs map[string]*metav1.Configuration) error
 
 		deps := cfg.Spec.MetaSpec.DependsOn
 		for _, dep := range deps {
-			image := ""
-			if dep.Configuration != nil { //nolint:gocritic // switch is not suitable here
-				image = *dep.Configuration
-			} else if dep.Provider != nil {
-				image = *dep.Provider
-			} else if dep.Function != nil {
-				image = *dep.Function
+			// Dependencies on an API can't be resolved to an image.
+			if dep.API != nil {
+				continue
 			}
-			if len(image) > 0 {
-				image = fmt.Sprintf(imageFmt, image, dep.Version)
-				m.deps[image] = true
+			// Dependencies with alternatives are validated against the
+			// first alternative.
+			ld, err := xpkg.LockDependency(dep)
+			if err != nil {
+				return errors.Wrapf(err, "cannot determine dependency of package %s", image)
+			}
+			depImage := fmt.Sprintf(imageFmt, ld.Package, ld.Constraints)
+			m.deps[depImage] = true
 
-				if _, ok := m.confs[image]; !ok && dep.Configuration != nil {
-					deepConfs[image] = nil
-					m.confs[image] = nil
-				}
+			if _, ok := m.confs[depImage]; !ok && ld.Type == v1beta1.ConfigurationPackageType {
+				deepConfs[depImage] = nil
+				m.confs[depImage] = nil
 			}
 		}
 	}
@@ -528,6 +528,14 @@ func TestConvertToCRDs(t *testing.T) {
 																},
 															},
 														},
+														"queuePosition": {
+															Description: "Position of the resource in the queue of resources waiting to be composed.",
+															Type:        "integer",
+														},
+														"estimatedWait": {
+															Description: "How long the resource is estimated to wait before it is composed.",
+															Type:        "string",
+														},
 														"claimConditionTypes": {
 															Type:      "array",
 															XListType: ptr.To("set"),
@@ -537,6 +545,22 @@ func TestConvertToCRDs(t *testing.T) {
 																},
 															},
 														},
+														"compositionRef": {
+															Description: "The composition that was last used to compose the resource.",
+															Type:        "object",
+															Required:    []string{"name"},
+															Properties: map[string]extv1.JSONSchemaProps{
+																"name": {Type: "string"},
+															},
+														},
+														"compositionRevisionRef": {
+															Description: "The composition revision that was last used to compose the resource.",
+															Type:        "object",
+															Required:    []string{"name"},
+															Properties: map[string]extv1.JSONSchemaProps{
+																"name": {Type: "string"},
+															},
+														},
 														"connectionDetails": {
 															Type: "object",
 															Properties: map[string]extv1.JSONSchemaProps{
@@ -880,6 +904,14 @@ func TestConvertToCRDs(t *testing.T) {
 																},
 															},
 														},
+														"queuePosition": {
+															Description: "Position of the resource in the queue of resources waiting to be composed.",
+															Type:        "integer",
+														},
+														"estimatedWait": {
+															Description: "How long the resource is estimated to wait before it is composed.",
+															Type:        "string",
+														},
 														"claimConditionTypes": {
 															Type:      "array",
 															XListType: ptr.To("set"),
@@ -889,6 +921,22 @@ func TestConvertToCRDs(t *testing.T) {
 																},
 															},
 														},
+														"compositionRef": {
+															Description: "The composition that was last used to compose the resource.",
+															Type:        "object",
+															Required:    []string{"name"},
+															Properties: map[string]extv1.JSONSchemaProps{
+																"name": {Type: "string"},
+															},
+														},
+														"compositionRevisionRef": {
+															Description: "The composition revision that was last used to compose the resource.",
+															Type:        "object",
+															Required:    []string{"name"},
+															Properties: map[string]extv1.JSONSchemaProps{
+																"name": {Type: "string"},
+															},
+														},
 														"connectionDetails": {
 															Type: "object",
 															Properties: map[string]extv1.JSONSchemaProps{
@@ -1133,6 +1181,14 @@ func TestConvertToCRDs(t *testing.T) {
 																},
 															},
 														},
+														"queuePosition": {
+															Description: "Position of the resource in the queue of resources waiting to be composed.",
+															Type:        "integer",
+														},
+														"estimatedWait": {
+															Description: "How long the resource is estimated to wait before it is composed.",
+															Type:        "string",
+														},
 														"claimConditionTypes": {
 															Type:      "array",
 															XListType: ptr.To("set"),
@@ -1142,6 +1198,22 @@ func TestConvertToCRDs(t *testing.T) {
 																},
 															},
 														},
+														"compositionRef": {
+															Description: "The composition that was last used to compose the resource.",
+															Type:        "object",
+															Required:    []string{"name"},
+															Properties: map[string]extv1.JSONSchemaProps{
+																"name": {Type: "string"},
+															},
+														},
+														"compositionRevisionRef": {
+															Description: "The composition revision that was last used to compose the resource.",
+															Type:        "object",
+															Required:    []string{"name"},
+															Properties: map[string]extv1.JSONSchemaProps{
+																"name": {Type: "string"},
+															},
+														},
 														"connectionDetails": {
 															Type: "object",
 															Properties: map[string]extv1.JSONSchemaProps{
@@ -32,6 +32,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/parser"
 
+	"github.com/crossplane/crossplane/internal/version"
 	"github.com/crossplane/crossplane/internal/xpkg"
 	"github.com/crossplane/crossplane/internal/xpkg/parser/examples"
 	"github.com/crossplane/crossplane/internal/xpkg/parser/yaml"
@@ -94,12 +95,13 @@ func (c *buildCmd) AfterApply() error {
 // buildCmd builds a crossplane package.
 type buildCmd struct {
 	// Flags. Keep sorted alphabetically.
-	EmbedRuntimeImage        string   `help:"An OCI image to embed in the package as its runtime."                                                                                                    placeholder:"NAME"                                                     xor:"runtime-image"`
-	EmbedRuntimeImageTarball string   `help:"An OCI image tarball to embed in the package as its runtime."                                                                                            placeholder:"PATH"                                                     type:"existingfile" xor:"runtime-image"`
-	ExamplesRoot             string   `default:"./examples"                                                                                                                                           help:"A directory of example YAML files to include in the package."    short:"e"           type:"path"`
-	Ignore                   []string `help:"Comma-separated file paths, specified relative to --package-root, to exclude from the package. Wildcards are supported. Directories cannot be excluded." placeholder:"PATH"`
-	PackageFile              string   `help:"The file to write the package to. Defaults to a generated filename in --package-root."                                                                   placeholder:"PATH"                                                     short:"o"           type:"path"`
-	PackageRoot              string   `default:"."                                                                                                                                                    help:"The directory that contains the package's crossplane.yaml file." short:"f"           type:"existingdir"`
+	EmbedRuntimeImage        string            `help:"An OCI image to embed in the package as its runtime."                                                                                                    placeholder:"NAME"                                                     xor:"runtime-image"`
+	EmbedRuntimeImageTarball string            `help:"An OCI image tarball to embed in the package as its runtime."                                                                                            placeholder:"PATH"                                                     type:"existingfile" xor:"runtime-image"`
+	ExamplesRoot             string            `default:"./examples"                                                                                                                                           help:"A directory of example YAML files to include in the package."    short:"e"           type:"path"`
+	Ignore                   []string          `help:"Comma-separated file paths, specified relative to --package-root, to exclude from the package. Wildcards are supported. Directories cannot be excluded." placeholder:"PATH"`
+	Label                    map[string]string `help:"Labels to add to the package image config, e.g. org.opencontainers.image.revision=<commit>." placeholder:"KEY=VALUE"`
+	PackageFile              string            `help:"The file to write the package to. Defaults to a generated filename in --package-root."                                                                   placeholder:"PATH"                                                     short:"o"           type:"path"`
+	PackageRoot              string            `default:"."                                                                                                                                                    help:"The directory that contains the package's crossplane.yaml file." short:"f"           type:"existingdir"`
 
 	// Internal state. These aren't part of the user-exposed CLI structure.
 	fs      afero.Fs
@@ -120,6 +122,10 @@ Examples:
   # 'docker build' so that the package can also be used to run the provider.
   # Provider and Function packages support embedding runtime images.
   crossplane xpkg build --embed-runtime-image=cc873e13cdc1
+
+  # Build a package that records the git commit it was built from. The
+  # package manager shows it in the status of the package's revisions.
+  crossplane xpkg build --label=org.opencontainers.image.revision=$(git rev-parse HEAD)
 `
 }
 
@@ -166,7 +172,11 @@ func (c *buildCmd) GetOutputFileName(meta runtime.Object, hash v1.Hash) (string,
 
 // Run executes the build command.
 func (c *buildCmd) Run(logger logging.Logger) error {
-	var buildOpts []xpkg.BuildOpt
+	labels := map[string]string{xpkg.LabelBuilderVersion: version.New().GetVersionString()}
+	for k, v := range c.Label {
+		labels[k] = v
+	}
+	buildOpts := []xpkg.BuildOpt{xpkg.WithLabels(labels)}
 	rtBuildOpts, err := c.GetRuntimeBaseImageOpts()
 	if err != nil {
 		return errors.Wrap(err, errGetRuntimeBaseImageOpts)
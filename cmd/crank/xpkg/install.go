@@ -28,7 +28,6 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/util/wait"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -39,6 +38,7 @@ import (
 	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
 	"github.com/crossplane/crossplane/internal/version"
 	"github.com/crossplane/crossplane/internal/xpkg"
+	"github.com/crossplane/crossplane/pkg/xclient"
 
 	// Load all the auth plugins for the cloud providers.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
@@ -179,27 +179,10 @@ func (c *installCmd) Run(k *kong.Context, logger logging.Logger) error {
 	if c.Wait > 0 {
 		// Poll every 2 seconds to see whether the package is ready.
 		logger.Debug("Waiting for package to be ready", "timeout", timeout)
-		go wait.UntilWithContext(ctx, func(ctx context.Context) {
-			if err := kube.Get(ctx, client.ObjectKeyFromObject(pkg), pkg); err != nil {
-				logger.Debug("Cannot get package", "error", err)
-				return
-			}
-
-			// Our package is ready, cancel the context to stop our wait loop.
-			if pkg.GetCondition(v1.TypeHealthy).Status == corev1.ConditionTrue {
-				logger.Debug("Package is ready")
-				cancel()
-				return
-			}
-
-			logger.Debug("Package is not yet ready")
-		}, 2*time.Second)
-
-		<-ctx.Done()
-
-		if err := ctx.Err(); errors.Is(err, context.DeadlineExceeded) {
+		if err := xclient.WaitForPackageHealthy(ctx, kube, pkg, 2*time.Second); err != nil {
 			return errors.Wrap(err, "Package did not become ready")
 		}
+		logger.Debug("Package is ready")
 	}
 
 	_, err = fmt.Fprintf(k.Stdout, "%s/%s created\n", c.Kind, pkg.GetName())
@@ -27,9 +27,13 @@ import (
 	"time"
 
 	"github.com/alecthomas/kong"
+	conregv1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/spf13/afero"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/client-go/rest"
 	kcache "k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
@@ -38,6 +42,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/crossplane/crossplane-runtime/pkg/certificates"
@@ -52,18 +57,35 @@ import (
 	apiextensionscontroller "github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
 	"github.com/crossplane/crossplane/internal/controller/pkg"
 	pkgcontroller "github.com/crossplane/crossplane/internal/controller/pkg/controller"
+	"github.com/crossplane/crossplane/internal/dependents"
 	"github.com/crossplane/crossplane/internal/engine"
 	"github.com/crossplane/crossplane/internal/features"
+	"github.com/crossplane/crossplane/internal/immutable"
 	"github.com/crossplane/crossplane/internal/initializer"
 	"github.com/crossplane/crossplane/internal/metrics"
+	"github.com/crossplane/crossplane/internal/notify"
+	"github.com/crossplane/crossplane/internal/plan"
+	"github.com/crossplane/crossplane/internal/quota"
 	"github.com/crossplane/crossplane/internal/transport"
 	"github.com/crossplane/crossplane/internal/usage"
+	"github.com/crossplane/crossplane/internal/useragent"
 	"github.com/crossplane/crossplane/internal/validation/apiextensions/v1/composition"
 	"github.com/crossplane/crossplane/internal/validation/apiextensions/v1/xrd"
+	"github.com/crossplane/crossplane/internal/xcrd"
 	"github.com/crossplane/crossplane/internal/xfn"
 	"github.com/crossplane/crossplane/internal/xpkg"
 )
 
+// Intervals used in dev mode. Dev mode tunes Crossplane's own controllers,
+// which are what make local development loops slow. Package runtimes, i.e.
+// providers and functions, are already configured using
+// DeploymentRuntimeConfigs, so dev mode doesn't touch them.
+const (
+	devSyncInterval    = 1 * time.Minute
+	devPollInterval    = 10 * time.Second
+	devMaxRequeueDelay = 5 * time.Second
+)
+
 // Command runs the core crossplane controllers.
 type Command struct {
 	Start startCommand `cmd:"" help:"Start Crossplane controllers."`
@@ -95,25 +117,70 @@ type startCommand struct {
 	CABundlePath   string `env:"CA_BUNDLE_PATH"            help:"Additional CA bundle to use when fetching packages from registry."`
 	UserAgent      string `default:"${default_user_agent}" env:"USER_AGENT"                                                         help:"The User-Agent header that will be set on all package requests."`
 
-	PackageRuntime string `default:"Deployment" env:"PACKAGE_RUNTIME" help:"The package runtime to use for packages with a runtime (e.g. Providers and Functions)"`
+	RemoteCacheRegistry string `env:"REMOTE_CACHE_REGISTRY" help:"A pull-through registry cache used to fetch packages before falling back to their upstream registry." placeholder:"host[:port][/path]"`
+	ImageCacheMaxSize   string `env:"IMAGE_CACHE_MAX_SIZE"  help:"The maximum size of the on-disk cache of package images, for example 1Gi. Images are cached by digest in the cache directory and the least recently used images are evicted. Disabled if unset." placeholder:"quantity"`
+	TunablesFile        string `env:"TUNABLES_FILE"         help:"A YAML file, for example a mounted ConfigMap, that overrides --image-cache-max-size, --max-concurrent-package-pulls, --max-package-pull-rate, --package-pull-burst, and --remote-cache-registry. It's reloaded when it changes or Crossplane receives a SIGHUP, without restarting Crossplane." placeholder:"path"`
+	PackageImageDir     string `env:"PACKAGE_IMAGE_DIR"     help:"A directory, e.g. a pre-loaded volume, from which packages may load their image tarball using spec.imageSource.path. Loading packages from paths is disabled if unset."`
+	BootstrapDir        string `env:"BOOTSTRAP_DIR"         help:"A directory, for example a mounted ConfigMap, of Packages, StoreConfigs, and DeploymentRuntimeConfigs to apply at startup. They're retried until they're all applied and every package is healthy." placeholder:"path"`
+
+	RegistryClientCertSecretName string `env:"REGISTRY_CLIENT_CERT_SECRET_NAME" help:"The name of a kubernetes.io/tls Secret in Crossplane's namespace containing a client certificate to present to package registries that require mutual TLS."`
+
+	RegistryCredentialHelpers map[string]string `env:"REGISTRY_CREDENTIAL_HELPERS" help:"Docker credential helpers used to get credentials for package registries, for example registry.example.org=example. ECR, GCR, Artifact Registry, and ACR credentials are resolved from workload identity without a helper. The docker-credential-<helper> binary must be on the PATH, and must finish within 30 seconds." placeholder:"registry=helper"`
+
+	PackageRuntime          string   `default:"Deployment"              env:"PACKAGE_RUNTIME"           help:"The package runtime to use for packages with a runtime (e.g. Providers and Functions)"`
+	PackageRuntimePlatforms []string `env:"PACKAGE_RUNTIME_PLATFORMS" help:"Platforms that package runtime pods may be scheduled to, for example linux/amd64. Ignored for packages whose runtime config sets node affinity." placeholder:"os[/arch]"`
+	PackagePlatform         string   `env:"PACKAGE_PLATFORM"          help:"Platform whose variant of multi-platform package images to pull, for example linux/arm64. Defaults to the platform Crossplane is running on. Package runtime pods are scheduled to nodes of this platform unless --package-runtime-platforms is set." placeholder:"os/arch[/variant]"`
 
 	SyncInterval                     time.Duration `default:"1h"  help:"How often all resources will be double-checked for drift from the desired state."                      short:"s"`
 	PollInterval                     time.Duration `default:"1m"  help:"How often individual resources will be checked for drift from the desired state."`
 	MaxReconcileRate                 int           `default:"100" help:"The global maximum rate per second at which resources may checked for drift from the desired state."`
-	MaxConcurrentPackageEstablishers int           `default:"10"  help:"The the maximum number of goroutines to use for establishing Providers, Configurations and Functions."`
+	MaxConcurrentPackageEstablishers int           `default:"10"  help:"The maximum number of objects, e.g. CRDs, each Provider, Configuration and Function revision may apply at once. Consider raising it when installing providers with hundreds of CRDs."`
+	MaxComposingComposites           int           `default:"0"   help:"The maximum number of composite resources of each kind that may be waiting to become ready at once. Others wait in a fair queue and report their queue position. Zero means no limit."`
+	MaxConcurrentComposedApplies     int           `default:"10"  help:"The maximum number of composed resources each composite resource may apply at once. Composite resources with many composed resources reconcile faster when it's higher, at the cost of a burstier load on the API server."`
+	CompositionRevisionHistoryLimit  int           `default:"0"   help:"The number of old revisions of each Composition to keep. Old revisions that are used by a composite resource are never deleted. Compositions may override this using the apiextensions.crossplane.io/revision-history-limit annotation. Zero means all revisions are kept."`
+	PackageFootprintSampleInterval   time.Duration `default:"0s"  help:"How often to sample how many CRDs, custom resources, and bytes of etcd storage each installed package is responsible for. Zero disables sampling."`
+	ProviderDrainPeriod              time.Duration `default:"0s"  help:"How long an inactive provider revision's Deployment keeps running after the active revision becomes healthy. The Deployment is then scaled to zero rather than deleted."`
+	PackageChannelPollInterval       time.Duration `default:"1h"  help:"How often to check whether the channels, for example stable, that package dependencies track have moved. Dependencies are updated to the version their channel points to. Zero disables updating them."`
+	PackageFetchRetries              int64         `default:"5"   help:"How many times to retry fetching a package image that failed for a transient reason, for example registry rate limiting, before the package revision is considered unhealthy."`
+	PackageFetchBackoff              time.Duration `default:"5s"  help:"How long to wait before first retrying a transient package image fetch failure. The wait doubles with each retry."`
+	MaxConcurrentPackagePulls        int           `default:"0"   help:"The maximum number of requests to package registries that may be in flight at once, across all packages. Zero means no limit."`
+	MaxPackagePullRate               float64       `default:"0"   help:"The maximum rate per second at which requests may be made to package registries, across all packages. Consider setting it when installing many packages from a rate limited registry, like Docker Hub. Zero means no limit."`
+	PackagePullBurst                 int           `default:"10"  help:"How many requests to package registries may be made at once in excess of --max-package-pull-rate."`
+	MaxPackages                      int           `default:"0"   help:"The maximum number of Providers, Configurations, and Functions that may be installed. Creating more is rejected by a webhook. Zero means no limit."`
+	MaxPackageRevisions              int           `default:"0"   help:"The maximum number of Provider, Configuration, and Function revisions that may exist. Creating more, including by upgrading a package, is rejected by a webhook. Zero means no limit."`
+
+	FairClaimQueues       bool           `env:"FAIR_CLAIM_QUEUES"       help:"Dispatch claims to be reconciled round-robin across namespaces, so that a namespace with many claims can't starve the others."`
+	ClaimNamespaceWeights map[string]int `env:"CLAIM_NAMESPACE_WEIGHTS" help:"How many claims from a namespace are dispatched in a row when --fair-claim-queues is set, for example team-a=3. Namespaces that aren't listed have weight one." placeholder:"namespace=weight"`
+
+	StrictConfigurationDependencies bool `env:"STRICT_CONFIGURATION_DEPENDENCIES" help:"Block activation of a Configuration until all of its dependencies are installed and healthy."`
+	RequirePackageDigests           bool `env:"REQUIRE_PACKAGE_DIGESTS"           help:"Reject packages, including dependencies, that are not referenced by digest. Otherwise package tags are resolved to a digest when a revision is created."`
+	RequirePackageSignatures        bool `env:"REQUIRE_PACKAGE_SIGNATURES"        help:"Refuse to activate package revisions whose signature isn't verified by an ImageConfig. Requires --enable-signature-verification."`
+	SkipDependencyResolution        bool `env:"SKIP_DEPENDENCY_RESOLUTION"        help:"Never install or upgrade package dependencies. Packages still check that their dependencies are installed and satisfy their constraints, so each dependency must be installed explicitly."`
+
+	NotificationEndpoints  []string `env:"NOTIFICATION_ENDPOINTS"   help:"HTTP endpoints to post a JSON notification to when a package is installed, upgraded, or fails, and when a CompositeResourceDefinition is established. Disabled if unset." placeholder:"url"`
+	NotificationSigningKey string   `env:"NOTIFICATION_SIGNING_KEY" help:"A key used to sign notifications. Each notification's X-Crossplane-Signature header is the hex encoded HMAC-SHA256 of its body, prefixed with sha256=."`
 
 	WebhookEnabled bool `default:"true" env:"WEBHOOK_ENABLED" help:"Enable webhook configuration."`
 
+	DevMode bool `env:"DEV_MODE" help:"Tune Crossplane for fast local development with a single replica. Disables leader election, shortens the sync and poll intervals, and requeues resources without long backoffs. Overrides --leader-election, --sync-interval and --poll-interval. Not for production use."`
+
+	CompositionForbiddenPatchTargets []string `env:"COMPOSITION_FORBIDDEN_PATCH_TARGETS" help:"Composed resource field paths that Compositions may not patch or set in a resource base, for example spec.forProvider.deletionProtection. Fields nested beneath these paths are also forbidden. Only enforced for Compositions in Resources mode; resources composed by a Function pipeline are not checked."`
+
 	TLSServerSecretName string `env:"TLS_SERVER_SECRET_NAME" help:"The name of the TLS Secret that will store Crossplane's server certificate."`
 	TLSServerCertsDir   string `env:"TLS_SERVER_CERTS_DIR"   help:"The path of the folder which will store TLS server certificate of Crossplane."`
 	TLSClientSecretName string `env:"TLS_CLIENT_SECRET_NAME" help:"The name of the TLS Secret that will be store Crossplane's client certificate."`
 	TLSClientCertsDir   string `env:"TLS_CLIENT_CERTS_DIR"   help:"The path of the folder which will store TLS client certificate of Crossplane."`
 
-	EnableEnvironmentConfigs   bool `group:"Alpha Features:" help:"Enable support for EnvironmentConfigs."`
-	EnableExternalSecretStores bool `group:"Alpha Features:" help:"Enable support for External Secret Stores."`
-	EnableUsages               bool `group:"Alpha Features:" help:"Enable support for deletion ordering and resource protection with Usages."`
-	EnableRealtimeCompositions bool `group:"Alpha Features:" help:"Enable support for realtime compositions, i.e. watching composed resources and reconciling compositions immediately when any of the composed resources is updated."`
-	EnableSSAClaims            bool `group:"Alpha Features:" help:"Enable support for using Kubernetes server-side apply to sync claims with composite resources (XRs)."`
+	EnableEnvironmentConfigs           bool `group:"Alpha Features:" help:"Enable support for EnvironmentConfigs."`
+	EnableExternalSecretStores         bool `group:"Alpha Features:" help:"Enable support for External Secret Stores."`
+	EnableUsages                       bool `group:"Alpha Features:" help:"Enable support for deletion ordering and resource protection with Usages."`
+	EnableRealtimeCompositions         bool `group:"Alpha Features:" help:"Enable support for realtime compositions, i.e. watching composed resources and reconciling compositions immediately when any of the composed resources is updated."`
+	EnableSSAClaims                    bool `group:"Alpha Features:" help:"Enable support for using Kubernetes server-side apply to sync claims with composite resources (XRs)."`
+	EnableSignatureVerification        bool `group:"Alpha Features:" help:"Enable support for verifying the cosign signatures of package images using ImageConfigs."`
+	EnableVulnerabilityScanning        bool `group:"Alpha Features:" help:"Enable support for scanning package images for vulnerabilities using ImageConfigs."`
+	EnableCompositionRevisionOverrides bool `group:"Alpha Features:" help:"Enable support for pinning composite resources and claims to a CompositionRevision using CompositionRevisionOverrides."`
+	EnablePackageUpgrades              bool `group:"Alpha Features:" help:"Enable support for upgrading several packages together, with rollback on failure, using PackageUpgrades."`
+	EnableDependencyPlans              bool `group:"Alpha Features:" help:"Enable support for planning what installing a package would install, without installing anything, using DependencyPlans."`
 
 	EnableCompositionWebhookSchemaValidation bool `default:"true" group:"Beta Features:" help:"Enable support for Composition validation using schemas."`
 	EnableDeploymentRuntimeConfigs           bool `default:"true" group:"Beta Features:" help:"Enable support for Deployment Runtime Configs."`
@@ -127,6 +194,19 @@ type startCommand struct {
 	EnableCompositionFunctionsExtraResources bool `default:"true" hidden:""`
 }
 
+// applyDevMode overrides the flags that dev mode overrides, if it's enabled.
+// It returns how long controllers may back off before requeueing a resource.
+// Zero means controllers use their default backoff.
+func (c *startCommand) applyDevMode() time.Duration {
+	if !c.DevMode {
+		return 0
+	}
+	c.LeaderElection = false
+	c.SyncInterval = devSyncInterval
+	c.PollInterval = devPollInterval
+	return devMaxRequeueDelay
+}
+
 // Run core Crossplane controllers.
 func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //nolint:gocognit // Only slightly over.
 	ctx, cancel := context.WithCancel(context.Background())
@@ -137,6 +217,11 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		return errors.Wrap(err, "cannot get config")
 	}
 
+	maxRequeueDelay := c.applyDevMode()
+	if c.DevMode {
+		log.Info("Dev mode is enabled. Leader election is disabled and resources are reconciled more often. Do not use dev mode in production.")
+	}
+
 	cfg.WarningHandler = rest.NewWarningWriter(os.Stderr, rest.WarningWriterOptions{
 		// Warnings from API requests should be deduplicated so they are only logged once
 		Deduplicate: true,
@@ -145,7 +230,7 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 	// The claim and XR controllers don't use the manager's cache or client.
 	// They use their own. They're setup later in this method.
 	eb := record.NewBroadcaster()
-	mgr, err := ctrl.NewManager(ratelimiter.LimitRESTConfig(cfg, c.MaxReconcileRate), ctrl.Options{
+	mgr, err := ctrl.NewManager(ratelimiter.LimitRESTConfig(useragent.Config(cfg, useragent.Core), c.MaxReconcileRate), ctrl.Options{
 		Scheme: s,
 		Cache: cache.Options{
 			SyncPeriod: &c.SyncInterval,
@@ -222,6 +307,24 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 	m := xfn.NewMetrics()
 	metrics.Registry.MustRegister(m)
 
+	// All controllers share the same work queue metrics.
+	qm := metrics.NewQueueMetrics()
+	metrics.Registry.MustRegister(qm)
+
+	// All controllers share the same notifier.
+	var n notify.Notifier
+	if len(c.NotificationEndpoints) > 0 {
+		hn := notify.NewHTTPNotifier(c.NotificationEndpoints,
+			notify.WithSigningKey([]byte(c.NotificationSigningKey)),
+			notify.WithLogger(log.WithValues("component", "notifier")),
+		)
+		if err := mgr.Add(hn); err != nil {
+			return errors.Wrap(err, "cannot add notifier to manager")
+		}
+		n = hn
+		log.Info("Lifecycle notifications enabled", "endpoints", len(c.NotificationEndpoints))
+	}
+
 	// We want all XR controllers to share the same gRPC clients.
 	functionRunner := xfn.NewPackagedFunctionRunner(mgr.GetClient(),
 		xfn.WithLogger(log),
@@ -273,39 +376,99 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		o.Features.Enable(features.EnableAlphaClaimSSA)
 		log.Info("Alpha feature enabled", "flag", features.EnableAlphaClaimSSA)
 	}
+	if c.EnableSignatureVerification {
+		o.Features.Enable(features.EnableAlphaSignatureVerification)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaSignatureVerification)
+	}
+	if c.RequirePackageSignatures && !c.EnableSignatureVerification {
+		return errors.New("--require-package-signatures requires --enable-signature-verification")
+	}
+	if c.EnableVulnerabilityScanning {
+		o.Features.Enable(features.EnableAlphaVulnerabilityScanning)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaVulnerabilityScanning)
+	}
+	if c.EnableCompositionRevisionOverrides {
+		o.Features.Enable(features.EnableAlphaCompositionRevisionOverrides)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaCompositionRevisionOverrides)
+	}
+	if c.EnablePackageUpgrades {
+		o.Features.Enable(features.EnableAlphaPackageUpgrades)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaPackageUpgrades)
+	}
+	if c.EnableDependencyPlans {
+		o.Features.Enable(features.EnableAlphaDependencyPlans)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaDependencyPlans)
+	}
 
 	// Claim and XR controllers are started and stopped dynamically by the
 	// ControllerEngine below. When realtime compositions are enabled, they also
 	// start and stop their watches (e.g. of composed resources) dynamically. To
 	// do this, the ControllerEngine must have exclusive ownership of a cache.
 	// This allows it to track what controllers are using the cache's informers.
-	ca, err := cache.New(mgr.GetConfig(), cache.Options{
-		HTTPClient: mgr.GetHTTPClient(),
-		Scheme:     mgr.GetScheme(),
-		Mapper:     mgr.GetRESTMapper(),
-		SyncPeriod: &c.SyncInterval,
-
-		// When a CRD is deleted, any informers for its GVKs will start trying
-		// to restart their watches, and fail with scary errors. This should
-		// only happen when realtime composition is enabled, and we should GC
-		// the informer within 60 seconds. This handler tries to make the error
-		// a little more informative, and less scary.
-		DefaultWatchErrorHandler: func(_ *kcache.Reflector, err error) {
-			if errors.Is(io.EOF, err) {
-				// Watch closed normally.
-				return
-			}
-			log.Debug("Watch error - probably due to CRD being uninstalled", "error", err)
-		},
+	//
+	// When a CRD is deleted, any informers for its GVKs will start trying to
+	// restart their watches, and fail with scary errors. This should only
+	// happen when realtime composition is enabled, and we should GC the
+	// informer within 60 seconds. This handler tries to make the error a
+	// little more informative, and less scary.
+	weh := func(_ *kcache.Reflector, err error) {
+		if errors.Is(io.EOF, err) {
+			// Watch closed normally.
+			return
+		}
+		log.Debug("Watch error - probably due to CRD being uninstalled", "error", err)
+	}
+	// The API extension controllers identify themselves to the API server
+	// separately from the rest of Crossplane, so that cluster admins can tell
+	// their requests apart.
+	aecfg := useragent.Config(mgr.GetConfig(), useragent.APIExtensions)
+	aehc, err := rest.HTTPClientFor(aecfg)
+	if err != nil {
+		return errors.Wrap(err, "cannot create HTTP client for API extension controllers")
+	}
+
+	ca, err := cache.New(aecfg, cache.Options{
+		HTTPClient:               aehc,
+		Scheme:                   mgr.GetScheme(),
+		Mapper:                   mgr.GetRESTMapper(),
+		SyncPeriod:               &c.SyncInterval,
+		DefaultWatchErrorHandler: weh,
 	})
 	if err != nil {
 		return errors.Wrap(err, "cannot create cache for API extension controllers")
 	}
 
+	// Composed resources are read and watched using a separate cache that only
+	// contains resources with the composite label. Otherwise we'd cache every
+	// resource of each composed kind, e.g. every managed resource of a kind
+	// even if only a few are composed.
+	composed, err := labels.NewRequirement(xcrd.LabelKeyNamePrefixForComposed, selection.Exists, nil)
+	if err != nil {
+		return errors.Wrap(err, "cannot create composed resource label selector")
+	}
+	cdca, err := cache.New(aecfg, cache.Options{
+		HTTPClient:               aehc,
+		Scheme:                   mgr.GetScheme(),
+		Mapper:                   mgr.GetRESTMapper(),
+		SyncPeriod:               &c.SyncInterval,
+		DefaultLabelSelector:     labels.NewSelector().Add(*composed),
+		DefaultWatchErrorHandler: weh,
+	})
+	if err != nil {
+		return errors.Wrap(err, "cannot create composed resource cache for API extension controllers")
+	}
+
 	go func() {
-		// Don't start the cache until the manager is elected.
+		// Don't start the caches until the manager is elected.
 		<-mgr.Elected()
 
+		go func() {
+			if err := cdca.Start(ctx); err != nil {
+				log.Info("API extensions composed resource cache returned an error", "error", err)
+			}
+			log.Info("API extensions composed resource cache stopped")
+		}()
+
 		if err := ca.Start(ctx); err != nil {
 			log.Info("API extensions cache returned an error", "error", err)
 		}
@@ -313,8 +476,8 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		log.Info("API extensions cache stopped")
 	}()
 
-	cl, err := client.New(mgr.GetConfig(), client.Options{
-		HTTPClient: mgr.GetHTTPClient(),
+	cl, err := client.New(aecfg, client.Options{
+		HTTPClient: aehc,
 		Scheme:     mgr.GetScheme(),
 		Mapper:     mgr.GetRESTMapper(),
 		Cache: &client.CacheOptions{
@@ -331,6 +494,19 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		return errors.Wrap(err, "cannot create client for API extension controllers")
 	}
 
+	cdcl, err := client.New(aecfg, client.Options{
+		HTTPClient: aehc,
+		Scheme:     mgr.GetScheme(),
+		Mapper:     mgr.GetRESTMapper(),
+		Cache: &client.CacheOptions{
+			Reader:       cdca,
+			Unstructured: true,
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "cannot create composed resource client for API extension controllers")
+	}
+
 	// It's important the engine's client is wrapped with unstructured.NewClient
 	// because controller-runtime always caches *unstructured.Unstructured, not
 	// our wrapper types like *composite.Unstructured. This client takes care of
@@ -338,6 +514,7 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 	ce := engine.New(mgr,
 		engine.TrackInformers(ca, mgr.GetScheme()),
 		unstructured.NewClient(cl),
+		engine.WithComposedResourceCache(engine.TrackInformers(cdca, mgr.GetScheme()), unstructured.NewClient(cdcl)),
 		engine.WithLogger(log),
 	)
 
@@ -357,6 +534,17 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		Options:          o,
 		ControllerEngine: ce,
 		FunctionRunner:   functionRunner,
+		Namespace:        c.Namespace,
+
+		MaxComposingComposites:          c.MaxComposingComposites,
+		MaxConcurrentComposedApplies:    c.MaxConcurrentComposedApplies,
+		MaxRequeueDelay:                 maxRequeueDelay,
+		CompositionRevisionHistoryLimit: c.CompositionRevisionHistoryLimit,
+		FairClaimQueues:                 c.FairClaimQueues,
+		ClaimNamespaceWeights:           c.ClaimNamespaceWeights,
+		WebhookEnabled:                  c.WebhookEnabled,
+		QueueMetrics:                    qm,
+		Notifier:                        n,
 	}
 
 	if err := apiextensions.Setup(mgr, ao); err != nil {
@@ -380,9 +568,45 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		Namespace:                        c.Namespace,
 		ServiceAccount:                   c.ServiceAccount,
 		DefaultRegistry:                  c.Registry,
-		FetcherOptions:                   []xpkg.FetcherOpt{xpkg.WithUserAgent(c.UserAgent)},
+		FetcherOptions:                   []xpkg.FetcherOpt{xpkg.WithUserAgent(c.UserAgent), xpkg.WithCredentialHelpers(c.RegistryCredentialHelpers)},
+		PackageImageDir:                  c.PackageImageDir,
+		DownloadDir:                      filepath.Join(c.CacheDir, "downloads"),
 		PackageRuntime:                   pr,
+		PackageRuntimePlatforms:          c.PackageRuntimePlatforms,
 		MaxConcurrentPackageEstablishers: c.MaxConcurrentPackageEstablishers,
+		ProviderDrainPeriod:              c.ProviderDrainPeriod,
+		StrictConfigurationDependencies:  c.StrictConfigurationDependencies,
+		RequireDigests:                   c.RequirePackageDigests,
+		RequireSignatures:                c.RequirePackageSignatures,
+		SkipDependencyResolution:         c.SkipDependencyResolution,
+		ChannelPollInterval:              c.PackageChannelPollInterval,
+		FootprintSampleInterval:          c.PackageFootprintSampleInterval,
+		FetchRetries:                     c.PackageFetchRetries,
+		FetchBackoff:                     c.PackageFetchBackoff,
+		MaxRequeueDelay:                  maxRequeueDelay,
+		QueueMetrics:                     qm,
+		Notifier:                         n,
+	}
+
+	// Tunables may only be changed while Crossplane is running if they're
+	// read from a file. Otherwise they're fixed at startup.
+	var tunables []xpkg.TunablesReloaderOption
+
+	if c.ImageCacheMaxSize != "" {
+		q, err := resource.ParseQuantity(c.ImageCacheMaxSize)
+		if err != nil {
+			return errors.Wrap(err, "cannot parse image cache max size")
+		}
+		ic := xpkg.NewFsImageCache(filepath.Join(c.CacheDir, "images"), q.Value())
+		po.ImageCache = ic
+		tunables = append(tunables, xpkg.WithTunableImageCache(ic))
+		log.Info("Package image cache enabled", "max-size", q.String())
+	}
+
+	if c.RemoteCacheRegistry != "" || c.TunablesFile != "" {
+		rc := xpkg.NewRemoteCache(c.RemoteCacheRegistry)
+		po.RemoteCache = rc
+		tunables = append(tunables, xpkg.WithTunableRemoteCache(rc))
 	}
 
 	if c.CABundlePath != "" {
@@ -393,10 +617,74 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		po.FetcherOptions = append(po.FetcherOptions, xpkg.WithCustomCA(rootCAs))
 	}
 
+	if c.RegistryClientCertSecretName != "" {
+		po.FetcherOptions = append(po.FetcherOptions, xpkg.WithClientCertSecret(c.RegistryClientCertSecretName))
+	}
+
+	if c.PackagePlatform != "" {
+		p, err := conregv1.ParsePlatform(c.PackagePlatform)
+		if err != nil {
+			return errors.Wrap(err, "cannot parse package platform")
+		}
+		po.FetcherOptions = append(po.FetcherOptions, xpkg.WithPlatform(*p))
+		// Run package runtimes on nodes of the platform whose package
+		// variant we pulled.
+		if len(po.PackageRuntimePlatforms) == 0 {
+			po.PackageRuntimePlatforms = []string{p.OS + "/" + p.Architecture}
+		}
+	}
+
+	// All package controllers share one limiter, so that registry requests are
+	// limited across all packages.
+	if c.MaxConcurrentPackagePulls > 0 || c.MaxPackagePullRate > 0 || c.TunablesFile != "" {
+		pl := xpkg.NewPullLimiter(c.MaxConcurrentPackagePulls, c.MaxPackagePullRate, c.PackagePullBurst)
+		po.FetcherOptions = append(po.FetcherOptions, xpkg.WithPullLimiter(pl))
+		tunables = append(tunables, xpkg.WithTunablePullLimiter(pl))
+	}
+	if c.MaxConcurrentPackagePulls > 0 || c.MaxPackagePullRate > 0 {
+		log.Info("Package pulls are limited", "max-concurrent-pulls", c.MaxConcurrentPackagePulls, "max-pull-rate", c.MaxPackagePullRate, "burst", c.PackagePullBurst)
+	}
+
+	if c.TunablesFile != "" {
+		defaults := xpkg.Tunables{
+			MaxConcurrentPackagePulls: &c.MaxConcurrentPackagePulls,
+			MaxPackagePullRate:        &c.MaxPackagePullRate,
+			PackagePullBurst:          &c.PackagePullBurst,
+			RemoteCacheRegistry:       &c.RemoteCacheRegistry,
+		}
+		if c.ImageCacheMaxSize != "" {
+			defaults.ImageCacheMaxSize = &c.ImageCacheMaxSize
+		}
+		tr := xpkg.NewTunablesReloader(c.TunablesFile, defaults, append(tunables, xpkg.WithTunablesLogger(log.WithValues("component", "tunables")))...)
+		if err := tr.Reload(); err != nil {
+			return errors.Wrap(err, "cannot load tunables")
+		}
+		if err := mgr.Add(tr); err != nil {
+			return errors.Wrap(err, "cannot add tunables reloader to manager")
+		}
+		log.Info("Tunables are reloaded when they change", "path", c.TunablesFile)
+	}
+
+	// PackageRepositories configure how packages whose references match
+	// their prefix are fetched.
+	po.FetcherOptions = append(po.FetcherOptions, xpkg.WithRepositories(xpkg.NewPackageRepositoryResolver(mgr.GetClient(), c.Namespace)))
+
 	if err := pkg.Setup(mgr, po); err != nil {
 		return errors.Wrap(err, "cannot add packages controllers to manager")
 	}
 
+	if c.BootstrapDir != "" {
+		b := initializer.NewBootstrapManifests(c.BootstrapDir, s,
+			initializer.WithBootstrapLogger(log.WithValues("component", "bootstrap")))
+		// Only the leader applies the bootstrap manifests.
+		if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+			return b.Run(ctx, mgr.GetClient())
+		})); err != nil {
+			return errors.Wrap(err, "cannot add bootstrap manifests to manager")
+		}
+		log.Info("Bootstrap manifests will be applied", "path", c.BootstrapDir)
+	}
+
 	// Registering webhooks with the manager is what actually starts the webhook
 	// server.
 	if c.WebhookEnabled {
@@ -406,9 +694,23 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		if err := xrd.SetupWebhookWithManager(mgr, o); err != nil {
 			return errors.Wrap(err, "cannot setup webhook for compositeresourcedefinitions")
 		}
-		if err := composition.SetupWebhookWithManager(mgr, o); err != nil {
+		if err := composition.SetupWebhookWithManager(mgr, o, composition.WithForbiddenPatchTargets(c.CompositionForbiddenPatchTargets...)); err != nil {
 			return errors.Wrap(err, "cannot setup webhook for compositions")
 		}
+		if err := immutable.SetupWebhookWithManager(mgr, o); err != nil {
+			return errors.Wrap(err, "cannot setup webhook for immutable fields")
+		}
+		// The package quota webhook is always configured, so it must always
+		// be registered. It allows everything when there are no limits.
+		if err := quota.SetupWebhookWithManager(mgr, o, quota.WithMaxPackages(c.MaxPackages), quota.WithMaxRevisions(c.MaxPackageRevisions)); err != nil {
+			return errors.Wrap(err, "cannot setup webhook for package quotas")
+		}
+		if err := dependents.SetupWebhookWithManager(mgr, o); err != nil {
+			return errors.Wrap(err, "cannot setup webhook for package dependents")
+		}
+		if err := plan.SetupWebhookWithManager(mgr, o); err != nil {
+			return errors.Wrap(err, "cannot setup webhook for claim plans")
+		}
 		if o.Features.Enabled(features.EnableAlphaUsages) {
 			if err := usage.SetupWebhookWithManager(mgr, o); err != nil {
 				return errors.Wrap(err, "cannot setup webhook for usages")
@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestApplyDevMode(t *testing.T) {
+	type want struct {
+		c               startCommand
+		maxRequeueDelay time.Duration
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      startCommand
+		want   want
+	}{
+		"Disabled": {
+			reason: "Flags should not be overridden when dev mode is disabled.",
+			c: startCommand{
+				LeaderElection: true,
+				SyncInterval:   time.Hour,
+				PollInterval:   time.Minute,
+			},
+			want: want{
+				c: startCommand{
+					LeaderElection: true,
+					SyncInterval:   time.Hour,
+					PollInterval:   time.Minute,
+				},
+			},
+		},
+		"Enabled": {
+			reason: "Dev mode should disable leader election, shorten the sync and poll intervals, and cap requeue backoff.",
+			c: startCommand{
+				DevMode:        true,
+				LeaderElection: true,
+				SyncInterval:   time.Hour,
+				PollInterval:   time.Minute,
+			},
+			want: want{
+				c: startCommand{
+					DevMode:        true,
+					LeaderElection: false,
+					SyncInterval:   devSyncInterval,
+					PollInterval:   devPollInterval,
+				},
+				maxRequeueDelay: devMaxRequeueDelay,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.c.applyDevMode()
+			if diff := cmp.Diff(tc.want.maxRequeueDelay, got); diff != "" {
+				t.Errorf("\n%s\napplyDevMode(): -want max requeue delay, +got max requeue delay:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.c, tc.c, cmpopts.IgnoreUnexported(startCommand{})); diff != "" {
+				t.Errorf("\n%s\napplyDevMode(): -want flags, +got flags:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -34,6 +34,10 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		return err
 	}
 
+	if err := composition.SetupGarbageCollector(mgr, o); err != nil {
+		return err
+	}
+
 	if err := definition.Setup(mgr, o); err != nil {
 		return err
 	}
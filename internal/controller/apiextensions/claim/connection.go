@@ -102,21 +102,78 @@ func (s soClaim) GetWriteConnectionSecretToReference() *xpv1.SecretReference {
 	return nil
 }
 
+// defaultRefClaim is a claim whose connection secret defaults to a Secret with
+// the same name as the claim if it doesn't reference one.
+type defaultRefClaim struct {
+	resource.LocalConnectionSecretOwner
+}
+
+func (c defaultRefClaim) GetWriteConnectionSecretToReference() *xpv1.LocalSecretReference {
+	if ref := c.LocalConnectionSecretOwner.GetWriteConnectionSecretToReference(); ref != nil {
+		return ref
+	}
+	return &xpv1.LocalSecretReference{Name: c.GetName()}
+}
+
+// NopConnectionPropagator is a ConnectionPropagator that does nothing.
+type NopConnectionPropagator struct{}
+
+// NewNopConnectionPropagator returns a new NopConnectionPropagator.
+func NewNopConnectionPropagator() *NopConnectionPropagator {
+	return &NopConnectionPropagator{}
+}
+
+// PropagateConnection does nothing. It never propagates connection details.
+func (n *NopConnectionPropagator) PropagateConnection(_ context.Context, _ resource.LocalConnectionSecretOwner, _ resource.ConnectionSecretOwner) (bool, error) {
+	return false, nil
+}
+
 // An APIConnectionPropagator propagates connection details by reading
 // them from and writing them to a Kubernetes API server.
 type APIConnectionPropagator struct {
-	client resource.ClientApplicator
+	client    resource.ClientApplicator
+	automatic bool
+	keys      []string
+}
+
+// An APIConnectionPropagatorOption configures an APIConnectionPropagator.
+type APIConnectionPropagatorOption func(a *APIConnectionPropagator)
+
+// WithAutomaticConnectionSecrets configures an APIConnectionPropagator to
+// propagate connection details to claims that don't specify a connection
+// secret. They're propagated to a Secret with the same name as the claim.
+func WithAutomaticConnectionSecrets() APIConnectionPropagatorOption {
+	return func(a *APIConnectionPropagator) {
+		a.automatic = true
+	}
+}
+
+// WithConnectionSecretKeys configures an APIConnectionPropagator to propagate
+// only the supplied connection secret keys. All keys are propagated if none
+// are supplied.
+func WithConnectionSecretKeys(keys ...string) APIConnectionPropagatorOption {
+	return func(a *APIConnectionPropagator) {
+		a.keys = keys
+	}
 }
 
 // NewAPIConnectionPropagator returns a new APIConnectionPropagator.
-func NewAPIConnectionPropagator(c client.Client) *APIConnectionPropagator {
-	return &APIConnectionPropagator{
+func NewAPIConnectionPropagator(c client.Client, o ...APIConnectionPropagatorOption) *APIConnectionPropagator {
+	a := &APIConnectionPropagator{
 		client: resource.ClientApplicator{Client: c, Applicator: resource.NewAPIUpdatingApplicator(c)},
 	}
+	for _, fn := range o {
+		fn(a)
+	}
+	return a
 }
 
 // PropagateConnection details from the supplied resource.
 func (a *APIConnectionPropagator) PropagateConnection(ctx context.Context, to resource.LocalConnectionSecretOwner, from resource.ConnectionSecretOwner) (bool, error) {
+	if a.automatic {
+		to = defaultRefClaim{LocalConnectionSecretOwner: to}
+	}
+
 	// Either from does not expose a connection secret, or to does not want one.
 	if from.GetWriteConnectionSecretToReference() == nil || to.GetWriteConnectionSecretToReference() == nil {
 		return false, nil
@@ -140,6 +197,14 @@ func (a *APIConnectionPropagator) PropagateConnection(ctx context.Context, to re
 
 	ts := resource.LocalConnectionSecretFor(to, to.GetObjectKind().GroupVersionKind())
 	ts.Data = fs.Data
+	if len(a.keys) > 0 {
+		ts.Data = make(map[string][]byte, len(a.keys))
+		for _, k := range a.keys {
+			if v, ok := fs.Data[k]; ok {
+				ts.Data[k] = v
+			}
+		}
+	}
 
 	err := a.client.Apply(ctx, ts,
 		resource.ConnectionSecretMustBeControllableBy(to.GetUID()),
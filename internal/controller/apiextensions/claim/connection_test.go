@@ -41,7 +41,7 @@ func TestPropagateConnection(t *testing.T) {
 
 	mgcsns := "coolnamespace"
 	mgcsname := "coolmanagedsecret"
-	mgcsdata := map[string][]byte{"cool": {1}}
+	mgcsdata := map[string][]byte{"cool": {1}, "secret": {2}}
 
 	cmcsns := "coolnamespace"
 	cmcsname := "coolclaimsecret"
@@ -59,8 +59,22 @@ func TestPropagateConnection(t *testing.T) {
 		},
 	}
 
+	auto := &fake.CompositeClaim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: cmcsns, Name: "coolclaim"},
+	}
+
+	get := test.NewMockGetFn(nil, func(o client.Object) error {
+		s := resource.ConnectionSecretFor(cp, schema.GroupVersionKind{})
+		s.Data = mgcsdata
+
+		*o.(*corev1.Secret) = *s
+		return nil
+	})
+
 	type fields struct {
-		client resource.ClientApplicator
+		client    resource.ClientApplicator
+		automatic bool
+		keys      []string
 	}
 
 	type args struct {
@@ -219,11 +233,62 @@ func TestPropagateConnection(t *testing.T) {
 				propagated: true,
 			},
 		},
+		"SuccessfulAutomatic": {
+			reason: "Claims that don't want a secret should receive one named after the claim if connection secrets are automatic",
+			fields: fields{
+				client: resource.ClientApplicator{
+					Client: &test.MockClient{MockGet: get},
+					Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+						want := resource.LocalConnectionSecretFor(defaultRefClaim{LocalConnectionSecretOwner: auto}, schema.GroupVersionKind{})
+						want.Data = mgcsdata
+						if want.GetName() != "coolclaim" {
+							t.Errorf("want claim secret named coolclaim, got %s", want.GetName())
+						}
+						if diff := cmp.Diff(want, o); diff != "" {
+							t.Errorf("-want, +got:\n %s", diff)
+						}
+						return nil
+					}),
+				},
+				automatic: true,
+			},
+			args: args{
+				to:   auto,
+				from: cp,
+			},
+			want: want{
+				propagated: true,
+			},
+		},
+		"SuccessfulFilteredKeys": {
+			reason: "Only the configured keys should be propagated to the claim secret",
+			fields: fields{
+				client: resource.ClientApplicator{
+					Client: &test.MockClient{MockGet: get},
+					Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+						want := resource.LocalConnectionSecretFor(cm, schema.GroupVersionKind{})
+						want.Data = map[string][]byte{"cool": {1}}
+						if diff := cmp.Diff(want, o); diff != "" {
+							t.Errorf("-want, +got:\n %s", diff)
+						}
+						return nil
+					}),
+				},
+				keys: []string{"cool", "missing"},
+			},
+			args: args{
+				to:   cm,
+				from: cp,
+			},
+			want: want{
+				propagated: true,
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			api := &APIConnectionPropagator{client: tc.fields.client}
+			api := &APIConnectionPropagator{client: tc.fields.client, automatic: tc.fields.automatic, keys: tc.fields.keys}
 			got, err := api.PropagateConnection(tc.args.ctx, tc.args.to, tc.args.from)
 			if diff := cmp.Diff(tc.want.propagated, got); diff != "" {
 				t.Errorf("\n%s\napi.PropagateConnection(...): -want, +got:\n%s", tc.reason, diff)
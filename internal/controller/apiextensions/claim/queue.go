@@ -0,0 +1,211 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package claim
+
+import (
+	"sync"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ workqueue.Interface = &NamespaceFairQueue{}
+
+// A NamespaceFairQueue is a work queue that dispatches items round-robin
+// across namespaces, so that a namespace with thousands of queued claims can't
+// starve the others. Each namespace has a sub-queue of items in the order they
+// were added. A namespace with weight n gets up to n items dispatched in a row
+// before the next namespace gets a turn. Items that aren't reconcile requests
+// share a namespace.
+//
+// Like the default work queue, an item is never queued more than once, and is
+// never dispatched again while it's being processed.
+type NamespaceFairQueue struct {
+	cond *sync.Cond
+
+	weights map[string]int
+
+	// Queued items, by namespace, in the order they were added.
+	queues map[string][]any
+
+	// Namespaces with queued items, in round-robin order. The namespace at
+	// the front of the order gets credit more items dispatched before it
+	// moves to the back.
+	order  []string
+	credit int
+
+	dirty      map[any]struct{}
+	processing map[any]struct{}
+
+	shuttingDown bool
+	drain        bool
+}
+
+// NewNamespaceFairQueue returns a NamespaceFairQueue. Namespaces have weight
+// one unless they have a positive weight in the supplied map.
+func NewNamespaceFairQueue(weights map[string]int) *NamespaceFairQueue {
+	return &NamespaceFairQueue{
+		cond:       sync.NewCond(&sync.Mutex{}),
+		weights:    weights,
+		queues:     make(map[string][]any),
+		dirty:      make(map[any]struct{}),
+		processing: make(map[any]struct{}),
+	}
+}
+
+// Add marks the supplied item as needing processing.
+func (q *NamespaceFairQueue) Add(item any) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	if q.shuttingDown {
+		return
+	}
+	if _, ok := q.dirty[item]; ok {
+		return
+	}
+	q.dirty[item] = struct{}{}
+	if _, ok := q.processing[item]; ok {
+		return
+	}
+	q.push(item)
+	q.cond.Signal()
+}
+
+// Len returns the number of queued items.
+func (q *NamespaceFairQueue) Len() int {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	n := 0
+	for _, items := range q.queues {
+		n += len(items)
+	}
+	return n
+}
+
+// Get blocks until it can return an item to be processed. If shutdown is true
+// the caller should stop processing items. Done must be called with the item
+// once it has been processed.
+func (q *NamespaceFairQueue) Get() (item any, shutdown bool) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	for len(q.order) == 0 && !q.shuttingDown {
+		q.cond.Wait()
+	}
+	if len(q.order) == 0 {
+		return nil, true
+	}
+	item = q.pop()
+	q.processing[item] = struct{}{}
+	delete(q.dirty, item)
+	return item, false
+}
+
+// Done marks the supplied item as processed. It's queued again if it was added
+// while it was being processed.
+func (q *NamespaceFairQueue) Done(item any) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	delete(q.processing, item)
+	if _, ok := q.dirty[item]; ok {
+		q.push(item)
+		q.cond.Signal()
+		return
+	}
+	if len(q.processing) == 0 {
+		q.cond.Broadcast()
+	}
+}
+
+// ShutDown causes the queue to ignore new items. Callers of Get are told to
+// stop once the queue is empty.
+func (q *NamespaceFairQueue) ShutDown() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	q.drain = false
+	q.shuttingDown = true
+	q.cond.Broadcast()
+}
+
+// ShutDownWithDrain is like ShutDown, but blocks until all items being
+// processed are done.
+func (q *NamespaceFairQueue) ShutDownWithDrain() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	q.drain = true
+	q.shuttingDown = true
+	q.cond.Broadcast()
+	for len(q.processing) != 0 && q.drain {
+		q.cond.Wait()
+	}
+}
+
+// ShuttingDown returns true if the queue is shutting down.
+func (q *NamespaceFairQueue) ShuttingDown() bool {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.shuttingDown
+}
+
+func (q *NamespaceFairQueue) push(item any) {
+	ns := namespace(item)
+	if len(q.queues[ns]) == 0 {
+		if len(q.order) == 0 {
+			q.credit = q.weight(ns)
+		}
+		q.order = append(q.order, ns)
+	}
+	q.queues[ns] = append(q.queues[ns], item)
+}
+
+func (q *NamespaceFairQueue) pop() any {
+	ns := q.order[0]
+	item := q.queues[ns][0]
+	q.queues[ns] = q.queues[ns][1:]
+	q.credit--
+
+	switch {
+	case len(q.queues[ns]) == 0:
+		// The namespace has nothing left to dispatch. It rejoins the back of
+		// the order next time an item is added.
+		delete(q.queues, ns)
+		q.order = q.order[1:]
+	case q.credit <= 0:
+		// The namespace has used its turn. Move it to the back.
+		q.order = append(q.order[1:], ns)
+	default:
+		return item
+	}
+
+	if len(q.order) > 0 {
+		q.credit = q.weight(q.order[0])
+	}
+	return item
+}
+
+func (q *NamespaceFairQueue) weight(ns string) int {
+	if w := q.weights[ns]; w > 0 {
+		return w
+	}
+	return 1
+}
+
+func namespace(item any) string {
+	if r, ok := item.(reconcile.Request); ok {
+		return r.Namespace
+	}
+	return ""
+}
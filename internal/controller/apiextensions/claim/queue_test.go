@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package claim
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ workqueue.Interface = &NamespaceFairQueue{}
+
+func TestNamespaceFairQueue(t *testing.T) {
+	req := func(namespace, name string) reconcile.Request {
+		return reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}
+	}
+
+	type args struct {
+		weights map[string]int
+		add     []reconcile.Request
+	}
+	type want struct {
+		got []reconcile.Request
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"SingleNamespace": {
+			reason: "Items from a single namespace should be dispatched in the order they were added.",
+			args: args{
+				add: []reconcile.Request{req("a", "1"), req("a", "2"), req("a", "3")},
+			},
+			want: want{
+				got: []reconcile.Request{req("a", "1"), req("a", "2"), req("a", "3")},
+			},
+		},
+		"RoundRobin": {
+			reason: "Items should be dispatched round-robin across namespaces, so a namespace with many items doesn't starve the others.",
+			args: args{
+				add: []reconcile.Request{req("a", "1"), req("a", "2"), req("a", "3"), req("b", "1"), req("c", "1"), req("b", "2")},
+			},
+			want: want{
+				got: []reconcile.Request{req("a", "1"), req("b", "1"), req("c", "1"), req("a", "2"), req("b", "2"), req("a", "3")},
+			},
+		},
+		"Weighted": {
+			reason: "A namespace should get as many items dispatched in a row as its weight.",
+			args: args{
+				weights: map[string]int{"a": 2},
+				add:     []reconcile.Request{req("a", "1"), req("a", "2"), req("a", "3"), req("b", "1"), req("b", "2")},
+			},
+			want: want{
+				got: []reconcile.Request{req("a", "1"), req("a", "2"), req("b", "1"), req("a", "3"), req("b", "2")},
+			},
+		},
+		"Deduplicated": {
+			reason: "An item that is already queued should not be queued again.",
+			args: args{
+				add: []reconcile.Request{req("a", "1"), req("b", "1"), req("a", "1")},
+			},
+			want: want{
+				got: []reconcile.Request{req("a", "1"), req("b", "1")},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			q := NewNamespaceFairQueue(tc.args.weights)
+			for _, r := range tc.args.add {
+				q.Add(r)
+			}
+
+			got := make([]reconcile.Request, 0, q.Len())
+			for q.Len() > 0 {
+				item, _ := q.Get()
+				got = append(got, item.(reconcile.Request))
+				q.Done(item)
+			}
+
+			if diff := cmp.Diff(tc.want.got, got); diff != "" {
+				t.Errorf("\n%s\nGet(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNamespaceFairQueueProcessing(t *testing.T) {
+	q := NewNamespaceFairQueue(nil)
+	r := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "a", Name: "1"}}
+
+	q.Add(r)
+	item, _ := q.Get()
+
+	// Adding an item that's being processed should requeue it once it's done.
+	q.Add(r)
+	if got := q.Len(); got != 0 {
+		t.Errorf("Len(): want 0 while the item is being processed, got %d", got)
+	}
+	q.Done(item)
+	if got := q.Len(); got != 1 {
+		t.Errorf("Len(): want 1 once the item is done, got %d", got)
+	}
+
+	q.ShutDown()
+	if _, shutdown := q.Get(); shutdown {
+		t.Errorf("Get(): want queued item after shutdown, got shutdown")
+	}
+	if _, shutdown := q.Get(); !shutdown {
+		t.Errorf("Get(): want shutdown once the queue is empty")
+	}
+}
@@ -27,6 +27,7 @@ import (
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/claim"
@@ -175,6 +176,14 @@ func (s *ClientSideCompositeSyncer) Sync(ctx context.Context, cm *claim.Unstruct
 		return errors.Wrap(err, errMergeClaimStatus)
 	}
 
+	// Merging won't remove the XR's queue status from the claim once the XR
+	// leaves the queue, so we remove it explicitly.
+	for _, f := range xcrd.GetPropFields(xcrd.CompositeResourceQueueStatusProps()) {
+		if _, err := fieldpath.Pave(xr.Object).GetValue("status." + f); fieldpath.IsNotFound(err) {
+			_ = fieldpath.Pave(cm.Object).DeleteField("status." + f)
+		}
+	}
+
 	if err := s.client.Status().Update(ctx, cm); err != nil {
 		return errors.Wrap(err, errUpdateClaimStatus)
 	}
@@ -19,12 +19,16 @@ package composite
 import (
 	"context"
 	"math/rand"
+	"sort"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -35,6 +39,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
 	"github.com/crossplane/crossplane/internal/xcrd"
 )
 
@@ -52,6 +57,9 @@ const (
 	errCompositionNotCompatible        = "referenced composition is not compatible with this composite resource"
 	errGetXRD                          = "cannot get composite resource definition"
 	errFetchCompositionRevision        = "cannot fetch composition revision"
+	errListOverrides                   = "cannot list CompositionRevisionOverrides"
+	errFmtOverrideSelector             = "cannot parse selector of CompositionRevisionOverride %q"
+	errFmtGetOverrideRevision          = "cannot get CompositionRevision of CompositionRevisionOverride %q"
 )
 
 // Event reasons.
@@ -195,6 +203,59 @@ func (f *APIRevisionFetcher) getCompositionRevisionList(ctx context.Context, cr
 	return rl, nil
 }
 
+// An OverridingRevisionFetcher fetches the CompositionRevision that a
+// CompositionRevisionOverride pins a composite resource to, if any. It falls
+// back to another CompositionRevisionFetcher for composite resources that
+// aren't pinned.
+type OverridingRevisionFetcher struct {
+	client  client.Reader
+	wrapped CompositionRevisionFetcher
+}
+
+// NewOverridingRevisionFetcher returns a CompositionRevisionFetcher that
+// fetches the CompositionRevision a composite resource is pinned to by a
+// CompositionRevisionOverride, or calls the supplied fetcher if it isn't.
+func NewOverridingRevisionFetcher(c client.Reader, wrapped CompositionRevisionFetcher) *OverridingRevisionFetcher {
+	return &OverridingRevisionFetcher{client: c, wrapped: wrapped}
+}
+
+// Fetch the CompositionRevision the supplied composite resource is pinned to.
+// The composite resource's composition references are left untouched, so it
+// returns to its selected revision once it's no longer pinned.
+func (f *OverridingRevisionFetcher) Fetch(ctx context.Context, cr resource.Composite) (*v1.CompositionRevision, error) {
+	l := &v1alpha1.CompositionRevisionOverrideList{}
+	if err := f.client.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, errListOverrides)
+	}
+	sort.Slice(l.Items, func(i, j int) bool { return l.Items[i].GetName() < l.Items[j].GetName() })
+
+	gvk := cr.GetObjectKind().GroupVersionKind()
+	for _, o := range l.Items {
+		s, err := metav1.LabelSelectorAsSelector(&o.Spec.Selector)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtOverrideSelector, o.GetName())
+		}
+		if !s.Matches(labels.Set(cr.GetLabels())) {
+			continue
+		}
+
+		rev := &v1.CompositionRevision{}
+		if err := f.client.Get(ctx, types.NamespacedName{Name: o.Spec.CompositionRevisionRef.Name}, rev); err != nil {
+			return nil, errors.Wrapf(err, errFmtGetOverrideRevision, o.GetName())
+		}
+
+		// The selector may match composite resources of other types. The
+		// override only applies to the revision's composite type.
+		ref := rev.Spec.CompositeTypeRef
+		if ref.APIVersion != gvk.GroupVersion().String() || ref.Kind != gvk.Kind {
+			continue
+		}
+		return rev, nil
+	}
+
+	return f.wrapped.Fetch(ctx, cr)
+}
+
 // NewCompositionSelectorChain returns a new CompositionSelectorChain.
 func NewCompositionSelectorChain(list ...CompositionSelector) *CompositionSelectorChain {
 	return &CompositionSelectorChain{list: list}
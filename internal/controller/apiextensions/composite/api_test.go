@@ -33,9 +33,11 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
 	"github.com/crossplane/crossplane/internal/xcrd"
 )
 
@@ -507,6 +509,148 @@ func TestFetchRevision(t *testing.T) {
 	}
 }
 
+func TestOverridingRevisionFetcher(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	pinned := &v1.CompositionRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: "cool-composition-hotfix"},
+		Spec: v1.CompositionRevisionSpec{
+			CompositeTypeRef: v1.TypeReference{APIVersion: "example.org/v1", Kind: "XCoolComposite"},
+			Revision:         3,
+		},
+	}
+	latest := &v1.CompositionRevision{ObjectMeta: metav1.ObjectMeta{Name: "cool-composition-latest"}}
+	wrapped := CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
+		return latest, nil
+	})
+
+	xr := func(l map[string]string) *composite.Unstructured {
+		cr := composite.New(composite.WithGroupVersionKind(schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XCoolComposite"}))
+		cr.SetLabels(l)
+		return cr
+	}
+	override := func(name string, l map[string]string, rev string) v1alpha1.CompositionRevisionOverride {
+		return v1alpha1.CompositionRevisionOverride{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: v1alpha1.CompositionRevisionOverrideSpec{
+				Selector:               metav1.LabelSelector{MatchLabels: l},
+				CompositionRevisionRef: v1alpha1.ResourceRef{Name: rev},
+			},
+		}
+	}
+	getPinned := func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+		if key.Name != pinned.GetName() {
+			return errBoom
+		}
+		*obj.(*v1.CompositionRevision) = *pinned
+		return nil
+	}
+
+	type want struct {
+		rev *v1.CompositionRevision
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Reader
+		cr     resource.Composite
+		want   want
+	}{
+		"ListOverridesError": {
+			reason: "We should return any error encountered listing overrides.",
+			client: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			cr:     xr(nil),
+			want: want{
+				err: errors.Wrap(errBoom, errListOverrides),
+			},
+		},
+		"NotSelected": {
+			reason: "We should fall back to the wrapped fetcher if no override selects the XR.",
+			client: &test.MockClient{
+				MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+					obj.(*v1alpha1.CompositionRevisionOverrideList).Items = []v1alpha1.CompositionRevisionOverride{
+						override("hotfix", map[string]string{"team": "cool"}, pinned.GetName()),
+					}
+					return nil
+				}),
+			},
+			cr: xr(map[string]string{"team": "uncool"}),
+			want: want{
+				rev: latest,
+			},
+		},
+		"Selected": {
+			reason: "We should return the revision of the first override, by name, that selects the XR.",
+			client: &test.MockClient{
+				MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+					obj.(*v1alpha1.CompositionRevisionOverrideList).Items = []v1alpha1.CompositionRevisionOverride{
+						override("b-broken", map[string]string{"team": "cool"}, "nonexistent"),
+						override("a-hotfix", map[string]string{"team": "cool"}, pinned.GetName()),
+					}
+					return nil
+				}),
+				MockGet: getPinned,
+			},
+			cr: xr(map[string]string{"team": "cool"}),
+			want: want{
+				rev: pinned,
+			},
+		},
+		"OtherCompositeType": {
+			reason: "We should ignore an override whose revision is for a different type of XR.",
+			client: &test.MockClient{
+				MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+					obj.(*v1alpha1.CompositionRevisionOverrideList).Items = []v1alpha1.CompositionRevisionOverride{
+						override("hotfix", nil, pinned.GetName()),
+					}
+					return nil
+				}),
+				MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					*obj.(*v1.CompositionRevision) = *pinned
+					obj.(*v1.CompositionRevision).Spec.CompositeTypeRef.Kind = "XUncoolComposite"
+					return nil
+				}),
+			},
+			cr: xr(nil),
+			want: want{
+				rev: latest,
+			},
+		},
+		"GetRevisionError": {
+			reason: "We should return any error encountered getting an override's revision.",
+			client: &test.MockClient{
+				MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+					obj.(*v1alpha1.CompositionRevisionOverrideList).Items = []v1alpha1.CompositionRevisionOverride{
+						override("hotfix", nil, "nonexistent"),
+					}
+					return nil
+				}),
+				MockGet: getPinned,
+			},
+			cr: xr(nil),
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtGetOverrideRevision, "hotfix"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			f := NewOverridingRevisionFetcher(tc.client, wrapped)
+			got, err := f.Fetch(context.Background(), tc.cr)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("%s\nf.Fetch(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.rev, got); diff != "" {
+				t.Errorf("%s\nf.Fetch(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestConfigure(t *testing.T) {
 	errBoom := errors.New("boom")
 
@@ -16,6 +16,22 @@ specific language governing permissions and limitations under the License.
 package composite
 
 import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
@@ -58,3 +74,315 @@ type ComposedResourceStates map[ResourceName]ComposedResourceState
 
 // ComposedResourceTemplates are the P&T templates for composed resources.
 type ComposedResourceTemplates map[ResourceName]v1.ComposedTemplate
+
+// TypeMissingAPI indicates whether the API of any of a composite resource's
+// composed resources is missing, for example because the provider that
+// defined it was uninstalled. Composed resources whose API is missing aren't
+// applied until the API is installed again.
+const TypeMissingAPI xpv1.ConditionType = "MissingAPI"
+
+// Reasons for the MissingAPI condition.
+const (
+	ReasonMissingAPIs   xpv1.ConditionReason = "MissingAPIs"
+	ReasonAPIsAvailable xpv1.ConditionReason = "APIsAvailable"
+)
+
+// APIOf returns a string identifying the API (i.e. the kind and API version)
+// of the supplied composed resource.
+func APIOf(cd resource.Object) string {
+	gvk := cd.GetObjectKind().GroupVersionKind()
+	return fmt.Sprintf("%s (%s)", gvk.Kind, gvk.GroupVersion())
+}
+
+// MissingAPICondition returns a condition that lists the supplied missing
+// composed resource APIs. It's false if no APIs are missing.
+func MissingAPICondition(apis []string) TargetedCondition {
+	c := xpv1.Condition{
+		Type:               TypeMissingAPI,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonAPIsAvailable,
+	}
+	if len(apis) > 0 {
+		c.Status = corev1.ConditionTrue
+		c.Reason = ReasonMissingAPIs
+		c.Message = "Composed resources with missing APIs will not be applied: " + strings.Join(apis, ", ")
+	}
+	return TargetedCondition{Condition: c, Target: CompositionTargetComposite}
+}
+
+// missingAPIConditions returns the MissingAPI condition of the supplied XR.
+// It returns no conditions if no APIs are missing and the XR has never had
+// the condition, so that XRs whose APIs have always been available don't grow
+// a condition they don't need.
+func missingAPIConditions(xr resource.Conditioned, missing sets.Set[string]) []TargetedCondition {
+	if missing.Len() == 0 && xr.GetCondition(TypeMissingAPI).Reason == "" {
+		return nil
+	}
+	return []TargetedCondition{MissingAPICondition(sets.List(missing))}
+}
+
+// TypeComposedResourceTimeout indicates whether any of a composite resource's
+// composed resources didn't become ready within the progress deadline of the
+// template that composed them.
+const TypeComposedResourceTimeout xpv1.ConditionType = "ComposedResourceTimeout"
+
+// Reasons for the ComposedResourceTimeout condition.
+const (
+	ReasonProgressDeadlineExceeded xpv1.ConditionReason = "ProgressDeadlineExceeded"
+	ReasonWithinProgressDeadline   xpv1.ConditionReason = "WithinProgressDeadline"
+)
+
+// ProgressDeadlineExceeded returns true if the supplied composed resource isn't
+// ready, and was created longer than the supplied deadline before now. It
+// returns false if there is no deadline, or the resource wasn't created yet.
+func ProgressDeadlineExceeded(cd resource.Object, ready bool, deadline *metav1.Duration, now time.Time) bool {
+	created := cd.GetCreationTimestamp()
+	if ready || deadline == nil || created.IsZero() {
+		return false
+	}
+	return now.Sub(created.Time) > deadline.Duration
+}
+
+// ComposedResourceTimeoutCondition returns a condition that lists the supplied
+// composed resources that didn't become ready within their progress deadline.
+// It's false if all composed resources are within their progress deadline.
+func ComposedResourceTimeoutCondition(names []string) TargetedCondition {
+	c := xpv1.Condition{
+		Type:               TypeComposedResourceTimeout,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonWithinProgressDeadline,
+	}
+	if len(names) > 0 {
+		c.Status = corev1.ConditionTrue
+		c.Reason = ReasonProgressDeadlineExceeded
+		c.Message = "Composed resources did not become ready within their progress deadline: " + strings.Join(names, ", ")
+	}
+	return TargetedCondition{Condition: c, Target: CompositionTargetCompositeAndClaim}
+}
+
+// timedOutConditions returns the ComposedResourceTimeout condition of the
+// supplied XR. Like missingAPIConditions it returns no conditions if no
+// composed resources timed out and the XR has never had the condition.
+func timedOutConditions(xr resource.Conditioned, timedOut sets.Set[string]) []TargetedCondition {
+	if timedOut.Len() == 0 && xr.GetCondition(TypeComposedResourceTimeout).Reason == "" {
+		return nil
+	}
+	return []TargetedCondition{ComposedResourceTimeoutCondition(sets.List(timedOut))}
+}
+
+// TypeDrifted indicates whether any of a composite resource's composed
+// resources were changed outside of Crossplane since they were last applied.
+// Drifted composed resources are reverted when they're applied.
+const TypeDrifted xpv1.ConditionType = "Drifted"
+
+// Reasons for the Drifted condition.
+const (
+	ReasonExternalChanges xpv1.ConditionReason = "ExternalChanges"
+	ReasonNoDrift         xpv1.ConditionReason = "NoDrift"
+)
+
+// AnnotationKeyCompositionDesiredHash is the key of an annotation that records
+// a hash of the spec a composed resource was last applied with.
+const AnnotationKeyCompositionDesiredHash = "crossplane.io/composition-desired-hash"
+
+// SetDesiredHash annotates the supplied composed resource with a hash of its
+// spec, so that we can tell whether its desired spec has changed the next time
+// it's composed.
+func SetDesiredHash(cd resource.Composed) error {
+	h, err := specHash(cd)
+	if err != nil {
+		return err
+	}
+	meta.AddAnnotations(cd, map[string]string{AnnotationKeyCompositionDesiredHash: h})
+	return nil
+}
+
+// DriftedPaths returns the paths of the fields of the observed composed
+// resource's spec that differ from the desired composed resource's spec. It
+// only returns paths if the desired spec is the same as the spec the observed
+// composed resource was last applied with, so that a change to the desired
+// spec isn't mistaken for a change made outside of Crossplane. Fields that are
+// set only in the observed spec, e.g. by late initialization, are ignored.
+func DriftedPaths(desired, observed resource.Composed) ([]string, error) {
+	h, err := specHash(desired)
+	if err != nil {
+		return nil, err
+	}
+	last, ok := observed.GetAnnotations()[AnnotationKeyCompositionDesiredHash]
+	if !ok || last != h {
+		return nil, nil
+	}
+	dp, err := fieldpath.PaveObject(desired)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot convert desired composed resource to unstructured")
+	}
+	op, err := fieldpath.PaveObject(observed)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot convert observed composed resource to unstructured")
+	}
+	return driftedPaths(fieldpath.Segments{fieldpath.Field("spec")}, dp.UnstructuredContent()["spec"], op.UnstructuredContent()["spec"]), nil
+}
+
+// driftedPaths recursively compares the supplied desired and observed values,
+// returning the paths of the values that differ.
+func driftedPaths(path fieldpath.Segments, desired, observed any) []string {
+	switch d := desired.(type) {
+	case map[string]any:
+		om, _ := observed.(map[string]any)
+		keys := make([]string, 0, len(d))
+		for k := range d {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		var paths []string
+		for _, k := range keys {
+			paths = append(paths, driftedPaths(append(path[:len(path):len(path)], fieldpath.Field(k)), d[k], om[k])...)
+		}
+		return paths
+	case []any:
+		oa, ok := observed.([]any)
+		if !ok {
+			return []string{path.String()}
+		}
+		return driftedElements(path, d, oa)
+	}
+
+	if !equalJSON(desired, observed) {
+		return []string{path.String()}
+	}
+	return nil
+}
+
+// listMapKeys are the fields commonly used as the merge key of a list when
+// it's server-side applied, e.g. containers are merged by name.
+var listMapKeys = []string{"name", "key"}
+
+// driftedElements compares the elements of the supplied desired and observed
+// arrays. We don't know the schema of the array, so we follow server-side
+// apply's semantics as closely as we can. Objects with a merge key are matched
+// to the observed object with the same key, regardless of order. Scalars are
+// treated as a set, matched to an equal observed scalar. Other elements are
+// matched by index. Observed elements that don't match a desired element are
+// ignored, like fields that are set only in the observed spec. If a desired
+// element is missing the path of the array itself is returned, since the
+// element has no path in the observed array.
+func driftedElements(path fieldpath.Segments, desired, observed []any) []string {
+	missing := false
+	var paths []string
+	for i, de := range desired {
+		j := i
+		switch d := de.(type) {
+		case map[string]any:
+			if k, keyed := matchElement(d, observed); keyed {
+				j = k
+			}
+		case []any:
+		default:
+			if !containsJSON(observed, de) {
+				missing = true
+			}
+			continue
+		}
+
+		if j < 0 || j >= len(observed) {
+			missing = true
+			continue
+		}
+		paths = append(paths, driftedPaths(append(path[:len(path):len(path)], fieldpath.FieldOrIndex(strconv.Itoa(j))), de, observed[j])...)
+	}
+	if missing {
+		return append([]string{path.String()}, paths...)
+	}
+	return paths
+}
+
+// matchElement returns the index of the observed object with the same merge
+// key as the supplied desired object, or -1 if there is none. It returns false
+// if the desired object doesn't have a merge key.
+func matchElement(desired map[string]any, observed []any) (int, bool) {
+	for _, k := range listMapKeys {
+		dv, ok := desired[k]
+		if !ok {
+			continue
+		}
+		for j, oe := range observed {
+			if om, ok := oe.(map[string]any); ok && equalJSON(dv, om[k]) {
+				return j, true
+			}
+		}
+		return -1, true
+	}
+	return -1, false
+}
+
+func containsJSON(observed []any, v any) bool {
+	for _, oe := range observed {
+		if equalJSON(v, oe) {
+			return true
+		}
+	}
+	return false
+}
+
+// equalJSON compares the JSON encodings of the supplied values, so that e.g.
+// an int64 and a float64 of the same value are considered equal.
+func equalJSON(a, b any) bool {
+	aj, _ := json.Marshal(a)
+	bj, _ := json.Marshal(b)
+	return string(aj) == string(bj)
+}
+
+// specHash returns a hash of the supplied composed resource's spec.
+func specHash(cd resource.Composed) (string, error) {
+	p, err := fieldpath.PaveObject(cd)
+	if err != nil {
+		return "", errors.Wrap(err, "cannot convert composed resource to unstructured")
+	}
+	// Maps are marshalled with sorted keys, so the hash is deterministic.
+	j, err := json.Marshal(p.UnstructuredContent()["spec"])
+	if err != nil {
+		return "", errors.Wrap(err, "cannot marshal composed resource spec")
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(j)), nil
+}
+
+// DriftedCondition returns a condition that lists the supplied drifted
+// composed resources and the paths of their fields that drifted. It's false if
+// no composed resources drifted.
+func DriftedCondition(drifted map[ResourceName][]string) TargetedCondition {
+	c := xpv1.Condition{
+		Type:               TypeDrifted,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNoDrift,
+	}
+	if len(drifted) > 0 {
+		names := make([]string, 0, len(drifted))
+		for name := range drifted {
+			names = append(names, string(name))
+		}
+		sort.Strings(names)
+
+		msgs := make([]string, len(names))
+		for i, name := range names {
+			msgs[i] = fmt.Sprintf("%s (%s)", name, strings.Join(drifted[ResourceName(name)], ", "))
+		}
+		c.Status = corev1.ConditionTrue
+		c.Reason = ReasonExternalChanges
+		c.Message = "Composed resources were changed outside of Crossplane and will be reverted: " + strings.Join(msgs, "; ")
+	}
+	return TargetedCondition{Condition: c, Target: CompositionTargetComposite}
+}
+
+// driftedConditions returns the Drifted condition of the supplied XR. Like
+// missingAPIConditions it returns no conditions if nothing drifted and the XR
+// has never had the condition.
+func driftedConditions(xr resource.Conditioned, drifted map[ResourceName][]string) []TargetedCondition {
+	if len(drifted) == 0 && xr.GetCondition(TypeDrifted).Reason == "" {
+		return nil
+	}
+	return []TargetedCondition{DriftedCondition(drifted)}
+}
@@ -0,0 +1,308 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestDriftedPaths(t *testing.T) {
+	desired := func() *composed.Unstructured {
+		cd := composed.New()
+		cd.SetUnstructuredContent(map[string]any{
+			"spec": map[string]any{
+				"forProvider": map[string]any{
+					"region": "us-east-2",
+					"size":   int64(3),
+					"tags":   []any{"a", "b"},
+					"rules": []any{
+						map[string]any{"name": "http", "port": int64(80)},
+						map[string]any{"name": "https", "port": int64(443)},
+					},
+				},
+			},
+		})
+		return cd
+	}
+	applied := func(spec map[string]any) *composed.Unstructured {
+		cd := desired()
+		_ = SetDesiredHash(cd)
+		cd.Object["spec"] = spec
+		return cd
+	}
+
+	type args struct {
+		desired  *composed.Unstructured
+		observed *composed.Unstructured
+	}
+	type want struct {
+		paths []string
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NeverApplied": {
+			reason: "We should not report drift if the observed resource has no desired hash annotation.",
+			args: args{
+				desired:  desired(),
+				observed: composed.New(),
+			},
+			want: want{},
+		},
+		"DesiredChanged": {
+			reason: "We should not report drift if the desired spec changed since the observed resource was last applied.",
+			args: args{
+				desired: desired(),
+				observed: func() *composed.Unstructured {
+					cd := composed.New()
+					cd.SetAnnotations(map[string]string{AnnotationKeyCompositionDesiredHash: "old"})
+					return cd
+				}(),
+			},
+			want: want{},
+		},
+		"NoDrift": {
+			reason: "We should not report drift if the observed spec matches the desired spec. Fields set only in the observed spec should be ignored.",
+			args: args{
+				desired: desired(),
+				observed: applied(map[string]any{
+					"forProvider": map[string]any{
+						"region": "us-east-2",
+						"size":   float64(3),
+						"tags":   []any{"b", "c", "a"},
+						"rules": []any{
+							map[string]any{"name": "ssh", "port": int64(22)},
+							map[string]any{"name": "https", "port": int64(443), "protocol": "TCP"},
+							map[string]any{"name": "http", "port": int64(80)},
+						},
+						"zone": "us-east-2a",
+					},
+				}),
+			},
+			want: want{},
+		},
+		"Drifted": {
+			reason: "We should return the paths of the observed fields that differ from the desired spec.",
+			args: args{
+				desired: desired(),
+				observed: applied(map[string]any{
+					"forProvider": map[string]any{
+						"size": int64(5),
+						"tags": []any{"a"},
+						"rules": []any{
+							map[string]any{"name": "https", "port": int64(8443)},
+						},
+					},
+				}),
+			},
+			want: want{
+				paths: []string{
+					"spec.forProvider.region",
+					"spec.forProvider.rules",
+					"spec.forProvider.rules[0].port",
+					"spec.forProvider.size",
+					"spec.forProvider.tags",
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			paths, err := DriftedPaths(tc.args.desired, tc.args.observed)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nDriftedPaths(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.paths, paths); diff != "" {
+				t.Errorf("\n%s\nDriftedPaths(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDriftedCondition(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		drifted map[ResourceName][]string
+		want    TargetedCondition
+	}{
+		"NoDrift": {
+			reason: "We should return a false condition if no composed resources drifted.",
+			want: TargetedCondition{
+				Condition: xpv1.Condition{
+					Type:   TypeDrifted,
+					Status: corev1.ConditionFalse,
+					Reason: ReasonNoDrift,
+				},
+				Target: CompositionTargetComposite,
+			},
+		},
+		"Drifted": {
+			reason: "We should return a true condition listing the drifted composed resources and paths, sorted by resource name.",
+			drifted: map[ResourceName][]string{
+				"db":     {"spec.forProvider.size"},
+				"bucket": {"spec.forProvider.region", "spec.forProvider.acl"},
+			},
+			want: TargetedCondition{
+				Condition: xpv1.Condition{
+					Type:    TypeDrifted,
+					Status:  corev1.ConditionTrue,
+					Reason:  ReasonExternalChanges,
+					Message: "Composed resources were changed outside of Crossplane and will be reverted: bucket (spec.forProvider.region, spec.forProvider.acl); db (spec.forProvider.size)",
+				},
+				Target: CompositionTargetComposite,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := DriftedCondition(tc.drifted)
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("\n%s\nDriftedCondition(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestProgressDeadlineExceeded(t *testing.T) {
+	now := time.Now()
+	created := func(ago time.Duration) resource.Object {
+		cd := composed.New()
+		cd.SetCreationTimestamp(metav1.NewTime(now.Add(-ago)))
+		return cd
+	}
+
+	type args struct {
+		cd       resource.Object
+		ready    bool
+		deadline *metav1.Duration
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   bool
+	}{
+		"NoDeadline": {
+			reason: "A composed resource without a progress deadline should never exceed it.",
+			args: args{
+				cd: created(time.Hour),
+			},
+			want: false,
+		},
+		"Ready": {
+			reason: "A ready composed resource should never exceed its progress deadline.",
+			args: args{
+				cd:       created(time.Hour),
+				ready:    true,
+				deadline: &metav1.Duration{Duration: time.Minute},
+			},
+			want: false,
+		},
+		"NotCreated": {
+			reason: "A composed resource that wasn't created yet shouldn't exceed its progress deadline.",
+			args: args{
+				cd:       composed.New(),
+				deadline: &metav1.Duration{Duration: time.Minute},
+			},
+			want: false,
+		},
+		"WithinDeadline": {
+			reason: "A composed resource that isn't ready shouldn't exceed its progress deadline until it passes.",
+			args: args{
+				cd:       created(time.Second),
+				deadline: &metav1.Duration{Duration: time.Minute},
+			},
+			want: false,
+		},
+		"Exceeded": {
+			reason: "A composed resource that isn't ready after its progress deadline should exceed it.",
+			args: args{
+				cd:       created(time.Hour),
+				deadline: &metav1.Duration{Duration: time.Minute},
+			},
+			want: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ProgressDeadlineExceeded(tc.args.cd, tc.args.ready, tc.args.deadline, now)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nProgressDeadlineExceeded(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestComposedResourceTimeoutCondition(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		names  []string
+		want   TargetedCondition
+	}{
+		"WithinDeadline": {
+			reason: "We should return a false condition if no composed resources timed out.",
+			want: TargetedCondition{
+				Condition: xpv1.Condition{
+					Type:   TypeComposedResourceTimeout,
+					Status: corev1.ConditionFalse,
+					Reason: ReasonWithinProgressDeadline,
+				},
+				Target: CompositionTargetCompositeAndClaim,
+			},
+		},
+		"TimedOut": {
+			reason: "We should return a true condition naming the composed resources that timed out.",
+			names:  []string{"bucket", "db"},
+			want: TargetedCondition{
+				Condition: xpv1.Condition{
+					Type:    TypeComposedResourceTimeout,
+					Status:  corev1.ConditionTrue,
+					Reason:  ReasonProgressDeadlineExceeded,
+					Message: "Composed resources did not become ready within their progress deadline: bucket, db",
+				},
+				Target: CompositionTargetCompositeAndClaim,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ComposedResourceTimeoutCondition(tc.names)
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("\n%s\nComposedResourceTimeoutCondition(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
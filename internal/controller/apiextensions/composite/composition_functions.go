@@ -19,18 +19,23 @@ import (
 	"context"
 	"crypto/sha256"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/types/known/structpb"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -46,6 +51,7 @@ import (
 	fnv1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1"
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	"github.com/crossplane/crossplane/internal/names"
+	"github.com/crossplane/crossplane/internal/xcrd"
 )
 
 // Error strings.
@@ -68,12 +74,14 @@ const (
 	errListExtraResources       = "cannot list extra resources"
 
 	errFmtApplyCD                    = "cannot apply composed resource %q"
+	errFmtDetectDrift                = "cannot detect whether composed resource %q drifted"
 	errFmtFetchCDConnectionDetails   = "cannot fetch connection details for composed resource %q (a %s named %s)"
 	errFmtUnmarshalPipelineStepInput = "cannot unmarshal input for Composition pipeline step %q"
 	errFmtGetCredentialsFromSecret   = "cannot get Composition pipeline step %q credential %q from Secret"
 	errFmtRunPipelineStep            = "cannot run Composition pipeline step %q"
 	errFmtControllerMismatch         = "refusing to delete composed resource %q that is controlled by %s %q"
 	errFmtDeleteCD                   = "cannot delete composed resource %q (a %s named %s)"
+	errFmtOrphanCD                   = "cannot orphan composed resource %q (a %s named %s)"
 	errFmtUnmarshalDesiredCD         = "cannot unmarshal desired composed resource %q from RunFunctionResponse"
 	errFmtCDAsStruct                 = "cannot encode composed resource %q to protocol buffer Struct well-known type"
 	errFmtFatalResult                = "pipeline step %q returned a fatal result: %s"
@@ -96,6 +104,17 @@ const (
 	FieldOwnerComposedPrefix = "apiextensions.crossplane.io/composed"
 )
 
+// TypeFunctionWarnings indicates whether any step of a composite resource's
+// Composition Function pipeline returned a warning result. Warnings don't stop
+// the composite resource from being composed.
+const TypeFunctionWarnings xpv1.ConditionType = "FunctionWarnings"
+
+// Reasons for the FunctionWarnings condition.
+const (
+	ReasonWarningResults   xpv1.ConditionReason = "WarningResults"
+	ReasonNoWarningResults xpv1.ConditionReason = "NoWarningResults"
+)
+
 const (
 	// FunctionContextKeyEnvironment is used to store the Composition
 	// Environment in the Function context.
@@ -108,6 +127,8 @@ type FunctionComposer struct {
 	client    client.Client
 	composite xr
 	pipeline  FunctionRunner
+
+	maxConcurrentApplies int
 }
 
 type xr struct {
@@ -159,20 +180,20 @@ func (fn ExtraResourcesFetcherFn) Fetch(ctx context.Context, rs *fnv1.ResourceSe
 	return fn(ctx, rs)
 }
 
-// A ComposedResourceGarbageCollector deletes observed composed resources that
-// are no longer desired.
+// A ComposedResourceGarbageCollector deletes or orphans observed composed
+// resources that are no longer desired, depending on the supplied policy.
 type ComposedResourceGarbageCollector interface {
-	GarbageCollectComposedResources(ctx context.Context, owner metav1.Object, observed, desired ComposedResourceStates) error
+	GarbageCollectComposedResources(ctx context.Context, owner metav1.Object, observed, desired ComposedResourceStates, p v1.RemovedResourcePolicy) error
 }
 
-// A ComposedResourceGarbageCollectorFn deletes observed composed resources that
-// are no longer desired.
-type ComposedResourceGarbageCollectorFn func(ctx context.Context, owner metav1.Object, observed, desired ComposedResourceStates) error
+// A ComposedResourceGarbageCollectorFn deletes or orphans observed composed
+// resources that are no longer desired, depending on the supplied policy.
+type ComposedResourceGarbageCollectorFn func(ctx context.Context, owner metav1.Object, observed, desired ComposedResourceStates, p v1.RemovedResourcePolicy) error
 
-// GarbageCollectComposedResources deletes observed composed resources that are
-// no longer desired.
-func (fn ComposedResourceGarbageCollectorFn) GarbageCollectComposedResources(ctx context.Context, owner metav1.Object, observed, desired ComposedResourceStates) error {
-	return fn(ctx, owner, observed, desired)
+// GarbageCollectComposedResources deletes or orphans observed composed
+// resources that are no longer desired.
+func (fn ComposedResourceGarbageCollectorFn) GarbageCollectComposedResources(ctx context.Context, owner metav1.Object, observed, desired ComposedResourceStates, p v1.RemovedResourcePolicy) error {
+	return fn(ctx, owner, observed, desired, p)
 }
 
 // A ManagedFieldsUpgrader upgrades an objects managed fields from client-side
@@ -210,6 +231,23 @@ func WithComposedResourceGarbageCollector(d ComposedResourceGarbageCollector) Fu
 	}
 }
 
+// WithComposedResourceNameGenerator configures how the FunctionComposer should
+// generate names for unnamed composed resources.
+func WithComposedResourceNameGenerator(g names.NameGenerator) FunctionComposerOption {
+	return func(c *FunctionComposer) {
+		c.composite.NameGenerator = g
+	}
+}
+
+// WithMaxConcurrentComposedResourceApplies configures how many composed
+// resources the FunctionComposer may apply at once. Values less than one mean
+// composed resources are applied one at a time.
+func WithMaxConcurrentComposedResourceApplies(n int) FunctionComposerOption {
+	return func(p *FunctionComposer) {
+		p.maxConcurrentApplies = n
+	}
+}
+
 // WithManagedFieldsUpgrader configures how the FunctionComposer should upgrade
 // composed resources managed fields from client-side apply to
 // server-side apply.
@@ -278,6 +316,7 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 
 	events := []TargetedEvent{}
 	conditions := []TargetedCondition{}
+	warnings := []string{}
 
 	// The Function context starts empty...
 	fctx := &structpb.Struct{Fields: map[string]*structpb.Value{}}
@@ -363,7 +402,8 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 		}
 
 		// Results of fatal severity stop the Composition process. Other results
-		// are accumulated to be emitted as events by the Reconciler.
+		// are accumulated to be emitted as events by the Reconciler. Warnings
+		// are also summarized in the FunctionWarnings condition.
 		for _, rs := range rsp.GetResults() {
 			reason := event.Reason(rs.GetReason())
 			if reason == "" {
@@ -374,10 +414,11 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 
 			switch rs.GetSeverity() {
 			case fnv1.Severity_SEVERITY_FATAL:
-				return CompositionResult{Events: events, Conditions: conditions}, errors.Errorf(errFmtFatalResult, fn.Step, rs.GetMessage())
+				return CompositionResult{Events: events, Conditions: append(conditions, FunctionWarningsCondition(warnings))}, errors.Errorf(errFmtFatalResult, fn.Step, rs.GetMessage())
 			case fnv1.Severity_SEVERITY_WARNING:
 				e.Event = event.Warning(reason, errors.New(rs.GetMessage()))
 				e.Detail = fmt.Sprintf("Pipeline step %q", fn.Step)
+				warnings = append(warnings, fmt.Sprintf("Pipeline step %q: %s", fn.Step, rs.GetMessage()))
 			case fnv1.Severity_SEVERITY_NORMAL:
 				e.Event = event.Normal(reason, rs.GetMessage())
 				e.Detail = fmt.Sprintf("Pipeline step %q", fn.Step)
@@ -389,11 +430,19 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 				// Explicitly target only the XR, since we're including information
 				// about an exceptional, unexpected state.
 				e.Target = CompositionTargetComposite
+				warnings = append(warnings, fmt.Sprintf("Pipeline step %q: %s", fn.Step, rs.GetMessage()))
 			}
 			events = append(events, e)
 		}
 	}
 
+	conditions = append(conditions, FunctionWarningsCondition(warnings))
+
+	// Composed resources whose API is missing, e.g. because the provider that
+	// defined it was uninstalled, can't be applied. We don't consider this an
+	// error - we skip them until their API is installed again.
+	missing := sets.New[string]()
+
 	// Load our desired composed resources from the Function pipeline.
 	desired := ComposedResourceStates{}
 	for name, dr := range d.GetResources() {
@@ -414,6 +463,14 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 		if err := RenderComposedResourceMetadata(cd, xr, ResourceName(name)); err != nil {
 			return CompositionResult{}, errors.Wrapf(err, errFmtRenderMetadata, name)
 		}
+		RenderPropagatedMetadata(cd, xr, req.Revision.Spec.PropagateMetadata)
+
+		// Record a hash of the desired spec, so that next time we compose
+		// we can tell whether changes to the composed resource were made
+		// outside of Crossplane.
+		if err := SetDesiredHash(cd); err != nil {
+			return CompositionResult{}, errors.Wrapf(err, errFmtRenderMetadata, name)
+		}
 
 		// Generate a name. We want to allocate this name before we actually
 		// create the resource so that we can persist a resourceRef to it.
@@ -423,7 +480,12 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 		// that it's taken before we create the object is low (there are 8
 		// million names).
 		if cd.GetName() == "" {
-			if err := c.composite.GenerateName(ctx, cd); err != nil {
+			err := c.composite.GenerateName(ctx, cd)
+			if kmeta.IsNoMatchError(err) {
+				missing.Insert(APIOf(cd))
+				err = nil
+			}
+			if err != nil {
 				return CompositionResult{}, errors.Wrapf(err, errFmtGenerateName, name)
 			}
 		}
@@ -443,7 +505,7 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 	// desired state. We must do this before we update the XR's resource
 	// references to ensure that we don't forget and leak them if a delete
 	// fails.
-	if err := c.composite.GarbageCollectComposedResources(ctx, xr, observed, desired); err != nil {
+	if err := c.composite.GarbageCollectComposedResources(ctx, xr, observed, desired, ptr.Deref(req.Revision.Spec.RemovedResourcePolicy, v1.RemovedResourcePolicyDelete)); err != nil {
 		return CompositionResult{}, errors.Wrap(err, errGarbageCollectCDs)
 	}
 
@@ -487,10 +549,44 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 	// Reconciler uses this array to determine whether the XR is ready.
 	resources := make([]ComposedResource, 0, len(desired))
 
+	// Composed resources that were changed outside of Crossplane since we
+	// last applied them, and the paths of the fields that were changed.
+	drifted := map[ResourceName][]string{}
+
 	// We apply all of our desired resources before we observe them in the loop
 	// below. This ensures that issues observing and processing one composed
 	// resource won't block the application of another.
+	apply := make([]ResourceName, 0, len(desired))
 	for name, cd := range desired {
+		if missing.Has(APIOf(cd.Resource)) {
+			resources = append(resources, ComposedResource{ResourceName: name, Ready: false, Synced: false})
+			continue
+		}
+
+		// Detect external changes before we revert them by applying.
+		if or, ok := observed[name]; ok {
+			paths, err := DriftedPaths(cd.Resource, or.Resource)
+			if err != nil {
+				return CompositionResult{}, errors.Wrapf(err, errFmtDetectDrift, name)
+			}
+			if len(paths) > 0 {
+				drifted[name] = paths
+			}
+		}
+
+		apply = append(apply, name)
+	}
+
+	// Resources are applied concurrently, so that compositions with many
+	// resources don't take many round trips to the API server to reconcile.
+	// We handle apply errors in name order, so that the events we emit and
+	// the error we return don't depend on which apply finished first.
+	slices.Sort(apply)
+	applyErrs := make([]error, len(apply))
+	g := &errgroup.Group{}
+	g.SetLimit(max(c.maxConcurrentApplies, 1))
+	for i, name := range apply {
+		cd := desired[name]
 		// We don't need any crossplane-runtime resource.Applicator style apply
 		// options here because server-side apply takes care of everything.
 		// Specifically it will merge rather than replace owner references (e.g.
@@ -499,7 +595,21 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 		// NOTE(phisco): We need to set a field owner unique for each XR here,
 		// this prevents multiple XRs composing the same resource to be
 		// continuously alternated as controllers.
-		if err := c.client.Patch(ctx, cd.Resource, client.Apply, client.ForceOwnership, client.FieldOwner(ComposedFieldOwnerName(xr))); err != nil {
+		g.Go(func() error {
+			applyErrs[i] = c.client.Patch(ctx, cd.Resource, client.Apply, client.ForceOwnership, client.FieldOwner(ComposedFieldOwnerName(xr)))
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	for i, name := range apply {
+		cd := desired[name]
+		if err := applyErrs[i]; err != nil {
+			if kmeta.IsNoMatchError(err) {
+				missing.Insert(APIOf(cd.Resource))
+				resources = append(resources, ComposedResource{ResourceName: name, Ready: false, Synced: false})
+				continue
+			}
 			if kerrors.IsInvalid(err) {
 				// We tried applying an invalid resource, we can't tell whether
 				// this means the resource will never be valid or it will if we
@@ -553,6 +663,9 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 		return CompositionResult{}, errors.Wrap(err, errApplyXRStatus)
 	}
 
+	conditions = append(conditions, missingAPIConditions(xr, missing)...)
+	conditions = append(conditions, driftedConditions(xr, drifted)...)
+
 	return CompositionResult{ConnectionDetails: d.GetComposite().GetConnectionDetails(), Composed: resources, Events: events, Conditions: conditions}, nil
 }
 
@@ -617,8 +730,9 @@ func (g *ExistingComposedResourceObserver) ObserveComposedResources(ctx context.
 		r := composed.New(composed.FromReference(ref))
 		nn := types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}
 		err := g.resource.Get(ctx, nn, r)
-		if kerrors.IsNotFound(err) {
-			// We believe we created this resource, but it doesn't exist.
+		if kerrors.IsNotFound(err) || kmeta.IsNoMatchError(err) {
+			// We believe we created this resource, but it doesn't exist. If
+			// its API doesn't exist either it was deleted along with the API.
 			continue
 		}
 		if err != nil {
@@ -734,7 +848,8 @@ func NewDeletingComposedResourceGarbageCollector(c client.Writer) *DeletingCompo
 // GarbageCollectComposedResources deletes any composed resource that didn't
 // come out the other end of the Composition Function pipeline (i.e. that wasn't
 // in the final desired state after running the pipeline) from the API server.
-func (d *DeletingComposedResourceGarbageCollector) GarbageCollectComposedResources(ctx context.Context, owner metav1.Object, observed, desired ComposedResourceStates) error {
+// If the supplied policy is Orphan it instead stops the owner controlling them.
+func (d *DeletingComposedResourceGarbageCollector) GarbageCollectComposedResources(ctx context.Context, owner metav1.Object, observed, desired ComposedResourceStates, p v1.RemovedResourcePolicy) error {
 	del := ComposedResourceStates{}
 	for name, cd := range observed {
 		if _, ok := desired[name]; !ok {
@@ -756,6 +871,13 @@ func (d *DeletingComposedResourceGarbageCollector) GarbageCollectComposedResourc
 			return errors.Errorf(errFmtControllerMismatch, name, c.Kind, c.Name)
 		}
 
+		if p == v1.RemovedResourcePolicyOrphan {
+			if err := orphan(ctx, d.client, owner, cd.Resource); resource.IgnoreNotFound(err) != nil {
+				return errors.Wrapf(err, errFmtOrphanCD, name, cd.Resource.GetObjectKind().GroupVersionKind().Kind, cd.Resource.GetName())
+			}
+			continue
+		}
+
 		if err := d.client.Delete(ctx, cd.Resource); resource.IgnoreNotFound(err) != nil {
 			return errors.Wrapf(err, errFmtDeleteCD, name, cd.Resource.GetObjectKind().GroupVersionKind().Kind, cd.Resource.GetName())
 		}
@@ -764,6 +886,23 @@ func (d *DeletingComposedResourceGarbageCollector) GarbageCollectComposedResourc
 	return nil
 }
 
+// orphan stops the supplied owner from owning the supplied composed resource,
+// so that it isn't deleted when its owner is. It also removes the metadata that
+// associates the composed resource with its owner, so that it isn't mistaken
+// for one of the owner's composed resources.
+func orphan(ctx context.Context, c client.Writer, owner metav1.Object, cd client.Object) error {
+	refs := make([]metav1.OwnerReference, 0, len(cd.GetOwnerReferences()))
+	for _, ref := range cd.GetOwnerReferences() {
+		if ref.UID != owner.GetUID() {
+			refs = append(refs, ref)
+		}
+	}
+	cd.SetOwnerReferences(refs)
+	meta.RemoveLabels(cd, xcrd.LabelKeyNamePrefixForComposed)
+	meta.RemoveAnnotations(cd, AnnotationKeyCompositionResourceName)
+	return c.Update(ctx, cd)
+}
+
 // UpdateResourceRefs updates the supplied state to ensure the XR references all
 // composed resources that exist or are pending creation.
 func UpdateResourceRefs(xr resource.ComposedResourcesReferencer, desired ComposedResourceStates) {
@@ -870,3 +1009,20 @@ func convertTarget(t fnv1.Target) CompositionTarget {
 	}
 	return CompositionTargetComposite
 }
+
+// FunctionWarningsCondition returns a condition that summarizes the supplied
+// warning results. It's false if there are no warnings.
+func FunctionWarningsCondition(warnings []string) TargetedCondition {
+	c := xpv1.Condition{
+		Type:               TypeFunctionWarnings,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNoWarningResults,
+	}
+	if len(warnings) > 0 {
+		c.Status = corev1.ConditionTrue
+		c.Reason = ReasonWarningResults
+		c.Message = strings.Join(warnings, "; ")
+	}
+	return TargetedCondition{Condition: c, Target: CompositionTargetComposite}
+}
@@ -27,6 +27,7 @@ import (
 	"google.golang.org/protobuf/types/known/structpb"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -349,6 +350,15 @@ func TestFunctionCompose(t *testing.T) {
 							},
 							Target: CompositionTargetCompositeAndClaim,
 						},
+						// No warnings were returned before the fatal result.
+						{
+							Condition: xpv1.Condition{
+								Type:   TypeFunctionWarnings,
+								Status: "False",
+								Reason: ReasonNoWarningResults,
+							},
+							Target: CompositionTargetComposite,
+						},
 					},
 				},
 			},
@@ -463,7 +473,7 @@ func TestFunctionCompose(t *testing.T) {
 					WithComposedResourceObserver(ComposedResourceObserverFn(func(_ context.Context, _ resource.Composite) (ComposedResourceStates, error) {
 						return nil, nil
 					})),
-					WithComposedResourceGarbageCollector(ComposedResourceGarbageCollectorFn(func(_ context.Context, _ metav1.Object, _, _ ComposedResourceStates) error {
+					WithComposedResourceGarbageCollector(ComposedResourceGarbageCollectorFn(func(_ context.Context, _ metav1.Object, _, _ ComposedResourceStates, _ v1.RemovedResourcePolicy) error {
 						return errBoom
 					})),
 				},
@@ -511,7 +521,7 @@ func TestFunctionCompose(t *testing.T) {
 					WithComposedResourceObserver(ComposedResourceObserverFn(func(_ context.Context, _ resource.Composite) (ComposedResourceStates, error) {
 						return nil, nil
 					})),
-					WithComposedResourceGarbageCollector(ComposedResourceGarbageCollectorFn(func(_ context.Context, _ metav1.Object, _, _ ComposedResourceStates) error {
+					WithComposedResourceGarbageCollector(ComposedResourceGarbageCollectorFn(func(_ context.Context, _ metav1.Object, _, _ ComposedResourceStates, _ v1.RemovedResourcePolicy) error {
 						return nil
 					})),
 				},
@@ -561,7 +571,7 @@ func TestFunctionCompose(t *testing.T) {
 					WithComposedResourceObserver(ComposedResourceObserverFn(func(_ context.Context, _ resource.Composite) (ComposedResourceStates, error) {
 						return nil, nil
 					})),
-					WithComposedResourceGarbageCollector(ComposedResourceGarbageCollectorFn(func(_ context.Context, _ metav1.Object, _, _ ComposedResourceStates) error {
+					WithComposedResourceGarbageCollector(ComposedResourceGarbageCollectorFn(func(_ context.Context, _ metav1.Object, _, _ ComposedResourceStates, _ v1.RemovedResourcePolicy) error {
 						return nil
 					})),
 				},
@@ -622,7 +632,7 @@ func TestFunctionCompose(t *testing.T) {
 					WithComposedResourceObserver(ComposedResourceObserverFn(func(_ context.Context, _ resource.Composite) (ComposedResourceStates, error) {
 						return nil, nil
 					})),
-					WithComposedResourceGarbageCollector(ComposedResourceGarbageCollectorFn(func(_ context.Context, _ metav1.Object, _, _ ComposedResourceStates) error {
+					WithComposedResourceGarbageCollector(ComposedResourceGarbageCollectorFn(func(_ context.Context, _ metav1.Object, _, _ ComposedResourceStates, _ v1.RemovedResourcePolicy) error {
 						return nil
 					})),
 				},
@@ -646,6 +656,112 @@ func TestFunctionCompose(t *testing.T) {
 				err: errors.Wrapf(errBoom, errFmtApplyCD, "uncool-resource"),
 			},
 		},
+		"MissingComposedResourceAPI": {
+			reason: "We should skip composed resources whose API is missing, report them as not ready and not synced, and return a condition listing their API.",
+			params: params{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						// The API of MissingComposed resources doesn't exist,
+						// so we can't generate a name for them.
+						if obj.GetObjectKind().GroupVersionKind().Kind == "MissingComposed" {
+							return &kmeta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "test.crossplane.io", Kind: "MissingComposed"}}
+						}
+						return kerrors.NewNotFound(schema.GroupResource{}, "")
+					}),
+					MockPatch:       test.NewMockPatchFn(nil),
+					MockStatusPatch: test.NewMockSubResourcePatchFn(nil),
+				},
+				r: FunctionRunnerFn(func(_ context.Context, _ string, _ *fnv1.RunFunctionRequest) (*fnv1.RunFunctionResponse, error) {
+					rsp := &fnv1.RunFunctionResponse{
+						Desired: &fnv1.State{
+							Composite: &fnv1.Resource{
+								Resource: MustStruct(map[string]any{}),
+							},
+							Resources: map[string]*fnv1.Resource{
+								"cool-resource": {
+									Resource: MustStruct(map[string]any{
+										"apiVersion": "test.crossplane.io/v1",
+										"kind":       "CoolComposed",
+									}),
+									Ready: fnv1.Ready_READY_TRUE,
+								},
+								"missing-resource": {
+									Resource: MustStruct(map[string]any{
+										"apiVersion": "test.crossplane.io/v1",
+										"kind":       "MissingComposed",
+									}),
+									Ready: fnv1.Ready_READY_TRUE,
+								},
+							},
+						},
+					}
+					return rsp, nil
+				}),
+				o: []FunctionComposerOption{
+					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedResourceObserver(ComposedResourceObserverFn(func(_ context.Context, _ resource.Composite) (ComposedResourceStates, error) {
+						return nil, nil
+					})),
+					WithComposedResourceGarbageCollector(ComposedResourceGarbageCollectorFn(func(_ context.Context, _ metav1.Object, _, _ ComposedResourceStates, _ v1.RemovedResourcePolicy) error {
+						return nil
+					})),
+				},
+			},
+			args: args{
+				xr: func() *composite.Unstructured {
+					xr := composite.New(composite.WithGroupVersionKind(schema.GroupVersionKind{
+						Group:   "test.crossplane.io",
+						Version: "v1",
+						Kind:    "CoolComposite",
+					}))
+					xr.SetLabels(map[string]string{
+						xcrd.LabelKeyNamePrefixForComposed: "parent-xr",
+					})
+					return xr
+				}(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Pipeline: []v1.PipelineStep{
+								{
+									Step:        "run-cool-function",
+									FunctionRef: v1.FunctionReference{Name: "cool-function"},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				res: CompositionResult{
+					Composed: []ComposedResource{
+						{ResourceName: "cool-resource", Ready: true, Synced: true},
+						{ResourceName: "missing-resource", Ready: false, Synced: false},
+					},
+					Conditions: []TargetedCondition{
+						{
+							Condition: xpv1.Condition{
+								Type:   TypeFunctionWarnings,
+								Status: corev1.ConditionFalse,
+								Reason: ReasonNoWarningResults,
+							},
+							Target: CompositionTargetComposite,
+						},
+						{
+							Condition: xpv1.Condition{
+								Type:    TypeMissingAPI,
+								Status:  corev1.ConditionTrue,
+								Reason:  ReasonMissingAPIs,
+								Message: "Composed resources with missing APIs will not be applied: MissingComposed (test.crossplane.io/v1)",
+							},
+							Target: CompositionTargetComposite,
+						},
+					},
+				},
+			},
+		},
 		"Successful": {
 			reason: "We should return a valid CompositionResult when a 'pure Function' (i.e. patch-and-transform-less) reconcile succeeds",
 			params: params{
@@ -751,7 +867,7 @@ func TestFunctionCompose(t *testing.T) {
 						}
 						return r, nil
 					})),
-					WithComposedResourceGarbageCollector(ComposedResourceGarbageCollectorFn(func(_ context.Context, _ metav1.Object, _, _ ComposedResourceStates) error {
+					WithComposedResourceGarbageCollector(ComposedResourceGarbageCollectorFn(func(_ context.Context, _ metav1.Object, _, _ ComposedResourceStates, _ v1.RemovedResourcePolicy) error {
 						return nil
 					})),
 				},
@@ -860,6 +976,16 @@ func TestFunctionCompose(t *testing.T) {
 							},
 							Target: CompositionTargetCompositeAndClaim,
 						},
+						// The warning results, summarized.
+						{
+							Condition: xpv1.Condition{
+								Type:    TypeFunctionWarnings,
+								Status:  "True",
+								Reason:  ReasonWarningResults,
+								Message: "Pipeline step \"run-cool-function\": A warning result; Pipeline step \"run-cool-function\": A result of unspecified severity",
+							},
+							Target: CompositionTargetComposite,
+						},
 					},
 				},
 				err: nil,
@@ -963,6 +1089,23 @@ func TestGetComposedResources(t *testing.T) {
 				},
 			},
 		},
+		"ComposedResourceAPINotFound": {
+			reason: "We should skip any resources whose API doesn't exist.",
+			params: params{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(&kmeta.NoKindMatchError{}),
+				},
+			},
+			args: args{
+				xr: &fake.Composite{
+					ComposedResourcesReferencer: fake.ComposedResourcesReferencer{
+						Refs: []corev1.ObjectReference{
+							{Name: "cool-resource"},
+						},
+					},
+				},
+			},
+		},
 		"GetComposedResourceError": {
 			reason: "We should return any error we encounter while getting a composed resource.",
 			params: params{
@@ -1209,6 +1352,7 @@ func TestGarbageCollectComposedResources(t *testing.T) {
 		owner    metav1.Object
 		observed ComposedResourceStates
 		desired  ComposedResourceStates
+		policy   v1.RemovedResourcePolicy
 	}
 
 	type want struct {
@@ -1315,6 +1459,84 @@ func TestGarbageCollectComposedResources(t *testing.T) {
 				err: nil,
 			},
 		},
+		"OrphanError": {
+			reason: "We should return any error encountered orphaning an undesired resource.",
+			params: params{
+				client: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(errBoom),
+				},
+			},
+			args: args{
+				owner: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						UID: "cool-xr",
+					},
+				},
+				observed: ComposedResourceStates{
+					"undesired-resource": ComposedResourceState{
+						Resource: &fake.Composed{
+							ObjectMeta: metav1.ObjectMeta{
+								Name: "undesired-resource",
+								OwnerReferences: []metav1.OwnerReference{{
+									Controller: ptr.To(true),
+									UID:        "cool-xr",
+								}},
+							},
+						},
+					},
+				},
+				policy: v1.RemovedResourcePolicyOrphan,
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtOrphanCD, "undesired-resource", "", "undesired-resource"),
+			},
+		},
+		"SuccessfulOrphan": {
+			reason: "We should orphan, rather than delete, an undesired resource if the policy is Orphan.",
+			params: params{
+				client: &test.MockClient{
+					// We know Delete wasn't called because it's nil and would
+					// panic if it was.
+					MockUpdate: test.NewMockUpdateFn(nil, func(obj client.Object) error {
+						if len(obj.GetOwnerReferences()) != 0 {
+							return errors.New("composed resource should not be owned by the XR")
+						}
+						if _, ok := obj.GetLabels()[xcrd.LabelKeyNamePrefixForComposed]; ok {
+							return errors.New("composed resource should not have the composite label")
+						}
+						if _, ok := obj.GetAnnotations()[AnnotationKeyCompositionResourceName]; ok {
+							return errors.New("composed resource should not have a composition resource name")
+						}
+						return nil
+					}),
+				},
+			},
+			args: args{
+				owner: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						UID: "cool-xr",
+					},
+				},
+				observed: ComposedResourceStates{
+					"undesired-resource": ComposedResourceState{
+						Resource: &fake.Composed{
+							ObjectMeta: metav1.ObjectMeta{
+								Labels:      map[string]string{xcrd.LabelKeyNamePrefixForComposed: "cool-xr"},
+								Annotations: map[string]string{AnnotationKeyCompositionResourceName: "undesired-resource"},
+								OwnerReferences: []metav1.OwnerReference{{
+									Controller: ptr.To(true),
+									UID:        "cool-xr",
+								}},
+							},
+						},
+					},
+				},
+				policy: v1.RemovedResourcePolicyOrphan,
+			},
+			want: want{
+				err: nil,
+			},
+		},
 		"SuccessfulNoop": {
 			reason: "We should not delete an observed resource from the API server if it is desired.",
 			params: params{
@@ -1356,7 +1578,7 @@ func TestGarbageCollectComposedResources(t *testing.T) {
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			d := NewDeletingComposedResourceGarbageCollector(tc.params.client)
-			err := d.GarbageCollectComposedResources(tc.args.ctx, tc.args.owner, tc.args.observed, tc.args.desired)
+			err := d.GarbageCollectComposedResources(tc.args.ctx, tc.args.owner, tc.args.observed, tc.args.desired, tc.args.policy)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nGarbageCollectComposedResources(...): -want, +got:\n%s", tc.reason, diff)
@@ -17,10 +17,13 @@ limitations under the License.
 package composite
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/pkg/errors"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -33,6 +36,7 @@ import (
 const (
 	errPatchSetType             = "a patch in a PatchSet cannot be of type PatchSet"
 	errCombineRequiresVariables = "combine patch types require at least one variable"
+	errMarshalConditionField    = "cannot marshal condition field value"
 
 	errFmtUndefinedPatchSet           = "cannot find PatchSet by name %s"
 	errFmtInvalidPatchType            = "patch type %s is unsupported"
@@ -40,6 +44,9 @@ const (
 	errFmtCombineConfigMissing        = "given combine strategy %s requires configuration"
 	errFmtCombineStrategyFailed       = "%s strategy could not combine"
 	errFmtExpandingArrayFieldPaths    = "cannot expand ToFieldPath %s"
+	errFmtConditionFieldPath          = "cannot get condition field path %s"
+	errFmtConditionValue              = "cannot parse condition value at index %d"
+	errFmtConditionOperator           = "patch condition operator %s is unsupported"
 )
 
 // ApplyEnvironmentPatch executes a patching operation between the cp and env objects.
@@ -110,6 +117,70 @@ func filterPatch(p v1.Patch, only ...v1.PatchType) bool {
 	return true
 }
 
+// ConditionMet returns true if the supplied composite resource meets the
+// supplied patch's condition, or if the patch has no condition.
+func ConditionMet(p v1.Patch, cp runtime.Object) (bool, error) {
+	c := p.Condition
+	if c == nil {
+		return true, nil
+	}
+
+	paved, err := fieldpath.PaveObject(cp)
+	if err != nil {
+		return false, err
+	}
+	value, err := paved.GetValue(c.FieldPath)
+	if err != nil && !fieldpath.IsNotFound(err) {
+		return false, errors.Wrapf(err, errFmtConditionFieldPath, c.FieldPath)
+	}
+	exists := err == nil
+
+	switch c.GetOperator() {
+	case v1.PatchConditionOperatorExists:
+		return exists, nil
+	case v1.PatchConditionOperatorDoesNotExist:
+		return !exists, nil
+	case v1.PatchConditionOperatorIn:
+		if !exists {
+			return false, nil
+		}
+		return conditionValuesContain(c.Values, value)
+	case v1.PatchConditionOperatorNotIn:
+		if !exists {
+			return true, nil
+		}
+		in, err := conditionValuesContain(c.Values, value)
+		return !in, err
+	}
+	return false, errors.Errorf(errFmtConditionOperator, c.Operator)
+}
+
+// conditionValuesContain returns true if any of the supplied values is equal
+// to the supplied field value. Values are compared as JSON, so that e.g. a
+// field value of int64(1) equals a condition value of 1.
+func conditionValuesContain(values []extv1.JSON, value any) (bool, error) {
+	got, err := json.Marshal(value)
+	if err != nil {
+		return false, errors.Wrap(err, errMarshalConditionField)
+	}
+	for i, v := range values {
+		var want any
+		if err := unmarshalJSON(v, &want); err != nil {
+			return false, errors.Wrapf(err, errFmtConditionValue, i)
+		}
+		// Marshalling both sides normalizes whitespace and the order of
+		// object keys.
+		w, err := json.Marshal(want)
+		if err != nil {
+			return false, errors.Wrapf(err, errFmtConditionValue, i)
+		}
+		if bytes.Equal(got, w) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // ResolveTransforms applies a list of transforms to a patch value.
 func ResolveTransforms(c v1.Patch, input any) (any, error) {
 	var err error
@@ -162,29 +233,12 @@ func ApplyFromFieldPathPatch(p v1.Patch, from, to runtime.Object) error {
 		p.ToFieldPath = p.FromFieldPath
 	}
 
-	fromMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(from)
-	if err != nil {
+	_, out, ok, err := resolveFromFieldPathPatch(p, from)
+	if err != nil || !ok {
 		return err
 	}
 
-	in, err := fieldpath.Pave(fromMap).GetValue(*p.FromFieldPath)
-	if IsOptionalFieldPathNotFound(err, p.Policy) {
-		return nil
-	}
-	if err != nil {
-		return err
-	}
-
-	var mo *xpv1.MergeOptions
-	if p.Policy != nil {
-		mo = p.Policy.MergeOptions
-	}
-
-	// Apply transform pipeline
-	out, err := ResolveTransforms(p, in)
-	if err != nil {
-		return err
-	}
+	mo := p.Policy.GetMergeOptions()
 
 	// Patch all expanded fields if the ToFieldPath contains wildcards
 	if strings.Contains(*p.ToFieldPath, "[*]") {
@@ -215,12 +269,51 @@ func ApplyCombineFromVariablesPatch(p v1.Patch, from, to runtime.Object) error {
 		return errors.New(errCombineRequiresVariables)
 	}
 
+	_, out, ok, err := resolveCombineFromVariablesPatch(p, from)
+	if err != nil || !ok {
+		return err
+	}
+
+	return patchFieldValueToObject(*p.ToFieldPath, out, to, nil)
+}
+
+// resolveFromFieldPathPatch returns the input value of the supplied patch,
+// read from the "from" resource, and its output value after transforms. It
+// returns false if the patch's optional source field was not found.
+func resolveFromFieldPathPatch(p v1.Patch, from runtime.Object) (in, out any, ok bool, err error) {
 	fromMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(from)
 	if err != nil {
-		return err
+		return nil, nil, false, err
+	}
+
+	in, err = fieldpath.Pave(fromMap).GetValue(*p.FromFieldPath)
+	if IsOptionalFieldPathNotFound(err, p.Policy) {
+		return nil, nil, false, nil
+	}
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	// Apply transform pipeline
+	out, err = ResolveTransforms(p, in)
+	if err != nil {
+		return in, nil, false, err
 	}
 
-	in := make([]any, vl)
+	return in, out, true, nil
+}
+
+// resolveCombineFromVariablesPatch returns the input variables of the supplied
+// patch, read from the "from" resource, and its output value after they're
+// combined and transformed. It returns false if any of the patch's optional
+// source fields were not found.
+func resolveCombineFromVariablesPatch(p v1.Patch, from runtime.Object) (in []any, out any, ok bool, err error) {
+	fromMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(from)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	in = make([]any, len(p.Combine.Variables))
 
 	// Get value of each variable
 	// NOTE: This currently assumes all variables define a 'fromFieldPath'
@@ -236,10 +329,10 @@ func ApplyCombineFromVariablesPatch(p v1.Patch, from, to runtime.Object) error {
 		// expecting 3 fields '%s-%s-%s' but only
 		// receiving 2 values).
 		if IsOptionalFieldPathNotFound(err, p.Policy) {
-			return nil
+			return nil, nil, false, nil
 		}
 		if err != nil {
-			return err
+			return nil, nil, false, err
 		}
 		in[i] = iv
 	}
@@ -247,16 +340,16 @@ func ApplyCombineFromVariablesPatch(p v1.Patch, from, to runtime.Object) error {
 	// Combine input values
 	cb, err := Combine(*p.Combine, in)
 	if err != nil {
-		return err
+		return in, nil, false, err
 	}
 
 	// Apply transform pipeline
-	out, err := ResolveTransforms(p, cb)
+	out, err = ResolveTransforms(p, cb)
 	if err != nil {
-		return err
+		return in, nil, false, err
 	}
 
-	return patchFieldValueToObject(*p.ToFieldPath, out, to, nil)
+	return in, out, true, nil
 }
 
 // IsOptionalFieldPathNotFound returns true if the supplied error indicates a
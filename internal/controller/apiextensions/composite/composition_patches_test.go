@@ -25,6 +25,7 @@ import (
 	"github.com/pkg/errors"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/utils/ptr"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -600,6 +601,87 @@ func TestPatchApply(t *testing.T) {
 				},
 			},
 		},
+		"ToCompositeFieldPathPatchForceMergeObjects": {
+			reason: "Should merge an object into the composite's object, overwriting fields that are already set",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeToCompositeFieldPath,
+					FromFieldPath: ptr.To("objectMeta.labels"),
+					Policy: &v1.PatchPolicy{
+						ToFieldPath: ptr.To(v1.ToFieldPathPolicyForceMergeObjects),
+					},
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cp",
+						Labels: map[string]string{
+							"Test":     "old",
+							"Existing": "kept",
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cd",
+						Labels: map[string]string{
+							"Test": "blah",
+						},
+					},
+				},
+			},
+			want: want{
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cp",
+						Labels: map[string]string{
+							"Test":     "blah",
+							"Existing": "kept",
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+			},
+		},
+		"ToCompositeFieldPathPatchAppendArrays": {
+			reason: "Should append array elements the composite doesn't already have",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeToCompositeFieldPath,
+					FromFieldPath: ptr.To("objectMeta.ownerReferences"),
+					Policy: &v1.PatchPolicy{
+						ToFieldPath: ptr.To(v1.ToFieldPathPolicyForceMergeObjectsAppendArrays),
+					},
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						OwnerReferences: []metav1.OwnerReference{
+							{Name: "a", APIVersion: "v1"},
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						OwnerReferences: []metav1.OwnerReference{
+							{Name: "a", APIVersion: "v1"},
+							{Name: "b", APIVersion: "v1"},
+						},
+					},
+				},
+			},
+			want: want{
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						OwnerReferences: []metav1.OwnerReference{
+							{Name: "a", APIVersion: "v1"},
+							{Name: "b", APIVersion: "v1"},
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+			},
+		},
 		"ValidToEnvironmentFieldPathPatch": {
 			reason: "Should correctly apply a ToEnvironmentFieldPath patch with valid settings",
 			args: args{
@@ -1180,6 +1262,137 @@ func TestOptionalFieldPathNotFound(t *testing.T) {
 	}
 }
 
+func TestConditionMet(t *testing.T) {
+	xr := &unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{
+			"parameters": map[string]any{
+				"tier":     "prod",
+				"replicas": int64(3),
+				"labels":   map[string]any{"a": "b", "c": "d"},
+			},
+		},
+	}}
+
+	type want struct {
+		met bool
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      *v1.PatchCondition
+		want   want
+	}{
+		"NoCondition": {
+			reason: "A patch without a condition should always be applied.",
+			want:   want{met: true},
+		},
+		"InDefault": {
+			reason: "The default operator should match a field equal to one of the values.",
+			c: &v1.PatchCondition{
+				FieldPath: "spec.parameters.tier",
+				Values:    []extv1.JSON{{Raw: []byte(`"dev"`)}, {Raw: []byte(`"prod"`)}},
+			},
+			want: want{met: true},
+		},
+		"InNotEqual": {
+			reason: "The In operator shouldn't match a field that isn't equal to any of the values.",
+			c: &v1.PatchCondition{
+				FieldPath: "spec.parameters.tier",
+				Operator:  v1.PatchConditionOperatorIn,
+				Values:    []extv1.JSON{{Raw: []byte(`"dev"`)}},
+			},
+			want: want{met: false},
+		},
+		"InNotFound": {
+			reason: "The In operator shouldn't match a field that doesn't exist.",
+			c: &v1.PatchCondition{
+				FieldPath: "spec.parameters.region",
+				Values:    []extv1.JSON{{Raw: []byte(`"us-east-1"`)}},
+			},
+			want: want{met: false},
+		},
+		"InNumber": {
+			reason: "The In operator should compare numbers as JSON.",
+			c: &v1.PatchCondition{
+				FieldPath: "spec.parameters.replicas",
+				Values:    []extv1.JSON{{Raw: []byte(`3`)}},
+			},
+			want: want{met: true},
+		},
+		"InObject": {
+			reason: "The In operator should compare objects regardless of key order and whitespace.",
+			c: &v1.PatchCondition{
+				FieldPath: "spec.parameters.labels",
+				Values:    []extv1.JSON{{Raw: []byte(`{"c": "d", "a": "b"}`)}},
+			},
+			want: want{met: true},
+		},
+		"NotIn": {
+			reason: "The NotIn operator should match a field that isn't equal to any of the values.",
+			c: &v1.PatchCondition{
+				FieldPath: "spec.parameters.tier",
+				Operator:  v1.PatchConditionOperatorNotIn,
+				Values:    []extv1.JSON{{Raw: []byte(`"dev"`)}},
+			},
+			want: want{met: true},
+		},
+		"NotInNotFound": {
+			reason: "The NotIn operator should match a field that doesn't exist.",
+			c: &v1.PatchCondition{
+				FieldPath: "spec.parameters.region",
+				Operator:  v1.PatchConditionOperatorNotIn,
+				Values:    []extv1.JSON{{Raw: []byte(`"us-east-1"`)}},
+			},
+			want: want{met: true},
+		},
+		"Exists": {
+			reason: "The Exists operator should match a field that exists.",
+			c: &v1.PatchCondition{
+				FieldPath: "spec.parameters.tier",
+				Operator:  v1.PatchConditionOperatorExists,
+			},
+			want: want{met: true},
+		},
+		"DoesNotExist": {
+			reason: "The DoesNotExist operator shouldn't match a field that exists.",
+			c: &v1.PatchCondition{
+				FieldPath: "spec.parameters.tier",
+				Operator:  v1.PatchConditionOperatorDoesNotExist,
+			},
+			want: want{met: false},
+		},
+		"InvalidValue": {
+			reason: "We should return an error if a value isn't valid JSON.",
+			c: &v1.PatchCondition{
+				FieldPath: "spec.parameters.tier",
+				Values:    []extv1.JSON{{Raw: []byte(`nope`)}},
+			},
+			want: want{err: errors.Wrapf(json.Unmarshal([]byte(`nope`), new(any)), errFmtConditionValue, 0)},
+		},
+		"UnknownOperator": {
+			reason: "We should return an error if the operator is unknown.",
+			c: &v1.PatchCondition{
+				FieldPath: "spec.parameters.tier",
+				Operator:  "Like",
+			},
+			want: want{err: errors.Errorf(errFmtConditionOperator, "Like")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			met, err := ConditionMet(v1.Patch{Condition: tc.c}, xr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nConditionMet(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.met, met); diff != "" {
+				t.Errorf("\n%s\nConditionMet(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestComposedTemplates(t *testing.T) {
 	asJSON := func(val interface{}) extv1.JSON {
 		raw, err := json.Marshal(val)
@@ -19,11 +19,15 @@ package composite
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"golang.org/x/sync/errgroup"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -42,10 +46,11 @@ import (
 
 // Error strings.
 const (
-	errGetComposed  = "cannot get composed resource"
-	errGCComposed   = "cannot garbage collect composed resource"
-	errFetchDetails = "cannot fetch connection details"
-	errInline       = "cannot inline Composition patch sets"
+	errGetComposed    = "cannot get composed resource"
+	errGCComposed     = "cannot garbage collect composed resource"
+	errOrphanComposed = "cannot orphan composed resource"
+	errFetchDetails   = "cannot fetch connection details"
+	errInline         = "cannot inline Composition patch sets"
 
 	errFmtApplyComposed              = "cannot apply composed resource %q"
 	errFmtPatchEnvironment           = "cannot apply environment patch at index %d"
@@ -56,6 +61,8 @@ const (
 	errFmtGenerateName               = "cannot generate a name for composed resource %q"
 	errFmtExtractDetails             = "cannot extract composite resource connection details from composed resource %q"
 	errFmtCheckReadiness             = "cannot check whether composed resource %q is ready"
+	errFmtGetPausedComposed          = "cannot get paused composed resource %q"
+	errFmtGetObservedComposed        = "cannot get observed composed resource %q"
 )
 
 // TODO(negz): Move P&T Composition logic into its own package?
@@ -71,6 +78,15 @@ func WithTemplateAssociator(a CompositionTemplateAssociator) PTComposerOption {
 	}
 }
 
+// WithComposedResourceClient configures the client a PatchAndTransformComposer
+// uses to read and apply composed resources. The composite resource is still
+// read and written using the client the PTComposer was created with.
+func WithComposedResourceClient(kube client.Client) PTComposerOption {
+	return func(c *PTComposer) {
+		c.composedClient = resource.ClientApplicator{Client: kube, Applicator: resource.NewAPIPatchingApplicator(kube)}
+	}
+}
+
 // WithComposedNameGenerator configures how the PTComposer should generate names
 // for unnamed composed resources.
 func WithComposedNameGenerator(r names.NameGenerator) PTComposerOption {
@@ -104,6 +120,24 @@ func WithComposedConnectionDetailsExtractor(e ConnectionDetailsExtractor) PTComp
 	}
 }
 
+// WithPatchTraceWriter configures where a PatchAndTransformComposer writes
+// the patch traces of composite resources that ask for their patches to be
+// debugged.
+func WithPatchTraceWriter(w PatchTraceWriter) PTComposerOption {
+	return func(c *PTComposer) {
+		c.traces = w
+	}
+}
+
+// WithMaxConcurrentComposedApplies configures how many composed resources a
+// PTComposer may apply at once. Values less than one mean composed resources
+// are applied one at a time.
+func WithMaxConcurrentComposedApplies(n int) PTComposerOption {
+	return func(c *PTComposer) {
+		c.maxConcurrentApplies = n
+	}
+}
+
 type composedResource struct {
 	names.NameGenerator
 	managed.ConnectionDetailsFetcher
@@ -116,17 +150,23 @@ type composedResource struct {
 // along with a series of patches and transforms. It does not support Functions
 // - any entries in the functions array are ignored.
 type PTComposer struct {
-	client resource.ClientApplicator
+	client         resource.ClientApplicator
+	composedClient resource.ClientApplicator
 
 	composition CompositionTemplateAssociator
 	composed    composedResource
+	traces      PatchTraceWriter
+
+	maxConcurrentApplies int
 }
 
 // NewPTComposer returns a Composer that composes resources using Patch and
 // Transform (P&T) Composition - a Composition's bases, patches, and transforms.
 func NewPTComposer(kube client.Client, o ...PTComposerOption) *PTComposer {
+	ca := resource.ClientApplicator{Client: kube, Applicator: resource.NewAPIPatchingApplicator(kube)}
 	c := &PTComposer{
-		client: resource.ClientApplicator{Client: kube, Applicator: resource.NewAPIPatchingApplicator(kube)},
+		client:         ca,
+		composedClient: ca,
 
 		composition: NewGarbageCollectingAssociator(kube),
 		composed: composedResource{
@@ -135,6 +175,7 @@ func NewPTComposer(kube client.Client, o ...PTComposerOption) *PTComposer {
 			ConnectionDetailsFetcher:   NewSecretConnectionDetailsFetcher(kube),
 			ConnectionDetailsExtractor: ConnectionDetailsExtractorFn(ExtractConnectionDetails),
 		},
+		traces: NopPatchTraceWriter{},
 	}
 
 	for _, fn := range o {
@@ -168,7 +209,7 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 	// strictly by order. If we're using a Composition with named resource
 	// templates we'll be able to instead read the template name annotation from
 	// the composed resources to make the annotation.
-	tas, err := c.composition.AssociateTemplates(ctx, xr, ct)
+	tas, err := c.composition.AssociateTemplates(ctx, xr, ct, ptr.Deref(req.Revision.Spec.RemovedResourcePolicy, v1.RemovedResourcePolicyDelete))
 	if err != nil {
 		return CompositionResult{}, errors.Wrap(err, errAssociate)
 	}
@@ -185,6 +226,18 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 
 	events := make([]TargetedEvent, 0)
 
+	// Composed resources whose API is missing, e.g. because the provider that
+	// defined it was uninstalled, can't be applied. We don't consider this an
+	// error - we skip them until their API is installed again.
+	missing := sets.New[string]()
+
+	// Trace how each patch is evaluated if the XR asks us to. This lets folks
+	// debug the patches of a single XR without raising the log level.
+	var traces *PatchTraces
+	if DebugPatches(xr) {
+		traces = &PatchTraces{}
+	}
+
 	// We optimistically render all composed resources that we are able to with
 	// the expectation that any that we fail to render will subsequently have
 	// their error corrected by manual intervention or propagation of a required
@@ -192,6 +245,10 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 	// process.
 	refs := make([]corev1.ObjectReference, len(tas))
 	cds := make([]resource.Composed, len(tas))
+
+	// Composed resources that were changed outside of Crossplane since we
+	// last applied them, and the paths of the fields that were changed.
+	drifted := map[ResourceName][]string{}
 	for i := range tas {
 		ta := tas[i]
 
@@ -199,6 +256,25 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 		name := ptr.Deref(ta.Template.Name, fmt.Sprintf("resource %d", i+1))
 		r := composed.New(composed.FromReference(ta.Reference))
 
+		// We don't render or apply paused composed resources. We keep our
+		// reference to an existing paused resource so that we don't lose
+		// track of it, and observe it as it is.
+		if ta.Template.IsPaused() {
+			refs[i] = ta.Reference
+			if ta.Reference.Name == "" {
+				continue
+			}
+			err := c.composedClient.Get(ctx, types.NamespacedName{Namespace: ta.Reference.Namespace, Name: ta.Reference.Name}, r)
+			if kerrors.IsNotFound(err) || kmeta.IsNoMatchError(err) {
+				continue
+			}
+			if err != nil {
+				return CompositionResult{}, errors.Wrapf(err, errFmtGetPausedComposed, name)
+			}
+			cds[i] = r
+			continue
+		}
+
 		if err := RenderFromJSON(r, ta.Template.Base.Raw); err != nil {
 			// We consider this a terminal error, since it indicates a broken
 			// CompositionRevision that will never be valid.
@@ -212,7 +288,7 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 		// unblock it.
 
 		rendered := true
-		if err := RenderFromCompositeAndEnvironmentPatches(r, xr, req.Environment, ta.Template.Patches); err != nil {
+		if err := RenderFromCompositeAndEnvironmentPatches(r, xr, req.Environment, ta.Template.Patches, traces.For(ResourceName(name))); err != nil {
 			events = append(events, TargetedEvent{
 				Event:  event.Warning(reasonCompose, errors.Wrapf(err, errFmtRenderFromCompositePatches, name)),
 				Target: CompositionTargetComposite,
@@ -227,8 +303,12 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 			})
 			rendered = false
 		}
+		RenderPropagatedMetadata(r, xr, req.Revision.Spec.PropagateMetadata)
 
-		if err := c.composed.GenerateName(ctx, r); err != nil {
+		if err := c.composed.GenerateName(ctx, r); kmeta.IsNoMatchError(err) {
+			missing.Insert(APIOf(r))
+			rendered = false
+		} else if err != nil {
 			events = append(events, TargetedEvent{
 				Event:  event.Warning(reasonCompose, errors.Wrapf(err, errFmtGenerateName, name)),
 				Target: CompositionTargetComposite,
@@ -245,9 +325,42 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 		refs[i] = *meta.ReferenceTo(r, r.GetObjectKind().GroupVersionKind())
 
 		// We only need the composed resource if it rendered correctly.
-		if rendered {
-			cds[i] = r
+		if !rendered {
+			continue
+		}
+
+		// Detect changes made to an existing composed resource outside of
+		// Crossplane before we revert them by applying.
+		if ta.Reference.Name != "" {
+			or := composed.New(composed.FromReference(ta.Reference))
+			err := c.composedClient.Get(ctx, types.NamespacedName{Namespace: ta.Reference.Namespace, Name: ta.Reference.Name}, or)
+			switch {
+			case kerrors.IsNotFound(err) || kmeta.IsNoMatchError(err):
+			case err != nil:
+				return CompositionResult{}, errors.Wrapf(err, errFmtGetObservedComposed, name)
+			default:
+				paths, err := DriftedPaths(r, or)
+				if err != nil {
+					return CompositionResult{}, errors.Wrapf(err, errFmtDetectDrift, name)
+				}
+				if len(paths) > 0 {
+					drifted[ResourceName(name)] = paths
+				}
+			}
+		}
+
+		// Record a hash of the desired spec, so that next time we compose we
+		// can tell whether changes to the composed resource were made outside
+		// of Crossplane.
+		if err := SetDesiredHash(r); err != nil {
+			events = append(events, TargetedEvent{
+				Event:  event.Warning(reasonCompose, errors.Wrapf(err, errFmtRenderMetadata, name)),
+				Target: CompositionTargetComposite,
+			})
+			continue
 		}
+
+		cds[i] = r
 	}
 
 	// We persist references to our composed resources before we create
@@ -261,7 +374,12 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 
 	// We apply all of our composed resources before we observe them in the
 	// loop below. This ensures that issues observing and processing one
-	// composed resource won't block the application of another.
+	// composed resource won't block the application of another. Resources are
+	// applied concurrently, so that compositions with many resources don't
+	// take many round trips to the API server to reconcile.
+	applyErrs := make([]error, len(tas))
+	g := &errgroup.Group{}
+	g.SetLimit(max(c.maxConcurrentApplies, 1))
 	for i := range tas {
 		t := tas[i].Template
 		cd := cds[i]
@@ -269,37 +387,58 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 		// If we were unable to render the composed resource we should not try
 		// and apply it. The risk of doing so is that we successfully apply a
 		// partially-rendered composed resource that we can't later fix (e.g.
-		// due to an immutable field).
-		if cd == nil {
+		// due to an immutable field). Paused resources must not be touched.
+		if cd == nil || t.IsPaused() {
 			continue
 		}
 
 		o := []resource.ApplyOption{resource.MustBeControllableBy(xr.GetUID()), usage.RespectOwnerRefs()}
 		o = append(o, mergeOptions(filterPatches(t.Patches, patchTypesFromXR()...))...)
-		if err := c.client.Apply(ctx, cd, o...); err != nil {
-			if kerrors.IsInvalid(err) {
-				// We tried applying an invalid resource, we can't tell whether
-				// this means the resource will never be valid or it will if we
-				// run again the composition after some other resource is
-				// created or updated successfully. So, we emit a warning event
-				// and move on.
-				events = append(events, TargetedEvent{
-					Event:  event.Warning(reasonCompose, errors.Wrapf(err, errFmtApplyComposed, ptr.Deref(t.Name, fmt.Sprintf("%d", i+1)))),
-					Target: CompositionTargetComposite,
-				})
-				// We unset the cd here so that we don't try to observe it
-				// later. This will also mean we report it as not ready and not
-				// synced. Resulting in the XR being reported as not ready nor
-				// synced too.
-				cds[i] = nil
-				continue
-			}
+		g.Go(func() error {
+			applyErrs[i] = c.composedClient.Apply(ctx, cd, o...)
+			return nil
+		})
+	}
+	_ = g.Wait()
 
-			// TODO(negz): Include the template name (if any) in this error.
-			// Including the rendered resource's kind may help too (e.g. if the
-			// template is anonymous).
-			return CompositionResult{}, errors.Wrapf(err, errFmtApplyComposed, ptr.Deref(t.Name, fmt.Sprintf("%d", i+1)))
+	// We handle apply errors in template order, so that the events we emit
+	// and the error we return don't depend on which apply finished first.
+	for i, err := range applyErrs {
+		if err == nil {
+			continue
 		}
+		t := tas[i].Template
+		cd := cds[i]
+
+		if kmeta.IsNoMatchError(err) {
+			// Like invalid resources below, we report resources whose
+			// API is missing as not ready and not synced.
+			missing.Insert(APIOf(cd))
+			cds[i] = nil
+			continue
+		}
+		if kerrors.IsInvalid(err) {
+			// We tried applying an invalid resource, we can't tell whether
+			// this means the resource will never be valid or it will if we
+			// run again the composition after some other resource is
+			// created or updated successfully. So, we emit a warning event
+			// and move on.
+			events = append(events, TargetedEvent{
+				Event:  event.Warning(reasonCompose, errors.Wrapf(err, errFmtApplyComposed, ptr.Deref(t.Name, fmt.Sprintf("%d", i+1)))),
+				Target: CompositionTargetComposite,
+			})
+			// We unset the cd here so that we don't try to observe it
+			// later. This will also mean we report it as not ready and not
+			// synced. Resulting in the XR being reported as not ready nor
+			// synced too.
+			cds[i] = nil
+			continue
+		}
+
+		// TODO(negz): Include the template name (if any) in this error.
+		// Including the rendered resource's kind may help too (e.g. if the
+		// template is anonymous).
+		return CompositionResult{}, errors.Wrapf(err, errFmtApplyComposed, ptr.Deref(t.Name, fmt.Sprintf("%d", i+1)))
 	}
 
 	// Produce our array of resources to return to the Reconciler. The
@@ -308,6 +447,7 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 	// in tas - i.e. a resources resource for every resource template.
 	resources := make([]ComposedResource, len(tas))
 	xrConnDetails := managed.ConnectionDetails{}
+	timedOut := sets.New[string]()
 	for i := range tas {
 		t := tas[i].Template
 		cd := cds[i]
@@ -324,10 +464,21 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 			continue
 		}
 
-		if err := RenderToCompositePatches(xr, cd, t.Patches); err != nil {
+		// We only observe paused composed resources; we don't patch the XR
+		// from them.
+		patches := t.Patches
+		if t.IsPaused() {
+			patches = nil
+		}
+
+		if err := RenderToCompositePatches(xr, cd, patches, traces.For(name)); err != nil {
 			// Failures to render ToComposite patches are terminal because this
 			// indicates a Required ToCompositeFieldPath patch failed; i.e. the
-			// composite was _required_ to be patched, but wasn't.
+			// composite was _required_ to be patched, but wasn't. We still
+			// write any patch traces, since they may explain the failure.
+			if traces != nil {
+				_ = c.traces.WritePatchTraces(ctx, xr, traces.Traces)
+			}
 			return CompositionResult{}, errors.Wrapf(err, errFmtRenderToCompositePatches, name)
 		}
 
@@ -350,9 +501,22 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 			return CompositionResult{}, errors.Wrapf(err, errFmtCheckReadiness, name)
 		}
 
+		if ProgressDeadlineExceeded(cd, ready, t.ProgressDeadline, time.Now()) {
+			timedOut.Insert(string(name))
+		}
+
 		resources[i] = ComposedResource{ResourceName: name, Ready: ready, Synced: true}
 	}
 
+	if traces != nil {
+		if err := c.traces.WritePatchTraces(ctx, xr, traces.Traces); err != nil {
+			events = append(events, TargetedEvent{
+				Event:  event.Warning(reasonCompose, err),
+				Target: CompositionTargetComposite,
+			})
+		}
+	}
+
 	// Call Apply so that we do not just replace fields on existing XR but
 	// merge fields for which a merge configuration has been specified. For
 	// fields for which a merge configuration does not exist, the behavior
@@ -371,7 +535,9 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 		return CompositionResult{}, errors.Wrap(err, errUpdate)
 	}
 
-	return CompositionResult{ConnectionDetails: xrConnDetails, Composed: resources, Events: events}, nil
+	conditions := append(missingAPIConditions(xr, missing), timedOutConditions(xr, timedOut)...)
+	conditions = append(conditions, driftedConditions(xr, drifted)...)
+	return CompositionResult{ConnectionDetails: xrConnDetails, Composed: resources, Events: events, Conditions: conditions}, nil
 }
 
 // toXRPatchesFromTAs selects patches defined in composed templates,
@@ -431,16 +597,18 @@ func AssociateByOrder(t []v1.ComposedTemplate, r []corev1.ObjectReference) []Tem
 }
 
 // A CompositionTemplateAssociator returns an array of template associations.
+// The supplied policy specifies what should happen to any composed resource
+// that can't be associated with a template.
 type CompositionTemplateAssociator interface {
-	AssociateTemplates(ctx context.Context, xr resource.Composite, cts []v1.ComposedTemplate) ([]TemplateAssociation, error)
+	AssociateTemplates(ctx context.Context, xr resource.Composite, cts []v1.ComposedTemplate, p v1.RemovedResourcePolicy) ([]TemplateAssociation, error)
 }
 
 // A CompositionTemplateAssociatorFn returns an array of template associations.
-type CompositionTemplateAssociatorFn func(context.Context, resource.Composite, []v1.ComposedTemplate) ([]TemplateAssociation, error)
+type CompositionTemplateAssociatorFn func(context.Context, resource.Composite, []v1.ComposedTemplate, v1.RemovedResourcePolicy) ([]TemplateAssociation, error)
 
 // AssociateTemplates with composed resources.
-func (fn CompositionTemplateAssociatorFn) AssociateTemplates(ctx context.Context, cr resource.Composite, ct []v1.ComposedTemplate) ([]TemplateAssociation, error) {
-	return fn(ctx, cr, ct)
+func (fn CompositionTemplateAssociatorFn) AssociateTemplates(ctx context.Context, cr resource.Composite, ct []v1.ComposedTemplate, p v1.RemovedResourcePolicy) ([]TemplateAssociation, error) {
+	return fn(ctx, cr, ct, p)
 }
 
 // A GarbageCollectingAssociator associates a Composition's resource templates
@@ -449,7 +617,7 @@ func (fn CompositionTemplateAssociatorFn) AssociateTemplates(ctx context.Context
 // template or existing composed resource can't be associated by name it falls
 // back to associating them by order. If it encounters a referenced resource
 // that corresponds to a non-existent template the resource will be garbage
-// collected (i.e. deleted).
+// collected, i.e. deleted or orphaned depending on the supplied policy.
 type GarbageCollectingAssociator struct {
 	client client.Client
 }
@@ -461,7 +629,7 @@ func NewGarbageCollectingAssociator(c client.Client) *GarbageCollectingAssociato
 }
 
 // AssociateTemplates with composed resources.
-func (a *GarbageCollectingAssociator) AssociateTemplates(ctx context.Context, cr resource.Composite, ct []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+func (a *GarbageCollectingAssociator) AssociateTemplates(ctx context.Context, cr resource.Composite, ct []v1.ComposedTemplate, p v1.RemovedResourcePolicy) ([]TemplateAssociation, error) {
 	templates := map[ResourceName]int{}
 	for i, t := range ct {
 		if t.Name == nil {
@@ -487,8 +655,9 @@ func (a *GarbageCollectingAssociator) AssociateTemplates(ctx context.Context, cr
 		nn := types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}
 		err := a.client.Get(ctx, nn, cd)
 
-		// We believe we created this resource, but it no longer exists.
-		if kerrors.IsNotFound(err) {
+		// We believe we created this resource, but it no longer exists. If its
+		// API no longer exists either it was deleted along with the API.
+		if kerrors.IsNotFound(err) || kmeta.IsNoMatchError(err) {
 			continue
 		}
 
@@ -531,6 +700,12 @@ func (a *GarbageCollectingAssociator) AssociateTemplates(ctx context.Context, cr
 
 		// This existing resource does not correspond to an extant template. It
 		// should be garbage collected.
+		if p == v1.RemovedResourcePolicyOrphan {
+			if err := orphan(ctx, a.client, cr, cd); resource.IgnoreNotFound(err) != nil {
+				return nil, errors.Wrap(err, errOrphanComposed)
+			}
+			continue
+		}
 		if err := a.client.Delete(ctx, cd); resource.IgnoreNotFound(err) != nil {
 			return nil, errors.Wrap(err, errGCComposed)
 		}
@@ -18,12 +18,15 @@ package composite
 
 import (
 	"context"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -31,11 +34,13 @@ import (
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
@@ -48,6 +53,34 @@ func TestPTCompose(t *testing.T) {
 	details := managed.ConnectionDetails{"a": []byte("b")}
 	base := runtime.RawExtension{Raw: []byte(`{"apiVersion":"test.crossplane.io/v1","kind":"ComposedResource"}`)}
 
+	// A composed resource whose spec was last applied with the desired spec
+	// of a template, but was since changed outside of Crossplane.
+	sized := runtime.RawExtension{Raw: []byte(`{"apiVersion":"test.crossplane.io/v1","kind":"ComposedResource","spec":{"size":"large"}}`)}
+	desired := composed.New()
+	if err := RenderFromJSON(desired, sized.Raw); err != nil {
+		t.Fatalf("RenderFromJSON(...): %v", err)
+	}
+	if err := SetDesiredHash(desired); err != nil {
+		t.Fatalf("SetDesiredHash(...): %v", err)
+	}
+	changed := func(obj client.Object) error {
+		if obj.GetObjectKind().GroupVersionKind().Kind != "ComposedResource" {
+			return nil
+		}
+		obj.SetAnnotations(desired.GetAnnotations())
+		obj.(*composed.Unstructured).Object["spec"] = map[string]any{"size": "small"}
+		return nil
+	}
+
+	// Used to check that composed resources are applied concurrently.
+	applying := &sync.WaitGroup{}
+	applying.Add(3)
+	allApplying := make(chan struct{})
+	go func() {
+		applying.Wait()
+		close(allApplying)
+	}()
+
 	type params struct {
 		kube client.Client
 		o    []PTComposerOption
@@ -94,7 +127,7 @@ func TestPTCompose(t *testing.T) {
 			reason: "We should return any error encountered while associating Composition templates with composed resources.",
 			params: params{
 				o: []PTComposerOption{
-					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate, _ v1.RemovedResourcePolicy) ([]TemplateAssociation, error) {
 						return nil, errBoom
 					})),
 				},
@@ -112,7 +145,7 @@ func TestPTCompose(t *testing.T) {
 			reason: "We should return any error encountered while parsing a composed resource base template",
 			params: params{
 				o: []PTComposerOption{
-					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate, _ v1.RemovedResourcePolicy) ([]TemplateAssociation, error) {
 						tas := []TemplateAssociation{
 							{
 								Template: v1.ComposedTemplate{
@@ -152,7 +185,7 @@ func TestPTCompose(t *testing.T) {
 					MockUpdate: test.NewMockUpdateFn(errBoom),
 				},
 				o: []PTComposerOption{
-					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate, _ v1.RemovedResourcePolicy) ([]TemplateAssociation, error) {
 						tas := []TemplateAssociation{{
 							Template: v1.ComposedTemplate{
 								Name: ptr.To("cool-resource"),
@@ -184,7 +217,7 @@ func TestPTCompose(t *testing.T) {
 					MockCreate: test.NewMockCreateFn(errBoom),
 				},
 				o: []PTComposerOption{
-					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate, _ v1.RemovedResourcePolicy) ([]TemplateAssociation, error) {
 						tas := []TemplateAssociation{{
 							Template: v1.ComposedTemplate{
 								Name: ptr.To("cool-resource"),
@@ -216,7 +249,7 @@ func TestPTCompose(t *testing.T) {
 					MockCreate: test.NewMockCreateFn(nil),
 				},
 				o: []PTComposerOption{
-					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate, _ v1.RemovedResourcePolicy) ([]TemplateAssociation, error) {
 						tas := []TemplateAssociation{{
 							Template: v1.ComposedTemplate{
 								Name: ptr.To("cool-resource"),
@@ -251,7 +284,7 @@ func TestPTCompose(t *testing.T) {
 					MockCreate: test.NewMockCreateFn(nil),
 				},
 				o: []PTComposerOption{
-					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate, _ v1.RemovedResourcePolicy) ([]TemplateAssociation, error) {
 						tas := []TemplateAssociation{{
 							Template: v1.ComposedTemplate{
 								Name: ptr.To("cool-resource"),
@@ -289,7 +322,7 @@ func TestPTCompose(t *testing.T) {
 					MockCreate: test.NewMockCreateFn(nil),
 				},
 				o: []PTComposerOption{
-					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate, _ v1.RemovedResourcePolicy) ([]TemplateAssociation, error) {
 						tas := []TemplateAssociation{{
 							Template: v1.ComposedTemplate{
 								Name: ptr.To("cool-resource"),
@@ -333,7 +366,7 @@ func TestPTCompose(t *testing.T) {
 					MockPatch: test.NewMockPatchFn(nil),
 				},
 				o: []PTComposerOption{
-					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate, _ v1.RemovedResourcePolicy) ([]TemplateAssociation, error) {
 						return nil, nil
 					})),
 				},
@@ -360,7 +393,7 @@ func TestPTCompose(t *testing.T) {
 					MockPatch:  test.NewMockPatchFn(nil),
 				},
 				o: []PTComposerOption{
-					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate, _ v1.RemovedResourcePolicy) ([]TemplateAssociation, error) {
 						tas := []TemplateAssociation{{
 							Template: v1.ComposedTemplate{
 								Name: ptr.To("cool-resource"),
@@ -410,7 +443,7 @@ func TestPTCompose(t *testing.T) {
 					MockPatch:  test.NewMockPatchFn(nil),
 				},
 				o: []PTComposerOption{
-					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate, _ v1.RemovedResourcePolicy) ([]TemplateAssociation, error) {
 						tas := []TemplateAssociation{
 							{
 								Template: v1.ComposedTemplate{
@@ -476,6 +509,425 @@ func TestPTCompose(t *testing.T) {
 				},
 			},
 		},
+		"PausedResources": {
+			reason: "We should observe, but not apply, paused composed resources that exist. Paused composed resources that don't exist should be reported as not ready and not synced.",
+			params: params{
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+
+					// Paused composed resources must not be created or
+					// patched.
+					MockGet:    test.NewMockGetFn(nil),
+					MockCreate: test.NewMockCreateFn(errBoom),
+					MockPatch: test.NewMockPatchFn(nil, func(obj client.Object) error {
+						if obj.GetObjectKind().GroupVersionKind().Kind == "ComposedResource" {
+							return errBoom
+						}
+						return nil
+					}),
+				},
+				o: []PTComposerOption{
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate, _ v1.RemovedResourcePolicy) ([]TemplateAssociation, error) {
+						tas := []TemplateAssociation{
+							{
+								Template: v1.ComposedTemplate{
+									Name:   ptr.To("existing-resource"),
+									Base:   base,
+									Paused: ptr.To(true),
+								},
+								Reference: corev1.ObjectReference{
+									APIVersion: "test.crossplane.io/v1",
+									Kind:       "ComposedResource",
+									Name:       "existing-resource-42",
+								},
+							},
+							{
+								Template: v1.ComposedTemplate{
+									Name:   ptr.To("new-resource"),
+									Base:   base,
+									Paused: ptr.To(true),
+								},
+							},
+						}
+						return tas, nil
+					})),
+					WithComposedNameGenerator(names.NameGeneratorFn(func(_ context.Context, _ resource.Object) error { return nil })),
+					WithComposedConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedConnectionDetailsExtractor(ConnectionDetailsExtractorFn(func(_ resource.Composed, _ managed.ConnectionDetails, _ ...ConnectionDetailExtractConfig) (managed.ConnectionDetails, error) {
+						return details, nil
+					})),
+					WithComposedReadinessChecker(ReadinessCheckerFn(func(_ context.Context, _ ConditionedObject, _ ...ReadinessCheck) (ready bool, err error) {
+						return true, nil
+					})),
+				},
+			},
+			args: args{
+				xr: WithParentLabel(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{},
+				},
+			},
+			want: want{
+				res: CompositionResult{
+					Composed: []ComposedResource{
+						{
+							ResourceName: "existing-resource",
+							Ready:        true,
+							Synced:       true,
+						},
+						{
+							ResourceName: "new-resource",
+							Ready:        false,
+							Synced:       false,
+						},
+					},
+					ConnectionDetails: details,
+				},
+			},
+		},
+		"ComposedResourceClient": {
+			reason: "We should read composed resources using the composed resource client, not the client used to read the composite resource.",
+			params: params{
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+
+					// Composed resources must not be read using this
+					// client.
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						if obj.GetObjectKind().GroupVersionKind().Kind == "ComposedResource" {
+							return errBoom
+						}
+						return nil
+					}),
+					MockCreate: test.NewMockCreateFn(errBoom),
+					MockPatch: test.NewMockPatchFn(nil, func(obj client.Object) error {
+						if obj.GetObjectKind().GroupVersionKind().Kind == "ComposedResource" {
+							return errBoom
+						}
+						return nil
+					}),
+				},
+				o: []PTComposerOption{
+					WithComposedResourceClient(&test.MockClient{MockGet: test.NewMockGetFn(nil)}),
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate, _ v1.RemovedResourcePolicy) ([]TemplateAssociation, error) {
+						tas := []TemplateAssociation{
+							{
+								Template: v1.ComposedTemplate{
+									Name:   ptr.To("existing-resource"),
+									Base:   base,
+									Paused: ptr.To(true),
+								},
+								Reference: corev1.ObjectReference{
+									APIVersion: "test.crossplane.io/v1",
+									Kind:       "ComposedResource",
+									Name:       "existing-resource-42",
+								},
+							},
+							{
+								Template: v1.ComposedTemplate{
+									Name:   ptr.To("new-resource"),
+									Base:   base,
+									Paused: ptr.To(true),
+								},
+							},
+						}
+						return tas, nil
+					})),
+					WithComposedNameGenerator(names.NameGeneratorFn(func(_ context.Context, _ resource.Object) error { return nil })),
+					WithComposedConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedConnectionDetailsExtractor(ConnectionDetailsExtractorFn(func(_ resource.Composed, _ managed.ConnectionDetails, _ ...ConnectionDetailExtractConfig) (managed.ConnectionDetails, error) {
+						return details, nil
+					})),
+					WithComposedReadinessChecker(ReadinessCheckerFn(func(_ context.Context, _ ConditionedObject, _ ...ReadinessCheck) (ready bool, err error) {
+						return true, nil
+					})),
+				},
+			},
+			args: args{
+				xr: WithParentLabel(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{},
+				},
+			},
+			want: want{
+				res: CompositionResult{
+					Composed: []ComposedResource{
+						{
+							ResourceName: "existing-resource",
+							Ready:        true,
+							Synced:       true,
+						},
+						{
+							ResourceName: "new-resource",
+							Ready:        false,
+							Synced:       false,
+						},
+					},
+					ConnectionDetails: details,
+				},
+			},
+		},
+		"MissingAPI": {
+			reason: "We should skip composed resources whose API is missing, report them as not ready and not synced, and return a condition listing their API.",
+			params: params{
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+
+					// Apply uses Get, Create, and Patch.
+					MockGet: test.NewMockGetFn(nil),
+					MockCreate: test.NewMockCreateFn(nil, func(obj client.Object) error {
+						if obj.GetObjectKind().GroupVersionKind().Kind == "MissingResource" {
+							return &kmeta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "test.crossplane.io", Kind: "MissingResource"}}
+						}
+						return nil
+					}),
+					MockPatch: test.NewMockPatchFn(nil),
+				},
+				o: []PTComposerOption{
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate, _ v1.RemovedResourcePolicy) ([]TemplateAssociation, error) {
+						tas := []TemplateAssociation{
+							{
+								Template: v1.ComposedTemplate{
+									Name: ptr.To("cool-resource"),
+									Base: base,
+								},
+							},
+							{
+								Template: v1.ComposedTemplate{
+									Name: ptr.To("missing-resource"),
+									Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"test.crossplane.io/v1","kind":"MissingResource"}`)},
+								},
+							},
+						}
+						return tas, nil
+					})),
+					WithComposedNameGenerator(names.NameGeneratorFn(func(_ context.Context, _ resource.Object) error { return nil })),
+					WithComposedConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedConnectionDetailsExtractor(ConnectionDetailsExtractorFn(func(_ resource.Composed, _ managed.ConnectionDetails, _ ...ConnectionDetailExtractConfig) (managed.ConnectionDetails, error) {
+						return details, nil
+					})),
+					WithComposedReadinessChecker(ReadinessCheckerFn(func(_ context.Context, _ ConditionedObject, _ ...ReadinessCheck) (ready bool, err error) {
+						return true, nil
+					})),
+				},
+			},
+			args: args{
+				xr: WithParentLabel(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{},
+				},
+			},
+			want: want{
+				res: CompositionResult{
+					Composed: []ComposedResource{
+						{
+							ResourceName: "cool-resource",
+							Ready:        true,
+							Synced:       true,
+						},
+						{
+							ResourceName: "missing-resource",
+							Ready:        false,
+							Synced:       false,
+						},
+					},
+					ConnectionDetails: details,
+					Conditions: []TargetedCondition{
+						{
+							Condition: xpv1.Condition{
+								Type:    TypeMissingAPI,
+								Status:  corev1.ConditionTrue,
+								Reason:  ReasonMissingAPIs,
+								Message: "Composed resources with missing APIs will not be applied: MissingResource (test.crossplane.io/v1)",
+							},
+							Target: CompositionTargetComposite,
+						},
+					},
+				},
+			},
+		},
+		"Drifted": {
+			reason: "We should return a condition listing composed resources that were changed outside of Crossplane since we last applied them.",
+			params: params{
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+
+					// Apply uses Get, Create, and Patch.
+					MockGet:    test.NewMockGetFn(nil, changed),
+					MockCreate: test.NewMockCreateFn(nil),
+					MockPatch:  test.NewMockPatchFn(nil),
+				},
+				o: []PTComposerOption{
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate, _ v1.RemovedResourcePolicy) ([]TemplateAssociation, error) {
+						tas := []TemplateAssociation{{
+							Template: v1.ComposedTemplate{
+								Name: ptr.To("cool-resource"),
+								Base: sized,
+							},
+							Reference: corev1.ObjectReference{
+								APIVersion: "test.crossplane.io/v1",
+								Kind:       "ComposedResource",
+								Name:       "cool-resource-42",
+							},
+						}}
+						return tas, nil
+					})),
+					WithComposedNameGenerator(names.NameGeneratorFn(func(_ context.Context, _ resource.Object) error { return nil })),
+					WithComposedConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedConnectionDetailsExtractor(ConnectionDetailsExtractorFn(func(_ resource.Composed, _ managed.ConnectionDetails, _ ...ConnectionDetailExtractConfig) (managed.ConnectionDetails, error) {
+						return details, nil
+					})),
+					WithComposedReadinessChecker(ReadinessCheckerFn(func(_ context.Context, _ ConditionedObject, _ ...ReadinessCheck) (ready bool, err error) {
+						return true, nil
+					})),
+				},
+			},
+			args: args{
+				xr: WithParentLabel(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{},
+				},
+			},
+			want: want{
+				res: CompositionResult{
+					Composed: []ComposedResource{{
+						ResourceName: "cool-resource",
+						Ready:        true,
+						Synced:       true,
+					}},
+					ConnectionDetails: details,
+					Conditions: []TargetedCondition{
+						{
+							Condition: xpv1.Condition{
+								Type:    TypeDrifted,
+								Status:  corev1.ConditionTrue,
+								Reason:  ReasonExternalChanges,
+								Message: "Composed resources were changed outside of Crossplane and will be reverted: cool-resource (spec.size)",
+							},
+							Target: CompositionTargetComposite,
+						},
+					},
+				},
+			},
+		},
+		"ConcurrentApplies": {
+			reason: "We should apply composed resources concurrently, and handle their errors in template order.",
+			params: params{
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+
+					// Apply uses Get, Create, and Patch.
+					MockGet: test.NewMockGetFn(nil),
+					MockCreate: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+						// Each create waits for the others to start. This
+						// would time out if resources were applied serially.
+						applying.Done()
+						select {
+						case <-allApplying:
+						case <-time.After(5 * time.Second):
+							return errBoom
+						}
+						switch obj.GetObjectKind().GroupVersionKind().Kind {
+						case "MissingResource":
+							return &kmeta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "test.crossplane.io", Kind: "MissingResource"}}
+						case "InvalidResource":
+							return kerrors.NewInvalid(schema.GroupKind{Group: "test.crossplane.io", Kind: "InvalidResource"}, "", nil)
+						}
+						return nil
+					},
+					MockPatch: test.NewMockPatchFn(nil),
+				},
+				o: []PTComposerOption{
+					WithMaxConcurrentComposedApplies(3),
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate, _ v1.RemovedResourcePolicy) ([]TemplateAssociation, error) {
+						tas := []TemplateAssociation{
+							{
+								Template: v1.ComposedTemplate{
+									Name: ptr.To("cool-resource"),
+									Base: base,
+								},
+							},
+							{
+								Template: v1.ComposedTemplate{
+									Name: ptr.To("missing-resource"),
+									Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"test.crossplane.io/v1","kind":"MissingResource"}`)},
+								},
+							},
+							{
+								Template: v1.ComposedTemplate{
+									Name: ptr.To("invalid-resource"),
+									Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"test.crossplane.io/v1","kind":"InvalidResource"}`)},
+								},
+							},
+						}
+						return tas, nil
+					})),
+					WithComposedNameGenerator(names.NameGeneratorFn(func(_ context.Context, _ resource.Object) error { return nil })),
+					WithComposedConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedConnectionDetailsExtractor(ConnectionDetailsExtractorFn(func(_ resource.Composed, _ managed.ConnectionDetails, _ ...ConnectionDetailExtractConfig) (managed.ConnectionDetails, error) {
+						return details, nil
+					})),
+					WithComposedReadinessChecker(ReadinessCheckerFn(func(_ context.Context, _ ConditionedObject, _ ...ReadinessCheck) (ready bool, err error) {
+						return true, nil
+					})),
+				},
+			},
+			args: args{
+				xr: WithParentLabel(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{},
+				},
+			},
+			want: want{
+				res: CompositionResult{
+					Composed: []ComposedResource{
+						{
+							ResourceName: "cool-resource",
+							Ready:        true,
+							Synced:       true,
+						},
+						{
+							ResourceName: "missing-resource",
+							Ready:        false,
+							Synced:       false,
+						},
+						{
+							ResourceName: "invalid-resource",
+							Ready:        false,
+							Synced:       false,
+						},
+					},
+					ConnectionDetails: details,
+					Events: []TargetedEvent{
+						{
+							Event:  event.Warning(reasonCompose, errors.Wrapf(errors.Wrap(kerrors.NewInvalid(schema.GroupKind{Group: "test.crossplane.io", Kind: "InvalidResource"}, "", nil), "cannot create object"), errFmtApplyComposed, "invalid-resource")),
+							Target: CompositionTargetComposite,
+						},
+					},
+					Conditions: []TargetedCondition{
+						{
+							Condition: xpv1.Condition{
+								Type:    TypeMissingAPI,
+								Status:  corev1.ConditionTrue,
+								Reason:  ReasonMissingAPIs,
+								Message: "Composed resources with missing APIs will not be applied: MissingResource (test.crossplane.io/v1)",
+							},
+							Target: CompositionTargetComposite,
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -561,6 +1013,7 @@ func TestGarbageCollectingAssociator(t *testing.T) {
 		ctx context.Context
 		cr  resource.Composite
 		ct  []v1.ComposedTemplate
+		p   v1.RemovedResourcePolicy
 	}
 
 	type want struct {
@@ -599,6 +1052,21 @@ func TestGarbageCollectingAssociator(t *testing.T) {
 				tas: []TemplateAssociation{{Template: t0}},
 			},
 		},
+		"ResourceAPINotFoundError": {
+			reason: "Resources whose API doesn't exist should be ignored.",
+			c: &test.MockClient{
+				MockGet: test.NewMockGetFn(&kmeta.NoKindMatchError{}),
+			},
+			args: args{
+				cr: &fake.Composite{
+					ComposedResourcesReferencer: fake.ComposedResourcesReferencer{Refs: []corev1.ObjectReference{r0}},
+				},
+				ct: []v1.ComposedTemplate{t0},
+			},
+			want: want{
+				tas: []TemplateAssociation{{Template: t0}},
+			},
+		},
 		"GetResourceError": {
 			reason: "Errors getting a referenced resource should be returned.",
 			c: &test.MockClient{
@@ -760,12 +1228,53 @@ func TestGarbageCollectingAssociator(t *testing.T) {
 				tas: []TemplateAssociation{{Template: t0}},
 			},
 		},
+		"OrphanedResource": {
+			reason: "We should orphan, rather than delete, a resource that doesn't correspond to a template if the policy is Orphan.",
+			c: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					// The template used to create this resource is no longer known to us.
+					SetCompositionResourceName(obj, "unknown")
+
+					// This resource is controlled by us.
+					ctrl := true
+					obj.SetOwnerReferences([]metav1.OwnerReference{{
+						Controller:         &ctrl,
+						BlockOwnerDeletion: &ctrl,
+						UID:                types.UID("it-me"),
+					}})
+
+					return nil
+				}),
+				// We know Delete wasn't called because it's nil and would
+				// panic if it was.
+				MockUpdate: test.NewMockUpdateFn(nil, func(obj client.Object) error {
+					if len(obj.GetOwnerReferences()) != 0 {
+						return errors.New("composed resource should not be owned by the XR")
+					}
+					if _, ok := obj.GetAnnotations()[AnnotationKeyCompositionResourceName]; ok {
+						return errors.New("composed resource should not have a composition resource name")
+					}
+					return nil
+				}),
+			},
+			args: args{
+				cr: &fake.Composite{
+					ObjectMeta:                  metav1.ObjectMeta{UID: "it-me"},
+					ComposedResourcesReferencer: fake.ComposedResourcesReferencer{Refs: []corev1.ObjectReference{r0}},
+				},
+				ct: []v1.ComposedTemplate{t0},
+				p:  v1.RemovedResourcePolicyOrphan,
+			},
+			want: want{
+				tas: []TemplateAssociation{{Template: t0}},
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			a := NewGarbageCollectingAssociator(tc.c)
-			got, err := a.AssociateTemplates(tc.args.ctx, tc.args.cr, tc.args.ct)
+			got, err := a.AssociateTemplates(tc.args.ctx, tc.args.cr, tc.args.ct, tc.args.p)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nAssociateTemplates(...): -want, +got:\n%s", tc.reason, diff)
@@ -16,6 +16,8 @@ specific language governing permissions and limitations under the License.
 package composite
 
 import (
+	"strings"
+
 	"k8s.io/apimachinery/pkg/util/json"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -80,15 +82,31 @@ func RenderFromJSON(o resource.Object, data []byte) error {
 
 // RenderFromCompositeAndEnvironmentPatches renders the supplied composed
 // resource by applying all patches that are _from_ the supplied composite
-// resource or are from or to the supplied environment.
-func RenderFromCompositeAndEnvironmentPatches(cd resource.Composed, xr resource.Composite, e *Environment, p []v1.Patch) error {
+// resource or are from or to the supplied environment, and whose condition the
+// composite resource meets. Each applied patch is traced using the supplied
+// PatchTracer.
+func RenderFromCompositeAndEnvironmentPatches(cd resource.Composed, xr resource.Composite, e *Environment, p []v1.Patch, t PatchTracer) error {
 	for i := range p {
-		if err := Apply(p[i], xr, cd, patchTypesFromXR()...); err != nil {
+		met, err := ConditionMet(p[i], xr)
+		if err != nil {
 			return errors.Wrapf(err, errFmtPatch, p[i].Type, i)
 		}
+		if !met {
+			continue
+		}
+
+		if !filterPatch(p[i], patchTypesFromXR()...) {
+			err := Apply(p[i], xr, cd, patchTypesFromXR()...)
+			t.TracePatch(i, p[i], xr, err)
+			if err != nil {
+				return errors.Wrapf(err, errFmtPatch, p[i].Type, i)
+			}
+		}
 
-		if e != nil {
-			if err := ApplyToObjects(p[i], e, cd, patchTypesFromToEnvironment()...); err != nil {
+		if e != nil && !filterPatch(p[i], patchTypesFromToEnvironment()...) {
+			err := ApplyToObjects(p[i], e, cd, patchTypesFromToEnvironment()...)
+			t.TracePatch(i, p[i], patchSource(p[i], e, cd), err)
+			if err != nil {
 				return errors.Wrapf(err, errFmtPatch, p[i].Type, i)
 			}
 		}
@@ -98,10 +116,22 @@ func RenderFromCompositeAndEnvironmentPatches(cd resource.Composed, xr resource.
 
 // RenderToCompositePatches renders the supplied composite resource by applying
 // all patches that are _from_ the supplied composed resource. composed resource
-// and template.
-func RenderToCompositePatches(xr resource.Composite, cd resource.Composed, p []v1.Patch) error {
+// and template. Each applied patch is traced using the supplied PatchTracer.
+func RenderToCompositePatches(xr resource.Composite, cd resource.Composed, p []v1.Patch, t PatchTracer) error {
 	for i := range p {
-		if err := Apply(p[i], xr, cd, patchTypesToXR()...); err != nil {
+		if filterPatch(p[i], patchTypesToXR()...) {
+			continue
+		}
+		met, err := ConditionMet(p[i], xr)
+		if err != nil {
+			return errors.Wrapf(err, errFmtPatch, p[i].Type, i)
+		}
+		if !met {
+			continue
+		}
+		err = Apply(p[i], xr, cd, patchTypesToXR()...)
+		t.TracePatch(i, p[i], cd, err)
+		if err != nil {
 			return errors.Wrapf(err, errFmtPatch, p[i].Type, i)
 		}
 	}
@@ -137,5 +167,46 @@ func RenderComposedResourceMetadata(cd, xr resource.Object, n ResourceName) erro
 	return errors.Wrap(meta.AddControllerReference(cd, or), errSetControllerRef)
 }
 
+// RenderPropagatedMetadata propagates the labels and annotations of the
+// supplied composite resource that the supplied MetadataPropagation allows to
+// the supplied composed resource. Labels and annotations the composed resource
+// already has take precedence.
+func RenderPropagatedMetadata(cd, xr resource.Object, p *v1.MetadataPropagation) {
+	if p == nil {
+		return
+	}
+	cd.SetLabels(withPropagated(cd.GetLabels(), xr.GetLabels(), p.Labels))
+	cd.SetAnnotations(withPropagated(cd.GetAnnotations(), xr.GetAnnotations(), p.Annotations))
+}
+
+// withPropagated adds the entries of src whose keys match any of the supplied
+// keys to dst, unless dst already has them.
+func withPropagated(dst, src map[string]string, keys []string) map[string]string {
+	for k, v := range src {
+		if _, ok := dst[k]; ok || !matchesAnyKey(k, keys) {
+			continue
+		}
+		if dst == nil {
+			dst = make(map[string]string)
+		}
+		dst[k] = v
+	}
+	return dst
+}
+
+// matchesAnyKey returns true if the supplied key matches any of the supplied
+// keys. A key ending in "*" matches any key with that prefix.
+func matchesAnyKey(k string, keys []string) bool {
+	for _, want := range keys {
+		if prefix, ok := strings.CutSuffix(want, "*"); ok && strings.HasPrefix(k, prefix) {
+			return true
+		}
+		if k == want {
+			return true
+		}
+	}
+	return false
+}
+
 // TODO(negz): It's simple enough that we should just inline it into the
 // PTComposer, which is now the only consumer.
@@ -32,6 +32,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	"github.com/crossplane/crossplane/internal/xcrd"
 )
 
@@ -321,3 +322,99 @@ func TestRenderComposedResourceMetadata(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderPropagatedMetadata(t *testing.T) {
+	xr := &fake.Composite{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				"team":                    "platform",
+				"billing.example.org/org": "engineering",
+				"billing.example.org/cc":  "1234",
+				"unrelated":               "value",
+			},
+			Annotations: map[string]string{
+				"example.org/owner": "platform@example.org",
+				"unrelated":         "value",
+			},
+		},
+	}
+
+	type args struct {
+		cd resource.Composed
+		p  *v1.MetadataPropagation
+	}
+	type want struct {
+		cd resource.Composed
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"NoPropagation": {
+			reason: "We should not propagate any metadata if no propagation is configured.",
+			args: args{
+				cd: &fake.Composed{},
+			},
+			want: want{
+				cd: &fake.Composed{},
+			},
+		},
+		"PropagateAllowedKeys": {
+			reason: "We should propagate labels and annotations whose keys match an allowed key or key prefix.",
+			args: args{
+				cd: &fake.Composed{},
+				p: &v1.MetadataPropagation{
+					Labels:      []string{"team", "billing.example.org/*"},
+					Annotations: []string{"example.org/owner"},
+				},
+			},
+			want: want{
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							"team":                    "platform",
+							"billing.example.org/org": "engineering",
+							"billing.example.org/cc":  "1234",
+						},
+						Annotations: map[string]string{
+							"example.org/owner": "platform@example.org",
+						},
+					},
+				},
+			},
+		},
+		"ComposedMetadataTakesPrecedence": {
+			reason: "We should not overwrite labels and annotations the composed resource already has.",
+			args: args{
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							"team": "data",
+						},
+					},
+				},
+				p: &v1.MetadataPropagation{
+					Labels: []string{"team"},
+				},
+			},
+			want: want{
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							"team": "data",
+						},
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			RenderPropagatedMetadata(tc.args.cd, xr, tc.args.p)
+			if diff := cmp.Diff(tc.want.cd, tc.args.cd); diff != "" {
+				t.Errorf("\n%s\nRenderPropagatedMetadata(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
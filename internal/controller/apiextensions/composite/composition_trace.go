@@ -0,0 +1,254 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/xcrd"
+)
+
+const (
+	// AnnotationKeyDebugPatches can be set to "true" on a composite resource
+	// to record how each of the patches of its Composition were evaluated.
+	// This only applies to Patch & Transform Composition.
+	AnnotationKeyDebugPatches = "crossplane.io/debug-patches"
+
+	// PatchTracesKey is the ConfigMap data key under which patch traces are
+	// written, as a JSON array.
+	PatchTracesKey = "traces.json"
+
+	// FieldOwnerPatchTraces is the field manager name used when writing patch
+	// traces.
+	FieldOwnerPatchTraces = "apiextensions.crossplane.io/patch-traces"
+)
+
+const (
+	errMarshalPatchTraces = "cannot marshal patch traces"
+	errWritePatchTraces   = "cannot write patch traces"
+)
+
+// DebugPatches returns true if the supplied composite resource asks for its
+// patches to be traced.
+func DebugPatches(xr metav1.Object) bool {
+	return xr.GetAnnotations()[AnnotationKeyDebugPatches] == "true"
+}
+
+// A PatchTrace records how a single patch was evaluated.
+type PatchTrace struct {
+	// Resource is the name of the composed resource template the patch
+	// belongs to.
+	Resource ResourceName `json:"resource"`
+
+	// Index of the patch in the template's array of patches.
+	Index int `json:"index"`
+
+	// Type of the patch.
+	Type v1.PatchType `json:"type"`
+
+	// FromFieldPaths are the field paths the patch read its input from.
+	FromFieldPaths []string `json:"fromFieldPaths,omitempty"`
+
+	// ToFieldPath is the field path the patch wrote its output to.
+	ToFieldPath string `json:"toFieldPath,omitempty"`
+
+	// Input is the value read from the source field path. Combine patches
+	// have an array of inputs - one per variable.
+	Input any `json:"input,omitempty"`
+
+	// Output is the value written to the target field path, after any
+	// transforms were applied.
+	Output any `json:"output,omitempty"`
+
+	// Skipped is true if the patch wasn't applied because an optional source
+	// field path didn't exist.
+	Skipped bool `json:"skipped,omitempty"`
+
+	// Error is the error encountered applying the patch, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// A PatchTracer traces the evaluation of patches.
+type PatchTracer interface {
+	// TracePatch traces the supplied patch, which was applied from the
+	// supplied object with the supplied result.
+	TracePatch(i int, p v1.Patch, from runtime.Object, err error)
+}
+
+// A NopPatchTracer does nothing.
+type NopPatchTracer struct{}
+
+// TracePatch does nothing.
+func (NopPatchTracer) TracePatch(_ int, _ v1.Patch, _ runtime.Object, _ error) {}
+
+// PatchTraces is a log of traced patches.
+type PatchTraces struct {
+	Traces []PatchTrace
+}
+
+// For returns a PatchTracer that adds traces of the named composed resource's
+// patches to the log. It returns a NopPatchTracer if the log is nil, i.e. if
+// patches are not being traced.
+func (t *PatchTraces) For(n ResourceName) PatchTracer {
+	if t == nil {
+		return NopPatchTracer{}
+	}
+	return &resourcePatchTracer{traces: t, resource: n}
+}
+
+type resourcePatchTracer struct {
+	traces   *PatchTraces
+	resource ResourceName
+}
+
+// TracePatch adds a trace of the supplied patch to the log.
+func (t *resourcePatchTracer) TracePatch(i int, p v1.Patch, from runtime.Object, err error) {
+	t.traces.Traces = append(t.traces.Traces, TracePatch(t.resource, i, p, from, err))
+}
+
+// TracePatch traces the supplied patch by evaluating it against the supplied
+// object, which the patch reads its input from. The supplied error is the
+// result of applying the patch. TracePatch doesn't modify any objects.
+func TracePatch(n ResourceName, i int, p v1.Patch, from runtime.Object, err error) PatchTrace {
+	t := PatchTrace{
+		Resource:    n,
+		Index:       i,
+		Type:        p.Type,
+		ToFieldPath: ptr.Deref(p.ToFieldPath, ptr.Deref(p.FromFieldPath, "")),
+	}
+	if err != nil {
+		t.Error = err.Error()
+	}
+
+	switch p.Type {
+	case v1.PatchTypeFromCompositeFieldPath, v1.PatchTypeFromEnvironmentFieldPath, v1.PatchTypeToCompositeFieldPath, v1.PatchTypeToEnvironmentFieldPath:
+		if p.FromFieldPath == nil {
+			return t
+		}
+		t.FromFieldPaths = []string{*p.FromFieldPath}
+		in, out, ok, _ := resolveFromFieldPathPatch(p, from)
+		t.Input, t.Output, t.Skipped = in, out, !ok && err == nil
+	case v1.PatchTypeCombineFromComposite, v1.PatchTypeCombineFromEnvironment, v1.PatchTypeCombineToComposite, v1.PatchTypeCombineToEnvironment:
+		if p.Combine == nil || len(p.Combine.Variables) == 0 {
+			return t
+		}
+		for _, v := range p.Combine.Variables {
+			t.FromFieldPaths = append(t.FromFieldPaths, v.FromFieldPath)
+		}
+		in, out, ok, _ := resolveCombineFromVariablesPatch(p, from)
+		t.Input, t.Output, t.Skipped = in, out, !ok && err == nil
+	case v1.PatchTypePatchSet:
+		// Already resolved - nothing to trace.
+	}
+
+	return t
+}
+
+// patchSource returns the object the supplied patch reads its input from.
+func patchSource(p v1.Patch, cp, cd runtime.Object) runtime.Object {
+	switch p.GetType() {
+	case v1.PatchTypeToCompositeFieldPath, v1.PatchTypeToEnvironmentFieldPath, v1.PatchTypeCombineToComposite, v1.PatchTypeCombineToEnvironment:
+		return cd
+	case v1.PatchTypeFromCompositeFieldPath, v1.PatchTypeFromEnvironmentFieldPath, v1.PatchTypeCombineFromComposite, v1.PatchTypeCombineFromEnvironment, v1.PatchTypePatchSet:
+		// These patches read from the composite resource or environment.
+	}
+	return cp
+}
+
+// A PatchTraceWriter writes the patch traces of a composite resource
+// somewhere they can be inspected.
+type PatchTraceWriter interface {
+	WritePatchTraces(ctx context.Context, xr resource.Composite, t []PatchTrace) error
+}
+
+// A PatchTraceWriterFn writes patch traces.
+type PatchTraceWriterFn func(ctx context.Context, xr resource.Composite, t []PatchTrace) error
+
+// WritePatchTraces writes the supplied patch traces.
+func (fn PatchTraceWriterFn) WritePatchTraces(ctx context.Context, xr resource.Composite, t []PatchTrace) error {
+	return fn(ctx, xr, t)
+}
+
+// A NopPatchTraceWriter discards patch traces.
+type NopPatchTraceWriter struct{}
+
+// WritePatchTraces does nothing.
+func (NopPatchTraceWriter) WritePatchTraces(_ context.Context, _ resource.Composite, _ []PatchTrace) error {
+	return nil
+}
+
+// A ConfigMapPatchTraceWriter writes the patch traces of each composite
+// resource to a ConfigMap named patch-trace-<uid>, where uid is the UID of the
+// composite resource. The composite resource owns the ConfigMap, so it's
+// garbage collected when the composite resource is deleted.
+type ConfigMapPatchTraceWriter struct {
+	client    client.Client
+	namespace string
+}
+
+// NewConfigMapPatchTraceWriter returns a PatchTraceWriter that writes patch
+// traces to ConfigMaps in the supplied namespace.
+func NewConfigMapPatchTraceWriter(c client.Client, namespace string) *ConfigMapPatchTraceWriter {
+	return &ConfigMapPatchTraceWriter{client: c, namespace: namespace}
+}
+
+// PatchTraceConfigMapName returns the name of the ConfigMap the patch traces
+// of the supplied composite resource are written to.
+func PatchTraceConfigMapName(xr metav1.Object) string {
+	return "patch-trace-" + string(xr.GetUID())
+}
+
+// WritePatchTraces writes the supplied patch traces to a ConfigMap, replacing
+// any traces previously written for the supplied composite resource.
+func (w *ConfigMapPatchTraceWriter) WritePatchTraces(ctx context.Context, xr resource.Composite, t []PatchTrace) error {
+	b, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, errMarshalPatchTraces)
+	}
+
+	gvk := xr.GetObjectKind().GroupVersionKind()
+	cm := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      PatchTraceConfigMapName(xr),
+			Namespace: w.namespace,
+			Labels: map[string]string{
+				xcrd.LabelKeyNamePrefixForComposed: xr.GetLabels()[xcrd.LabelKeyNamePrefixForComposed],
+			},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: gvk.GroupVersion().String(),
+				Kind:       gvk.Kind,
+				Name:       xr.GetName(),
+				UID:        xr.GetUID(),
+			}},
+		},
+		Data: map[string]string{PatchTracesKey: string(b)},
+	}
+
+	return errors.Wrap(w.client.Patch(ctx, cm, client.Apply, client.ForceOwnership, client.FieldOwner(FieldOwnerPatchTraces)), errWritePatchTraces)
+}
@@ -0,0 +1,269 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+var (
+	_ PatchTracer      = NopPatchTracer{}
+	_ PatchTraceWriter = &ConfigMapPatchTraceWriter{}
+)
+
+func TestTracePatch(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	xr := composite.New()
+	xr.SetName("cool-xr")
+	xr.SetLabels(map[string]string{"a": "A", "b": "B"})
+
+	type args struct {
+		p    v1.Patch
+		from runtime.Object
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   PatchTrace
+	}{
+		"FromFieldPath": {
+			reason: "We should trace the input and transformed output of a FromFieldPath patch.",
+			args: args{
+				p: v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: ptr.To("metadata.name"),
+					ToFieldPath:   ptr.To("spec.forProvider.name"),
+					Transforms: []v1.Transform{{
+						Type:   v1.TransformTypeString,
+						String: &v1.StringTransform{Type: v1.StringTransformTypeFormat, Format: ptr.To("%s-suffix")},
+					}},
+				},
+				from: xr,
+			},
+			want: PatchTrace{
+				Resource:       "cool-resource",
+				Index:          1,
+				Type:           v1.PatchTypeFromCompositeFieldPath,
+				FromFieldPaths: []string{"metadata.name"},
+				ToFieldPath:    "spec.forProvider.name",
+				Input:          "cool-xr",
+				Output:         "cool-xr-suffix",
+			},
+		},
+		"DefaultToFieldPath": {
+			reason: "A FromFieldPath patch's ToFieldPath should default to its FromFieldPath.",
+			args: args{
+				p: v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: ptr.To("metadata.name"),
+				},
+				from: xr,
+			},
+			want: PatchTrace{
+				Resource:       "cool-resource",
+				Index:          1,
+				Type:           v1.PatchTypeFromCompositeFieldPath,
+				FromFieldPaths: []string{"metadata.name"},
+				ToFieldPath:    "metadata.name",
+				Input:          "cool-xr",
+				Output:         "cool-xr",
+			},
+		},
+		"OptionalFieldPathNotFound": {
+			reason: "We should trace that a patch was skipped if its optional source field path doesn't exist.",
+			args: args{
+				p: v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: ptr.To("spec.nope"),
+				},
+				from: xr,
+			},
+			want: PatchTrace{
+				Resource:       "cool-resource",
+				Index:          1,
+				Type:           v1.PatchTypeFromCompositeFieldPath,
+				FromFieldPaths: []string{"spec.nope"},
+				ToFieldPath:    "spec.nope",
+				Skipped:        true,
+			},
+		},
+		"Combine": {
+			reason: "We should trace the inputs and combined output of a Combine patch.",
+			args: args{
+				p: v1.Patch{
+					Type: v1.PatchTypeCombineFromComposite,
+					Combine: &v1.Combine{
+						Variables: []v1.CombineVariable{{FromFieldPath: "metadata.labels.a"}, {FromFieldPath: "metadata.labels.b"}},
+						Strategy:  v1.CombineStrategyString,
+						String:    &v1.StringCombine{Format: "%s-%s"},
+					},
+					ToFieldPath: ptr.To("spec.forProvider.name"),
+				},
+				from: xr,
+			},
+			want: PatchTrace{
+				Resource:       "cool-resource",
+				Index:          1,
+				Type:           v1.PatchTypeCombineFromComposite,
+				FromFieldPaths: []string{"metadata.labels.a", "metadata.labels.b"},
+				ToFieldPath:    "spec.forProvider.name",
+				Input:          []any{"A", "B"},
+				Output:         "A-B",
+			},
+		},
+		"ApplyError": {
+			reason: "We should trace any error encountered applying a patch.",
+			args: args{
+				p: v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: ptr.To("metadata.name"),
+				},
+				from: xr,
+				err:  errBoom,
+			},
+			want: PatchTrace{
+				Resource:       "cool-resource",
+				Index:          1,
+				Type:           v1.PatchTypeFromCompositeFieldPath,
+				FromFieldPaths: []string{"metadata.name"},
+				ToFieldPath:    "metadata.name",
+				Input:          "cool-xr",
+				Output:         "cool-xr",
+				Error:          errBoom.Error(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := TracePatch("cool-resource", 1, tc.args.p, tc.args.from, tc.args.err)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nTracePatch(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPatchTracesFor(t *testing.T) {
+	xr := composite.New()
+	xr.SetName("cool-xr")
+	p := v1.Patch{Type: v1.PatchTypeFromCompositeFieldPath, FromFieldPath: ptr.To("metadata.name")}
+
+	// Tracing with a nil log should do nothing.
+	var none *PatchTraces
+	none.For("cool-resource").TracePatch(0, p, xr, nil)
+
+	traces := &PatchTraces{}
+	traces.For("cool-resource").TracePatch(0, p, xr, nil)
+	traces.For("other-resource").TracePatch(2, p, xr, nil)
+
+	want := []PatchTrace{
+		TracePatch("cool-resource", 0, p, xr, nil),
+		TracePatch("other-resource", 2, p, xr, nil),
+	}
+	if diff := cmp.Diff(want, traces.Traces); diff != "" {
+		t.Errorf("For(...).TracePatch(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestConfigMapPatchTraceWriter(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	xr := composite.New(composite.WithGroupVersionKind(schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XCool"}))
+	xr.SetName("cool-xr")
+	xr.SetUID(types.UID("cool-uid"))
+
+	traces := []PatchTrace{{Resource: "cool-resource", Type: v1.PatchTypeFromCompositeFieldPath, Input: "a", Output: "b"}}
+	b, _ := json.MarshalIndent(traces, "", "  ")
+
+	type want struct {
+		cm  *corev1.ConfigMap
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   want
+	}{
+		"Success": {
+			reason: "We should write patch traces to a ConfigMap owned by the XR.",
+			want: want{
+				cm: func() *corev1.ConfigMap {
+					cm := &corev1.ConfigMap{}
+					cm.SetName("patch-trace-cool-uid")
+					cm.SetNamespace("crossplane-system")
+					return cm
+				}(),
+			},
+		},
+		"PatchError": {
+			reason: "We should return any error encountered writing the ConfigMap.",
+			err:    errBoom,
+			want:   want{err: errors.Wrap(errBoom, errWritePatchTraces)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got *corev1.ConfigMap
+			c := &test.MockClient{MockPatch: func(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+				got = obj.(*corev1.ConfigMap)
+				return tc.err
+			}}
+
+			err := NewConfigMapPatchTraceWriter(c, "crossplane-system").WritePatchTraces(context.Background(), xr, traces)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nWritePatchTraces(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if tc.want.cm == nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.cm.GetName(), got.GetName()); diff != "" {
+				t.Errorf("\n%s\nWritePatchTraces(...): -want name, +got name:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cm.GetNamespace(), got.GetNamespace()); diff != "" {
+				t.Errorf("\n%s\nWritePatchTraces(...): -want namespace, +got namespace:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(string(b), got.Data[PatchTracesKey]); diff != "" {
+				t.Errorf("\n%s\nWritePatchTraces(...): -want traces, +got traces:\n%s", tc.reason, diff)
+			}
+			if len(got.GetOwnerReferences()) != 1 || got.GetOwnerReferences()[0].UID != xr.GetUID() {
+				t.Errorf("\n%s\nWritePatchTraces(...): want ConfigMap owned by XR, got owners %v", tc.reason, got.GetOwnerReferences())
+			}
+		})
+	}
+}
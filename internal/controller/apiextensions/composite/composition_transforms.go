@@ -28,6 +28,8 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
+	"text/template"
 
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -60,6 +62,9 @@ const (
 	errFmtMatchPatternTypeInvalid = "unsupported pattern type '%s'"
 	errFmtMatchInputTypeInvalid   = "unsupported input type '%s'"
 	errMatchRegexpCompile         = "cannot compile regexp"
+	errFmtMatchExpandResult       = "cannot expand capture groups in result of pattern at index %d"
+	errFmtMatchExpandPatternType  = "cannot expand capture groups for pattern type '%s'"
+	errFmtMatchExpandResultType   = "cannot expand capture groups in result of type '%s'"
 
 	errStringTransformTypeFailed        = "type %s is not supported for string transform type"
 	errStringTransformTypeFormat        = "string transform of type %s fmt is not set"
@@ -72,6 +77,10 @@ const (
 	errStringTransformTypeRegexpNoMatch = "regexp %q had no matches for group %d"
 	errStringConvertTypeFailed          = "type %s is not supported for string convert"
 
+	errTemplateParse             = "cannot parse template"
+	errTemplateExecute           = "cannot execute template"
+	errFmtTemplateOutputTooLarge = "template output is larger than %d bytes"
+
 	errDecodeString = "string is not valid base64"
 	errMarshalJSON  = "cannot marshal to JSON"
 	errHash         = "cannot generate hash"
@@ -109,6 +118,11 @@ func Resolve(t v1.Transform, input any) (any, error) {
 			return nil, errors.Errorf(errFmtTransformConfigMissing, t.Type)
 		}
 		out, err = ResolveConvert(*t.Convert, input)
+	case v1.TransformTypeTemplate:
+		if t.Template == nil {
+			return nil, errors.Errorf(errFmtTransformConfigMissing, t.Type)
+		}
+		out, err = ResolveTemplate(*t.Template, input)
 	default:
 		return nil, errors.Errorf(errFmtTypeNotSupported, string(t.Type))
 	}
@@ -213,6 +227,10 @@ func ResolveMatch(t v1.MatchTransform, input any) (any, error) {
 			if err := unmarshalJSON(p.Result, &output); err != nil {
 				return nil, errors.Wrapf(err, errFmtMatchParseResult, i)
 			}
+			if ptr.Deref(p.ExpandCaptureGroups, false) {
+				output, err = expandCaptureGroups(p, input, output)
+				return output, errors.Wrapf(err, errFmtMatchExpandResult, i)
+			}
 			return output, nil
 		}
 	}
@@ -273,6 +291,26 @@ func matchesRegexp(p v1.MatchTransformPattern, input any) (bool, error) {
 	return re.MatchString(inputStr), nil
 }
 
+// expandCaptureGroups replaces references to the capture groups of the
+// supplied regexp pattern in the supplied result with the text they matched
+// in the supplied input. The pattern must match the input.
+func expandCaptureGroups(p v1.MatchTransformPattern, input, result any) (any, error) {
+	if p.Type != v1.MatchTransformPatternTypeRegexp || p.Regexp == nil {
+		return nil, errors.Errorf(errFmtMatchExpandPatternType, string(p.Type))
+	}
+	tmpl, ok := result.(string)
+	if !ok {
+		return nil, errors.Errorf(errFmtMatchExpandResultType, fmt.Sprintf("%T", result))
+	}
+	re, err := regexp.Compile(*p.Regexp)
+	if err != nil {
+		return nil, errors.Wrap(err, errMatchRegexpCompile)
+	}
+	// Matches has already checked that the input is a string.
+	in, _ := input.(string)
+	return string(re.ExpandString(nil, tmpl, in, re.FindStringSubmatchIndex(in))), nil
+}
+
 // unmarshalJSON is a small utility function that returns nil if j contains no
 // data. json.Unmarshal seems to not be able to handle this.
 func unmarshalJSON(j extv1.JSON, output *any) error {
@@ -315,6 +353,75 @@ func ResolveString(t v1.StringTransform, input any) (string, error) {
 	}
 }
 
+// maxCachedTemplates is the maximum number of parsed Template transforms that
+// are cached. The cache is reset when it's full, so that templates that are no
+// longer used don't accumulate as Compositions change.
+const maxCachedTemplates = 1000
+
+// templates caches parsed Template transforms by their text, so that each is
+// parsed once rather than every time a composite resource is composed.
+var templates = &templateCache{parsed: make(map[string]*template.Template)}
+
+type templateCache struct {
+	mu     sync.RWMutex
+	parsed map[string]*template.Template
+}
+
+func (c *templateCache) Get(t v1.TemplateTransform) (*template.Template, error) {
+	c.mu.RLock()
+	tmpl, ok := c.parsed[t.Text]
+	c.mu.RUnlock()
+	if ok {
+		return tmpl, nil
+	}
+
+	tmpl, err := t.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.parsed) >= maxCachedTemplates {
+		c.parsed = make(map[string]*template.Template)
+	}
+	c.parsed[t.Text] = tmpl
+	return tmpl, nil
+}
+
+// ResolveTemplate resolves a Template transform.
+func ResolveTemplate(t v1.TemplateTransform, input any) (string, error) {
+	tmpl, err := templates.Get(t)
+	if err != nil {
+		return "", errors.Wrap(err, errTemplateParse)
+	}
+	w := &limitedWriter{limit: maxTemplateOutput}
+	if err := tmpl.Execute(w, input); err != nil {
+		return "", errors.Wrap(err, errTemplateExecute)
+	}
+	return w.String(), nil
+}
+
+// maxTemplateOutput is the maximum size in bytes of a Template transform's
+// output. Templates run in the core controller, so we stop executing one that
+// produces more output than could reasonably be patched into a field, e.g.
+// because it ranges over a large input.
+const maxTemplateOutput = 4 << 10
+
+// A limitedWriter buffers what's written to it, and returns an error once more
+// than its limit has been written.
+type limitedWriter struct {
+	strings.Builder
+	limit int
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if w.Len()+len(p) > w.limit {
+		return 0, errors.Errorf(errFmtTemplateOutputTooLarge, w.limit)
+	}
+	return w.Builder.Write(p)
+}
+
 func stringConvertTransform(t *v1.StringConversionType, input any) (string, error) {
 	str := fmt.Sprintf("%v", input)
 	switch *t {
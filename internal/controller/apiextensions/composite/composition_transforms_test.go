@@ -19,6 +19,7 @@ package composite
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -397,6 +398,60 @@ func TestMatchResolve(t *testing.T) {
 				o: "Hello World",
 			},
 		},
+		"MatchRegexpExpandCaptureGroups": {
+			args: args{
+				t: v1.MatchTransform{
+					Patterns: []v1.MatchTransformPattern{
+						{
+							Type:                v1.MatchTransformPatternTypeRegexp,
+							Regexp:              ptr.To(`^arn:aws:[a-z0-9-]+:(?P<region>[a-z0-9-]+):(\d+):`),
+							Result:              asJSON("${region}/$2"),
+							ExpandCaptureGroups: ptr.To(true),
+						},
+					},
+				},
+				i: "arn:aws:rds:us-west-2:123456789012:db:my-db",
+			},
+			want: want{
+				o: "us-west-2/123456789012",
+			},
+		},
+		"ErrMatchRegexpExpandCaptureGroupsNonStringResult": {
+			args: args{
+				t: v1.MatchTransform{
+					Patterns: []v1.MatchTransformPattern{
+						{
+							Type:                v1.MatchTransformPatternTypeRegexp,
+							Regexp:              ptr.To("^(foo).*$"),
+							Result:              asJSON(5),
+							ExpandCaptureGroups: ptr.To(true),
+						},
+					},
+				},
+				i: "foobar",
+			},
+			want: want{
+				err: errors.Wrapf(errors.Errorf(errFmtMatchExpandResultType, "float64"), errFmtMatchExpandResult, 0),
+			},
+		},
+		"ErrMatchLiteralExpandCaptureGroups": {
+			args: args{
+				t: v1.MatchTransform{
+					Patterns: []v1.MatchTransformPattern{
+						{
+							Type:                v1.MatchTransformPatternTypeLiteral,
+							Literal:             ptr.To("foo"),
+							Result:              asJSON("$1"),
+							ExpandCaptureGroups: ptr.To(true),
+						},
+					},
+				},
+				i: "foo",
+			},
+			want: want{
+				err: errors.Wrapf(errors.Errorf(errFmtMatchExpandPatternType, string(v1.MatchTransformPatternTypeLiteral)), errFmtMatchExpandResult, 0),
+			},
+		},
 		"ErrMissingRegexp": {
 			args: args{
 				t: v1.MatchTransform{
@@ -1230,6 +1285,152 @@ func TestConvertResolve(t *testing.T) {
 	}
 }
 
+func TestTemplateResolve(t *testing.T) {
+	type args struct {
+		text string
+		i    any
+	}
+	type want struct {
+		o   string
+		err error
+	}
+
+	cases := map[string]struct {
+		args
+		want
+	}{
+		"Lower": {
+			args: args{
+				text: "{{ . | lower }}",
+				i:    "Hello-World",
+			},
+			want: want{
+				o: "hello-world",
+			},
+		},
+		"Trunc": {
+			args: args{
+				text: "{{ trunc 5 . }}",
+				i:    "abcdefgh",
+			},
+			want: want{
+				o: "abcde",
+			},
+		},
+		"TruncFromEnd": {
+			args: args{
+				text: "{{ trunc -3 . }}",
+				i:    "abcdefgh",
+			},
+			want: want{
+				o: "fgh",
+			},
+		},
+		"TruncMultiByte": {
+			args: args{
+				text: "{{ trunc 2 . }}-{{ trunc -2 . }}",
+				i:    "日本語",
+			},
+			want: want{
+				o: "日本-本語",
+			},
+		},
+		"TruncShort": {
+			args: args{
+				text: "{{ trunc 63 . }}",
+				i:    "abc",
+			},
+			want: want{
+				o: "abc",
+			},
+		},
+		"SHA1Sum": {
+			args: args{
+				text: "{{ sha1sum . | trunc 8 }}",
+				i:    "crossplane",
+			},
+			want: want{
+				o: "acc45ad3",
+			},
+		},
+		"Printf": {
+			args: args{
+				text: `{{ printf "%s-%s" .name .region }}`,
+				i:    map[string]any{"name": "db", "region": "us-east-1"},
+			},
+			want: want{
+				o: "db-us-east-1",
+			},
+		},
+		"PrintfWidth": {
+			args: args{
+				text: `{{ printf "%05d%%" . }}`,
+				i:    42,
+			},
+			want: want{
+				o: "00042%",
+			},
+		},
+		"ErrorUnknownFunction": {
+			args: args{
+				text: `{{ env "HOME" }}`,
+				i:    "abc",
+			},
+			want: want{
+				err: errors.Wrap(errors.New(`template: template:1: function "env" not defined`), errTemplateParse),
+			},
+		},
+		"ErrorMissingKey": {
+			args: args{
+				text: "{{ .missing }}",
+				i:    map[string]any{"name": "db"},
+			},
+			want: want{
+				err: errors.Wrap(errors.New(`template: template:1:3: executing "template" at <.missing>: map has no entry for key "missing"`), errTemplateExecute),
+			},
+		},
+		"ErrorOutputTooLarge": {
+			args: args{
+				text: "{{ . }}",
+				i:    strings.Repeat("a", maxTemplateOutput+1),
+			},
+			want: want{
+				err: errors.Wrap(errors.Errorf(errFmtTemplateOutputTooLarge, maxTemplateOutput), errTemplateExecute),
+			},
+		},
+		"ErrorRange": {
+			args: args{
+				text: "{{ range 2000000000 }}{{ end }}",
+				i:    "abc",
+			},
+			want: want{
+				err: errors.Wrap(errors.New("range actions are not supported"), errTemplateParse),
+			},
+		},
+		"ErrorPrintfWidthTooLarge": {
+			args: args{
+				text: `{{ printf "%999999999d" 1 }}`,
+				i:    "abc",
+			},
+			want: want{
+				err: errors.Wrap(errors.New(`template: template:1:3: executing "template" at <printf "%999999999d" 1>: error calling printf: printf widths and precisions must be at most 1024`), errTemplateExecute),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveTemplate(v1.TemplateTransform{Text: tc.text}, tc.i)
+
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Resolve(b): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestConvertTransformGetConversionFunc(t *testing.T) {
 	type args struct {
 		ct   *v1.ConvertTransform
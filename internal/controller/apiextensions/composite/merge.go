@@ -78,7 +78,7 @@ func mergeOptions(pas []v1.Patch) []resource.ApplyOption {
 		if p.Policy == nil || p.ToFieldPath == nil {
 			continue
 		}
-		opts = append(opts, withMergeOptions(*p.ToFieldPath, p.Policy.MergeOptions))
+		opts = append(opts, withMergeOptions(*p.ToFieldPath, p.Policy.GetMergeOptions()))
 	}
 	return opts
 }
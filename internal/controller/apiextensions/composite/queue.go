@@ -0,0 +1,302 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+)
+
+const (
+	fieldQueuePosition = "status.queuePosition"
+	fieldEstimatedWait = "status.estimatedWait"
+
+	// An admitted composite resource that doesn't become ready within this
+	// long loses its slot, so that it can't block the queue forever.
+	defaultAdmissionDeadline = 10 * time.Minute
+)
+
+// An Admission describes whether a composite resource was admitted by an
+// AdmissionQueue.
+type Admission struct {
+	// Admitted is true if the composite resource may be composed.
+	Admitted bool
+
+	// Position of the composite resource in the queue, starting at 1. Zero if
+	// the composite resource was admitted.
+	Position int
+
+	// EstimatedWait is how long the composite resource is expected to wait
+	// before it's admitted. Zero if unknown.
+	EstimatedWait time.Duration
+}
+
+// An AdmissionQueue limits how many composite resources may be composed at
+// once. Composite resources that aren't admitted wait in the queue.
+type AdmissionQueue interface {
+	// Admit the supplied composite resource, if possible.
+	Admit(xr *composite.Unstructured) Admission
+
+	// Done indicates that the supplied composite resource is ready, or was
+	// deleted, and no longer needs to be admitted.
+	Done(xr *composite.Unstructured)
+}
+
+// A NopAdmissionQueue admits all composite resources.
+type NopAdmissionQueue struct{}
+
+// Admit always admits the supplied composite resource.
+func (q NopAdmissionQueue) Admit(_ *composite.Unstructured) Admission {
+	return Admission{Admitted: true}
+}
+
+// Done does nothing.
+func (q NopAdmissionQueue) Done(_ *composite.Unstructured) {}
+
+// A FairQueue admits a fixed number of composite resources at once. Waiting
+// composite resources are admitted round-robin across tenants, so that one
+// tenant can't starve the others. A composite resource's tenant is the
+// namespace of its claim. Composite resources without a claim share a tenant.
+// An admitted composite resource holds its slot until it's ready, or until its
+// admission deadline passes.
+type FairQueue struct {
+	mx sync.Mutex
+
+	slots    int
+	deadline time.Duration
+	admitted map[types.UID]time.Time
+
+	// Waiting composite resources, by tenant, in the order they arrived.
+	waiting map[string][]types.UID
+
+	// Tenants with waiting composite resources, in round-robin order.
+	tenants []string
+
+	// The average time admitted composite resources take to become ready.
+	average time.Duration
+
+	now func() time.Time
+}
+
+// A FairQueueOption configures a FairQueue.
+type FairQueueOption func(q *FairQueue)
+
+// WithAdmissionDeadline specifies how long an admitted composite resource may
+// hold its slot without becoming ready.
+func WithAdmissionDeadline(d time.Duration) FairQueueOption {
+	return func(q *FairQueue) {
+		q.deadline = d
+	}
+}
+
+// NewFairQueue returns a FairQueue that admits the supplied number of
+// composite resources at once.
+func NewFairQueue(slots int, o ...FairQueueOption) *FairQueue {
+	q := &FairQueue{
+		slots:    slots,
+		deadline: defaultAdmissionDeadline,
+		admitted: make(map[types.UID]time.Time),
+		waiting:  make(map[string][]types.UID),
+		now:      time.Now,
+	}
+	for _, fn := range o {
+		fn(q)
+	}
+	return q
+}
+
+// Admit the supplied composite resource if it's next in the queue and there's
+// a free slot.
+func (q *FairQueue) Admit(xr *composite.Unstructured) Admission {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+
+	q.expire()
+
+	uid := xr.GetUID()
+	if _, ok := q.admitted[uid]; ok {
+		return Admission{Admitted: true}
+	}
+
+	t := tenant(xr)
+	if !q.isWaiting(t, uid) {
+		if len(q.waiting[t]) == 0 {
+			q.tenants = append(q.tenants, t)
+		}
+		q.waiting[t] = append(q.waiting[t], uid)
+	}
+
+	pos := q.position(uid)
+	free := q.slots - len(q.admitted)
+	if pos > free {
+		return Admission{Position: pos, EstimatedWait: q.estimate(pos)}
+	}
+
+	q.remove(t, uid)
+	q.admitted[uid] = q.now()
+
+	// Move the tenant to the back of the round-robin order, so other tenants
+	// get the next free slot.
+	if len(q.waiting[t]) > 0 {
+		q.rotate(t)
+	}
+
+	return Admission{Admitted: true}
+}
+
+// Done frees the supplied composite resource's slot, or removes it from the
+// queue if it's waiting.
+func (q *FairQueue) Done(xr *composite.Unstructured) {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+
+	uid := xr.GetUID()
+	if at, ok := q.admitted[uid]; ok {
+		q.release(uid, q.now().Sub(at))
+		return
+	}
+
+	q.remove(tenant(xr), uid)
+}
+
+// expire frees the slots of admitted composite resources whose admission
+// deadline has passed. They continue to be composed, but no longer count
+// against the queue's slots.
+func (q *FairQueue) expire() {
+	if q.deadline <= 0 {
+		return
+	}
+	for uid, at := range q.admitted {
+		if q.now().Sub(at) >= q.deadline {
+			q.release(uid, q.deadline)
+		}
+	}
+}
+
+// release frees the supplied composite resource's slot, which it held for the
+// supplied duration.
+func (q *FairQueue) release(uid types.UID, held time.Duration) {
+	delete(q.admitted, uid)
+
+	// Track an exponentially weighted moving average of how long composite
+	// resources hold a slot once they're admitted.
+	if q.average == 0 {
+		q.average = held
+	} else {
+		q.average = (q.average*4 + held) / 5
+	}
+}
+
+func (q *FairQueue) isWaiting(t string, uid types.UID) bool {
+	for _, w := range q.waiting[t] {
+		if w == uid {
+			return true
+		}
+	}
+	return false
+}
+
+// position returns the supplied composite resource's position in the queue,
+// interleaving each tenant's waiting composite resources in round-robin order.
+func (q *FairQueue) position(uid types.UID) int {
+	pos := 0
+	for round := 0; ; round++ {
+		found := false
+		for _, t := range q.tenants {
+			w := q.waiting[t]
+			if round >= len(w) {
+				continue
+			}
+			found = true
+			pos++
+			if w[round] == uid {
+				return pos
+			}
+		}
+		if !found {
+			return 0
+		}
+	}
+}
+
+func (q *FairQueue) remove(t string, uid types.UID) {
+	w := q.waiting[t]
+	for i := range w {
+		if w[i] != uid {
+			continue
+		}
+		q.waiting[t] = append(w[:i], w[i+1:]...)
+		break
+	}
+	if len(q.waiting[t]) > 0 {
+		return
+	}
+	delete(q.waiting, t)
+	for i := range q.tenants {
+		if q.tenants[i] == t {
+			q.tenants = append(q.tenants[:i], q.tenants[i+1:]...)
+			return
+		}
+	}
+}
+
+func (q *FairQueue) rotate(t string) {
+	for i := range q.tenants {
+		if q.tenants[i] == t {
+			q.tenants = append(append(q.tenants[:i], q.tenants[i+1:]...), t)
+			return
+		}
+	}
+}
+
+func (q *FairQueue) estimate(pos int) time.Duration {
+	if q.average == 0 || q.slots == 0 {
+		return 0
+	}
+	// Each slot frees up about once per average time-to-ready.
+	return time.Duration((pos+q.slots-1)/q.slots) * q.average
+}
+
+// setQueueStatus records the supplied admission in the status of the supplied
+// composite resource. The queue status is removed once the composite resource
+// is admitted.
+func setQueueStatus(xr *composite.Unstructured, a Admission) {
+	p := fieldpath.Pave(xr.Object)
+	if a.Admitted {
+		_ = p.DeleteField(fieldQueuePosition)
+		_ = p.DeleteField(fieldEstimatedWait)
+		return
+	}
+	_ = p.SetValue(fieldQueuePosition, int64(a.Position))
+	if a.EstimatedWait == 0 {
+		_ = p.DeleteField(fieldEstimatedWait)
+		return
+	}
+	_ = p.SetValue(fieldEstimatedWait, a.EstimatedWait.Round(time.Second).String())
+}
+
+func tenant(xr *composite.Unstructured) string {
+	if ref := xr.GetClaimReference(); ref != nil {
+		return ref.Namespace
+	}
+	return ""
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/claim"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+)
+
+var _ AdmissionQueue = &FairQueue{}
+
+func TestFairQueue(t *testing.T) {
+	xr := func(uid, namespace string) *composite.Unstructured {
+		xr := composite.New()
+		xr.SetUID(types.UID(uid))
+		if namespace != "" {
+			xr.SetClaimReference(&claim.Reference{Namespace: namespace, Name: uid})
+		}
+		return xr
+	}
+
+	type step struct {
+		xr      *composite.Unstructured
+		done    bool
+		elapsed time.Duration
+		want    Admission
+	}
+
+	cases := map[string]struct {
+		reason   string
+		slots    int
+		deadline time.Duration
+		steps    []step
+	}{
+		"AdmitUpToSlots": {
+			reason: "Composite resources should be admitted until all slots are used.",
+			slots:  2,
+			steps: []step{
+				{xr: xr("a", "ns1"), want: Admission{Admitted: true}},
+				{xr: xr("b", "ns1"), want: Admission{Admitted: true}},
+				{xr: xr("c", "ns1"), want: Admission{Position: 1}},
+				{xr: xr("a", "ns1"), want: Admission{Admitted: true}},
+			},
+		},
+		"RoundRobinAcrossTenants": {
+			reason: "Waiting composite resources should be interleaved across tenants.",
+			slots:  1,
+			steps: []step{
+				{xr: xr("a", "ns1"), want: Admission{Admitted: true}},
+				{xr: xr("b1", "ns1"), want: Admission{Position: 1}},
+				{xr: xr("b2", "ns1"), want: Admission{Position: 2}},
+				{xr: xr("c1", "ns2"), want: Admission{Position: 2}},
+				{xr: xr("b2", "ns1"), want: Admission{Position: 3}},
+			},
+		},
+		"AdmitInOrderWhenSlotFrees": {
+			reason: "When a slot frees only the composite resource at the front of the queue should be admitted, and the wait should be estimated.",
+			slots:  1,
+			steps: []step{
+				{xr: xr("a", "ns1"), want: Admission{Admitted: true}},
+				{xr: xr("b", "ns1"), want: Admission{Position: 1}},
+				{xr: xr("c", "ns2"), want: Admission{Position: 2}},
+				{xr: xr("a", "ns1"), done: true, elapsed: 10 * time.Second},
+				{xr: xr("c", "ns2"), want: Admission{Position: 2, EstimatedWait: 20 * time.Second}},
+				{xr: xr("b", "ns1"), want: Admission{Admitted: true}},
+				{xr: xr("c", "ns2"), want: Admission{Position: 1, EstimatedWait: 10 * time.Second}},
+			},
+		},
+		"DoneWhileWaiting": {
+			reason: "A composite resource that is deleted while waiting should leave the queue.",
+			slots:  1,
+			steps: []step{
+				{xr: xr("a", ""), want: Admission{Admitted: true}},
+				{xr: xr("b", ""), want: Admission{Position: 1}},
+				{xr: xr("c", ""), want: Admission{Position: 2}},
+				{xr: xr("b", ""), done: true},
+				{xr: xr("c", ""), want: Admission{Position: 1}},
+			},
+		},
+		"AdmissionDeadline": {
+			reason:   "A composite resource that doesn't become ready before its admission deadline should lose its slot.",
+			slots:    1,
+			deadline: time.Minute,
+			steps: []step{
+				{xr: xr("a", "ns1"), want: Admission{Admitted: true}},
+				{xr: xr("b", "ns1"), elapsed: 30 * time.Second, want: Admission{Position: 1}},
+				{xr: xr("b", "ns1"), elapsed: 30 * time.Second, want: Admission{Admitted: true}},
+				{xr: xr("a", "ns1"), want: Admission{Position: 1, EstimatedWait: time.Minute}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			now := time.Now()
+			q := NewFairQueue(tc.slots, WithAdmissionDeadline(tc.deadline))
+			q.now = func() time.Time { return now }
+
+			for i, s := range tc.steps {
+				now = now.Add(s.elapsed)
+				if s.done {
+					q.Done(s.xr)
+					continue
+				}
+				got := q.Admit(s.xr)
+				if diff := cmp.Diff(s.want, got); diff != "" {
+					t.Errorf("\n%s\nstep %d: q.Admit(%s): -want, +got:\n%s", tc.reason, i, s.xr.GetUID(), diff)
+				}
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ package composite
 
 import (
 	"context"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/utils/ptr"
@@ -131,6 +132,19 @@ func ReadinessChecksFromComposedTemplate(t *v1.ComposedTemplate) []ReadinessChec
 	return out
 }
 
+// ReadinessChecksFromCompositionRevision derives the readiness checks the
+// supplied CompositionRevision specifies for the composite resource itself.
+func ReadinessChecksFromCompositionRevision(rev *v1.CompositionRevision) []ReadinessCheck {
+	if rev == nil {
+		return nil
+	}
+	out := make([]ReadinessCheck, len(rev.Spec.ReadinessChecks))
+	for i := range rev.Spec.ReadinessChecks {
+		out[i] = ReadinessCheckFromV1(&rev.Spec.ReadinessChecks[i])
+	}
+	return out
+}
+
 // TODO(negz): Ideally we'd validate P&T readiness checks (which are specified
 // in the Composition) using a webhook. We still need to validate the output of
 // a Composition Function Pipeline, though.
@@ -231,17 +245,27 @@ type ConditionedObject interface {
 	resource.Conditioned
 }
 
-// IsReady returns whether the composed resource is ready.
+// IsReady returns whether the composed resource is ready. Built-in Kubernetes
+// objects that don't have a Ready condition, like ConfigMaps and Namespaces,
+// pass any check for a Ready condition once they exist.
 func IsReady(_ context.Context, o ConditionedObject, rc ...ReadinessCheck) (bool, error) {
-	// kept as a safety net, but defaulting should ensure this is never hit
-	if len(rc) == 0 {
-		return resource.IsConditionTrue(o.GetCondition(xpv1.TypeReady)), nil
-	}
 	paved, err := fieldpath.PaveObject(o)
 	if err != nil {
 		return false, errors.Wrap(err, errPaveObject)
 	}
 
+	if IsKubernetesObject(o) && !hasCondition(paved, xpv1.TypeReady) {
+		rc = withoutReadyConditionChecks(rc)
+		if len(rc) == 0 {
+			return true, nil
+		}
+	}
+
+	// kept as a safety net, but defaulting should ensure this is never hit
+	if len(rc) == 0 {
+		return resource.IsConditionTrue(o.GetCondition(xpv1.TypeReady)), nil
+	}
+
 	for i := range rc {
 		ready, err := rc[i].IsReady(paved, o)
 		if err != nil {
@@ -253,3 +277,63 @@ func IsReady(_ context.Context, o ConditionedObject, rc ...ReadinessCheck) (bool
 	}
 	return true, nil
 }
+
+// builtinGroups are the API groups of built-in Kubernetes objects that contain
+// a dot. Custom resources can't be in an API group without a dot, but they can
+// be in a group under k8s.io, for example gateway.networking.k8s.io.
+var builtinGroups = map[string]bool{
+	"admissionregistration.k8s.io": true,
+	"apiextensions.k8s.io":         true,
+	"apiregistration.k8s.io":       true,
+	"authentication.k8s.io":        true,
+	"authorization.k8s.io":         true,
+	"certificates.k8s.io":          true,
+	"coordination.k8s.io":          true,
+	"discovery.k8s.io":             true,
+	"events.k8s.io":                true,
+	"flowcontrol.apiserver.k8s.io": true,
+	"internal.apiserver.k8s.io":    true,
+	"networking.k8s.io":            true,
+	"node.k8s.io":                  true,
+	"rbac.authorization.k8s.io":    true,
+	"resource.k8s.io":              true,
+	"scheduling.k8s.io":            true,
+	"storage.k8s.io":               true,
+	"storagemigration.k8s.io":      true,
+}
+
+// IsKubernetesObject returns true if the supplied object is a built-in
+// Kubernetes object, e.g. a ConfigMap or a NetworkPolicy. Built-in objects are
+// in the core API group, an API group without a domain like apps or batch, or
+// one of the built-in API groups under k8s.io like networking.k8s.io.
+func IsKubernetesObject(o resource.Object) bool {
+	gvk := o.GetObjectKind().GroupVersionKind()
+	if gvk.Kind == "" {
+		return false
+	}
+	return !strings.Contains(gvk.Group, ".") || builtinGroups[gvk.Group]
+}
+
+func hasCondition(p *fieldpath.Paved, ct xpv1.ConditionType) bool {
+	conditions := []xpv1.Condition{}
+	if err := p.GetValueInto("status.conditions", &conditions); err != nil {
+		return false
+	}
+	for _, c := range conditions {
+		if c.Type == ct {
+			return true
+		}
+	}
+	return false
+}
+
+func withoutReadyConditionChecks(rc []ReadinessCheck) []ReadinessCheck {
+	out := make([]ReadinessCheck, 0, len(rc))
+	for _, c := range rc {
+		if c.Type == ReadinessCheckTypeMatchCondition && c.MatchCondition != nil && c.MatchCondition.Type == xpv1.TypeReady {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
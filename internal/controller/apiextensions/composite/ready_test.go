@@ -395,6 +395,95 @@ func TestIsReady(t *testing.T) {
 				ready: false,
 			},
 		},
+		"KubernetesObjectWithoutReadyCondition": {
+			reason: "A built-in Kubernetes object without a Ready condition should pass a Ready condition check once it exists",
+			args: args{
+				o: composed.New(composed.FromReference(corev1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap"})),
+				rc: []ReadinessCheck{{
+					Type: ReadinessCheckTypeMatchCondition,
+					MatchCondition: &MatchConditionReadinessCheck{
+						Type:   xpv1.TypeReady,
+						Status: corev1.ConditionTrue,
+					},
+				}},
+			},
+			want: want{
+				ready: true,
+			},
+		},
+		"KubernetesObjectWithOtherChecks": {
+			reason: "A built-in Kubernetes object without a Ready condition should still run checks that aren't for a Ready condition",
+			args: args{
+				o: composed.New(composed.FromReference(corev1.ObjectReference{APIVersion: "networking.k8s.io/v1", Kind: "NetworkPolicy"})),
+				rc: []ReadinessCheck{
+					{
+						Type: ReadinessCheckTypeMatchCondition,
+						MatchCondition: &MatchConditionReadinessCheck{
+							Type:   xpv1.TypeReady,
+							Status: corev1.ConditionTrue,
+						},
+					},
+					{
+						Type:      ReadinessCheckTypeNonEmpty,
+						FieldPath: ptr.To("spec.podSelector"),
+					},
+				},
+			},
+			want: want{
+				ready: false,
+			},
+		},
+		"KubernetesObjectWithReadyCondition": {
+			reason: "A built-in Kubernetes object with a Ready condition should be checked like any other object",
+			args: args{
+				o: composed.New(
+					composed.FromReference(corev1.ObjectReference{APIVersion: "v1", Kind: "Pod"}),
+					composed.WithConditions(xpv1.Unavailable()),
+				),
+				rc: []ReadinessCheck{{
+					Type: ReadinessCheckTypeMatchCondition,
+					MatchCondition: &MatchConditionReadinessCheck{
+						Type:   xpv1.TypeReady,
+						Status: corev1.ConditionTrue,
+					},
+				}},
+			},
+			want: want{
+				ready: false,
+			},
+		},
+		"CustomResourceUnderK8sIOWithoutReadyCondition": {
+			reason: "A custom resource in an API group under k8s.io without a Ready condition should not pass a Ready condition check",
+			args: args{
+				o: composed.New(composed.FromReference(corev1.ObjectReference{APIVersion: "gateway.networking.k8s.io/v1", Kind: "Gateway"})),
+				rc: []ReadinessCheck{{
+					Type: ReadinessCheckTypeMatchCondition,
+					MatchCondition: &MatchConditionReadinessCheck{
+						Type:   xpv1.TypeReady,
+						Status: corev1.ConditionTrue,
+					},
+				}},
+			},
+			want: want{
+				ready: false,
+			},
+		},
+		"CustomResourceWithoutReadyCondition": {
+			reason: "A custom resource without a Ready condition should not pass a Ready condition check",
+			args: args{
+				o: composed.New(composed.FromReference(corev1.ObjectReference{APIVersion: "nop.crossplane.io/v1alpha1", Kind: "NopResource"})),
+				rc: []ReadinessCheck{{
+					Type: ReadinessCheckTypeMatchCondition,
+					MatchCondition: &MatchConditionReadinessCheck{
+						Type:   xpv1.TypeReady,
+						Status: corev1.ConditionTrue,
+					},
+				}},
+			},
+			want: want{
+				ready: false,
+			},
+		},
 		"UnknownType": {
 			reason: "If unknown type is chosen, it should return an error",
 			args: args{
@@ -36,6 +36,7 @@ import (
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
@@ -51,6 +52,7 @@ import (
 const (
 	timeout             = 2 * time.Minute
 	defaultPollInterval = 1 * time.Minute
+	queuePollInterval   = 15 * time.Second
 	finalizer           = "composite.apiextensions.crossplane.io"
 )
 
@@ -75,6 +77,7 @@ const (
 	errInvalidResources       = "some resources were invalid, check events"
 	errRenderCD               = "cannot render composed resource"
 	errSyncResources          = "cannot sync composed resources"
+	errCheckReadiness         = "cannot check whether the composite resource is ready"
 	errGetClaim               = "cannot get referenced claim"
 	errParseClaimRef          = "cannot parse claim reference"
 
@@ -96,6 +99,11 @@ const (
 	reasonFatalError xpv1.ConditionReason = "FatalError"
 )
 
+const (
+	fieldCompositionRef         = "status.compositionRef"
+	fieldCompositionRevisionRef = "status.compositionRevisionRef"
+)
+
 // ControllerName returns the recommended name for controllers that use this
 // package to reconcile a particular kind of composite resource.
 func ControllerName(name string) string {
@@ -376,6 +384,14 @@ func WithComposer(c Composer) ReconcilerOption {
 	}
 }
 
+// WithAdmissionQueue specifies how the Reconciler should limit how many
+// composite resources may be composed at once.
+func WithAdmissionQueue(q AdmissionQueue) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.queue = q
+	}
+}
+
 // WithWatchStarter specifies how the Reconciler should start watches for any
 // resources it composes.
 func WithWatchStarter(controllerName string, h handler.EventHandler, w WatchStarter) ReconcilerOption {
@@ -478,6 +494,9 @@ func NewReconciler(c client.Client, of resource.CompositeKind, opts ...Reconcile
 
 		resource: NewPTComposer(c),
 
+		// All composite resources are admitted by default.
+		queue: NopAdmissionQueue{},
+
 		// Dynamic watches are disabled by default.
 		engine: &NopWatchStarter{},
 
@@ -507,6 +526,8 @@ type Reconciler struct {
 
 	resource Composer
 
+	queue AdmissionQueue
+
 	// Used to dynamically start composed resource watches.
 	controllerName string
 	engine         WatchStarter
@@ -569,6 +590,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
 		}
 
+		r.queue.Done(xr)
+
 		log.Debug("Successfully deleted composite resource")
 		xr.SetConditions(xpv1.ReconcileSuccess())
 		return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
@@ -653,6 +676,19 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
 	}
 
+	// Composite resources must be admitted before they're first composed. Once
+	// admitted they occupy a slot in the queue until they become ready, or
+	// until the queue's admission deadline passes.
+	if len(xr.GetResourceReferences()) == 0 && xr.GetCondition(xpv1.TypeReady).Status != corev1.ConditionTrue {
+		if a := r.queue.Admit(xr); !a.Admitted {
+			log.Debug("Composite resource is waiting to be admitted", "queue-position", a.Position)
+			setQueueStatus(xr, a)
+			xr.SetConditions(xpv1.ReconcileSuccess(), xpv1.Creating().WithMessage(fmt.Sprintf("Waiting to be composed at queue position %d", a.Position)))
+			return reconcile.Result{RequeueAfter: queuePollInterval}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+		}
+	}
+	setQueueStatus(xr, Admission{Admitted: true})
+
 	res, err := r.resource.Compose(ctx, xr, CompositionRequest{Revision: rev, Environment: env})
 	if err != nil {
 		log.Debug(errCompose, "error", err)
@@ -692,6 +728,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
 	}
 
+	// Record which composition revision the XR was actually composed with.
+	// Unlike the spec's references this is never set by the user.
+	setCompositionStatus(xr, rev)
+
 	ws := make([]engine.Watch, len(xr.GetResourceReferences()))
 	for i, ref := range xr.GetResourceReferences() {
 		ws[i] = engine.WatchFor(composed.New(composed.FromReference(ref)), engine.WatchTypeComposedResource, r.watchHandler)
@@ -755,7 +795,28 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		}
 	}
 
-	if updateXRConditions(xr, unsynced, unready) {
+	// Once all of its composed resources are ready the XR must also pass any
+	// readiness checks its Composition specifies for the XR itself.
+	checksPassed := true
+	if rc := ReadinessChecksFromCompositionRevision(rev); len(unready) == 0 && len(rc) > 0 {
+		checksPassed, err = IsReady(ctx, xr, rc...)
+		if err != nil {
+			log.Debug(errCheckReadiness, "error", err)
+			err = errors.Wrap(err, errCheckReadiness)
+			r.record.Event(xr, event.Warning(reasonCompose, err))
+			xr.SetConditions(xpv1.ReconcileError(err))
+			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+		}
+		if !checksPassed {
+			log.Debug("Composite resource readiness checks are not yet passing")
+		}
+	}
+
+	if len(unready) == 0 && checksPassed {
+		r.queue.Done(xr)
+	}
+
+	if updateXRConditions(xr, unsynced, unready, checksPassed) {
 		// This requeue is subject to rate limiting. Requeues will exponentially
 		// backoff from 1 to 30 seconds. See the 'definition' (XRD) reconciler
 		// that sets up the ratelimiter.
@@ -769,9 +830,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 }
 
 // updateXRConditions updates the conditions of the supplied composite resource
-// based on the supplied composed resources. It returns true if the XR should be
-// requeued immediately.
-func updateXRConditions(xr *composite.Unstructured, unsynced, unready []ComposedResource) (requeueImmediately bool) {
+// based on the supplied composed resources, and whether the XR passed its own
+// readiness checks. It returns true if the XR should be requeued immediately.
+func updateXRConditions(xr *composite.Unstructured, unsynced, unready []ComposedResource, checksPassed bool) (requeueImmediately bool) {
 	readyCond := xpv1.Available()
 	syncedCond := xpv1.ReconcileSuccess()
 	if len(unsynced) > 0 {
@@ -786,10 +847,30 @@ func updateXRConditions(xr *composite.Unstructured, unsynced, unready []Composed
 		readyCond = xpv1.Creating().WithMessage(fmt.Sprintf("Unready resources: %s", resource.StableNAndSomeMore(resource.DefaultFirstN, getComposerResourcesNames(unready))))
 		requeueImmediately = true
 	}
+	if len(unready) == 0 && !checksPassed {
+		// We can't watch the XR's own status for changes caused by its
+		// composed resources either.
+		readyCond = xpv1.Creating().WithMessage("Composite resource readiness checks are not yet passing")
+		requeueImmediately = true
+	}
 	xr.SetConditions(syncedCond, readyCond)
 	return requeueImmediately
 }
 
+// setCompositionStatus records the supplied composition revision, and the
+// composition it's a revision of, as the ones last used to compose the supplied
+// XR.
+func setCompositionStatus(xr *composite.Unstructured, rev *v1.CompositionRevision) {
+	if rev.GetName() == "" {
+		return
+	}
+	p := fieldpath.Pave(xr.Object)
+	_ = p.SetValue(fieldCompositionRevisionRef, map[string]any{"name": rev.GetName()})
+	if name := rev.GetLabels()[v1.LabelCompositionName]; name != "" {
+		_ = p.SetValue(fieldCompositionRef, map[string]any{"name": name})
+	}
+}
+
 func getComposerResourcesNames(cds []ComposedResource) []string {
 	names := make([]string, len(cds))
 	for i, cd := range cds {
@@ -0,0 +1,217 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composition
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
+)
+
+// Error strings.
+const (
+	errListXRs           = "cannot list composite resources"
+	errDeleteRev         = "cannot delete CompositionRevision"
+	errFmtParseHistLimit = "cannot parse %s annotation"
+)
+
+// Event reasons.
+const (
+	reasonGarbageCollect event.Reason = "GarbageCollectRevisions"
+)
+
+// gcInterval is how often a Composition's old revisions are garbage collected.
+// Revisions stop being used when composite resources are updated or deleted,
+// which we don't watch.
+const gcInterval = 10 * time.Minute
+
+// SetupGarbageCollector adds a controller that garbage collects a Composition's
+// old CompositionRevisions.
+func SetupGarbageCollector(mgr ctrl.Manager, o controller.Options) error {
+	name := "gc/" + strings.ToLower(v1.CompositionRevisionGroupKind)
+
+	r := NewGarbageCollector(mgr.GetClient(), mgr.GetAPIReader(),
+		WithGCLogger(o.Logger.WithValues("controller", name)),
+		WithGCRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		WithRevisionHistoryLimit(o.CompositionRevisionHistoryLimit))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1.Composition{}).
+		Owns(&v1.CompositionRevision{}).
+		WithOptions(o.ForControllerRuntime()).
+		Complete(ratelimiter.NewReconciler(name, errors.WithSilentRequeueOnConflict(r), o.GlobalRateLimiter))
+}
+
+// A GarbageCollectorOption is used to configure the GarbageCollector.
+type GarbageCollectorOption func(*GarbageCollector)
+
+// WithGCLogger specifies how the GarbageCollector should log messages.
+func WithGCLogger(log logging.Logger) GarbageCollectorOption {
+	return func(gc *GarbageCollector) {
+		gc.log = log
+	}
+}
+
+// WithGCRecorder specifies how the GarbageCollector should record Kubernetes
+// events.
+func WithGCRecorder(er event.Recorder) GarbageCollectorOption {
+	return func(gc *GarbageCollector) {
+		gc.record = er
+	}
+}
+
+// WithRevisionHistoryLimit specifies how many old revisions of a Composition
+// to keep, unless the Composition overrides it. Zero means no limit.
+func WithRevisionHistoryLimit(l int) GarbageCollectorOption {
+	return func(gc *GarbageCollector) {
+		gc.limit = l
+	}
+}
+
+// NewGarbageCollector returns a GarbageCollector of CompositionRevisions.
+// Composite resources are listed using the supplied reader, to avoid caching
+// every kind of composite resource.
+func NewGarbageCollector(c client.Client, xrs client.Reader, opts ...GarbageCollectorOption) *GarbageCollector {
+	gc := &GarbageCollector{
+		client: c,
+		xrs:    xrs,
+		log:    logging.NewNopLogger(),
+		record: event.NewNopRecorder(),
+	}
+
+	for _, f := range opts {
+		f(gc)
+	}
+	return gc
+}
+
+// A GarbageCollector deletes a Composition's oldest CompositionRevisions once it
+// has more than its revision history limit. The latest revision, and any
+// revision that is used by a composite resource, are never deleted.
+type GarbageCollector struct {
+	client client.Client
+	xrs    client.Reader
+	limit  int
+
+	log    logging.Logger
+	record event.Recorder
+}
+
+// Reconcile a Composition's revisions.
+func (gc *GarbageCollector) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := gc.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	comp := &v1.Composition{}
+	if err := gc.client.Get(ctx, req.NamespacedName, comp); err != nil {
+		log.Debug(errGet, "error", err)
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGet)
+	}
+
+	if meta.WasDeleted(comp) {
+		return reconcile.Result{}, nil
+	}
+
+	limit := gc.limit
+	if v, ok := comp.GetAnnotations()[v1.AnnotationRevisionHistoryLimit]; ok {
+		l, err := strconv.Atoi(v)
+		if err != nil {
+			err = errors.Wrapf(err, errFmtParseHistLimit, v1.AnnotationRevisionHistoryLimit)
+			gc.record.Event(comp, event.Warning(reasonGarbageCollect, err))
+			return reconcile.Result{}, nil
+		}
+		limit = l
+	}
+	if limit <= 0 {
+		return reconcile.Result{}, nil
+	}
+
+	rl := &v1.CompositionRevisionList{}
+	if err := gc.client.List(ctx, rl, client.MatchingLabels{v1.LabelCompositionName: comp.GetName()}); err != nil {
+		log.Debug(errListRevs, "error", err)
+		return reconcile.Result{}, errors.Wrap(err, errListRevs)
+	}
+
+	// The latest revision is always kept, in addition to limit old revisions.
+	if len(rl.Items) <= limit+1 {
+		return reconcile.Result{RequeueAfter: gcInterval}, nil
+	}
+
+	used, err := gc.usedRevisions(ctx, comp)
+	if err != nil {
+		log.Debug(errListXRs, "error", err)
+		return reconcile.Result{}, errors.Wrap(err, errListXRs)
+	}
+
+	revs := rl.Items
+	sort.Slice(revs, func(i, j int) bool { return revs[i].Spec.Revision > revs[j].Spec.Revision })
+	for i := limit + 1; i < len(revs); i++ {
+		if used[revs[i].GetName()] {
+			continue
+		}
+		if err := gc.client.Delete(ctx, &revs[i]); resource.IgnoreNotFound(err) != nil {
+			log.Debug(errDeleteRev, "error", err, "revision", revs[i].GetName())
+			gc.record.Event(comp, event.Warning(reasonGarbageCollect, errors.Wrap(err, errDeleteRev)))
+			return reconcile.Result{}, errors.Wrap(err, errDeleteRev)
+		}
+		log.Debug("Deleted old revision", "revision", revs[i].GetName())
+	}
+
+	return reconcile.Result{RequeueAfter: gcInterval}, nil
+}
+
+// usedRevisions returns the names of the revisions used by composite resources
+// of the type the supplied Composition composes.
+func (gc *GarbageCollector) usedRevisions(ctx context.Context, comp *v1.Composition) (map[string]bool, error) {
+	l := &unstructured.UnstructuredList{}
+	l.SetAPIVersion(comp.Spec.CompositeTypeRef.APIVersion)
+	l.SetKind(comp.Spec.CompositeTypeRef.Kind + "List")
+	if err := gc.xrs.List(ctx, l); err != nil {
+		return nil, err
+	}
+
+	used := make(map[string]bool)
+	for i := range l.Items {
+		xr := composite.Unstructured{Unstructured: l.Items[i]}
+		if ref := xr.GetCompositionRevisionReference(); ref != nil {
+			used[ref.Name] = true
+		}
+	}
+	return used, nil
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composition
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestGarbageCollectorReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	comp := func(annotations map[string]string) func(context.Context, client.ObjectKey, client.Object) error {
+		return test.NewMockGetFn(nil, func(obj client.Object) error {
+			c := obj.(*v1.Composition)
+			c.SetName("cool-composition")
+			c.SetAnnotations(annotations)
+			c.Spec.CompositeTypeRef = v1.TypeReference{APIVersion: "example.org/v1", Kind: "XCool"}
+			return nil
+		})
+	}
+
+	revs := func(n int) func(context.Context, client.ObjectList, ...client.ListOption) error {
+		return func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+			l := obj.(*v1.CompositionRevisionList)
+			for i := 1; i <= n; i++ {
+				l.Items = append(l.Items, v1.CompositionRevision{
+					ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("cool-composition-%d", i)},
+					Spec:       v1.CompositionRevisionSpec{Revision: int64(i)},
+				})
+			}
+			return nil
+		}
+	}
+
+	xrs := func(revisions ...string) client.Reader {
+		return &test.MockClient{MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+			l := obj.(*unstructured.UnstructuredList)
+			if l.GetKind() != "XCoolList" {
+				return errors.Errorf("unexpected kind %s", l.GetKind())
+			}
+			for _, rev := range revisions {
+				xr := unstructured.Unstructured{Object: map[string]any{}}
+				_ = unstructured.SetNestedField(xr.Object, rev, "spec", "compositionRevisionRef", "name")
+				l.Items = append(l.Items, xr)
+			}
+			return nil
+		}}
+	}
+
+	type args struct {
+		client client.Client
+		xrs    client.Reader
+		limit  int
+	}
+	type want struct {
+		r       reconcile.Result
+		err     error
+		deleted []string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"CompositionNotFound": {
+			reason: "We should not return an error if the Composition was not found.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ""))},
+				limit:  1,
+			},
+			want: want{r: reconcile.Result{}},
+		},
+		"NoLimit": {
+			reason: "We should not garbage collect revisions if there is no history limit.",
+			args: args{
+				client: &test.MockClient{MockGet: comp(nil), MockList: revs(5)},
+			},
+			want: want{r: reconcile.Result{}},
+		},
+		"InvalidAnnotation": {
+			reason: "We should not garbage collect revisions if the history limit annotation is invalid.",
+			args: args{
+				client: &test.MockClient{
+					MockGet:  comp(map[string]string{v1.AnnotationRevisionHistoryLimit: "many"}),
+					MockList: revs(5),
+				},
+				limit: 1,
+			},
+			want: want{r: reconcile.Result{}},
+		},
+		"ListRevisionsError": {
+			reason: "We should return any error encountered listing revisions.",
+			args: args{
+				client: &test.MockClient{MockGet: comp(nil), MockList: test.NewMockListFn(errBoom)},
+				limit:  1,
+			},
+			want: want{err: errors.Wrap(errBoom, errListRevs)},
+		},
+		"WithinLimit": {
+			reason: "We should not delete any revisions if there are no more than the history limit plus the latest revision.",
+			args: args{
+				client: &test.MockClient{MockGet: comp(nil), MockList: revs(3)},
+				limit:  2,
+			},
+			want: want{r: reconcile.Result{RequeueAfter: gcInterval}},
+		},
+		"ListXRsError": {
+			reason: "We should return any error encountered listing composite resources.",
+			args: args{
+				client: &test.MockClient{MockGet: comp(nil), MockList: revs(5)},
+				xrs:    &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				limit:  1,
+			},
+			want: want{err: errors.Wrap(errBoom, errListXRs)},
+		},
+		"DeleteUnusedRevisions": {
+			reason: "We should delete the oldest revisions beyond the history limit that aren't used by a composite resource.",
+			args: args{
+				client: &test.MockClient{MockGet: comp(nil), MockList: revs(5), MockDelete: test.NewMockDeleteFn(nil)},
+				xrs:    xrs("cool-composition-2"),
+				limit:  1,
+			},
+			want: want{
+				r:       reconcile.Result{RequeueAfter: gcInterval},
+				deleted: []string{"cool-composition-1", "cool-composition-3"},
+			},
+		},
+		"AnnotationOverridesLimit": {
+			reason: "A Composition's history limit annotation should override the default limit.",
+			args: args{
+				client: &test.MockClient{
+					MockGet:    comp(map[string]string{v1.AnnotationRevisionHistoryLimit: "3"}),
+					MockList:   revs(5),
+					MockDelete: test.NewMockDeleteFn(nil),
+				},
+				xrs: xrs(),
+			},
+			want: want{
+				r:       reconcile.Result{RequeueAfter: gcInterval},
+				deleted: []string{"cool-composition-1"},
+			},
+		},
+		"DeleteError": {
+			reason: "We should return any error encountered deleting a revision.",
+			args: args{
+				client: &test.MockClient{MockGet: comp(nil), MockList: revs(3), MockDelete: test.NewMockDeleteFn(errBoom)},
+				xrs:    xrs(),
+				limit:  1,
+			},
+			want: want{err: errors.Wrap(errBoom, errDeleteRev)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var deleted []string
+			if mc, ok := tc.args.client.(*test.MockClient); ok && mc.MockDelete != nil {
+				del := mc.MockDelete
+				mc.MockDelete = func(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+					if err := del(ctx, obj, opts...); err != nil {
+						return err
+					}
+					deleted = append(deleted, obj.GetName())
+					return nil
+				}
+			}
+
+			gc := NewGarbageCollector(tc.args.client, tc.args.xrs, WithRevisionHistoryLimit(tc.args.limit))
+			got, err := gc.Reconcile(context.Background(), reconcile.Request{})
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ngc.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.r, got); diff != "" {
+				t.Errorf("\n%s\ngc.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			sort.Strings(deleted)
+			if diff := cmp.Diff(tc.want.deleted, deleted); diff != "" {
+				t.Errorf("\n%s\ngc.Reconcile(...): -want deleted, +got deleted:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -18,9 +18,16 @@ limitations under the License.
 package controller
 
 import (
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	crcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
+
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 
 	"github.com/crossplane/crossplane/internal/engine"
+	"github.com/crossplane/crossplane/internal/metrics"
+	"github.com/crossplane/crossplane/internal/notify"
 	"github.com/crossplane/crossplane/internal/xfn"
 )
 
@@ -31,6 +38,64 @@ type Options struct {
 	// ControllerEngine used to dynamically start and stop controllers.
 	ControllerEngine *engine.ControllerEngine
 
+	// Namespace Crossplane is running in. Patch traces of composite resources
+	// that are being debugged are written to ConfigMaps in this namespace.
+	Namespace string
+
 	// FunctionRunner used to run Composition Functions.
 	FunctionRunner *xfn.PackagedFunctionRunner
+
+	// MaxComposingComposites is the maximum number of composite resources of
+	// each kind that may be waiting to become ready at once. Others wait in a
+	// queue. Zero means there is no limit.
+	MaxComposingComposites int
+
+	// MaxConcurrentComposedApplies is the maximum number of composed
+	// resources each composite resource may apply at once.
+	MaxConcurrentComposedApplies int
+
+	// MaxRequeueDelay caps how long controllers back off before requeueing a
+	// resource. Zero means the default of 60 seconds, or 30 seconds for
+	// composite resources.
+	MaxRequeueDelay time.Duration
+
+	// CompositionRevisionHistoryLimit is the number of old revisions of each
+	// Composition to keep. Compositions may override it using an annotation.
+	// Zero means all revisions are kept.
+	CompositionRevisionHistoryLimit int
+
+	// FairClaimQueues dispatches claims to be reconciled round-robin across
+	// namespaces, rather than in the order they were queued.
+	FairClaimQueues bool
+
+	// ClaimNamespaceWeights is how many claims from each namespace are
+	// dispatched in a row when FairClaimQueues is enabled. Namespaces that
+	// aren't listed have weight one.
+	ClaimNamespaceWeights map[string]int
+
+	// WebhookEnabled indicates whether Crossplane's webhook server is running.
+	// The immutable fields of composite resources and claims are enforced by
+	// a webhook, so they're only enforced if it is.
+	WebhookEnabled bool
+
+	// QueueMetrics records the depth of each controller's work queue, and the
+	// age of the oldest item in it. Optional.
+	QueueMetrics *metrics.QueueMetrics
+
+	// Notifier notifies external systems of lifecycle events. Optional.
+	Notifier notify.Notifier
+}
+
+// ForControllerRuntime extracts options for controller-runtime. Requeues back
+// off for at most MaxRequeueDelay, if it's set. Work queues are tracked by
+// QueueMetrics, if it's set.
+func (o Options) ForControllerRuntime() crcontroller.Options {
+	co := o.Options.ForControllerRuntime()
+	if o.MaxRequeueDelay > 0 {
+		co.RateLimiter = workqueue.NewItemExponentialFailureRateLimiter(min(1*time.Second, o.MaxRequeueDelay), o.MaxRequeueDelay)
+	}
+	if o.QueueMetrics != nil {
+		co.NewQueue = o.QueueMetrics.NewQueueFn(nil)
+	}
+	return co
 }
@@ -0,0 +1,58 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestForControllerRuntime(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		o      Options
+		want   time.Duration
+	}{
+		"DefaultMaxRequeueDelay": {
+			reason: "Requeues should back off for up to 60 seconds by default.",
+			o:      Options{},
+			want:   60 * time.Second,
+		},
+		"MaxRequeueDelay": {
+			reason: "Requeues should back off for at most the maximum requeue delay, if it's set.",
+			o:      Options{MaxRequeueDelay: 5 * time.Second},
+			want:   5 * time.Second,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rl := tc.o.ForControllerRuntime().RateLimiter
+
+			// Back off until we hit the maximum delay.
+			var got time.Duration
+			for range 20 {
+				got = rl.When("resource")
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nForControllerRuntime(): -want max requeue delay, +got max requeue delay:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	kmeta "k8s.io/apimachinery/pkg/api/meta"
@@ -53,6 +54,10 @@ import (
 	apiextensionscontroller "github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
 	"github.com/crossplane/crossplane/internal/engine"
 	"github.com/crossplane/crossplane/internal/features"
+	"github.com/crossplane/crossplane/internal/immutable"
+	"github.com/crossplane/crossplane/internal/names"
+	"github.com/crossplane/crossplane/internal/notify"
+	"github.com/crossplane/crossplane/internal/plan"
 	"github.com/crossplane/crossplane/internal/xcrd"
 )
 
@@ -76,6 +81,7 @@ const (
 	errDeleteCRs                      = "cannot delete defined composite resources"
 	errListCRDs                       = "cannot list CustomResourceDefinitions"
 	errCannotAddInformerLoopToManager = "cannot add resources informer loop to manager"
+	errConfigureWebhook               = "cannot configure webhooks"
 )
 
 // Wait strings.
@@ -102,9 +108,38 @@ type ControllerEngine interface {
 	StartWatches(name string, ws ...engine.Watch) error
 	StopWatches(ctx context.Context, name string, ws ...engine.WatchID) (int, error)
 	GetClient() client.Client
+	GetComposedResourceClient() client.Client
 	GetFieldIndexer() client.FieldIndexer
 }
 
+// A WebhookConfigurator configures the webhooks that handle a
+// CompositeResourceDefinition's composite resources and claims, for example
+// the webhook that enforces its immutable fields.
+type WebhookConfigurator interface {
+	Configure(ctx context.Context, d *v1.CompositeResourceDefinition) error
+}
+
+// WebhookConfigurators configures several webhooks.
+type WebhookConfigurators []WebhookConfigurator
+
+// Configure each webhook in order, returning the first error encountered.
+func (cs WebhookConfigurators) Configure(ctx context.Context, d *v1.CompositeResourceDefinition) error {
+	for _, c := range cs {
+		if err := c.Configure(ctx, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// A NopWebhookConfigurator does nothing.
+type NopWebhookConfigurator struct{}
+
+// Configure does nothing.
+func (NopWebhookConfigurator) Configure(_ context.Context, _ *v1.CompositeResourceDefinition) error {
+	return nil
+}
+
 // A NopEngine does nothing.
 type NopEngine struct{}
 
@@ -133,6 +168,11 @@ func (e *NopEngine) GetClient() client.Client {
 	return nil
 }
 
+// GetComposedResourceClient returns a nil client.
+func (e *NopEngine) GetComposedResourceClient() client.Client {
+	return nil
+}
+
 // GetFieldIndexer returns a nil field indexer.
 func (e *NopEngine) GetFieldIndexer() client.FieldIndexer {
 	return nil
@@ -159,11 +199,23 @@ func (fn CRDRenderFn) Render(d *v1.CompositeResourceDefinition) (*extv1.CustomRe
 func Setup(mgr ctrl.Manager, o apiextensionscontroller.Options) error {
 	name := "defined/" + strings.ToLower(v1.CompositeResourceDefinitionGroupKind)
 
-	r := NewReconciler(NewClientApplicator(mgr.GetClient()),
+	ro := []ReconcilerOption{
 		WithLogger(o.Logger.WithValues("controller", name)),
 		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 		WithControllerEngine(o.ControllerEngine),
-		WithOptions(o))
+		WithOptions(o),
+	}
+	if o.Notifier != nil {
+		ro = append(ro, WithNotifier(o.Notifier))
+	}
+	if o.WebhookEnabled {
+		ro = append(ro, WithWebhookConfigurator(WebhookConfigurators{
+			immutable.NewWebhookConfigurator(mgr.GetClient()),
+			plan.NewWebhookConfigurator(mgr.GetClient()),
+		}))
+	}
+
+	r := NewReconciler(NewClientApplicator(mgr.GetClient()), ro...)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
@@ -190,6 +242,14 @@ func WithRecorder(er event.Recorder) ReconcilerOption {
 	}
 }
 
+// WithNotifier specifies how the Reconciler should notify external systems
+// that a CompositeResourceDefinition was established.
+func WithNotifier(n notify.Notifier) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.notify = n
+	}
+}
+
 // WithOptions lets the Reconciler know which options to pass to new composite
 // resource controllers.
 func WithOptions(o apiextensionscontroller.Options) ReconcilerOption {
@@ -222,6 +282,15 @@ func WithCRDRenderer(c CRDRenderer) ReconcilerOption {
 	}
 }
 
+// WithWebhookConfigurator specifies how the Reconciler should configure the
+// webhooks that handle a CompositeResourceDefinition's composite resources and
+// claims.
+func WithWebhookConfigurator(c WebhookConfigurator) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.webhooks = c
+	}
+}
+
 type definition struct {
 	CRDRenderer
 	resource.Finalizer
@@ -244,10 +313,12 @@ func NewReconciler(ca resource.ClientApplicator, opts ...ReconcilerOption) *Reco
 			Finalizer:   resource.NewAPIFinalizer(ca, finalizer),
 		},
 
-		engine: &NopEngine{},
+		engine:   &NopEngine{},
+		webhooks: NopWebhookConfigurator{},
 
 		log:    logging.NewNopLogger(),
 		record: event.NewNopRecorder(),
+		notify: notify.NopNotifier{},
 
 		options: apiextensionscontroller.Options{
 			Options: controller.DefaultOptions(),
@@ -273,8 +344,11 @@ type Reconciler struct {
 
 	engine ControllerEngine
 
+	webhooks WebhookConfigurator
+
 	log    logging.Logger
 	record event.Recorder
+	notify notify.Notifier
 
 	options apiextensionscontroller.Options
 }
@@ -447,6 +521,13 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{Requeue: true}, nil
 	}
 
+	if err := r.webhooks.Configure(ctx, d); err != nil {
+		log.Debug(errConfigureWebhook, "error", err)
+		err = errors.Wrap(err, errConfigureWebhook)
+		r.record.Event(d, event.Warning(reasonEstablishXR, err))
+		return reconcile.Result{}, err
+	}
+
 	observed := d.Status.Controllers.CompositeResourceTypeRef
 	desired := v1.TypeReferenceTo(d.GetCompositeGroupVersionKind())
 	if observed.APIVersion != "" && observed != desired {
@@ -477,8 +558,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	// to errors. It also rate limits requeues due to a reconcile returning
 	// {Requeue: true}. The XR reconciler returns {Requeue: true} while waiting
 	// for composed resources to become ready, and we don't want to back off as
-	// far as 60 seconds. Instead we cap the XR reconciler at 30 seconds.
-	ko.RateLimiter = workqueue.NewItemExponentialFailureRateLimiter(1*time.Second, 30*time.Second)
+	// far as 60 seconds. Instead we cap the XR reconciler at 30 seconds, or
+	// less if configured.
+	maxDelay := compositeMaxRequeueDelay(r.options.MaxRequeueDelay)
+	ko.RateLimiter = workqueue.NewItemExponentialFailureRateLimiter(min(1*time.Second, maxDelay), maxDelay)
 	ko.Reconciler = ratelimiter.NewReconciler(composite.ControllerName(d.GetName()), errors.WithSilentRequeueOnConflict(cr), r.options.GlobalRateLimiter)
 
 	xrGVK := d.GetCompositeGroupVersionKind()
@@ -522,6 +605,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 
 	log.Debug("Started composite resource controller")
 
+	if d.Status.GetCondition(v1.TypeEstablished).Status != corev1.ConditionTrue {
+		e := notify.Event{
+			Type:    notify.CompositeResourceDefinitionEstablished,
+			Name:    d.GetName(),
+			Message: "Started composite resource controller for " + xrGVK.String(),
+		}
+		e.APIVersion, e.Kind = v1.CompositeResourceDefinitionGroupVersionKind.ToAPIVersionAndKind()
+		r.notify.Notify(ctx, e)
+	}
+
 	d.Status.Controllers.CompositeResourceTypeRef = v1.TypeReferenceTo(d.GetCompositeGroupVersionKind())
 	d.Status.SetConditions(v1.WatchingComposite())
 	return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, d), errUpdateStatus)
@@ -543,6 +636,10 @@ func (r *Reconciler) CompositeReconcilerOptions(ctx context.Context, d *v1.Compo
 		composite.WithPollInterval(r.options.PollInterval),
 	}
 
+	if n := r.options.MaxComposingComposites; n > 0 {
+		o = append(o, composite.WithAdmissionQueue(composite.NewFairQueue(n)))
+	}
+
 	// We only want to enable Composition environment support if the relevant
 	// feature flag is enabled. Otherwise we will default to noop selector and
 	// fetcher that will always return nil. All environment features are
@@ -553,6 +650,14 @@ func (r *Reconciler) CompositeReconcilerOptions(ctx context.Context, d *v1.Compo
 			composite.WithEnvironmentFetcher(composite.NewAPIEnvironmentFetcher(r.engine.GetClient())))
 	}
 
+	// CompositionRevisionOverrides may pin an XR to a revision regardless of
+	// the revision it would otherwise use.
+	if r.options.Features.Enabled(features.EnableAlphaCompositionRevisionOverrides) {
+		c := r.engine.GetClient()
+		o = append(o, composite.WithCompositionRevisionFetcher(composite.NewOverridingRevisionFetcher(c,
+			composite.NewAPIRevisionFetcher(resource.ClientApplicator{Client: c, Applicator: resource.NewAPIPatchingApplicator(c)}))))
+	}
+
 	// If external secret stores aren't enabled we just fetch connection details
 	// from Kubernetes secrets.
 	var fetcher managed.ConnectionDetailsFetcher = composite.NewSecretConnectionDetailsFetcher(r.engine.GetClient())
@@ -588,8 +693,21 @@ func (r *Reconciler) CompositeReconcilerOptions(ctx context.Context, d *v1.Compo
 			composite.WithConfigurator(cc))
 	}
 
+	// Composed resources are read using a client that may only be able to
+	// read composed resources, i.e. resources with the composite label. This
+	// avoids caching every resource of each composed kind. Every read of a
+	// composed resource must use this client, including the reads made to
+	// generate names and to apply composed resources.
+	cdc := r.engine.GetComposedResourceClient()
+
 	// This composer is used for mode: Resources Compositions (the default).
-	ptc := composite.NewPTComposer(r.engine.GetClient(), composite.WithComposedConnectionDetailsFetcher(fetcher))
+	ptc := composite.NewPTComposer(r.engine.GetClient(),
+		composite.WithComposedResourceClient(cdc),
+		composite.WithComposedNameGenerator(names.NewNameGenerator(cdc)),
+		composite.WithTemplateAssociator(composite.NewGarbageCollectingAssociator(cdc)),
+		composite.WithComposedConnectionDetailsFetcher(fetcher),
+		composite.WithPatchTraceWriter(composite.NewConfigMapPatchTraceWriter(r.engine.GetClient(), r.options.Namespace)),
+		composite.WithMaxConcurrentComposedApplies(r.options.MaxConcurrentComposedApplies))
 
 	// Wrap the PackagedFunctionRunner setup in main with support for loading
 	// extra resources to satisfy function requirements.
@@ -597,8 +715,12 @@ func (r *Reconciler) CompositeReconcilerOptions(ctx context.Context, d *v1.Compo
 
 	// This composer is used for mode: Pipeline Compositions.
 	fc := composite.NewFunctionComposer(r.engine.GetClient(), runner,
-		composite.WithComposedResourceObserver(composite.NewExistingComposedResourceObserver(r.engine.GetClient(), fetcher)),
+		composite.WithComposedResourceObserver(composite.NewExistingComposedResourceObserver(cdc, fetcher)),
+		composite.WithComposedResourceGarbageCollector(composite.NewDeletingComposedResourceGarbageCollector(cdc)),
+		composite.WithComposedResourceNameGenerator(names.NewNameGenerator(cdc)),
+		composite.WithManagedFieldsUpgrader(composite.NewPatchingManagedFieldsUpgrader(cdc)),
 		composite.WithCompositeConnectionDetailsFetcher(fetcher),
+		composite.WithMaxConcurrentComposedResourceApplies(r.options.MaxConcurrentComposedApplies),
 	)
 
 	// We use two different Composer implementations. One supports P&T (aka
@@ -640,3 +762,13 @@ func (r *Reconciler) CompositeReconcilerOptions(ctx context.Context, d *v1.Compo
 
 	return o
 }
+
+// compositeMaxRequeueDelay returns how long the XR reconciler may back off
+// before requeueing an XR. It's 30 seconds, or the supplied maximum requeue
+// delay if that's shorter.
+func compositeMaxRequeueDelay(maxDelay time.Duration) time.Duration {
+	if maxDelay > 0 && maxDelay < 30*time.Second {
+		return maxDelay
+	}
+	return 30 * time.Second
+}
@@ -19,6 +19,7 @@ package definition
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -43,6 +44,12 @@ var (
 	_ ControllerEngine = &NopEngine{}
 )
 
+type WebhookConfiguratorFn func(ctx context.Context, d *v1.CompositeResourceDefinition) error
+
+func (fn WebhookConfiguratorFn) Configure(ctx context.Context, d *v1.CompositeResourceDefinition) error {
+	return fn(ctx, d)
+}
+
 type MockEngine struct {
 	MockStart           func(name string, o ...engine.ControllerOption) error
 	MockStop            func(ctx context.Context, name string) error
@@ -51,6 +58,7 @@ type MockEngine struct {
 	MockStartWatches    func(name string, ws ...engine.Watch) error
 	MockStopWatches     func(ctx context.Context, name string, ws ...engine.WatchID) (int, error)
 	MockGetClient       func() client.Client
+	MockGetCDClient     func() client.Client
 	MockGetFieldIndexer func() client.FieldIndexer
 }
 
@@ -82,6 +90,10 @@ func (m *MockEngine) GetClient() client.Client {
 	return m.MockGetClient()
 }
 
+func (m *MockEngine) GetComposedResourceClient() client.Client {
+	return m.MockGetCDClient()
+}
+
 func (m *MockEngine) GetFieldIndexer() client.FieldIndexer {
 	return m.MockGetFieldIndexer()
 }
@@ -593,6 +605,40 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: true},
 			},
 		},
+		"ConfigureWebhookError": {
+			reason: "We should return any error we encounter while configuring webhooks.",
+			args: args{
+				ca: resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+					},
+					Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+						return nil
+					}),
+				},
+				opts: []ReconcilerOption{
+					WithCRDRenderer(CRDRenderFn(func(_ *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+						return &extv1.CustomResourceDefinition{
+							Status: extv1.CustomResourceDefinitionStatus{
+								Conditions: []extv1.CustomResourceDefinitionCondition{
+									{Type: extv1.Established, Status: extv1.ConditionTrue},
+								},
+							},
+						}, nil
+					})),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithWebhookConfigurator(WebhookConfiguratorFn(func(_ context.Context, _ *v1.CompositeResourceDefinition) error {
+						return errBoom
+					})),
+				},
+			},
+			want: want{
+				r:   reconcile.Result{},
+				err: errors.Wrap(errBoom, errConfigureWebhook),
+			},
+		},
 		"VersionChangedStopControllerError": {
 			reason: "We should return any error we encounter while stopping our controller because the XRD's referencable version changed.",
 			args: args{
@@ -688,7 +734,8 @@ func TestReconcile(t *testing.T) {
 						MockStart: func(_ string, _ ...engine.ControllerOption) error {
 							return errBoom
 						},
-						MockGetClient: func() client.Client { return test.NewMockClient() },
+						MockGetClient:   func() client.Client { return test.NewMockClient() },
+						MockGetCDClient: func() client.Client { return test.NewMockClient() },
 					}),
 				},
 			},
@@ -729,7 +776,8 @@ func TestReconcile(t *testing.T) {
 						MockStartWatches: func(_ string, _ ...engine.Watch) error {
 							return errBoom
 						},
-						MockGetClient: func() client.Client { return test.NewMockClient() },
+						MockGetClient:   func() client.Client { return test.NewMockClient() },
+						MockGetCDClient: func() client.Client { return test.NewMockClient() },
 					}),
 				},
 			},
@@ -776,6 +824,7 @@ func TestReconcile(t *testing.T) {
 						MockStart:        func(_ string, _ ...engine.ControllerOption) error { return nil },
 						MockStartWatches: func(_ string, _ ...engine.Watch) error { return nil },
 						MockGetClient:    func() client.Client { return test.NewMockClient() },
+						MockGetCDClient:  func() client.Client { return test.NewMockClient() },
 					}),
 				},
 			},
@@ -835,6 +884,7 @@ func TestReconcile(t *testing.T) {
 						MockIsRunning:    func(_ string) bool { return false },
 						MockStartWatches: func(_ string, _ ...engine.Watch) error { return nil },
 						MockGetClient:    func() client.Client { return test.NewMockClient() },
+						MockGetCDClient:  func() client.Client { return test.NewMockClient() },
 					}),
 				},
 			},
@@ -904,3 +954,35 @@ func TestReconcile(t *testing.T) {
 		})
 	}
 }
+
+func TestCompositeMaxRequeueDelay(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		maxDelay time.Duration
+		want     time.Duration
+	}{
+		"Unset": {
+			reason: "XRs should back off for up to 30 seconds by default.",
+			want:   30 * time.Second,
+		},
+		"Shorter": {
+			reason:   "XRs should back off for at most the maximum requeue delay if it's shorter than 30 seconds.",
+			maxDelay: 5 * time.Second,
+			want:     5 * time.Second,
+		},
+		"Longer": {
+			reason:   "XRs should back off for at most 30 seconds even if the maximum requeue delay is longer.",
+			maxDelay: time.Minute,
+			want:     30 * time.Second,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := compositeMaxRequeueDelay(tc.maxDelay)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ncompositeMaxRequeueDelay(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
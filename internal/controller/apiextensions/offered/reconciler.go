@@ -29,6 +29,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -444,20 +445,6 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		)
 	}
 
-	// We only want to enable ExternalSecretStore support if the relevant
-	// feature flag is enabled. Otherwise, we start the Claim reconcilers with
-	// their default Connection Propagator.
-	if r.options.Features.Enabled(features.EnableAlphaExternalSecretStores) {
-		pc := claim.ConnectionPropagatorChain{
-			claim.NewAPIConnectionPropagator(r.engine.GetClient()),
-			connection.NewDetailsManager(r.engine.GetClient(), secretsv1alpha1.StoreConfigGroupVersionKind, connection.WithTLSConfig(r.options.ESSOptions.TLSConfig)),
-		}
-
-		o = append(o, claim.WithConnectionPropagator(pc), claim.WithConnectionUnpublisher(
-			claim.NewSecretStoreConnectionUnpublisher(connection.NewDetailsManager(r.engine.GetClient(),
-				secretsv1alpha1.StoreConfigGroupVersionKind, connection.WithTLSConfig(r.options.ESSOptions.TLSConfig)))))
-	}
-
 	observed := d.Status.Controllers.CompositeResourceClaimTypeRef
 	desired := v1.TypeReferenceTo(d.GetClaimGroupVersionKind())
 	if observed.APIVersion != "" && observed != desired {
@@ -477,12 +464,44 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, d), errUpdateStatus)
 	}
 
+	// The XRD's claim connection secret policy determines which claims receive
+	// connection details, and which keys they receive.
+	po := []claim.APIConnectionPropagatorOption{claim.WithConnectionSecretKeys(d.GetClaimConnectionSecretKeys()...)}
+	if d.GetClaimConnectionSecretMode() == v1.ClaimConnectionSecretAutomatic {
+		po = append(po, claim.WithAutomaticConnectionSecrets())
+	}
+	var pc claim.ConnectionPropagator = claim.NewAPIConnectionPropagator(r.engine.GetClient(), po...)
+
+	// We only want to enable ExternalSecretStore support if the relevant
+	// feature flag is enabled. Otherwise, we start the Claim reconcilers with
+	// only the API Connection Propagator.
+	if r.options.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		pc = claim.ConnectionPropagatorChain{
+			pc,
+			connection.NewDetailsManager(r.engine.GetClient(), secretsv1alpha1.StoreConfigGroupVersionKind, connection.WithTLSConfig(r.options.ESSOptions.TLSConfig)),
+		}
+
+		o = append(o, claim.WithConnectionUnpublisher(
+			claim.NewSecretStoreConnectionUnpublisher(connection.NewDetailsManager(r.engine.GetClient(),
+				secretsv1alpha1.StoreConfigGroupVersionKind, connection.WithTLSConfig(r.options.ESSOptions.TLSConfig)))))
+	}
+
+	if d.GetClaimConnectionSecretMode() == v1.ClaimConnectionSecretNone {
+		pc = claim.NewNopConnectionPropagator()
+	}
+	o = append(o, claim.WithConnectionPropagator(pc))
+
 	cr := claim.NewReconciler(r.engine.GetClient(),
 		resource.CompositeClaimKind(d.GetClaimGroupVersionKind()),
 		resource.CompositeKind(d.GetCompositeGroupVersionKind()), o...)
 
 	ko := r.options.ForControllerRuntime()
 	ko.Reconciler = ratelimiter.NewReconciler(claim.ControllerName(d.GetName()), errors.WithSilentRequeueOnConflict(cr), r.options.GlobalRateLimiter)
+	if r.options.FairClaimQueues {
+		ko.NewQueue = r.options.QueueMetrics.NewQueueFn(func(_ string) workqueue.Interface {
+			return claim.NewNamespaceFairQueue(r.options.ClaimNamespaceWeights)
+		})
+	}
 
 	if err := r.engine.Start(claim.ControllerName(d.GetName()), engine.WithRuntimeOptions(ko)); err != nil {
 		err = errors.Wrap(err, errStartController)
@@ -18,11 +18,23 @@ limitations under the License.
 package controller
 
 import (
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/workqueue"
+	crcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
+
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 
+	"github.com/crossplane/crossplane/internal/metrics"
+	"github.com/crossplane/crossplane/internal/notify"
 	"github.com/crossplane/crossplane/internal/xpkg"
 )
 
+// httpSourceTimeout is how long downloading a package image from an https://
+// URL may take.
+const httpSourceTimeout = 5 * time.Minute
+
 // Options specific to pkg controllers.
 type Options struct {
 	controller.Options
@@ -30,6 +42,11 @@ type Options struct {
 	// Cache for package OCI images.
 	Cache xpkg.PackageCache
 
+	// ImageCache is an optional cache of package images, keyed by digest. It
+	// allows package images to be fetched without pulling them from their
+	// registry again.
+	ImageCache xpkg.ImageCache
+
 	// Namespace used to unpack and run packages.
 	Namespace string
 
@@ -43,10 +60,132 @@ type Options struct {
 	// NewK8sFetcher.
 	FetcherOptions []xpkg.FetcherOpt
 
+	// PackageImageDir is a directory from which package images may be loaded,
+	// e.g. a pre-loaded volume. Packages can't be loaded from paths if it's
+	// unset.
+	PackageImageDir string
+
+	// DownloadDir is the directory in which package images downloaded from
+	// https:// URLs are spooled while they're read.
+	DownloadDir string
+
+	// RemoteCache is an optional pull-through registry cache used to fetch
+	// packages before falling back to their upstream registry.
+	RemoteCache *xpkg.RemoteCache
+
 	// PackageRuntime specifies the runtime to use for package runtime.
 	PackageRuntime PackageRuntime
 
+	// PackageRuntimePlatforms are the platforms, in os[/arch] form, that
+	// package runtime pods may be scheduled to.
+	PackageRuntimePlatforms []string
+
 	// MaxConcurrentPackageEstablishers is the maximum number of goroutines to use
 	// for establishing Providers, Configurations and Functions.
 	MaxConcurrentPackageEstablishers int
+
+	// ProviderDrainPeriod is how long an inactive provider revision's
+	// Deployment keeps running after the active revision becomes healthy. If
+	// zero the Deployment is deleted as soon as the revision is deactivated.
+	ProviderDrainPeriod time.Duration
+
+	// StrictConfigurationDependencies blocks activation of a configuration
+	// revision until all of its dependencies are installed and healthy.
+	StrictConfigurationDependencies bool
+
+	// SkipDependencyResolution stops the package manager installing or
+	// upgrading the dependencies of any package. Packages still check that
+	// their dependencies are installed and satisfy their constraints.
+	SkipDependencyResolution bool
+
+	// ChannelPollInterval is how often the package manager checks whether the
+	// channels that dependencies track have moved. Zero means dependencies
+	// aren't updated when their channel moves.
+	ChannelPollInterval time.Duration
+
+	// RequireDigests rejects packages whose source is not a digest
+	// reference, rather than resolving their tag to a digest.
+	RequireDigests bool
+
+	// RequireSignatures rejects package revisions whose signature isn't
+	// verified by an ImageConfig, if signature verification is enabled.
+	RequireSignatures bool
+
+	// FetchRetries is how many times fetching a package image that failed for
+	// a transient reason is retried before the package revision is considered
+	// unhealthy.
+	FetchRetries int64
+
+	// FetchBackoff is how long to wait before first retrying a transient
+	// package image fetch failure. It doubles with each retry.
+	FetchBackoff time.Duration
+
+	// MaxRequeueDelay caps how long controllers back off before requeueing a
+	// resource. Zero means the default of 60 seconds.
+	MaxRequeueDelay time.Duration
+
+	// FootprintSampleInterval is how often the number of objects, and the
+	// amount of etcd storage, each active package revision is responsible for
+	// is sampled. Zero disables sampling.
+	FootprintSampleInterval time.Duration
+
+	// QueueMetrics records the depth of each controller's work queue, and the
+	// age of the oldest item in it. Optional.
+	QueueMetrics *metrics.QueueMetrics
+
+	// Notifier notifies external systems of lifecycle events. Optional.
+	Notifier notify.Notifier
+}
+
+// ForControllerRuntime extracts options for controller-runtime. Requeues back
+// off for at most MaxRequeueDelay, if it's set. Work queues are tracked by
+// QueueMetrics, if it's set.
+func (o Options) ForControllerRuntime() crcontroller.Options {
+	co := o.Options.ForControllerRuntime()
+	if o.MaxRequeueDelay > 0 {
+		co.RateLimiter = workqueue.NewItemExponentialFailureRateLimiter(min(1*time.Second, o.MaxRequeueDelay), o.MaxRequeueDelay)
+	}
+	if o.QueueMetrics != nil {
+		co.NewQueue = o.QueueMetrics.NewQueueFn(nil)
+	}
+	return co
+}
+
+// Fetcher returns a Fetcher that fetches packages using the supplied
+// Kubernetes clientset to load credentials. Packages are fetched from the image
+// cache, then via the remote cache registry, if either is configured.
+func (o Options) Fetcher(cs kubernetes.Interface) (xpkg.Fetcher, error) {
+	k, err := o.k8sFetcher(cs)
+	if err != nil {
+		return nil, err
+	}
+	var f xpkg.Fetcher = k
+	if o.RemoteCache != nil {
+		f = xpkg.NewRemoteCacheFetcher(f, o.RemoteCache, xpkg.WithRemoteCacheLogger(o.Logger))
+	}
+	if o.ImageCache != nil {
+		f = xpkg.NewImageCacheFetcher(f, o.ImageCache)
+	}
+	return f, nil
+}
+
+// ImageSources returns an ImageSourceFetcher that fetches packages from their
+// alternative image source, if any, or from their URL if their source is an
+// https:// or file:// URL. Other packages are fetched using the supplied
+// Fetcher. Packages are downloaded from https:// URLs using the same TLS
+// configuration and proxy as they're fetched from registries.
+func (o Options) ImageSources(cs kubernetes.Interface, f xpkg.Fetcher) (*xpkg.ImageSourceFetcher, error) {
+	k, err := o.k8sFetcher(cs)
+	if err != nil {
+		return nil, err
+	}
+	return xpkg.NewImageSourceFetcher(f,
+		xpkg.WithSecretImageSources(cs, o.Namespace),
+		xpkg.WithPathImageSources(o.PackageImageDir),
+		xpkg.WithHTTPSSources(k.HTTPClient(httpSourceTimeout), o.DownloadDir),
+	), nil
+}
+
+func (o Options) k8sFetcher(cs kubernetes.Interface) (*xpkg.K8sFetcher, error) {
+	return xpkg.NewK8sFetcher(cs, append(o.FetcherOptions, xpkg.WithNamespace(o.Namespace), xpkg.WithServiceAccount(o.ServiceAccount))...)
 }
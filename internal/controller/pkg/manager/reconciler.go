@@ -26,6 +26,8 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -42,6 +44,7 @@ import (
 
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/internal/controller/pkg/controller"
+	"github.com/crossplane/crossplane/internal/notify"
 	"github.com/crossplane/crossplane/internal/xpkg"
 )
 
@@ -50,14 +53,22 @@ const (
 
 	// pullWait is the time after which the package manager will check for
 	// updated content for the given package reference. This behavior is only
-	// enabled when the packagePullPolicy is Always.
+	// enabled when the packagePullPolicy is Always, or when the package has a
+	// version constraint.
 	pullWait = 1 * time.Minute
 
-	reconcilePausedMsg = "Reconciliation (including deletion) is paused via the pause annotation"
+	reconcilePausedMsg     = "Reconciliation (including deletion) is paused via the pause annotation"
+	reconcileSpecPausedMsg = "Reconciliation is paused via spec.paused"
 )
 
-func pullBasedRequeue(p *corev1.PullPolicy) reconcile.Result {
-	if p != nil && *p == corev1.PullAlways {
+func pullBasedRequeue(p v1.Package) reconcile.Result {
+	pp := p.GetPackagePullPolicy()
+	if pp != nil && *pp == corev1.PullAlways {
+		return reconcile.Result{RequeueAfter: pullWait}
+	}
+	// Packages with a version constraint are requeued so that newly published
+	// versions that satisfy the constraint are installed.
+	if p.GetVersionConstraint() != nil && (pp == nil || *pp != corev1.PullNever) {
 		return reconcile.Result{RequeueAfter: pullWait}
 	}
 	return reconcile.Result{Requeue: false}
@@ -67,8 +78,11 @@ const (
 	errGetPackage           = "cannot get package"
 	errListRevisions        = "cannot list revisions for package"
 	errUnpack               = "cannot unpack package"
+	errResolveSource        = "cannot resolve package source"
 	errApplyPackageRevision = "cannot apply package revision"
 	errGCPackageRevision    = "cannot garbage collect old package revision"
+	errPauseRevision        = "cannot pause current package revision"
+	errResumeRevision       = "cannot resume current package revision"
 
 	errUpdateStatus                  = "cannot update package status"
 	errUpdateInactivePackageRevision = "cannot update inactive package revision"
@@ -136,12 +150,21 @@ func WithRecorder(er event.Recorder) ReconcilerOption {
 	}
 }
 
+// WithNotifier specifies how the Reconciler should notify external systems of
+// package lifecycle events.
+func WithNotifier(n notify.Notifier) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.notify = n
+	}
+}
+
 // Reconciler reconciles packages.
 type Reconciler struct {
 	client resource.ClientApplicator
 	pkg    Revisioner
 	log    logging.Logger
 	record event.Recorder
+	notify notify.Notifier
 
 	newPackage             func() v1.Package
 	newPackageRevision     func() v1.PackageRevision
@@ -159,7 +182,11 @@ func SetupProvider(mgr ctrl.Manager, o controller.Options) error {
 	if err != nil {
 		return errors.Wrap(err, errCreateK8sClient)
 	}
-	f, err := xpkg.NewK8sFetcher(cs, append(o.FetcherOptions, xpkg.WithNamespace(o.Namespace), xpkg.WithServiceAccount(o.ServiceAccount))...)
+	f, err := o.Fetcher(cs)
+	if err != nil {
+		return errors.Wrap(err, errBuildFetcher)
+	}
+	sources, err := o.ImageSources(cs, f)
 	if err != nil {
 		return errors.Wrap(err, errBuildFetcher)
 	}
@@ -168,10 +195,13 @@ func SetupProvider(mgr ctrl.Manager, o controller.Options) error {
 		WithNewPackageFn(np),
 		WithNewPackageRevisionFn(nr),
 		WithNewPackageRevisionListFn(nrl),
-		WithRevisioner(NewPackageRevisioner(f, WithDefaultRegistry(o.DefaultRegistry))),
+		WithRevisioner(NewPackageRevisioner(f, WithDefaultRegistry(o.DefaultRegistry), WithRequireDigest(o.RequireDigests), WithImageSources(sources))),
 		WithLogger(o.Logger.WithValues("controller", name)),
 		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 	}
+	if o.Notifier != nil {
+		opts = append(opts, WithNotifier(o.Notifier))
+	}
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
@@ -192,19 +222,27 @@ func SetupConfiguration(mgr ctrl.Manager, o controller.Options) error {
 	if err != nil {
 		return errors.Wrap(err, "failed to initialize clientset")
 	}
-	fetcher, err := xpkg.NewK8sFetcher(clientset, append(o.FetcherOptions, xpkg.WithNamespace(o.Namespace), xpkg.WithServiceAccount(o.ServiceAccount))...)
+	fetcher, err := o.Fetcher(clientset)
+	if err != nil {
+		return errors.Wrap(err, "cannot build fetcher")
+	}
+	sources, err := o.ImageSources(clientset, fetcher)
 	if err != nil {
 		return errors.Wrap(err, "cannot build fetcher")
 	}
 
-	r := NewReconciler(mgr,
+	opts := []ReconcilerOption{
 		WithNewPackageFn(np),
 		WithNewPackageRevisionFn(nr),
 		WithNewPackageRevisionListFn(nrl),
-		WithRevisioner(NewPackageRevisioner(fetcher, WithDefaultRegistry(o.DefaultRegistry))),
+		WithRevisioner(NewPackageRevisioner(fetcher, WithDefaultRegistry(o.DefaultRegistry), WithRequireDigest(o.RequireDigests), WithImageSources(sources))),
 		WithLogger(o.Logger.WithValues("controller", name)),
 		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-	)
+	}
+	if o.Notifier != nil {
+		opts = append(opts, WithNotifier(o.Notifier))
+	}
+	r := NewReconciler(mgr, opts...)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
@@ -225,7 +263,11 @@ func SetupFunction(mgr ctrl.Manager, o controller.Options) error {
 	if err != nil {
 		return errors.Wrap(err, errCreateK8sClient)
 	}
-	f, err := xpkg.NewK8sFetcher(cs, append(o.FetcherOptions, xpkg.WithNamespace(o.Namespace), xpkg.WithServiceAccount(o.ServiceAccount))...)
+	f, err := o.Fetcher(cs)
+	if err != nil {
+		return errors.Wrap(err, errBuildFetcher)
+	}
+	sources, err := o.ImageSources(cs, f)
 	if err != nil {
 		return errors.Wrap(err, errBuildFetcher)
 	}
@@ -234,10 +276,13 @@ func SetupFunction(mgr ctrl.Manager, o controller.Options) error {
 		WithNewPackageFn(np),
 		WithNewPackageRevisionFn(nr),
 		WithNewPackageRevisionListFn(nrl),
-		WithRevisioner(NewPackageRevisioner(f, WithDefaultRegistry(o.DefaultRegistry))),
+		WithRevisioner(NewPackageRevisioner(f, WithDefaultRegistry(o.DefaultRegistry), WithRequireDigest(o.RequireDigests), WithImageSources(sources))),
 		WithLogger(o.Logger.WithValues("controller", name)),
 		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 	}
+	if o.Notifier != nil {
+		opts = append(opts, WithNotifier(o.Notifier))
+	}
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
@@ -257,6 +302,7 @@ func NewReconciler(mgr ctrl.Manager, opts ...ReconcilerOption) *Reconciler {
 		pkg:    NewNopRevisioner(),
 		log:    logging.NewNopLogger(),
 		record: event.NewNopRecorder(),
+		notify: notify.NopNotifier{},
 	}
 
 	for _, f := range opts {
@@ -291,6 +337,22 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		// and if status update fails, we will reconcile again to retry to update the status
 		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
 	}
+
+	// A package paused via its spec pauses its current revision, and doesn't
+	// resolve or create new revisions until it's resumed.
+	if ptr.Deref(p.GetPaused(), false) {
+		if err := r.pauseCurrentRevision(ctx, p); err != nil {
+			if kerrors.IsConflict(err) {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			err = errors.Wrap(err, errPauseRevision)
+			r.record.Event(p, event.Warning(reasonPaused, err))
+			return reconcile.Result{}, err
+		}
+		r.record.Event(p, event.Normal(reasonPaused, reconcileSpecPausedMsg))
+		p.SetConditions(xpv1.ReconcilePaused().WithMessage(reconcileSpecPausedMsg))
+		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+	}
 	if c := p.GetCondition(xpv1.ReconcilePaused().Type); c.Reason == xpv1.ReconcilePaused().Reason {
 		p.CleanConditions()
 	}
@@ -331,6 +393,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	oldestRevision := int64(math.MaxInt64)
 	oldestRevisionIndex := -1
 	revisions := prs.GetRevisions()
+	active := make([]v1.PackageRevision, 0, 1)
 
 	// Check to see if revision already exists.
 	for index, rev := range revisions {
@@ -356,18 +419,27 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			continue
 		}
 		if rev.GetDesiredState() == v1.PackageRevisionActive {
-			// If revision is not the current revision, set to
-			// inactive. This should always be done, regardless of
-			// the package's revision activation policy.
-			rev.SetDesiredState(v1.PackageRevisionInactive)
-			if err := r.client.Apply(ctx, rev, resource.MustBeControllableBy(p.GetUID())); err != nil {
-				if kerrors.IsConflict(err) {
-					return reconcile.Result{Requeue: true}, nil
-				}
-				err = errors.Wrap(err, errUpdateInactivePackageRevision)
-				r.record.Event(p, event.Warning(reasonTransitionRevision, err))
-				return reconcile.Result{}, err
+			active = append(active, rev)
+		}
+	}
+
+	// If a revision is not the current revision, set it to inactive. The
+	// exception is a package with a manual activation policy whose current
+	// revision is still being staged, i.e. fetched, parsed, and validated
+	// while inactive. Its previously active revision stays active as a warm
+	// standby until the current revision is activated, so that switching
+	// revisions doesn't leave the package without an active revision.
+	staging := p.GetActivationPolicy() != nil && *p.GetActivationPolicy() == v1.ManualActivation && pr.GetDesiredState() != v1.PackageRevisionActive
+	for i := 0; i < len(active) && !staging; i++ {
+		rev := active[i]
+		rev.SetDesiredState(v1.PackageRevisionInactive)
+		if err := r.client.Apply(ctx, rev, resource.MustBeControllableBy(p.GetUID())); err != nil {
+			if kerrors.IsConflict(err) {
+				return reconcile.Result{Requeue: true}, nil
 			}
+			err = errors.Wrap(err, errUpdateInactivePackageRevision)
+			r.record.Event(p, event.Warning(reasonTransitionRevision, err))
+			return reconcile.Result{}, err
 		}
 	}
 
@@ -376,6 +448,31 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		pr.SetRevision(maxRevision + 1)
 	}
 
+	// Pin new revisions to the digest their package's source resolves to, so
+	// that their content can't change if a tag is moved. Revisions are named
+	// for their digest, so an existing revision is already pinned correctly.
+	source := pr.GetSource()
+	if xpkg.UnpinnedSource(source) != p.GetSource() {
+		source, err = r.pkg.ResolveSource(ctx, p)
+		if err != nil {
+			err = errors.Wrap(err, errResolveSource)
+			p.SetConditions(v1.Unpacking().WithMessage(err.Error()))
+			r.record.Event(p, event.Warning(reasonUnpack, err))
+
+			if updateErr := r.client.Status().Update(ctx, p); updateErr != nil {
+				return reconcile.Result{}, errors.Wrap(updateErr, errUpdateStatus)
+			}
+
+			return reconcile.Result{}, err
+		}
+	}
+
+	// A package with a version constraint is identified by the version its
+	// constraint resolved to.
+	if p.GetVersionConstraint() != nil {
+		p.SetCurrentIdentifier(xpkg.UnpinnedSource(source))
+	}
+
 	// Check to see if there are revisions eligible for garbage collection.
 	if p.GetRevisionHistoryLimit() != nil &&
 		*p.GetRevisionHistoryLimit() != 0 &&
@@ -389,32 +486,54 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		}
 	}
 
+	p.SetFootprint(pr.GetFootprint())
+	p.SetDependencyEdges(pr.GetDependencyEdges())
+
 	// TODO(phisco): refactor these conditions to make it clearer
+	wasHealthy := p.GetCondition(v1.TypeHealthy).Status
 	if pr.GetCondition(v1.TypeHealthy).Status == corev1.ConditionTrue {
-		if p.GetCondition(v1.TypeHealthy).Status != corev1.ConditionTrue {
+		if wasHealthy != corev1.ConditionTrue {
 			// NOTE(phisco): We don't want to spam the user with events if the
 			// package is already healthy.
 			r.record.Event(p, event.Normal(reasonInstall, "Successfully installed package revision"))
+			t := notify.PackageUpgraded
+			if pr.GetRevision() == 1 {
+				t = notify.PackageInstalled
+			}
+			r.notify.Notify(ctx, packageEvent(t, p, pr, "Successfully installed package revision"))
 		}
 		p.SetConditions(v1.Healthy())
 	}
 	if prHealthy := pr.GetCondition(v1.TypeHealthy); prHealthy.Status == corev1.ConditionFalse {
 		p.SetConditions(v1.Unhealthy().WithMessage(prHealthy.Message))
 		r.record.Event(p, event.Warning(reasonInstall, errors.New(errUnhealthyPackageRevision)))
+		if wasHealthy != corev1.ConditionFalse {
+			r.notify.Notify(ctx, packageEvent(notify.PackageFailed, p, pr, prHealthy.Message))
+		}
 	}
 	if prHealthy := pr.GetCondition(v1.TypeHealthy); prHealthy.Status == corev1.ConditionUnknown {
 		p.SetConditions(v1.UnknownHealth().WithMessage(prHealthy.Message))
 		r.record.Event(p, event.Warning(reasonInstall, errors.New(errUnknownPackageRevisionHealth)))
 	}
 
+	// Roll up the details of the revision's health, so it's clear from the
+	// package why it is or isn't healthy.
+	for _, t := range []xpv1.ConditionType{v1.TypeRuntimeHealthy, v1.TypeAPIsEstablished, v1.TypeDependenciesSatisfied} {
+		if c := pr.GetCondition(t); c.Reason != "" {
+			p.SetConditions(c)
+		}
+	}
+
 	// Create the non-existent package revision.
 	pr.SetName(revisionName)
 	pr.SetLabels(map[string]string{v1.LabelParentPackage: p.GetName()})
-	pr.SetSource(p.GetSource())
+	pr.SetSource(source)
 	pr.SetPackagePullPolicy(p.GetPackagePullPolicy())
 	pr.SetPackagePullSecrets(p.GetPackagePullSecrets())
+	pr.SetImageSource(p.GetImageSource())
 	pr.SetIgnoreCrossplaneConstraints(p.GetIgnoreCrossplaneConstraints())
 	pr.SetSkipDependencyResolution(p.GetSkipDependencyResolution())
+	pr.SetDependencyUpgradePolicy(p.GetDependencyUpgradePolicy())
 	pr.SetCommonLabels(p.GetCommonLabels())
 
 	pwr, pwok := p.(v1.PackageWithRuntime)
@@ -426,6 +545,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		prwr.SetTLSClientSecretName(pwr.GetTLSClientSecretName())
 	}
 
+	pwv, pwvok := p.(v1.PackageWithValues)
+	prwv, prvok := pr.(v1.PackageRevisionWithValues)
+	if pwvok && prvok {
+		prwv.SetValues(pwv.GetValues())
+	}
+
 	// If current revision is not active, and we have an automatic or
 	// undefined activation policy, always activate.
 	if pr.GetDesiredState() != v1.PackageRevisionActive && (p.GetActivationPolicy() == nil || *p.GetActivationPolicy() == v1.AutomaticActivation) {
@@ -444,6 +569,20 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{}, err
 	}
 
+	// Resume the revision if it was paused. Applying can't unset the paused
+	// field, so we update the revision.
+	if ptr.Deref(pr.GetPaused(), false) {
+		pr.SetPaused(nil)
+		if err := r.client.Update(ctx, pr); err != nil {
+			if kerrors.IsConflict(err) {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			err = errors.Wrap(err, errResumeRevision)
+			r.record.Event(p, event.Warning(reasonInstall, err))
+			return reconcile.Result{}, err
+		}
+	}
+
 	// Handle changes in labels
 	same := reflect.DeepEqual(pr.GetCommonLabels(), p.GetCommonLabels())
 	if !same {
@@ -469,5 +608,45 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	// package, the health of the package is not set until the revision reports
 	// its health. If updating from an existing revision, the package health
 	// will match the health of the old revision until the next reconcile.
-	return pullBasedRequeue(p.GetPackagePullPolicy()), errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+	return pullBasedRequeue(p), errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+}
+
+// pauseCurrentRevision pauses the supplied package's current revision, if it
+// has one.
+func (r *Reconciler) pauseCurrentRevision(ctx context.Context, p v1.Package) error {
+	if p.GetCurrentRevision() == "" {
+		return nil
+	}
+	pr := r.newPackageRevision()
+	if err := r.client.Get(ctx, types.NamespacedName{Name: p.GetCurrentRevision()}, pr); err != nil {
+		return resource.IgnoreNotFound(err)
+	}
+	if ptr.Deref(pr.GetPaused(), false) {
+		return nil
+	}
+	pr.SetPaused(ptr.To(true))
+	return r.client.Update(ctx, pr)
+}
+
+// packageEvent returns a lifecycle event about the supplied package and its
+// current revision.
+func packageEvent(t notify.EventType, p v1.Package, pr v1.PackageRevision, msg string) notify.Event {
+	e := notify.Event{
+		Type:     t,
+		Name:     p.GetName(),
+		Package:  pr.GetSource(),
+		Revision: pr.GetName(),
+		Message:  msg,
+	}
+	var gvk schema.GroupVersionKind
+	switch p.(type) {
+	case *v1.Provider:
+		gvk = v1.ProviderGroupVersionKind
+	case *v1.Configuration:
+		gvk = v1.ConfigurationGroupVersionKind
+	case *v1.Function:
+		gvk = v1.FunctionGroupVersionKind
+	}
+	e.APIVersion, e.Kind = gvk.ToAPIVersionAndKind()
+	return e
 }
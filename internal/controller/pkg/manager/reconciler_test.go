@@ -27,6 +27,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -40,12 +41,14 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/notify"
 )
 
 var _ Revisioner = &MockRevisioner{}
 
 type MockRevisioner struct {
-	MockRevision func() (string, error)
+	MockRevision      func() (string, error)
+	MockResolveSource func() (string, error)
 }
 
 func NewMockRevisionFn(hash string, err error) func() (string, error) {
@@ -58,6 +61,13 @@ func (m *MockRevisioner) Revision(context.Context, v1.Package) (string, error) {
 	return m.MockRevision()
 }
 
+func (m *MockRevisioner) ResolveSource(_ context.Context, p v1.Package) (string, error) {
+	if m.MockResolveSource == nil {
+		return p.GetSource(), nil
+	}
+	return m.MockResolveSource()
+}
+
 func TestReconcile(t *testing.T) {
 	errBoom := errors.New("boom")
 	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
@@ -126,6 +136,7 @@ func TestReconcile(t *testing.T) {
 					},
 					log:    testLog,
 					record: event.NewNopRecorder(),
+					notify: notify.NopNotifier{},
 				},
 			},
 			want: want{
@@ -158,6 +169,7 @@ func TestReconcile(t *testing.T) {
 					},
 					log:    testLog,
 					record: event.NewNopRecorder(),
+					notify: notify.NopNotifier{},
 					pkg: &MockRevisioner{
 						MockRevision: NewMockRevisionFn("", errBoom),
 					},
@@ -167,6 +179,50 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, errUnpack),
 			},
 		},
+		"ErrResolveSource": {
+			reason: "We should return an error if resolving the source of a new revision fails.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetSource("xpkg.upbound.io/crossplane/configuration-cool:v0.1.0")
+								return nil
+							}),
+							MockList: test.NewMockListFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetSource("xpkg.upbound.io/crossplane/configuration-cool:v0.1.0")
+								want.SetCurrentRevision("test-1234567")
+								want.SetCurrentIdentifier("xpkg.upbound.io/crossplane/configuration-cool:v0.1.0")
+								want.SetConditions(v1.Unpacking().WithMessage(errors.Wrap(errBoom, errResolveSource).Error()))
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+					},
+					log:    testLog,
+					record: event.NewNopRecorder(),
+					notify: notify.NopNotifier{},
+					pkg: &MockRevisioner{
+						MockRevision:      NewMockRevisionFn("test-1234567", nil),
+						MockResolveSource: NewMockRevisionFn("", errBoom),
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errResolveSource),
+			},
+		},
 		"SuccessfulNoExistingRevisionsAutoActivate": {
 			reason: "We should be active and not requeue on successful creation of the first revision with auto activation.",
 			args: args{
@@ -208,6 +264,7 @@ func TestReconcile(t *testing.T) {
 					},
 					log:    testLog,
 					record: event.NewNopRecorder(),
+					notify: notify.NopNotifier{},
 				},
 			},
 			want: want{
@@ -257,6 +314,7 @@ func TestReconcile(t *testing.T) {
 					},
 					log:    testLog,
 					record: event.NewNopRecorder(),
+					notify: notify.NopNotifier{},
 				},
 			},
 			want: want{
@@ -304,6 +362,68 @@ func TestReconcile(t *testing.T) {
 					},
 					log:    testLog,
 					record: event.NewNopRecorder(),
+					notify: notify.NopNotifier{},
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulManualActivateKeepsActiveRevision": {
+			reason: "We should not deactivate the previously active revision while the current revision of a package with manual activation policy is inactive.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetActivationPolicy(&v1.ManualActivation)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								old := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-7654321"}}
+								old.SetRevision(1)
+								old.SetDesiredState(v1.PackageRevisionActive)
+								cr := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-1234567"}}
+								cr.SetRevision(2)
+								cr.SetDesiredState(v1.PackageRevisionInactive)
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{old, cr}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetActivationPolicy(&v1.ManualActivation)
+								want.SetCurrentRevision("test-1234567")
+								want.SetConditions(v1.UnknownHealth())
+								want.SetConditions(v1.Inactive().WithMessage("Package is inactive"))
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							if o.GetName() == "test-7654321" {
+								t.Errorf("Apply(...): unexpected deactivation of previously active revision %q", o.GetName())
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					log:    testLog,
+					record: event.NewNopRecorder(),
+					notify: notify.NopNotifier{},
 				},
 			},
 			want: want{
@@ -362,6 +482,19 @@ func TestReconcile(t *testing.T) {
 					},
 					log:    testLog,
 					record: event.NewNopRecorder(),
+					notify: notify.NotifierFn(func(_ context.Context, e notify.Event) {
+						want := notify.Event{
+							Type:       notify.PackageInstalled,
+							APIVersion: v1.ConfigurationGroupVersionKind.GroupVersion().String(),
+							Kind:       v1.ConfigurationKind,
+							Name:       "test",
+							Revision:   "test-1234567",
+							Message:    "Successfully installed package revision",
+						}
+						if diff := cmp.Diff(want, e); diff != "" {
+							t.Errorf("Notify(...): -want, +got:\n%s", diff)
+						}
+					}),
 				},
 			},
 			want: want{
@@ -440,6 +573,7 @@ func TestReconcile(t *testing.T) {
 					},
 					log:    testLog,
 					record: event.NewNopRecorder(),
+					notify: notify.NopNotifier{},
 				},
 			},
 			want: want{
@@ -499,6 +633,7 @@ func TestReconcile(t *testing.T) {
 					},
 					log:    testLog,
 					record: event.NewNopRecorder(),
+					notify: notify.NopNotifier{},
 				},
 			},
 			want: want{
@@ -559,6 +694,80 @@ func TestReconcile(t *testing.T) {
 					},
 					log:    testLog,
 					record: event.NewNopRecorder(),
+					notify: notify.NotifierFn(func(_ context.Context, e notify.Event) {
+						want := notify.Event{
+							Type:       notify.PackageFailed,
+							APIVersion: v1.ConfigurationGroupVersionKind.GroupVersion().String(),
+							Kind:       v1.ConfigurationKind,
+							Name:       "test",
+							Revision:   "test-1234567",
+							Message:    "some message",
+						}
+						if diff := cmp.Diff(want, e); diff != "" {
+							t.Errorf("Notify(...): -want, +got:\n%s", diff)
+						}
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulTransitionHealthDetails": {
+			reason: "The details of the current revision's health should be rolled up into the package.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cr := v1.ConfigurationRevision{
+									ObjectMeta: metav1.ObjectMeta{
+										Name: "test-1234567",
+									},
+								}
+								cr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								cr.SetConditions(v1.Unhealthy().WithMessage("some message"), v1.APIsEstablished(), v1.DependenciesUnsatisfied().WithMessage("some message"))
+								cr.SetDesiredState(v1.PackageRevisionActive)
+								c := v1.ConfigurationRevisionList{
+									Items: []v1.ConfigurationRevision{cr},
+								}
+								*l = c
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetCurrentRevision("test-1234567")
+								want.SetConditions(v1.Unhealthy().WithMessage("some message"), v1.APIsEstablished(), v1.DependenciesUnsatisfied().WithMessage("some message"))
+								want.SetConditions(v1.Active())
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					log:    testLog,
+					record: event.NewNopRecorder(),
+					notify: notify.NopNotifier{},
 				},
 			},
 			want: want{
@@ -599,16 +808,20 @@ func TestReconcile(t *testing.T) {
 											ObjectMeta: metav1.ObjectMeta{
 												Name: "made-the-cut",
 											},
-											Spec: v1.PackageRevisionSpec{
-												Revision: 2,
+											Spec: v1.ConfigurationRevisionSpec{
+												PackageRevisionSpec: v1.PackageRevisionSpec{
+													Revision: 2,
+												},
 											},
 										},
 										{
 											ObjectMeta: metav1.ObjectMeta{
 												Name: "missed-the-cut",
 											},
-											Spec: v1.PackageRevisionSpec{
-												Revision: 1,
+											Spec: v1.ConfigurationRevisionSpec{
+												PackageRevisionSpec: v1.PackageRevisionSpec{
+													Revision: 1,
+												},
 											},
 										},
 									},
@@ -656,6 +869,7 @@ func TestReconcile(t *testing.T) {
 					},
 					log:    testLog,
 					record: event.NewNopRecorder(),
+					notify: notify.NopNotifier{},
 				},
 			},
 			want: want{
@@ -697,18 +911,22 @@ func TestReconcile(t *testing.T) {
 											ObjectMeta: metav1.ObjectMeta{
 												Name: "made-the-cut",
 											},
-											Spec: v1.PackageRevisionSpec{
-												Revision:     2,
-												DesiredState: v1.PackageRevisionInactive,
+											Spec: v1.ConfigurationRevisionSpec{
+												PackageRevisionSpec: v1.PackageRevisionSpec{
+													Revision:     2,
+													DesiredState: v1.PackageRevisionInactive,
+												},
 											},
 										},
 										{
 											ObjectMeta: metav1.ObjectMeta{
 												Name: "missed-the-cut",
 											},
-											Spec: v1.PackageRevisionSpec{
-												Revision:     1,
-												DesiredState: v1.PackageRevisionInactive,
+											Spec: v1.ConfigurationRevisionSpec{
+												PackageRevisionSpec: v1.PackageRevisionSpec{
+													Revision:     1,
+													DesiredState: v1.PackageRevisionInactive,
+												},
 											},
 										},
 									},
@@ -735,6 +953,7 @@ func TestReconcile(t *testing.T) {
 					},
 					log:    testLog,
 					record: event.NewNopRecorder(),
+					notify: notify.NopNotifier{},
 				},
 			},
 			want: want{
@@ -782,6 +1001,143 @@ func TestReconcile(t *testing.T) {
 					},
 					log:    testLog,
 					record: event.NewNopRecorder(),
+					notify: notify.NopNotifier{},
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"PauseReconcileSpec": {
+			reason: "Pause reconciliation and pause the current revision if the package is paused via its spec",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								switch p := o.(type) {
+								case *v1.Configuration:
+									p.SetName("test")
+									p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+									p.SetCurrentRevision("test-1234567")
+									p.SetPaused(ptr.To(true))
+								case *v1.ConfigurationRevision:
+									p.SetName("test-1234567")
+								}
+								return nil
+							}),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ConfigurationRevision{}
+								want.SetName("test-1234567")
+								want.SetPaused(ptr.To(true))
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetCurrentRevision("test-1234567")
+								want.SetPaused(ptr.To(true))
+								want.SetConditions(commonv1.ReconcilePaused().WithMessage(reconcileSpecPausedMsg))
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+					},
+					log:    testLog,
+					record: event.NewNopRecorder(),
+					notify: notify.NopNotifier{},
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"ErrPauseRevision": {
+			reason: "We should return an error if we can't pause the current revision of a package that is paused via its spec",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								if p, ok := o.(*v1.Configuration); ok {
+									p.SetName("test")
+									p.SetCurrentRevision("test-1234567")
+									p.SetPaused(ptr.To(true))
+								}
+								return nil
+							}),
+							MockUpdate: test.NewMockUpdateFn(errBoom),
+						},
+					},
+					log:    testLog,
+					record: event.NewNopRecorder(),
+					notify: notify.NopNotifier{},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errPauseRevision),
+			},
+		},
+		"ResumeRevision": {
+			reason: "We should resume the current revision if the package is no longer paused via its spec",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetActivationPolicy(&v1.AutomaticActivation)
+								p.SetConditions(commonv1.ReconcilePaused())
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								cr := v1.ConfigurationRevision{}
+								cr.SetName("test-1234567")
+								cr.SetRevision(1)
+								cr.SetDesiredState(v1.PackageRevisionActive)
+								cr.SetPaused(ptr.To(true))
+								l := o.(*v1.ConfigurationRevisionList)
+								l.Items = []v1.ConfigurationRevision{cr}
+								return nil
+							}),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								if o.(*v1.ConfigurationRevision).GetPaused() != nil {
+									t.Errorf("Update(...): want revision to be resumed")
+								}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					log:    testLog,
+					record: event.NewNopRecorder(),
+					notify: notify.NopNotifier{},
 				},
 			},
 			want: want{
@@ -830,6 +1186,7 @@ func TestReconcile(t *testing.T) {
 					},
 					log:    testLog,
 					record: event.NewNopRecorder(),
+					notify: notify.NopNotifier{},
 				},
 			},
 			want: want{
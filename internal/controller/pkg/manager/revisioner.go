@@ -18,7 +18,9 @@ package manager
 
 import (
 	"context"
+	"sort"
 
+	"github.com/Masterminds/semver"
 	"github.com/google/go-containerregistry/pkg/name"
 	corev1 "k8s.io/api/core/v1"
 
@@ -31,17 +33,32 @@ import (
 const (
 	errBadReference = "package tag is not a valid reference"
 	errFetchPackage = "failed to fetch package digest from remote"
+	errImageSource  = "cannot use package image source"
+
+	errInvalidVersionConstraint = "package version constraint is invalid"
+	errFetchTags                = "cannot fetch package tags"
+	errVersionConstraintDigest  = "packages must be referenced by digest, not by version constraint"
+	errVersionConstraintURL     = "packages fetched from a URL cannot have a version constraint"
+
+	errFmtNotDigest         = "package %q must be referenced by digest"
+	errFmtNoMatchingVersion = "no version of package %q satisfies version constraint %q"
 )
 
 // Revisioner extracts a revision name for a package source.
 type Revisioner interface {
+	// Revision extracts a revision name for a package source.
 	Revision(ctx context.Context, p v1.Package) (string, error)
+
+	// ResolveSource returns the source a new revision of the supplied
+	// package should be created from.
+	ResolveSource(ctx context.Context, p v1.Package) (string, error)
 }
 
 // PackageRevisioner extracts a revision name for a package source.
 type PackageRevisioner struct {
-	fetcher  xpkg.Fetcher
-	registry string
+	sources       *xpkg.ImageSourceFetcher
+	registry      string
+	requireDigest bool
 }
 
 // A PackageRevisionerOption sets configuration for a package revisioner.
@@ -54,10 +71,26 @@ func WithDefaultRegistry(registry string) PackageRevisionerOption {
 	}
 }
 
+// WithRequireDigest configures a package revisioner to reject packages whose
+// source is not a digest reference.
+func WithRequireDigest(require bool) PackageRevisionerOption {
+	return func(r *PackageRevisioner) {
+		r.requireDigest = require
+	}
+}
+
+// WithImageSources configures how a package revisioner fetches packages that
+// specify an alternative image source.
+func WithImageSources(s *xpkg.ImageSourceFetcher) PackageRevisionerOption {
+	return func(r *PackageRevisioner) {
+		r.sources = s
+	}
+}
+
 // NewPackageRevisioner returns a new PackageRevisioner.
 func NewPackageRevisioner(fetcher xpkg.Fetcher, opts ...PackageRevisionerOption) *PackageRevisioner {
 	r := &PackageRevisioner{
-		fetcher: fetcher,
+		sources: xpkg.NewImageSourceFetcher(fetcher),
 	}
 	for _, opt := range opts {
 		opt(r)
@@ -67,26 +100,152 @@ func NewPackageRevisioner(fetcher xpkg.Fetcher, opts ...PackageRevisionerOption)
 
 // Revision extracts a revision name for a package source.
 func (r *PackageRevisioner) Revision(ctx context.Context, p v1.Package) (string, error) {
+	if r.requireDigest {
+		if p.GetVersionConstraint() != nil {
+			return "", errors.New(errVersionConstraintDigest)
+		}
+		ref, err := name.ParseReference(p.GetSource(), name.WithDefaultRegistry(r.registry))
+		if err != nil {
+			return "", errors.Wrap(err, errBadReference)
+		}
+		if _, ok := ref.(name.Digest); !ok {
+			return "", errors.Errorf(errFmtNotDigest, p.GetSource())
+		}
+	}
+
 	pullPolicy := p.GetPackagePullPolicy()
 	if pullPolicy != nil && *pullPolicy == corev1.PullNever {
 		return xpkg.FriendlyID(p.GetName(), p.GetSource()), nil
 	}
-	if pullPolicy != nil && *pullPolicy == corev1.PullIfNotPresent {
+	// A package with a version constraint must be resolved every time, to
+	// find out whether a newer version satisfies it.
+	if pullPolicy != nil && *pullPolicy == corev1.PullIfNotPresent && p.GetVersionConstraint() == nil {
 		if p.GetCurrentIdentifier() == p.GetSource() {
 			return p.GetCurrentRevision(), nil
 		}
 	}
-	ref, err := name.ParseReference(p.GetSource(), name.WithDefaultRegistry(r.registry))
+	if xpkg.IsURLSource(p.GetSource()) {
+		return r.urlRevision(ctx, p)
+	}
+	f, err := r.sources.For(p.GetImageSource())
+	if err != nil {
+		return "", errors.Wrap(err, errImageSource)
+	}
+	source, err := r.source(ctx, f, p)
+	if err != nil {
+		return "", err
+	}
+	ref, err := name.ParseReference(source, name.WithDefaultRegistry(r.registry))
 	if err != nil {
 		return "", errors.Wrap(err, errBadReference)
 	}
-	d, err := r.fetcher.Head(ctx, ref, v1.RefNames(p.GetPackagePullSecrets())...)
+	d, err := f.Head(ctx, ref, v1.RefNames(p.GetPackagePullSecrets())...)
 	if err != nil || d == nil {
 		return "", errors.Wrap(err, errFetchPackage)
 	}
 	return xpkg.FriendlyID(p.GetName(), d.Digest.Hex), nil
 }
 
+// urlRevision extracts a revision name for a package whose source is a URL.
+// Like any other package it's revisioned by the digest of its image, so a new
+// revision is created whenever the image at the URL changes.
+func (r *PackageRevisioner) urlRevision(ctx context.Context, p v1.Package) (string, error) {
+	if p.GetVersionConstraint() != nil {
+		return "", errors.New(errVersionConstraintURL)
+	}
+	f, err := r.sources.ForURL(p.GetSource())
+	if err != nil {
+		return "", errors.Wrap(err, errImageSource)
+	}
+	d, err := f.Head(ctx, nil)
+	if err != nil || d == nil {
+		return "", errors.Wrap(err, errFetchPackage)
+	}
+	return xpkg.FriendlyID(p.GetName(), d.Digest.Hex), nil
+}
+
+// ResolveSource resolves the source of the supplied package to a digest, so
+// that its new revision can't change if the package's tag is moved. Sources
+// that are already digests, and sources that are never pulled, are returned
+// unchanged, as are sources that are URLs. The source of a package with a
+// version constraint is resolved to the highest version that satisfies it
+// before it's pinned.
+func (r *PackageRevisioner) ResolveSource(ctx context.Context, p v1.Package) (string, error) {
+	pullPolicy := p.GetPackagePullPolicy()
+	if pullPolicy != nil && *pullPolicy == corev1.PullNever {
+		return p.GetSource(), nil
+	}
+	if xpkg.IsURLSource(p.GetSource()) {
+		return p.GetSource(), nil
+	}
+	f, err := r.sources.For(p.GetImageSource())
+	if err != nil {
+		return "", errors.Wrap(err, errImageSource)
+	}
+	source, err := r.source(ctx, f, p)
+	if err != nil {
+		return "", err
+	}
+	ref, err := name.ParseReference(source, name.WithDefaultRegistry(r.registry))
+	if err != nil {
+		return "", errors.Wrap(err, errBadReference)
+	}
+	if _, ok := ref.(name.Digest); ok {
+		return source, nil
+	}
+	d, err := f.Head(ctx, ref, v1.RefNames(p.GetPackagePullSecrets())...)
+	if err != nil || d == nil {
+		return "", errors.Wrap(err, errFetchPackage)
+	}
+	return xpkg.PinnedSource(source, d.Digest), nil
+}
+
+// source returns the source the supplied package should be installed from.
+// This is the package's source unless it has a version constraint, in which
+// case it's the package's repository tagged with the highest version that
+// satisfies the constraint.
+func (r *PackageRevisioner) source(ctx context.Context, f xpkg.Fetcher, p v1.Package) (string, error) {
+	if p.GetVersionConstraint() == nil {
+		return p.GetSource(), nil
+	}
+	c, err := semver.NewConstraint(*p.GetVersionConstraint())
+	if err != nil {
+		return "", errors.Wrap(err, errInvalidVersionConstraint)
+	}
+	ref, err := name.ParseReference(p.GetSource(), name.WithDefaultRegistry(r.registry))
+	if err != nil {
+		return "", errors.Wrap(err, errBadReference)
+	}
+	tags, err := f.Tags(ctx, ref, v1.RefNames(p.GetPackagePullSecrets())...)
+	if err != nil {
+		return "", errors.Wrap(err, errFetchTags)
+	}
+
+	vs := []*semver.Version{}
+	for _, t := range tags {
+		v, err := semver.NewVersion(t)
+		if err != nil {
+			// We skip any tags that are not valid semantic versions.
+			continue
+		}
+		if c.Check(v) {
+			vs = append(vs, v)
+		}
+	}
+	if len(vs) == 0 {
+		return "", errors.Errorf(errFmtNoMatchingVersion, p.GetSource(), *p.GetVersionConstraint())
+	}
+	sort.Sort(semver.Collection(vs))
+
+	// Unpinning the source first ensures we strip its tag even if it's
+	// pinned to a digest, e.g. registry/org/repo:v1.0.0@sha256:...
+	unpinned, err := name.ParseReference(xpkg.UnpinnedSource(p.GetSource()), name.WithDefaultRegistry(r.registry))
+	if err != nil {
+		return "", errors.Wrap(err, errBadReference)
+	}
+	return xpkg.ParsePackageSourceFromReference(unpinned) + ":" + vs[len(vs)-1].Original(), nil
+}
+
 // NopRevisioner returns an empty revision name.
 type NopRevisioner struct{}
 
@@ -99,3 +258,8 @@ func NewNopRevisioner() *NopRevisioner {
 func (d *NopRevisioner) Revision(context.Context, v1.Package) (string, error) {
 	return "", nil
 }
+
+// ResolveSource returns the package's source unchanged.
+func (d *NopRevisioner) ResolveSource(_ context.Context, p v1.Package) (string, error) {
+	return p.GetSource(), nil
+}
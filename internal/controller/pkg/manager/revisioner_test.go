@@ -18,12 +18,14 @@ package manager
 
 import (
 	"context"
+	"net/url"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	conregv1 "github.com/google/go-containerregistry/pkg/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
@@ -38,9 +40,21 @@ func TestPackageRevisioner(t *testing.T) {
 	pullNever := corev1.PullNever
 	pullIfNotPresent := corev1.PullIfNotPresent
 
+	urls := xpkg.NewImageSourceFetcher(nil, xpkg.WithURLSources("https", func(_ *url.URL) (xpkg.Fetcher, error) {
+		return &fake.MockFetcher{
+			MockHead: fake.NewMockHeadFn(&conregv1.Descriptor{
+				Digest: conregv1.Hash{
+					Algorithm: "sha256",
+					Hex:       "ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904",
+				},
+			}, nil),
+		}, nil
+	}))
+
 	type args struct {
-		f   xpkg.Fetcher
-		pkg v1.Package
+		f    xpkg.Fetcher
+		pkg  v1.Package
+		opts []PackageRevisionerOption
 	}
 
 	type want struct {
@@ -124,6 +138,42 @@ func TestPackageRevisioner(t *testing.T) {
 				digest: "provider-nop-ecc25c121431",
 			},
 		},
+		"SuccessfulURL": {
+			reason: "Should return the digest of the package image at the package source URL.",
+			args: args{
+				pkg: &v1.Provider{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "provider-nop",
+					},
+					Spec: v1.ProviderSpec{
+						PackageSpec: v1.PackageSpec{
+							Package: "https://example.org/provider-nop.xpkg",
+						},
+					},
+				},
+				opts: []PackageRevisionerOption{WithImageSources(urls)},
+			},
+			want: want{
+				digest: "provider-nop-ecc25c121431",
+			},
+		},
+		"ErrURLVersionConstraint": {
+			reason: "Should return an error if a package whose source is a URL has a version constraint.",
+			args: args{
+				pkg: &v1.Provider{
+					Spec: v1.ProviderSpec{
+						PackageSpec: v1.PackageSpec{
+							Package:           "https://example.org/provider-nop.xpkg",
+							VersionConstraint: ptr.To(">=0.20"),
+						},
+					},
+				},
+				opts: []PackageRevisionerOption{WithImageSources(urls)},
+			},
+			want: want{
+				err: errors.New(errVersionConstraintURL),
+			},
+		},
 		"ErrParseRef": {
 			reason: "Should return an error if we cannot parse reference from package source image.",
 			args: args{
@@ -157,11 +207,44 @@ func TestPackageRevisioner(t *testing.T) {
 				err: errors.Wrap(errBoom, errFetchPackage),
 			},
 		},
+		"ErrNotDigest": {
+			reason: "Should return an error if a digest is required but the package source is a tag.",
+			args: args{
+				pkg: &v1.Provider{
+					Spec: v1.ProviderSpec{
+						PackageSpec: v1.PackageSpec{
+							Package: "test/test:test",
+						},
+					},
+				},
+				opts: []PackageRevisionerOption{WithRequireDigest(true)},
+			},
+			want: want{
+				err: errors.Errorf(errFmtNotDigest, "test/test:test"),
+			},
+		},
+		"ErrVersionConstraintNotDigest": {
+			reason: "Should return an error if a digest is required but the package has a version constraint.",
+			args: args{
+				pkg: &v1.Provider{
+					Spec: v1.ProviderSpec{
+						PackageSpec: v1.PackageSpec{
+							Package:           "test/test@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904",
+							VersionConstraint: ptr.To(">=0.20"),
+						},
+					},
+				},
+				opts: []PackageRevisionerOption{WithRequireDigest(true)},
+			},
+			want: want{
+				err: errors.New(errVersionConstraintDigest),
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			r := NewPackageRevisioner(tc.args.f)
+			r := NewPackageRevisioner(tc.args.f, tc.args.opts...)
 			h, err := r.Revision(context.TODO(), tc.args.pkg)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
@@ -173,3 +256,167 @@ func TestPackageRevisioner(t *testing.T) {
 		})
 	}
 }
+
+func TestPackageRevisionerResolveSource(t *testing.T) {
+	errBoom := errors.New("boom")
+	pullNever := corev1.PullNever
+	digest := "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904"
+
+	head := &fake.MockFetcher{
+		MockHead: fake.NewMockHeadFn(&conregv1.Descriptor{
+			Digest: conregv1.Hash{
+				Algorithm: "sha256",
+				Hex:       "ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904",
+			},
+		}, nil),
+		MockTags: fake.NewMockTagsFn([]string{"latest", "v0.19.0", "v0.20.1", "v0.21.3", "v0.22.0"}, nil),
+	}
+
+	type args struct {
+		f          xpkg.Fetcher
+		source     string
+		policy     *corev1.PullPolicy
+		constraint *string
+	}
+
+	type want struct {
+		source string
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Tag": {
+			reason: "A tag should be resolved to the digest it currently references.",
+			args: args{
+				f:      head,
+				source: "crossplane-contrib/provider-nop:v0.1.0",
+			},
+			want: want{
+				source: "crossplane-contrib/provider-nop:v0.1.0@" + digest,
+			},
+		},
+		"Digest": {
+			reason: "A digest should be returned unchanged.",
+			args: args{
+				f:      &fake.MockFetcher{MockHead: fake.NewMockHeadFn(nil, errBoom)},
+				source: "crossplane-contrib/provider-nop@" + digest,
+			},
+			want: want{
+				source: "crossplane-contrib/provider-nop@" + digest,
+			},
+		},
+		"PullNever": {
+			reason: "A package that is never pulled should not be resolved.",
+			args: args{
+				f:      &fake.MockFetcher{MockHead: fake.NewMockHeadFn(nil, errBoom)},
+				source: "crossplane-contrib/provider-nop:v0.1.0",
+				policy: &pullNever,
+			},
+			want: want{
+				source: "crossplane-contrib/provider-nop:v0.1.0",
+			},
+		},
+		"URL": {
+			reason: "A URL can't be resolved to a digest, so it should be returned unchanged.",
+			args: args{
+				f:      &fake.MockFetcher{MockHead: fake.NewMockHeadFn(nil, errBoom)},
+				source: "https://example.org/provider-nop.xpkg",
+			},
+			want: want{
+				source: "https://example.org/provider-nop.xpkg",
+			},
+		},
+		"ErrBadFetch": {
+			reason: "Should return an error if we fail to fetch the package digest.",
+			args: args{
+				f:      &fake.MockFetcher{MockHead: fake.NewMockHeadFn(nil, errBoom)},
+				source: "crossplane-contrib/provider-nop:v0.1.0",
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errFetchPackage),
+			},
+		},
+		"VersionConstraint": {
+			reason: "A package with a version constraint should be resolved to the highest version that satisfies it.",
+			args: args{
+				f:          head,
+				source:     "crossplane-contrib/provider-nop:v0.1.0@" + digest,
+				constraint: ptr.To(">=0.20, <0.22"),
+			},
+			want: want{
+				source: "crossplane-contrib/provider-nop:v0.21.3@" + digest,
+			},
+		},
+		"VersionConstraintPullNever": {
+			reason: "The version constraint of a package that is never pulled should be ignored.",
+			args: args{
+				f:          &fake.MockFetcher{MockTags: fake.NewMockTagsFn(nil, errBoom)},
+				source:     "crossplane-contrib/provider-nop:v0.1.0",
+				policy:     &pullNever,
+				constraint: ptr.To(">=0.20, <0.22"),
+			},
+			want: want{
+				source: "crossplane-contrib/provider-nop:v0.1.0",
+			},
+		},
+		"ErrInvalidVersionConstraint": {
+			reason: "Should return an error if the package's version constraint is invalid.",
+			args: args{
+				f:          head,
+				source:     "crossplane-contrib/provider-nop",
+				constraint: ptr.To("nope"),
+			},
+			want: want{
+				err: errors.Wrap(errors.New("improper constraint: nope"), errInvalidVersionConstraint),
+			},
+		},
+		"ErrFetchTags": {
+			reason: "Should return an error if we fail to fetch the package's tags.",
+			args: args{
+				f:          &fake.MockFetcher{MockTags: fake.NewMockTagsFn(nil, errBoom)},
+				source:     "crossplane-contrib/provider-nop",
+				constraint: ptr.To(">=0.20"),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errFetchTags),
+			},
+		},
+		"ErrNoMatchingVersion": {
+			reason: "Should return an error if no version of the package satisfies its version constraint.",
+			args: args{
+				f:          head,
+				source:     "crossplane-contrib/provider-nop",
+				constraint: ptr.To(">=1.0"),
+			},
+			want: want{
+				err: errors.Errorf(errFmtNoMatchingVersion, "crossplane-contrib/provider-nop", ">=1.0"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := &v1.Provider{
+				Spec: v1.ProviderSpec{
+					PackageSpec: v1.PackageSpec{
+						Package:           tc.args.source,
+						PackagePullPolicy: tc.args.policy,
+						VersionConstraint: tc.args.constraint,
+					},
+				},
+			}
+			got, err := NewPackageRevisioner(tc.args.f).ResolveSource(context.TODO(), p)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.ResolveSource(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.source, got); diff != "" {
+				t.Errorf("\n%s\nr.ResolveSource(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
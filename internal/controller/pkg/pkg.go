@@ -24,11 +24,13 @@ import (
 	"github.com/crossplane/crossplane/internal/controller/pkg/manager"
 	"github.com/crossplane/crossplane/internal/controller/pkg/resolver"
 	"github.com/crossplane/crossplane/internal/controller/pkg/revision"
+	"github.com/crossplane/crossplane/internal/controller/pkg/upgrade"
+	"github.com/crossplane/crossplane/internal/features"
 )
 
 // Setup package controllers.
 func Setup(mgr ctrl.Manager, o controller.Options) error {
-	for _, setup := range []func(ctrl.Manager, controller.Options) error{
+	setups := []func(ctrl.Manager, controller.Options) error{
 		manager.SetupConfiguration,
 		manager.SetupProvider,
 		manager.SetupFunction,
@@ -36,7 +38,14 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		revision.SetupConfigurationRevision,
 		revision.SetupProviderRevision,
 		revision.SetupFunctionRevision,
-	} {
+	}
+	if o.Features.Enabled(features.EnableAlphaPackageUpgrades) {
+		setups = append(setups, upgrade.Setup)
+	}
+	if o.Features.Enabled(features.EnableAlphaDependencyPlans) {
+		setups = append(setups, resolver.SetupDependencyPlan)
+	}
+	for _, setup := range setups {
 		if err := setup(mgr, o); err != nil {
 			return err
 		}
@@ -0,0 +1,476 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/parser"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1alpha1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/internal/controller/pkg/controller"
+	"github.com/crossplane/crossplane/internal/controller/pkg/revision"
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+const (
+	// lockName is the name of the package Lock.
+	lockName = "lock"
+
+	// maxPlanSteps is the maximum number of times a DependencyPlan resolves
+	// a package. A package is resolved again if a constraint that its
+	// resolved version doesn't satisfy is discovered.
+	maxPlanSteps = 1000
+)
+
+const (
+	errGetPlan              = "cannot get dependency plan"
+	errUpdatePlanStatus     = "cannot update dependency plan status"
+	errPlan                 = "cannot plan dependencies"
+	errParsePlanPackage     = "cannot parse planned package"
+	errFetchMeta            = "cannot fetch package metadata"
+	errParseMeta            = "cannot parse package metadata"
+	errNotMeta              = "package metadata is not a Provider, Configuration, or Function"
+	errPlanTooLarge         = "cannot plan dependencies: too many packages"
+	errFmtNotOneMeta        = "package must contain exactly one metadata object, found %d"
+	errFmtPlanUnsatisfiable = "%d of %d packages can't be resolved"
+)
+
+const reasonPlan event.Reason = "PlanDependencies"
+
+// A MetaFetcher fetches the metadata of a package.
+type MetaFetcher interface {
+	// FetchMeta fetches the metadata of the package of the supplied type
+	// at the supplied source.
+	FetchMeta(ctx context.Context, t v1beta1.PackageType, source string, secrets []corev1.LocalObjectReference) (pkgmetav1.Pkg, error)
+}
+
+// A MetaFetcherFn fetches the metadata of a package.
+type MetaFetcherFn func(ctx context.Context, t v1beta1.PackageType, source string, secrets []corev1.LocalObjectReference) (pkgmetav1.Pkg, error)
+
+// FetchMeta fetches the metadata of a package.
+func (fn MetaFetcherFn) FetchMeta(ctx context.Context, t v1beta1.PackageType, source string, secrets []corev1.LocalObjectReference) (pkgmetav1.Pkg, error) {
+	return fn(ctx, t, source, secrets)
+}
+
+// An ImageMetaFetcher fetches package metadata from package images.
+type ImageMetaFetcher struct {
+	backend parser.Backend
+	parser  parser.Parser
+}
+
+// NewImageMetaFetcher returns a MetaFetcher that pulls package images using
+// the supplied backend, and parses them using the supplied parser.
+func NewImageMetaFetcher(b parser.Backend, p parser.Parser) *ImageMetaFetcher {
+	return &ImageMetaFetcher{backend: b, parser: p}
+}
+
+// FetchMeta fetches the metadata of a package from its image.
+func (f *ImageMetaFetcher) FetchMeta(ctx context.Context, t v1beta1.PackageType, source string, secrets []corev1.LocalObjectReference) (pkgmetav1.Pkg, error) {
+	_, pr, ok := newPackage(t)
+	if !ok {
+		return nil, errors.New(errInvalidPackageType)
+	}
+	pr.SetSource(source)
+	pr.SetPackagePullSecrets(secrets)
+
+	rc, err := f.backend.Init(ctx, revision.PackageRevision(pr))
+	if err != nil {
+		return nil, errors.Wrap(err, errFetchMeta)
+	}
+
+	// The parser closes the reader.
+	pkg, err := f.parser.Parse(ctx, rc)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseMeta)
+	}
+	if len(pkg.GetMeta()) != 1 {
+		return nil, errors.Errorf(errFmtNotOneMeta, len(pkg.GetMeta()))
+	}
+	p, ok := xpkg.TryConvertToPkg(pkg.GetMeta()[0], &pkgmetav1.Provider{}, &pkgmetav1.Configuration{}, &pkgmetav1.Function{})
+	if !ok {
+		return nil, errors.New(errNotMeta)
+	}
+	return p, nil
+}
+
+// PlanReconcilerOption is used to configure the PlanReconciler.
+type PlanReconcilerOption func(*PlanReconciler)
+
+// WithPlanLogger specifies how the PlanReconciler should log messages.
+func WithPlanLogger(log logging.Logger) PlanReconcilerOption {
+	return func(r *PlanReconciler) {
+		r.log = log
+	}
+}
+
+// WithPlanRecorder specifies how the PlanReconciler should record events.
+func WithPlanRecorder(er event.Recorder) PlanReconcilerOption {
+	return func(r *PlanReconciler) {
+		r.record = er
+	}
+}
+
+// WithPlanFetcher specifies how the PlanReconciler should fetch package tags.
+func WithPlanFetcher(f xpkg.Fetcher) PlanReconcilerOption {
+	return func(r *PlanReconciler) {
+		r.fetcher = f
+	}
+}
+
+// WithMetaFetcher specifies how the PlanReconciler should fetch package
+// metadata.
+func WithMetaFetcher(f MetaFetcher) PlanReconcilerOption {
+	return func(r *PlanReconciler) {
+		r.meta = f
+	}
+}
+
+// WithPlanDefaultRegistry sets the default registry to use.
+func WithPlanDefaultRegistry(registry string) PlanReconcilerOption {
+	return func(r *PlanReconciler) {
+		r.registry = registry
+	}
+}
+
+// PlanReconciler reconciles DependencyPlans.
+type PlanReconciler struct {
+	client   client.Client
+	log      logging.Logger
+	record   event.Recorder
+	fetcher  xpkg.Fetcher
+	meta     MetaFetcher
+	registry string
+}
+
+// SetupDependencyPlan adds a controller that reconciles DependencyPlans.
+func SetupDependencyPlan(mgr ctrl.Manager, o controller.Options) error {
+	name := "packages/" + strings.ToLower(v1alpha1.DependencyPlanGroupKind)
+
+	cs, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return errors.Wrap(err, "failed to initialize clientset")
+	}
+	f, err := o.Fetcher(cs)
+	if err != nil {
+		return errors.Wrap(err, "cannot build fetcher")
+	}
+	sources, err := o.ImageSources(cs, f)
+	if err != nil {
+		return errors.Wrap(err, "cannot build fetcher")
+	}
+	metaScheme, err := xpkg.BuildMetaScheme()
+	if err != nil {
+		return errors.Wrap(err, "cannot build meta scheme for package parser")
+	}
+	objScheme, err := xpkg.BuildObjectScheme()
+	if err != nil {
+		return errors.Wrap(err, "cannot build object scheme for package parser")
+	}
+
+	r := NewPlanReconciler(mgr,
+		WithPlanLogger(o.Logger.WithValues("controller", name)),
+		WithPlanRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		WithPlanFetcher(f),
+		WithMetaFetcher(NewImageMetaFetcher(
+			revision.NewImageBackend(f, revision.WithDefaultRegistry(o.DefaultRegistry), revision.WithImageSources(sources)),
+			xpkg.NewParser(metaScheme, objScheme),
+		)),
+		WithPlanDefaultRegistry(o.DefaultRegistry),
+	)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha1.DependencyPlan{}).
+		WithOptions(o.ForControllerRuntime()).
+		Complete(ratelimiter.NewReconciler(name, errors.WithSilentRequeueOnConflict(r), o.GlobalRateLimiter))
+}
+
+// NewPlanReconciler creates a new DependencyPlan reconciler.
+func NewPlanReconciler(mgr manager.Manager, opts ...PlanReconcilerOption) *PlanReconciler {
+	r := &PlanReconciler{
+		client:  mgr.GetClient(),
+		log:     logging.NewNopLogger(),
+		record:  event.NewNopRecorder(),
+		fetcher: xpkg.NewNopFetcher(),
+	}
+
+	for _, f := range opts {
+		f(r)
+	}
+
+	return r
+}
+
+// Reconcile a DependencyPlan. A plan is computed once, without installing
+// anything.
+func (r *PlanReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(ctx, reconcileTimeout)
+	defer cancel()
+
+	p := &v1alpha1.DependencyPlan{}
+	if err := r.client.Get(ctx, req.NamespacedName, p); err != nil {
+		log.Debug(errGetPlan, "error", err)
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetPlan)
+	}
+
+	if meta.WasDeleted(p) {
+		return reconcile.Result{}, nil
+	}
+
+	// The plan has already been computed.
+	if p.GetCondition(xpv1.TypeReady).Status != corev1.ConditionUnknown {
+		return reconcile.Result{}, nil
+	}
+
+	pkgs, err := r.plan(ctx, p)
+	if err != nil {
+		log.Debug(errPlan, "error", err)
+		err = errors.Wrap(err, errPlan)
+		r.record.Event(p, event.Warning(reasonPlan, err))
+		p.SetConditions(xpv1.ReconcileError(err))
+		_ = r.client.Status().Update(ctx, p)
+		return reconcile.Result{}, err
+	}
+
+	p.Status.Packages = pkgs
+	p.SetConditions(xpv1.Available(), xpv1.ReconcileSuccess())
+	unsatisfiable := 0
+	for _, pp := range pkgs {
+		if pp.Action == v1alpha1.PlannedActionUnsatisfiable {
+			unsatisfiable++
+		}
+	}
+	if unsatisfiable > 0 {
+		p.SetConditions(xpv1.Unavailable().WithMessage(fmt.Sprintf(errFmtPlanUnsatisfiable, unsatisfiable, len(pkgs))))
+	}
+
+	return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdatePlanStatus)
+}
+
+// A planNode is a package in a DependencyPlan's dependency tree.
+type planNode struct {
+	planned     v1alpha1.PlannedPackage
+	digest      string
+	constraints []Constraint
+	resolved    bool
+}
+
+// plan resolves the dependency tree of the supplied DependencyPlan's package
+// the way the package manager would, against the packages that are already
+// installed. Each package is resolved to the installed version if it
+// satisfies every constraint on it, or else the highest version that does.
+func (r *PlanReconciler) plan(ctx context.Context, p *v1alpha1.DependencyPlan) ([]v1alpha1.PlannedPackage, error) {
+	lock := &v1beta1.Lock{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: lockName}, lock); resource.IgnoreNotFound(err) != nil {
+		return nil, errors.Wrap(err, errGetLock)
+	}
+
+	// Like the package manager, we identify the planned package by its
+	// source without a tag or digest.
+	ref, err := name.ParseReference(xpkg.UnpinnedSource(p.Spec.Package), name.WithDefaultRegistry(""))
+	if err != nil {
+		return nil, errors.Wrap(err, errParsePlanPackage)
+	}
+	t := v1beta1.PackageType(p.Spec.Type)
+	if t == "" {
+		t = v1beta1.ConfigurationPackageType
+	}
+	root := &planNode{planned: v1alpha1.PlannedPackage{
+		Type:    string(t),
+		Package: xpkg.ParsePackageSourceFromReference(ref),
+		Version: ref.Identifier(),
+		Action:  v1alpha1.PlannedActionInstall,
+	}}
+
+	// Installed packages other than the planned package constrain the
+	// versions of their dependencies.
+	installed := map[string]v1beta1.LockPackage{}
+	others := make([]v1beta1.LockPackage, 0, len(lock.Packages))
+	for _, lp := range lock.Packages {
+		installed[lp.Identifier()] = lp
+		if lp.Identifier() != root.planned.Package {
+			others = append(others, lp)
+		}
+	}
+	if lp, ok := installed[root.planned.Package]; ok {
+		root.planned.InstalledVersion = lp.Version
+		root.planned.Action = v1alpha1.PlannedActionUpgrade
+		if lp.Version == root.planned.Version {
+			root.planned.Action = v1alpha1.PlannedActionNone
+		}
+	}
+
+	m, err := r.meta.FetchMeta(ctx, t, p.Spec.Package, p.Spec.PackagePullSecrets)
+	if err != nil {
+		return nil, err
+	}
+	deps, err := dependencies(m)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := map[string]*planNode{root.planned.Package: root}
+	order := []string{root.planned.Package}
+	var queue []string
+	add := func(dependent string, deps []v1beta1.Dependency) {
+		for _, d := range deps {
+			// Like the package manager, we plan to install the first
+			// alternative unless another is installed.
+			d = xpkg.PreferInstalled(d, lock.Packages)
+			id := d.Identifier()
+			n, ok := nodes[id]
+			if !ok {
+				n = &planNode{
+					planned:     v1alpha1.PlannedPackage{Type: string(d.Type), Package: id},
+					constraints: dependencyConstraints(others, id),
+				}
+				nodes[id] = n
+				order = append(order, id)
+			}
+			n.constraints = append(n.constraints, Constraint{Dependent: dependent, Constraints: d.Constraints, Channel: d.Channel})
+			if !slices.Contains(n.planned.RequiredBy, dependent) {
+				n.planned.RequiredBy = append(n.planned.RequiredBy, dependent)
+			}
+			queue = append(queue, id)
+		}
+	}
+	add(root.planned.Package, deps)
+
+	for i := 0; len(queue) > 0; i++ {
+		if i > maxPlanSteps {
+			return nil, errors.New(errPlanTooLarge)
+		}
+		id := queue[0]
+		queue = queue[1:]
+
+		n := nodes[id]
+		if n.resolved && (n.planned.Action == v1alpha1.PlannedActionUnsatisfiable || satisfiedBy(v1beta1.DependencyResolution{Version: n.planned.Version, Digest: n.digest}, n.constraints)) {
+			continue
+		}
+		n.resolved = true
+
+		lp, ok := installed[id]
+		deps, err := r.resolve(ctx, n, lp, ok)
+		if err != nil {
+			return nil, err
+		}
+		add(id, deps)
+	}
+
+	out := make([]v1alpha1.PlannedPackage, len(order))
+	for i, id := range order {
+		out[i] = nodes[id].planned
+	}
+	return out, nil
+}
+
+// resolve the supplied dependency, and return its dependencies.
+func (r *PlanReconciler) resolve(ctx context.Context, n *planNode, lp v1beta1.LockPackage, installed bool) ([]v1beta1.Dependency, error) {
+	n.planned.Message = ""
+	n.planned.Action = v1alpha1.PlannedActionInstall
+	if installed {
+		n.planned.InstalledVersion = lp.Version
+		n.planned.Action = v1alpha1.PlannedActionUpgrade
+
+		// An installed dependency that satisfies its constraints isn't
+		// changed. The lock records its dependencies.
+		if satisfiedBy(v1beta1.DependencyResolution{Version: lp.Version, Digest: lp.Digest}, n.constraints) {
+			n.planned.Version, n.digest = lp.Version, lp.Digest
+			n.planned.Action = v1alpha1.PlannedActionNone
+			return lp.Dependencies, nil
+		}
+	}
+
+	ref, err := name.ParseReference(n.planned.Package, name.WithDefaultRegistry(r.registry))
+	if err != nil {
+		return nil, errors.Wrap(err, errParsePlanPackage)
+	}
+
+	version, conflicting, err := findVersion(ctx, r.fetcher, n.constraints, r.log, ref)
+	if err != nil {
+		n.planned.Action, n.planned.Message = v1alpha1.PlannedActionUnsatisfiable, err.Error()
+		return nil, nil
+	}
+	if len(conflicting) > 0 {
+		n.planned.Action, n.planned.Message = v1alpha1.PlannedActionUnsatisfiable, errors.Errorf(errFmtConflict, n.planned.Package, describe(conflicting)).Error()
+		return nil, nil
+	}
+	if version == "" {
+		n.planned.Action, n.planned.Message = v1alpha1.PlannedActionUnsatisfiable, errors.Errorf(errFmtNoValidVersion, n.planned.Package, describe(n.constraints)).Error()
+		return nil, nil
+	}
+
+	n.planned.Version = version
+	source := fmt.Sprintf(packageTagFmt, ref.String(), version)
+	if strings.HasPrefix(version, "sha256:") {
+		n.digest = version
+		source = fmt.Sprintf(packageDigestFmt, ref.String(), version)
+	}
+
+	m, err := r.meta.FetchMeta(ctx, v1beta1.PackageType(n.planned.Type), source, nil)
+	if err != nil {
+		return nil, err
+	}
+	return dependencies(m)
+}
+
+// dependencies returns the package dependencies of the supplied package
+// metadata. Dependencies on an API are omitted, because they're not satisfied
+// by a particular package.
+func dependencies(m pkgmetav1.Pkg) ([]v1beta1.Dependency, error) {
+	deps := make([]v1beta1.Dependency, 0, len(m.GetDependencies()))
+	for _, d := range m.GetDependencies() {
+		_, api, err := xpkg.DependencyAPI(d)
+		if err != nil {
+			return nil, errors.Wrap(err, errInvalidDependency)
+		}
+		if api {
+			continue
+		}
+		ld, err := xpkg.LockDependency(d)
+		if err != nil {
+			return nil, errors.Wrap(err, errInvalidDependency)
+		}
+		deps = append(deps, ld)
+	}
+	return deps, nil
+}
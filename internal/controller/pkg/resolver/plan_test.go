@@ -0,0 +1,241 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1alpha1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	fakexpkg "github.com/crossplane/crossplane/internal/xpkg/fake"
+)
+
+func TestPlanReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	plan := &v1alpha1.DependencyPlan{
+		Spec: v1alpha1.DependencyPlanSpec{
+			Type:    string(v1beta1.ConfigurationPackageType),
+			Package: "xpkg.example.org/platform:v1.0.0",
+		},
+	}
+
+	// The platform Configuration depends on provider-nop, which depends on
+	// function-nop.
+	metas := MetaFetcherFn(func(_ context.Context, _ v1beta1.PackageType, source string, _ []corev1.LocalObjectReference) (pkgmetav1.Pkg, error) {
+		switch source {
+		case "xpkg.example.org/platform:v1.0.0":
+			return &pkgmetav1.Configuration{Spec: pkgmetav1.ConfigurationSpec{MetaSpec: pkgmetav1.MetaSpec{DependsOn: []pkgmetav1.Dependency{
+				{Provider: ptr.To("xpkg.example.org/provider-nop"), Version: ">=v1.0.0"},
+			}}}}, nil
+		case "xpkg.example.org/provider-nop:v1.1.0":
+			return &pkgmetav1.Provider{Spec: pkgmetav1.ProviderSpec{MetaSpec: pkgmetav1.MetaSpec{DependsOn: []pkgmetav1.Dependency{
+				{Function: ptr.To("xpkg.example.org/function-nop"), Version: ">=v1.1.0"},
+			}}}}, nil
+		case "xpkg.example.org/function-nop:v1.1.0":
+			return &pkgmetav1.Function{}, nil
+		}
+		return nil, errors.Errorf("unexpected source %s", source)
+	})
+
+	// kube returns a client that gets the supplied plan and lock, and records
+	// the plan's status when it's updated.
+	kube := func(p *v1alpha1.DependencyPlan, l *v1beta1.Lock, status **v1alpha1.DependencyPlan) *test.MockClient {
+		return &test.MockClient{
+			MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+				switch out := obj.(type) {
+				case *v1alpha1.DependencyPlan:
+					p.DeepCopyInto(out)
+				case *v1beta1.Lock:
+					if l == nil {
+						return kerrors.NewNotFound(schema.GroupResource{}, lockName)
+					}
+					l.DeepCopyInto(out)
+				}
+				return nil
+			},
+			MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(obj client.Object) error {
+				*status = obj.(*v1alpha1.DependencyPlan).DeepCopy()
+				return nil
+			}),
+		}
+	}
+
+	type args struct {
+		lock *v1beta1.Lock
+		plan *v1alpha1.DependencyPlan
+		tags []string
+		meta MetaFetcher
+		kube client.Client
+	}
+	type want struct {
+		plan *v1alpha1.DependencyPlan
+		err  error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"GetPlanError": {
+			reason: "We should return an error if we can't get the plan.",
+			args: args{
+				kube: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetPlan),
+			},
+		},
+		"AlreadyPlanned": {
+			reason: "We shouldn't plan again once a plan has been computed.",
+			args: args{
+				plan: func() *v1alpha1.DependencyPlan {
+					p := plan.DeepCopy()
+					p.SetConditions(xpv1.Available())
+					return p
+				}(),
+				meta: MetaFetcherFn(func(_ context.Context, _ v1beta1.PackageType, _ string, _ []corev1.LocalObjectReference) (pkgmetav1.Pkg, error) {
+					return nil, errBoom
+				}),
+			},
+			want: want{},
+		},
+		"FetchMetaError": {
+			reason: "We should return an error if we can't fetch the planned package's metadata.",
+			args: args{
+				plan: plan,
+				meta: MetaFetcherFn(func(_ context.Context, _ v1beta1.PackageType, _ string, _ []corev1.LocalObjectReference) (pkgmetav1.Pkg, error) {
+					return nil, errBoom
+				}),
+			},
+			want: want{
+				plan: func() *v1alpha1.DependencyPlan {
+					p := plan.DeepCopy()
+					p.SetConditions(xpv1.ReconcileError(errors.Wrap(errBoom, errPlan)))
+					return p
+				}(),
+				err: errors.Wrap(errBoom, errPlan),
+			},
+		},
+		"PlanInstall": {
+			reason: "We should plan to install the highest version of each dependency that satisfies its constraints.",
+			args: args{
+				plan: plan,
+				tags: []string{"v0.1.0", "v1.0.0", "v1.1.0"},
+				meta: metas,
+			},
+			want: want{
+				plan: func() *v1alpha1.DependencyPlan {
+					p := plan.DeepCopy()
+					p.Status.Packages = []v1alpha1.PlannedPackage{
+						{Type: "Configuration", Package: "xpkg.example.org/platform", Version: "v1.0.0", Action: v1alpha1.PlannedActionInstall},
+						{Type: "Provider", Package: "xpkg.example.org/provider-nop", Version: "v1.1.0", Action: v1alpha1.PlannedActionInstall, RequiredBy: []string{"xpkg.example.org/platform"}},
+						{Type: "Function", Package: "xpkg.example.org/function-nop", Version: "v1.1.0", Action: v1alpha1.PlannedActionInstall, RequiredBy: []string{"xpkg.example.org/provider-nop"}},
+					}
+					p.SetConditions(xpv1.Available(), xpv1.ReconcileSuccess())
+					return p
+				}(),
+			},
+		},
+		"PlanInstalled": {
+			reason: "We should plan to leave installed packages that satisfy their constraints as they are, and upgrade those that don't.",
+			args: args{
+				plan: plan,
+				lock: &v1beta1.Lock{Packages: []v1beta1.LockPackage{
+					{Type: v1beta1.ProviderPackageType, Source: "xpkg.example.org/provider-nop", Version: "v1.0.0", Dependencies: []v1beta1.Dependency{
+						{Package: "xpkg.example.org/function-nop", Type: v1beta1.FunctionPackageType, Constraints: ">=v1.0.0"},
+					}},
+					{Type: v1beta1.FunctionPackageType, Source: "xpkg.example.org/function-nop", Version: "v1.0.0"},
+				}},
+				tags: []string{"v0.1.0", "v1.0.0", "v1.1.0"},
+				meta: metas,
+			},
+			want: want{
+				plan: func() *v1alpha1.DependencyPlan {
+					p := plan.DeepCopy()
+					p.Status.Packages = []v1alpha1.PlannedPackage{
+						{Type: "Configuration", Package: "xpkg.example.org/platform", Version: "v1.0.0", Action: v1alpha1.PlannedActionInstall},
+						{Type: "Provider", Package: "xpkg.example.org/provider-nop", Version: "v1.0.0", InstalledVersion: "v1.0.0", Action: v1alpha1.PlannedActionNone, RequiredBy: []string{"xpkg.example.org/platform"}},
+						{Type: "Function", Package: "xpkg.example.org/function-nop", Version: "v1.0.0", InstalledVersion: "v1.0.0", Action: v1alpha1.PlannedActionNone, RequiredBy: []string{"xpkg.example.org/provider-nop"}},
+					}
+					p.SetConditions(xpv1.Available(), xpv1.ReconcileSuccess())
+					return p
+				}(),
+			},
+		},
+		"PlanUnsatisfiable": {
+			reason: "We should record dependencies that no version satisfies, and report that the plan can't be installed.",
+			args: args{
+				plan: plan,
+				tags: []string{"v0.1.0"},
+				meta: metas,
+			},
+			want: want{
+				plan: func() *v1alpha1.DependencyPlan {
+					p := plan.DeepCopy()
+					p.Status.Packages = []v1alpha1.PlannedPackage{
+						{Type: "Configuration", Package: "xpkg.example.org/platform", Version: "v1.0.0", Action: v1alpha1.PlannedActionInstall},
+						{
+							Type: "Provider", Package: "xpkg.example.org/provider-nop", Action: v1alpha1.PlannedActionUnsatisfiable, RequiredBy: []string{"xpkg.example.org/platform"},
+							Message: errors.Errorf(errFmtNoValidVersion, "xpkg.example.org/provider-nop", "xpkg.example.org/platform requires >=v1.0.0").Error(),
+						},
+					}
+					p.SetConditions(xpv1.Unavailable().WithMessage(fmt.Sprintf(errFmtPlanUnsatisfiable, 1, 2)), xpv1.ReconcileSuccess())
+					return p
+				}(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got *v1alpha1.DependencyPlan
+			c := tc.args.kube
+			if c == nil {
+				c = kube(tc.args.plan, tc.args.lock, &got)
+			}
+			r := NewPlanReconciler(&fake.Manager{Client: c},
+				WithPlanFetcher(&fakexpkg.MockFetcher{MockTags: fakexpkg.NewMockTagsFn(tc.args.tags, nil)}),
+				WithMetaFetcher(tc.args.meta),
+			)
+
+			_, err := r.Reconcile(context.Background(), reconcile.Request{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.plan, got, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want status, +got status:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -24,17 +24,19 @@ import (
 	"strings"
 	"time"
 
-	"github.com/Masterminds/semver"
 	"github.com/google/go-containerregistry/pkg/name"
 	conregv1 "github.com/google/go-containerregistry/pkg/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -63,12 +65,29 @@ const (
 	errSortDAG              = "cannot sort DAG"
 	errFmtMissingDependency = "missing package (%s) is not a dependency"
 	errInvalidConstraint    = "version constraint on dependency is invalid"
+	errFmtInvalidConstraint = "invalid version constraint (%s)"
 	errInvalidDependency    = "dependency package is not valid"
 	errFetchTags            = "cannot fetch dependency package tags"
 	errNoValidVersion       = "cannot find a valid version for package constraints"
 	errFmtNoValidVersion    = "dependency (%s) does not have version in constraints (%s)"
+	errFmtConflict          = "no version of dependency (%s) satisfies all of its constraints, conflicting constraints: %s"
 	errInvalidPackageType   = "cannot create invalid package dependency type"
 	errCreateDependency     = "cannot create dependency package"
+	errResolveDigest        = "cannot resolve dependency version to a digest"
+	errUpdateLock           = "cannot update package lock"
+	errFmtNoUpgrade         = "cannot upgrade dependency (%s) from %s: the highest version satisfying its constraints (%s) is %s"
+	errGetRevision          = "cannot get dependency package revision"
+	errGetPackage           = "cannot get dependency package"
+	errUpgradeDependency    = "cannot upgrade dependency package"
+	errFmtManualActivation  = "not upgrading dependency (%s) from %s to %s: its package has a Manual revision activation policy"
+	errFollowChannel        = "cannot update dependency to the version its channel points to"
+	errFmtNoAlternative     = "cannot resolve dependency (%s) or any of its alternatives: %s"
+)
+
+// Event reasons.
+const (
+	reasonResolve event.Reason = "ResolveDependencies"
+	reasonUpgrade event.Reason = "UpgradeDependency"
 )
 
 // ReconcilerOption is used to configure the Reconciler.
@@ -109,14 +128,43 @@ func WithDefaultRegistry(registry string) ReconcilerOption {
 	}
 }
 
+// WithSkipDependencyResolution specifies whether the Reconciler should skip
+// installing and upgrading dependencies for every package. Packages still
+// check that their dependencies are installed.
+func WithSkipDependencyResolution(skip bool) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.skipResolution = skip
+	}
+}
+
+// WithChannelPollInterval specifies how often the Reconciler checks whether
+// the channels that installed dependencies track have moved. Dependencies
+// aren't updated when their channel moves if it's zero.
+func WithChannelPollInterval(d time.Duration) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.channelInterval = d
+	}
+}
+
+// WithRecorder specifies how the Reconciler should record events.
+func WithRecorder(er event.Recorder) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.record = er
+	}
+}
+
 // Reconciler reconciles packages.
 type Reconciler struct {
 	client   client.Client
 	log      logging.Logger
+	record   event.Recorder
 	lock     resource.Finalizer
 	newDag   dag.NewDAGFn
 	fetcher  xpkg.Fetcher
 	registry string
+
+	skipResolution  bool
+	channelInterval time.Duration
 }
 
 // Setup adds a controller that reconciles the Lock.
@@ -127,7 +175,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 	if err != nil {
 		return errors.Wrap(err, "failed to initialize clientset")
 	}
-	f, err := xpkg.NewK8sFetcher(cs, append(o.FetcherOptions, xpkg.WithNamespace(o.Namespace), xpkg.WithServiceAccount(o.ServiceAccount))...)
+	f, err := o.Fetcher(cs)
 	if err != nil {
 		return errors.Wrap(err, "cannot build fetcher")
 	}
@@ -136,6 +184,9 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		WithLogger(o.Logger.WithValues("controller", name)),
 		WithFetcher(f),
 		WithDefaultRegistry(o.DefaultRegistry),
+		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		WithSkipDependencyResolution(o.SkipDependencyResolution),
+		WithChannelPollInterval(o.ChannelPollInterval),
 	)
 
 	return ctrl.NewControllerManagedBy(mgr).
@@ -153,6 +204,7 @@ func NewReconciler(mgr manager.Manager, opts ...ReconcilerOption) *Reconciler {
 		client:  mgr.GetClient(),
 		lock:    resource.NewAPIFinalizer(mgr.GetClient(), finalizer),
 		log:     logging.NewNopLogger(),
+		record:  event.NewNopRecorder(),
 		newDag:  dag.NewMapDag,
 		fetcher: xpkg.NewNopFetcher(),
 	}
@@ -209,62 +261,159 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		"name", lock.GetName(),
 	)
 
-	dag := r.newDag()
-	implied, err := dag.Init(v1beta1.ToNodes(lock.Packages...))
+	d := r.newDag()
+	implied, err := d.Init(v1beta1.ToNodes(lock.Packages...))
 	if err != nil {
 		log.Debug(errBuildDAG, "error", err)
 		return reconcile.Result{}, errors.Wrap(err, errBuildDAG)
 	}
 
 	// Make sure we don't have any cyclical imports. If we do, refuse to
-	// install additional packages.
-	_, err = dag.Sort()
+	// install additional packages. The packages in the cycle report it, and
+	// we'll be requeued when one of them changes the lock.
+	_, err = d.Sort()
+	if ce := (&dag.CycleError{}); errors.As(err, &ce) {
+		log.Debug(errSortDAG, "error", err)
+		r.record.Event(lock, event.Warning(reasonResolve, errors.Wrap(err, errSortDAG)))
+		return reconcile.Result{Requeue: false}, nil
+	}
 	if err != nil {
 		log.Debug(errSortDAG, "error", err)
 		return reconcile.Result{}, errors.Wrap(err, errSortDAG)
 	}
 
-	if len(implied) == 0 {
+	// Forget the resolutions of dependencies that no package depends on.
+	if rs, pruned := pruneResolutions(lock.Resolutions, lock.Packages); pruned {
+		lock.Resolutions = rs
+		if err := r.client.Update(ctx, lock); err != nil {
+			log.Debug(errUpdateLock, "error", err)
+			if kerrors.IsConflict(err) {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			return reconcile.Result{}, errors.Wrap(err, errUpdateLock)
+		}
+	}
+
+	// Packages only check that their dependencies are installed when the
+	// package manager skips dependency resolution.
+	if r.skipResolution {
 		return reconcile.Result{Requeue: false}, nil
 	}
 
-	// If we are missing a node, we want to create it. The resolver never
-	// modifies the Lock. We only create the first implied node as we will
-	// be requeued when it adds itself to the Lock, at which point we will
-	// check for missing nodes again.
+	// Don't install dependencies that only packages that skip dependency
+	// resolution depend on.
+	implied = installable(lock.Packages, implied)
+
+	// Don't install dependencies that are satisfied by an installed
+	// alternative.
+	implied = unsatisfied(lock.Packages, implied)
+
+	if len(implied) == 0 {
+		// Every dependency is installed, but an installed dependency may not
+		// satisfy the constraints of a package that was installed after it.
+		if r.channelInterval <= 0 {
+			return r.upgradeDependency(ctx, lock, log)
+		}
+
+		// Dependencies that track a channel follow it when it moves. We poll
+		// the channels, because nothing tells us when they move.
+		updated, err := r.followChannels(ctx, lock, log)
+		if kerrors.IsConflict(err) {
+			return reconcile.Result{Requeue: true}, nil
+		}
+		if err != nil || updated {
+			return reconcile.Result{Requeue: false}, err
+		}
+		result, err := r.upgradeDependency(ctx, lock, log)
+		if err == nil && !result.Requeue && len(tracking(lock.Packages)) > 0 {
+			result.RequeueAfter = r.channelInterval
+		}
+		return result, err
+	}
+
+	// If we are missing a node, we want to create it. The resolver only
+	// modifies the Lock to record the resolutions of the dependencies it
+	// installs. We only create the first implied node as we will be requeued
+	// when it adds itself to the Lock, at which point we will check for
+	// missing nodes again.
 	dep, ok := implied[0].(*v1beta1.Dependency)
 	if !ok {
 		log.Debug(errInvalidDependency, "error", errors.Errorf(errFmtMissingDependency, dep.Identifier()))
 		return reconcile.Result{Requeue: false}, nil
 	}
 
+	// A dependency with alternatives is satisfied by any one of them. We
+	// install the first that can be resolved.
+	if len(dep.Alternatives) > 0 {
+		c, reasons := r.firstResolvable(ctx, lock, dep, log)
+		if c == nil {
+			err := errors.Errorf(errFmtNoAlternative, dep.Identifier(), strings.Join(reasons, "; "))
+			log.Debug(errNoValidVersion, "error", err)
+			r.record.Event(lock, event.Warning(reasonResolve, err))
+			return reconcile.Result{Requeue: false}, nil
+		}
+		dep = c
+	}
+
 	ref, err := name.ParseReference(dep.Package, name.WithDefaultRegistry(r.registry))
 	if err != nil {
 		log.Debug(errInvalidDependency, "error", err)
 		return reconcile.Result{Requeue: false}, nil
 	}
 
-	var addVer string
-	if addVer, err = r.findDependencyVersion(ctx, dep, log, ref); err != nil {
-		return reconcile.Result{Requeue: false}, errors.New(errInvalidDependency)
+	// Several packages may depend on the missing package. We must install a
+	// version that satisfies all of their constraints.
+	cs := dependencyConstraints(lock.Packages, dep.Identifier())
+	if len(cs) == 0 {
+		cs = []Constraint{{Constraints: dep.Constraints, Channel: dep.Channel}}
 	}
 
-	// NOTE(hasheddan): consider creating event on package revision
-	// dictating constraints.
-	if addVer == "" {
-		log.Debug(errNoValidVersion, "error", errors.Errorf(errFmtNoValidVersion, dep.Identifier(), dep.Constraints))
-		return reconcile.Result{Requeue: false}, nil
+	// Install the dependency at the digest it was previously resolved to, if
+	// that still satisfies its constraints. This makes installs reproducible,
+	// e.g. if the dependency is deleted or the Lock is restored from a backup.
+	res, ok := recordedResolution(lock.Resolutions, dep, cs)
+	if !ok {
+		addVer, conflicting, err := r.findDependencyVersion(ctx, cs, log, ref)
+		if err != nil {
+			return reconcile.Result{Requeue: false}, errors.New(errInvalidDependency)
+		}
+
+		if len(conflicting) > 0 {
+			err := errors.Errorf(errFmtConflict, dep.Identifier(), describe(conflicting))
+			log.Debug(errNoValidVersion, "error", err)
+			r.record.Event(lock, event.Warning(reasonResolve, err))
+			return reconcile.Result{Requeue: false}, nil
+		}
+
+		// NOTE(hasheddan): consider creating event on package revision
+		// dictating constraints.
+		if addVer == "" {
+			err := errors.Errorf(errFmtNoValidVersion, dep.Identifier(), describe(cs))
+			log.Debug(errNoValidVersion, "error", err)
+			r.record.Event(lock, event.Warning(reasonResolve, err))
+			return reconcile.Result{Requeue: false}, nil
+		}
+
+		res, err = r.resolveDigest(ctx, dep, ref, addVer)
+		if err != nil {
+			log.Debug(errResolveDigest, "error", err)
+			return reconcile.Result{}, errors.Wrap(err, errResolveDigest)
+		}
+
+		// Record the resolution before we create the dependency, so that it's
+		// created at the same digest if creating it fails.
+		lock.Resolutions = setResolution(lock.Resolutions, res)
+		if err := r.client.Update(ctx, lock); err != nil {
+			log.Debug(errUpdateLock, "error", err)
+			if kerrors.IsConflict(err) {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			return reconcile.Result{}, errors.Wrap(err, errUpdateLock)
+		}
 	}
 
-	var pack v1.Package
-	switch dep.Type {
-	case v1beta1.ConfigurationPackageType:
-		pack = &v1.Configuration{}
-	case v1beta1.ProviderPackageType:
-		pack = &v1.Provider{}
-	case v1beta1.FunctionPackageType:
-		pack = &v1.Function{}
-	default:
+	pack, _, ok := newPackage(dep.Type)
+	if !ok {
 		log.Debug(errInvalidPackageType)
 		return reconcile.Result{Requeue: false}, nil
 	}
@@ -274,13 +423,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	// no packagePullSecrets are set. Settings can be modified manually
 	// after dependency creation to address this.
 	pack.SetName(xpkg.ToDNSLabel(ref.Context().RepositoryStr()))
-
-	format := packageTagFmt
-	if strings.HasPrefix(addVer, "sha256:") {
-		format = packageDigestFmt
-	}
-
-	pack.SetSource(fmt.Sprintf(format, ref.String(), addVer))
+	pack.SetSource(source(ref, res))
 
 	// NOTE(hasheddan): consider making the lock the controller of packages
 	// it creates.
@@ -292,45 +435,310 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	return reconcile.Result{Requeue: false}, nil
 }
 
-func (r *Reconciler) findDependencyVersion(ctx context.Context, dep *v1beta1.Dependency, log logging.Logger, ref name.Reference) (string, error) {
-	var addVer string
+// firstResolvable returns the first of the supplied dependency's package and
+// its alternatives that a version can be found for. If none can be resolved it
+// returns nil, and why each can't be.
+func (r *Reconciler) firstResolvable(ctx context.Context, lock *v1beta1.Lock, dep *v1beta1.Dependency, log logging.Logger) (*v1beta1.Dependency, []string) {
+	var reasons []string
+	for i, c := range dep.Candidates() {
+		cd := &v1beta1.Dependency{Package: c.Package, Type: c.Type, Constraints: c.Constraints}
+		if i == 0 {
+			cd = dep
+		}
 
-	if digest, err := conregv1.NewHash(dep.Constraints); err == nil {
-		log.Debug("package is pinned to a specific digest, skipping resolution")
-		return digest.String(), nil
+		ref, err := name.ParseReference(c.Package, name.WithDefaultRegistry(r.registry))
+		if err != nil {
+			reasons = append(reasons, fmt.Sprintf("%s: %s", c.Package, errors.Wrap(err, errInvalidDependency)))
+			continue
+		}
+
+		cs := dependencyConstraints(lock.Packages, c.Package)
+		if len(cs) == 0 {
+			cs = []Constraint{{Constraints: cd.Constraints, Channel: cd.Channel}}
+		}
+		if _, ok := recordedResolution(lock.Resolutions, cd, cs); ok {
+			return cd, nil
+		}
+
+		version, conflicting, err := r.findDependencyVersion(ctx, cs, log, ref)
+		switch {
+		case err != nil:
+			reasons = append(reasons, fmt.Sprintf("%s: %s", c.Package, err))
+		case len(conflicting) > 0:
+			reasons = append(reasons, errors.Errorf(errFmtConflict, c.Package, describe(conflicting)).Error())
+		case version == "":
+			reasons = append(reasons, errors.Errorf(errFmtNoValidVersion, c.Package, describe(cs)).Error())
+		default:
+			return cd, nil
+		}
+	}
+	return nil, reasons
+}
+
+// upgradeDependency upgrades the first installed dependency that doesn't
+// satisfy the constraints of a package with an Automatic dependency upgrade
+// policy, and can be upgraded. It upgrades the dependency to the highest
+// version that satisfies the constraints of every package that depends on it,
+// but never downgrades it. A dependency that can't be upgraded doesn't stop
+// the others from being upgraded.
+func (r *Reconciler) upgradeDependency(ctx context.Context, lock *v1beta1.Lock, log logging.Logger) (reconcile.Result, error) {
+	var failed error
+	for _, installed := range upgradable(lock.Packages) {
+		upgraded, err := r.upgrade(ctx, lock, installed, log.WithValues("dependency", installed.Source, "installed-version", installed.Version))
+		if kerrors.IsConflict(err) {
+			return reconcile.Result{Requeue: true}, nil
+		}
+		if err != nil && failed == nil {
+			failed = err
+		}
+		if upgraded {
+			return reconcile.Result{Requeue: false}, nil
+		}
+	}
+	return reconcile.Result{}, failed
+}
+
+// upgrade upgrades the supplied installed dependency, if a higher version
+// satisfies the constraints of every package that depends on it. It returns
+// true if it upgraded the dependency.
+func (r *Reconciler) upgrade(ctx context.Context, lock *v1beta1.Lock, installed v1beta1.LockPackage, log logging.Logger) (bool, error) {
+	ref, err := name.ParseReference(installed.Source, name.WithDefaultRegistry(r.registry))
+	if err != nil {
+		log.Debug(errInvalidDependency, "error", err)
+		return false, nil
+	}
+
+	cs := dependencyConstraints(lock.Packages, installed.Source)
+	version, conflicting, err := r.findDependencyVersion(ctx, cs, log, ref)
+	if err != nil {
+		r.record.Event(lock, event.Warning(reasonUpgrade, err))
+		return false, errors.Wrap(err, errUpgradeDependency)
+	}
+	if len(conflicting) > 0 {
+		err := errors.Errorf(errFmtConflict, installed.Source, describe(conflicting))
+		log.Debug(errNoValidVersion, "error", err)
+		r.record.Event(lock, event.Warning(reasonUpgrade, err))
+		return false, nil
+	}
+
+	// We only upgrade to a semantic version that is higher than the installed
+	// version. A dependency pinned to a digest by another package can't be
+	// upgraded without breaking that package's constraint.
+	if !isUpgrade(installed.Version, version) {
+		err := errors.Errorf(errFmtNoUpgrade, installed.Source, installed.Version, describe(cs), version)
+		if version == "" {
+			err = errors.Errorf(errFmtNoValidVersion, installed.Source, describe(cs))
+		}
+		log.Debug(errNoValidVersion, "error", err)
+		r.record.Event(lock, event.Warning(reasonUpgrade, err))
+		return false, nil
+	}
+
+	dep := &v1beta1.Dependency{Package: installed.Source, Type: installed.Type}
+	res, err := r.resolveDigest(ctx, dep, ref, version)
+	if err != nil {
+		log.Debug(errResolveDigest, "error", err)
+		return false, errors.Wrap(err, errResolveDigest)
+	}
+
+	updated, err := r.updateDependency(ctx, lock, installed, ref, res)
+	if err != nil {
+		log.Debug(errUpgradeDependency, "error", err)
+		return false, resource.IgnoreNotFound(err)
+	}
+	if !updated {
+		err := errors.Errorf(errFmtManualActivation, installed.Source, installed.Version, version)
+		log.Debug("Cannot upgrade dependency", "error", err)
+		r.record.Event(lock, event.Warning(reasonUpgrade, err))
+		return false, nil
+	}
+
+	log.Debug("Upgraded dependency", "version", version)
+	r.record.Event(lock, event.Normal(reasonUpgrade, fmt.Sprintf("Upgraded dependency %s from %s to %s", installed.Source, installed.Version, version)))
+	return true, nil
+}
+
+// followChannels updates the first installed dependency that was installed
+// from a channel the channel has since moved from. It returns true if it
+// updated a dependency.
+func (r *Reconciler) followChannels(ctx context.Context, lock *v1beta1.Lock, log logging.Logger) (bool, error) {
+	for _, installed := range tracking(lock.Packages) {
+		log := log.WithValues("dependency", installed.Source, "channel", installed.Version)
+
+		ref, err := name.ParseReference(installed.Source, name.WithDefaultRegistry(r.registry))
+		if err != nil {
+			log.Debug(errInvalidDependency, "error", err)
+			continue
+		}
+
+		dep := &v1beta1.Dependency{Package: installed.Source, Type: installed.Type}
+		res, err := r.resolveDigest(ctx, dep, ref, installed.Version)
+		if err != nil {
+			log.Debug(errResolveDigest, "error", err)
+			return false, errors.Wrap(err, errResolveDigest)
+		}
+		if res.Digest == installed.Digest {
+			continue
+		}
+
+		updated, err := r.updateDependency(ctx, lock, installed, ref, res)
+		if err != nil {
+			log.Debug(errFollowChannel, "error", err)
+			return false, resource.IgnoreNotFound(errors.Wrap(err, errFollowChannel))
+		}
+		if !updated {
+			log.Debug("Not updating dependency to follow its channel, because its package has a Manual revision activation policy", "digest", res.Digest)
+			continue
+		}
+
+		log.Debug("Updated dependency to follow its channel", "digest", res.Digest)
+		r.record.Event(lock, event.Normal(reasonUpgrade, fmt.Sprintf("Updated dependency %s to %s, which channel %s points to", installed.Source, res.Digest, installed.Version)))
+		return true, nil
+	}
+	return false, nil
+}
+
+// updateDependency records the supplied resolution of an installed dependency
+// in the lock, and updates the dependency's package to install it. It doesn't
+// update a dependency whose package has a Manual revision activation policy,
+// because its revisions are activated by hand, and returns false.
+func (r *Reconciler) updateDependency(ctx context.Context, lock *v1beta1.Lock, installed v1beta1.LockPackage, ref name.Reference, res v1beta1.DependencyResolution) (bool, error) {
+	// The dependency may have been installed by hand, so we find its package
+	// via the revision that added it to the lock rather than by name.
+	pack, pr, ok := newPackage(installed.Type)
+	if !ok {
+		return false, errors.New(errInvalidPackageType)
+	}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: installed.Name}, pr); err != nil {
+		return false, errors.Wrap(err, errGetRevision)
+	}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: pr.GetLabels()[v1.LabelParentPackage]}, pack); err != nil {
+		return false, errors.Wrap(err, errGetPackage)
+	}
+	if ptr.Deref(pack.GetActivationPolicy(), v1.AutomaticActivation) == v1.ManualActivation {
+		return false, nil
 	}
 
-	c, err := semver.NewConstraint(dep.Constraints)
+	lock.Resolutions = setResolution(lock.Resolutions, res)
+	if err := r.client.Update(ctx, lock); err != nil {
+		return false, errors.Wrap(err, errUpdateLock)
+	}
+
+	pack.SetSource(source(ref, res))
+	return true, errors.Wrap(r.client.Update(ctx, pack), errUpgradeDependency)
+}
+
+// newPackage returns an empty package and package revision of the supplied
+// type. It returns false if the type is unknown.
+func newPackage(t v1beta1.PackageType) (v1.Package, v1.PackageRevision, bool) {
+	switch t {
+	case v1beta1.ConfigurationPackageType:
+		return &v1.Configuration{}, &v1.ConfigurationRevision{}, true
+	case v1beta1.ProviderPackageType:
+		return &v1.Provider{}, &v1.ProviderRevision{}, true
+	case v1beta1.FunctionPackageType:
+		return &v1.Function{}, &v1.FunctionRevision{}, true
+	default:
+		return nil, nil, false
+	}
+}
+
+// findDependencyVersion finds the highest version of a dependency that
+// satisfies all of the supplied constraints. If no version does, it returns
+// the constraints that conflict.
+func (r *Reconciler) findDependencyVersion(ctx context.Context, cs []Constraint, log logging.Logger, ref name.Reference) (string, []Constraint, error) {
+	return findVersion(ctx, r.fetcher, cs, log, ref)
+}
+
+func findVersion(ctx context.Context, f xpkg.Fetcher, cs []Constraint, log logging.Logger, ref name.Reference) (string, []Constraint, error) {
+	digests, ranges, err := parseConstraints(cs)
 	if err != nil {
 		log.Debug(errInvalidConstraint, "error", err)
-		return "", errors.New(errInvalidConstraint)
+		return "", nil, errors.New(errInvalidConstraint)
+	}
+
+	// A dependency that tracks a channel is installed from the channel's tag,
+	// unless another package constrains its version. Dependents can't track
+	// different channels.
+	if chs := channels(cs); len(chs) > 0 && len(digests) == 0 && len(ranges) == 0 {
+		if len(chs) == 1 {
+			for ch := range chs {
+				return ch, nil, nil
+			}
+		}
+		var conflicting []Constraint
+		for _, c := range chs {
+			conflicting = append(conflicting, c...)
+		}
+		sort.Slice(conflicting, func(i, j int) bool { return conflicting[i].String() < conflicting[j].String() })
+		return "", conflicting, nil
+	}
+
+	// A package pinned to a digest can't be solved against version ranges
+	// without fetching it. We trust the pin, and leave checking any ranges to
+	// the package revision's dependency manager once it's installed.
+	switch len(digests) {
+	case 0:
+	case 1:
+		log.Debug("package is pinned to a specific digest, skipping resolution")
+		for d := range digests {
+			return d, nil, nil
+		}
+	default:
+		var conflicting []Constraint
+		for _, c := range digests {
+			conflicting = append(conflicting, c...)
+		}
+		sort.Slice(conflicting, func(i, j int) bool { return conflicting[i].String() < conflicting[j].String() })
+		return "", conflicting, nil
 	}
 
 	// NOTE(hasheddan): we will be unable to fetch tags for private
 	// dependencies because we do not attach any secrets. Consider copying
 	// secrets from parent dependencies.
-	tags, err := r.fetcher.Tags(ctx, ref)
+	tags, err := f.Tags(ctx, ref)
 	if err != nil {
 		log.Debug(errFetchTags, "error", err)
-		return "", errors.New(errFetchTags)
+		return "", nil, errors.New(errFetchTags)
 	}
 
-	vs := []*semver.Version{}
-	for _, r := range tags {
-		v, err := semver.NewVersion(r)
-		if err != nil {
-			// We skip any tags that are not valid semantic versions.
-			continue
-		}
-		vs = append(vs, v)
+	v, conflicting := solve(ranges, tags)
+	return v, conflicting, nil
+}
+
+// resolveDigest resolves the supplied version of a dependency to a digest.
+// The version may already be a digest.
+func (r *Reconciler) resolveDigest(ctx context.Context, dep *v1beta1.Dependency, ref name.Reference, version string) (v1beta1.DependencyResolution, error) {
+	res := v1beta1.DependencyResolution{Package: dep.Identifier(), Type: dep.Type}
+	if strings.HasPrefix(version, "sha256:") {
+		res.Digest = version
+		return res, nil
 	}
 
-	sort.Sort(semver.Collection(vs))
-	for _, v := range vs {
-		if c.Check(v) {
-			addVer = v.Original()
-		}
+	// Like tags, we can't resolve the digests of private dependencies
+	// because we don't attach any secrets.
+	d, err := r.fetcher.Head(ctx, ref.Context().Tag(version))
+	if err != nil {
+		return res, err
 	}
+	if d == nil {
+		return res, errors.New(errResolveDigest)
+	}
+	res.Version = version
+	res.Digest = d.Digest.String()
+	return res, nil
+}
 
-	return addVer, nil
+// source returns the source a dependency with the supplied resolution should
+// be installed from. A dependency that was resolved from a version is pinned
+// to its digest, but keeps its tag so that its version is still known.
+func source(ref name.Reference, res v1beta1.DependencyResolution) string {
+	if res.Version == "" {
+		return fmt.Sprintf(packageDigestFmt, ref.String(), res.Digest)
+	}
+	h, err := conregv1.NewHash(res.Digest)
+	if err != nil {
+		return fmt.Sprintf(packageTagFmt, ref.String(), res.Version)
+	}
+	return xpkg.PinnedSource(fmt.Sprintf(packageTagFmt, ref.String(), res.Version), h)
 }
@@ -19,9 +19,12 @@ package resolver
 import (
 	"context"
 	"io"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	conregv1 "github.com/google/go-containerregistry/pkg/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -36,6 +39,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
 	"github.com/crossplane/crossplane/internal/dag"
 	fakedag "github.com/crossplane/crossplane/internal/dag/fake"
@@ -44,6 +48,7 @@ import (
 
 func TestReconcile(t *testing.T) {
 	errBoom := errors.New("boom")
+	digest := conregv1.Hash{Algorithm: "sha256", Hex: "ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904"}
 	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
 
 	type args struct {
@@ -215,6 +220,45 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, errSortDAG),
 			},
 		},
+		"DependencyCycle": {
+			reason: "We should not return an error or requeue if the DAG contains a cycle.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							// Populate package list so we attempt
+							// reconciliation. This is overridden by the mock
+							// DAG.
+							l := o.(*v1beta1.Lock)
+							l.Packages = append(l.Packages, v1beta1.LockPackage{
+								Name:    "cool-package",
+								Type:    v1beta1.ProviderPackageType,
+								Source:  "cool-repo/cool-image",
+								Version: "v0.0.1",
+							})
+							return nil
+						}),
+						MockUpdate: test.NewMockUpdateFn(nil),
+					},
+				},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewDagFn(func() dag.DAG {
+						return &fakedag.MockDag{
+							MockInit: func(_ []dag.Node) ([]dag.Node, error) {
+								return nil, nil
+							},
+							MockSort: func() ([]string, error) {
+								return nil, &dag.CycleError{Cycle: []string{"cool-repo/cool-image", "cool-repo/cool-image"}}
+							},
+						}
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
 		"SuccessfulNoMissing": {
 			reason: "We should not return error and not requeue if no missing dependencies.",
 			args: args{
@@ -384,6 +428,54 @@ func TestReconcile(t *testing.T) {
 				err: errors.New(errInvalidDependency),
 			},
 		},
+		"SkipDependencyResolution": {
+			reason: "We should not install missing dependencies if the package manager skips dependency resolution.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							// Populate package list so we attempt
+							// reconciliation. This is overridden by the mock
+							// DAG.
+							l := o.(*v1beta1.Lock)
+							l.Packages = append(l.Packages, v1beta1.LockPackage{
+								Name:    "cool-package",
+								Type:    v1beta1.ProviderPackageType,
+								Source:  "cool-repo/cool-image",
+								Version: "v0.0.1",
+							})
+							return nil
+						}),
+						MockUpdate: test.NewMockUpdateFn(nil),
+					},
+				},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewDagFn(func() dag.DAG {
+						return &fakedag.MockDag{
+							MockInit: func(_ []dag.Node) ([]dag.Node, error) {
+								return []dag.Node{
+									&v1beta1.Dependency{
+										Package:     "hasheddan/config-nop-b",
+										Constraints: "*",
+									},
+								}, nil
+							},
+							MockSort: func() ([]string, error) {
+								return nil, nil
+							},
+						}
+					}),
+					WithFetcher(&fakexpkg.MockFetcher{
+						MockTags: fakexpkg.NewMockTagsFn(nil, errBoom),
+					}),
+					WithSkipDependencyResolution(true),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
 		"ErrorNoValidVersion": {
 			reason: "We should not requeue if valid version does not exist for dependency.",
 			args: args{
@@ -431,6 +523,70 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: false},
 			},
 		},
+		"ConflictingConstraints": {
+			reason: "We should not create a dependency if no version satisfies the constraints of every package that depends on it.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							l := o.(*v1beta1.Lock)
+							l.Packages = append(l.Packages,
+								v1beta1.LockPackage{
+									Name:    "config-a",
+									Type:    v1beta1.ConfigurationPackageType,
+									Source:  "hasheddan/config-nop-a",
+									Version: "v0.0.1",
+									Dependencies: []v1beta1.Dependency{{
+										Package:     "hasheddan/provider-nop-c",
+										Type:        v1beta1.ProviderPackageType,
+										Constraints: ">=v1.0.0",
+									}},
+								},
+								v1beta1.LockPackage{
+									Name:    "config-b",
+									Type:    v1beta1.ConfigurationPackageType,
+									Source:  "hasheddan/config-nop-b",
+									Version: "v0.0.1",
+									Dependencies: []v1beta1.Dependency{{
+										Package:     "hasheddan/provider-nop-c",
+										Type:        v1beta1.ProviderPackageType,
+										Constraints: "<v1.0.0",
+									}},
+								},
+							)
+							return nil
+						}),
+						MockUpdate: test.NewMockUpdateFn(nil),
+						MockCreate: test.NewMockCreateFn(errBoom),
+					},
+				},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewDagFn(func() dag.DAG {
+						return &fakedag.MockDag{
+							MockInit: func(_ []dag.Node) ([]dag.Node, error) {
+								return []dag.Node{
+									&v1beta1.Dependency{
+										Package:     "hasheddan/provider-nop-c",
+										Type:        v1beta1.ProviderPackageType,
+										Constraints: ">=v1.0.0",
+									},
+								}, nil
+							},
+							MockSort: func() ([]string, error) {
+								return nil, nil
+							},
+						}
+					}),
+					WithFetcher(&fakexpkg.MockFetcher{
+						MockTags: fakexpkg.NewMockTagsFn([]string{"v0.2.0", "v0.3.0", "v1.0.0"}, nil),
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
 		"ErrorCreateMissingDependency": {
 			reason: "We should return an error if unable to create missing dependency.",
 			args: args{
@@ -473,6 +629,7 @@ func TestReconcile(t *testing.T) {
 					}),
 					WithFetcher(&fakexpkg.MockFetcher{
 						MockTags: fakexpkg.NewMockTagsFn([]string{"v0.2.0", "v0.3.0", "v1.0.0", "v1.2.0"}, nil),
+						MockHead: fakexpkg.NewMockHeadFn(&conregv1.Descriptor{Digest: digest}, nil),
 					}),
 				},
 			},
@@ -522,6 +679,7 @@ func TestReconcile(t *testing.T) {
 					}),
 					WithFetcher(&fakexpkg.MockFetcher{
 						MockTags: fakexpkg.NewMockTagsFn([]string{"v0.2.0", "v0.3.0", "v1.0.0", "v1.2.0"}, nil),
+						MockHead: fakexpkg.NewMockHeadFn(&conregv1.Descriptor{Digest: digest}, nil),
 					}),
 				},
 			},
@@ -547,7 +705,13 @@ func TestReconcile(t *testing.T) {
 							})
 							return nil
 						}),
-						MockCreate: test.NewMockCreateFn(nil),
+						MockCreate: test.NewMockCreateFn(nil, func(o client.Object) error {
+							want := "hasheddan/config-nop-c:v1.2.0@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904"
+							if diff := cmp.Diff(want, o.(*v1.Configuration).GetSource()); diff != "" {
+								t.Errorf("Create(...): -want source, +got source:\n%s", diff)
+							}
+							return nil
+						}),
 						MockUpdate: test.NewMockUpdateFn(nil),
 					},
 				},
@@ -571,6 +735,7 @@ func TestReconcile(t *testing.T) {
 					}),
 					WithFetcher(&fakexpkg.MockFetcher{
 						MockTags: fakexpkg.NewMockTagsFn([]string{"v0.2.0", "v0.3.0", "v1.0.0", "v1.2.0"}, nil),
+						MockHead: fakexpkg.NewMockHeadFn(&conregv1.Descriptor{Digest: digest}, nil),
 					}),
 				},
 			},
@@ -578,8 +743,8 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: false},
 			},
 		},
-		"SuccessfulCreateMissingDependencyWithDigest": {
-			reason: "We should not requeue if able to create missing dependency with digest.",
+		"SuccessfulCreateMissingAlternative": {
+			reason: "We should create the first alternative that can be resolved if a missing dependency can't be.",
 			args: args{
 				mgr: &fake.Manager{
 					Client: &test.MockClient{
@@ -596,7 +761,13 @@ func TestReconcile(t *testing.T) {
 							})
 							return nil
 						}),
-						MockCreate: test.NewMockCreateFn(nil),
+						MockCreate: test.NewMockCreateFn(nil, func(o client.Object) error {
+							want := "hasheddan/config-nop-c:v1.2.0@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904"
+							if diff := cmp.Diff(want, o.(*v1.Configuration).GetSource()); diff != "" {
+								t.Errorf("Create(...): -want source, +got source:\n%s", diff)
+							}
+							return nil
+						}),
 						MockUpdate: test.NewMockUpdateFn(nil),
 					},
 				},
@@ -607,9 +778,69 @@ func TestReconcile(t *testing.T) {
 							MockInit: func(_ []dag.Node) ([]dag.Node, error) {
 								return []dag.Node{
 									&v1beta1.Dependency{
-										Package:     "hasheddan/provider-nop-c",
-										Constraints: "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904",
-										Type:        v1beta1.ProviderPackageType,
+										Package:     "hasheddan/config-nop-b",
+										Constraints: ">v2.0.0",
+										Type:        v1beta1.ConfigurationPackageType,
+										Alternatives: []v1beta1.DependencyAlternative{{
+											Package:     "hasheddan/config-nop-c",
+											Constraints: ">v1.0.0",
+											Type:        v1beta1.ConfigurationPackageType,
+										}},
+									},
+								}, nil
+							},
+							MockSort: func() ([]string, error) {
+								return nil, nil
+							},
+						}
+					}),
+					WithFetcher(&fakexpkg.MockFetcher{
+						MockTags: fakexpkg.NewMockTagsFn([]string{"v0.2.0", "v0.3.0", "v1.0.0", "v1.2.0"}, nil),
+						MockHead: fakexpkg.NewMockHeadFn(&conregv1.Descriptor{Digest: digest}, nil),
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"ErrorNoAlternativeResolvable": {
+			reason: "We should not requeue, or create a dependency, if neither a missing dependency nor any of its alternatives can be resolved.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							// Populate package list so we attempt
+							// reconciliation. This is overridden by the mock
+							// DAG.
+							l := o.(*v1beta1.Lock)
+							l.Packages = append(l.Packages, v1beta1.LockPackage{
+								Name:    "cool-package",
+								Type:    v1beta1.ProviderPackageType,
+								Source:  "cool-repo/cool-image",
+								Version: "v0.0.1",
+							})
+							return nil
+						}),
+						MockCreate: test.NewMockCreateFn(errBoom),
+						MockUpdate: test.NewMockUpdateFn(nil),
+					},
+				},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewDagFn(func() dag.DAG {
+						return &fakedag.MockDag{
+							MockInit: func(_ []dag.Node) ([]dag.Node, error) {
+								return []dag.Node{
+									&v1beta1.Dependency{
+										Package:     "hasheddan/config-nop-b",
+										Constraints: ">v2.0.0",
+										Type:        v1beta1.ConfigurationPackageType,
+										Alternatives: []v1beta1.DependencyAlternative{{
+											Package:     "hasheddan/config-nop-c",
+											Constraints: ">v3.0.0",
+											Type:        v1beta1.ConfigurationPackageType,
+										}},
 									},
 								}, nil
 							},
@@ -618,12 +849,715 @@ func TestReconcile(t *testing.T) {
 							},
 						}
 					}),
+					WithFetcher(&fakexpkg.MockFetcher{
+						MockTags: fakexpkg.NewMockTagsFn([]string{"v0.2.0", "v0.3.0", "v1.0.0", "v1.2.0"}, nil),
+					}),
 				},
 			},
 			want: want{
 				r: reconcile.Result{Requeue: false},
 			},
 		},
+		"ErrorResolveDigest": {
+			reason: "We should return an error if we can't resolve the version of a missing dependency to a digest.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							l := o.(*v1beta1.Lock)
+							l.Packages = append(l.Packages, v1beta1.LockPackage{
+								Name:    "cool-package",
+								Type:    v1beta1.ProviderPackageType,
+								Source:  "cool-repo/cool-image",
+								Version: "v0.0.1",
+							})
+							return nil
+						}),
+						MockUpdate: test.NewMockUpdateFn(nil),
+					},
+				},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewDagFn(func() dag.DAG {
+						return &fakedag.MockDag{
+							MockInit: func(_ []dag.Node) ([]dag.Node, error) {
+								return []dag.Node{
+									&v1beta1.Dependency{
+										Package:     "hasheddan/config-nop-c",
+										Constraints: ">v1.0.0",
+										Type:        v1beta1.ConfigurationPackageType,
+									},
+								}, nil
+							},
+							MockSort: func() ([]string, error) {
+								return nil, nil
+							},
+						}
+					}),
+					WithFetcher(&fakexpkg.MockFetcher{
+						MockTags: fakexpkg.NewMockTagsFn([]string{"v1.0.0", "v1.2.0"}, nil),
+						MockHead: fakexpkg.NewMockHeadFn(nil, errBoom),
+					}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errResolveDigest),
+			},
+		},
+		"SuccessfulCreateMissingDependencyFromResolution": {
+			reason: "We should create a missing dependency at its recorded resolution if it satisfies the dependency's constraints.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							l := o.(*v1beta1.Lock)
+							l.Packages = append(l.Packages, v1beta1.LockPackage{
+								Name:    "cool-package",
+								Type:    v1beta1.ConfigurationPackageType,
+								Source:  "cool-repo/cool-image",
+								Version: "v0.0.1",
+								Dependencies: []v1beta1.Dependency{{
+									Package:     "hasheddan/config-nop-c",
+									Constraints: ">v1.0.0",
+									Type:        v1beta1.ConfigurationPackageType,
+								}},
+							})
+							l.Resolutions = []v1beta1.DependencyResolution{{
+								Package: "hasheddan/config-nop-c",
+								Type:    v1beta1.ConfigurationPackageType,
+								Version: "v1.1.0",
+								Digest:  digest.String(),
+							}}
+							return nil
+						}),
+						MockCreate: test.NewMockCreateFn(nil, func(o client.Object) error {
+							want := "hasheddan/config-nop-c:v1.1.0@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904"
+							if diff := cmp.Diff(want, o.(*v1.Configuration).GetSource()); diff != "" {
+								t.Errorf("Create(...): -want source, +got source:\n%s", diff)
+							}
+							return nil
+						}),
+						MockUpdate: test.NewMockUpdateFn(nil),
+					},
+				},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewDagFn(func() dag.DAG {
+						return &fakedag.MockDag{
+							MockInit: func(_ []dag.Node) ([]dag.Node, error) {
+								return []dag.Node{
+									&v1beta1.Dependency{
+										Package:     "hasheddan/config-nop-c",
+										Constraints: ">v1.0.0",
+										Type:        v1beta1.ConfigurationPackageType,
+									},
+								}, nil
+							},
+							MockSort: func() ([]string, error) {
+								return nil, nil
+							},
+						}
+					}),
+					WithFetcher(&fakexpkg.MockFetcher{
+						MockTags: fakexpkg.NewMockTagsFn(nil, errBoom),
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulCreateMissingDependencyWithDigest": {
+			reason: "We should not requeue if able to create missing dependency with digest.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							// Populate package list so we attempt
+							// reconciliation. This is overridden by the mock
+							// DAG.
+							l := o.(*v1beta1.Lock)
+							l.Packages = append(l.Packages, v1beta1.LockPackage{
+								Name:    "cool-package",
+								Type:    v1beta1.ProviderPackageType,
+								Source:  "cool-repo/cool-image",
+								Version: "v0.0.1",
+							})
+							return nil
+						}),
+						MockCreate: test.NewMockCreateFn(nil),
+						MockUpdate: test.NewMockUpdateFn(nil),
+					},
+				},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewDagFn(func() dag.DAG {
+						return &fakedag.MockDag{
+							MockInit: func(_ []dag.Node) ([]dag.Node, error) {
+								return []dag.Node{
+									&v1beta1.Dependency{
+										Package:     "hasheddan/provider-nop-c",
+										Constraints: "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904",
+										Type:        v1beta1.ProviderPackageType,
+									},
+								}, nil
+							},
+							MockSort: func() ([]string, error) {
+								return nil, nil
+							},
+						}
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulUpgradeDependency": {
+			reason: "We should upgrade an installed dependency that doesn't satisfy the constraints of a package with an Automatic dependency upgrade policy.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							switch o := o.(type) {
+							case *v1beta1.Lock:
+								o.Packages = []v1beta1.LockPackage{
+									{
+										Name:                    "cool-config-abc123",
+										Type:                    v1beta1.ConfigurationPackageType,
+										Source:                  "cool-repo/cool-config",
+										Version:                 "v1.0.0",
+										DependencyUpgradePolicy: v1beta1.DependencyUpgradePolicy(v1.AutomaticDependencyUpgrade),
+										Dependencies: []v1beta1.Dependency{{
+											Package:     "cool-repo/cool-provider",
+											Constraints: ">=v0.20.0",
+											Type:        v1beta1.ProviderPackageType,
+										}},
+									},
+									{
+										Name:    "cool-provider-abc123",
+										Type:    v1beta1.ProviderPackageType,
+										Source:  "cool-repo/cool-provider",
+										Version: "v0.18.0",
+									},
+								}
+							case *v1.ProviderRevision:
+								o.SetLabels(map[string]string{v1.LabelParentPackage: "cool-provider"})
+							case *v1.Provider:
+								o.SetSource("cool-repo/cool-provider:v0.18.0")
+							}
+							return nil
+						}),
+						MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+							p, ok := o.(*v1.Provider)
+							if !ok {
+								return nil
+							}
+							want := "cool-repo/cool-provider:v0.21.0@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904"
+							if diff := cmp.Diff(want, p.GetSource()); diff != "" {
+								t.Errorf("Update(...): -want source, +got source:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+				},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewDagFn(func() dag.DAG {
+						return &fakedag.MockDag{
+							MockInit: func(_ []dag.Node) ([]dag.Node, error) {
+								return nil, nil
+							},
+							MockSort: func() ([]string, error) {
+								return nil, nil
+							},
+						}
+					}),
+					WithFetcher(&fakexpkg.MockFetcher{
+						MockTags: fakexpkg.NewMockTagsFn([]string{"v0.18.0", "v0.20.0", "v0.21.0"}, nil),
+						MockHead: fakexpkg.NewMockHeadFn(&conregv1.Descriptor{Digest: digest}, nil),
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"NoDowngradeDependency": {
+			reason: "We should not downgrade an installed dependency, even if a package with an Automatic dependency upgrade policy requires it.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							switch o := o.(type) {
+							case *v1beta1.Lock:
+								o.Packages = []v1beta1.LockPackage{
+									{
+										Name:                    "cool-config-abc123",
+										Type:                    v1beta1.ConfigurationPackageType,
+										Source:                  "cool-repo/cool-config",
+										Version:                 "v1.0.0",
+										DependencyUpgradePolicy: v1beta1.DependencyUpgradePolicy(v1.AutomaticDependencyUpgrade),
+										Dependencies: []v1beta1.Dependency{{
+											Package:     "cool-repo/cool-provider",
+											Constraints: "<v0.18.0",
+											Type:        v1beta1.ProviderPackageType,
+										}},
+									},
+									{
+										Name:    "cool-provider-abc123",
+										Type:    v1beta1.ProviderPackageType,
+										Source:  "cool-repo/cool-provider",
+										Version: "v0.18.0",
+									},
+								}
+							case *v1.ProviderRevision:
+								o.SetLabels(map[string]string{v1.LabelParentPackage: "cool-provider"})
+							case *v1.Provider:
+								o.SetSource("cool-repo/cool-provider:v0.18.0")
+							}
+							return nil
+						}),
+						MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+							if _, ok := o.(*v1.Provider); ok {
+								t.Errorf("Update(...): unexpected update of dependency package")
+							}
+							return nil
+						}),
+					},
+				},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewDagFn(func() dag.DAG {
+						return &fakedag.MockDag{
+							MockInit: func(_ []dag.Node) ([]dag.Node, error) {
+								return nil, nil
+							},
+							MockSort: func() ([]string, error) {
+								return nil, nil
+							},
+						}
+					}),
+					WithFetcher(&fakexpkg.MockFetcher{
+						MockTags: fakexpkg.NewMockTagsFn([]string{"v0.17.0", "v0.18.0"}, nil),
+						MockHead: fakexpkg.NewMockHeadFn(&conregv1.Descriptor{Digest: digest}, nil),
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"ErrorUpgradeDependency": {
+			reason: "We should return an error if we can't update an installed dependency's package.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							switch o := o.(type) {
+							case *v1beta1.Lock:
+								o.Packages = []v1beta1.LockPackage{
+									{
+										Name:                    "cool-config-abc123",
+										Type:                    v1beta1.ConfigurationPackageType,
+										Source:                  "cool-repo/cool-config",
+										Version:                 "v1.0.0",
+										DependencyUpgradePolicy: v1beta1.DependencyUpgradePolicy(v1.AutomaticDependencyUpgrade),
+										Dependencies: []v1beta1.Dependency{{
+											Package:     "cool-repo/cool-provider",
+											Constraints: ">=v0.20.0",
+											Type:        v1beta1.ProviderPackageType,
+										}},
+									},
+									{
+										Name:    "cool-provider-abc123",
+										Type:    v1beta1.ProviderPackageType,
+										Source:  "cool-repo/cool-provider",
+										Version: "v0.18.0",
+									},
+								}
+							case *v1.ProviderRevision:
+								o.SetLabels(map[string]string{v1.LabelParentPackage: "cool-provider"})
+							case *v1.Provider:
+								o.SetSource("cool-repo/cool-provider:v0.18.0")
+							}
+							return nil
+						}),
+						MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+							if _, ok := o.(*v1.Provider); ok {
+								return errBoom
+							}
+							return nil
+						}),
+					},
+				},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewDagFn(func() dag.DAG {
+						return &fakedag.MockDag{
+							MockInit: func(_ []dag.Node) ([]dag.Node, error) {
+								return nil, nil
+							},
+							MockSort: func() ([]string, error) {
+								return nil, nil
+							},
+						}
+					}),
+					WithFetcher(&fakexpkg.MockFetcher{
+						MockTags: fakexpkg.NewMockTagsFn([]string{"v0.18.0", "v0.20.0", "v0.21.0"}, nil),
+						MockHead: fakexpkg.NewMockHeadFn(&conregv1.Descriptor{Digest: digest}, nil),
+					}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errUpgradeDependency),
+			},
+		},
+		"UpgradeNextDependency": {
+			reason: "We should upgrade the next installed dependency that needs upgrading if an earlier one can't be upgraded.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: func(_ context.Context, key client.ObjectKey, o client.Object) error {
+							switch o := o.(type) {
+							case *v1beta1.Lock:
+								o.Packages = []v1beta1.LockPackage{
+									{
+										Name:                    "cool-config-abc123",
+										Type:                    v1beta1.ConfigurationPackageType,
+										Source:                  "cool-repo/cool-config",
+										Version:                 "v1.0.0",
+										DependencyUpgradePolicy: v1beta1.DependencyUpgradePolicy(v1.AutomaticDependencyUpgrade),
+										Dependencies: []v1beta1.Dependency{
+											{
+												Package:     "cool-repo/cool-provider",
+												Constraints: ">=v0.20.0",
+												Type:        v1beta1.ProviderPackageType,
+											},
+											{
+												Package:     "cool-repo/other-provider",
+												Constraints: ">=v2.0.0",
+												Type:        v1beta1.ProviderPackageType,
+											},
+										},
+									},
+									{
+										Name:    "old-config-abc123",
+										Type:    v1beta1.ConfigurationPackageType,
+										Source:  "cool-repo/old-config",
+										Version: "v1.0.0",
+										Dependencies: []v1beta1.Dependency{{
+											Package:     "cool-repo/cool-provider",
+											Constraints: "<v0.19.0",
+											Type:        v1beta1.ProviderPackageType,
+										}},
+									},
+									{
+										Name:    "cool-provider-abc123",
+										Type:    v1beta1.ProviderPackageType,
+										Source:  "cool-repo/cool-provider",
+										Version: "v0.18.0",
+									},
+									{
+										Name:    "other-provider-abc123",
+										Type:    v1beta1.ProviderPackageType,
+										Source:  "cool-repo/other-provider",
+										Version: "v1.0.0",
+									},
+								}
+							case *v1.ProviderRevision:
+								o.SetLabels(map[string]string{v1.LabelParentPackage: strings.TrimSuffix(key.Name, "-abc123")})
+							case *v1.Provider:
+								o.SetName(key.Name)
+							}
+							return nil
+						},
+						MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+							p, ok := o.(*v1.Provider)
+							if !ok {
+								return nil
+							}
+							want := "cool-repo/other-provider:v2.1.0@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904"
+							if diff := cmp.Diff(want, p.GetSource()); diff != "" {
+								t.Errorf("Update(...): -want source, +got source:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+				},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewDagFn(func() dag.DAG {
+						return &fakedag.MockDag{
+							MockInit: func(_ []dag.Node) ([]dag.Node, error) {
+								return nil, nil
+							},
+							MockSort: func() ([]string, error) {
+								return nil, nil
+							},
+						}
+					}),
+					WithFetcher(&fakexpkg.MockFetcher{
+						MockTags: fakexpkg.NewMockTagsFn([]string{"v0.18.0", "v0.20.0", "v1.0.0", "v2.1.0"}, nil),
+						MockHead: fakexpkg.NewMockHeadFn(&conregv1.Descriptor{Digest: digest}, nil),
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"ManualActivationDependency": {
+			reason: "We should not upgrade an installed dependency whose package has a Manual revision activation policy.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							switch o := o.(type) {
+							case *v1beta1.Lock:
+								o.Packages = []v1beta1.LockPackage{
+									{
+										Name:                    "cool-config-abc123",
+										Type:                    v1beta1.ConfigurationPackageType,
+										Source:                  "cool-repo/cool-config",
+										Version:                 "v1.0.0",
+										DependencyUpgradePolicy: v1beta1.DependencyUpgradePolicy(v1.AutomaticDependencyUpgrade),
+										Dependencies: []v1beta1.Dependency{{
+											Package:     "cool-repo/cool-provider",
+											Constraints: ">=v0.20.0",
+											Type:        v1beta1.ProviderPackageType,
+										}},
+									},
+									{
+										Name:    "cool-provider-abc123",
+										Type:    v1beta1.ProviderPackageType,
+										Source:  "cool-repo/cool-provider",
+										Version: "v0.18.0",
+									},
+								}
+							case *v1.ProviderRevision:
+								o.SetLabels(map[string]string{v1.LabelParentPackage: "cool-provider"})
+							case *v1.Provider:
+								o.SetSource("cool-repo/cool-provider:v0.18.0")
+								o.SetActivationPolicy(&v1.ManualActivation)
+							}
+							return nil
+						}),
+						MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+							if _, ok := o.(*v1.Provider); ok {
+								t.Errorf("Update(...): unexpected update of dependency package")
+							}
+							if l, ok := o.(*v1beta1.Lock); ok && len(l.Resolutions) > 0 {
+								t.Errorf("Update(...): unexpected resolution recorded in lock")
+							}
+							return nil
+						}),
+					},
+				},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewDagFn(func() dag.DAG {
+						return &fakedag.MockDag{
+							MockInit: func(_ []dag.Node) ([]dag.Node, error) {
+								return nil, nil
+							},
+							MockSort: func() ([]string, error) {
+								return nil, nil
+							},
+						}
+					}),
+					WithFetcher(&fakexpkg.MockFetcher{
+						MockTags: fakexpkg.NewMockTagsFn([]string{"v0.18.0", "v0.20.0", "v0.21.0"}, nil),
+						MockHead: fakexpkg.NewMockHeadFn(&conregv1.Descriptor{Digest: digest}, nil),
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulCreateMissingDependencyFromChannel": {
+			reason: "We should create a missing dependency that tracks a channel from the digest the channel points to.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							l := o.(*v1beta1.Lock)
+							l.Packages = append(l.Packages, v1beta1.LockPackage{
+								Name:    "cool-package",
+								Type:    v1beta1.ProviderPackageType,
+								Source:  "cool-repo/cool-image",
+								Version: "v0.0.1",
+							})
+							return nil
+						}),
+						MockCreate: test.NewMockCreateFn(nil, func(o client.Object) error {
+							want := "hasheddan/config-nop-c:stable@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904"
+							if diff := cmp.Diff(want, o.(*v1.Configuration).GetSource()); diff != "" {
+								t.Errorf("Create(...): -want source, +got source:\n%s", diff)
+							}
+							return nil
+						}),
+						MockUpdate: test.NewMockUpdateFn(nil),
+					},
+				},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewDagFn(func() dag.DAG {
+						return &fakedag.MockDag{
+							MockInit: func(_ []dag.Node) ([]dag.Node, error) {
+								return []dag.Node{
+									&v1beta1.Dependency{
+										Package: "hasheddan/config-nop-c",
+										Channel: "stable",
+										Type:    v1beta1.ConfigurationPackageType,
+									},
+								}, nil
+							},
+							MockSort: func() ([]string, error) {
+								return nil, nil
+							},
+						}
+					}),
+					WithFetcher(&fakexpkg.MockFetcher{
+						MockHead: fakexpkg.NewMockHeadFn(&conregv1.Descriptor{Digest: digest}, nil),
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulFollowChannel": {
+			reason: "We should update an installed dependency that tracks a channel when the channel moves.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							switch o := o.(type) {
+							case *v1beta1.Lock:
+								o.Packages = []v1beta1.LockPackage{
+									{
+										Name:    "cool-config-abc123",
+										Type:    v1beta1.ConfigurationPackageType,
+										Source:  "cool-repo/cool-config",
+										Version: "v1.0.0",
+										Dependencies: []v1beta1.Dependency{{
+											Package: "cool-repo/cool-provider",
+											Channel: "stable",
+											Type:    v1beta1.ProviderPackageType,
+										}},
+									},
+									{
+										Name:    "cool-provider-abc123",
+										Type:    v1beta1.ProviderPackageType,
+										Source:  "cool-repo/cool-provider",
+										Version: "stable",
+										Digest:  "sha256:0000000000000000000000000000000000000000000000000000000000000000",
+									},
+								}
+							case *v1.ProviderRevision:
+								o.SetLabels(map[string]string{v1.LabelParentPackage: "cool-provider"})
+							case *v1.Provider:
+								o.SetSource("cool-repo/cool-provider:stable@sha256:0000000000000000000000000000000000000000000000000000000000000000")
+							}
+							return nil
+						}),
+						MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+							p, ok := o.(*v1.Provider)
+							if !ok {
+								return nil
+							}
+							want := "cool-repo/cool-provider:stable@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904"
+							if diff := cmp.Diff(want, p.GetSource()); diff != "" {
+								t.Errorf("Update(...): -want source, +got source:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+				},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewDagFn(func() dag.DAG {
+						return &fakedag.MockDag{
+							MockInit: func(_ []dag.Node) ([]dag.Node, error) {
+								return nil, nil
+							},
+							MockSort: func() ([]string, error) {
+								return nil, nil
+							},
+						}
+					}),
+					WithFetcher(&fakexpkg.MockFetcher{
+						MockHead: fakexpkg.NewMockHeadFn(&conregv1.Descriptor{Digest: digest}, nil),
+					}),
+					WithChannelPollInterval(1 * time.Hour),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"ChannelUnchanged": {
+			reason: "We should check again later whether a channel an installed dependency tracks has moved.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							if l, ok := o.(*v1beta1.Lock); ok {
+								l.Packages = []v1beta1.LockPackage{
+									{
+										Name:    "cool-config-abc123",
+										Type:    v1beta1.ConfigurationPackageType,
+										Source:  "cool-repo/cool-config",
+										Version: "v1.0.0",
+										Dependencies: []v1beta1.Dependency{{
+											Package: "cool-repo/cool-provider",
+											Channel: "stable",
+											Type:    v1beta1.ProviderPackageType,
+										}},
+									},
+									{
+										Name:    "cool-provider-abc123",
+										Type:    v1beta1.ProviderPackageType,
+										Source:  "cool-repo/cool-provider",
+										Version: "stable",
+										Digest:  digest.String(),
+									},
+								}
+							}
+							return nil
+						}),
+						MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+							if _, ok := o.(*v1.Provider); ok {
+								t.Errorf("Update(...): unexpected update of a dependency whose channel hasn't moved")
+							}
+							return nil
+						}),
+					},
+				},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewDagFn(func() dag.DAG {
+						return &fakedag.MockDag{
+							MockInit: func(_ []dag.Node) ([]dag.Node, error) {
+								return nil, nil
+							},
+							MockSort: func() ([]string, error) {
+								return nil, nil
+							},
+						}
+					}),
+					WithFetcher(&fakexpkg.MockFetcher{
+						MockHead: fakexpkg.NewMockHeadFn(&conregv1.Descriptor{Digest: digest}, nil),
+					}),
+					WithChannelPollInterval(1 * time.Hour),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: 1 * time.Hour},
+			},
+		},
 	}
 
 	for name, tc := range cases {
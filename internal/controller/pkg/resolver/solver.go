@@ -0,0 +1,409 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resolver
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	conregv1 "github.com/google/go-containerregistry/pkg/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/internal/dag"
+)
+
+// A Constraint is a version constraint that a package places on one of its
+// dependencies.
+type Constraint struct {
+	// Dependent is the source of the package that declared the constraint.
+	// It's empty if unknown.
+	Dependent string
+
+	// Constraints is a semantic version range or a digest.
+	Constraints string
+
+	// Channel is the tag the dependent tracks. Constraints is ignored if
+	// it's set.
+	Channel string
+}
+
+// String returns a human readable description of the constraint.
+func (c Constraint) String() string {
+	if c.Channel != "" {
+		if c.Dependent == "" {
+			return fmt.Sprintf("channel %s", c.Channel)
+		}
+		return fmt.Sprintf("%s tracks channel %s", c.Dependent, c.Channel)
+	}
+	if c.Dependent == "" {
+		return strings.TrimSpace(c.Constraints)
+	}
+	return fmt.Sprintf("%s requires %s", c.Dependent, strings.TrimSpace(c.Constraints))
+}
+
+// dependencyConstraints returns the constraints that the supplied packages
+// place on the supplied dependency.
+func dependencyConstraints(pkgs []v1beta1.LockPackage, dependency string) []Constraint {
+	var cs []Constraint
+	for _, p := range pkgs {
+		for _, d := range p.Dependencies {
+			if d.Package != dependency {
+				continue
+			}
+			cs = append(cs, Constraint{Dependent: p.Source, Constraints: d.Constraints, Channel: d.Channel})
+		}
+	}
+	return cs
+}
+
+// A rangeConstraint is a Constraint that is a semantic version range.
+type rangeConstraint struct {
+	Constraint
+	semver *semver.Constraints
+}
+
+// parseConstraints splits the supplied constraints into digests and
+// semantic version ranges. Digests are deduplicated. Channels are omitted.
+func parseConstraints(cs []Constraint) (map[string][]Constraint, []rangeConstraint, error) {
+	digests := map[string][]Constraint{}
+	var ranges []rangeConstraint
+	for _, c := range cs {
+		if c.Channel != "" {
+			continue
+		}
+		if d, err := conregv1.NewHash(c.Constraints); err == nil {
+			digests[d.String()] = append(digests[d.String()], c)
+			continue
+		}
+		sc, err := semver.NewConstraint(c.Constraints)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, errFmtInvalidConstraint, c.String())
+		}
+		ranges = append(ranges, rangeConstraint{Constraint: c, semver: sc})
+	}
+	return digests, ranges, nil
+}
+
+// channels returns the channels the supplied constraints track. Channels are
+// deduplicated.
+func channels(cs []Constraint) map[string][]Constraint {
+	chs := map[string][]Constraint{}
+	for _, c := range cs {
+		if c.Channel != "" {
+			chs[c.Channel] = append(chs[c.Channel], c)
+		}
+	}
+	return chs
+}
+
+// solve returns the highest of the supplied tags that is a semantic version
+// satisfying every supplied range. If none does it returns the ranges that
+// conflict, if any. It returns an empty version and no conflicts if a single
+// range isn't satisfied by any tag.
+func solve(ranges []rangeConstraint, tags []string) (string, []Constraint) {
+	vs := []*semver.Version{}
+	for _, t := range tags {
+		v, err := semver.NewVersion(t)
+		if err != nil {
+			// We skip any tags that are not valid semantic versions.
+			continue
+		}
+		vs = append(vs, v)
+	}
+	sort.Sort(sort.Reverse(semver.Collection(vs)))
+
+	for _, v := range vs {
+		if satisfiesAll(v, ranges...) {
+			return v.Original(), nil
+		}
+	}
+
+	if len(ranges) < 2 {
+		return "", nil
+	}
+	return "", conflicts(ranges, vs)
+}
+
+// conflicts returns the ranges that can't be satisfied by any of the supplied
+// versions, alone or together with another range. If every range can be
+// satisfied alone and pairwise, the conflict involves more than two ranges so
+// all ranges are returned.
+func conflicts(ranges []rangeConstraint, vs []*semver.Version) []Constraint {
+	alone := make([]bool, len(ranges))
+	in := make([]bool, len(ranges))
+	for i := range ranges {
+		alone[i] = satisfiable(vs, ranges[i])
+		in[i] = !alone[i]
+	}
+	for i := range ranges {
+		for j := i + 1; j < len(ranges); j++ {
+			if alone[i] && alone[j] && !satisfiable(vs, ranges[i], ranges[j]) {
+				in[i], in[j] = true, true
+			}
+		}
+	}
+
+	var cs []Constraint
+	for i, c := range ranges {
+		if in[i] {
+			cs = append(cs, c.Constraint)
+		}
+	}
+	if len(cs) > 0 {
+		return cs
+	}
+	for _, c := range ranges {
+		cs = append(cs, c.Constraint)
+	}
+	return cs
+}
+
+func satisfiable(vs []*semver.Version, ranges ...rangeConstraint) bool {
+	for _, v := range vs {
+		if satisfiesAll(v, ranges...) {
+			return true
+		}
+	}
+	return false
+}
+
+func satisfiesAll(v *semver.Version, ranges ...rangeConstraint) bool {
+	for _, r := range ranges {
+		if !r.semver.Check(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// describe the supplied constraints.
+func describe(cs []Constraint) string {
+	s := make([]string, len(cs))
+	for i, c := range cs {
+		s[i] = c.String()
+	}
+	return strings.Join(s, ", ")
+}
+
+// recordedResolution returns the recorded resolution of the supplied
+// dependency, if there is one and it satisfies all of the supplied
+// constraints.
+func recordedResolution(rs []v1beta1.DependencyResolution, dep *v1beta1.Dependency, cs []Constraint) (v1beta1.DependencyResolution, bool) {
+	for _, res := range rs {
+		if res.Package != dep.Identifier() || res.Type != dep.Type || res.Digest == "" {
+			continue
+		}
+		return res, satisfiedBy(res, cs)
+	}
+	return v1beta1.DependencyResolution{}, false
+}
+
+// satisfiedBy returns true if the supplied resolution satisfies every
+// supplied constraint. A resolution satisfies a digest constraint if it
+// resolved to the digest, and a range if its version is in the range. Any
+// resolution satisfies a channel.
+func satisfiedBy(res v1beta1.DependencyResolution, cs []Constraint) bool {
+	digests, ranges, err := parseConstraints(cs)
+	if err != nil {
+		return false
+	}
+	for d := range digests {
+		if d != res.Digest {
+			return false
+		}
+	}
+	if len(ranges) == 0 {
+		return true
+	}
+	v, err := semver.NewVersion(res.Version)
+	if err != nil {
+		return false
+	}
+	return satisfiesAll(v, ranges...)
+}
+
+// setResolution returns the supplied resolutions, with the supplied
+// resolution replacing any existing resolution of the same package.
+func setResolution(rs []v1beta1.DependencyResolution, res v1beta1.DependencyResolution) []v1beta1.DependencyResolution {
+	for i := range rs {
+		if rs[i].Package == res.Package {
+			rs[i] = res
+			return rs
+		}
+	}
+	return append(rs, res)
+}
+
+// pruneResolutions returns the supplied resolutions, without the resolutions
+// of packages that none of the supplied packages depend on. It returns true
+// if any were pruned.
+func pruneResolutions(rs []v1beta1.DependencyResolution, pkgs []v1beta1.LockPackage) ([]v1beta1.DependencyResolution, bool) {
+	depended := map[string]bool{}
+	for _, p := range pkgs {
+		for _, d := range p.Dependencies {
+			depended[d.Package] = true
+			for _, a := range d.Alternatives {
+				depended[a.Package] = true
+			}
+		}
+	}
+	kept := make([]v1beta1.DependencyResolution, 0, len(rs))
+	for _, res := range rs {
+		if depended[res.Package] {
+			kept = append(kept, res)
+		}
+	}
+	return kept, len(kept) != len(rs)
+}
+
+// installable returns the implied (i.e. missing) dependencies that the
+// package manager may install. It omits dependencies that only packages that
+// skip dependency resolution depend on.
+func installable(pkgs []v1beta1.LockPackage, implied []dag.Node) []dag.Node {
+	skipped := map[string]bool{}
+	for _, p := range pkgs {
+		for _, d := range p.Dependencies {
+			s, seen := skipped[d.Identifier()]
+			skipped[d.Identifier()] = p.SkipDependencyResolution && (s || !seen)
+		}
+	}
+	out := make([]dag.Node, 0, len(implied))
+	for _, n := range implied {
+		if !skipped[n.Identifier()] {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// unsatisfied returns the implied (i.e. missing) dependencies that a package
+// needs. It omits dependencies that every package that depends on them has
+// an installed alternative to.
+func unsatisfied(pkgs []v1beta1.LockPackage, implied []dag.Node) []dag.Node {
+	installed := map[string]bool{}
+	for _, p := range pkgs {
+		installed[p.Source] = true
+	}
+	satisfied := map[string]bool{}
+	for _, p := range pkgs {
+		for _, d := range p.Dependencies {
+			alt := slices.ContainsFunc(d.Alternatives, func(a v1beta1.DependencyAlternative) bool { return installed[a.Package] })
+			s, seen := satisfied[d.Identifier()]
+			satisfied[d.Identifier()] = alt && (s || !seen)
+		}
+	}
+	out := make([]dag.Node, 0, len(implied))
+	for _, n := range implied {
+		if !satisfied[n.Identifier()] {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// upgradable returns the installed packages that don't satisfy the semantic
+// version constraint of a package with an Automatic dependency upgrade policy,
+// in the order they appear in the lock. Packages whose version isn't a semantic
+// version, and digest and channel constraints, are ignored.
+// Packages that skip dependency resolution are ignored too.
+func upgradable(pkgs []v1beta1.LockPackage) []v1beta1.LockPackage {
+	installed := map[string]v1beta1.LockPackage{}
+	for _, p := range pkgs {
+		installed[p.Source] = p
+	}
+	unsatisfied := map[string]bool{}
+	for _, p := range pkgs {
+		if string(p.DependencyUpgradePolicy) != string(v1.AutomaticDependencyUpgrade) || p.SkipDependencyResolution {
+			continue
+		}
+		for _, d := range p.Dependencies {
+			lp, ok := installed[d.Package]
+			if !ok || lp.Type != d.Type {
+				continue
+			}
+			if _, err := conregv1.NewHash(d.Constraints); err == nil || d.Channel != "" {
+				continue
+			}
+			c, err := semver.NewConstraint(d.Constraints)
+			if err != nil {
+				continue
+			}
+			v, err := semver.NewVersion(lp.Version)
+			if err != nil {
+				continue
+			}
+			if !c.Check(v) {
+				unsatisfied[lp.Source] = true
+			}
+		}
+	}
+
+	var out []v1beta1.LockPackage
+	for _, p := range pkgs {
+		if unsatisfied[p.Source] {
+			out = append(out, p)
+			delete(unsatisfied, p.Source)
+		}
+	}
+	return out
+}
+
+// isUpgrade returns true if the candidate version is a semantic version that
+// is higher than the installed version.
+func isUpgrade(installed, candidate string) bool {
+	iv, err := semver.NewVersion(installed)
+	if err != nil {
+		return false
+	}
+	cv, err := semver.NewVersion(candidate)
+	if err != nil {
+		return false
+	}
+	return cv.GreaterThan(iv)
+}
+
+// tracking returns the installed packages that were installed from the
+// channel that a package depends on them by, i.e. whose version is the
+// channel. Packages that skip dependency resolution are ignored.
+func tracking(pkgs []v1beta1.LockPackage) []v1beta1.LockPackage {
+	installed := map[string]v1beta1.LockPackage{}
+	for _, p := range pkgs {
+		installed[p.Source] = p
+	}
+	seen := map[string]bool{}
+	var out []v1beta1.LockPackage
+	for _, p := range pkgs {
+		if p.SkipDependencyResolution {
+			continue
+		}
+		for _, d := range p.Dependencies {
+			lp, ok := installed[d.Package]
+			if !ok || lp.Type != d.Type || d.Channel == "" || lp.Version != d.Channel || seen[lp.Source] {
+				continue
+			}
+			seen[lp.Source] = true
+			out = append(out, lp)
+		}
+	}
+	return out
+}
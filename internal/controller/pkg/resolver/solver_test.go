@@ -0,0 +1,494 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resolver
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/internal/dag"
+)
+
+func TestDependencyConstraints(t *testing.T) {
+	pkgs := []v1beta1.LockPackage{
+		{
+			Source: "xpkg.upbound.io/acme/config-a",
+			Dependencies: []v1beta1.Dependency{
+				{Package: "xpkg.upbound.io/acme/provider-nop", Constraints: ">=v1.0.0"},
+				{Package: "xpkg.upbound.io/acme/function-nop", Constraints: "v0.1.0"},
+			},
+		},
+		{
+			Source: "xpkg.upbound.io/acme/config-b",
+			Dependencies: []v1beta1.Dependency{
+				{Package: "xpkg.upbound.io/acme/provider-nop", Constraints: "<v1.2.0"},
+			},
+		},
+	}
+
+	want := []Constraint{
+		{Dependent: "xpkg.upbound.io/acme/config-a", Constraints: ">=v1.0.0"},
+		{Dependent: "xpkg.upbound.io/acme/config-b", Constraints: "<v1.2.0"},
+	}
+	got := dependencyConstraints(pkgs, "xpkg.upbound.io/acme/provider-nop")
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("dependencyConstraints(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestSolve(t *testing.T) {
+	type args struct {
+		constraints []Constraint
+		tags        []string
+	}
+	type want struct {
+		version     string
+		conflicting []Constraint
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"SingleConstraint": {
+			reason: "We should return the highest version satisfying a single constraint.",
+			args: args{
+				constraints: []Constraint{{Dependent: "a", Constraints: ">=v1.0.0"}},
+				tags:        []string{"v0.9.0", "v1.0.0", "v1.1.0", "latest"},
+			},
+			want: want{
+				version: "v1.1.0",
+			},
+		},
+		"SingleUnsatisfiableConstraint": {
+			reason: "We should return no version and no conflicts if a single constraint can't be satisfied.",
+			args: args{
+				constraints: []Constraint{{Dependent: "a", Constraints: ">v2.0.0"}},
+				tags:        []string{"v0.9.0", "v1.0.0"},
+			},
+			want: want{},
+		},
+		"OverlappingConstraints": {
+			reason: "We should return the highest version satisfying every constraint.",
+			args: args{
+				constraints: []Constraint{
+					{Dependent: "a", Constraints: ">=v1.0.0"},
+					{Dependent: "b", Constraints: "<v1.2.0"},
+					{Dependent: "c", Constraints: "!=v1.1.1"},
+				},
+				tags: []string{"v1.0.0", "v1.1.0", "v1.1.1", "v1.2.0", "v1.3.0"},
+			},
+			want: want{
+				version: "v1.1.0",
+			},
+		},
+		"PairwiseConflict": {
+			reason: "We should return only the constraints that conflict with each other.",
+			args: args{
+				constraints: []Constraint{
+					{Dependent: "a", Constraints: ">=v1.0.0"},
+					{Dependent: "b", Constraints: "<v0.5.0"},
+					{Dependent: "c", Constraints: "*"},
+				},
+				tags: []string{"v0.4.0", "v1.0.0"},
+			},
+			want: want{
+				conflicting: []Constraint{
+					{Dependent: "a", Constraints: ">=v1.0.0"},
+					{Dependent: "b", Constraints: "<v0.5.0"},
+				},
+			},
+		},
+		"UnsatisfiableConstraintConflicts": {
+			reason: "We should return a constraint that no version satisfies, but not the constraints it's combined with.",
+			args: args{
+				constraints: []Constraint{
+					{Dependent: "a", Constraints: ">=v1.0.0"},
+					{Dependent: "b", Constraints: ">=v3.0.0"},
+				},
+				tags: []string{"v1.0.0", "v2.0.0"},
+			},
+			want: want{
+				conflicting: []Constraint{
+					{Dependent: "b", Constraints: ">=v3.0.0"},
+				},
+			},
+		},
+		"ThreeWayConflict": {
+			reason: "We should return every constraint if they only conflict when combined.",
+			args: args{
+				constraints: []Constraint{
+					{Dependent: "a", Constraints: "v1.0.0 || v2.0.0"},
+					{Dependent: "b", Constraints: "v2.0.0 || v3.0.0"},
+					{Dependent: "c", Constraints: "v1.0.0 || v3.0.0"},
+				},
+				tags: []string{"v1.0.0", "v2.0.0", "v3.0.0"},
+			},
+			want: want{
+				conflicting: []Constraint{
+					{Dependent: "a", Constraints: "v1.0.0 || v2.0.0"},
+					{Dependent: "b", Constraints: "v2.0.0 || v3.0.0"},
+					{Dependent: "c", Constraints: "v1.0.0 || v3.0.0"},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, ranges, err := parseConstraints(tc.args.constraints)
+			if err != nil {
+				t.Fatalf("parseConstraints(...): %v", err)
+			}
+			version, conflicting := solve(ranges, tc.args.tags)
+			if diff := cmp.Diff(tc.want.version, version); diff != "" {
+				t.Errorf("\n%s\nsolve(...): -want version, +got version:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.conflicting, conflicting, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nsolve(...): -want conflicting, +got conflicting:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRecordedResolution(t *testing.T) {
+	dep := &v1beta1.Dependency{Package: "xpkg.upbound.io/acme/provider-nop", Type: v1beta1.ProviderPackageType}
+	res := v1beta1.DependencyResolution{
+		Package: "xpkg.upbound.io/acme/provider-nop",
+		Type:    v1beta1.ProviderPackageType,
+		Version: "v1.1.0",
+		Digest:  "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904",
+	}
+
+	type want struct {
+		res v1beta1.DependencyResolution
+		ok  bool
+	}
+	cases := map[string]struct {
+		reason string
+		rs     []v1beta1.DependencyResolution
+		cs     []Constraint
+		want   want
+	}{
+		"NoResolution": {
+			reason: "We should return false if the dependency has no recorded resolution.",
+			cs:     []Constraint{{Constraints: ">=v1.0.0"}},
+			want:   want{},
+		},
+		"SatisfiesConstraints": {
+			reason: "We should return a recorded resolution that satisfies every constraint.",
+			rs:     []v1beta1.DependencyResolution{res},
+			cs:     []Constraint{{Constraints: ">=v1.0.0"}, {Constraints: res.Digest}},
+			want:   want{res: res, ok: true},
+		},
+		"ViolatesRange": {
+			reason: "We should return false if a recorded resolution's version is outside a range.",
+			rs:     []v1beta1.DependencyResolution{res},
+			cs:     []Constraint{{Constraints: ">=v1.0.0"}, {Constraints: ">=v1.2.0"}},
+			want:   want{res: res},
+		},
+		"ViolatesDigest": {
+			reason: "We should return false if a recorded resolution isn't the pinned digest.",
+			rs:     []v1beta1.DependencyResolution{res},
+			cs:     []Constraint{{Constraints: "sha256:0000000000000000000000000000000000000000000000000000000000000000"}},
+			want:   want{res: res},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, ok := recordedResolution(tc.rs, dep, tc.cs)
+			if diff := cmp.Diff(tc.want.res, got); diff != "" {
+				t.Errorf("\n%s\nrecordedResolution(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ok, ok); diff != "" {
+				t.Errorf("\n%s\nrecordedResolution(...): -want ok, +got ok:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPruneResolutions(t *testing.T) {
+	rs := []v1beta1.DependencyResolution{
+		{Package: "xpkg.upbound.io/acme/provider-nop", Digest: "sha256:a"},
+		{Package: "xpkg.upbound.io/acme/function-nop", Digest: "sha256:b"},
+	}
+	pkgs := []v1beta1.LockPackage{{
+		Source:       "xpkg.upbound.io/acme/config",
+		Dependencies: []v1beta1.Dependency{{Package: "xpkg.upbound.io/acme/function-nop"}},
+	}}
+
+	got, pruned := pruneResolutions(rs, pkgs)
+	if diff := cmp.Diff(rs[1:], got); diff != "" {
+		t.Errorf("pruneResolutions(...): -want, +got:\n%s", diff)
+	}
+	if !pruned {
+		t.Errorf("pruneResolutions(...): want pruned, got not pruned")
+	}
+}
+
+func TestUpgradable(t *testing.T) {
+	automatic := v1beta1.DependencyUpgradePolicy(v1.AutomaticDependencyUpgrade)
+	provider := v1beta1.LockPackage{
+		Name:    "provider-nop-abc123",
+		Type:    v1beta1.ProviderPackageType,
+		Source:  "xpkg.upbound.io/acme/provider-nop",
+		Version: "v0.18.0",
+	}
+	dependent := func(p v1beta1.DependencyUpgradePolicy, constraints string) v1beta1.LockPackage {
+		return v1beta1.LockPackage{
+			Source:                  "xpkg.upbound.io/acme/config",
+			DependencyUpgradePolicy: p,
+			Dependencies: []v1beta1.Dependency{{
+				Package:     provider.Source,
+				Type:        v1beta1.ProviderPackageType,
+				Constraints: constraints,
+			}},
+		}
+	}
+
+	other := v1beta1.LockPackage{
+		Name:    "provider-other-abc123",
+		Type:    v1beta1.ProviderPackageType,
+		Source:  "xpkg.upbound.io/acme/provider-other",
+		Version: "v1.0.0",
+	}
+
+	cases := map[string]struct {
+		reason string
+		pkgs   []v1beta1.LockPackage
+		want   []v1beta1.LockPackage
+	}{
+		"Automatic": {
+			reason: "We should return an installed package that doesn't satisfy the constraint of a package with an Automatic policy.",
+			pkgs:   []v1beta1.LockPackage{dependent(automatic, ">=v0.20.0"), provider},
+			want:   []v1beta1.LockPackage{provider},
+		},
+		"Manual": {
+			reason: "We should not return an installed package if the package that constrains it doesn't have an Automatic policy.",
+			pkgs:   []v1beta1.LockPackage{dependent("", ">=v0.20.0"), provider},
+			want:   nil,
+		},
+		"Satisfied": {
+			reason: "We should not return an installed package that satisfies its constraints.",
+			pkgs:   []v1beta1.LockPackage{dependent(automatic, ">=v0.18.0"), provider},
+			want:   nil,
+		},
+		"Digest": {
+			reason: "We should ignore digest constraints.",
+			pkgs:   []v1beta1.LockPackage{dependent(automatic, "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904"), provider},
+			want:   nil,
+		},
+		"Channel": {
+			reason: "We should ignore channel constraints.",
+			pkgs: func() []v1beta1.LockPackage {
+				d := dependent(automatic, "")
+				d.Dependencies[0].Channel = "stable"
+				return []v1beta1.LockPackage{d, provider}
+			}(),
+			want: nil,
+		},
+		"NotInstalled": {
+			reason: "We should ignore dependencies that aren't installed.",
+			pkgs:   []v1beta1.LockPackage{dependent(automatic, ">=v0.20.0")},
+			want:   nil,
+		},
+		"All": {
+			reason: "We should return every installed package that doesn't satisfy a constraint, once each, in lock order.",
+			pkgs: func() []v1beta1.LockPackage {
+				d := dependent(automatic, ">=v0.20.0")
+				d.Dependencies = append(d.Dependencies, v1beta1.Dependency{Package: other.Source, Type: v1beta1.ProviderPackageType, Constraints: ">=v2.0.0"})
+				d2 := dependent(automatic, ">=v0.19.0")
+				d2.Source = "xpkg.upbound.io/acme/config-2"
+				return []v1beta1.LockPackage{d, d2, other, provider}
+			}(),
+			want: []v1beta1.LockPackage{other, provider},
+		},
+		"SkipDependencyResolution": {
+			reason: "We should not return an installed package if the package that constrains it skips dependency resolution.",
+			pkgs: func() []v1beta1.LockPackage {
+				d := dependent(automatic, ">=v0.20.0")
+				d.SkipDependencyResolution = true
+				return []v1beta1.LockPackage{d, provider}
+			}(),
+			want: nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := upgradable(tc.pkgs)
+			if diff := cmp.Diff(tc.want, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nupgradable(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestInstallable(t *testing.T) {
+	nop := &v1beta1.Dependency{Package: "xpkg.upbound.io/acme/provider-nop", Type: v1beta1.ProviderPackageType}
+	dependent := func(source string, skip bool) v1beta1.LockPackage {
+		return v1beta1.LockPackage{
+			Source:                   source,
+			SkipDependencyResolution: skip,
+			Dependencies:             []v1beta1.Dependency{*nop},
+		}
+	}
+
+	cases := map[string]struct {
+		reason string
+		pkgs   []v1beta1.LockPackage
+		want   []dag.Node
+	}{
+		"NotSkipped": {
+			reason: "We should return a dependency of a package that doesn't skip dependency resolution.",
+			pkgs:   []v1beta1.LockPackage{dependent("xpkg.upbound.io/acme/a", false)},
+			want:   []dag.Node{nop},
+		},
+		"Skipped": {
+			reason: "We should not return a dependency that only packages that skip dependency resolution depend on.",
+			pkgs:   []v1beta1.LockPackage{dependent("xpkg.upbound.io/acme/a", true), dependent("xpkg.upbound.io/acme/b", true)},
+			want:   []dag.Node{},
+		},
+		"SomeSkipped": {
+			reason: "We should return a dependency if any package that depends on it doesn't skip dependency resolution.",
+			pkgs:   []v1beta1.LockPackage{dependent("xpkg.upbound.io/acme/a", true), dependent("xpkg.upbound.io/acme/b", false)},
+			want:   []dag.Node{nop},
+		},
+		"NoDependents": {
+			reason: "We should return a dependency that no package in the lock depends on.",
+			want:   []dag.Node{nop},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := installable(tc.pkgs, []dag.Node{nop})
+			if diff := cmp.Diff(tc.want, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\ninstallable(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUnsatisfied(t *testing.T) {
+	aws := &v1beta1.Dependency{
+		Package:      "xpkg.upbound.io/upbound/provider-aws",
+		Type:         v1beta1.ProviderPackageType,
+		Alternatives: []v1beta1.DependencyAlternative{{Package: "xpkg.upbound.io/upbound/provider-gcp", Type: v1beta1.ProviderPackageType}},
+	}
+	gcp := v1beta1.LockPackage{Source: "xpkg.upbound.io/upbound/provider-gcp", Type: v1beta1.ProviderPackageType}
+	dependent := func(source string, dep v1beta1.Dependency) v1beta1.LockPackage {
+		return v1beta1.LockPackage{Source: source, Dependencies: []v1beta1.Dependency{dep}}
+	}
+
+	cases := map[string]struct {
+		reason string
+		pkgs   []v1beta1.LockPackage
+		want   []dag.Node
+	}{
+		"NoAlternativeInstalled": {
+			reason: "We should return a dependency none of whose alternatives is installed.",
+			pkgs:   []v1beta1.LockPackage{dependent("xpkg.upbound.io/acme/a", *aws)},
+			want:   []dag.Node{aws},
+		},
+		"AlternativeInstalled": {
+			reason: "We should not return a dependency with an installed alternative.",
+			pkgs:   []v1beta1.LockPackage{dependent("xpkg.upbound.io/acme/a", *aws), gcp},
+			want:   []dag.Node{},
+		},
+		"NeededByAnother": {
+			reason: "We should return a dependency with an installed alternative if another package depends on it without alternatives.",
+			pkgs: []v1beta1.LockPackage{
+				dependent("xpkg.upbound.io/acme/a", *aws),
+				dependent("xpkg.upbound.io/acme/b", v1beta1.Dependency{Package: aws.Package, Type: aws.Type}),
+				gcp,
+			},
+			want: []dag.Node{aws},
+		},
+		"NoDependents": {
+			reason: "We should return a dependency that no package in the lock depends on.",
+			want:   []dag.Node{aws},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := unsatisfied(tc.pkgs, []dag.Node{aws})
+			if diff := cmp.Diff(tc.want, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nunsatisfied(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTracking(t *testing.T) {
+	provider := v1beta1.LockPackage{
+		Name:    "provider-nop-abc123",
+		Type:    v1beta1.ProviderPackageType,
+		Source:  "xpkg.upbound.io/acme/provider-nop",
+		Version: "stable",
+	}
+	dependent := func(channel string, skip bool) v1beta1.LockPackage {
+		return v1beta1.LockPackage{
+			Source:                   "xpkg.upbound.io/acme/config",
+			SkipDependencyResolution: skip,
+			Dependencies: []v1beta1.Dependency{{
+				Package: provider.Source,
+				Type:    v1beta1.ProviderPackageType,
+				Channel: channel,
+			}},
+		}
+	}
+
+	cases := map[string]struct {
+		reason string
+		pkgs   []v1beta1.LockPackage
+		want   []v1beta1.LockPackage
+	}{
+		"Tracking": {
+			reason: "We should return an installed package that was installed from the channel a package depends on it by.",
+			pkgs:   []v1beta1.LockPackage{dependent("stable", false), dependent("stable", false), provider},
+			want:   []v1beta1.LockPackage{provider},
+		},
+		"DifferentChannel": {
+			reason: "We should not return an installed package that was installed from a different channel.",
+			pkgs:   []v1beta1.LockPackage{dependent("beta", false), provider},
+		},
+		"NoChannel": {
+			reason: "We should not return an installed package that no package depends on by channel.",
+			pkgs:   []v1beta1.LockPackage{dependent("", false), provider},
+		},
+		"SkipDependencyResolution": {
+			reason: "We should not return an installed package if the package that tracks it skips dependency resolution.",
+			pkgs:   []v1beta1.LockPackage{dependent("stable", true), provider},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tracking(tc.pkgs)
+			if diff := cmp.Diff(tc.want, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\ntracking(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errFmtParseRequiredAPI = "cannot parse required API %q"
+	errFmtDiscoverAPI      = "cannot discover whether the API server serves %s"
+)
+
+// An APIChecker checks whether the API server serves an API.
+type APIChecker interface {
+	// ServesAPI returns true if the API server serves the supplied API
+	// version, and the supplied kind of that API version if it's not empty.
+	ServesAPI(ctx context.Context, gv schema.GroupVersion, kind string) (bool, error)
+}
+
+// An APICheckerFn checks whether the API server serves an API.
+type APICheckerFn func(ctx context.Context, gv schema.GroupVersion, kind string) (bool, error)
+
+// ServesAPI returns true if the API server serves the supplied API.
+func (fn APICheckerFn) ServesAPI(ctx context.Context, gv schema.GroupVersion, kind string) (bool, error) {
+	return fn(ctx, gv, kind)
+}
+
+// A DiscoveryAPIChecker uses the discovery API to check whether the API server
+// serves an API.
+type DiscoveryAPIChecker struct {
+	client discovery.DiscoveryInterface
+}
+
+// NewDiscoveryAPIChecker returns an APIChecker that uses the discovery API.
+func NewDiscoveryAPIChecker(c discovery.DiscoveryInterface) *DiscoveryAPIChecker {
+	return &DiscoveryAPIChecker{client: c}
+}
+
+// ServesAPI returns true if the API server serves the supplied API.
+func (c *DiscoveryAPIChecker) ServesAPI(_ context.Context, gv schema.GroupVersion, kind string) (bool, error) {
+	rl, err := c.client.ServerResourcesForGroupVersion(gv.String())
+	if kerrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, errFmtDiscoverAPI, gv)
+	}
+	if kind == "" {
+		return true, nil
+	}
+	for _, r := range rl.APIResources {
+		if r.Kind == kind {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ParseRequiredAPIs parses the value of the pkg.crossplane.io/requires-apis
+// annotation. Each comma separated API is either an API version, for example
+// monitoring.coreos.com/v1, or a kind of an API version, for example
+// monitoring.coreos.com/v1/ServiceMonitor. Kinds are distinguished from
+// versions by their leading upper case letter.
+func ParseRequiredAPIs(value string) ([]schema.GroupVersionKind, error) {
+	var out []schema.GroupVersionKind
+	for _, s := range strings.Split(value, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		apiVersion, kind := s, ""
+		if i := strings.LastIndex(s, "/"); i > 0 && i < len(s)-1 && unicode.IsUpper(rune(s[i+1])) {
+			apiVersion, kind = s[:i], s[i+1:]
+		}
+		gv, err := schema.ParseGroupVersion(apiVersion)
+		if err != nil || gv.Version == "" {
+			return nil, errors.Errorf(errFmtParseRequiredAPI, s)
+		}
+		out = append(out, gv.WithKind(kind))
+	}
+	return out, nil
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ APIChecker = &DiscoveryAPIChecker{}
+
+func TestParseRequiredAPIs(t *testing.T) {
+	type want struct {
+		gvks []schema.GroupVersionKind
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		value  string
+		want   want
+	}{
+		"Empty": {
+			reason: "An empty annotation should require no APIs.",
+			value:  "",
+			want:   want{},
+		},
+		"VersionsAndKinds": {
+			reason: "We should parse a comma separated list of API versions and kinds.",
+			value:  "monitoring.coreos.com/v1, v1/ConfigMap,example.org/v1beta1/Example,",
+			want: want{
+				gvks: []schema.GroupVersionKind{
+					{Group: "monitoring.coreos.com", Version: "v1"},
+					{Version: "v1", Kind: "ConfigMap"},
+					{Group: "example.org", Version: "v1beta1", Kind: "Example"},
+				},
+			},
+		},
+		"Invalid": {
+			reason: "We should return an error if an API can't be parsed.",
+			value:  "example.org/v1/what/Example",
+			want: want{
+				err: errors.Errorf(errFmtParseRequiredAPI, "example.org/v1/what/Example"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			gvks, err := ParseRequiredAPIs(tc.value)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nParseRequiredAPIs(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.gvks, gvks); diff != "" {
+				t.Errorf("\n%s\nParseRequiredAPIs(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDiscoveryAPICheckerServesAPI(t *testing.T) {
+	served := []*metav1.APIResourceList{
+		{
+			GroupVersion: "example.org/v1",
+			APIResources: []metav1.APIResource{{Name: "examples", Kind: "Example"}},
+		},
+	}
+
+	type args struct {
+		gv   schema.GroupVersion
+		kind string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   bool
+	}{
+		"ServedVersion": {
+			reason: "We should return true if the API server serves the API version.",
+			args:   args{gv: schema.GroupVersion{Group: "example.org", Version: "v1"}},
+			want:   true,
+		},
+		"ServedKind": {
+			reason: "We should return true if the API server serves the kind.",
+			args:   args{gv: schema.GroupVersion{Group: "example.org", Version: "v1"}, kind: "Example"},
+			want:   true,
+		},
+		"UnservedKind": {
+			reason: "We should return false if the API server serves the API version, but not the kind.",
+			args:   args{gv: schema.GroupVersion{Group: "example.org", Version: "v1"}, kind: "Other"},
+			want:   false,
+		},
+		"UnservedVersion": {
+			reason: "We should return false if the API server doesn't serve the API version.",
+			args:   args{gv: schema.GroupVersion{Group: "example.org", Version: "v2"}},
+			want:   false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := NewDiscoveryAPIChecker(&fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: served}})
+			got, err := c.ServesAPI(context.Background(), tc.args.gv, tc.args.kind)
+			if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nServesAPI(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nServesAPI(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"fmt"
+	"sort"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	extv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/parser"
+
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+const (
+	warnFmtCRDVersionNoSchema = "CustomResourceDefinition %q version %q has no schema"
+	warnFmtCRDDeprecatedAPI   = "CustomResourceDefinition %q uses deprecated API version %s"
+	warnFmtXRDVersionNoSchema = "CompositeResourceDefinition %q version %q has no schema"
+)
+
+// summarizeContents summarizes the contents of the supplied package. Objects
+// are counted by kind, and objects that are likely to cause problems produce a
+// warning.
+func summarizeContents(pkg parser.Lintable) *v1.PackageContents {
+	c := &v1.PackageContents{}
+	counts := map[schema.GroupVersionKind]int64{}
+
+	for _, o := range pkg.GetObjects() {
+		counts[o.GetObjectKind().GroupVersionKind()]++
+
+		switch obj := o.(type) {
+		case *extv1.CustomResourceDefinition:
+			for _, v := range obj.Spec.Versions {
+				if v.Schema == nil || v.Schema.OpenAPIV3Schema == nil {
+					c.Warnings = append(c.Warnings, fmt.Sprintf(warnFmtCRDVersionNoSchema, obj.GetName(), v.Name))
+				}
+			}
+		case *extv1beta1.CustomResourceDefinition:
+			c.Warnings = append(c.Warnings, fmt.Sprintf(warnFmtCRDDeprecatedAPI, obj.GetName(), extv1beta1.SchemeGroupVersion))
+		case *apiextensionsv1.CompositeResourceDefinition:
+			for _, v := range obj.Spec.Versions {
+				if v.Schema == nil || len(v.Schema.OpenAPIV3Schema.Raw) == 0 {
+					c.Warnings = append(c.Warnings, fmt.Sprintf(warnFmtXRDVersionNoSchema, obj.GetName(), v.Name))
+				}
+			}
+		}
+	}
+
+	for gvk, n := range counts {
+		c.Objects = append(c.Objects, v1.PackageObjectCount{
+			APIVersion: gvk.GroupVersion().String(),
+			Kind:       gvk.Kind,
+			Count:      n,
+		})
+	}
+
+	// Map iteration order is random. Sort so that we don't update the status
+	// of the revision unnecessarily.
+	sort.Slice(c.Objects, func(i, j int) bool {
+		if c.Objects[i].APIVersion != c.Objects[j].APIVersion {
+			return c.Objects[i].APIVersion < c.Objects[j].APIVersion
+		}
+		return c.Objects[i].Kind < c.Objects[j].Kind
+	})
+
+	return c
+}
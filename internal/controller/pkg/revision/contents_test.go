@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/parser"
+
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+var _ parser.Lintable = &lintable{}
+
+type lintable struct {
+	meta    []runtime.Object
+	objects []runtime.Object
+}
+
+func (l *lintable) GetMeta() []runtime.Object    { return l.meta }
+func (l *lintable) GetObjects() []runtime.Object { return l.objects }
+
+func TestSummarizeContents(t *testing.T) {
+	crd := func(name string, schema *extv1.CustomResourceValidation) *extv1.CustomResourceDefinition {
+		return &extv1.CustomResourceDefinition{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition"},
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: extv1.CustomResourceDefinitionSpec{
+				Versions: []extv1.CustomResourceDefinitionVersion{{Name: "v1", Schema: schema}},
+			},
+		}
+	}
+	xrd := &apiextensionsv1.CompositeResourceDefinition{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apiextensions.crossplane.io/v1", Kind: "CompositeResourceDefinition"},
+		ObjectMeta: metav1.ObjectMeta{Name: "xcools.example.org"},
+		Spec: apiextensionsv1.CompositeResourceDefinitionSpec{
+			Versions: []apiextensionsv1.CompositeResourceDefinitionVersion{{Name: "v1alpha1"}},
+		},
+	}
+
+	cases := map[string]struct {
+		reason string
+		pkg    parser.Lintable
+		want   *v1.PackageContents
+	}{
+		"Empty": {
+			reason: "A package with no objects should produce an empty summary.",
+			pkg:    &lintable{},
+			want:   &v1.PackageContents{},
+		},
+		"CountsAndWarnings": {
+			reason: "Objects should be counted by kind, and objects without a schema should produce warnings.",
+			pkg: &lintable{
+				objects: []runtime.Object{
+					crd("a.example.org", &extv1.CustomResourceValidation{OpenAPIV3Schema: &extv1.JSONSchemaProps{Type: "object"}}),
+					crd("b.example.org", nil),
+					xrd,
+				},
+			},
+			want: &v1.PackageContents{
+				Objects: []v1.PackageObjectCount{
+					{APIVersion: "apiextensions.crossplane.io/v1", Kind: "CompositeResourceDefinition", Count: 1},
+					{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Count: 2},
+				},
+				Warnings: []string{
+					`CustomResourceDefinition "b.example.org" version "v1" has no schema`,
+					`CompositeResourceDefinition "xcools.example.org" version "v1alpha1" has no schema`,
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := summarizeContents(tc.pkg)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nsummarizeContents(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -19,18 +19,26 @@ package revision
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/Masterminds/semver"
 	"github.com/google/go-containerregistry/pkg/name"
 	conregv1 "github.com/google/go-containerregistry/pkg/v1"
+	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
@@ -43,33 +51,62 @@ const (
 
 	errNotMeta                   = "meta type is not a valid package"
 	errGetOrCreateLock           = "cannot get or create lock"
+	errGetLock                   = "cannot get lock"
+	errInvalidDependency         = "invalid package dependency"
 	errInitDAG                   = "cannot initialize dependency graph from the packages in the lock"
-	errFmtIncompatibleDependency = "incompatible dependencies: %s"
+	errFmtIncompatibleDependency = "incompatible dependencies: %s (%s)"
+	errUpgradeManually           = "upgrade the dependencies, or set spec.dependencyUpgradePolicy to Automatic"
+	errUpgradeAutomatically      = "the package manager upgrades the dependencies if a version satisfies every package's constraints"
 	errFmtMissingDependencies    = "missing dependencies: %+v"
 	errDependencyNotInGraph      = "dependency is not present in graph"
 	errDependencyNotLockPackage  = "dependency in graph is not a lock package"
+	errFmtUnhealthyDependencies  = "dependencies are not yet healthy: %s"
+	errFmtUnknownDependencyType  = "unknown package type %q"
+	errFmtMissingAPIs            = "no installed package defines the APIs: %s"
+	errGetDependencyRevision     = "cannot get package revision to check the APIs it defines"
+	errGetDependencyCRD          = "cannot get CustomResourceDefinition to check the APIs it defines"
+
+	kindCRD = "CustomResourceDefinition"
 )
 
 // DependencyManager is a lock on packages.
 type DependencyManager interface {
 	Resolve(ctx context.Context, pkg runtime.Object, pr v1.PackageRevision) (found, installed, invalid int, err error)
 	RemoveSelf(ctx context.Context, pr v1.PackageRevision) error
+	ProviderGroups(ctx context.Context, pr v1.PackageRevision) ([]string, error)
 }
 
 // PackageDependencyManager is a resolver for packages.
 type PackageDependencyManager struct {
-	client      client.Client
-	newDag      dag.NewDAGFn
-	packageType v1beta1.PackageType
+	client         client.Client
+	newDag         dag.NewDAGFn
+	packageType    v1beta1.PackageType
+	requireHealthy bool
+}
+
+// PackageDependencyManagerOption configures a PackageDependencyManager.
+type PackageDependencyManagerOption func(*PackageDependencyManager)
+
+// PackageDependencyManagerWithHealthyDependencies requires a package's direct
+// dependencies to be healthy, not just installed, before its dependencies are
+// considered resolved.
+func PackageDependencyManagerWithHealthyDependencies() PackageDependencyManagerOption {
+	return func(m *PackageDependencyManager) {
+		m.requireHealthy = true
+	}
 }
 
 // NewPackageDependencyManager creates a new PackageDependencyManager.
-func NewPackageDependencyManager(c client.Client, nd dag.NewDAGFn, t v1beta1.PackageType) *PackageDependencyManager {
-	return &PackageDependencyManager{
+func NewPackageDependencyManager(c client.Client, nd dag.NewDAGFn, t v1beta1.PackageType, opts ...PackageDependencyManagerOption) *PackageDependencyManager {
+	m := &PackageDependencyManager{
 		client:      c,
 		newDag:      nd,
 		packageType: t,
 	}
+	for _, o := range opts {
+		o(m)
+	}
+	return m
 }
 
 // Resolve resolves package dependencies.
@@ -84,26 +121,28 @@ func (m *PackageDependencyManager) Resolve(ctx context.Context, pkg runtime.Obje
 		return found, installed, invalid, errors.New(errNotMeta)
 	}
 
-	// Copy package dependencies into Lock Dependencies.
-	sources := make([]v1beta1.Dependency, len(pack.GetDependencies()))
-	for i, dep := range pack.GetDependencies() {
-		pdep := v1beta1.Dependency{}
-		switch {
-		case dep.Configuration != nil:
-			pdep.Package = *dep.Configuration
-			pdep.Type = v1beta1.ConfigurationPackageType
-		case dep.Provider != nil:
-			pdep.Package = *dep.Provider
-			pdep.Type = v1beta1.ProviderPackageType
-		case dep.Function != nil:
-			pdep.Package = *dep.Function
-			pdep.Type = v1beta1.FunctionPackageType
+	// Copy package dependencies into Lock Dependencies. Dependencies on an
+	// API aren't added to the lock, because they're not satisfied by a
+	// particular package.
+	sources := make([]v1beta1.Dependency, 0, len(pack.GetDependencies()))
+	var apis []schema.GroupVersion
+	for _, dep := range pack.GetDependencies() {
+		gv, ok, err := xpkg.DependencyAPI(dep)
+		if err != nil {
+			return found, installed, invalid, errors.Wrap(err, errInvalidDependency)
+		}
+		if ok {
+			apis = append(apis, gv)
+			continue
 		}
-		pdep.Constraints = dep.Version
-		sources[i] = pdep
+		ld, err := xpkg.LockDependency(dep)
+		if err != nil {
+			return found, installed, invalid, errors.Wrap(err, errInvalidDependency)
+		}
+		sources = append(sources, ld)
 	}
 
-	found = len(sources)
+	found = len(sources) + len(apis)
 
 	// Get the lock.
 	lock := &v1beta1.Lock{}
@@ -116,9 +155,23 @@ func (m *PackageDependencyManager) Resolve(ctx context.Context, pkg runtime.Obje
 		return found, installed, invalid, errors.Wrap(err, errGetOrCreateLock)
 	}
 
-	prRef, err := name.ParseReference(pr.GetSource(), name.WithDefaultRegistry(""))
-	if err != nil {
-		return found, installed, invalid, err
+	// A dependency with alternatives is satisfied by whichever of them is
+	// installed.
+	for i := range sources {
+		sources[i] = xpkg.PreferInstalled(sources[i], lock.Packages)
+	}
+
+	// Revisions may be pinned to a digest. We record the tag they were pinned
+	// from in the lock, so that it can be compared to version constraints.
+	// Revisions fetched from a URL have no tag, so we record their URL.
+	lockRef, version, digest := pr.GetSource(), "", ""
+	if !xpkg.IsURLSource(pr.GetSource()) {
+		prRef, err := name.ParseReference(xpkg.UnpinnedSource(pr.GetSource()), name.WithDefaultRegistry(""))
+		if err != nil {
+			return found, installed, invalid, err
+		}
+		lockRef, version = xpkg.ParsePackageSourceFromReference(prRef), prRef.Identifier()
+		digest = pinnedDigest(pr.GetSource())
 	}
 
 	d := m.newDag()
@@ -127,16 +180,23 @@ func (m *PackageDependencyManager) Resolve(ctx context.Context, pkg runtime.Obje
 		return found, installed, invalid, errors.Wrap(err, errInitDAG)
 	}
 
-	lockRef := xpkg.ParsePackageSourceFromReference(prRef)
 	// NOTE(hasheddan): consider adding health of package to lock so that it can
 	// be rolled up to any dependent packages.
 	self := v1beta1.LockPackage{
 		Name:         pr.GetName(),
 		Type:         m.packageType,
 		Source:       lockRef,
-		Version:      prRef.Identifier(),
+		Version:      version,
+		Digest:       digest,
 		Dependencies: sources,
 	}
+	for _, gv := range apis {
+		self.APIs = append(self.APIs, gv.String())
+	}
+	if p := pr.GetDependencyUpgradePolicy(); p != nil {
+		self.DependencyUpgradePolicy = v1beta1.DependencyUpgradePolicy(*p)
+	}
+	self.SkipDependencyResolution = ptr.Deref(pr.GetSkipDependencyResolution(), false)
 
 	// Delete packages in lock with same name and distinct source
 	// This is a corner case when source is updated but image SHA is not (i.e. relocate same image
@@ -154,12 +214,35 @@ func (m *PackageDependencyManager) Resolve(ctx context.Context, pkg runtime.Obje
 		}
 	}
 
+	pr.SetDependencyEdges(dependencyEdges(self, lock.Packages))
+
 	prExists := false
-	for _, lp := range lock.Packages {
-		if lp.Name == pr.GetName() {
-			prExists = true
-			break
+	for i, lp := range lock.Packages {
+		if lp.Name != pr.GetName() {
+			continue
 		}
+		prExists = true
+
+		// The dependency upgrade policy may change after we're added to the
+		// lock. Keep it up to date, so the lock's resolver knows whether it
+		// may upgrade or install our dependencies. So may which alternative
+		// satisfies a dependency, once the resolver installs one. Packages
+		// added to the lock before it recorded API dependencies need them
+		// recorded too.
+		switched := hasAlternatives(self.Dependencies) && !equality.Semantic.DeepEqual(lp.Dependencies, self.Dependencies)
+		if lp.DependencyUpgradePolicy != self.DependencyUpgradePolicy || lp.SkipDependencyResolution != self.SkipDependencyResolution || switched || !slices.Equal(lp.APIs, self.APIs) {
+			lock.Packages[i].DependencyUpgradePolicy = self.DependencyUpgradePolicy
+			lock.Packages[i].SkipDependencyResolution = self.SkipDependencyResolution
+			lock.Packages[i].Dependencies = self.Dependencies
+			lock.Packages[i].APIs = self.APIs
+			if err := m.client.Update(ctx, lock); err != nil {
+				return found, installed, invalid, err
+			}
+			if switched {
+				d.AddOrUpdateNodes(&self)
+			}
+		}
+		break
 	}
 
 	// If we don't exist in lock then we should add self.
@@ -182,7 +265,7 @@ func (m *PackageDependencyManager) Resolve(ctx context.Context, pkg runtime.Obje
 			}
 			missing = append(missing, dep.Identifier())
 		}
-		if installed != found {
+		if len(missing) > 0 {
 			return found, installed, invalid, errors.Errorf(errFmtMissingDependencies, missing)
 		}
 	}
@@ -191,6 +274,10 @@ func (m *PackageDependencyManager) Resolve(ctx context.Context, pkg runtime.Obje
 	if err != nil {
 		return found, installed, invalid, err
 	}
+	// A package that is its own transitive dependency can never be satisfied.
+	if _, ok := tree[lockRef]; ok {
+		return found, installed, invalid, &dag.CycleError{Cycle: dag.FindCycle(d, lockRef)}
+	}
 	found = len(tree)
 	installed = found
 	// Check if any dependencies or transitive dependencies are missing (implied).
@@ -223,6 +310,12 @@ func (m *PackageDependencyManager) Resolve(ctx context.Context, pkg runtime.Obje
 			continue
 		}
 
+		// A channel may point to any version, so any installed version
+		// satisfies a dependency that tracks one.
+		if dep.Channel != "" {
+			continue
+		}
+
 		c, err := semver.NewConstraint(dep.Constraints)
 		if err != nil {
 			return found, installed, invalid, err
@@ -241,11 +334,227 @@ func (m *PackageDependencyManager) Resolve(ctx context.Context, pkg runtime.Obje
 	}
 	invalid = len(invalidDeps)
 	if invalid > 0 {
-		return found, installed, invalid, errors.Errorf(errFmtIncompatibleDependency, strings.Join(invalidDeps, "; "))
+		hint := errUpgradeManually
+		if ptr.Deref(pr.GetDependencyUpgradePolicy(), v1.ManualDependencyUpgrade) == v1.AutomaticDependencyUpgrade {
+			hint = errUpgradeAutomatically
+		}
+		return found, installed, invalid, errors.Errorf(errFmtIncompatibleDependency, strings.Join(invalidDeps, "; "), hint)
+	}
+
+	// Any other installed package that defines an API satisfies a dependency
+	// on it.
+	found += len(apis)
+	installed += len(apis)
+	var missingAPIs []string
+	for _, gv := range apis {
+		ok, err := m.definesAPI(ctx, lock.Packages, self.Name, gv)
+		if err != nil {
+			return found, installed, invalid, err
+		}
+		if !ok {
+			installed--
+			missingAPIs = append(missingAPIs, gv.String())
+		}
+	}
+	if len(missingAPIs) > 0 {
+		return found, installed, invalid, errors.Errorf(errFmtMissingAPIs, strings.Join(missingAPIs, ", "))
+	}
+
+	if !m.requireHealthy {
+		return found, installed, invalid, nil
+	}
+
+	var unhealthy []string
+	for _, dep := range self.Dependencies {
+		n, err := d.GetNode(dep.Package)
+		if err != nil {
+			return found, installed, invalid, errors.New(errDependencyNotInGraph)
+		}
+		lp, ok := n.(*v1beta1.LockPackage)
+		if !ok {
+			return found, installed, invalid, errors.New(errDependencyNotLockPackage)
+		}
+		healthy, err := m.isHealthy(ctx, lp)
+		if err != nil {
+			return found, installed, invalid, err
+		}
+		if !healthy {
+			unhealthy = append(unhealthy, lp.Identifier())
+		}
+	}
+	if len(unhealthy) > 0 {
+		return found, installed, invalid, errors.Errorf(errFmtUnhealthyDependencies, strings.Join(unhealthy, ", "))
 	}
 	return found, installed, invalid, nil
 }
 
+// hasAlternatives returns true if any of the supplied dependencies has
+// alternatives.
+func hasAlternatives(deps []v1beta1.Dependency) bool {
+	return slices.ContainsFunc(deps, func(d v1beta1.Dependency) bool { return len(d.Alternatives) > 0 })
+}
+
+// isHealthy returns true if the package revision that added the supplied
+// package to the lock is healthy.
+func (m *PackageDependencyManager) isHealthy(ctx context.Context, lp *v1beta1.LockPackage) (bool, error) {
+	pr, err := newPackageRevision(lp.Type)
+	if err != nil {
+		return false, err
+	}
+	if err := m.client.Get(ctx, types.NamespacedName{Name: lp.Name}, pr); err != nil {
+		return false, resource.IgnoreNotFound(err)
+	}
+	return pr.GetCondition(v1.TypeHealthy).Status == corev1.ConditionTrue, nil
+}
+
+// definesAPI returns true if a package revision in the supplied lock packages,
+// other than the named one, defines the supplied API. A revision defines an
+// API if it established a CRD or XRD in the API's group, and the CRD (or the
+// CRD the XRD defined) serves the API's version.
+func (m *PackageDependencyManager) definesAPI(ctx context.Context, pkgs []v1beta1.LockPackage, self string, gv schema.GroupVersion) (bool, error) {
+	for _, lp := range pkgs {
+		if lp.Name == self {
+			continue
+		}
+		pr, err := newPackageRevision(lp.Type)
+		if err != nil {
+			return false, err
+		}
+		if err := m.client.Get(ctx, types.NamespacedName{Name: lp.Name}, pr); err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return false, errors.Wrap(err, errGetDependencyRevision)
+		}
+		for _, ref := range pr.GetObjects() {
+			if ref.Kind != kindCRD && ref.Kind != apiextensionsv1.CompositeResourceDefinitionKind {
+				continue
+			}
+			// CRDs and XRDs are named <plural>.<group>.
+			if !strings.HasSuffix(ref.Name, "."+gv.Group) {
+				continue
+			}
+			crd := &extv1.CustomResourceDefinition{}
+			if err := m.client.Get(ctx, types.NamespacedName{Name: ref.Name}, crd); err != nil {
+				if kerrors.IsNotFound(err) {
+					continue
+				}
+				return false, errors.Wrap(err, errGetDependencyCRD)
+			}
+			if crd.Spec.Group != gv.Group {
+				continue
+			}
+			for _, v := range crd.Spec.Versions {
+				if v.Name == gv.Version && v.Served {
+					return true, nil
+				}
+			}
+		}
+	}
+	return false, nil
+}
+
+// dependencyEdges returns the edges of the supplied package in the dependency
+// graph described by the supplied lock packages. It returns the packages the
+// supplied package depends on, and the packages that depend on it. Each edge
+// includes the version of the related package that's in the lock, if any.
+func dependencyEdges(self v1beta1.LockPackage, pkgs []v1beta1.LockPackage) (dependsOn, installedBy []v1.DependencyEdge) {
+	versions := make(map[string]string, len(pkgs))
+	for _, lp := range pkgs {
+		versions[lp.Identifier()] = lp.Version
+	}
+
+	for _, dep := range self.Dependencies {
+		dependsOn = append(dependsOn, v1.DependencyEdge{
+			Package:     dep.Package,
+			Type:        string(dep.Type),
+			Version:     versions[dep.Identifier()],
+			Constraints: dep.Constraints,
+		})
+	}
+
+	for _, lp := range pkgs {
+		if lp.Name == self.Name {
+			continue
+		}
+		for _, dep := range lp.Dependencies {
+			if dep.Identifier() != self.Identifier() {
+				continue
+			}
+			installedBy = append(installedBy, v1.DependencyEdge{
+				Package:     lp.Source,
+				Type:        string(lp.Type),
+				Version:     lp.Version,
+				Constraints: dep.Constraints,
+			})
+			break
+		}
+	}
+
+	return dependsOn, installedBy
+}
+
+// newPackageRevision returns a new package revision of the supplied type.
+func newPackageRevision(t v1beta1.PackageType) (v1.PackageRevision, error) {
+	switch t {
+	case v1beta1.ConfigurationPackageType:
+		return &v1.ConfigurationRevision{}, nil
+	case v1beta1.ProviderPackageType:
+		return &v1.ProviderRevision{}, nil
+	case v1beta1.FunctionPackageType:
+		return &v1.FunctionRevision{}, nil
+	}
+	return nil, errors.Errorf(errFmtUnknownDependencyType, t)
+}
+
+// ProviderGroups returns the API groups of the CRDs established by the
+// installed providers that the supplied package revision depends on, according
+// to the lock.
+func (m *PackageDependencyManager) ProviderGroups(ctx context.Context, pr v1.PackageRevision) ([]string, error) {
+	lock := &v1beta1.Lock{}
+	if err := m.client.Get(ctx, types.NamespacedName{Name: lockName}, lock); err != nil {
+		return nil, errors.Wrap(resource.IgnoreNotFound(err), errGetLock)
+	}
+
+	var providers []string
+	for _, lp := range lock.Packages {
+		if lp.Name != pr.GetName() {
+			continue
+		}
+		for _, dep := range lp.Dependencies {
+			for _, c := range dep.Candidates() {
+				if c.Type == v1beta1.ProviderPackageType {
+					providers = append(providers, c.Package)
+				}
+			}
+		}
+	}
+
+	var groups []string
+	for _, lp := range lock.Packages {
+		if lp.Type != v1beta1.ProviderPackageType || !slices.Contains(providers, lp.Source) {
+			continue
+		}
+		prv := &v1.ProviderRevision{}
+		if err := m.client.Get(ctx, types.NamespacedName{Name: lp.Name}, prv); err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return nil, errors.Wrap(err, errGetDependencyRevision)
+		}
+		for _, ref := range prv.GetObjects() {
+			if ref.Kind != kindCRD {
+				continue
+			}
+			// CRDs are named <plural>.<group>.
+			if _, g, ok := strings.Cut(ref.Name, "."); ok && !slices.Contains(groups, g) {
+				groups = append(groups, g)
+			}
+		}
+	}
+	return groups, nil
+}
+
 // RemoveSelf removes a package from the lock.
 func (m *PackageDependencyManager) RemoveSelf(ctx context.Context, pr v1.PackageRevision) error {
 	// Get the lock.
@@ -268,3 +577,16 @@ func (m *PackageDependencyManager) RemoveSelf(ctx context.Context, pr v1.Package
 	}
 	return nil
 }
+
+// pinnedDigest returns the digest the supplied package source is pinned to, or
+// an empty string if it isn't pinned to a digest.
+func pinnedDigest(source string) string {
+	ref, err := name.ParseReference(source, name.WithDefaultRegistry(""))
+	if err != nil {
+		return ""
+	}
+	if d, ok := ref.(name.Digest); ok {
+		return d.DigestStr()
+	}
+	return ""
+}
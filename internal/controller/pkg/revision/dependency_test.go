@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -28,6 +29,7 @@ import (
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
@@ -67,9 +69,11 @@ func TestResolve(t *testing.T) {
 			args: args{
 				meta: &pkgmetav1.Configuration{},
 				pr: &v1.ConfigurationRevision{
-					Spec: v1.PackageRevisionSpec{
-						Package:      "hasheddan/config-nop-a:v0.0.1",
-						DesiredState: v1.PackageRevisionInactive,
+					Spec: v1.ConfigurationRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:      "hasheddan/config-nop-a:v0.0.1",
+							DesiredState: v1.PackageRevisionInactive,
+						},
 					},
 				},
 			},
@@ -81,8 +85,10 @@ func TestResolve(t *testing.T) {
 				dep:  &PackageDependencyManager{},
 				meta: &v1.Configuration{},
 				pr: &v1.ConfigurationRevision{
-					Spec: v1.PackageRevisionSpec{
-						DesiredState: v1.PackageRevisionActive,
+					Spec: v1.ConfigurationRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							DesiredState: v1.PackageRevisionActive,
+						},
 					},
 				},
 			},
@@ -100,8 +106,10 @@ func TestResolve(t *testing.T) {
 				},
 				meta: &pkgmetav1.Configuration{},
 				pr: &v1.ConfigurationRevision{
-					Spec: v1.PackageRevisionSpec{
-						DesiredState: v1.PackageRevisionActive,
+					Spec: v1.ConfigurationRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							DesiredState: v1.PackageRevisionActive,
+						},
 					},
 				},
 			},
@@ -120,8 +128,10 @@ func TestResolve(t *testing.T) {
 				},
 				meta: &pkgmetav1.Configuration{},
 				pr: &v1.ConfigurationRevision{
-					Spec: v1.PackageRevisionSpec{
-						DesiredState: v1.PackageRevisionActive,
+					Spec: v1.ConfigurationRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							DesiredState: v1.PackageRevisionActive,
+						},
 					},
 				},
 			},
@@ -147,8 +157,10 @@ func TestResolve(t *testing.T) {
 				},
 				meta: &pkgmetav1.Configuration{},
 				pr: &v1.ConfigurationRevision{
-					Spec: v1.PackageRevisionSpec{
-						Package: "hasheddan/config-nop-a:v0.0.1",
+					Spec: v1.ConfigurationRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package: "hasheddan/config-nop-a:v0.0.1",
+						},
 					},
 				},
 			},
@@ -188,9 +200,216 @@ func TestResolve(t *testing.T) {
 					ObjectMeta: metav1.ObjectMeta{
 						Name: "config-nop-a-abc123",
 					},
-					Spec: v1.PackageRevisionSpec{
-						Package:      "hasheddan/config-nop-a:v0.0.1",
-						DesiredState: v1.PackageRevisionActive,
+					Spec: v1.ConfigurationRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:      "hasheddan/config-nop-a:v0.0.1",
+							DesiredState: v1.PackageRevisionActive,
+						},
+					},
+				},
+			},
+			want: want{},
+		},
+		"SuccessfulAPIDependency": {
+			reason: "Should not return error if another installed package defines an API we depend on, and should record the dependency in the lock.",
+			args: args{
+				dep: &PackageDependencyManager{
+					client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							switch o := obj.(type) {
+							case *v1beta1.Lock:
+								o.Packages = []v1beta1.LockPackage{
+									{
+										Name:   "config-nop-a-abc123",
+										Source: "hasheddan/config-nop-a",
+									},
+									{
+										Name:   "config-db-abc123",
+										Source: "hasheddan/config-db",
+										Type:   v1beta1.ConfigurationPackageType,
+									},
+								}
+							case *v1.ConfigurationRevision:
+								o.SetObjects([]xpv1.TypedReference{
+									{
+										Kind: "CompositeResourceDefinition",
+										Name: "xdatabases.databases.example.org",
+									},
+								})
+							case *extv1.CustomResourceDefinition:
+								o.Spec.Group = "databases.example.org"
+								o.Spec.Versions = []extv1.CustomResourceDefinitionVersion{
+									{Name: "v1alpha1", Served: true},
+								}
+							}
+							return nil
+						}),
+						MockUpdate: test.NewMockUpdateFn(nil, func(obj client.Object) error {
+							if diff := cmp.Diff([]string{"databases.example.org/v1alpha1"}, obj.(*v1beta1.Lock).Packages[0].APIs); diff != "" {
+								t.Errorf("-want APIs, +got APIs:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+					newDag: func() dag.DAG {
+						return &dagfake.MockDag{
+							MockInit: func(_ []dag.Node) ([]dag.Node, error) {
+								return nil, nil
+							},
+							MockTraceNode: func(_ string) (map[string]dag.Node, error) {
+								return nil, nil
+							},
+						}
+					},
+				},
+				meta: &pkgmetav1.Configuration{
+					Spec: pkgmetav1.ConfigurationSpec{
+						MetaSpec: pkgmetav1.MetaSpec{
+							DependsOn: []pkgmetav1.Dependency{
+								{
+									API: ptr.To("databases.example.org/v1alpha1"),
+								},
+							},
+						},
+					},
+				},
+				pr: &v1.ConfigurationRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "config-nop-a-abc123",
+					},
+					Spec: v1.ConfigurationRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:      "hasheddan/config-nop-a:v0.0.1",
+							DesiredState: v1.PackageRevisionActive,
+						},
+					},
+				},
+			},
+			want: want{
+				total:     1,
+				installed: 1,
+			},
+		},
+		"ErrorMissingAPIDependency": {
+			reason: "Should return error if no other installed package defines an API we depend on.",
+			args: args{
+				dep: &PackageDependencyManager{
+					client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							switch o := obj.(type) {
+							case *v1beta1.Lock:
+								o.Packages = []v1beta1.LockPackage{
+									{
+										Name:   "config-nop-a-abc123",
+										Source: "hasheddan/config-nop-a",
+										APIs:   []string{"databases.example.org/v1beta1"},
+									},
+									{
+										Name:   "config-db-abc123",
+										Source: "hasheddan/config-db",
+										Type:   v1beta1.ConfigurationPackageType,
+									},
+								}
+							case *v1.ConfigurationRevision:
+								o.SetObjects([]xpv1.TypedReference{
+									{
+										Kind: "CompositeResourceDefinition",
+										Name: "xdatabases.databases.example.org",
+									},
+								})
+							case *extv1.CustomResourceDefinition:
+								o.Spec.Group = "databases.example.org"
+								o.Spec.Versions = []extv1.CustomResourceDefinitionVersion{
+									{Name: "v1alpha1", Served: true},
+								}
+							}
+							return nil
+						}),
+					},
+					newDag: func() dag.DAG {
+						return &dagfake.MockDag{
+							MockInit: func(_ []dag.Node) ([]dag.Node, error) {
+								return nil, nil
+							},
+							MockTraceNode: func(_ string) (map[string]dag.Node, error) {
+								return nil, nil
+							},
+						}
+					},
+				},
+				meta: &pkgmetav1.Configuration{
+					Spec: pkgmetav1.ConfigurationSpec{
+						MetaSpec: pkgmetav1.MetaSpec{
+							DependsOn: []pkgmetav1.Dependency{
+								{
+									API: ptr.To("databases.example.org/v1beta1"),
+								},
+							},
+						},
+					},
+				},
+				pr: &v1.ConfigurationRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "config-nop-a-abc123",
+					},
+					Spec: v1.ConfigurationRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:      "hasheddan/config-nop-a:v0.0.1",
+							DesiredState: v1.PackageRevisionActive,
+						},
+					},
+				},
+			},
+			want: want{
+				total:     1,
+				installed: 0,
+				err:       errors.Errorf(errFmtMissingAPIs, "databases.example.org/v1beta1"),
+			},
+		},
+		"SuccessfulSelfNotExistPinnedDigest": {
+			reason: "Should add self to the lock with the version and digest it's pinned to if self does not exist.",
+			args: args{
+				dep: &PackageDependencyManager{
+					client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockUpdate: test.NewMockUpdateFn(nil, func(obj client.Object) error {
+							want := []v1beta1.LockPackage{{
+								Name:         "config-nop-a-abc123",
+								Type:         v1beta1.ConfigurationPackageType,
+								Source:       "hasheddan/config-nop-a",
+								Version:      "v0.0.1",
+								Digest:       "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904",
+								Dependencies: []v1beta1.Dependency{},
+							}}
+							if diff := cmp.Diff(want, obj.(*v1beta1.Lock).Packages); diff != "" {
+								t.Errorf("-want, +got:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+					newDag: func() dag.DAG {
+						return &dagfake.MockDag{
+							MockInit: func(_ []dag.Node) ([]dag.Node, error) {
+								return nil, nil
+							},
+							MockAddOrUpdateNodes: func(_ ...dag.Node) {},
+							MockTraceNode: func(_ string) (map[string]dag.Node, error) {
+								return nil, nil
+							},
+						}
+					},
+					packageType: v1beta1.ConfigurationPackageType,
+				},
+				meta: &pkgmetav1.Configuration{},
+				pr: &v1.ConfigurationRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "config-nop-a-abc123",
+					},
+					Spec: v1.ConfigurationRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:      "hasheddan/config-nop-a:v0.0.1@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904",
+							DesiredState: v1.PackageRevisionActive,
+						},
 					},
 				},
 			},
@@ -239,9 +458,11 @@ func TestResolve(t *testing.T) {
 					ObjectMeta: metav1.ObjectMeta{
 						Name: "config-nop-a-abc123",
 					},
-					Spec: v1.PackageRevisionSpec{
-						Package:      "hasheddan/config-nop-a:v0.0.1",
-						DesiredState: v1.PackageRevisionActive,
+					Spec: v1.ConfigurationRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:      "hasheddan/config-nop-a:v0.0.1",
+							DesiredState: v1.PackageRevisionActive,
+						},
 					},
 				},
 			},
@@ -326,9 +547,11 @@ func TestResolve(t *testing.T) {
 					ObjectMeta: metav1.ObjectMeta{
 						Name: "config-nop-a-abc123",
 					},
-					Spec: v1.PackageRevisionSpec{
-						Package:      "hasheddan/config-nop-a:v0.0.1",
-						DesiredState: v1.PackageRevisionActive,
+					Spec: v1.ConfigurationRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:      "hasheddan/config-nop-a:v0.0.1",
+							DesiredState: v1.PackageRevisionActive,
+						},
 					},
 				},
 			},
@@ -424,9 +647,11 @@ func TestResolve(t *testing.T) {
 					ObjectMeta: metav1.ObjectMeta{
 						Name: "config-nop-a-abc123",
 					},
-					Spec: v1.PackageRevisionSpec{
-						Package:      "hasheddan/config-nop-a:v0.0.1",
-						DesiredState: v1.PackageRevisionActive,
+					Spec: v1.ConfigurationRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:      "hasheddan/config-nop-a:v0.0.1",
+							DesiredState: v1.PackageRevisionActive,
+						},
 					},
 				},
 			},
@@ -434,11 +659,11 @@ func TestResolve(t *testing.T) {
 				total:     3,
 				installed: 3,
 				invalid:   2,
-				err:       errors.Errorf(errFmtIncompatibleDependency, "existing package not-here-1@v0.0.1 is incompatible with constraint >=v0.1.0; existing package not-here-2@v0.0.1 is incompatible with constraint >=v0.1.0"),
+				err:       errors.Errorf(errFmtIncompatibleDependency, "existing package not-here-1@v0.0.1 is incompatible with constraint >=v0.1.0; existing package not-here-2@v0.0.1 is incompatible with constraint >=v0.1.0", errUpgradeManually),
 			},
 		},
-		"SuccessfulSelfExistValidDependencies": {
-			reason: "Should not return error if self exists, all dependencies exist and are valid.",
+		"SuccessfulSelfExistChannelDependencies": {
+			reason: "Should not return error if dependencies that track a channel are installed at any version.",
 			args: args{
 				dep: &PackageDependencyManager{
 					client: &test.MockClient{
@@ -457,10 +682,6 @@ func TestResolve(t *testing.T) {
 											Package: "not-here-2",
 											Type:    v1beta1.ConfigurationPackageType,
 										},
-										{
-											Package: "function-not-here-1",
-											Type:    v1beta1.FunctionPackageType,
-										},
 									},
 								},
 								{
@@ -487,32 +708,24 @@ func TestResolve(t *testing.T) {
 							},
 							MockTraceNode: func(_ string) (map[string]dag.Node, error) {
 								return map[string]dag.Node{
-									"not-here-1":          &v1beta1.Dependency{},
-									"not-here-2":          &v1beta1.Dependency{},
-									"not-here-3":          &v1beta1.Dependency{},
-									"function-not-here-1": &v1beta1.Dependency{},
+									"not-here-1": &v1beta1.Dependency{},
+									"not-here-2": &v1beta1.Dependency{},
+									"not-here-3": &v1beta1.Dependency{},
 								}, nil
 							},
 							MockGetNode: func(s string) (dag.Node, error) {
 								if s == "not-here-1" {
 									return &v1beta1.LockPackage{
 										Source:  "not-here-1",
-										Version: "v0.20.0",
+										Version: "v0.0.1",
 									}, nil
 								}
 								if s == "not-here-2" {
 									return &v1beta1.LockPackage{
 										Source:  "not-here-2",
-										Version: "v0.100.1",
-									}, nil
-								}
-								if s == "function-not-here-1" {
-									return &v1beta1.LockPackage{
-										Source:  "function-not-here-1",
-										Version: "v0.1.0",
+										Version: "v0.0.1",
 									}, nil
 								}
-
 								return nil, nil
 							},
 						}
@@ -524,15 +737,11 @@ func TestResolve(t *testing.T) {
 							DependsOn: []pkgmetav1.Dependency{
 								{
 									Provider: ptr.To("not-here-1"),
-									Version:  ">=v0.1.0",
+									Channel:  ptr.To("stable"),
 								},
 								{
 									Provider: ptr.To("not-here-2"),
-									Version:  ">=v0.1.0",
-								},
-								{
-									Function: ptr.To("function-not-here-1"),
-									Version:  ">=v0.1.0",
+									Channel:  ptr.To("stable"),
 								},
 							},
 						},
@@ -542,94 +751,784 @@ func TestResolve(t *testing.T) {
 					ObjectMeta: metav1.ObjectMeta{
 						Name: "config-nop-a-abc123",
 					},
-					Spec: v1.PackageRevisionSpec{
-						Package:      "hasheddan/config-nop-a:v0.0.1",
-						DesiredState: v1.PackageRevisionActive,
+					Spec: v1.ConfigurationRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:      "hasheddan/config-nop-a:v0.0.1",
+							DesiredState: v1.PackageRevisionActive,
+						},
 					},
 				},
 			},
 			want: want{
-				total:     4,
-				installed: 4,
+				total:     3,
+				installed: 3,
 				invalid:   0,
 			},
 		},
-		"SuccessfulLockPackageSourceMismatch": {
-			reason: "Should not return error if source in packages does not match provider revision package.",
+		"SuccessfulSelfExistAlternativeInstalled": {
+			reason: "Should record that a dependency is satisfied by an installed alternative, and not return error.",
 			args: args{
 				dep: &PackageDependencyManager{
 					client: &test.MockClient{
 						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
 							l := obj.(*v1beta1.Lock)
-							if mockUpdateCallCount < 1 {
-								l.Packages = []v1beta1.LockPackage{
-									{
-										Name: "config-nop-a-abc123",
-										// Source mistmatch provider revision package
-										Source: "hasheddan/config-nop-b",
+							l.Packages = []v1beta1.LockPackage{
+								{
+									Name:   "config-nop-a-abc123",
+									Source: "hasheddan/config-nop-a",
+									Dependencies: []v1beta1.Dependency{
+										{
+											Package:     "provider-aws",
+											Type:        v1beta1.ProviderPackageType,
+											Constraints: ">=v0.1.0",
+											Alternatives: []v1beta1.DependencyAlternative{
+												{Package: "provider-gcp", Type: v1beta1.ProviderPackageType, Constraints: ">=v0.1.0"},
+											},
+										},
 									},
-								}
-							} else {
-								l.Packages = []v1beta1.LockPackage{}
+								},
+								{
+									Source:  "provider-gcp",
+									Type:    v1beta1.ProviderPackageType,
+									Version: "v0.2.0",
+								},
 							}
 							return nil
 						}),
-						MockUpdate: func(_ context.Context, _ client.Object, _ ...client.UpdateOption) error {
-							mockUpdateCallCount++
+						MockUpdate: test.NewMockUpdateFn(nil, func(obj client.Object) error {
+							want := []v1beta1.Dependency{{
+								Package:     "provider-gcp",
+								Type:        v1beta1.ProviderPackageType,
+								Constraints: ">=v0.1.0",
+								Alternatives: []v1beta1.DependencyAlternative{
+									{Package: "provider-aws", Type: v1beta1.ProviderPackageType, Constraints: ">=v0.1.0"},
+								},
+							}}
+							if diff := cmp.Diff(want, obj.(*v1beta1.Lock).Packages[0].Dependencies); diff != "" {
+								t.Errorf("Update(...): -want dependencies, +got dependencies:\n%s", diff)
+							}
 							return nil
-						},
+						}),
 					},
 					newDag: func() dag.DAG {
 						return &dagfake.MockDag{
 							MockInit: func(_ []dag.Node) ([]dag.Node, error) {
-								return []dag.Node{}, nil
-							},
-							MockTraceNode: func(s string) (map[string]dag.Node, error) {
-								if s == "hasheddan/config-nop-a" {
-									return map[string]dag.Node{
-										s: &v1beta1.Dependency{},
-									}, nil
-								}
-								return nil, errors.New("missing node in tree")
+								return nil, nil
 							},
 							MockAddOrUpdateNodes: func(_ ...dag.Node) {},
+							MockTraceNode: func(_ string) (map[string]dag.Node, error) {
+								return map[string]dag.Node{
+									"provider-gcp": &v1beta1.Dependency{},
+								}, nil
+							},
+							MockGetNode: func(_ string) (dag.Node, error) {
+								return &v1beta1.LockPackage{
+									Source:  "provider-gcp",
+									Version: "v0.2.0",
+								}, nil
+							},
 						}
 					},
 				},
-				meta: &pkgmetav1.Configuration{},
+				meta: &pkgmetav1.Configuration{
+					Spec: pkgmetav1.ConfigurationSpec{
+						MetaSpec: pkgmetav1.MetaSpec{
+							DependsOn: []pkgmetav1.Dependency{
+								{
+									AnyOf: []pkgmetav1.AlternativeDependency{
+										{Provider: ptr.To("provider-aws"), Version: ">=v0.1.0"},
+										{Provider: ptr.To("provider-gcp"), Version: ">=v0.1.0"},
+									},
+								},
+							},
+						},
+					},
+				},
 				pr: &v1.ConfigurationRevision{
 					ObjectMeta: metav1.ObjectMeta{
 						Name: "config-nop-a-abc123",
 					},
-					Spec: v1.PackageRevisionSpec{
-						Package:      "hasheddan/config-nop-a:v0.0.1",
-						DesiredState: v1.PackageRevisionActive,
+					Spec: v1.ConfigurationRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:      "hasheddan/config-nop-a:v0.0.1",
+							DesiredState: v1.PackageRevisionActive,
+						},
 					},
 				},
 			},
 			want: want{
 				total:     1,
 				installed: 1,
+				invalid:   0,
 			},
 		},
-	}
-
-	for name, tc := range cases {
-		mockUpdateCallCount = 0
-		t.Run(name, func(t *testing.T) {
-			total, installed, invalid, err := tc.args.dep.Resolve(context.TODO(), tc.args.meta, tc.args.pr)
-
-			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
-				t.Errorf("\n%s\np.Resolve(...): -want error, +got error:\n%s", tc.reason, diff)
-			}
-			if diff := cmp.Diff(tc.want.total, total); diff != "" {
-				t.Errorf("\n%s\nTotal(...): -want, +got:\n%s", tc.reason, diff)
-			}
-			if diff := cmp.Diff(tc.want.installed, installed); diff != "" {
-				t.Errorf("\n%s\nInstalled(...): -want, +got:\n%s", tc.reason, diff)
-			}
-			if diff := cmp.Diff(tc.want.invalid, invalid); diff != "" {
-				t.Errorf("\n%s\nInvalid(...): -want, +got:\n%s", tc.reason, diff)
+		"ErrorSelfExistInvalidDependenciesAutomaticUpgrade": {
+			reason: "Should say the package manager upgrades invalid dependencies if the dependency upgrade policy is Automatic.",
+			args: args{
+				dep: &PackageDependencyManager{
+					client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							l := obj.(*v1beta1.Lock)
+							l.Packages = []v1beta1.LockPackage{
+								{
+									Name:   "config-nop-a-abc123",
+									Source: "hasheddan/config-nop-a",
+									Dependencies: []v1beta1.Dependency{
+										{
+											Package: "not-here-1",
+											Type:    v1beta1.ProviderPackageType,
+										},
+										{
+											Package: "not-here-2",
+											Type:    v1beta1.ConfigurationPackageType,
+										},
+									},
+								},
+								{
+									Source: "not-here-1",
+									Dependencies: []v1beta1.Dependency{
+										{
+											Package: "not-here-3",
+											Type:    v1beta1.ProviderPackageType,
+										},
+									},
+								},
+							}
+							return nil
+						}),
+						MockUpdate: test.NewMockUpdateFn(nil),
+					},
+					newDag: func() dag.DAG {
+						return &dagfake.MockDag{
+							MockInit: func(_ []dag.Node) ([]dag.Node, error) {
+								return nil, nil
+							},
+							MockTraceNode: func(_ string) (map[string]dag.Node, error) {
+								return map[string]dag.Node{
+									"not-here-1": &v1beta1.Dependency{},
+									"not-here-2": &v1beta1.Dependency{},
+									"not-here-3": &v1beta1.Dependency{},
+								}, nil
+							},
+							MockGetNode: func(s string) (dag.Node, error) {
+								if s == "not-here-1" {
+									return &v1beta1.LockPackage{
+										Source:  "not-here-1",
+										Version: "v0.0.1",
+									}, nil
+								}
+								if s == "not-here-2" {
+									return &v1beta1.LockPackage{
+										Source:  "not-here-2",
+										Version: "v0.0.1",
+									}, nil
+								}
+								return nil, nil
+							},
+						}
+					},
+				},
+				meta: &pkgmetav1.Configuration{
+					Spec: pkgmetav1.ConfigurationSpec{
+						MetaSpec: pkgmetav1.MetaSpec{
+							DependsOn: []pkgmetav1.Dependency{
+								{
+									Provider: ptr.To("not-here-1"),
+									Version:  ">=v0.1.0",
+								},
+								{
+									Provider: ptr.To("not-here-2"),
+									Version:  ">=v0.1.0",
+								},
+							},
+						},
+					},
+				},
+				pr: &v1.ConfigurationRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "config-nop-a-abc123",
+					},
+					Spec: v1.ConfigurationRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:                 "hasheddan/config-nop-a:v0.0.1",
+							DesiredState:            v1.PackageRevisionActive,
+							DependencyUpgradePolicy: &v1.AutomaticDependencyUpgrade,
+						},
+					},
+				},
+			},
+			want: want{
+				total:     3,
+				installed: 3,
+				invalid:   2,
+				err:       errors.Errorf(errFmtIncompatibleDependency, "existing package not-here-1@v0.0.1 is incompatible with constraint >=v0.1.0; existing package not-here-2@v0.0.1 is incompatible with constraint >=v0.1.0", errUpgradeAutomatically),
+			},
+		},
+		"ErrorDependencyCycle": {
+			reason: "Should return a cycle error if the package is its own transitive dependency.",
+			args: args{
+				dep: &PackageDependencyManager{
+					client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							l := obj.(*v1beta1.Lock)
+							l.Packages = []v1beta1.LockPackage{
+								{
+									Name:    "config-nop-a-abc123",
+									Type:    v1beta1.ConfigurationPackageType,
+									Source:  "hasheddan/config-nop-a",
+									Version: "v0.0.1",
+									Dependencies: []v1beta1.Dependency{{
+										Package:     "hasheddan/config-nop-b",
+										Type:        v1beta1.ConfigurationPackageType,
+										Constraints: ">=v0.0.1",
+									}},
+								},
+								{
+									Name:    "config-nop-b-abc123",
+									Type:    v1beta1.ConfigurationPackageType,
+									Source:  "hasheddan/config-nop-b",
+									Version: "v0.0.1",
+									Dependencies: []v1beta1.Dependency{{
+										Package:     "hasheddan/config-nop-a",
+										Type:        v1beta1.ConfigurationPackageType,
+										Constraints: ">=v0.0.1",
+									}},
+								},
+							}
+							return nil
+						}),
+						MockUpdate: test.NewMockUpdateFn(nil),
+					},
+					newDag: dag.NewMapDag,
+				},
+				meta: &pkgmetav1.Configuration{
+					Spec: pkgmetav1.ConfigurationSpec{
+						MetaSpec: pkgmetav1.MetaSpec{
+							DependsOn: []pkgmetav1.Dependency{{
+								Configuration: ptr.To("hasheddan/config-nop-b"),
+								Version:       ">=v0.0.1",
+							}},
+						},
+					},
+				},
+				pr: &v1.ConfigurationRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "config-nop-a-abc123",
+					},
+					Spec: v1.ConfigurationRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:      "hasheddan/config-nop-a:v0.0.1",
+							DesiredState: v1.PackageRevisionActive,
+						},
+					},
+				},
+			},
+			want: want{
+				total: 1,
+				err:   &dag.CycleError{Cycle: []string{"hasheddan/config-nop-a", "hasheddan/config-nop-b", "hasheddan/config-nop-a"}},
+			},
+		},
+		"SuccessfulSelfExistValidDependencies": {
+			reason: "Should not return error if self exists, all dependencies exist and are valid.",
+			args: args{
+				dep: &PackageDependencyManager{
+					client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							l := obj.(*v1beta1.Lock)
+							l.Packages = []v1beta1.LockPackage{
+								{
+									Name:   "config-nop-a-abc123",
+									Source: "hasheddan/config-nop-a",
+									Dependencies: []v1beta1.Dependency{
+										{
+											Package: "not-here-1",
+											Type:    v1beta1.ProviderPackageType,
+										},
+										{
+											Package: "not-here-2",
+											Type:    v1beta1.ConfigurationPackageType,
+										},
+										{
+											Package: "function-not-here-1",
+											Type:    v1beta1.FunctionPackageType,
+										},
+									},
+								},
+								{
+									Source: "not-here-1",
+									Dependencies: []v1beta1.Dependency{
+										{
+											Package: "not-here-3",
+											Type:    v1beta1.ProviderPackageType,
+										},
+									},
+								},
+							}
+							return nil
+						}),
+						MockUpdate: test.NewMockUpdateFn(nil),
+					},
+					newDag: func() dag.DAG {
+						return &dagfake.MockDag{
+							MockInit: func(_ []dag.Node) ([]dag.Node, error) {
+								return nil, nil
+							},
+							MockNodeExists: func(_ string) bool {
+								return true
+							},
+							MockTraceNode: func(_ string) (map[string]dag.Node, error) {
+								return map[string]dag.Node{
+									"not-here-1":          &v1beta1.Dependency{},
+									"not-here-2":          &v1beta1.Dependency{},
+									"not-here-3":          &v1beta1.Dependency{},
+									"function-not-here-1": &v1beta1.Dependency{},
+								}, nil
+							},
+							MockGetNode: func(s string) (dag.Node, error) {
+								if s == "not-here-1" {
+									return &v1beta1.LockPackage{
+										Source:  "not-here-1",
+										Version: "v0.20.0",
+									}, nil
+								}
+								if s == "not-here-2" {
+									return &v1beta1.LockPackage{
+										Source:  "not-here-2",
+										Version: "v0.100.1",
+									}, nil
+								}
+								if s == "function-not-here-1" {
+									return &v1beta1.LockPackage{
+										Source:  "function-not-here-1",
+										Version: "v0.1.0",
+									}, nil
+								}
+
+								return nil, nil
+							},
+						}
+					},
+				},
+				meta: &pkgmetav1.Configuration{
+					Spec: pkgmetav1.ConfigurationSpec{
+						MetaSpec: pkgmetav1.MetaSpec{
+							DependsOn: []pkgmetav1.Dependency{
+								{
+									Provider: ptr.To("not-here-1"),
+									Version:  ">=v0.1.0",
+								},
+								{
+									Provider: ptr.To("not-here-2"),
+									Version:  ">=v0.1.0",
+								},
+								{
+									Function: ptr.To("function-not-here-1"),
+									Version:  ">=v0.1.0",
+								},
+							},
+						},
+					},
+				},
+				pr: &v1.ConfigurationRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "config-nop-a-abc123",
+					},
+					Spec: v1.ConfigurationRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:      "hasheddan/config-nop-a:v0.0.1",
+							DesiredState: v1.PackageRevisionActive,
+						},
+					},
+				},
+			},
+			want: want{
+				total:     4,
+				installed: 4,
+				invalid:   0,
+			},
+		},
+		"ErrorSelfExistUnhealthyDependencies": {
+			reason: "Should return error if healthy dependencies are required and a dependency is not healthy.",
+			args: args{
+				dep: &PackageDependencyManager{
+					requireHealthy: true,
+					client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							switch o := obj.(type) {
+							case *v1beta1.Lock:
+								o.Packages = []v1beta1.LockPackage{
+									{
+										Name:   "config-nop-a-abc123",
+										Source: "hasheddan/config-nop-a",
+										Dependencies: []v1beta1.Dependency{
+											{
+												Package: "not-here-1",
+												Type:    v1beta1.ProviderPackageType,
+											},
+										},
+									},
+									{
+										Name:   "not-here-1-abc123",
+										Source: "not-here-1",
+										Type:   v1beta1.ProviderPackageType,
+									},
+								}
+							case *v1.ProviderRevision:
+								o.SetConditions(v1.Unhealthy())
+							}
+							return nil
+						}),
+						MockUpdate: test.NewMockUpdateFn(nil),
+					},
+					newDag: func() dag.DAG {
+						return &dagfake.MockDag{
+							MockInit: func(_ []dag.Node) ([]dag.Node, error) {
+								return nil, nil
+							},
+							MockNodeExists: func(_ string) bool {
+								return true
+							},
+							MockTraceNode: func(_ string) (map[string]dag.Node, error) {
+								return map[string]dag.Node{
+									"not-here-1": &v1beta1.Dependency{},
+								}, nil
+							},
+							MockGetNode: func(_ string) (dag.Node, error) {
+								return &v1beta1.LockPackage{
+									Name:    "not-here-1-abc123",
+									Source:  "not-here-1",
+									Type:    v1beta1.ProviderPackageType,
+									Version: "v0.20.0",
+								}, nil
+							},
+						}
+					},
+				},
+				meta: &pkgmetav1.Configuration{
+					Spec: pkgmetav1.ConfigurationSpec{
+						MetaSpec: pkgmetav1.MetaSpec{
+							DependsOn: []pkgmetav1.Dependency{
+								{
+									Provider: ptr.To("not-here-1"),
+									Version:  ">=v0.1.0",
+								},
+							},
+						},
+					},
+				},
+				pr: &v1.ConfigurationRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "config-nop-a-abc123",
+					},
+					Spec: v1.ConfigurationRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:      "hasheddan/config-nop-a:v0.0.1",
+							DesiredState: v1.PackageRevisionActive,
+						},
+					},
+				},
+			},
+			want: want{
+				total:     1,
+				installed: 1,
+				err:       errors.Errorf(errFmtUnhealthyDependencies, "not-here-1"),
+			},
+		},
+		"SuccessfulLockPackageSourceMismatch": {
+			reason: "Should not return error if source in packages does not match provider revision package.",
+			args: args{
+				dep: &PackageDependencyManager{
+					client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							l := obj.(*v1beta1.Lock)
+							if mockUpdateCallCount < 1 {
+								l.Packages = []v1beta1.LockPackage{
+									{
+										Name: "config-nop-a-abc123",
+										// Source mistmatch provider revision package
+										Source: "hasheddan/config-nop-b",
+									},
+								}
+							} else {
+								l.Packages = []v1beta1.LockPackage{}
+							}
+							return nil
+						}),
+						MockUpdate: func(_ context.Context, _ client.Object, _ ...client.UpdateOption) error {
+							mockUpdateCallCount++
+							return nil
+						},
+					},
+					newDag: func() dag.DAG {
+						return &dagfake.MockDag{
+							MockInit: func(_ []dag.Node) ([]dag.Node, error) {
+								return []dag.Node{}, nil
+							},
+							MockTraceNode: func(s string) (map[string]dag.Node, error) {
+								if s == "hasheddan/config-nop-a" {
+									return map[string]dag.Node{
+										"hasheddan/provider-nop": &v1beta1.Dependency{},
+									}, nil
+								}
+								return nil, errors.New("missing node in tree")
+							},
+							MockAddOrUpdateNodes: func(_ ...dag.Node) {},
+						}
+					},
+				},
+				meta: &pkgmetav1.Configuration{},
+				pr: &v1.ConfigurationRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "config-nop-a-abc123",
+					},
+					Spec: v1.ConfigurationRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:      "hasheddan/config-nop-a:v0.0.1",
+							DesiredState: v1.PackageRevisionActive,
+						},
+					},
+				},
+			},
+			want: want{
+				total:     1,
+				installed: 1,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		mockUpdateCallCount = 0
+		t.Run(name, func(t *testing.T) {
+			total, installed, invalid, err := tc.args.dep.Resolve(context.TODO(), tc.args.meta, tc.args.pr)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\np.Resolve(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.total, total); diff != "" {
+				t.Errorf("\n%s\nTotal(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.installed, installed); diff != "" {
+				t.Errorf("\n%s\nInstalled(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.invalid, invalid); diff != "" {
+				t.Errorf("\n%s\nInvalid(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDependencyEdges(t *testing.T) {
+	self := v1beta1.LockPackage{
+		Name:    "config-nop-b-abc123",
+		Type:    v1beta1.ConfigurationPackageType,
+		Source:  "hasheddan/config-nop-b",
+		Version: "v0.0.2",
+		Dependencies: []v1beta1.Dependency{
+			{
+				Package:     "hasheddan/provider-nop",
+				Type:        v1beta1.ProviderPackageType,
+				Constraints: ">=v0.1.0",
+			},
+			{
+				Package:     "hasheddan/function-nop",
+				Type:        v1beta1.FunctionPackageType,
+				Constraints: ">=v0.2.0",
+			},
+		},
+	}
+
+	type args struct {
+		self v1beta1.LockPackage
+		pkgs []v1beta1.LockPackage
+	}
+	type want struct {
+		dependsOn   []v1.DependencyEdge
+		installedBy []v1.DependencyEdge
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoDependencies": {
+			reason: "A package that isn't related to any other package should have no edges.",
+			args: args{
+				self: v1beta1.LockPackage{Name: "config-nop-a-abc123", Source: "hasheddan/config-nop-a"},
+				pkgs: []v1beta1.LockPackage{{Name: "config-nop-a-abc123", Source: "hasheddan/config-nop-a"}},
+			},
+			want: want{},
+		},
+		"DependsOnAndInstalledBy": {
+			reason: "We should return the packages we depend on with their installed versions, and the packages that depend on us.",
+			args: args{
+				self: self,
+				pkgs: []v1beta1.LockPackage{
+					self,
+					{
+						Name:    "provider-nop-abc123",
+						Type:    v1beta1.ProviderPackageType,
+						Source:  "hasheddan/provider-nop",
+						Version: "v0.1.1",
+					},
+					{
+						Name:    "config-nop-a-abc123",
+						Type:    v1beta1.ConfigurationPackageType,
+						Source:  "hasheddan/config-nop-a",
+						Version: "v1.0.0",
+						Dependencies: []v1beta1.Dependency{
+							{
+								Package:     "hasheddan/config-nop-b",
+								Type:        v1beta1.ConfigurationPackageType,
+								Constraints: "v0.0.2",
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				dependsOn: []v1.DependencyEdge{
+					{
+						Package:     "hasheddan/provider-nop",
+						Type:        "Provider",
+						Version:     "v0.1.1",
+						Constraints: ">=v0.1.0",
+					},
+					{
+						// Not yet installed, so there's no version.
+						Package:     "hasheddan/function-nop",
+						Type:        "Function",
+						Constraints: ">=v0.2.0",
+					},
+				},
+				installedBy: []v1.DependencyEdge{
+					{
+						Package:     "hasheddan/config-nop-a",
+						Type:        "Configuration",
+						Version:     "v1.0.0",
+						Constraints: "v0.0.2",
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dependsOn, installedBy := dependencyEdges(tc.args.self, tc.args.pkgs)
+			if diff := cmp.Diff(tc.want.dependsOn, dependsOn); diff != "" {
+				t.Errorf("\n%s\ndependencyEdges(...): -want dependsOn, +got dependsOn:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.installedBy, installedBy); diff != "" {
+				t.Errorf("\n%s\ndependencyEdges(...): -want installedBy, +got installedBy:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestProviderGroups(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	pr := &v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "config-nop-a-abc123"}}
+
+	// The configuration depends on provider-nop, or provider-alt in its
+	// place. It doesn't depend on provider-other.
+	lock := func(obj client.Object) error {
+		l := obj.(*v1beta1.Lock)
+		l.Packages = []v1beta1.LockPackage{
+			{
+				Name:   "config-nop-a-abc123",
+				Type:   v1beta1.ConfigurationPackageType,
+				Source: "hasheddan/config-nop-a",
+				Dependencies: []v1beta1.Dependency{
+					{
+						Package: "hasheddan/provider-nop",
+						Type:    v1beta1.ProviderPackageType,
+						Alternatives: []v1beta1.DependencyAlternative{
+							{Package: "hasheddan/provider-alt", Type: v1beta1.ProviderPackageType},
+						},
+					},
+				},
+			},
+			{Name: "provider-alt-abc123", Type: v1beta1.ProviderPackageType, Source: "hasheddan/provider-alt"},
+			{Name: "provider-other-abc123", Type: v1beta1.ProviderPackageType, Source: "hasheddan/provider-other"},
+		}
+		return nil
+	}
+
+	type args struct {
+		client client.Client
+	}
+	type want struct {
+		groups []string
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"GetLockError": {
+			reason: "We should return an error if we can't get the lock.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetLock),
+			},
+		},
+		"NoLock": {
+			reason: "No groups should be returned if there's no lock.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, lockName))},
+			},
+			want: want{},
+		},
+		"GetRevisionError": {
+			reason: "We should return an error if we can't get a provider dependency's revision.",
+			args: args{
+				client: &test.MockClient{MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+					if _, ok := obj.(*v1beta1.Lock); ok {
+						return lock(obj)
+					}
+					return errBoom
+				}},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetDependencyRevision),
+			},
+		},
+		"InstalledProviderDependencies": {
+			reason: "We should return the groups of the CRDs of the installed providers we depend on, including alternatives.",
+			args: args{
+				client: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+					switch o := obj.(type) {
+					case *v1beta1.Lock:
+						return lock(obj)
+					case *v1.ProviderRevision:
+						o.SetObjects([]xpv1.TypedReference{
+							{Kind: kindCRD, Name: "buckets." + key.Name + ".example.org"},
+							{Kind: kindCRD, Name: "providerconfigs." + key.Name + ".example.org"},
+							{Kind: "MutatingWebhookConfiguration", Name: "webhooks.example.org"},
+						})
+					}
+					return nil
+				}},
+			},
+			want: want{
+				groups: []string{"provider-alt-abc123.example.org"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := NewPackageDependencyManager(tc.args.client, dag.NewMapDag, v1beta1.ConfigurationPackageType)
+			groups, err := m.ProviderGroups(context.Background(), pr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nProviderGroups(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.groups, groups); diff != "" {
+				t.Errorf("\n%s\nProviderGroups(...): -want, +got:\n%s", tc.reason, diff)
 			}
 		})
 	}
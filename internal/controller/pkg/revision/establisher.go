@@ -29,6 +29,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -38,6 +39,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
 )
 
@@ -49,6 +51,9 @@ const (
 	errWebhookSecretWithoutCABundle = "the value for the key tls.crt cannot be empty"
 	errFmtGetOwnedObject            = "cannot get owned object: %s/%s"
 	errFmtUpdateOwnedObject         = "cannot update owned object: %s/%s"
+	errFmtOverrideKind              = "cannot override %s %q: only Compositions may be overridden"
+	errFmtOverrideNotInstalled      = "cannot override Composition %q: it is not installed by Configuration %q"
+	errFmtRequiredAPIs              = "cannot determine the APIs %s %q requires"
 )
 
 // An Establisher establishes control or ownership of a set of resources in the
@@ -78,20 +83,38 @@ func (*NopEstablisher) ReleaseObjects(_ context.Context, _ v1.PackageRevision) e
 }
 
 // APIEstablisher establishes control or ownership of resources in the API
-// server for a parent.
+// server for a parent. Resources are validated, then applied, concurrently by
+// at most MaxConcurrentPackageEstablishers goroutines.
 type APIEstablisher struct {
 	client                           client.Client
 	namespace                        string
+	apis                             APIChecker
 	MaxConcurrentPackageEstablishers int
 }
 
+// An APIEstablisherOption configures an APIEstablisher.
+type APIEstablisherOption func(e *APIEstablisher)
+
+// WithAPIChecker configures how the APIEstablisher checks whether the API
+// server serves the APIs an object requires. Objects that require APIs are
+// always established if no APIChecker is configured.
+func WithAPIChecker(c APIChecker) APIEstablisherOption {
+	return func(e *APIEstablisher) {
+		e.apis = c
+	}
+}
+
 // NewAPIEstablisher creates a new APIEstablisher.
-func NewAPIEstablisher(client client.Client, namespace string, maxConcurrentPackageEstablishers int) *APIEstablisher {
-	return &APIEstablisher{
+func NewAPIEstablisher(client client.Client, namespace string, maxConcurrentPackageEstablishers int, o ...APIEstablisherOption) *APIEstablisher {
+	e := &APIEstablisher{
 		client:                           client,
 		namespace:                        namespace,
 		MaxConcurrentPackageEstablishers: maxConcurrentPackageEstablishers,
 	}
+	for _, fn := range o {
+		fn(e)
+	}
+	return e
 }
 
 // currentDesired caches resources while checking for control or ownership so
@@ -106,10 +129,13 @@ type currentDesired struct {
 // Establish checks that control or ownership of resources can be established by
 // parent, then establishes it.
 func (e *APIEstablisher) Establish(ctx context.Context, objs []runtime.Object, parent v1.PackageRevision, control bool) ([]xpv1.TypedReference, error) {
-	err := e.addLabels(objs, parent)
+	objs, err := e.servedObjects(ctx, objs)
 	if err != nil {
 		return nil, err
 	}
+	if err := e.addLabels(objs, parent); err != nil {
+		return nil, err
+	}
 	allObjs, err := e.validate(ctx, objs, parent, control)
 	if err != nil {
 		return nil, err
@@ -125,7 +151,7 @@ func (e *APIEstablisher) Establish(ctx context.Context, objs []runtime.Object, p
 // ReleaseObjects removes control of owned resources in the API server for a
 // package revision.
 func (e *APIEstablisher) ReleaseObjects(ctx context.Context, parent v1.PackageRevision) error { //nolint:gocognit // complexity coming from parallelism.
-	// Note(turkenh): We rely on status.objectRefs to get the list of objects
+	// Note(turkenh): We rely on status.objectRefGroups to get the list of objects
 	// that are controlled by the package revision. Relying on the status is
 	// not ideal as it might get lost (e.g. if the status subresource is
 	// not properly restored after a backup/restore operation). However, we will
@@ -197,6 +223,50 @@ func (e *APIEstablisher) ReleaseObjects(ctx context.Context, parent v1.PackageRe
 	return g.Wait()
 }
 
+// servedObjects returns the supplied objects, except those that require an API
+// the API server doesn't serve. This allows a package to target clusters with
+// different capabilities, for example by only installing a Composition that
+// composes ServiceMonitors if the API server serves them.
+func (e *APIEstablisher) servedObjects(ctx context.Context, objs []runtime.Object) ([]runtime.Object, error) {
+	if e.apis == nil {
+		return objs, nil
+	}
+
+	// Several objects often require the same API.
+	served := map[schema.GroupVersionKind]bool{}
+
+	out := make([]runtime.Object, 0, len(objs))
+	for _, obj := range objs {
+		o, ok := obj.(resource.Object)
+		if !ok {
+			return nil, errors.New(errAssertResourceObj)
+		}
+		required, err := ParseRequiredAPIs(o.GetAnnotations()[v1.AnnotationRequiresAPIs])
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtRequiredAPIs, o.GetObjectKind().GroupVersionKind().Kind, o.GetName())
+		}
+		include := true
+		for _, gvk := range required {
+			ok, cached := served[gvk]
+			if !cached {
+				ok, err = e.apis.ServesAPI(ctx, gvk.GroupVersion(), gvk.Kind)
+				if err != nil {
+					return nil, err
+				}
+				served[gvk] = ok
+			}
+			if !ok {
+				include = false
+				break
+			}
+		}
+		if include {
+			out = append(out, obj)
+		}
+	}
+	return out, nil
+}
+
 func (e *APIEstablisher) addLabels(objs []runtime.Object, parent v1.PackageRevision) error {
 	commonLabels := parent.GetCommonLabels()
 	for _, obj := range objs {
@@ -256,6 +326,12 @@ func (e *APIEstablisher) validate(ctx context.Context, objs []runtime.Object, pa
 				return err
 			}
 
+			if control {
+				if err := validateOverride(desired, current, !kerrors.IsNotFound(err)); err != nil {
+					return err
+				}
+			}
+
 			// If resource does not already exist, we must attempt to dry run create
 			// it.
 			if kerrors.IsNotFound(err) {
@@ -457,11 +533,27 @@ func (e *APIEstablisher) update(ctx context.Context, current, desired resource.O
 		return e.client.Update(ctx, current, opts...)
 	}
 
+	// If another package has overridden the object we only take ownership.
+	if overriddenBy(current, parent) {
+		meta.AddOwnerReference(current, meta.AsOwner(meta.TypedReferenceTo(parent, parent.GetObjectKind().GroupVersionKind())))
+		return e.client.Update(ctx, current, opts...)
+	}
+
 	// If desire is to control object, we attempt to update the object by
 	// setting the desired owner references equal to that of the current, adding
 	// a controller reference to the parent, and setting the desired resource
 	// version to that of the current.
 	desired.SetOwnerReferences(current.GetOwnerReferences())
+	if _, ok := desired.GetAnnotations()[v1.AnnotationOverrides]; ok {
+		// Take control from the package we're overriding.
+		refs := desired.GetOwnerReferences()
+		for i := range refs {
+			if refs[i].UID != parent.GetUID() {
+				refs[i].Controller = nil
+			}
+		}
+		desired.SetOwnerReferences(refs)
+	}
 	if err := meta.AddControllerReference(desired, meta.AsController(meta.TypedReferenceTo(parent, parent.GetObjectKind().GroupVersionKind()))); err != nil {
 		return err
 	}
@@ -469,6 +561,43 @@ func (e *APIEstablisher) update(ctx context.Context, current, desired resource.O
 	return e.client.Update(ctx, desired, opts...)
 }
 
+// validateOverride returns an error if the desired object declares that it
+// overrides an object that may not be overridden, or that isn't installed by the
+// declared package.
+func validateOverride(desired, current resource.Object, exists bool) error {
+	base, ok := desired.GetAnnotations()[v1.AnnotationOverrides]
+	if !ok {
+		return nil
+	}
+	if _, ok := desired.(*apiextensionsv1.Composition); !ok {
+		return errors.Errorf(errFmtOverrideKind, desired.GetObjectKind().GroupVersionKind().Kind, desired.GetName())
+	}
+	if exists {
+		// The object was already overridden, for example by a previous
+		// revision of the parent's package.
+		if current.GetAnnotations()[v1.AnnotationOverrides] == base {
+			return nil
+		}
+		for _, ref := range current.GetOwnerReferences() {
+			if ref.Kind == v1.ConfigurationKind && ref.Name == base {
+				return nil
+			}
+		}
+	}
+	return errors.Errorf(errFmtOverrideNotInstalled, desired.GetName(), base)
+}
+
+// overriddenBy returns true if the current object is controlled by another
+// package that overrides the parent's package.
+func overriddenBy(current, parent resource.Object) bool {
+	base := current.GetAnnotations()[v1.AnnotationOverrides]
+	if base == "" || base != parent.GetLabels()[v1.LabelParentPackage] {
+		return false
+	}
+	c := metav1.GetControllerOf(current)
+	return c != nil && c.UID != parent.GetUID()
+}
+
 // GetPackageOwnerReference returns the owner reference that points to the owner
 // package of given revision, if it can find one.
 func GetPackageOwnerReference(rev resource.Object) (metav1.OwnerReference, bool) {
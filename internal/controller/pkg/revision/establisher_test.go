@@ -39,6 +39,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
 )
 
@@ -403,6 +404,202 @@ func TestAPIEstablisherEstablish(t *testing.T) {
 				err: errBoom,
 			},
 		},
+		"SuccessfulOverrideEstablishControl": {
+			reason: "Establishment should take control of a Composition from the package it declares it overrides.",
+			args: args{
+				est: newAPIEstablisher(&test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						obj.SetOwnerReferences([]metav1.OwnerReference{
+							{Kind: v1.ConfigurationKind, Name: "base", UID: "base-uid"},
+							{Kind: v1.ConfigurationRevisionKind, Name: "base-abc", UID: "base-abc-uid", Controller: ptr.To(true)},
+						})
+						return nil
+					}),
+					MockUpdate: test.NewMockUpdateFn(nil, func(obj client.Object) error {
+						c := metav1.GetControllerOf(obj)
+						if c == nil || c.UID != "overlay-abc-uid" {
+							t.Errorf("Update(...): want overlay revision to control object, got %v", c)
+						}
+						return nil
+					}),
+				}),
+				objs: []runtime.Object{
+					&apiextensionsv1.Composition{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:        "ref-me",
+							Annotations: map[string]string{v1.AnnotationOverrides: "base"},
+						},
+					},
+				},
+				parent: &v1.ConfigurationRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "overlay-abc",
+						UID:  "overlay-abc-uid",
+						Labels: map[string]string{
+							v1.LabelParentPackage: "overlay",
+						},
+					},
+				},
+				control: true,
+			},
+			want: want{
+				refs: []xpv1.TypedReference{{Name: "ref-me"}},
+			},
+		},
+		"SuccessfulOverriddenEstablishOwnership": {
+			reason: "Establishment should only take ownership of an object that another package overrides.",
+			args: args{
+				est: newAPIEstablisher(&test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						obj.SetAnnotations(map[string]string{v1.AnnotationOverrides: "base"})
+						obj.SetOwnerReferences([]metav1.OwnerReference{
+							{Kind: v1.ConfigurationRevisionKind, Name: "overlay-abc", UID: "overlay-abc-uid", Controller: ptr.To(true)},
+						})
+						return nil
+					}),
+					MockUpdate: test.NewMockUpdateFn(nil, func(obj client.Object) error {
+						c := metav1.GetControllerOf(obj)
+						if c == nil || c.UID != "overlay-abc-uid" {
+							t.Errorf("Update(...): want overlay revision to control object, got %v", c)
+						}
+						return nil
+					}),
+				}),
+				objs: []runtime.Object{
+					&apiextensionsv1.Composition{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "ref-me",
+						},
+					},
+				},
+				parent: &v1.ConfigurationRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "base-abc",
+						UID:  "base-abc-uid",
+						Labels: map[string]string{
+							v1.LabelParentPackage: "base",
+						},
+					},
+				},
+				control: true,
+			},
+			want: want{
+				refs: []xpv1.TypedReference{{Name: "ref-me"}},
+			},
+		},
+		"FailedOverrideNotInstalled": {
+			reason: "Establishment should fail if an overridden Composition is not installed by the declared package.",
+			args: args{
+				est: newAPIEstablisher(&test.MockClient{
+					MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+				}),
+				objs: []runtime.Object{
+					&apiextensionsv1.Composition{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:        "ref-me",
+							Annotations: map[string]string{v1.AnnotationOverrides: "base"},
+						},
+					},
+				},
+				parent:  &v1.ConfigurationRevision{},
+				control: true,
+			},
+			want: want{
+				err: errors.Errorf(errFmtOverrideNotInstalled, "ref-me", "base"),
+			},
+		},
+		"FailedOverrideKind": {
+			reason: "Establishment should fail if an object other than a Composition declares an override.",
+			args: args{
+				est: newAPIEstablisher(&test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+				}),
+				objs: []runtime.Object{
+					&extv1.CustomResourceDefinition{
+						TypeMeta: metav1.TypeMeta{Kind: "CustomResourceDefinition"},
+						ObjectMeta: metav1.ObjectMeta{
+							Name:        "ref-me",
+							Annotations: map[string]string{v1.AnnotationOverrides: "base"},
+						},
+					},
+				},
+				parent:  &v1.ConfigurationRevision{},
+				control: true,
+			},
+			want: want{
+				err: errors.Errorf(errFmtOverrideKind, "CustomResourceDefinition", "ref-me"),
+			},
+		},
+		"SuccessfulSkipUnservedAPIs": {
+			reason: "Establishment should skip objects that require an API the API server doesn't serve.",
+			args: args{
+				est: NewAPIEstablisher(&test.MockClient{
+					MockGet:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+					MockCreate: test.NewMockCreateFn(nil),
+				}, "", 10, WithAPIChecker(APICheckerFn(func(_ context.Context, gv schema.GroupVersion, _ string) (bool, error) {
+					return gv.Group == "served.example.org", nil
+				}))),
+				objs: []runtime.Object{
+					&extv1.CustomResourceDefinition{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "served",
+							Annotations: map[string]string{
+								v1.AnnotationRequiresAPIs: "served.example.org/v1",
+							},
+						},
+					},
+					&extv1.CustomResourceDefinition{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "not-served",
+							Annotations: map[string]string{
+								v1.AnnotationRequiresAPIs: "served.example.org/v1, unserved.example.org/v1/Example",
+							},
+						},
+					},
+					&extv1.CustomResourceDefinition{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "requires-nothing",
+						},
+					},
+				},
+				parent: &v1.ProviderRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test",
+					},
+				},
+				control: true,
+			},
+			want: want{
+				refs: []xpv1.TypedReference{{Name: "served"}, {Name: "requires-nothing"}},
+			},
+		},
+		"FailedCheckRequiredAPIs": {
+			reason: "Establishment should fail if we can't check whether the API server serves an API an object requires.",
+			args: args{
+				est: NewAPIEstablisher(&test.MockClient{}, "", 10, WithAPIChecker(APICheckerFn(func(_ context.Context, _ schema.GroupVersion, _ string) (bool, error) {
+					return false, errBoom
+				}))),
+				objs: []runtime.Object{
+					&extv1.CustomResourceDefinition{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "ref-me",
+							Annotations: map[string]string{
+								v1.AnnotationRequiresAPIs: "example.org/v1",
+							},
+						},
+					},
+				},
+				parent: &v1.ProviderRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test",
+					},
+				},
+				control: true,
+			},
+			want: want{
+				err: errBoom,
+			},
+		},
 		"FailedUpdate": {
 			reason: "Cannot establish control of object if we cannot update it.",
 			args: args{
@@ -0,0 +1,316 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/metrics"
+)
+
+const (
+	errFmtGetXRD      = "cannot get composite resource definition %q"
+	errFmtGetCRD      = "cannot get custom resource definition %q"
+	errFmtListCRs     = "cannot list custom resources defined by %q"
+	errFmtNoStorage   = "custom resource definition %q has no storage version"
+	errRegisterMetric = "cannot register package footprint metrics"
+)
+
+// DefaultFootprintSampleSize is the default number of custom resources of each
+// type that are used to estimate how much etcd storage the type uses.
+const DefaultFootprintSampleSize = 100
+
+// A FootprintSampler samples how many objects, and how much etcd storage, a
+// package revision is responsible for.
+type FootprintSampler interface {
+	// Sample the footprint of the supplied package revision.
+	Sample(ctx context.Context, pr v1.PackageRevision) (*v1.PackageFootprint, error)
+}
+
+// A FootprintSamplerFn is a function that satisfies FootprintSampler.
+type FootprintSamplerFn func(ctx context.Context, pr v1.PackageRevision) (*v1.PackageFootprint, error)
+
+// Sample the footprint of the supplied package revision.
+func (fn FootprintSamplerFn) Sample(ctx context.Context, pr v1.PackageRevision) (*v1.PackageFootprint, error) {
+	return fn(ctx, pr)
+}
+
+// A NopFootprintSampler doesn't sample footprints.
+type NopFootprintSampler struct{}
+
+// Sample does nothing.
+func (NopFootprintSampler) Sample(_ context.Context, _ v1.PackageRevision) (*v1.PackageFootprint, error) {
+	return nil, nil
+}
+
+// An APIFootprintSampler samples a package revision's footprint by reading the
+// CRDs it defines, and a sample of the custom resources of each type, from the
+// API server.
+type APIFootprintSampler struct {
+	client client.Reader
+	size   int64
+}
+
+// NewAPIFootprintSampler returns a FootprintSampler that samples up to size
+// custom resources of each type the package defines. The supplied reader
+// should not be backed by a cache, to avoid caching every custom resource.
+func NewAPIFootprintSampler(c client.Reader, size int64) *APIFootprintSampler {
+	return &APIFootprintSampler{client: c, size: size}
+}
+
+// Sample the footprint of the supplied package revision. The number of custom
+// resources of each type is read from the API server's list metadata, and may
+// be approximate.
+func (s *APIFootprintSampler) Sample(ctx context.Context, pr v1.PackageRevision) (*v1.PackageFootprint, error) {
+	fp := &v1.PackageFootprint{}
+
+	for _, ref := range pr.GetObjects() {
+		names, err := s.crdNames(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range names {
+			crd := &extv1.CustomResourceDefinition{}
+			if err := s.client.Get(ctx, types.NamespacedName{Name: n}, crd); err != nil {
+				// The CRD may not have been created yet.
+				if resource.IgnoreNotFound(err) == nil {
+					continue
+				}
+				return nil, errors.Wrapf(err, errFmtGetCRD, n)
+			}
+			count, bytes, err := s.sampleCustomResources(ctx, crd)
+			if err != nil {
+				return nil, err
+			}
+			fp.CustomResourceDefinitions++
+			fp.CustomResources += count
+			fp.EstimatedBytes += jsonSize(crd) + bytes
+		}
+	}
+
+	fp.SampleTime = &metav1.Time{Time: time.Now()}
+	return fp, nil
+}
+
+// crdNames returns the names of the CRDs the referenced package object
+// defines.
+func (s *APIFootprintSampler) crdNames(ctx context.Context, ref xpv1.TypedReference) ([]string, error) {
+	gvk := ref.GroupVersionKind()
+	switch {
+	case gvk.Group == extv1.GroupName && gvk.Kind == "CustomResourceDefinition":
+		return []string{ref.Name}, nil
+	case gvk.Group == apiextensionsv1.Group && gvk.Kind == apiextensionsv1.CompositeResourceDefinitionKind:
+		xrd := &apiextensionsv1.CompositeResourceDefinition{}
+		if err := s.client.Get(ctx, types.NamespacedName{Name: ref.Name}, xrd); err != nil {
+			if resource.IgnoreNotFound(err) == nil {
+				return nil, nil
+			}
+			return nil, errors.Wrapf(err, errFmtGetXRD, ref.Name)
+		}
+		names := []string{xrd.GetName()}
+		if xrd.Spec.ClaimNames != nil {
+			names = append(names, xrd.Spec.ClaimNames.Plural+"."+xrd.Spec.Group)
+		}
+		return names, nil
+	}
+	return nil, nil
+}
+
+// sampleCustomResources returns the number of custom resources of the type
+// the supplied CRD defines, and an estimate of the bytes of etcd storage they
+// use.
+func (s *APIFootprintSampler) sampleCustomResources(ctx context.Context, crd *extv1.CustomResourceDefinition) (int64, int64, error) {
+	version := ""
+	for _, v := range crd.Spec.Versions {
+		if v.Storage {
+			version = v.Name
+			break
+		}
+	}
+	if version == "" {
+		return 0, 0, errors.Errorf(errFmtNoStorage, crd.GetName())
+	}
+
+	l := &unstructured.UnstructuredList{}
+	l.SetAPIVersion(crd.Spec.Group + "/" + version)
+	l.SetKind(crd.Spec.Names.ListKind)
+	if l.GetKind() == "" {
+		l.SetKind(crd.Spec.Names.Kind + "List")
+	}
+	if err := s.client.List(ctx, l, client.Limit(s.size)); err != nil {
+		return 0, 0, errors.Wrapf(err, errFmtListCRs, crd.GetName())
+	}
+	if len(l.Items) == 0 {
+		return 0, 0, nil
+	}
+
+	var sampled int64
+	for i := range l.Items {
+		sampled += jsonSize(&l.Items[i])
+	}
+
+	count := int64(len(l.Items))
+	if rem := l.GetRemainingItemCount(); rem != nil {
+		count += *rem
+	}
+
+	// Custom resources are stored in etcd as JSON, so we extrapolate their
+	// total size from the mean size of the sample.
+	return count, sampled * count / int64(len(l.Items)), nil
+}
+
+func jsonSize(o any) int64 {
+	b, err := json.Marshal(o)
+	if err != nil {
+		return 0
+	}
+	return int64(len(b))
+}
+
+// A FootprintRecorder records sampled package footprints, e.g. as metrics.
+type FootprintRecorder interface {
+	// Record the footprint of the supplied package revision.
+	Record(pr v1.PackageRevision, fp *v1.PackageFootprint)
+
+	// Forget the footprint of the supplied package revision.
+	Forget(pr v1.PackageRevision)
+}
+
+// A NopFootprintRecorder doesn't record footprints.
+type NopFootprintRecorder struct{}
+
+// Record does nothing.
+func (NopFootprintRecorder) Record(_ v1.PackageRevision, _ *v1.PackageFootprint) {}
+
+// Forget does nothing.
+func (NopFootprintRecorder) Forget(_ v1.PackageRevision) {}
+
+// FootprintMetrics records package footprints as Prometheus gauges, labelled
+// by the kind and name of the package.
+type FootprintMetrics struct {
+	crds      *prometheus.GaugeVec
+	resources *prometheus.GaugeVec
+	bytes     *prometheus.GaugeVec
+}
+
+// NewFootprintMetrics creates metrics for package footprints.
+func NewFootprintMetrics() *FootprintMetrics {
+	labels := []string{"package_kind", "package"}
+	return &FootprintMetrics{
+		crds: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Subsystem: "package",
+			Name:      "custom_resource_definitions",
+			Help:      "Number of CRDs defined by a package's active revision.",
+		}, labels),
+		resources: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Subsystem: "package",
+			Name:      "custom_resources",
+			Help:      "Number of custom resources of the types defined by a package's active revision.",
+		}, labels),
+		bytes: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Subsystem: "package",
+			Name:      "estimated_etcd_bytes",
+			Help:      "Estimated bytes of etcd storage used by the CRDs and custom resources of a package's active revision.",
+		}, labels),
+	}
+}
+
+// RegisterFootprintMetrics registers footprint metrics with the Crossplane
+// metrics registry. It returns the already registered metrics if it's called
+// more than once, e.g. by the controller for each kind of package.
+func RegisterFootprintMetrics() (*FootprintMetrics, error) {
+	m := NewFootprintMetrics()
+	err := metrics.Registry.Register(m)
+	are := prometheus.AlreadyRegisteredError{}
+	if errors.As(err, &are) {
+		if existing, ok := are.ExistingCollector.(*FootprintMetrics); ok {
+			return existing, nil
+		}
+	}
+	return m, errors.Wrap(err, errRegisterMetric)
+}
+
+// Describe sends the super-set of all possible descriptors of metrics
+// collected by this Collector to the provided channel and returns once
+// the last descriptor has been sent.
+func (m *FootprintMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.crds.Describe(ch)
+	m.resources.Describe(ch)
+	m.bytes.Describe(ch)
+}
+
+// Collect is called by the Prometheus registry when collecting
+// metrics. The implementation sends each collected metric via the
+// provided channel and returns once the last metric has been sent.
+func (m *FootprintMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.crds.Collect(ch)
+	m.resources.Collect(ch)
+	m.bytes.Collect(ch)
+}
+
+// Record the footprint of the supplied package revision.
+func (m *FootprintMetrics) Record(pr v1.PackageRevision, fp *v1.PackageFootprint) {
+	l := footprintLabels(pr)
+	m.crds.With(l).Set(float64(fp.CustomResourceDefinitions))
+	m.resources.With(l).Set(float64(fp.CustomResources))
+	m.bytes.With(l).Set(float64(fp.EstimatedBytes))
+}
+
+// Forget the footprint of the supplied package revision.
+func (m *FootprintMetrics) Forget(pr v1.PackageRevision) {
+	l := footprintLabels(pr)
+	m.crds.Delete(l)
+	m.resources.Delete(l)
+	m.bytes.Delete(l)
+}
+
+func footprintLabels(pr v1.PackageRevision) prometheus.Labels {
+	kind := ""
+	switch pr.(type) {
+	case *v1.ProviderRevision:
+		kind = v1.ProviderKind
+	case *v1.ConfigurationRevision:
+		kind = v1.ConfigurationKind
+	case *v1.FunctionRevision:
+		kind = v1.FunctionKind
+	}
+	return prometheus.Labels{"package_kind": kind, "package": pr.GetLabels()[v1.LabelParentPackage]}
+}
+
+// footprintStale returns true if the supplied footprint was sampled longer
+// than the supplied interval ago, or was never sampled.
+func footprintStale(fp *v1.PackageFootprint, interval time.Duration) bool {
+	if fp == nil || fp.SampleTime == nil {
+		return true
+	}
+	return time.Since(fp.SampleTime.Time) >= interval
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+var (
+	_ FootprintSampler  = &APIFootprintSampler{}
+	_ FootprintRecorder = &FootprintMetrics{}
+)
+
+func TestAPIFootprintSamplerSample(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	crd := extv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "cools.example.org"},
+		Spec: extv1.CustomResourceDefinitionSpec{
+			Group:    "example.org",
+			Names:    extv1.CustomResourceDefinitionNames{Kind: "Cool", ListKind: "CoolList"},
+			Versions: []extv1.CustomResourceDefinitionVersion{{Name: "v1alpha1"}, {Name: "v1", Storage: true}},
+		},
+	}
+
+	cr := unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "example.org/v1",
+		"kind":       "Cool",
+		"metadata":   map[string]any{"name": "cool"},
+	}}
+
+	get := func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+		switch o := obj.(type) {
+		case *extv1.CustomResourceDefinition:
+			crd.DeepCopyInto(o)
+		case *apiextensionsv1.CompositeResourceDefinition:
+			o.SetName(key.Name)
+			o.Spec.Group = "example.org"
+			o.Spec.ClaimNames = &extv1.CustomResourceDefinitionNames{Plural: "claims"}
+		}
+		return nil
+	}
+
+	list := func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+		l := obj.(*unstructured.UnstructuredList)
+		if l.GetAPIVersion() != "example.org/v1" || l.GetKind() != "CoolList" {
+			return errors.Errorf("unexpected list %s %s", l.GetAPIVersion(), l.GetKind())
+		}
+		l.Items = []unstructured.Unstructured{cr, cr}
+		l.SetRemainingItemCount(ptr.To[int64](8))
+		return nil
+	}
+
+	crdRef := xpv1.TypedReference{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "cools.example.org"}
+	xrdRef := xpv1.TypedReference{APIVersion: "apiextensions.crossplane.io/v1", Kind: "CompositeResourceDefinition", Name: "xcools.example.org"}
+	compRef := xpv1.TypedReference{APIVersion: "apiextensions.crossplane.io/v1", Kind: "Composition", Name: "cool"}
+
+	crdSize := jsonSize(&crd)
+	crSize := jsonSize(&cr)
+
+	type want struct {
+		fp  *v1.PackageFootprint
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Reader
+		refs   []xpv1.TypedReference
+		want   want
+	}{
+		"CRDs": {
+			reason: "We should count a provider's CRDs, extrapolating the size of their custom resources from a sample.",
+			client: &test.MockClient{MockGet: get, MockList: list},
+			refs:   []xpv1.TypedReference{crdRef},
+			want: want{fp: &v1.PackageFootprint{
+				CustomResourceDefinitions: 1,
+				CustomResources:           10,
+				EstimatedBytes:            crdSize + 10*crSize,
+			}},
+		},
+		"XRDs": {
+			reason: "We should count the composite resource and claim CRDs defined by a configuration's XRDs, and ignore other objects.",
+			client: &test.MockClient{MockGet: get, MockList: list},
+			refs:   []xpv1.TypedReference{xrdRef, compRef},
+			want: want{fp: &v1.PackageFootprint{
+				CustomResourceDefinitions: 2,
+				CustomResources:           20,
+				EstimatedBytes:            2 * (crdSize + 10*crSize),
+			}},
+		},
+		"CRDNotFound": {
+			reason: "We should ignore CRDs that don't exist yet.",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ""))},
+			refs:   []xpv1.TypedReference{crdRef},
+			want:   want{fp: &v1.PackageFootprint{}},
+		},
+		"ListError": {
+			reason: "We should return any error encountered listing custom resources.",
+			client: &test.MockClient{MockGet: get, MockList: test.NewMockListFn(errBoom)},
+			refs:   []xpv1.TypedReference{crdRef},
+			want:   want{err: errors.Wrapf(errBoom, errFmtListCRs, "cools.example.org")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pr := &v1.ProviderRevision{}
+			pr.SetObjects(tc.refs)
+
+			fp, err := NewAPIFootprintSampler(tc.client, DefaultFootprintSampleSize).Sample(context.Background(), pr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nSample(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.fp, fp, cmpopts.IgnoreFields(v1.PackageFootprint{}, "SampleTime")); diff != "" {
+				t.Errorf("\n%s\nSample(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestFootprintStale(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		fp     *v1.PackageFootprint
+		want   bool
+	}{
+		"NeverSampled": {
+			reason: "A footprint that was never sampled is stale.",
+			want:   true,
+		},
+		"RecentlySampled": {
+			reason: "A footprint sampled within the interval is not stale.",
+			fp:     &v1.PackageFootprint{SampleTime: &metav1.Time{Time: time.Now().Add(-time.Minute)}},
+			want:   false,
+		},
+		"SampledLongAgo": {
+			reason: "A footprint sampled longer than the interval ago is stale.",
+			fp:     &v1.PackageFootprint{SampleTime: &metav1.Time{Time: time.Now().Add(-time.Hour)}},
+			want:   true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := footprintStale(tc.fp, 10*time.Minute)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nfootprintStale(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -118,7 +118,7 @@ func FuzzRevisionControllerPackageHandling(f *testing.F) {
 		}
 		prs := &v1.PackageRevisionSpec{}
 		ff.GenerateStruct(prs)
-		pr := &v1.ConfigurationRevision{Spec: *prs}
+		pr := &v1.ConfigurationRevision{Spec: v1.ConfigurationRevisionSpec{PackageRevisionSpec: *prs}}
 
 		if err := linter.Lint(pkg); err != nil {
 			return
@@ -20,9 +20,12 @@ import (
 	"archive/tar"
 	"context"
 	"io"
+	"time"
 
 	"github.com/google/go-containerregistry/pkg/name"
+	conregv1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
 	"github.com/google/go-containerregistry/pkg/v1/validate"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -34,8 +37,10 @@ import (
 
 const (
 	errBadReference            = "package tag is not a valid reference"
+	errImageSource             = "cannot use package image source"
 	errFetchPackage            = "failed to fetch package from remote"
 	errGetManifest             = "failed to get package image manifest from remote"
+	errGetConfig               = "failed to get package image config from remote"
 	errFetchLayer              = "failed to fetch annotated base layer from remote"
 	errGetUncompressed         = "failed to get uncompressed contents from layer"
 	errMultipleAnnotatedLayers = "package is invalid due to multiple annotated base layers"
@@ -43,6 +48,10 @@ const (
 	errFmtMaxManifestLayers    = "package has %d layers, but only %d are allowed"
 	errValidateLayer           = "invalid package layer"
 	errValidateImage           = "invalid package image"
+	errGetArtifactLayer        = "failed to get package file from artifact layer"
+	errNoArtifactPackage       = "package artifact has no layer titled \"" + xpkg.StreamFile + "\" and no tarball layer"
+	errGetDigest               = "failed to get package image digest"
+	errFmtDigestMismatch       = "package image has digest %s, but its source is pinned to %s"
 )
 
 const (
@@ -50,12 +59,42 @@ const (
 	baseAnnotationValue = "base"
 	// maxLayers is the maximum number of layers an image can have.
 	maxLayers = 256
+
+	// annotationTitle is the OCI annotation that tools like ORAS use to record
+	// the file name of an artifact's layer.
+	annotationTitle = "org.opencontainers.image.title"
+
+	// defaultPullProgressInterval is how often the progress of a layer pull
+	// is reported by default.
+	defaultPullProgressInterval = 10 * time.Second
 )
 
+// A PullReporter is notified as a package image is pulled.
+type PullReporter interface {
+	// PullingImage is called once the image's manifest has been fetched,
+	// before any of its layers are pulled. The size is the total compressed
+	// size of the image's layers.
+	PullingImage(layers int, size int64)
+
+	// PullingLayer is called periodically while a layer is pulled, with the
+	// number of bytes of the layer that have been pulled so far.
+	PullingLayer(digest string, pulled, size int64)
+}
+
+// A NopPullReporter does nothing.
+type NopPullReporter struct{}
+
+// PullingImage does nothing.
+func (NopPullReporter) PullingImage(_ int, _ int64) {}
+
+// PullingLayer does nothing.
+func (NopPullReporter) PullingLayer(_ string, _, _ int64) {}
+
 // ImageBackend is a backend for parser.
 type ImageBackend struct {
 	registry string
-	fetcher  xpkg.Fetcher
+	sources  *xpkg.ImageSourceFetcher
+	interval time.Duration
 }
 
 // An ImageBackendOption sets configuration for an image backend.
@@ -68,10 +107,27 @@ func WithDefaultRegistry(registry string) ImageBackendOption {
 	}
 }
 
+// WithImageSources configures how an image backend fetches packages that
+// specify an alternative image source.
+func WithImageSources(s *xpkg.ImageSourceFetcher) ImageBackendOption {
+	return func(i *ImageBackend) {
+		i.sources = s
+	}
+}
+
+// WithPullProgressInterval sets how often an image backend reports the
+// progress of a layer pull.
+func WithPullProgressInterval(d time.Duration) ImageBackendOption {
+	return func(i *ImageBackend) {
+		i.interval = d
+	}
+}
+
 // NewImageBackend creates a new image backend.
 func NewImageBackend(fetcher xpkg.Fetcher, opts ...ImageBackendOption) *ImageBackend {
 	i := &ImageBackend{
-		fetcher: fetcher,
+		sources:  xpkg.NewImageSourceFetcher(fetcher),
+		interval: defaultPullProgressInterval,
 	}
 	for _, opt := range opts {
 		opt(i)
@@ -89,19 +145,36 @@ func (i *ImageBackend) Init(ctx context.Context, bo ...parser.BackendOption) (io
 	// exclusive access to the image backend other than its poor design. We
 	// should consider restructuring the parser backend interface to better
 	// accommodate for shared, thread-safe backends.
-	n := &nestedBackend{}
+	n := &nestedBackend{reporter: NopPullReporter{}}
 	for _, o := range bo {
 		o(n)
 	}
-	ref, err := name.ParseReference(n.pr.GetSource(), name.WithDefaultRegistry(i.registry))
+	f, ref, err := i.fetcher(n.pr)
 	if err != nil {
-		return nil, errors.Wrap(err, errBadReference)
+		return nil, err
 	}
-	// Fetch image from registry.
-	img, err := i.fetcher.Fetch(ctx, ref, v1.RefNames(n.pr.GetPackagePullSecrets())...)
+	// Fetch image from registry, or from its alternative source.
+	img, err := f.Fetch(ctx, ref, v1.RefNames(n.pr.GetPackagePullSecrets())...)
 	if err != nil {
 		return nil, errors.Wrap(err, errFetchPackage)
 	}
+	// Secret and path image sources ignore the reference they're asked to
+	// fetch. Make sure they served the image the revision is pinned to, which
+	// is the image whose signature was verified. We don't check images pulled
+	// from a registry. The revision may be pinned to a multi-platform index,
+	// in which case the fetched image is the index's platform variant and has
+	// a different digest. go-containerregistry already verifies that the
+	// index or manifest it pulls by digest has that digest, and it pulls the
+	// platform variant by the digest the index records.
+	if d, ok := ref.(name.Digest); ok && ignoresReference(n.pr.GetImageSource()) {
+		h, err := img.Digest()
+		if err != nil {
+			return nil, errors.Wrap(err, errGetDigest)
+		}
+		if h.String() != d.DigestStr() {
+			return nil, errors.Errorf(errFmtDigestMismatch, h, d.DigestStr())
+		}
+	}
 	// Get image manifest.
 	manifest, err := img.Manifest()
 	if err != nil {
@@ -113,6 +186,27 @@ func (i *ImageBackend) Init(ctx context.Context, bo ...parser.BackendOption) (io
 		return nil, errors.Errorf(errFmtMaxManifestLayers, nLayers, maxLayers)
 	}
 
+	// Record how the package was built, so that it can be traced back to
+	// its source. Artifacts don't have an image config, so we look for their
+	// build metadata in their manifest's annotations instead.
+	cfg := &conregv1.ConfigFile{Config: conregv1.Config{Labels: manifest.Annotations}}
+	if !isArtifact(manifest) {
+		if cfg, err = img.ConfigFile(); err != nil {
+			return nil, errors.Wrap(err, errGetConfig)
+		}
+	}
+	n.pr.SetPackageBuild(xpkg.ParseBuildMetadata(cfg))
+
+	var size int64
+	for _, l := range manifest.Layers {
+		size += l.Size
+	}
+	n.reporter.PullingImage(len(manifest.Layers), size)
+
+	if isArtifact(manifest) {
+		return i.artifact(img, manifest, n.reporter)
+	}
+
 	// Determine if the image is using annotated layers.
 	var tarc io.ReadCloser
 	foundAnnotated := false
@@ -133,6 +227,12 @@ func (i *ImageBackend) Init(ctx context.Context, bo ...parser.BackendOption) (io
 		if err != nil {
 			return nil, errors.Wrap(err, errFetchLayer)
 		}
+		// The layer is pulled each time it's read, i.e. once to validate
+		// it and again to extract it. Each pull's progress is reported.
+		layer, err = partial.CompressedToLayer(&progressLayer{Layer: layer, digest: l.Digest.String(), size: l.Size, reporter: n.reporter, interval: i.interval})
+		if err != nil {
+			return nil, errors.Wrap(err, errFetchLayer)
+		}
 		if err := validate.Layer(layer); err != nil {
 			return nil, errors.Wrap(err, errValidateLayer)
 		}
@@ -143,6 +243,7 @@ func (i *ImageBackend) Init(ctx context.Context, bo ...parser.BackendOption) (io
 	}
 
 	// If we still don't have content then we need to flatten image filesystem.
+	// We don't report the progress of pulling a flattened image's layers.
 	if !foundAnnotated {
 		if err := validate.Image(img); err != nil {
 			return nil, errors.Wrap(err, errValidateImage)
@@ -153,6 +254,12 @@ func (i *ImageBackend) Init(ctx context.Context, bo ...parser.BackendOption) (io
 	// The ReadCloser is an uncompressed tarball, either consisting of annotated
 	// layer contents or flattened filesystem content. Either way, we only want
 	// the package YAML stream.
+	return packageStream(tarc, foundAnnotated)
+}
+
+// packageStream returns the package YAML stream contained in the supplied
+// uncompressed tarball.
+func packageStream(tarc io.ReadCloser, foundAnnotated bool) (io.ReadCloser, error) {
 	t := tar.NewReader(tarc)
 	var read int
 	for {
@@ -166,6 +273,10 @@ func (i *ImageBackend) Init(ctx context.Context, bo ...parser.BackendOption) (io
 		read++
 	}
 
+	// The package YAML stream is never buffered. The tar reader is positioned
+	// at the stream file, and the parser decodes it one document at a time,
+	// so memory use is bounded by the largest document rather than the layer.
+	//
 	// NOTE(hasheddan): we return a JoinedReadCloser such that closing will free
 	// resources allocated to the underlying ReadCloser. See
 	// https://github.com/google/go-containerregistry/blob/329563766ce8131011c25fd8758a25d94d9ad81b/pkg/v1/mutate/mutate.go#L222
@@ -173,12 +284,127 @@ func (i *ImageBackend) Init(ctx context.Context, bo ...parser.BackendOption) (io
 	return xpkg.JoinedReadCloser(t, tarc), nil
 }
 
+// isArtifact returns true if the supplied manifest is an OCI artifact, as
+// published by tools like ORAS, rather than a container image. An artifact's
+// config isn't an image config - it's typically the empty descriptor, or has
+// the artifact's type as its media type.
+func isArtifact(m *conregv1.Manifest) bool {
+	return !m.Config.MediaType.IsConfig()
+}
+
+// artifact returns the package YAML stream of the supplied OCI artifact. The
+// stream is the content of the layer titled package.yaml, if there is one.
+// Otherwise it's the package.yaml file in the artifact's first tarball layer.
+func (i *ImageBackend) artifact(img conregv1.Image, m *conregv1.Manifest, r PullReporter) (io.ReadCloser, error) {
+	var pkg *conregv1.Descriptor
+	for idx := range m.Layers {
+		l := &m.Layers[idx]
+		if l.Annotations[annotationTitle] == xpkg.StreamFile {
+			pkg = l
+			break
+		}
+		if pkg == nil && l.MediaType.IsLayer() {
+			pkg = l
+		}
+	}
+	if pkg == nil {
+		return nil, errors.New(errNoArtifactPackage)
+	}
+
+	layer, err := img.LayerByDigest(pkg.Digest)
+	if err != nil {
+		return nil, errors.Wrap(err, errFetchLayer)
+	}
+	layer, err = partial.CompressedToLayer(&progressLayer{Layer: layer, digest: pkg.Digest.String(), size: pkg.Size, reporter: r, interval: i.interval})
+	if err != nil {
+		return nil, errors.Wrap(err, errFetchLayer)
+	}
+
+	// A layer titled package.yaml is the package YAML stream itself. Its
+	// "compressed" contents are the layer's blob, exactly as it was pushed.
+	if pkg.Annotations[annotationTitle] == xpkg.StreamFile {
+		rc, err := layer.Compressed()
+		return rc, errors.Wrap(err, errGetArtifactLayer)
+	}
+
+	tarc, err := layer.Uncompressed()
+	if err != nil {
+		return nil, errors.Wrap(err, errGetUncompressed)
+	}
+	return packageStream(tarc, true)
+}
+
+// fetcher returns the Fetcher and reference the supplied package revision's
+// image should be fetched with. Images whose source is a URL have no
+// reference.
+func (i *ImageBackend) fetcher(pr v1.PackageRevision) (xpkg.Fetcher, name.Reference, error) {
+	if xpkg.IsURLSource(pr.GetSource()) {
+		f, err := i.sources.ForURL(pr.GetSource())
+		return f, nil, errors.Wrap(err, errImageSource)
+	}
+	ref, err := name.ParseReference(pr.GetSource(), name.WithDefaultRegistry(i.registry))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errBadReference)
+	}
+	f, err := i.sources.For(pr.GetImageSource())
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errImageSource)
+	}
+	return f, ref, nil
+}
+
+// ignoresReference returns true if images loaded from the supplied source
+// ignore the reference they're fetched by, i.e. are loaded from a tarball.
+func ignoresReference(src *v1.ImageSource) bool {
+	return src != nil && (src.Secret != nil || src.Path != nil)
+}
+
+// A progressLayer reports the progress of pulling its compressed contents.
+type progressLayer struct {
+	conregv1.Layer
+
+	digest   string
+	size     int64
+	reporter PullReporter
+	interval time.Duration
+}
+
+// Compressed returns the compressed contents of the layer.
+func (l *progressLayer) Compressed() (io.ReadCloser, error) {
+	rc, err := l.Layer.Compressed()
+	if err != nil {
+		return nil, err
+	}
+	return &progressReader{ReadCloser: rc, layer: l, last: time.Now()}, nil
+}
+
+// A progressReader reports how many bytes of a layer have been read at most
+// once per interval.
+type progressReader struct {
+	io.ReadCloser
+
+	layer  *progressLayer
+	pulled int64
+	last   time.Time
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.pulled += int64(n)
+	if now := time.Now(); now.Sub(r.last) >= r.layer.interval {
+		r.last = now
+		r.layer.reporter.PullingLayer(r.layer.digest, r.pulled, r.layer.size)
+	}
+	return n, err
+}
+
 // nestedBackend is a nop parser backend that conforms to the parser backend
 // interface to allow holding intermediate data passed via parser backend
 // options.
 // NOTE(hasheddan): see usage in ImageBackend Init() for reasoning.
 type nestedBackend struct {
-	pr v1.PackageRevision
+	pr       v1.PackageRevision
+	reporter PullReporter
 }
 
 // Init is a nop because nestedBackend does not actually meant to act as a
@@ -197,3 +423,15 @@ func PackageRevision(pr v1.PackageRevision) parser.BackendOption {
 		i.pr = pr
 	}
 }
+
+// ReportPullProgress reports the progress of pulling a package image to the
+// supplied PullReporter.
+func ReportPullProgress(r PullReporter) parser.BackendOption {
+	return func(p parser.Backend) {
+		i, ok := p.(*nestedBackend)
+		if !ok {
+			return
+		}
+		i.reporter = r
+	}
+}
@@ -17,14 +17,22 @@ limitations under the License.
 package revision
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
 	"io"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	conregv1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/empty"
 	"github.com/google/go-containerregistry/pkg/v1/mutate"
 	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
 	"github.com/google/go-containerregistry/pkg/v1/types"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -72,8 +80,26 @@ func TestImageBackend(t *testing.T) {
 	// })
 	// packImg, _ := mutate.AppendLayers(empty.Image, packLayer)
 
+	otherDigest := "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d2d7d1c5d1d3abf"
+
+	// A multi-platform index, of which randImg is the linux/amd64 variant.
+	armImg, _ := random.Image(int64(1000), 1)
+	randIdx := mutate.AppendManifests(empty.Index,
+		mutate.IndexAddendum{Add: randImg, Descriptor: conregv1.Descriptor{Platform: &conregv1.Platform{OS: "linux", Architecture: "amd64"}}},
+		mutate.IndexAddendum{Add: armImg, Descriptor: conregv1.Descriptor{Platform: &conregv1.Platform{OS: "linux", Architecture: "arm64"}}},
+	)
+	idxDigest, _ := randIdx.Digest()
+
+	// A directory of pre-loaded package images.
+	dir := t.TempDir()
+	_ = tarball.WriteToFile(filepath.Join(dir, "rand.xpkg"), name.MustParseReference("test/test:latest"), randImg)
+	path := "rand.xpkg"
+	tarImg, _ := tarball.ImageFromPath(filepath.Join(dir, path), nil)
+	tarDigest, _ := tarImg.Digest()
+
 	type args struct {
 		f    xpkg.Fetcher
+		bo   []ImageBackendOption
 		opts []parser.BackendOption
 	}
 
@@ -159,6 +185,54 @@ func TestImageBackend(t *testing.T) {
 			},
 			want: errors.Wrap(errBoom, errFetchPackage),
 		},
+		"ErrDigestMismatch": {
+			reason: "Should return error if the image loaded from an image source doesn't have the digest the package source is pinned to.",
+			args: args{
+				f:  &fake.MockFetcher{},
+				bo: []ImageBackendOption{WithImageSources(xpkg.NewImageSourceFetcher(&fake.MockFetcher{}, xpkg.WithPathImageSources(dir)))},
+				opts: []parser.BackendOption{PackageRevision(&v1.ProviderRevision{
+					Spec: v1.ProviderRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:     "test/test:latest@" + otherDigest,
+							ImageSource: &v1.ImageSource{Path: &path},
+						},
+					},
+				})},
+			},
+			want: errors.Errorf(errFmtDigestMismatch, tarDigest, otherDigest),
+		},
+		"PinnedDigest": {
+			reason: "Should fetch the package if the image loaded from an image source has the digest the package source is pinned to.",
+			args: args{
+				f:  &fake.MockFetcher{},
+				bo: []ImageBackendOption{WithImageSources(xpkg.NewImageSourceFetcher(&fake.MockFetcher{}, xpkg.WithPathImageSources(dir)))},
+				opts: []parser.BackendOption{PackageRevision(&v1.ProviderRevision{
+					Spec: v1.ProviderRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:     "test/test:latest@" + tarDigest.String(),
+							ImageSource: &v1.ImageSource{Path: &path},
+						},
+					},
+				})},
+			},
+			want: errors.Wrapf(io.EOF, errFmtNoPackageFileFound, 1, false),
+		},
+		"PinnedMultiPlatformIndex": {
+			reason: "Should fetch the package if it's pulled from a registry and pinned to a multi-platform index, whose platform variant has a different digest.",
+			args: args{
+				f: &fake.MockFetcher{
+					MockFetch: fake.NewMockFetchFn(randImg, nil),
+				},
+				opts: []parser.BackendOption{PackageRevision(&v1.ProviderRevision{
+					Spec: v1.ProviderRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package: "test/test:latest@" + idxDigest.String(),
+						},
+					},
+				})},
+			},
+			want: errors.Wrapf(io.EOF, errFmtNoPackageFileFound, 1, true),
+		},
 		// TODO(phisco): uncomment when https://github.com/google/go-containerregistry/pull/1758 is merged
 		// "SuccessFetchPackage": {
 		// 	reason: "Should not return error is package is not in cache but is fetched successfully.",
@@ -177,7 +251,7 @@ func TestImageBackend(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			b := NewImageBackend(tc.args.f)
+			b := NewImageBackend(tc.args.f, tc.args.bo...)
 			rc, err := b.Init(context.TODO(), tc.args.opts...)
 			if err == nil && rc != nil {
 				_, err = io.ReadAll(rc)
@@ -188,3 +262,137 @@ func TestImageBackend(t *testing.T) {
 		})
 	}
 }
+
+type recordingPullReporter struct {
+	layers int
+	size   int64
+	pulled map[string]int64
+}
+
+func (r *recordingPullReporter) PullingImage(layers int, size int64) {
+	r.layers, r.size = layers, size
+}
+
+func (r *recordingPullReporter) PullingLayer(digest string, pulled, _ int64) {
+	if r.pulled == nil {
+		r.pulled = map[string]int64{}
+	}
+	r.pulled[digest] = max(r.pulled[digest], pulled)
+}
+
+func TestImageBackendPullProgress(t *testing.T) {
+	layer, _ := random.Layer(int64(1000), types.DockerLayer)
+	img, _ := mutate.Append(empty.Image, mutate.Addendum{
+		Layer: layer,
+		Annotations: map[string]string{
+			layerAnnotation: baseAnnotationValue,
+		},
+	})
+	d, _ := layer.Digest()
+	size, _ := layer.Size()
+
+	r := &recordingPullReporter{}
+	b := NewImageBackend(&fake.MockFetcher{MockFetch: fake.NewMockFetchFn(img, nil)}, WithPullProgressInterval(0))
+
+	// The random layer doesn't contain a package, but it's pulled before we
+	// find that out.
+	_, _ = b.Init(context.TODO(), PackageRevision(&v1.ProviderRevision{
+		Spec: v1.ProviderRevisionSpec{
+			PackageRevisionSpec: v1.PackageRevisionSpec{
+				Package: "test/test:latest",
+			},
+		},
+	}), ReportPullProgress(r))
+
+	want := &recordingPullReporter{layers: 1, size: size, pulled: map[string]int64{d.String(): size}}
+	if diff := cmp.Diff(want, r, cmp.AllowUnexported(recordingPullReporter{})); diff != "" {
+		t.Errorf("b.Init(...): -want reported progress, +got reported progress:\n%s", diff)
+	}
+}
+
+func TestImageBackendArtifact(t *testing.T) {
+	stream := "somestreamofyaml"
+
+	tarBuf := new(bytes.Buffer)
+	tw := tar.NewWriter(tarBuf)
+	_ = tw.WriteHeader(&tar.Header{Name: xpkg.StreamFile, Mode: int64(xpkg.StreamFileMode), Size: int64(len(stream))})
+	_, _ = io.Copy(tw, strings.NewReader(stream))
+	_ = tw.Close()
+
+	artifact := func(layers ...mutate.Addendum) conregv1.Image {
+		img := mutate.MediaType(empty.Image, types.OCIManifestSchema1)
+		img = mutate.ConfigMediaType(img, "application/vnd.oci.empty.v1+json")
+		img, _ = mutate.Append(img, layers...)
+		return img
+	}
+
+	type want struct {
+		stream string
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		img    conregv1.Image
+		want   want
+	}{
+		"PackageFileLayer": {
+			reason: "We should return the contents of an artifact layer titled package.yaml.",
+			img: artifact(
+				mutate.Addendum{
+					Layer:       static.NewLayer([]byte("README"), "text/markdown"),
+					Annotations: map[string]string{annotationTitle: "README.md"},
+				},
+				mutate.Addendum{
+					Layer:       static.NewLayer([]byte(stream), "application/vnd.crossplane.package.v1+yaml"),
+					Annotations: map[string]string{annotationTitle: xpkg.StreamFile},
+				},
+			),
+			want: want{
+				stream: stream,
+			},
+		},
+		"TarballLayer": {
+			reason: "We should return the package.yaml file in an artifact's tarball layer.",
+			img: artifact(mutate.Addendum{
+				Layer: static.NewLayer(tarBuf.Bytes(), types.OCIUncompressedLayer),
+			}),
+			want: want{
+				stream: stream,
+			},
+		},
+		"ErrNoPackage": {
+			reason: "We should return an error if an artifact has no package.yaml layer and no tarball layer.",
+			img: artifact(mutate.Addendum{
+				Layer:       static.NewLayer([]byte("README"), "text/markdown"),
+				Annotations: map[string]string{annotationTitle: "README.md"},
+			}),
+			want: want{
+				err: errors.New(errNoArtifactPackage),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			b := NewImageBackend(&fake.MockFetcher{MockFetch: fake.NewMockFetchFn(tc.img, nil)})
+			rc, err := b.Init(context.TODO(), PackageRevision(&v1.ProviderRevision{
+				Spec: v1.ProviderRevisionSpec{
+					PackageRevisionSpec: v1.PackageRevisionSpec{
+						Package: "test/test:latest",
+					},
+				},
+			}))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nb.Init(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			got, _ := io.ReadAll(rc)
+			if diff := cmp.Diff(tc.want.stream, string(got)); diff != "" {
+				t.Errorf("\n%s\nb.Init(...): -want stream, +got stream:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"slices"
+	"strings"
+
+	admv1 "k8s.io/api/admissionregistration/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+const (
+	errFmtDisallowedKinds  = "package contains objects of kinds that are not allowed: %s"
+	errFmtDisallowedGroups = "package contains objects of API groups that its provider dependencies don't define: %s"
+)
+
+// A ContentPolicy decides whether a package revision may establish the objects
+// its package contains.
+type ContentPolicy interface {
+	// Check returns an error if any of the supplied objects are not allowed.
+	Check(objs []runtime.Object) error
+
+	// CheckDependencyGroups returns an error if any of the supplied objects
+	// that must be of an API group defined by the package's provider
+	// dependencies isn't. The supplied function returns those groups. It's
+	// only called once the package's dependencies are resolved.
+	CheckDependencyGroups(objs []runtime.Object, groups func() ([]string, error)) error
+}
+
+// A NopContentPolicy allows all objects.
+type NopContentPolicy struct{}
+
+// Check always returns nil.
+func (NopContentPolicy) Check(_ []runtime.Object) error { return nil }
+
+// CheckDependencyGroups always returns nil.
+func (NopContentPolicy) CheckDependencyGroups(_ []runtime.Object, _ func() ([]string, error)) error {
+	return nil
+}
+
+const (
+	// AnyGroup matches a kind of any API group in a KindAllowList.
+	AnyGroup = "*"
+
+	// DependencyGroup matches a kind of any API group defined by the CRDs of
+	// the package's provider dependencies in a KindAllowList.
+	DependencyGroup = "<provider-dependencies>"
+)
+
+// A KindAllowList is a ContentPolicy that only allows objects of the listed
+// kinds. A listed kind whose group is AnyGroup allows that kind of any group. A
+// listed kind whose group is DependencyGroup allows that kind of any group
+// defined by one of the package's provider dependencies.
+type KindAllowList []schema.GroupKind
+
+// Check returns an error listing the kinds of any supplied objects that aren't
+// in the allow-list.
+func (l KindAllowList) Check(objs []runtime.Object) error {
+	var disallowed []string
+	for _, o := range objs {
+		gk := o.GetObjectKind().GroupVersionKind().GroupKind()
+		if l.allows(gk) || slices.Contains(disallowed, gk.String()) {
+			continue
+		}
+		disallowed = append(disallowed, gk.String())
+	}
+	if len(disallowed) == 0 {
+		return nil
+	}
+	slices.Sort(disallowed)
+	return errors.Errorf(errFmtDisallowedKinds, strings.Join(disallowed, ", "))
+}
+
+// CheckDependencyGroups returns an error listing the kinds of any supplied
+// objects that are only allowed if their API group is defined by one of the
+// package's provider dependencies, and isn't.
+func (l KindAllowList) CheckDependencyGroups(objs []runtime.Object, groups func() ([]string, error)) error {
+	var (
+		allowed    []string
+		fetched    bool
+		disallowed []string
+	)
+	for _, o := range objs {
+		gk := o.GetObjectKind().GroupVersionKind().GroupKind()
+		if !l.allowsDependencyGroup(gk) {
+			continue
+		}
+		if !fetched {
+			g, err := groups()
+			if err != nil {
+				return err
+			}
+			allowed, fetched = g, true
+		}
+		if slices.Contains(allowed, gk.Group) || slices.Contains(disallowed, gk.String()) {
+			continue
+		}
+		disallowed = append(disallowed, gk.String())
+	}
+	if len(disallowed) == 0 {
+		return nil
+	}
+	slices.Sort(disallowed)
+	return errors.Errorf(errFmtDisallowedGroups, strings.Join(disallowed, ", "))
+}
+
+func (l KindAllowList) allows(gk schema.GroupKind) bool {
+	return slices.Contains(l, gk) || slices.Contains(l, schema.GroupKind{Group: AnyGroup, Kind: gk.Kind}) || slices.Contains(l, schema.GroupKind{Group: DependencyGroup, Kind: gk.Kind})
+}
+
+// allowsDependencyGroup returns true if the supplied kind is only allowed if
+// its API group is defined by one of the package's provider dependencies.
+func (l KindAllowList) allowsDependencyGroup(gk schema.GroupKind) bool {
+	return slices.Contains(l, schema.GroupKind{Group: DependencyGroup, Kind: gk.Kind}) && !slices.Contains(l, gk) && !slices.Contains(l, schema.GroupKind{Group: AnyGroup, Kind: gk.Kind})
+}
+
+// ProviderKinds are the kinds of object a provider package may contain.
+// Providers may ship webhook configurations for their CRDs.
+func ProviderKinds() KindAllowList {
+	return KindAllowList{
+		{Group: extv1.GroupName, Kind: "CustomResourceDefinition"},
+		{Group: admv1.GroupName, Kind: "MutatingWebhookConfiguration"},
+		{Group: admv1.GroupName, Kind: "ValidatingWebhookConfiguration"},
+	}
+}
+
+// ConfigurationKinds are the kinds of object a configuration package may
+// contain. Configurations may ship ProviderConfigs for the providers they
+// depend on, but not for other providers.
+func ConfigurationKinds() KindAllowList {
+	return KindAllowList{
+		{Group: extv1.GroupName, Kind: "CustomResourceDefinition"},
+		apiextensionsv1.CompositeResourceDefinitionGroupVersionKind.GroupKind(),
+		apiextensionsv1.CompositionGroupVersionKind.GroupKind(),
+		{Group: DependencyGroup, Kind: xpkg.ProviderConfigKind},
+	}
+}
+
+// FunctionKinds are the kinds of object a function package may contain.
+func FunctionKinds() KindAllowList {
+	return KindAllowList{
+		{Group: extv1.GroupName, Kind: "CustomResourceDefinition"},
+	}
+}
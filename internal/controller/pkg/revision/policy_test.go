@@ -0,0 +1,189 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestKindAllowListCheck(t *testing.T) {
+	crd := &extv1.CustomResourceDefinition{TypeMeta: metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition"}}
+	xrd := &v1.CompositeResourceDefinition{TypeMeta: metav1.TypeMeta{APIVersion: v1.SchemeGroupVersion.String(), Kind: v1.CompositeResourceDefinitionKind}}
+	comp := &v1.Composition{TypeMeta: metav1.TypeMeta{APIVersion: v1.SchemeGroupVersion.String(), Kind: v1.CompositionKind}}
+	deploy := &appsv1.Deployment{TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"}}
+	sa := &corev1.ServiceAccount{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ServiceAccount"}}
+	pc := &unstructured.Unstructured{}
+	pc.SetAPIVersion("nop.crossplane.io/v1alpha1")
+	pc.SetKind("ProviderConfig")
+
+	type args struct {
+		l    KindAllowList
+		objs []runtime.Object
+	}
+	type want struct {
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Allowed": {
+			reason: "A configuration should be allowed to contain CRDs, XRDs, and Compositions.",
+			args: args{
+				l:    ConfigurationKinds(),
+				objs: []runtime.Object{crd, xrd, comp},
+			},
+			want: want{},
+		},
+		"Disallowed": {
+			reason: "A provider should not be allowed to contain Compositions. Each disallowed kind should be reported once.",
+			args: args{
+				l:    ProviderKinds(),
+				objs: []runtime.Object{crd, comp, deploy, comp, sa},
+			},
+			want: want{
+				err: errors.Errorf(errFmtDisallowedKinds, "Composition.apiextensions.crossplane.io, Deployment.apps, ServiceAccount"),
+			},
+		},
+		"AllowedDependencyGroup": {
+			reason: "A configuration's ProviderConfigs should pass the kind check whatever their group. Their group is checked once dependencies are resolved.",
+			args: args{
+				l:    ConfigurationKinds(),
+				objs: []runtime.Object{xrd, pc},
+			},
+			want: want{},
+		},
+		"DisallowedAnyGroup": {
+			reason: "A provider should not be allowed to contain ProviderConfigs.",
+			args: args{
+				l:    ProviderKinds(),
+				objs: []runtime.Object{crd, pc},
+			},
+			want: want{
+				err: errors.Errorf(errFmtDisallowedKinds, "ProviderConfig.nop.crossplane.io"),
+			},
+		},
+		"NoObjects": {
+			reason: "A package with no objects should be allowed.",
+			args: args{
+				l: FunctionKinds(),
+			},
+			want: want{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.args.l.Check(tc.args.objs)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCheck(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestKindAllowListCheckDependencyGroups(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	xrd := &v1.CompositeResourceDefinition{TypeMeta: metav1.TypeMeta{APIVersion: v1.SchemeGroupVersion.String(), Kind: v1.CompositeResourceDefinitionKind}}
+	pc := &unstructured.Unstructured{}
+	pc.SetAPIVersion("nop.crossplane.io/v1alpha1")
+	pc.SetKind("ProviderConfig")
+	other := &unstructured.Unstructured{}
+	other.SetAPIVersion("other.crossplane.io/v1alpha1")
+	other.SetKind("ProviderConfig")
+
+	type args struct {
+		l      KindAllowList
+		objs   []runtime.Object
+		groups func() ([]string, error)
+	}
+	type want struct {
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"DependencyGroup": {
+			reason: "A configuration should be allowed to contain ProviderConfigs of the groups its provider dependencies define.",
+			args: args{
+				l:      ConfigurationKinds(),
+				objs:   []runtime.Object{xrd, pc},
+				groups: func() ([]string, error) { return []string{"nop.crossplane.io"}, nil },
+			},
+			want: want{},
+		},
+		"OtherGroup": {
+			reason: "A configuration should not be allowed to contain ProviderConfigs of groups its provider dependencies don't define.",
+			args: args{
+				l:      ConfigurationKinds(),
+				objs:   []runtime.Object{xrd, pc, other, other},
+				groups: func() ([]string, error) { return []string{"nop.crossplane.io"}, nil },
+			},
+			want: want{
+				err: errors.Errorf(errFmtDisallowedGroups, "ProviderConfig.other.crossplane.io"),
+			},
+		},
+		"GroupsError": {
+			reason: "We should return any error encountered getting the groups the provider dependencies define.",
+			args: args{
+				l:      ConfigurationKinds(),
+				objs:   []runtime.Object{pc},
+				groups: func() ([]string, error) { return nil, errBoom },
+			},
+			want: want{
+				err: errBoom,
+			},
+		},
+		"NoDependencyGroupKinds": {
+			reason: "We shouldn't get the groups the provider dependencies define if no object needs them.",
+			args: args{
+				l:      ConfigurationKinds(),
+				objs:   []runtime.Object{xrd},
+				groups: func() ([]string, error) { return nil, errBoom },
+			},
+			want: want{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.args.l.CheckDependencyGroups(tc.args.objs, tc.args.groups)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCheckDependencyGroups(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -19,17 +19,21 @@ package revision
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/Masterminds/semver"
+	"github.com/docker/go-units"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -58,8 +62,16 @@ import (
 
 const (
 	reconcileTimeout = 3 * time.Minute
+	// maxFetchBackoffDoublings caps how many times the fetch backoff doubles.
+	maxFetchBackoffDoublings = 10
 	// the max size of a package parsed by the parser.
 	maxPackageSize = 200 << 20 // 100 MB
+	// dependencyCycleWait is how long to wait before checking whether a
+	// dependency cycle was broken.
+	dependencyCycleWait = 5 * time.Minute
+	// sourceUnavailableWait is how long to wait before checking whether an
+	// established revision's unavailable package image is available again.
+	sourceUnavailableWait = 5 * time.Minute
 )
 
 const (
@@ -83,6 +95,13 @@ const (
 	errLintPackage       = "linting package contents failed"
 	errNotOneMeta        = "cannot install package with multiple meta types"
 	errIncompatible      = "incompatible Crossplane version"
+	errFmtDowngraded     = "Crossplane was downgraded from %s to %s"
+	errContentPolicy     = "package violates content policy"
+	errRenderValues      = "cannot render package objects using values"
+	errVerifySignature   = "cannot verify package signature"
+	errScanImage         = "cannot scan package image for vulnerabilities"
+	errFmtVulnerable     = "package image has %d vulnerabilities of severity %s or higher"
+	errSampleFootprint   = "cannot sample package footprint"
 
 	errManifestBuilderOptions = "cannot prepare runtime manifest builder options"
 	errPreHook                = "pre establish runtime hook failed for package"
@@ -109,12 +128,16 @@ const (
 	errGetRuntimeConfig    = "cannot get referenced deployment runtime config"
 	errGetServiceAccount   = "cannot get Crossplane service account"
 
-	reconcilePausedMsg = "Reconciliation (including deletion) is paused via the pause annotation"
+	reconcilePausedMsg     = "Reconciliation (including deletion) is paused via the pause annotation"
+	reconcileSpecPausedMsg = "Reconciliation is paused because the parent package is paused via spec.paused"
 )
 
 // Event reasons.
 const (
 	reasonParse        event.Reason = "ParsePackage"
+	reasonPull         event.Reason = "PullPackage"
+	reasonVerify       event.Reason = "VerifyPackage"
+	reasonScan         event.Reason = "ScanPackage"
 	reasonLint         event.Reason = "LintPackage"
 	reasonDependencies event.Reason = "ResolveDependencies"
 	reasonSync         event.Reason = "SyncPackage"
@@ -185,6 +208,59 @@ func WithRuntimeHooks(h RuntimeHooks) ReconcilerOption {
 	}
 }
 
+// WithRuntimePlatforms specifies the platforms, in os[/arch] form, that
+// package runtime pods may be scheduled to.
+func WithRuntimePlatforms(p []string) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.platforms = p
+	}
+}
+
+// WithSignatureVerifier specifies how the Reconciler should verify the
+// signatures of package images.
+func WithSignatureVerifier(v SignatureVerifier) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.verifier = v
+	}
+}
+
+// WithVulnerabilityScanner specifies how the Reconciler should scan package
+// images for vulnerabilities.
+func WithVulnerabilityScanner(s VulnerabilityScanner) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.scanner = s
+	}
+}
+
+// WithFootprintSampler specifies how the Reconciler should sample the
+// footprint of active package revisions, and how often.
+func WithFootprintSampler(s FootprintSampler, interval time.Duration) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.footprints = s
+		r.footprintInterval = interval
+	}
+}
+
+// WithFetchRetries specifies how many times the Reconciler should retry
+// fetching a package image that failed for a transient reason, e.g. because
+// the registry rate limited the request, before it considers the package
+// revision unhealthy. The Reconciler backs off for the supplied duration
+// before the first retry, doubling it for each subsequent retry.
+func WithFetchRetries(retries int64, backoff time.Duration) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.fetchRetries = retries
+		r.fetchBackoff = backoff
+	}
+}
+
+// WithFootprintRecorder specifies how the Reconciler should record sampled
+// package footprints.
+func WithFootprintRecorder(fr FootprintRecorder) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.footprintRecorder = fr
+	}
+}
+
 // WithEstablisher specifies how the Reconciler should establish package resources.
 func WithEstablisher(e Establisher) ReconcilerOption {
 	return func(r *Reconciler) {
@@ -192,6 +268,14 @@ func WithEstablisher(e Establisher) ReconcilerOption {
 	}
 }
 
+// WithContentPolicy specifies which objects the Reconciler allows a package to
+// establish.
+func WithContentPolicy(p ContentPolicy) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.policy = p
+	}
+}
+
 // WithParser specifies how the Reconciler should parse a package.
 func WithParser(p parser.Parser) ReconcilerOption {
 	return func(r *Reconciler) {
@@ -249,6 +333,40 @@ func uniqueResourceIdentifier(ref xpv1.TypedReference) string {
 	return strings.Join([]string{ref.GroupVersionKind().String(), ref.Name}, "/")
 }
 
+// downgraded returns true if the running version of Crossplane is lower than
+// the supplied previous version. Versions that aren't semantic versions are
+// never considered downgraded.
+func downgraded(v version.Operations, prev string) bool {
+	if prev == "" {
+		return false
+	}
+	pv, err := semver.NewVersion(prev)
+	if err != nil {
+		return false
+	}
+	cv, err := v.GetSemVer()
+	if err != nil {
+		return false
+	}
+	return cv.LessThan(pv)
+}
+
+// An eventPullReporter records the size of a package revision's image in its
+// status, and emits events as the image's layers are pulled.
+type eventPullReporter struct {
+	pr     v1.PackageRevision
+	record event.Recorder
+}
+
+func (e *eventPullReporter) PullingImage(layers int, size int64) {
+	e.pr.SetPackageImage(&v1.PackageImage{Layers: int64(layers), Size: size})
+	e.record.Event(e.pr, event.Normal(reasonPull, fmt.Sprintf("Pulling package image with %d layers totalling %s", layers, units.HumanSize(float64(size)))))
+}
+
+func (e *eventPullReporter) PullingLayer(digest string, pulled, size int64) {
+	e.record.Event(e.pr, event.Normal(reasonPull, fmt.Sprintf("Pulled %s of %s of package image layer %s", units.HumanSize(float64(pulled)), units.HumanSize(float64(size)), digest)))
+}
+
 // Reconciler reconciles packages.
 type Reconciler struct {
 	client         client.Client
@@ -257,6 +375,7 @@ type Reconciler struct {
 	lock           DependencyManager
 	runtimeHook    RuntimeHooks
 	objects        Establisher
+	policy         ContentPolicy
 	parser         parser.Parser
 	linter         parser.Linter
 	versioner      version.Operations
@@ -266,6 +385,16 @@ type Reconciler struct {
 	features       *feature.Flags
 	namespace      string
 	serviceAccount string
+	platforms      []string
+	verifier       SignatureVerifier
+	scanner        VulnerabilityScanner
+
+	footprints        FootprintSampler
+	footprintRecorder FootprintRecorder
+	footprintInterval time.Duration
+
+	fetchRetries int64
+	fetchBackoff time.Duration
 
 	newPackageRevision func() v1.PackageRevision
 }
@@ -288,7 +417,11 @@ func SetupProviderRevision(mgr ctrl.Manager, o controller.Options) error {
 	if err != nil {
 		return errors.New(errCannotBuildObjectSchema)
 	}
-	fetcher, err := xpkg.NewK8sFetcher(clientset, append(o.FetcherOptions, xpkg.WithNamespace(o.Namespace), xpkg.WithServiceAccount(o.ServiceAccount))...)
+	fetcher, err := o.Fetcher(clientset)
+	if err != nil {
+		return errors.Wrap(err, errCannotBuildFetcher)
+	}
+	sources, err := o.ImageSources(clientset, fetcher)
 	if err != nil {
 		return errors.Wrap(err, errCannotBuildFetcher)
 	}
@@ -307,20 +440,41 @@ func SetupProviderRevision(mgr ctrl.Manager, o controller.Options) error {
 	ro := []ReconcilerOption{
 		WithCache(o.Cache),
 		WithDependencyManager(NewPackageDependencyManager(mgr.GetClient(), dag.NewMapDag, v1beta1.ProviderPackageType)),
-		WithEstablisher(NewAPIEstablisher(mgr.GetClient(), o.Namespace, o.MaxConcurrentPackageEstablishers)),
+		WithEstablisher(NewAPIEstablisher(mgr.GetClient(), o.Namespace, o.MaxConcurrentPackageEstablishers, WithAPIChecker(NewDiscoveryAPIChecker(clientset.Discovery())))),
 		WithNewPackageRevisionFn(nr),
-		WithParser(parser.New(metaScheme, objScheme)),
-		WithParserBackend(NewImageBackend(fetcher, WithDefaultRegistry(o.DefaultRegistry))),
+		WithParser(xpkg.NewParser(metaScheme, objScheme)),
+		WithParserBackend(NewImageBackend(fetcher, WithDefaultRegistry(o.DefaultRegistry), WithImageSources(sources))),
 		WithLinter(xpkg.NewProviderLinter()),
+		WithContentPolicy(ProviderKinds()),
 		WithLogger(o.Logger.WithValues("controller", name)),
 		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 		WithNamespace(o.Namespace),
 		WithServiceAccount(o.ServiceAccount),
+		WithRuntimePlatforms(o.PackageRuntimePlatforms),
 		WithFeatureFlags(o.Features),
+		WithFetchRetries(o.FetchRetries, o.FetchBackoff),
+	}
+
+	if o.Features.Enabled(features.EnableAlphaSignatureVerification) {
+		ro = append(ro, WithSignatureVerifier(NewImageConfigVerifier(mgr.GetClient(), xpkg.NewCosignVerifier(fetcher), o.Namespace, o.DefaultRegistry, WithRequireVerification(o.RequireSignatures))))
+	}
+
+	if o.Features.Enabled(features.EnableAlphaVulnerabilityScanning) {
+		ro = append(ro, WithVulnerabilityScanner(NewImageConfigScanner(mgr.GetClient(), xpkg.NewHarborScanner(), o.Namespace, o.DefaultRegistry)))
+	}
+
+	if o.FootprintSampleInterval > 0 {
+		fm, err := RegisterFootprintMetrics()
+		if err != nil {
+			return err
+		}
+		ro = append(ro,
+			WithFootprintSampler(NewAPIFootprintSampler(mgr.GetAPIReader(), DefaultFootprintSampleSize), o.FootprintSampleInterval),
+			WithFootprintRecorder(fm))
 	}
 
 	if o.PackageRuntime == controller.PackageRuntimeDeployment {
-		ro = append(ro, WithRuntimeHooks(NewProviderHooks(mgr.GetClient(), o.DefaultRegistry)))
+		ro = append(ro, WithRuntimeHooks(NewProviderHooks(mgr.GetClient(), o.DefaultRegistry, ProviderHooksWithDrainPeriod(o.ProviderDrainPeriod))))
 
 		if o.Features.Enabled(features.EnableBetaDeploymentRuntimeConfigs) {
 			cb = cb.Watches(&v1beta1.DeploymentRuntimeConfig{}, &EnqueueRequestForReferencingProviderRevisions{
@@ -351,31 +505,60 @@ func SetupConfigurationRevision(mgr ctrl.Manager, o controller.Options) error {
 	if err != nil {
 		return errors.New(errCannotBuildObjectSchema)
 	}
-	f, err := xpkg.NewK8sFetcher(cs, append(o.FetcherOptions, xpkg.WithNamespace(o.Namespace), xpkg.WithServiceAccount(o.ServiceAccount))...)
+	f, err := o.Fetcher(cs)
 	if err != nil {
 		return errors.Wrap(err, errCannotBuildFetcher)
 	}
+	sources, err := o.ImageSources(cs, f)
+	if err != nil {
+		return errors.Wrap(err, errCannotBuildFetcher)
+	}
+
+	var dmo []PackageDependencyManagerOption
+	if o.StrictConfigurationDependencies {
+		dmo = append(dmo, PackageDependencyManagerWithHealthyDependencies())
+	}
 
-	r := NewReconciler(mgr,
+	ro := []ReconcilerOption{
 		WithCache(o.Cache),
-		WithDependencyManager(NewPackageDependencyManager(mgr.GetClient(), dag.NewMapDag, v1beta1.ConfigurationPackageType)),
+		WithDependencyManager(NewPackageDependencyManager(mgr.GetClient(), dag.NewMapDag, v1beta1.ConfigurationPackageType, dmo...)),
 		WithNewPackageRevisionFn(nr),
-		WithEstablisher(NewAPIEstablisher(mgr.GetClient(), o.Namespace, o.MaxConcurrentPackageEstablishers)),
-		WithParser(parser.New(metaScheme, objScheme)),
-		WithParserBackend(NewImageBackend(f, WithDefaultRegistry(o.DefaultRegistry))),
+		WithEstablisher(NewAPIEstablisher(mgr.GetClient(), o.Namespace, o.MaxConcurrentPackageEstablishers, WithAPIChecker(NewDiscoveryAPIChecker(cs.Discovery())))),
+		WithParser(xpkg.NewParser(metaScheme, objScheme)),
+		WithParserBackend(NewImageBackend(f, WithDefaultRegistry(o.DefaultRegistry), WithImageSources(sources))),
 		WithLinter(xpkg.NewConfigurationLinter()),
+		WithContentPolicy(ConfigurationKinds()),
 		WithLogger(o.Logger.WithValues("controller", name)),
 		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 		WithNamespace(o.Namespace),
 		WithServiceAccount(o.ServiceAccount),
 		WithFeatureFlags(o.Features),
-	)
+		WithFetchRetries(o.FetchRetries, o.FetchBackoff),
+	}
+
+	if o.Features.Enabled(features.EnableAlphaSignatureVerification) {
+		ro = append(ro, WithSignatureVerifier(NewImageConfigVerifier(mgr.GetClient(), xpkg.NewCosignVerifier(f), o.Namespace, o.DefaultRegistry, WithRequireVerification(o.RequireSignatures))))
+	}
+
+	if o.Features.Enabled(features.EnableAlphaVulnerabilityScanning) {
+		ro = append(ro, WithVulnerabilityScanner(NewImageConfigScanner(mgr.GetClient(), xpkg.NewHarborScanner(), o.Namespace, o.DefaultRegistry)))
+	}
+
+	if o.FootprintSampleInterval > 0 {
+		fm, err := RegisterFootprintMetrics()
+		if err != nil {
+			return err
+		}
+		ro = append(ro,
+			WithFootprintSampler(NewAPIFootprintSampler(mgr.GetAPIReader(), DefaultFootprintSampleSize), o.FootprintSampleInterval),
+			WithFootprintRecorder(fm))
+	}
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		For(&v1.ConfigurationRevision{}).
 		WithOptions(o.ForControllerRuntime()).
-		Complete(ratelimiter.NewReconciler(name, errors.WithSilentRequeueOnConflict(r), o.GlobalRateLimiter))
+		Complete(ratelimiter.NewReconciler(name, errors.WithSilentRequeueOnConflict(NewReconciler(mgr, ro...)), o.GlobalRateLimiter))
 }
 
 // SetupFunctionRevision adds a controller that reconciles FunctionRevisions.
@@ -396,7 +579,11 @@ func SetupFunctionRevision(mgr ctrl.Manager, o controller.Options) error {
 	if err != nil {
 		return errors.New(errCannotBuildObjectSchema)
 	}
-	fetcher, err := xpkg.NewK8sFetcher(clientset, append(o.FetcherOptions, xpkg.WithNamespace(o.Namespace), xpkg.WithServiceAccount(o.ServiceAccount))...)
+	fetcher, err := o.Fetcher(clientset)
+	if err != nil {
+		return errors.Wrap(err, errCannotBuildFetcher)
+	}
+	sources, err := o.ImageSources(clientset, fetcher)
 	if err != nil {
 		return errors.Wrap(err, errCannotBuildFetcher)
 	}
@@ -415,16 +602,37 @@ func SetupFunctionRevision(mgr ctrl.Manager, o controller.Options) error {
 	ro := []ReconcilerOption{
 		WithCache(o.Cache),
 		WithDependencyManager(NewPackageDependencyManager(mgr.GetClient(), dag.NewMapDag, v1beta1.FunctionPackageType)),
-		WithEstablisher(NewAPIEstablisher(mgr.GetClient(), o.Namespace, o.MaxConcurrentPackageEstablishers)),
+		WithEstablisher(NewAPIEstablisher(mgr.GetClient(), o.Namespace, o.MaxConcurrentPackageEstablishers, WithAPIChecker(NewDiscoveryAPIChecker(clientset.Discovery())))),
 		WithNewPackageRevisionFn(nr),
-		WithParser(parser.New(metaScheme, objScheme)),
-		WithParserBackend(NewImageBackend(fetcher, WithDefaultRegistry(o.DefaultRegistry))),
+		WithParser(xpkg.NewParser(metaScheme, objScheme)),
+		WithParserBackend(NewImageBackend(fetcher, WithDefaultRegistry(o.DefaultRegistry), WithImageSources(sources))),
 		WithLinter(xpkg.NewFunctionLinter()),
+		WithContentPolicy(FunctionKinds()),
 		WithLogger(o.Logger.WithValues("controller", name)),
 		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 		WithNamespace(o.Namespace),
 		WithServiceAccount(o.ServiceAccount),
+		WithRuntimePlatforms(o.PackageRuntimePlatforms),
 		WithFeatureFlags(o.Features),
+		WithFetchRetries(o.FetchRetries, o.FetchBackoff),
+	}
+
+	if o.Features.Enabled(features.EnableAlphaSignatureVerification) {
+		ro = append(ro, WithSignatureVerifier(NewImageConfigVerifier(mgr.GetClient(), xpkg.NewCosignVerifier(fetcher), o.Namespace, o.DefaultRegistry, WithRequireVerification(o.RequireSignatures))))
+	}
+
+	if o.Features.Enabled(features.EnableAlphaVulnerabilityScanning) {
+		ro = append(ro, WithVulnerabilityScanner(NewImageConfigScanner(mgr.GetClient(), xpkg.NewHarborScanner(), o.Namespace, o.DefaultRegistry)))
+	}
+
+	if o.FootprintSampleInterval > 0 {
+		fm, err := RegisterFootprintMetrics()
+		if err != nil {
+			return err
+		}
+		ro = append(ro,
+			WithFootprintSampler(NewAPIFootprintSampler(mgr.GetAPIReader(), DefaultFootprintSampleSize), o.FootprintSampleInterval),
+			WithFootprintRecorder(fm))
 	}
 
 	if o.PackageRuntime == controller.PackageRuntimeDeployment {
@@ -448,11 +656,16 @@ func NewReconciler(mgr manager.Manager, opts ...ReconcilerOption) *Reconciler {
 		cache:     xpkg.NewNopCache(),
 		revision:  resource.NewAPIFinalizer(mgr.GetClient(), finalizer),
 		objects:   NewNopEstablisher(),
+		policy:    NopContentPolicy{},
 		parser:    parser.New(nil, nil),
 		linter:    parser.NewPackageLinter(nil, nil, nil),
 		versioner: version.New(),
+		scanner:   NopVulnerabilityScanner{},
 		log:       logging.NewNopLogger(),
 		record:    event.NewNopRecorder(),
+
+		footprints:        NopFootprintSampler{},
+		footprintRecorder: NopFootprintRecorder{},
 	}
 
 	for _, f := range opts {
@@ -530,9 +743,22 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			r.record.Event(pr, event.Warning(reasonSync, err))
 			return reconcile.Result{}, err
 		}
+		// Only the active revision's footprint is recorded, so if it's gone
+		// the package is most likely being deleted.
+		if pr.GetDesiredState() == v1.PackageRevisionActive {
+			r.footprintRecorder.Forget(pr)
+		}
 		return reconcile.Result{Requeue: false}, nil
 	}
 
+	// A revision is paused via its spec when its parent package is paused.
+	// Its runtime is left as is until the package is resumed.
+	if ptr.Deref(pr.GetPaused(), false) {
+		r.record.Event(pr, event.Normal(reasonPaused, reconcileSpecPausedMsg))
+		pr.SetConditions(xpv1.ReconcilePaused().WithMessage(reconcileSpecPausedMsg))
+		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, pr), errUpdateStatus)
+	}
+
 	if c := pr.GetCondition(xpv1.ReconcilePaused().Type); c.Reason == xpv1.ReconcilePaused().Reason {
 		pr.CleanConditions()
 	}
@@ -571,16 +797,28 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			if kerrors.IsConflict(err) {
 				return reconcile.Result{Requeue: true}, nil
 			}
+			// The revision's runtime is still handing over to the active
+			// revision's runtime. This isn't an error - check again once it
+			// may have drained.
+			if after, ok := IsDraining(err); ok {
+				log.Debug("Waiting for inactive package revision runtime to drain", "requeue-after", after)
+				pr.SetConditions(v1.RuntimeDraining().WithMessage(err.Error()))
+				return reconcile.Result{RequeueAfter: after}, errors.Wrap(r.client.Status().Update(ctx, pr), errUpdateStatus)
+			}
 			err = errors.Wrap(err, errDeactivateRevision)
 			r.record.Event(pr, event.Warning(reasonDeactivate, err))
 			return reconcile.Result{}, err
 		}
+		if pr.GetCondition(v1.TypeRuntimeHealthy).Reason == v1.ReasonRuntimeDraining {
+			pr.SetConditions(v1.RuntimeDrained())
+		}
 
 		if len(pr.GetObjects()) > 0 {
 			// Note(turkenh): If the revision is inactive we don't need to
 			// fetch/parse the package again, so we can report success and return
 			// here. The only exception is that revision NOT having references
-			// to the objects that it owns, i.e. status.objectRefs is empty.
+			// to the objects that it owns, i.e. status.objectRefGroups (or the
+			// deprecated status.objectRefs) is empty.
 			// This could happen in one of the following two ways:
 			// 1. The revision created as inactive, i.e. was never active before
 			//    which could be possible if package installed with
@@ -593,8 +831,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			// would trigger another reconcile after setting object references
 			// in the status where we finalize the deactivation by transitioning
 			// from "controller" to "owner" on owned resources.
-			// We still want to call r.deactivateRevision() above, i.e. even
-			// status.objectRefs is empty, to make sure that the revision is
+			// We still want to call r.deactivateRevision() above, even if
+			// the revision has no object references, to make sure that it is
 			// removed from the lock which could otherwise block a successful
 			// reconciliation.
 			if pr.GetCondition(v1.TypeHealthy).Status != corev1.ConditionTrue {
@@ -615,6 +853,62 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	// 2. We'll requeue and try the status update again if needed.
 	// 3. There's little else we could do about it apart from log.
 
+	// Verify the package's signature before we activate it. A revision's
+	// package is immutable, so we only need to verify it once. Revisions that
+	// are staged while inactive are verified too, so that activating them
+	// doesn't have to wait for verification. Signatures are only verified if
+	// signature verification is enabled.
+	if r.verifier != nil && pr.GetCondition(v1.TypeVerified).Status != corev1.ConditionTrue {
+		verified, err := r.verifier.Verify(ctx, pr)
+		if err != nil {
+			err = errors.Wrap(err, errVerifySignature)
+			pr.SetConditions(v1.SignatureVerificationFailed().WithMessage(err.Error()))
+			pr.SetConditions(v1.Unhealthy().WithMessage(err.Error()))
+			_ = r.client.Status().Update(ctx, pr)
+
+			r.record.Event(pr, event.Warning(reasonVerify, err))
+
+			return reconcile.Result{}, err
+		}
+		if verified {
+			pr.SetConditions(v1.SignatureVerified())
+		} else {
+			pr.SetConditions(v1.SignatureVerificationSkipped())
+		}
+	}
+
+	// Scan the package's image for vulnerabilities before we activate it.
+	// Once a scan passes we don't scan again, but a scan that found blocking
+	// vulnerabilities is retried in case they're fixed, e.g. by updating the
+	// scanner's vulnerability database or the ImageConfig's severity.
+	if pr.GetCondition(v1.TypeScanned).Status != corev1.ConditionTrue {
+		res, err := r.scanner.Scan(ctx, pr)
+		if err != nil {
+			err = errors.Wrap(err, errScanImage)
+			pr.SetConditions(v1.VulnerabilityScanFailed().WithMessage(err.Error()))
+			pr.SetConditions(v1.Unhealthy().WithMessage(err.Error()))
+			_ = r.client.Status().Update(ctx, pr)
+
+			r.record.Event(pr, event.Warning(reasonScan, err))
+
+			return reconcile.Result{}, err
+		}
+		if res != nil {
+			pr.SetVulnerabilities(res.Summary())
+			if b := res.Blocking(); len(b) > 0 {
+				err := errors.Errorf(errFmtVulnerable, len(b), res.FailOnSeverity)
+				pr.SetConditions(v1.VulnerabilitiesFound().WithMessage(err.Error()))
+				pr.SetConditions(v1.Unhealthy().WithMessage(err.Error()))
+				_ = r.client.Status().Update(ctx, pr)
+
+				r.record.Event(pr, event.Warning(reasonScan, err))
+
+				return reconcile.Result{}, err
+			}
+			pr.SetConditions(v1.VulnerabilityScanPassed())
+		}
+	}
+
 	pullPolicyNever := false
 	id := pr.GetName()
 	// If packagePullPolicy is Never, the identifier is the package source and
@@ -658,19 +952,48 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	// If we didn't get a ReadCloser from cache, we need to get it from image.
 	if rc == nil {
 		// Initialize parser backend to obtain package contents.
-		imgrc, err := r.backend.Init(ctx, PackageRevision(pr))
+		imgrc, err := r.backend.Init(ctx, PackageRevision(pr), ReportPullProgress(&eventPullReporter{pr: pr, record: r.record}))
 		if err != nil {
 			err = errors.Wrap(err, errInitParserBackend)
-			pr.SetConditions(v1.Unhealthy().WithMessage(err.Error()))
-			_ = r.client.Status().Update(ctx, pr)
-
 			r.record.Event(pr, event.Warning(reasonParse, err))
 
+			// The image isn't cached and the registry no longer has it. If
+			// the revision was already established there's nothing to gain
+			// by marking it unhealthy - its objects and runtime are fine.
+			// Leave them untouched, and check occasionally whether the
+			// image is available again.
+			if xpkg.IsSourceUnavailableError(err) && len(pr.GetObjects()) > 0 {
+				pr.SetConditions(v1.SourceUnavailable().WithMessage(err.Error()))
+				return reconcile.Result{RequeueAfter: sourceUnavailableWait}, errors.Wrap(r.client.Status().Update(ctx, pr), errUpdateStatus)
+			}
+
+			attempt := pr.GetFetchAttempts() + 1
+			pr.SetFetchAttempts(attempt)
+
+			// Retry transient failures without marking the revision
+			// unhealthy, so that e.g. registry rate limiting doesn't
+			// cause it to flap.
+			if xpkg.IsTransientFetchError(err) && attempt <= r.fetchRetries {
+				delay := r.fetchBackoff << min(attempt-1, maxFetchBackoffDoublings)
+				pr.SetConditions(v1.FetchFailed(attempt, &metav1.Time{Time: time.Now().Add(delay)}, err))
+				return reconcile.Result{RequeueAfter: delay}, errors.Wrap(r.client.Status().Update(ctx, pr), errUpdateStatus)
+			}
+
+			pr.SetConditions(v1.FetchFailed(attempt, nil, err), v1.Unhealthy().WithMessage(err.Error()))
+			_ = r.client.Status().Update(ctx, pr)
+
 			// Requeue because we may be waiting for parent package
 			// controller to recreate Pod.
 			return reconcile.Result{}, err
 		}
 
+		// Only report that the image was fetched if we previously reported
+		// that fetching it failed, or that it was unavailable.
+		if reason := pr.GetCondition(v1.TypeFetched).Reason; pr.GetFetchAttempts() > 0 || reason == v1.ReasonFetchFailed || reason == v1.ReasonSourceUnavailable {
+			pr.SetFetchAttempts(0)
+			pr.SetConditions(v1.Fetched())
+		}
+
 		// Package is not in cache, so we write it to the cache while parsing.
 		pipeR, pipeW := io.Pipe()
 		rc = xpkg.TeeReadCloser(imgrc, pipeW)
@@ -711,8 +1034,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{}, err
 	}
 
+	// Record what we found in the package, so that package authors can see
+	// exactly what we thought of it.
+	contents := summarizeContents(pkg)
+	pr.SetContents(contents)
+
 	// Lint package using package-specific linter.
 	if err := r.linter.Lint(pkg); err != nil {
+		contents.LintError = err.Error()
 		err = errors.Wrap(err, errLintPackage)
 		pr.SetConditions(v1.Unhealthy().WithMessage(err.Error()))
 		_ = r.client.Status().Update(ctx, pr)
@@ -758,25 +1087,69 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{}, err
 	}
 
-	// Check Crossplane constraints if they exist.
-	if pr.GetIgnoreCrossplaneConstraints() == nil || !*pr.GetIgnoreCrossplaneConstraints() {
-		if err := xpkg.PackageCrossplaneCompatible(r.versioner)(pkgMeta); err != nil {
-			err = errors.Wrap(err, errIncompatible)
-			pr.SetConditions(v1.Unhealthy().WithMessage(err.Error()))
+	// Check Crossplane constraints if they exist. A package that ignores its
+	// Crossplane constraints is still checked against known broken
+	// crossplane-runtime skews. Ignoring constraints is for Crossplane
+	// versions the package's author didn't anticipate, not for versions that
+	// are known not to work.
+	checkConstraints := pr.GetIgnoreCrossplaneConstraints() == nil || !*pr.GetIgnoreCrossplaneConstraints()
+	var incompatible error
+	if checkConstraints {
+		incompatible = xpkg.PackageCrossplaneCompatible(r.versioner)(pkgMeta)
+	}
+	if incompatible == nil {
+		incompatible = xpkg.PackageRuntimeCompatible(r.versioner, version.KnownBrokenSkews)(pkgMeta)
+	}
+	if incompatible != nil {
+		err := errors.Wrap(incompatible, errIncompatible)
+		if prev := pr.GetCrossplaneVersion(); downgraded(r.versioner, prev) {
+			err = errors.Wrapf(err, errFmtDowngraded, prev, r.versioner.GetVersionString())
+		}
+		pr.SetConditions(v1.IncompatibleCrossplaneVersion().WithMessage(err.Error()))
 
-			r.record.Event(pr, event.Warning(reasonLint, err))
+		r.record.Event(pr, event.Warning(reasonLint, err))
 
-			// No need to requeue if outside version constraints.
-			// Package will either need to be updated or ignore
-			// crossplane constraints will need to be specified,
-			// both of which will trigger a new reconcile.
-			return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, pr), errUpdateStatus)
-		}
+		// No need to requeue if outside version constraints.
+		// Package will either need to be updated or ignore
+		// crossplane constraints will need to be specified,
+		// both of which will trigger a new reconcile.
+		return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, pr), errUpdateStatus)
 	}
 
-	// Check status of package dependencies unless package specifies to skip
-	// resolution.
-	if pr.GetSkipDependencyResolution() != nil && !*pr.GetSkipDependencyResolution() {
+	// Record the version of Crossplane that found the package compatible,
+	// so we can tell whether it was later downgraded.
+	if p, ok := xpkg.TryConvertToPkg(pkgMeta, &pkgmetav1.Provider{}, &pkgmetav1.Configuration{}, &pkgmetav1.Function{}); ok && checkConstraints && p.GetCrossplaneConstraints() != nil {
+		pr.SetCrossplaneVersion(r.versioner.GetVersionString())
+	}
+
+	// Ignoring Crossplane constraints doesn't enable the features a package
+	// requires, so we always check them.
+	if err := xpkg.PackageFeaturesEnabled(r.features)(pkgMeta); err != nil {
+		pr.SetConditions(v1.RequiredFeaturesDisabled().WithMessage(err.Error()))
+
+		r.record.Event(pr, event.Warning(reasonLint, err))
+
+		// No need to requeue. Feature flags can only be enabled by
+		// restarting Crossplane, which will trigger a new reconcile.
+		return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, pr), errUpdateStatus)
+	}
+
+	// Refuse to establish objects the package isn't allowed to contain, before
+	// we create anything for the package revision or add it to the Lock.
+	if err := r.policy.Check(pkg.GetObjects()); err != nil {
+		err = errors.Wrap(err, errContentPolicy)
+		pr.SetConditions(v1.ContentPolicyViolation().WithMessage(err.Error()))
+
+		r.record.Event(pr, event.Warning(reasonLint, err))
+
+		// No need to requeue. The package's contents won't change.
+		return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, pr), errUpdateStatus)
+	}
+
+	// Check status of package dependencies. A package that skips dependency
+	// resolution still checks its dependencies, but the package manager won't
+	// install them.
+	if pr.GetSkipDependencyResolution() != nil {
 		found, installed, invalid, err := r.lock.Resolve(ctx, pkgMeta, pr)
 		pr.SetDependencyStatus(int64(found), int64(installed), int64(invalid))
 		if err != nil {
@@ -785,13 +1158,53 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			}
 
 			err = errors.Wrap(err, errResolveDeps)
-			pr.SetConditions(v1.UnknownHealth().WithMessage(err.Error()))
+
+			// A dependency cycle won't be broken until one of the packages
+			// in it changes, so we don't retry with backoff. We check again
+			// occasionally in case another package in the cycle changed.
+			ce := &dag.CycleError{}
+			if errors.As(err, &ce) {
+				pr.SetConditions(v1.UnknownHealth().WithMessage(err.Error()), v1.DependencyCycle().WithMessage(err.Error()))
+				r.record.Event(pr, event.Warning(reasonDependencies, err))
+				return reconcile.Result{RequeueAfter: dependencyCycleWait}, errors.Wrap(r.client.Status().Update(ctx, pr), errUpdateStatus)
+			}
+
+			pr.SetConditions(v1.UnknownHealth().WithMessage(err.Error()), v1.DependenciesUnsatisfied().WithMessage(err.Error()))
 			_ = r.client.Status().Update(ctx, pr)
 
 			r.record.Event(pr, event.Warning(reasonDependencies, err))
 
 			return reconcile.Result{}, err
 		}
+		pr.SetConditions(v1.DependenciesSatisfied())
+	}
+
+	// Some objects, e.g. a Configuration's ProviderConfigs, may only be of an
+	// API group defined by the package's provider dependencies. We can only
+	// check them once the dependencies are installed. A dependency may not
+	// have established its CRDs yet, so we retry.
+	if err := r.policy.CheckDependencyGroups(pkg.GetObjects(), func() ([]string, error) { return r.lock.ProviderGroups(ctx, pr) }); err != nil {
+		err = errors.Wrap(err, errContentPolicy)
+		pr.SetConditions(v1.ContentPolicyViolation().WithMessage(err.Error()))
+		_ = r.client.Status().Update(ctx, pr)
+
+		r.record.Event(pr, event.Warning(reasonLint, err))
+
+		return reconcile.Result{}, err
+	}
+
+	// Render any ProviderConfigs the package includes using the values
+	// supplied by its package.
+	if prv, ok := pr.(v1.PackageRevisionWithValues); ok {
+		if err := renderProviderConfigs(pkg.GetObjects(), prv.GetValues()); err != nil {
+			err = errors.Wrap(err, errRenderValues)
+			pr.SetConditions(v1.Unhealthy().WithMessage(err.Error()))
+
+			r.record.Event(pr, event.Warning(reasonSync, err))
+
+			// No need to requeue. We'll be requeued when the values change.
+			return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, pr), errUpdateStatus)
+		}
 	}
 
 	if hasRuntime && r.runtimeHook != nil {
@@ -818,7 +1231,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		}
 
 		err = errors.Wrap(err, errEstablishControl)
-		pr.SetConditions(v1.Unhealthy().WithMessage(err.Error()))
+		pr.SetConditions(v1.Unhealthy().WithMessage(err.Error()), v1.APIsNotEstablished().WithMessage(err.Error()))
 		_ = r.client.Status().Update(ctx, pr)
 
 		r.record.Event(pr, event.Warning(reasonSync, err))
@@ -842,6 +1255,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return uniqueResourceIdentifier(refs[i]) > uniqueResourceIdentifier(refs[j])
 	})
 	pr.SetObjects(refs)
+	pr.SetConditions(v1.APIsEstablished())
 
 	if hasRuntime && r.runtimeHook != nil {
 		if err := r.runtimeHook.Post(ctx, pkgMeta, pwr, runtimeManifestBuilder); err != nil {
@@ -850,13 +1264,32 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			}
 
 			err = errors.Wrap(err, errPostHook)
-			pr.SetConditions(v1.Unhealthy().WithMessage(err.Error()))
+			pr.SetConditions(v1.Unhealthy().WithMessage(err.Error()), v1.RuntimeUnhealthy().WithMessage(err.Error()))
 			_ = r.client.Status().Update(ctx, pr)
 
 			r.record.Event(pr, event.Warning(reasonSync, err))
 
 			return reconcile.Result{}, err
 		}
+		pr.SetConditions(v1.RuntimeHealthy())
+	}
+
+	// Sampling a package's footprint is best effort. We don't want to mark
+	// the revision unhealthy because we couldn't sample it.
+	result := reconcile.Result{Requeue: false}
+	if pr.GetDesiredState() == v1.PackageRevisionActive && r.footprintInterval > 0 {
+		if footprintStale(pr.GetFootprint(), r.footprintInterval) {
+			fp, err := r.footprints.Sample(ctx, pr)
+			if err != nil {
+				log.Debug(errSampleFootprint, "error", err)
+				r.record.Event(pr, event.Warning(reasonSync, errors.Wrap(err, errSampleFootprint)))
+			}
+			if fp != nil {
+				pr.SetFootprint(fp)
+				r.footprintRecorder.Record(pr, fp)
+			}
+		}
+		result.RequeueAfter = r.footprintInterval
 	}
 
 	if pr.GetCondition(v1.TypeHealthy).Status != corev1.ConditionTrue {
@@ -865,7 +1298,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		r.record.Event(pr, event.Normal(reasonSync, "Successfully configured package revision"))
 	}
 	pr.SetConditions(v1.Healthy())
-	return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, pr), errUpdateStatus)
+	return result, errors.Wrap(r.client.Status().Update(ctx, pr), errUpdateStatus)
 }
 
 func (r *Reconciler) deactivateRevision(ctx context.Context, pr v1.PackageRevision, runtimeManifestBuilder ManifestBuilder) error {
@@ -934,5 +1367,9 @@ func (r *Reconciler) runtimeManifestBuilderOptions(ctx context.Context, pwr v1.P
 		opts = append(opts, RuntimeManifestBuilderWithServiceAccountPullSecrets(sa.ImagePullSecrets))
 	}
 
+	if len(r.platforms) > 0 {
+		opts = append(opts, RuntimeManifestBuilderWithPlatforms(r.platforms))
+	}
+
 	return opts, nil
 }
@@ -20,9 +20,13 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"net/http"
 	"testing"
+	"time"
 
+	"github.com/Masterminds/semver"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -45,6 +49,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/dag"
 	verfake "github.com/crossplane/crossplane/internal/version/fake"
 	"github.com/crossplane/crossplane/internal/xpkg"
 	xpkgfake "github.com/crossplane/crossplane/internal/xpkg/fake"
@@ -88,6 +93,21 @@ func (e *MockEstablisher) ReleaseObjects(context.Context, v1.PackageRevision) er
 	return e.MockRelinquish()
 }
 
+var _ ContentPolicy = &MockContentPolicy{}
+
+type MockContentPolicy struct {
+	MockCheck                 func(objs []runtime.Object) error
+	MockCheckDependencyGroups func(objs []runtime.Object, groups func() ([]string, error)) error
+}
+
+func (p *MockContentPolicy) Check(objs []runtime.Object) error {
+	return p.MockCheck(objs)
+}
+
+func (p *MockContentPolicy) CheckDependencyGroups(objs []runtime.Object, groups func() ([]string, error)) error {
+	return p.MockCheckDependencyGroups(objs, groups)
+}
+
 var _ RuntimeHooks = &MockHook{}
 
 type MockHook struct {
@@ -141,8 +161,9 @@ func (fn MockParseFn) Parse(ctx context.Context, r io.ReadCloser) (*parser.Packa
 }
 
 type MockDependencyManager struct {
-	MockResolve    func() (int, int, int, error)
-	MockRemoveSelf func() error
+	MockResolve        func() (int, int, int, error)
+	MockRemoveSelf     func() error
+	MockProviderGroups func() ([]string, error)
 }
 
 func NewMockResolveFn(total, installed, invalid int, err error) func() (int, int, int, error) {
@@ -161,6 +182,34 @@ func (m *MockDependencyManager) RemoveSelf(_ context.Context, _ v1.PackageRevisi
 	return m.MockRemoveSelf()
 }
 
+func (m *MockDependencyManager) ProviderGroups(_ context.Context, _ v1.PackageRevision) ([]string, error) {
+	return m.MockProviderGroups()
+}
+
+var _ SignatureVerifier = &MockSignatureVerifier{}
+
+type MockSignatureVerifier struct {
+	MockVerify func() (bool, error)
+}
+
+func NewMockVerifyFn(verified bool, err error) func() (bool, error) {
+	return func() (bool, error) { return verified, err }
+}
+
+func (m *MockSignatureVerifier) Verify(_ context.Context, _ v1.PackageRevision) (bool, error) {
+	return m.MockVerify()
+}
+
+var _ VulnerabilityScanner = &MockVulnerabilityScanner{}
+
+type MockVulnerabilityScanner struct {
+	MockScan func() (*ScanResult, error)
+}
+
+func (m *MockVulnerabilityScanner) Scan(_ context.Context, _ v1.PackageRevision) (*ScanResult, error) {
+	return m.MockScan()
+}
+
 var providerBytes = []byte(`apiVersion: meta.pkg.crossplane.io/v1
 kind: Provider
 metadata:
@@ -175,6 +224,7 @@ spec:
 
 func TestReconcile(t *testing.T) {
 	errBoom := errors.New("boom")
+	fp := &v1.PackageFootprint{CustomResourceDefinitions: 1, CustomResources: 10, EstimatedBytes: 4096}
 	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
 	now := metav1.Now()
 	pullPolicy := corev1.PullNever
@@ -443,8 +493,10 @@ func TestReconcile(t *testing.T) {
 				rec: []ReconcilerOption{
 					WithNewPackageRevisionFn(func() v1.PackageRevision {
 						return &v1.ConfigurationRevision{
-							Spec: v1.PackageRevisionSpec{
-								PackagePullPolicy: &pullPolicy,
+							Spec: v1.ConfigurationRevisionSpec{
+								PackageRevisionSpec: v1.PackageRevisionSpec{
+									PackagePullPolicy: &pullPolicy,
+								},
 							},
 						}
 					}),
@@ -482,8 +534,8 @@ func TestReconcile(t *testing.T) {
 				err: errors.New(errPullPolicyNever),
 			},
 		},
-		"ErrInitParserBackend": {
-			reason: "We should return an error if we fail to initialize parser backend.",
+		"ErrVerifySignature": {
+			reason: "We should return an error if we fail to verify the package's signature.",
 			args: args{
 				mgr: &fake.Manager{},
 				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
@@ -501,7 +553,8 @@ func TestReconcile(t *testing.T) {
 								want := &v1.ConfigurationRevision{}
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
-								want.SetConditions(v1.Unhealthy().WithMessage("cannot initialize parser backend: boom"))
+								want.SetConditions(v1.SignatureVerificationFailed().WithMessage("cannot verify package signature: boom"))
+								want.SetConditions(v1.Unhealthy().WithMessage("cannot verify package signature: boom"))
 
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
@@ -513,36 +566,37 @@ func TestReconcile(t *testing.T) {
 					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
 						return nil
 					}}),
-					WithCache(&xpkgfake.MockCache{
-						MockHas: xpkgfake.NewMockCacheHasFn(false),
-					}),
-					WithParserBackend(&ErrBackend{err: errBoom}),
+					WithSignatureVerifier(&MockSignatureVerifier{MockVerify: NewMockVerifyFn(false, errBoom)}),
 				},
 			},
 			want: want{
-				err: errors.Wrap(errBoom, errInitParserBackend),
+				err: errors.Wrap(errBoom, errVerifySignature),
 			},
 		},
-		"ErrParseFromCache": {
-			reason: "We should return an error if fail to parse the package from the cache.",
+		"ErrVerifyInactiveSignature": {
+			reason: "We should verify the signature of an inactive revision that is being staged, and return an error if we fail to.",
 			args: args{
 				mgr: &fake.Manager{},
 				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
 				rec: []ReconcilerOption{
 					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ConfigurationRevision{} }),
+					WithDependencyManager(&MockDependencyManager{
+						MockRemoveSelf: NewMockRemoveSelfFn(nil),
+					}),
 					WithClientApplicator(resource.ClientApplicator{
 						Client: &test.MockClient{
 							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
 								pr := o.(*v1.ConfigurationRevision)
 								pr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
-								pr.SetDesiredState(v1.PackageRevisionActive)
+								pr.SetDesiredState(v1.PackageRevisionInactive)
 								return nil
 							}),
 							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
 								want := &v1.ConfigurationRevision{}
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
-								want.SetDesiredState(v1.PackageRevisionActive)
-								want.SetConditions(v1.Unhealthy().WithMessage("cannot parse package contents: boom"))
+								want.SetDesiredState(v1.PackageRevisionInactive)
+								want.SetConditions(v1.SignatureVerificationFailed().WithMessage("cannot verify package signature: boom"))
+								want.SetConditions(v1.Unhealthy().WithMessage("cannot verify package signature: boom"))
 
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
@@ -554,19 +608,15 @@ func TestReconcile(t *testing.T) {
 					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
 						return nil
 					}}),
-					WithParser(MockParseFn(func(_ context.Context, _ io.ReadCloser) (*parser.Package, error) { return nil, errBoom })),
-					WithCache(&xpkgfake.MockCache{
-						MockHas: xpkgfake.NewMockCacheHasFn(true),
-						MockGet: xpkgfake.NewMockCacheGetFn(io.NopCloser(bytes.NewBuffer(providerBytes)), nil),
-					}),
+					WithSignatureVerifier(&MockSignatureVerifier{MockVerify: NewMockVerifyFn(false, errBoom)}),
 				},
 			},
 			want: want{
-				err: errors.Wrap(errBoom, errParsePackage),
+				err: errors.Wrap(errBoom, errVerifySignature),
 			},
 		},
-		"ErrParseFromImage": {
-			reason: "We should return an error if we fail to parse the package from the image.",
+		"ErrScanImage": {
+			reason: "We should return an error if we fail to scan the package's image.",
 			args: args{
 				mgr: &fake.Manager{},
 				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
@@ -584,7 +634,8 @@ func TestReconcile(t *testing.T) {
 								want := &v1.ConfigurationRevision{}
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
-								want.SetConditions(v1.Unhealthy().WithMessage("cannot parse package contents: boom"))
+								want.SetConditions(v1.VulnerabilityScanFailed().WithMessage("cannot scan package image for vulnerabilities: boom"))
+								want.SetConditions(v1.Unhealthy().WithMessage("cannot scan package image for vulnerabilities: boom"))
 
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
@@ -596,20 +647,15 @@ func TestReconcile(t *testing.T) {
 					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
 						return nil
 					}}),
-					WithParser(MockParseFn(func(_ context.Context, _ io.ReadCloser) (*parser.Package, error) { return nil, errBoom })),
-					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
-					WithCache(&xpkgfake.MockCache{
-						MockHas:   xpkgfake.NewMockCacheHasFn(false),
-						MockStore: xpkgfake.NewMockCacheStoreFn(nil),
-					}),
+					WithVulnerabilityScanner(&MockVulnerabilityScanner{MockScan: func() (*ScanResult, error) { return nil, errBoom }}),
 				},
 			},
 			want: want{
-				err: errors.Wrap(errBoom, errParsePackage),
+				err: errors.Wrap(errBoom, errScanImage),
 			},
 		},
-		"ErrParseFromImageFailedCache": {
-			reason: "We should return an error if we fail to parse the package from the image and fail to cache.",
+		"SignatureVerificationSkipped": {
+			reason: "We should report that a package's signature was not verified if no ImageConfig configures its verification.",
 			args: args{
 				mgr: &fake.Manager{},
 				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
@@ -627,7 +673,9 @@ func TestReconcile(t *testing.T) {
 								want := &v1.ConfigurationRevision{}
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
-								want.SetConditions(v1.Unhealthy().WithMessage("cannot parse package contents: boom"))
+								want.SetConditions(v1.SignatureVerificationSkipped())
+								want.SetConditions(v1.VulnerabilityScanFailed().WithMessage("cannot scan package image for vulnerabilities: boom"))
+								want.SetConditions(v1.Unhealthy().WithMessage("cannot scan package image for vulnerabilities: boom"))
 
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
@@ -639,21 +687,16 @@ func TestReconcile(t *testing.T) {
 					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
 						return nil
 					}}),
-					WithParser(MockParseFn(func(_ context.Context, _ io.ReadCloser) (*parser.Package, error) { return nil, errBoom })),
-					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
-					WithCache(&xpkgfake.MockCache{
-						MockHas:    xpkgfake.NewMockCacheHasFn(false),
-						MockStore:  xpkgfake.NewMockCacheStoreFn(errBoom),
-						MockDelete: xpkgfake.NewMockCacheDeleteFn(nil),
-					}),
+					WithSignatureVerifier(&MockSignatureVerifier{MockVerify: NewMockVerifyFn(false, nil)}),
+					WithVulnerabilityScanner(&MockVulnerabilityScanner{MockScan: func() (*ScanResult, error) { return nil, errBoom }}),
 				},
 			},
 			want: want{
-				err: errors.Wrap(errBoom, errParsePackage),
+				err: errors.Wrap(errBoom, errScanImage),
 			},
 		},
-		"ErrParseFromImageFailedCacheFailedDelete": {
-			reason: "We should return an error if we fail to parse the package from the image, fail to cache, and fail to delete from cache.",
+		"VulnerabilitiesFound": {
+			reason: "We should block activation if the package's image has vulnerabilities at or above the configured severity.",
 			args: args{
 				mgr: &fake.Manager{},
 				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
@@ -671,7 +714,17 @@ func TestReconcile(t *testing.T) {
 								want := &v1.ConfigurationRevision{}
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
-								want.SetConditions(v1.Unhealthy().WithMessage("cannot parse package contents: boom"))
+								want.SetVulnerabilities(&v1.PackageVulnerabilities{
+									Critical: 1,
+									Low:      1,
+									Findings: []v1.PackageVulnerability{
+										{ID: "CVE-2024-0002", Severity: "Critical"},
+										{ID: "CVE-2024-0001", Severity: "Low"},
+									},
+									ScanTime: o.(*v1.ConfigurationRevision).GetVulnerabilities().ScanTime,
+								})
+								want.SetConditions(v1.VulnerabilitiesFound().WithMessage("package image has 1 vulnerabilities of severity High or higher"))
+								want.SetConditions(v1.Unhealthy().WithMessage("package image has 1 vulnerabilities of severity High or higher"))
 
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
@@ -683,21 +736,23 @@ func TestReconcile(t *testing.T) {
 					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
 						return nil
 					}}),
-					WithParser(MockParseFn(func(_ context.Context, _ io.ReadCloser) (*parser.Package, error) { return nil, errBoom })),
-					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
-					WithCache(&xpkgfake.MockCache{
-						MockHas:    xpkgfake.NewMockCacheHasFn(false),
-						MockStore:  xpkgfake.NewMockCacheStoreFn(errBoom),
-						MockDelete: xpkgfake.NewMockCacheDeleteFn(errBoom),
-					}),
+					WithVulnerabilityScanner(&MockVulnerabilityScanner{MockScan: func() (*ScanResult, error) {
+						return &ScanResult{
+							Vulnerabilities: []xpkg.Vulnerability{
+								{ID: "CVE-2024-0001", Severity: xpkg.SeverityLow},
+								{ID: "CVE-2024-0002", Severity: xpkg.SeverityCritical},
+							},
+							FailOnSeverity: xpkg.SeverityHigh,
+						}, nil
+					}}),
 				},
 			},
 			want: want{
-				err: errors.Wrap(errBoom, errParsePackage),
+				err: errors.Errorf(errFmtVulnerable, 1, xpkg.SeverityHigh),
 			},
 		},
-		"ErrLint": {
-			reason: "We should return an error if fail to lint the package.",
+		"ErrInitParserBackend": {
+			reason: "We should return an error if we fail to initialize parser backend.",
 			args: args{
 				mgr: &fake.Manager{},
 				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
@@ -715,7 +770,9 @@ func TestReconcile(t *testing.T) {
 								want := &v1.ConfigurationRevision{}
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
-								want.SetConditions(v1.Unhealthy().WithMessage("linting package contents failed: boom"))
+								want.SetFetchAttempts(1)
+								want.SetConditions(v1.FetchFailed(1, nil, errors.Wrap(errBoom, errInitParserBackend)))
+								want.SetConditions(v1.Unhealthy().WithMessage("cannot initialize parser backend: boom"))
 
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
@@ -727,24 +784,18 @@ func TestReconcile(t *testing.T) {
 					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
 						return nil
 					}}),
-					WithParser(parser.New(metaScheme, objScheme)),
-					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
-					WithLinter(&MockLinter{MockLint: NewMockLintFn(errBoom)}),
 					WithCache(&xpkgfake.MockCache{
 						MockHas: xpkgfake.NewMockCacheHasFn(false),
-						MockStore: func(_ string, rc io.ReadCloser) error {
-							_, err := io.ReadAll(rc)
-							return err
-						},
 					}),
+					WithParserBackend(&ErrBackend{err: errBoom}),
 				},
 			},
 			want: want{
-				err: errors.Wrap(errBoom, errLintPackage),
+				err: errors.Wrap(errBoom, errInitParserBackend),
 			},
 		},
-		"ErrCrossplaneConstraints": {
-			reason: "We should not requeue if Crossplane version is incompatible.",
+		"ErrInitParserBackendTransient": {
+			reason: "We should retry transient failures to initialize the parser backend without marking the revision unhealthy.",
 			args: args{
 				mgr: &fake.Manager{},
 				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
@@ -756,27 +807,20 @@ func TestReconcile(t *testing.T) {
 								pr := o.(*v1.ConfigurationRevision)
 								pr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								pr.SetDesiredState(v1.PackageRevisionActive)
+								pr.SetFetchAttempts(1)
+								pr.SetConditions(v1.Healthy())
 								return nil
 							}),
 							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
-								want := &v1.ConfigurationRevision{}
-								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
-								want.SetDesiredState(v1.PackageRevisionActive)
-								want.SetConditions(v1.Unhealthy().WithMessage("incompatible Crossplane version: package is not compatible with Crossplane version (v0.11.0): boom"))
-								want.SetAnnotations(map[string]string{"author": "crossplane"})
-
-								if diff := cmp.Diff(want, o); diff != "" {
-									t.Errorf("-want, +got:\n%s", diff)
+								pr := o.(*v1.ConfigurationRevision)
+								if diff := cmp.Diff(int64(2), pr.GetFetchAttempts()); diff != "" {
+									t.Errorf("-want attempts, +got attempts:\n%s", diff)
 								}
-								return nil
-							}),
-							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
-								want := &v1.ConfigurationRevision{}
-								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
-								want.SetDesiredState(v1.PackageRevisionActive)
-								want.SetAnnotations(map[string]string{"author": "crossplane"})
-								if diff := cmp.Diff(want, o); diff != "" {
-									t.Errorf("-want, +got:\n%s", diff)
+								if diff := cmp.Diff(v1.ReasonFetchFailed, pr.GetCondition(v1.TypeFetched).Reason); diff != "" {
+									t.Errorf("-want reason, +got reason:\n%s", diff)
+								}
+								if diff := cmp.Diff(v1.ReasonHealthy, pr.GetCondition(v1.TypeHealthy).Reason); diff != "" {
+									t.Errorf("-want reason, +got reason:\n%s", diff)
 								}
 								return nil
 							}),
@@ -785,49 +829,44 @@ func TestReconcile(t *testing.T) {
 					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
 						return nil
 					}}),
-					WithParser(parser.New(metaScheme, objScheme)),
-					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
 					WithCache(&xpkgfake.MockCache{
 						MockHas: xpkgfake.NewMockCacheHasFn(false),
-						MockStore: func(_ string, rc io.ReadCloser) error {
-							_, err := io.ReadAll(rc)
-							return err
-						},
-					}),
-					WithLinter(&MockLinter{MockLint: NewMockLintFn(nil)}),
-					WithVersioner(&verfake.MockVersioner{
-						MockInConstraints:    verfake.NewMockInConstraintsFn(false, errBoom),
-						MockGetVersionString: verfake.NewMockGetVersionStringFn("v0.11.0"),
 					}),
+					WithParserBackend(&ErrBackend{err: &transport.Error{StatusCode: http.StatusTooManyRequests}}),
+					WithFetchRetries(3, time.Second),
 				},
 			},
 			want: want{
-				r: reconcile.Result{Requeue: false},
+				r: reconcile.Result{RequeueAfter: 2 * time.Second},
 			},
 		},
-		"ErrOneMeta": {
-			reason: "We should return an error if not exactly one meta package type.",
+		"SourceUnavailable": {
+			reason: "We should leave an established revision untouched if its package image is no longer available.",
 			args: args{
 				mgr: &fake.Manager{},
 				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
 				rec: []ReconcilerOption{
-					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ConfigurationRevision{} }),
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ProviderRevision{} }),
 					WithClientApplicator(resource.ClientApplicator{
 						Client: &test.MockClient{
 							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
-								pr := o.(*v1.ConfigurationRevision)
-								pr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								pr := o.(*v1.ProviderRevision)
+								pr.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
 								pr.SetDesiredState(v1.PackageRevisionActive)
+								pr.SetObjects([]xpv1.TypedReference{{Kind: "CustomResourceDefinition", Name: "coolresources.example.org"}})
+								pr.SetConditions(v1.Healthy())
 								return nil
 							}),
 							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
-								want := &v1.ConfigurationRevision{}
-								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
-								want.SetDesiredState(v1.PackageRevisionActive)
-								want.SetConditions(v1.Unhealthy().WithMessage("cannot install package with multiple meta types"))
-
-								if diff := cmp.Diff(want, o); diff != "" {
-									t.Errorf("-want, +got:\n%s", diff)
+								pr := o.(*v1.ProviderRevision)
+								if diff := cmp.Diff(int64(0), pr.GetFetchAttempts()); diff != "" {
+									t.Errorf("-want attempts, +got attempts:\n%s", diff)
+								}
+								if diff := cmp.Diff(v1.ReasonSourceUnavailable, pr.GetCondition(v1.TypeFetched).Reason); diff != "" {
+									t.Errorf("-want reason, +got reason:\n%s", diff)
+								}
+								if diff := cmp.Diff(v1.ReasonHealthy, pr.GetCondition(v1.TypeHealthy).Reason); diff != "" {
+									t.Errorf("-want reason, +got reason:\n%s", diff)
 								}
 								return nil
 							}),
@@ -836,21 +875,18 @@ func TestReconcile(t *testing.T) {
 					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
 						return nil
 					}}),
-					WithParser(parser.New(metaScheme, objScheme)),
-					WithParserBackend(parser.NewEchoBackend("")),
 					WithCache(&xpkgfake.MockCache{
-						MockHas:   xpkgfake.NewMockCacheHasFn(false),
-						MockStore: xpkgfake.NewMockCacheStoreFn(nil),
+						MockHas: xpkgfake.NewMockCacheHasFn(false),
 					}),
-					WithLinter(&MockLinter{MockLint: NewMockLintFn(nil)}),
+					WithParserBackend(&ErrBackend{err: &transport.Error{StatusCode: http.StatusNotFound}}),
 				},
 			},
 			want: want{
-				err: errors.New(errNotOneMeta),
+				r: reconcile.Result{RequeueAfter: sourceUnavailableWait},
 			},
 		},
-		"ErrUpdateAnnotations": {
-			reason: "We should return an error if we fail to update our annotations.",
+		"ErrParseFromCache": {
+			reason: "We should return an error if fail to parse the package from the cache.",
 			args: args{
 				mgr: &fake.Manager{},
 				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
@@ -864,13 +900,11 @@ func TestReconcile(t *testing.T) {
 								pr.SetDesiredState(v1.PackageRevisionActive)
 								return nil
 							}),
-							MockUpdate: test.NewMockUpdateFn(errBoom),
 							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
 								want := &v1.ConfigurationRevision{}
-								want.SetAnnotations(map[string]string{"author": "crossplane"})
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
-								want.SetConditions(v1.Unhealthy().WithMessage("cannot update package revision object metadata: boom"))
+								want.SetConditions(v1.Unhealthy().WithMessage("cannot parse package contents: boom"))
 
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
@@ -882,136 +916,611 @@ func TestReconcile(t *testing.T) {
 					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
 						return nil
 					}}),
-					WithParser(parser.New(metaScheme, objScheme)),
-					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
+					WithParser(MockParseFn(func(_ context.Context, _ io.ReadCloser) (*parser.Package, error) { return nil, errBoom })),
 					WithCache(&xpkgfake.MockCache{
-						MockHas: xpkgfake.NewMockCacheHasFn(false),
-						MockStore: func(_ string, rc io.ReadCloser) error {
-							_, err := io.ReadAll(rc)
-							return err
-						},
+						MockHas: xpkgfake.NewMockCacheHasFn(true),
+						MockGet: xpkgfake.NewMockCacheGetFn(io.NopCloser(bytes.NewBuffer(providerBytes)), nil),
 					}),
-					WithLinter(&MockLinter{MockLint: NewMockLintFn(nil)}),
 				},
 			},
 			want: want{
-				err: errors.Wrap(errBoom, errUpdateMeta),
+				err: errors.Wrap(errBoom, errParsePackage),
 			},
 		},
-		"ErrResolveDependencies": {
-			reason: "We should return an error if we fail to resolve dependencies.",
+		"ErrParseFromImage": {
+			reason: "We should return an error if we fail to parse the package from the image.",
 			args: args{
 				mgr: &fake.Manager{},
 				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
 				rec: []ReconcilerOption{
-					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ProviderRevision{} }),
-					WithDependencyManager(&MockDependencyManager{
-						MockResolve: NewMockResolveFn(0, 0, 0, errBoom),
-					}),
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ConfigurationRevision{} }),
 					WithClientApplicator(resource.ClientApplicator{
 						Client: &test.MockClient{
 							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
-								pr := o.(*v1.ProviderRevision)
-								pr.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								pr := o.(*v1.ConfigurationRevision)
+								pr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								pr.SetDesiredState(v1.PackageRevisionActive)
-								pr.SetSkipDependencyResolution(ptr.To(false))
 								return nil
 							}),
 							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
-								want := &v1.ProviderRevision{}
-								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								want := &v1.ConfigurationRevision{}
+								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
-								want.SetSkipDependencyResolution(ptr.To(false))
-								want.SetAnnotations(map[string]string{"author": "crossplane"})
-								want.SetConditions(v1.UnknownHealth().WithMessage("cannot resolve package dependencies: boom"))
+								want.SetConditions(v1.Unhealthy().WithMessage("cannot parse package contents: boom"))
 
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
 							}),
-							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
-								want := &v1.ProviderRevision{}
-								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
-								want.SetDesiredState(v1.PackageRevisionActive)
-								want.SetAnnotations(map[string]string{"author": "crossplane"})
-								want.SetSkipDependencyResolution(ptr.To(false))
-								if diff := cmp.Diff(want, o); diff != "" {
-									t.Errorf("-want, +got:\n%s", diff)
-								}
-								return nil
-							}),
 						},
 					}),
 					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
 						return nil
 					}}),
-					WithParser(parser.New(metaScheme, objScheme)),
+					WithParser(MockParseFn(func(_ context.Context, _ io.ReadCloser) (*parser.Package, error) { return nil, errBoom })),
 					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
 					WithCache(&xpkgfake.MockCache{
-						MockHas: xpkgfake.NewMockCacheHasFn(false),
-						MockStore: func(_ string, rc io.ReadCloser) error {
-							_, err := io.ReadAll(rc)
-							return err
-						},
+						MockHas:   xpkgfake.NewMockCacheHasFn(false),
+						MockStore: xpkgfake.NewMockCacheStoreFn(nil),
 					}),
-					WithLinter(&MockLinter{MockLint: NewMockLintFn(nil)}),
-					WithVersioner(&verfake.MockVersioner{MockInConstraints: verfake.NewMockInConstraintsFn(true, nil)}),
 				},
 			},
 			want: want{
-				err: errors.Wrap(errBoom, errResolveDeps),
+				err: errors.Wrap(errBoom, errParsePackage),
 			},
 		},
-		"ErrPreHook": {
-			reason: "We should return an error if pre establishment runtimeHook returns an error.",
+		"ErrParseFromImageFailedCache": {
+			reason: "We should return an error if we fail to parse the package from the image and fail to cache.",
 			args: args{
 				mgr: &fake.Manager{},
 				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
 				rec: []ReconcilerOption{
-					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ProviderRevision{} }),
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ConfigurationRevision{} }),
 					WithClientApplicator(resource.ClientApplicator{
 						Client: &test.MockClient{
 							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
-								if pr, ok := o.(*v1.ProviderRevision); ok {
-									pr.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
-									pr.SetDesiredState(v1.PackageRevisionActive)
-									pr.SetRuntimeConfigRef(&v1.RuntimeConfigReference{Name: "default"})
-								}
+								pr := o.(*v1.ConfigurationRevision)
+								pr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								pr.SetDesiredState(v1.PackageRevisionActive)
 								return nil
 							}),
 							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
-								want := &v1.ProviderRevision{}
-								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								want := &v1.ConfigurationRevision{}
+								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
-								want.SetRuntimeConfigRef(&v1.RuntimeConfigReference{Name: "default"})
-								want.SetAnnotations(map[string]string{"author": "crossplane"})
-								want.SetConditions(v1.Unhealthy().WithMessage(errPreHook + ": boom"))
+								want.SetConditions(v1.Unhealthy().WithMessage("cannot parse package contents: boom"))
 
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
 							}),
-							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
-								want := &v1.ProviderRevision{}
-								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
-								want.SetDesiredState(v1.PackageRevisionActive)
-								want.SetRuntimeConfigRef(&v1.RuntimeConfigReference{Name: "default"})
-								want.SetAnnotations(map[string]string{"author": "crossplane"})
-								if diff := cmp.Diff(want, o); diff != "" {
-									t.Errorf("-want, +got:\n%s", diff)
-								}
-								return nil
-							}),
 						},
 					}),
 					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
 						return nil
 					}}),
-					WithRuntimeHooks(&MockHook{
-						MockPre: NewMockPreFn(errBoom),
-					}),
+					WithParser(MockParseFn(func(_ context.Context, _ io.ReadCloser) (*parser.Package, error) { return nil, errBoom })),
+					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
+					WithCache(&xpkgfake.MockCache{
+						MockHas:    xpkgfake.NewMockCacheHasFn(false),
+						MockStore:  xpkgfake.NewMockCacheStoreFn(errBoom),
+						MockDelete: xpkgfake.NewMockCacheDeleteFn(nil),
+					}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errParsePackage),
+			},
+		},
+		"ErrParseFromImageFailedCacheFailedDelete": {
+			reason: "We should return an error if we fail to parse the package from the image, fail to cache, and fail to delete from cache.",
+			args: args{
+				mgr: &fake.Manager{},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ConfigurationRevision{} }),
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								pr := o.(*v1.ConfigurationRevision)
+								pr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								pr.SetDesiredState(v1.PackageRevisionActive)
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ConfigurationRevision{}
+								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetConditions(v1.Unhealthy().WithMessage("cannot parse package contents: boom"))
+
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+					}),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithParser(MockParseFn(func(_ context.Context, _ io.ReadCloser) (*parser.Package, error) { return nil, errBoom })),
+					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
+					WithCache(&xpkgfake.MockCache{
+						MockHas:    xpkgfake.NewMockCacheHasFn(false),
+						MockStore:  xpkgfake.NewMockCacheStoreFn(errBoom),
+						MockDelete: xpkgfake.NewMockCacheDeleteFn(errBoom),
+					}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errParsePackage),
+			},
+		},
+		"ErrLint": {
+			reason: "We should return an error if fail to lint the package.",
+			args: args{
+				mgr: &fake.Manager{},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ConfigurationRevision{} }),
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								pr := o.(*v1.ConfigurationRevision)
+								pr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								pr.SetDesiredState(v1.PackageRevisionActive)
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ConfigurationRevision{}
+								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetConditions(v1.Unhealthy().WithMessage("linting package contents failed: boom"))
+								want.SetContents(&v1.PackageContents{LintError: "boom"})
+
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+					}),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithParser(parser.New(metaScheme, objScheme)),
+					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
+					WithLinter(&MockLinter{MockLint: NewMockLintFn(errBoom)}),
+					WithCache(&xpkgfake.MockCache{
+						MockHas: xpkgfake.NewMockCacheHasFn(false),
+						MockStore: func(_ string, rc io.ReadCloser) error {
+							_, err := io.ReadAll(rc)
+							return err
+						},
+					}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errLintPackage),
+			},
+		},
+		"ErrCrossplaneConstraints": {
+			reason: "We should not requeue if Crossplane version is incompatible.",
+			args: args{
+				mgr: &fake.Manager{},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ConfigurationRevision{} }),
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								pr := o.(*v1.ConfigurationRevision)
+								pr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								pr.SetDesiredState(v1.PackageRevisionActive)
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ConfigurationRevision{}
+								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetConditions(v1.IncompatibleCrossplaneVersion().WithMessage("incompatible Crossplane version: package is not compatible with Crossplane version (v0.11.0): boom"))
+								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetContents(&v1.PackageContents{})
+
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ConfigurationRevision{}
+								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetContents(&v1.PackageContents{})
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+					}),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithParser(parser.New(metaScheme, objScheme)),
+					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
+					WithCache(&xpkgfake.MockCache{
+						MockHas: xpkgfake.NewMockCacheHasFn(false),
+						MockStore: func(_ string, rc io.ReadCloser) error {
+							_, err := io.ReadAll(rc)
+							return err
+						},
+					}),
+					WithLinter(&MockLinter{MockLint: NewMockLintFn(nil)}),
+					WithVersioner(&verfake.MockVersioner{
+						MockInConstraints:    verfake.NewMockInConstraintsFn(false, errBoom),
+						MockGetVersionString: verfake.NewMockGetVersionStringFn("v0.11.0"),
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"ErrCrossplaneDowngraded": {
+			reason: "We should report that Crossplane was downgraded if its version is incompatible, and lower than the version that last found the package compatible.",
+			args: args{
+				mgr: &fake.Manager{},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ConfigurationRevision{} }),
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								pr := o.(*v1.ConfigurationRevision)
+								pr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								pr.SetDesiredState(v1.PackageRevisionActive)
+								pr.SetCrossplaneVersion("v0.13.0")
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ConfigurationRevision{}
+								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetCrossplaneVersion("v0.13.0")
+								want.SetConditions(v1.IncompatibleCrossplaneVersion().WithMessage(`Crossplane was downgraded from v0.13.0 to v0.11.0: incompatible Crossplane version: Crossplane version v0.11.0 does not satisfy the package's Crossplane version constraint ">v0.13.0"`))
+								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetContents(&v1.PackageContents{})
+
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ConfigurationRevision{}
+								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetCrossplaneVersion("v0.13.0")
+								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetContents(&v1.PackageContents{})
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+					}),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithParser(parser.New(metaScheme, objScheme)),
+					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
+					WithCache(&xpkgfake.MockCache{
+						MockHas: xpkgfake.NewMockCacheHasFn(false),
+						MockStore: func(_ string, rc io.ReadCloser) error {
+							_, err := io.ReadAll(rc)
+							return err
+						},
+					}),
+					WithLinter(&MockLinter{MockLint: NewMockLintFn(nil)}),
+					WithVersioner(&verfake.MockVersioner{
+						MockInConstraints:    verfake.NewMockInConstraintsFn(false, nil),
+						MockGetVersionString: verfake.NewMockGetVersionStringFn("v0.11.0"),
+						MockGetSemVer:        verfake.NewMockGetSemVerFn(semver.MustParse("v0.11.0"), nil),
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"ErrOneMeta": {
+			reason: "We should return an error if not exactly one meta package type.",
+			args: args{
+				mgr: &fake.Manager{},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ConfigurationRevision{} }),
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								pr := o.(*v1.ConfigurationRevision)
+								pr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								pr.SetDesiredState(v1.PackageRevisionActive)
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ConfigurationRevision{}
+								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetConditions(v1.Unhealthy().WithMessage("cannot install package with multiple meta types"))
+								want.SetContents(&v1.PackageContents{})
+
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+					}),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithParser(parser.New(metaScheme, objScheme)),
+					WithParserBackend(parser.NewEchoBackend("")),
+					WithCache(&xpkgfake.MockCache{
+						MockHas:   xpkgfake.NewMockCacheHasFn(false),
+						MockStore: xpkgfake.NewMockCacheStoreFn(nil),
+					}),
+					WithLinter(&MockLinter{MockLint: NewMockLintFn(nil)}),
+				},
+			},
+			want: want{
+				err: errors.New(errNotOneMeta),
+			},
+		},
+		"ErrUpdateAnnotations": {
+			reason: "We should return an error if we fail to update our annotations.",
+			args: args{
+				mgr: &fake.Manager{},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ConfigurationRevision{} }),
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								pr := o.(*v1.ConfigurationRevision)
+								pr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								pr.SetDesiredState(v1.PackageRevisionActive)
+								return nil
+							}),
+							MockUpdate: test.NewMockUpdateFn(errBoom),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ConfigurationRevision{}
+								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetContents(&v1.PackageContents{})
+								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetConditions(v1.Unhealthy().WithMessage("cannot update package revision object metadata: boom"))
+
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+					}),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithParser(parser.New(metaScheme, objScheme)),
+					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
+					WithCache(&xpkgfake.MockCache{
+						MockHas: xpkgfake.NewMockCacheHasFn(false),
+						MockStore: func(_ string, rc io.ReadCloser) error {
+							_, err := io.ReadAll(rc)
+							return err
+						},
+					}),
+					WithLinter(&MockLinter{MockLint: NewMockLintFn(nil)}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errUpdateMeta),
+			},
+		},
+		"ErrResolveDependencies": {
+			reason: "We should return an error if we fail to resolve dependencies.",
+			args: args{
+				mgr: &fake.Manager{},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ProviderRevision{} }),
+					WithDependencyManager(&MockDependencyManager{
+						MockResolve: NewMockResolveFn(0, 0, 0, errBoom),
+					}),
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								pr := o.(*v1.ProviderRevision)
+								pr.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								pr.SetDesiredState(v1.PackageRevisionActive)
+								pr.SetSkipDependencyResolution(ptr.To(false))
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ProviderRevision{}
+								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetSkipDependencyResolution(ptr.To(false))
+								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetContents(&v1.PackageContents{})
+								want.SetCrossplaneVersion("v0.13.1")
+								want.SetConditions(v1.UnknownHealth().WithMessage("cannot resolve package dependencies: boom"), v1.DependenciesUnsatisfied().WithMessage("cannot resolve package dependencies: boom"))
+
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ProviderRevision{}
+								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetContents(&v1.PackageContents{})
+								want.SetSkipDependencyResolution(ptr.To(false))
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+					}),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithParser(parser.New(metaScheme, objScheme)),
+					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
+					WithCache(&xpkgfake.MockCache{
+						MockHas: xpkgfake.NewMockCacheHasFn(false),
+						MockStore: func(_ string, rc io.ReadCloser) error {
+							_, err := io.ReadAll(rc)
+							return err
+						},
+					}),
+					WithLinter(&MockLinter{MockLint: NewMockLintFn(nil)}),
+					WithVersioner(&verfake.MockVersioner{MockInConstraints: verfake.NewMockInConstraintsFn(true, nil), MockGetVersionString: verfake.NewMockGetVersionStringFn("v0.13.1")}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errResolveDeps),
+			},
+		},
+		"DependencyCycle": {
+			reason: "We should report a dependency cycle and wait rather than returning an error.",
+			args: args{
+				mgr: &fake.Manager{},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ProviderRevision{} }),
+					WithDependencyManager(&MockDependencyManager{
+						MockResolve: NewMockResolveFn(0, 0, 0, &dag.CycleError{Cycle: []string{"cool/a", "cool/b", "cool/a"}}),
+					}),
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								pr := o.(*v1.ProviderRevision)
+								pr.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								pr.SetDesiredState(v1.PackageRevisionActive)
+								pr.SetSkipDependencyResolution(ptr.To(false))
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ProviderRevision{}
+								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetSkipDependencyResolution(ptr.To(false))
+								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetContents(&v1.PackageContents{})
+								want.SetCrossplaneVersion("v0.13.1")
+								want.SetConditions(v1.UnknownHealth().WithMessage("cannot resolve package dependencies: detected cycle: cool/a -> cool/b -> cool/a"), v1.DependencyCycle().WithMessage("cannot resolve package dependencies: detected cycle: cool/a -> cool/b -> cool/a"))
+
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ProviderRevision{}
+								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetContents(&v1.PackageContents{})
+								want.SetSkipDependencyResolution(ptr.To(false))
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+					}),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithParser(parser.New(metaScheme, objScheme)),
+					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
+					WithCache(&xpkgfake.MockCache{
+						MockHas: xpkgfake.NewMockCacheHasFn(false),
+						MockStore: func(_ string, rc io.ReadCloser) error {
+							_, err := io.ReadAll(rc)
+							return err
+						},
+					}),
+					WithLinter(&MockLinter{MockLint: NewMockLintFn(nil)}),
+					WithVersioner(&verfake.MockVersioner{MockInConstraints: verfake.NewMockInConstraintsFn(true, nil), MockGetVersionString: verfake.NewMockGetVersionStringFn("v0.13.1")}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: dependencyCycleWait},
+			},
+		},
+		"ErrPreHook": {
+			reason: "We should return an error if pre establishment runtimeHook returns an error.",
+			args: args{
+				mgr: &fake.Manager{},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ProviderRevision{} }),
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								if pr, ok := o.(*v1.ProviderRevision); ok {
+									pr.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+									pr.SetDesiredState(v1.PackageRevisionActive)
+									pr.SetRuntimeConfigRef(&v1.RuntimeConfigReference{Name: "default"})
+								}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ProviderRevision{}
+								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetRuntimeConfigRef(&v1.RuntimeConfigReference{Name: "default"})
+								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetContents(&v1.PackageContents{})
+								want.SetCrossplaneVersion("v0.13.1")
+								want.SetConditions(v1.Unhealthy().WithMessage(errPreHook + ": boom"))
+
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ProviderRevision{}
+								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetRuntimeConfigRef(&v1.RuntimeConfigReference{Name: "default"})
+								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetContents(&v1.PackageContents{})
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+					}),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithRuntimeHooks(&MockHook{
+						MockPre: NewMockPreFn(errBoom),
+					}),
 					WithParser(parser.New(metaScheme, objScheme)),
 					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
 					WithCache(&xpkgfake.MockCache{
@@ -1022,7 +1531,7 @@ func TestReconcile(t *testing.T) {
 						},
 					}),
 					WithLinter(&MockLinter{MockLint: NewMockLintFn(nil)}),
-					WithVersioner(&verfake.MockVersioner{MockInConstraints: verfake.NewMockInConstraintsFn(true, nil)}),
+					WithVersioner(&verfake.MockVersioner{MockInConstraints: verfake.NewMockInConstraintsFn(true, nil), MockGetVersionString: verfake.NewMockGetVersionStringFn("v0.13.1")}),
 				},
 			},
 			want: want{
@@ -1052,7 +1561,9 @@ func TestReconcile(t *testing.T) {
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetRuntimeConfigRef(&v1.RuntimeConfigReference{Name: "default"})
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
-								want.SetConditions(v1.Unhealthy().WithMessage(errPostHook + ": boom"))
+								want.SetContents(&v1.PackageContents{})
+								want.SetCrossplaneVersion("v0.13.1")
+								want.SetConditions(v1.APIsEstablished(), v1.Unhealthy().WithMessage(errPostHook+": boom"), v1.RuntimeUnhealthy().WithMessage(errPostHook+": boom"))
 
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
@@ -1065,6 +1576,7 @@ func TestReconcile(t *testing.T) {
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetRuntimeConfigRef(&v1.RuntimeConfigReference{Name: "default"})
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetContents(&v1.PackageContents{})
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
@@ -1090,7 +1602,7 @@ func TestReconcile(t *testing.T) {
 						},
 					}),
 					WithLinter(&MockLinter{MockLint: NewMockLintFn(nil)}),
-					WithVersioner(&verfake.MockVersioner{MockInConstraints: verfake.NewMockInConstraintsFn(true, nil)}),
+					WithVersioner(&verfake.MockVersioner{MockInConstraints: verfake.NewMockInConstraintsFn(true, nil), MockGetVersionString: verfake.NewMockGetVersionStringFn("v0.13.1")}),
 				},
 			},
 			want: want{
@@ -1117,7 +1629,9 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
-								want.SetConditions(v1.Healthy())
+								want.SetContents(&v1.PackageContents{})
+								want.SetCrossplaneVersion("v0.13.1")
+								want.SetConditions(v1.APIsEstablished(), v1.Healthy())
 
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
@@ -1129,6 +1643,7 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetContents(&v1.PackageContents{})
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
@@ -1152,13 +1667,82 @@ func TestReconcile(t *testing.T) {
 						},
 					}),
 					WithLinter(&MockLinter{MockLint: NewMockLintFn(nil)}),
-					WithVersioner(&verfake.MockVersioner{MockInConstraints: verfake.NewMockInConstraintsFn(true, nil)}),
+					WithVersioner(&verfake.MockVersioner{MockInConstraints: verfake.NewMockInConstraintsFn(true, nil), MockGetVersionString: verfake.NewMockGetVersionStringFn("v0.13.1")}),
 				},
 			},
 			want: want{
 				r: reconcile.Result{Requeue: false},
 			},
 		},
+		"SuccessfulActiveRevisionSampleFootprint": {
+			reason: "An active revision should sample its footprint, and requeue to sample it again.",
+			args: args{
+				mgr: &fake.Manager{},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ConfigurationRevision{} }),
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								pr := o.(*v1.ConfigurationRevision)
+								pr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								pr.SetDesiredState(v1.PackageRevisionActive)
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ConfigurationRevision{}
+								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetContents(&v1.PackageContents{})
+								want.SetCrossplaneVersion("v0.13.1")
+								want.SetFootprint(fp)
+								want.SetConditions(v1.APIsEstablished(), v1.Healthy())
+
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ConfigurationRevision{}
+								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetContents(&v1.PackageContents{})
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+
+							MockDelete: test.NewMockDeleteFn(nil),
+						},
+					}),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithEstablisher(NewMockEstablisher()),
+					WithParser(parser.New(metaScheme, objScheme)),
+					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
+					WithCache(&xpkgfake.MockCache{
+						MockHas: xpkgfake.NewMockCacheHasFn(false),
+						MockStore: func(_ string, rc io.ReadCloser) error {
+							_, err := io.ReadAll(rc)
+							return err
+						},
+					}),
+					WithLinter(&MockLinter{MockLint: NewMockLintFn(nil)}),
+					WithVersioner(&verfake.MockVersioner{MockInConstraints: verfake.NewMockInConstraintsFn(true, nil), MockGetVersionString: verfake.NewMockGetVersionStringFn("v0.13.1")}),
+					WithFootprintSampler(FootprintSamplerFn(func(_ context.Context, _ v1.PackageRevision) (*v1.PackageFootprint, error) {
+						return fp, nil
+					}), time.Hour),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: time.Hour},
+			},
+		},
 		"SuccessfulActiveRevisionIgnoreConstraints": {
 			reason: "An active revision with incompatible Crossplane version should install successfully when constraints ignored.",
 			args: args{
@@ -1180,7 +1764,8 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
-								want.SetConditions(v1.Healthy())
+								want.SetContents(&v1.PackageContents{})
+								want.SetConditions(v1.APIsEstablished(), v1.Healthy())
 								want.SetIgnoreCrossplaneConstraints(&trueVal)
 
 								if diff := cmp.Diff(want, o); diff != "" {
@@ -1193,6 +1778,7 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetContents(&v1.PackageContents{})
 								want.SetIgnoreCrossplaneConstraints(&trueVal)
 
 								if diff := cmp.Diff(want, o); diff != "" {
@@ -1225,6 +1811,53 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: false},
 			},
 		},
+		"ErrRuntimeSkewIgnoreConstraints": {
+			reason: "An active revision built with a crossplane-runtime version known not to work with Crossplane should be unhealthy even when constraints are ignored.",
+			args: args{
+				mgr: &fake.Manager{},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ConfigurationRevision{} }),
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								pr := o.(*v1.ConfigurationRevision)
+								pr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								pr.SetDesiredState(v1.PackageRevisionActive)
+								pr.SetIgnoreCrossplaneConstraints(&trueVal)
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								c := o.(*v1.ConfigurationRevision).GetCondition(v1.TypeHealthy)
+								if c.Reason != v1.ReasonIncompatibleCrossplaneVersion {
+									t.Errorf("want Healthy condition with reason %q, got %q", v1.ReasonIncompatibleCrossplaneVersion, c.Reason)
+								}
+								return nil
+							}),
+							MockUpdate: test.NewMockUpdateFn(nil),
+						},
+					}),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithParser(parser.New(metaScheme, objScheme)),
+					WithParserBackend(parser.NewEchoBackend(string(providerBytes) + `
+    runtime: v1.14.0`)),
+					WithCache(&xpkgfake.MockCache{
+						MockHas: xpkgfake.NewMockCacheHasFn(false),
+						MockStore: func(_ string, rc io.ReadCloser) error {
+							_, err := io.ReadAll(rc)
+							return err
+						},
+					}),
+					WithLinter(&MockLinter{MockLint: NewMockLintFn(nil)}),
+					WithVersioner(&verfake.MockVersioner{MockInConstraints: verfake.NewMockInConstraintsFn(true, nil), MockGetVersionString: verfake.NewMockGetVersionStringFn("v1.13.0")}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
 		"ErrEstablishActiveRevision": {
 			reason: "An active revision that fails to establish control should return an error.",
 			args: args{
@@ -1245,7 +1878,9 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
-								want.SetConditions(v1.Unhealthy().WithMessage("cannot establish control of object: boom"))
+								want.SetContents(&v1.PackageContents{})
+								want.SetCrossplaneVersion("v0.13.1")
+								want.SetConditions(v1.Unhealthy().WithMessage("cannot establish control of object: boom"), v1.APIsNotEstablished().WithMessage("cannot establish control of object: boom"))
 
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
@@ -1258,6 +1893,7 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetContents(&v1.PackageContents{})
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
@@ -1281,13 +1917,156 @@ func TestReconcile(t *testing.T) {
 						},
 					}),
 					WithLinter(&MockLinter{MockLint: NewMockLintFn(nil)}),
-					WithVersioner(&verfake.MockVersioner{MockInConstraints: verfake.NewMockInConstraintsFn(true, nil)}),
+					WithVersioner(&verfake.MockVersioner{MockInConstraints: verfake.NewMockInConstraintsFn(true, nil), MockGetVersionString: verfake.NewMockGetVersionStringFn("v0.13.1")}),
 				},
 			},
 			want: want{
 				err: errors.Wrap(errBoom, errEstablishControl),
 			},
 		},
+		"ErrContentPolicy": {
+			reason: "A revision whose package violates the content policy should report the violation, and establish nothing or add itself to the Lock.",
+			args: args{
+				mgr: &fake.Manager{},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ProviderRevision{} }),
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								pr := o.(*v1.ProviderRevision)
+								pr.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								pr.SetDesiredState(v1.PackageRevisionActive)
+								pr.SetSkipDependencyResolution(ptr.To(false))
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ProviderRevision{}
+								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetSkipDependencyResolution(ptr.To(false))
+								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetContents(&v1.PackageContents{})
+								want.SetCrossplaneVersion("v0.13.1")
+								want.SetConditions(v1.ContentPolicyViolation().WithMessage("package violates content policy: boom"))
+
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+							MockDelete: test.NewMockDeleteFn(nil),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ProviderRevision{}
+								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetSkipDependencyResolution(ptr.To(false))
+								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetContents(&v1.PackageContents{})
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+					}),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithDependencyManager(&MockDependencyManager{MockResolve: func() (int, int, int, error) {
+						return 0, 0, 0, errors.New("dependencies should not be resolved for a package that violates the content policy")
+					}}),
+					WithContentPolicy(&MockContentPolicy{MockCheck: func(_ []runtime.Object) error { return errBoom }}),
+					WithParser(parser.New(metaScheme, objScheme)),
+					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
+					WithCache(&xpkgfake.MockCache{
+						MockHas: xpkgfake.NewMockCacheHasFn(false),
+						MockStore: func(_ string, rc io.ReadCloser) error {
+							_, err := io.ReadAll(rc)
+							return err
+						},
+					}),
+					WithLinter(&MockLinter{MockLint: NewMockLintFn(nil)}),
+					WithVersioner(&verfake.MockVersioner{MockInConstraints: verfake.NewMockInConstraintsFn(true, nil), MockGetVersionString: verfake.NewMockGetVersionStringFn("v0.13.1")}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"ErrContentPolicyDependencyGroups": {
+			reason: "We should report a content policy violation, and retry, if a package contains objects of API groups its provider dependencies don't define.",
+			args: args{
+				mgr: &fake.Manager{},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ProviderRevision{} }),
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								pr := o.(*v1.ProviderRevision)
+								pr.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								pr.SetDesiredState(v1.PackageRevisionActive)
+								pr.SetSkipDependencyResolution(ptr.To(false))
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ProviderRevision{}
+								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetSkipDependencyResolution(ptr.To(false))
+								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetContents(&v1.PackageContents{})
+								want.SetCrossplaneVersion("v0.13.1")
+								want.SetConditions(v1.DependenciesSatisfied(), v1.ContentPolicyViolation().WithMessage("package violates content policy: boom"))
+
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+							MockDelete: test.NewMockDeleteFn(nil),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ProviderRevision{}
+								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetSkipDependencyResolution(ptr.To(false))
+								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetContents(&v1.PackageContents{})
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+					}),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithDependencyManager(&MockDependencyManager{MockResolve: NewMockResolveFn(0, 0, 0, nil)}),
+					WithContentPolicy(&MockContentPolicy{
+						MockCheck: func(_ []runtime.Object) error { return nil },
+						MockCheckDependencyGroups: func(_ []runtime.Object, _ func() ([]string, error)) error {
+							return errBoom
+						},
+					}),
+					WithParser(parser.New(metaScheme, objScheme)),
+					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
+					WithCache(&xpkgfake.MockCache{
+						MockHas: xpkgfake.NewMockCacheHasFn(false),
+						MockStore: func(_ string, rc io.ReadCloser) error {
+							_, err := io.ReadAll(rc)
+							return err
+						},
+					}),
+					WithLinter(&MockLinter{MockLint: NewMockLintFn(nil)}),
+					WithVersioner(&verfake.MockVersioner{MockInConstraints: verfake.NewMockInConstraintsFn(true, nil), MockGetVersionString: verfake.NewMockGetVersionStringFn("v0.13.1")}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errContentPolicy),
+			},
+		},
 		"ErrEstablishInactiveRevision": {
 			reason: "An inactive revision that fails to establish ownership should return an error.",
 			args: args{
@@ -1378,7 +2157,9 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionInactive)
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
-								want.SetConditions(v1.Healthy())
+								want.SetContents(&v1.PackageContents{})
+								want.SetCrossplaneVersion("v0.13.1")
+								want.SetConditions(v1.APIsEstablished(), v1.Healthy())
 
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
@@ -1390,6 +2171,7 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionInactive)
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetContents(&v1.PackageContents{})
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
@@ -1413,7 +2195,7 @@ func TestReconcile(t *testing.T) {
 						},
 					}),
 					WithLinter(&MockLinter{MockLint: NewMockLintFn(nil)}),
-					WithVersioner(&verfake.MockVersioner{MockInConstraints: verfake.NewMockInConstraintsFn(true, nil)}),
+					WithVersioner(&verfake.MockVersioner{MockInConstraints: verfake.NewMockInConstraintsFn(true, nil), MockGetVersionString: verfake.NewMockGetVersionStringFn("v0.13.1")}),
 				},
 			},
 			want: want{
@@ -1531,7 +2313,53 @@ func TestReconcile(t *testing.T) {
 						},
 					}),
 					WithLinter(&MockLinter{MockLint: NewMockLintFn(nil)}),
-					WithVersioner(&verfake.MockVersioner{MockInConstraints: verfake.NewMockInConstraintsFn(true, nil)}),
+					WithVersioner(&verfake.MockVersioner{MockInConstraints: verfake.NewMockInConstraintsFn(true, nil), MockGetVersionString: verfake.NewMockGetVersionStringFn("v0.13.1")}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"PauseReconcileSpec": {
+			reason: "Pause reconciliation if the revision is paused via its spec.",
+			args: args{
+				mgr: &fake.Manager{},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ProviderRevision{} }),
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								pr := o.(*v1.ProviderRevision)
+								pr.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								pr.SetDesiredState(v1.PackageRevisionActive)
+								pr.SetPaused(ptr.To(true))
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ProviderRevision{}
+								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetPaused(ptr.To(true))
+								want.SetConditions(xpv1.ReconcilePaused().WithMessage(reconcileSpecPausedMsg))
+
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+					}),
+					WithRuntimeHooks(&MockHook{
+						MockPre: func() error {
+							t.Errorf("Pre(...): runtime hooks should not run while paused")
+							return nil
+						},
+						MockPost: func() error {
+							t.Errorf("Post(...): runtime hooks should not run while paused")
+							return nil
+						},
+					}),
 				},
 			},
 			want: want{
@@ -1559,7 +2387,9 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
-								want.SetConditions(v1.Healthy())
+								want.SetContents(&v1.PackageContents{})
+								want.SetCrossplaneVersion("v0.13.1")
+								want.SetConditions(v1.APIsEstablished(), v1.Healthy())
 
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
@@ -1571,6 +2401,7 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetContents(&v1.PackageContents{})
 								want.CleanConditions()
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
@@ -1595,7 +2426,7 @@ func TestReconcile(t *testing.T) {
 						},
 					}),
 					WithLinter(&MockLinter{MockLint: NewMockLintFn(nil)}),
-					WithVersioner(&verfake.MockVersioner{MockInConstraints: verfake.NewMockInConstraintsFn(true, nil)}),
+					WithVersioner(&verfake.MockVersioner{MockInConstraints: verfake.NewMockInConstraintsFn(true, nil), MockGetVersionString: verfake.NewMockGetVersionStringFn("v0.13.1")}),
 				},
 			},
 			want: want{
@@ -17,6 +17,8 @@ limitations under the License.
 package revision
 
 import (
+	"fmt"
+
 	"golang.org/x/net/context"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -46,6 +48,7 @@ const (
 	grpcPortName       = "grpc"
 	servicePort        = 9443
 	serviceEndpointFmt = "dns:///%s.%s:%d"
+	serviceDNSNameFmt  = "%s.%s.svc"
 
 	essTLSCertDirEnvVar = "ESS_TLS_CERTS_DIR"
 
@@ -56,6 +59,10 @@ const (
 	tlsClientCertDirEnvVar   = "TLS_CLIENT_CERTS_DIR"
 	tlsClientCertsVolumeName = "tls-client-certs"
 	tlsClientCertsDir        = "/tls/client"
+
+	kubeconfigEnvVar     = "KUBECONFIG"
+	kubeconfigVolumeName = "kubeconfig"
+	kubeconfigDir        = "/kubeconfig"
 )
 
 //nolint:gochecknoglobals // We treat these as constants, but take their addresses.
@@ -101,6 +108,7 @@ type RuntimeManifestBuilder struct {
 	serviceAccountPullSecrets []corev1.LocalObjectReference
 	runtimeConfig             *v1beta1.DeploymentRuntimeConfig
 	controllerConfig          *v1alpha1.ControllerConfig
+	platforms                 []string
 }
 
 // RuntimeManifestBuilderOption is used to configure a RuntimeManifestBuilder.
@@ -130,6 +138,14 @@ func RuntimeManifestBuilderWithServiceAccountPullSecrets(secrets []corev1.LocalO
 	}
 }
 
+// RuntimeManifestBuilderWithPlatforms sets the platforms, in os[/arch] form,
+// that the runtime Deployment's pods may be scheduled to.
+func RuntimeManifestBuilderWithPlatforms(platforms []string) RuntimeManifestBuilderOption {
+	return func(b *RuntimeManifestBuilder) {
+		b.platforms = platforms
+	}
+}
+
 // NewRuntimeManifestBuilder returns a new RuntimeManifestBuilder.
 func NewRuntimeManifestBuilder(pwr v1.PackageRevisionWithRuntime, namespace string, opts ...RuntimeManifestBuilderOption) *RuntimeManifestBuilder {
 	b := &RuntimeManifestBuilder{
@@ -209,6 +225,7 @@ func (b *RuntimeManifestBuilder) Deployment(serviceAccount string, overrides ...
 			RunAsNonRoot:             &runAsNonRoot,
 		}),
 		DeploymentWithOptionalServiceAccount(serviceAccount),
+		DeploymentWithOptionalPlatformAffinity(b.platforms),
 
 		// Overrides that we are opinionated about.
 		DeploymentWithNamespace(b.namespace),
@@ -247,6 +264,16 @@ func (b *RuntimeManifestBuilder) Deployment(serviceAccount string, overrides ...
 		allOverrides = append(allOverrides, DeploymentForControllerConfig(b.controllerConfig))
 	}
 
+	// Additional args are appended after the ControllerConfig overrides,
+	// which replace the runtime container's args.
+	if b.runtimeConfig != nil && len(b.runtimeConfig.Spec.AdditionalArgs) > 0 {
+		allOverrides = append(allOverrides, DeploymentRuntimeWithAdditionalArgs(b.runtimeConfig.Spec.AdditionalArgs))
+	}
+
+	if b.runtimeConfig != nil && b.runtimeConfig.Spec.KubeconfigSecretRef != nil {
+		allOverrides = append(allOverrides, DeploymentRuntimeWithKubeconfigSecret(*b.runtimeConfig.Spec.KubeconfigSecretRef))
+	}
+
 	for _, o := range allOverrides {
 		o(d)
 	}
@@ -320,6 +347,38 @@ func (b *RuntimeManifestBuilder) TLSServerSecret() *corev1.Secret {
 	}
 }
 
+// setRuntimeDeployment records in the supplied package revision's status that
+// its runtime is the supplied Deployment.
+func setRuntimeDeployment(pr v1.PackageRevisionWithRuntime, d *appsv1.Deployment) {
+	s := pr.GetRuntimeStatus()
+	if s == nil {
+		s = &v1.PackageRevisionRuntimeStatus{}
+	}
+	s.DeploymentRef = &v1.RuntimeObjectReference{Name: d.GetName(), Namespace: d.GetNamespace()}
+	s.ServiceAccountRef = &v1.RuntimeObjectReference{Name: d.Spec.Template.Spec.ServiceAccountName, Namespace: d.GetNamespace()}
+	pr.SetRuntimeStatus(s)
+}
+
+// setRuntimeService records in the supplied package revision's status that
+// its runtime is fronted by the supplied Service.
+func setRuntimeService(pr v1.PackageRevisionWithRuntime, svc *corev1.Service) {
+	s := pr.GetRuntimeStatus()
+	if s == nil {
+		s = &v1.PackageRevisionRuntimeStatus{}
+	}
+	s.ServiceRef = &v1.RuntimeObjectReference{Name: svc.GetName(), Namespace: svc.GetNamespace()}
+	s.ServiceDNSName = fmt.Sprintf(serviceDNSNameFmt, svc.GetName(), svc.GetNamespace())
+	pr.SetRuntimeStatus(s)
+}
+
+// clearDeploymentRef removes the reference to a deleted runtime Deployment
+// from the supplied package revision's status.
+func clearDeploymentRef(pr v1.PackageRevisionWithRuntime) {
+	if s := pr.GetRuntimeStatus(); s != nil {
+		s.DeploymentRef = nil
+	}
+}
+
 func (b *RuntimeManifestBuilder) podSelectors() map[string]string {
 	return map[string]string{
 		"pkg.crossplane.io/revision":           b.revision.GetName(),
@@ -65,7 +65,14 @@ func deploymentFromRuntimeConfig(tmpl *v1beta1.DeploymentTemplate) *appsv1.Deplo
 func serviceFromRuntimeConfig(tmpl *v1beta1.ServiceTemplate) *corev1.Service {
 	svc := &corev1.Service{}
 
-	if tmpl == nil || tmpl.Metadata == nil {
+	if tmpl == nil {
+		return svc
+	}
+
+	// Copy the ports, so that appending to them can't modify the template.
+	svc.Spec.Ports = append([]corev1.ServicePort(nil), tmpl.Ports...)
+
+	if tmpl.Metadata == nil {
 		return svc
 	}
 
@@ -297,6 +297,14 @@ func TestFunctionPostHook(t *testing.T) {
 							DesiredState: v1.PackageRevisionActive,
 						},
 					},
+					Status: v1.FunctionRevisionStatus{
+						PackageRevisionStatus: v1.PackageRevisionStatus{
+							Runtime: &v1.PackageRevisionRuntimeStatus{
+								DeploymentRef:     &v1.RuntimeObjectReference{},
+								ServiceAccountRef: &v1.RuntimeObjectReference{},
+							},
+						},
+					},
 				},
 				err: errors.New(errNoAvailableConditionFunctionDeployment),
 			},
@@ -346,6 +354,14 @@ func TestFunctionPostHook(t *testing.T) {
 							DesiredState: v1.PackageRevisionActive,
 						},
 					},
+					Status: v1.FunctionRevisionStatus{
+						PackageRevisionStatus: v1.PackageRevisionStatus{
+							Runtime: &v1.PackageRevisionRuntimeStatus{
+								DeploymentRef:     &v1.RuntimeObjectReference{},
+								ServiceAccountRef: &v1.RuntimeObjectReference{},
+							},
+						},
+					},
 				},
 				err: errors.Errorf(errFmtUnavailableFunctionDeployment, errBoom.Error()),
 			},
@@ -394,6 +410,14 @@ func TestFunctionPostHook(t *testing.T) {
 							DesiredState: v1.PackageRevisionActive,
 						},
 					},
+					Status: v1.FunctionRevisionStatus{
+						PackageRevisionStatus: v1.PackageRevisionStatus{
+							Runtime: &v1.PackageRevisionRuntimeStatus{
+								DeploymentRef:     &v1.RuntimeObjectReference{},
+								ServiceAccountRef: &v1.RuntimeObjectReference{},
+							},
+						},
+					},
 				},
 			},
 		},
@@ -444,6 +468,14 @@ func TestFunctionPostHook(t *testing.T) {
 							DesiredState: v1.PackageRevisionActive,
 						},
 					},
+					Status: v1.FunctionRevisionStatus{
+						PackageRevisionStatus: v1.PackageRevisionStatus{
+							Runtime: &v1.PackageRevisionRuntimeStatus{
+								DeploymentRef:     &v1.RuntimeObjectReference{},
+								ServiceAccountRef: &v1.RuntimeObjectReference{},
+							},
+						},
+					},
 				},
 			},
 		},
@@ -509,6 +541,14 @@ func TestFunctionPostHook(t *testing.T) {
 							DesiredState: v1.PackageRevisionActive,
 						},
 					},
+					Status: v1.FunctionRevisionStatus{
+						PackageRevisionStatus: v1.PackageRevisionStatus{
+							Runtime: &v1.PackageRevisionRuntimeStatus{
+								DeploymentRef:     &v1.RuntimeObjectReference{},
+								ServiceAccountRef: &v1.RuntimeObjectReference{},
+							},
+						},
+					},
 				},
 			},
 		},
@@ -571,8 +611,18 @@ func TestFunctionDeactivateHook(t *testing.T) {
 			},
 		},
 		"Successful": {
-			reason: "Should not return error if successfully deleted service account and deployment.",
+			reason: "Should not return error if successfully deleted deployment, and should clear the deployment reference.",
 			args: args{
+				rev: &v1.FunctionRevision{
+					Status: v1.FunctionRevisionStatus{
+						PackageRevisionStatus: v1.PackageRevisionStatus{
+							Runtime: &v1.PackageRevisionRuntimeStatus{
+								DeploymentRef:     &v1.RuntimeObjectReference{Name: "some-deployment"},
+								ServiceAccountRef: &v1.RuntimeObjectReference{Name: "some-sa"},
+							},
+						},
+					},
+				},
 				manifests: &MockManifestBuilder{
 					ServiceAccountFn: func(_ ...ServiceAccountOverride) *corev1.ServiceAccount {
 						return &corev1.ServiceAccount{
@@ -615,6 +665,17 @@ func TestFunctionDeactivateHook(t *testing.T) {
 					},
 				},
 			},
+			want: want{
+				rev: &v1.FunctionRevision{
+					Status: v1.FunctionRevisionStatus{
+						PackageRevisionStatus: v1.PackageRevisionStatus{
+							Runtime: &v1.PackageRevisionRuntimeStatus{
+								ServiceAccountRef: &v1.RuntimeObjectReference{Name: "some-sa"},
+							},
+						},
+					},
+				},
+			},
 		},
 	}
 
@@ -17,11 +17,15 @@ limitations under the License.
 package revision
 
 import (
+	"path"
+	"strings"
+
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/crossplane/crossplane/apis/pkg/v1alpha1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
 	"github.com/crossplane/crossplane/internal/initializer"
 )
 
@@ -206,6 +210,30 @@ func DeploymentRuntimeWithTLSClientSecret(secret string) DeploymentOverride {
 	}
 }
 
+// DeploymentRuntimeWithKubeconfigSecret mounts the referenced key of a Secret
+// containing a kubeconfig into the runtime container of a Deployment, and
+// points the KUBECONFIG environment variable at it.
+func DeploymentRuntimeWithKubeconfigSecret(ref v1beta1.SecretKeySelector) DeploymentOverride {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Template.Spec.Volumes = append(d.Spec.Template.Spec.Volumes, corev1.Volume{
+			Name: kubeconfigVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: ref.Name,
+					Items:      []corev1.KeyToPath{{Key: ref.Key, Path: kubeconfigVolumeName}},
+				},
+			},
+		})
+		c := &d.Spec.Template.Spec.Containers[0]
+		c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
+			Name:      kubeconfigVolumeName,
+			ReadOnly:  true,
+			MountPath: kubeconfigDir,
+		})
+		c.Env = append(c.Env, corev1.EnvVar{Name: kubeconfigEnvVar, Value: path.Join(kubeconfigDir, kubeconfigVolumeName)})
+	}
+}
+
 // DeploymentRuntimeWithAdditionalEnvironments adds additional environment
 // variables to the runtime container of a Deployment.
 func DeploymentRuntimeWithAdditionalEnvironments(env []corev1.EnvVar) DeploymentOverride {
@@ -214,6 +242,14 @@ func DeploymentRuntimeWithAdditionalEnvironments(env []corev1.EnvVar) Deployment
 	}
 }
 
+// DeploymentRuntimeWithAdditionalArgs appends additional arguments to the
+// runtime container of a Deployment.
+func DeploymentRuntimeWithAdditionalArgs(args []string) DeploymentOverride {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Template.Spec.Containers[0].Args = append(d.Spec.Template.Spec.Containers[0].Args, args...)
+	}
+}
+
 // DeploymentRuntimeWithAdditionalPorts adds additional ports to the runtime
 // container of a Deployment.
 func DeploymentRuntimeWithAdditionalPorts(ports []corev1.ContainerPort) DeploymentOverride {
@@ -222,6 +258,65 @@ func DeploymentRuntimeWithAdditionalPorts(ports []corev1.ContainerPort) Deployme
 	}
 }
 
+// DeploymentWithAdditionalContainers adds additional containers to a
+// Deployment. A container is not added if the Deployment already has a
+// container with the same name.
+//
+// The containers come from package metadata, which is less trusted than a
+// DeploymentRuntimeConfig. Only a vetted subset of each container's fields is
+// used, and each container runs with the same restricted security context as
+// the runtime container. A container that needs more, for example volume
+// mounts, must be specified using a DeploymentRuntimeConfig.
+func DeploymentWithAdditionalContainers(containers []corev1.Container) DeploymentOverride {
+	return func(d *appsv1.Deployment) {
+		existing := make(map[string]bool, len(d.Spec.Template.Spec.Containers))
+		for _, c := range d.Spec.Template.Spec.Containers {
+			existing[c.Name] = true
+		}
+		for _, c := range containers {
+			if existing[c.Name] {
+				continue
+			}
+			d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, packageContainer(c))
+		}
+	}
+}
+
+// packageContainer returns a copy of the supplied container that includes only
+// the fields a package may set, and the restricted security context.
+func packageContainer(c corev1.Container) corev1.Container {
+	out := corev1.Container{
+		Name:           c.Name,
+		Image:          c.Image,
+		Command:        c.Command,
+		Args:           c.Args,
+		WorkingDir:     c.WorkingDir,
+		Ports:          c.Ports,
+		Resources:      c.Resources,
+		LivenessProbe:  c.LivenessProbe,
+		ReadinessProbe: c.ReadinessProbe,
+		SecurityContext: &corev1.SecurityContext{
+			RunAsUser:                &runAsUser,
+			RunAsGroup:               &runAsGroup,
+			AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+			Privileged:               &privileged,
+			RunAsNonRoot:             &runAsNonRoot,
+		},
+	}
+
+	// Only literal environment variables are allowed. A variable that
+	// references a Secret or ConfigMap could read data the package author
+	// shouldn't have access to.
+	for _, e := range c.Env {
+		if e.ValueFrom != nil {
+			continue
+		}
+		out.Env = append(out.Env, e)
+	}
+
+	return out
+}
+
 // DeploymentWithOptionalPodSecurityContext sets the pod security context if it
 // is unset.
 func DeploymentWithOptionalPodSecurityContext(podSecurityContext *corev1.PodSecurityContext) DeploymentOverride {
@@ -268,6 +363,43 @@ func DeploymentWithRuntimeContainer() DeploymentOverride {
 	}
 }
 
+// DeploymentWithOptionalPlatformAffinity requires the Deployment's pods to be
+// scheduled to nodes of one of the supplied platforms, in os[/arch] form. It
+// does nothing if the Deployment already specifies node affinity.
+func DeploymentWithOptionalPlatformAffinity(platforms []string) DeploymentOverride {
+	return func(d *appsv1.Deployment) {
+		if len(platforms) == 0 {
+			return
+		}
+		if d.Spec.Template.Spec.Affinity != nil && d.Spec.Template.Spec.Affinity.NodeAffinity != nil {
+			return
+		}
+
+		// Node selector terms are ORed, and the expressions within a term
+		// are ANDed.
+		terms := make([]corev1.NodeSelectorTerm, 0, len(platforms))
+		for _, p := range platforms {
+			os, arch, _ := strings.Cut(p, "/")
+			t := corev1.NodeSelectorTerm{
+				MatchExpressions: []corev1.NodeSelectorRequirement{
+					{Key: corev1.LabelOSStable, Operator: corev1.NodeSelectorOpIn, Values: []string{os}},
+				},
+			}
+			if arch != "" {
+				t.MatchExpressions = append(t.MatchExpressions, corev1.NodeSelectorRequirement{Key: corev1.LabelArchStable, Operator: corev1.NodeSelectorOpIn, Values: []string{arch}})
+			}
+			terms = append(terms, t)
+		}
+
+		if d.Spec.Template.Spec.Affinity == nil {
+			d.Spec.Template.Spec.Affinity = &corev1.Affinity{}
+		}
+		d.Spec.Template.Spec.Affinity.NodeAffinity = &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: terms},
+		}
+	}
+}
+
 // DeploymentForControllerConfig overrides the deployment with the values
 // defined in the ControllerConfig.
 func DeploymentForControllerConfig(cc *v1alpha1.ControllerConfig) DeploymentOverride { //nolint:gocognit // Simple if statements for setting values if they are not nil/empty.
@@ -329,6 +461,12 @@ func DeploymentForControllerConfig(cc *v1alpha1.ControllerConfig) DeploymentOver
 		if cc.Spec.RuntimeClassName != nil {
 			d.Spec.Template.Spec.RuntimeClassName = cc.Spec.RuntimeClassName
 		}
+		if cc.Spec.DNSPolicy != nil {
+			d.Spec.Template.Spec.DNSPolicy = *cc.Spec.DNSPolicy
+		}
+		if cc.Spec.DNSConfig != nil {
+			d.Spec.Template.Spec.DNSConfig = cc.Spec.DNSConfig
+		}
 		if cc.Spec.ResourceRequirements != nil {
 			d.Spec.Template.Spec.Containers[0].Resources = *cc.Spec.ResourceRequirements
 		}
@@ -6,6 +6,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
 )
 
 func TestDeploymentWithRuntimeContainer(t *testing.T) {
@@ -211,3 +212,203 @@ func TestDeploymentWithRuntimeContainer(t *testing.T) {
 		})
 	}
 }
+
+func TestDeploymentWithOptionalPlatformAffinity(t *testing.T) {
+	type args struct {
+		platforms  []string
+		deployment *appsv1.Deployment
+	}
+	type want struct {
+		deployment *appsv1.Deployment
+	}
+
+	existing := &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{
+					{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "example.org/pool", Operator: corev1.NodeSelectorOpExists}}},
+				},
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoPlatforms": {
+			reason: "Should not add node affinity if no platforms are supplied",
+			args: args{
+				deployment: &appsv1.Deployment{},
+			},
+			want: want{
+				deployment: &appsv1.Deployment{},
+			},
+		},
+		"ExistingNodeAffinity": {
+			reason: "Should not override existing node affinity",
+			args: args{
+				platforms: []string{"linux/amd64"},
+				deployment: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Affinity: existing}}},
+				},
+			},
+			want: want{
+				deployment: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Affinity: existing}}},
+				},
+			},
+		},
+		"Platforms": {
+			reason: "Should require one node selector term per platform",
+			args: args{
+				platforms:  []string{"linux/amd64", "linux"},
+				deployment: &appsv1.Deployment{},
+			},
+			want: want{
+				deployment: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Affinity: &corev1.Affinity{
+									NodeAffinity: &corev1.NodeAffinity{
+										RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+											NodeSelectorTerms: []corev1.NodeSelectorTerm{
+												{
+													MatchExpressions: []corev1.NodeSelectorRequirement{
+														{Key: corev1.LabelOSStable, Operator: corev1.NodeSelectorOpIn, Values: []string{"linux"}},
+														{Key: corev1.LabelArchStable, Operator: corev1.NodeSelectorOpIn, Values: []string{"amd64"}},
+													},
+												},
+												{
+													MatchExpressions: []corev1.NodeSelectorRequirement{
+														{Key: corev1.LabelOSStable, Operator: corev1.NodeSelectorOpIn, Values: []string{"linux"}},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			DeploymentWithOptionalPlatformAffinity(tc.args.platforms)(tc.args.deployment)
+			if diff := cmp.Diff(tc.want.deployment, tc.args.deployment); diff != "" {
+				t.Errorf("\n%s\nDeploymentWithOptionalPlatformAffinity(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDeploymentWithAdditionalContainers(t *testing.T) {
+	type args struct {
+		containers []corev1.Container
+		deployment *appsv1.Deployment
+	}
+	type want struct {
+		deployment *appsv1.Deployment
+	}
+
+	restricted := &corev1.SecurityContext{
+		RunAsUser:                &runAsUser,
+		RunAsGroup:               &runAsGroup,
+		AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+		Privileged:               &privileged,
+		RunAsNonRoot:             &runAsNonRoot,
+	}
+
+	withContainers := func(cs ...corev1.Container) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: cs}}},
+		}
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoContainers": {
+			reason: "Should not change the deployment if no containers are supplied",
+			args: args{
+				deployment: withContainers(corev1.Container{Name: runtimeContainerName}),
+			},
+			want: want{
+				deployment: withContainers(corev1.Container{Name: runtimeContainerName}),
+			},
+		},
+		"AdditionalContainers": {
+			reason: "Should append the supplied containers after the existing containers",
+			args: args{
+				containers: []corev1.Container{{Name: "agent", Image: "example.org/agent"}},
+				deployment: withContainers(corev1.Container{Name: runtimeContainerName}),
+			},
+			want: want{
+				deployment: withContainers(
+					corev1.Container{Name: runtimeContainerName},
+					corev1.Container{Name: "agent", Image: "example.org/agent", SecurityContext: restricted},
+				),
+			},
+		},
+		"UnvettedFields": {
+			reason: "Should drop fields a package may not set, drop non-literal environment variables, and enforce the restricted security context",
+			args: args{
+				containers: []corev1.Container{{
+					Name:  "agent",
+					Image: "example.org/agent",
+					Args:  []string{"--debug"},
+					Env: []corev1.EnvVar{
+						{Name: "MODE", Value: "agent"},
+						{Name: "TOKEN", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{Key: "token"}}},
+					},
+					VolumeMounts:    []corev1.VolumeMount{{Name: "host", MountPath: "/host"}},
+					SecurityContext: &corev1.SecurityContext{Privileged: ptr.To(true)},
+				}},
+				deployment: withContainers(corev1.Container{Name: runtimeContainerName}),
+			},
+			want: want{
+				deployment: withContainers(
+					corev1.Container{Name: runtimeContainerName},
+					corev1.Container{
+						Name:            "agent",
+						Image:           "example.org/agent",
+						Args:            []string{"--debug"},
+						Env:             []corev1.EnvVar{{Name: "MODE", Value: "agent"}},
+						SecurityContext: restricted,
+					},
+				),
+			},
+		},
+		"ExistingContainer": {
+			reason: "Should not add a container if the deployment already has a container with the same name",
+			args: args{
+				containers: []corev1.Container{{Name: "agent", Image: "example.org/agent"}},
+				deployment: withContainers(
+					corev1.Container{Name: runtimeContainerName},
+					corev1.Container{Name: "agent", Image: "example.org/custom-agent"},
+				),
+			},
+			want: want{
+				deployment: withContainers(
+					corev1.Container{Name: runtimeContainerName},
+					corev1.Container{Name: "agent", Image: "example.org/custom-agent"},
+				),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			DeploymentWithAdditionalContainers(tc.args.containers)(tc.args.deployment)
+			if diff := cmp.Diff(tc.want.deployment, tc.args.deployment); diff != "" {
+				t.Errorf("\n%s\nDeploymentWithAdditionalContainers(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
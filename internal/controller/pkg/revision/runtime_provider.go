@@ -19,12 +19,14 @@ package revision
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/go-containerregistry/pkg/name"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -49,23 +51,48 @@ const (
 	errFmtUnavailableProviderDeployment       = "provider package deployment is unavailable with message: %s"
 	errNoAvailableConditionProviderDeployment = "provider package deployment has no condition of type \"Available\" yet"
 	errParseProviderImage                     = "cannot parse provider package image"
+	errGetProviderDeployment                  = "cannot get provider package deployment"
+	errScaleDownProviderDeployment            = "cannot scale down provider package deployment"
+	errListProviderRevisions                  = "cannot list provider revisions"
+	errFmtDrainingProviderDeployment          = "provider package deployment is draining until active revision %q has been healthy for %s"
+	errFmtProviderImageDigest                 = "provider package deployment image %q does not reference digest %q pinned by the package metadata"
+	errFmtNoProviderRuntimeContainer          = "provider package deployment has no %q container"
 )
 
 // ProviderHooks performs runtime operations for provider packages.
 type ProviderHooks struct {
 	client          resource.ClientApplicator
 	defaultRegistry string
+	drainPeriod     time.Duration
+}
+
+// A ProviderHooksOption configures ProviderHooks.
+type ProviderHooksOption func(*ProviderHooks)
+
+// ProviderHooksWithDrainPeriod configures ProviderHooks to scale the
+// Deployment of a deactivated revision to zero once the active revision has
+// been healthy for the supplied period, rather than deleting it immediately.
+func ProviderHooksWithDrainPeriod(d time.Duration) ProviderHooksOption {
+	return func(h *ProviderHooks) {
+		h.drainPeriod = d
+	}
 }
 
 // NewProviderHooks returns a new ProviderHooks.
-func NewProviderHooks(client client.Client, defaultRegistry string) *ProviderHooks {
-	return &ProviderHooks{
+func NewProviderHooks(client client.Client, defaultRegistry string, opts ...ProviderHooksOption) *ProviderHooks {
+	h := &ProviderHooks{
 		client: resource.ClientApplicator{
 			Client:     client,
 			Applicator: resource.NewAPIPatchingApplicator(client),
 		},
 		defaultRegistry: defaultRegistry,
 	}
+
+	for _, o := range opts {
+		o(h)
+	}
+
+	return h
 }
 
 // Pre performs operations meant to happen before establishing objects.
@@ -102,6 +129,7 @@ func (h *ProviderHooks) Pre(ctx context.Context, pkg runtime.Object, pr v1.Packa
 	if err := h.client.Apply(ctx, svc); err != nil {
 		return errors.Wrap(err, errApplyProviderService)
 	}
+	setRuntimeService(pr, svc)
 
 	secClient := build.TLSClientSecret()
 	secServer := build.TLSServerSecret()
@@ -149,6 +177,9 @@ func (h *ProviderHooks) Post(ctx context.Context, pkg runtime.Object, pr v1.Pack
 	}
 
 	d := build.Deployment(sa.Name, providerDeploymentOverrides(providerMeta, pr, image)...)
+	if err := verifyProviderImageDigest(providerMeta, d, h.defaultRegistry); err != nil {
+		return err
+	}
 	// Create/Apply the SA only if the deployment references it.
 	// This is to avoid creating a SA that is not used by the deployment when
 	// the SA is managed externally by the user and configured by setting
@@ -162,6 +193,7 @@ func (h *ProviderHooks) Post(ctx context.Context, pkg runtime.Object, pr v1.Pack
 	if err := h.client.Apply(ctx, d); err != nil {
 		return errors.Wrap(err, errApplyProviderDeployment)
 	}
+	setRuntimeDeployment(pr, d)
 
 	for _, c := range d.Status.Conditions {
 		if c.Type == appsv1.DeploymentAvailable {
@@ -177,12 +209,23 @@ func (h *ProviderHooks) Post(ctx context.Context, pkg runtime.Object, pr v1.Pack
 // Deactivate performs operations meant to happen before deactivating a revision.
 func (h *ProviderHooks) Deactivate(ctx context.Context, pr v1.PackageRevisionWithRuntime, build ManifestBuilder) error {
 	sa := build.ServiceAccount()
-	// Delete the deployment if it exists.
 	// Different from the Post runtimeHook, we don't need to pass the
 	// "providerDeploymentOverrides()" here, because we're only interested
-	// in the name and namespace of the deployment to delete it.
-	if err := h.client.Delete(ctx, build.Deployment(sa.Name)); resource.IgnoreNotFound(err) != nil {
-		return errors.Wrap(err, errDeleteProviderDeployment)
+	// in the name and namespace of the deployment to drain or delete it.
+	d := build.Deployment(sa.Name)
+
+	if h.drainPeriod > 0 {
+		// Scale the deployment to zero rather than deleting it once it has
+		// drained. It's garbage collected with the revision.
+		if err := h.drain(ctx, pr, d); err != nil {
+			return err
+		}
+	} else {
+		// Delete the deployment if it exists.
+		if err := h.client.Delete(ctx, d); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, errDeleteProviderDeployment)
+		}
+		clearDeploymentRef(pr)
 	}
 
 	// TODO(phisco): only added to cleanup the service we were previously
@@ -204,6 +247,67 @@ func (h *ProviderHooks) Deactivate(ctx context.Context, pr v1.PackageRevisionWit
 	return nil
 }
 
+// A drainingError indicates that an inactive revision's deployment is still
+// draining, and that the revision should be reconciled again once it may have
+// drained.
+type drainingError struct {
+	active      string
+	drainPeriod time.Duration
+	after       time.Duration
+}
+
+func (e *drainingError) Error() string {
+	return fmt.Sprintf(errFmtDrainingProviderDeployment, e.active, e.drainPeriod)
+}
+
+// IsDraining returns true, and how long to wait before checking again, if the
+// supplied error indicates that an inactive revision's deployment is still
+// draining.
+func IsDraining(err error) (time.Duration, bool) {
+	de := &drainingError{}
+	if errors.As(err, &de) {
+		return de.after, true
+	}
+	return 0, false
+}
+
+// drain scales the supplied deployment of an inactive revision to zero once
+// the active revision of the same provider has been healthy for the drain
+// period. This gives the new revision's controller time to take over before
+// the old one stops reconciling. A drainingError is returned until then.
+func (h *ProviderHooks) drain(ctx context.Context, pr v1.PackageRevisionWithRuntime, d *appsv1.Deployment) error {
+	existing := &appsv1.Deployment{}
+	if err := h.client.Get(ctx, types.NamespacedName{Name: d.GetName(), Namespace: d.GetNamespace()}, existing); err != nil {
+		return errors.Wrap(resource.IgnoreNotFound(err), errGetProviderDeployment)
+	}
+	if existing.Spec.Replicas != nil && *existing.Spec.Replicas == 0 {
+		return nil
+	}
+
+	l := &v1.ProviderRevisionList{}
+	if err := h.client.List(ctx, l, client.MatchingLabels{v1.LabelParentPackage: pr.GetLabels()[v1.LabelParentPackage]}); err != nil {
+		return errors.Wrap(err, errListProviderRevisions)
+	}
+	for _, rev := range l.Items {
+		if rev.GetName() == pr.GetName() || rev.GetDesiredState() != v1.PackageRevisionActive {
+			continue
+		}
+		// Keep the inactive revision's controller running until the active
+		// revision's controller has been healthy for the drain period. If
+		// there is no active revision there is nothing to hand over to.
+		c := rev.GetCondition(v1.TypeHealthy)
+		if c.Status != corev1.ConditionTrue {
+			return &drainingError{active: rev.GetName(), drainPeriod: h.drainPeriod, after: h.drainPeriod}
+		}
+		if healthy := time.Since(c.LastTransitionTime.Time); healthy < h.drainPeriod {
+			return &drainingError{active: rev.GetName(), drainPeriod: h.drainPeriod, after: h.drainPeriod - healthy}
+		}
+	}
+
+	existing.Spec.Replicas = ptr.To[int32](0)
+	return errors.Wrap(h.client.Update(ctx, existing), errScaleDownProviderDeployment)
+}
+
 func providerDeploymentOverrides(pm *pkgmetav1.Provider, pr v1.PackageRevisionWithRuntime, image string) []DeploymentOverride {
 	do := []DeploymentOverride{
 		DeploymentRuntimeWithAdditionalEnvironments([]corev1.EnvVar{
@@ -239,6 +343,12 @@ func providerDeploymentOverrides(pm *pkgmetav1.Provider, pr v1.PackageRevisionWi
 
 	do = append(do, DeploymentRuntimeWithOptionalImage(image))
 
+	// Providers that consist of more than one process may ship the
+	// additional containers and ports they need in their package metadata.
+	if rt := pm.Spec.Controller.Runtime; rt != nil {
+		do = append(do, DeploymentWithAdditionalContainers(rt.Containers), DeploymentRuntimeWithAdditionalPorts(rt.Ports))
+	}
+
 	if pr.GetTLSClientSecretName() != nil {
 		do = append(do, DeploymentRuntimeWithAdditionalEnvironments([]corev1.EnvVar{
 			// for backward compatibility with existing providers, we set the
@@ -281,6 +391,8 @@ func providerSelectors(providerMeta *pkgmetav1.Provider, pr v1.PackageRevisionWi
 // getProviderImage determines a complete provider image, taking into account a
 // default registry. If the provider meta specifies an image, we have a
 // preference for that image over what is specified in the package revision.
+// If the provider meta pins an image digest, an image that is referenced by
+// tag is referenced by the pinned digest instead.
 func getProviderImage(pm *pkgmetav1.Provider, pr v1.PackageRevisionWithRuntime, defaultRegistry string) (string, error) {
 	image := pr.GetSource()
 	if pm.Spec.Controller.Image != nil {
@@ -291,9 +403,45 @@ func getProviderImage(pm *pkgmetav1.Provider, pr v1.PackageRevisionWithRuntime,
 		return "", errors.Wrap(err, errParseProviderImage)
 	}
 
+	if d := pm.Spec.Controller.ImageDigest; d != nil {
+		if _, ok := ref.(name.Tag); ok {
+			return ref.Context().Digest(*d).Name(), nil
+		}
+	}
+
 	return ref.Name(), nil
 }
 
+// verifyProviderImageDigest returns an error if the provider meta pins an
+// image digest, and the runtime container of the supplied Deployment doesn't
+// reference it. The image may have been overridden, for example by a
+// DeploymentRuntimeConfig.
+func verifyProviderImageDigest(pm *pkgmetav1.Provider, d *appsv1.Deployment, defaultRegistry string) error {
+	want := pm.Spec.Controller.ImageDigest
+	if want == nil {
+		return nil
+	}
+	var image string
+	found := false
+	for _, c := range d.Spec.Template.Spec.Containers {
+		if c.Name == runtimeContainerName {
+			image, found = c.Image, true
+			break
+		}
+	}
+	if !found {
+		return errors.Errorf(errFmtNoProviderRuntimeContainer, runtimeContainerName)
+	}
+	ref, err := name.ParseReference(image, name.WithDefaultRegistry(defaultRegistry))
+	if err != nil {
+		return errors.Wrap(err, errParseProviderImage)
+	}
+	if dr, ok := ref.(name.Digest); ok && dr.DigestStr() == *want {
+		return nil
+	}
+	return errors.Errorf(errFmtProviderImageDigest, image, *want)
+}
+
 // applySA creates/updates a ServiceAccount and includes any image pull secrets
 // that have been added by external controllers.
 func applySA(ctx context.Context, cl resource.ClientApplicator, sa *corev1.ServiceAccount) error {
@@ -18,7 +18,9 @@ package revision
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	appsv1 "k8s.io/api/apps/v1"
@@ -35,6 +37,7 @@ import (
 
 	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
 	"github.com/crossplane/crossplane/internal/xpkg"
 )
 
@@ -44,6 +47,8 @@ const (
 	versionDep        = "v0.1.1"
 
 	xpManagedSA = "xp-managed-sa"
+
+	testDigest = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
 )
 
 var errBoom = errors.New("boom")
@@ -148,7 +153,12 @@ func TestProviderPreHook(t *testing.T) {
 				},
 				manifests: &MockManifestBuilder{
 					ServiceFn: func(_ ...ServiceOverride) *corev1.Service {
-						return &corev1.Service{}
+						return &corev1.Service{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      "provider-nop",
+								Namespace: "crossplane-system",
+							},
+						}
 					},
 					TLSClientSecretFn: func() *corev1.Secret {
 						return &corev1.Secret{}
@@ -180,6 +190,15 @@ func TestProviderPreHook(t *testing.T) {
 							TLSServerSecretName: ptr.To("some-server-secret"),
 						},
 					},
+					Status: v1.PackageRevisionStatus{
+						Runtime: &v1.PackageRevisionRuntimeStatus{
+							ServiceRef: &v1.RuntimeObjectReference{
+								Name:      "provider-nop",
+								Namespace: "crossplane-system",
+							},
+							ServiceDNSName: "provider-nop.crossplane-system.svc",
+						},
+					},
 				},
 			},
 		},
@@ -368,6 +387,12 @@ func TestProviderPostHook(t *testing.T) {
 							DesiredState: v1.PackageRevisionActive,
 						},
 					},
+					Status: v1.PackageRevisionStatus{
+						Runtime: &v1.PackageRevisionRuntimeStatus{
+							DeploymentRef:     &v1.RuntimeObjectReference{},
+							ServiceAccountRef: &v1.RuntimeObjectReference{},
+						},
+					},
 				},
 				err: errors.New(errNoAvailableConditionProviderDeployment),
 			},
@@ -417,6 +442,12 @@ func TestProviderPostHook(t *testing.T) {
 							DesiredState: v1.PackageRevisionActive,
 						},
 					},
+					Status: v1.PackageRevisionStatus{
+						Runtime: &v1.PackageRevisionRuntimeStatus{
+							DeploymentRef:     &v1.RuntimeObjectReference{},
+							ServiceAccountRef: &v1.RuntimeObjectReference{},
+						},
+					},
 				},
 				err: errors.Errorf(errFmtUnavailableProviderDeployment, errBoom.Error()),
 			},
@@ -465,6 +496,12 @@ func TestProviderPostHook(t *testing.T) {
 							DesiredState: v1.PackageRevisionActive,
 						},
 					},
+					Status: v1.PackageRevisionStatus{
+						Runtime: &v1.PackageRevisionRuntimeStatus{
+							DeploymentRef:     &v1.RuntimeObjectReference{},
+							ServiceAccountRef: &v1.RuntimeObjectReference{},
+						},
+					},
 				},
 			},
 		},
@@ -515,6 +552,12 @@ func TestProviderPostHook(t *testing.T) {
 							DesiredState: v1.PackageRevisionActive,
 						},
 					},
+					Status: v1.PackageRevisionStatus{
+						Runtime: &v1.PackageRevisionRuntimeStatus{
+							DeploymentRef:     &v1.RuntimeObjectReference{},
+							ServiceAccountRef: &v1.RuntimeObjectReference{},
+						},
+					},
 				},
 			},
 		},
@@ -592,6 +635,12 @@ func TestProviderPostHook(t *testing.T) {
 							DesiredState: v1.PackageRevisionActive,
 						},
 					},
+					Status: v1.PackageRevisionStatus{
+						Runtime: &v1.PackageRevisionRuntimeStatus{
+							DeploymentRef:     &v1.RuntimeObjectReference{},
+							ServiceAccountRef: &v1.RuntimeObjectReference{Name: "external-sa"},
+						},
+					},
 				},
 			},
 		},
@@ -617,6 +666,7 @@ func TestProviderDeactivateHook(t *testing.T) {
 		client    client.Client
 		rev       v1.PackageRevisionWithRuntime
 		manifests ManifestBuilder
+		opts      []ProviderHooksOption
 	}
 
 	type want struct {
@@ -717,11 +767,160 @@ func TestProviderDeactivateHook(t *testing.T) {
 				},
 			},
 		},
+		"DrainWaitsForActiveRevision": {
+			reason: "Should return a draining error and leave the deployment running while the active revision is not yet healthy.",
+			args: args{
+				rev: &v1.ProviderRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "old",
+						Labels: map[string]string{v1.LabelParentPackage: "provider"},
+					},
+				},
+				opts: []ProviderHooksOption{ProviderHooksWithDrainPeriod(time.Minute)},
+				manifests: &MockManifestBuilder{
+					ServiceAccountFn: func(_ ...ServiceAccountOverride) *corev1.ServiceAccount {
+						return &corev1.ServiceAccount{}
+					},
+					DeploymentFn: func(_ string, _ ...DeploymentOverride) *appsv1.Deployment {
+						return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "old"}}
+					},
+				},
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						l := obj.(*v1.ProviderRevisionList)
+						active := v1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{Name: "new"}}
+						active.SetDesiredState(v1.PackageRevisionActive)
+						active.SetConditions(v1.Healthy())
+						l.Items = []v1.ProviderRevision{active}
+						return nil
+					}),
+					MockUpdate: func(_ context.Context, _ client.Object, _ ...client.UpdateOption) error {
+						return errors.New("deployment should not be scaled down while draining")
+					},
+					MockDelete: func(_ context.Context, _ client.Object, _ ...client.DeleteOption) error {
+						return errors.New("deployment should not be deleted while draining")
+					},
+				},
+			},
+			want: want{
+				rev: &v1.ProviderRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "old",
+						Labels: map[string]string{v1.LabelParentPackage: "provider"},
+					},
+				},
+				err: &drainingError{active: "new", drainPeriod: time.Minute, after: time.Minute},
+			},
+		},
+		"DrainScalesDownDeployment": {
+			reason: "Should scale the deployment to zero rather than delete it once the active revision has been healthy for the drain period.",
+			args: args{
+				rev: &v1.ProviderRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "old",
+						Labels: map[string]string{v1.LabelParentPackage: "provider"},
+					},
+				},
+				opts: []ProviderHooksOption{ProviderHooksWithDrainPeriod(time.Minute)},
+				manifests: &MockManifestBuilder{
+					ServiceAccountFn: func(_ ...ServiceAccountOverride) *corev1.ServiceAccount {
+						return &corev1.ServiceAccount{}
+					},
+					DeploymentFn: func(_ string, _ ...DeploymentOverride) *appsv1.Deployment {
+						return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "old"}}
+					},
+					ServiceFn: func(_ ...ServiceOverride) *corev1.Service {
+						return &corev1.Service{}
+					},
+				},
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						l := obj.(*v1.ProviderRevisionList)
+						active := v1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{Name: "new"}}
+						active.SetDesiredState(v1.PackageRevisionActive)
+						c := v1.Healthy()
+						c.LastTransitionTime = metav1.NewTime(time.Now().Add(-2 * time.Minute))
+						active.SetConditions(c)
+						l.Items = []v1.ProviderRevision{active}
+						return nil
+					}),
+					MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+						d, ok := obj.(*appsv1.Deployment)
+						if !ok || d.Spec.Replicas == nil || *d.Spec.Replicas != 0 {
+							return errors.New("expected deployment to be scaled to zero")
+						}
+						return nil
+					},
+					MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+						if _, ok := obj.(*appsv1.Deployment); ok {
+							return errors.New("deployment should not be deleted when draining")
+						}
+						return nil
+					},
+				},
+			},
+			want: want{
+				rev: &v1.ProviderRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "old",
+						Labels: map[string]string{v1.LabelParentPackage: "provider"},
+					},
+				},
+			},
+		},
+		"DrainedDeployment": {
+			reason: "Should do nothing to a deployment that was already scaled to zero.",
+			args: args{
+				rev: &v1.ProviderRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "old",
+						Labels: map[string]string{v1.LabelParentPackage: "provider"},
+					},
+				},
+				opts: []ProviderHooksOption{ProviderHooksWithDrainPeriod(time.Minute)},
+				manifests: &MockManifestBuilder{
+					ServiceAccountFn: func(_ ...ServiceAccountOverride) *corev1.ServiceAccount {
+						return &corev1.ServiceAccount{}
+					},
+					DeploymentFn: func(_ string, _ ...DeploymentOverride) *appsv1.Deployment {
+						return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "old"}}
+					},
+					ServiceFn: func(_ ...ServiceOverride) *corev1.Service {
+						return &corev1.Service{}
+					},
+				},
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						obj.(*appsv1.Deployment).Spec.Replicas = ptr.To[int32](0)
+						return nil
+					}),
+					MockUpdate: func(_ context.Context, _ client.Object, _ ...client.UpdateOption) error {
+						return errors.New("drained deployment should not be updated")
+					},
+					MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+						if _, ok := obj.(*appsv1.Deployment); ok {
+							return errors.New("deployment should not be deleted when draining")
+						}
+						return nil
+					},
+				},
+			},
+			want: want{
+				rev: &v1.ProviderRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "old",
+						Labels: map[string]string{v1.LabelParentPackage: "provider"},
+					},
+				},
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			h := NewProviderHooks(tc.args.client, xpkg.DefaultRegistry)
+			h := NewProviderHooks(tc.args.client, xpkg.DefaultRegistry, tc.args.opts...)
 			err := h.Deactivate(context.TODO(), tc.args.rev, tc.args.manifests)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
@@ -823,6 +1022,31 @@ func TestGetProviderImage(t *testing.T) {
 				image: "registry.notdefault.io/crossplane/provider-bar:v1.2.3",
 			},
 		},
+		"WithDigestFromMeta": {
+			reason: "Should reference the image by the digest pinned in the provider meta.",
+			args: args{
+				providerMeta: &pkgmetav1.Provider{
+					Spec: pkgmetav1.ProviderSpec{
+						Controller: pkgmetav1.ControllerSpec{
+							Image:       ptr.To("crossplane/provider-bar-controller:v1.2.3"),
+							ImageDigest: ptr.To(testDigest),
+						},
+					},
+				},
+				providerRevision: &v1.ProviderRevision{
+					Spec: v1.ProviderRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package: "crossplane/provider-bar:v1.2.3",
+						},
+					},
+				},
+				defaultRegistry: "registry.default.io",
+			},
+			want: want{
+				err:   nil,
+				image: "registry.default.io/crossplane/provider-bar-controller@" + testDigest,
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -838,3 +1062,123 @@ func TestGetProviderImage(t *testing.T) {
 		})
 	}
 }
+
+func TestVerifyProviderImageDigest(t *testing.T) {
+	pinned := &pkgmetav1.Provider{
+		Spec: pkgmetav1.ProviderSpec{
+			Controller: pkgmetav1.ControllerSpec{ImageDigest: ptr.To(testDigest)},
+		},
+	}
+	runtimeContainer := func(image string) *appsv1.Deployment {
+		d := &appsv1.Deployment{}
+		d.Spec.Template.Spec.Containers = []corev1.Container{{Name: runtimeContainerName, Image: image}}
+		return d
+	}
+
+	type args struct {
+		providerMeta *pkgmetav1.Provider
+		deployment   *appsv1.Deployment
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"NoDigestPinned": {
+			reason: "Any image should be allowed if the provider meta doesn't pin a digest.",
+			args: args{
+				providerMeta: &pkgmetav1.Provider{},
+				deployment:   runtimeContainer("crossplane/provider-bar:v1.2.3"),
+			},
+		},
+		"DigestMatches": {
+			reason: "An image that references the pinned digest should be allowed.",
+			args: args{
+				providerMeta: pinned,
+				deployment:   runtimeContainer("crossplane/provider-bar@" + testDigest),
+			},
+		},
+		"ReferencedByTag": {
+			reason: "An image that is referenced by tag, for example by a DeploymentRuntimeConfig, should be rejected.",
+			args: args{
+				providerMeta: pinned,
+				deployment:   runtimeContainer("crossplane/provider-bar:v1.2.3"),
+			},
+			want: errors.Errorf(errFmtProviderImageDigest, "crossplane/provider-bar:v1.2.3", testDigest),
+		},
+		"DigestDoesNotMatch": {
+			reason: "An image that references a different digest should be rejected.",
+			args: args{
+				providerMeta: pinned,
+				deployment:   runtimeContainer("crossplane/provider-bar@sha256:" + strings.Repeat("b", 64)),
+			},
+			want: errors.Errorf(errFmtProviderImageDigest, "crossplane/provider-bar@sha256:"+strings.Repeat("b", 64), testDigest),
+		},
+		"RuntimeContainerNotFirst": {
+			reason: "The image of the runtime container should be checked, even if another container is listed first.",
+			args: args{
+				providerMeta: pinned,
+				deployment: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{
+									{Name: "sidecar", Image: "example.org/sidecar:v1.0.0"},
+									{Name: runtimeContainerName, Image: "crossplane/provider-bar@" + testDigest},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"NoRuntimeContainer": {
+			reason: "A deployment without a runtime container should be rejected.",
+			args: args{
+				providerMeta: pinned,
+				deployment: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{Name: "sidecar", Image: "crossplane/provider-bar@" + testDigest}},
+							},
+						},
+					},
+				},
+			},
+			want: errors.Errorf(errFmtNoProviderRuntimeContainer, runtimeContainerName),
+		},
+		"RuntimeConfigPinnedImage": {
+			reason: "A DeploymentRuntimeConfig that lists a sidecar first and overrides the runtime image with the pinned digest should be allowed.",
+			args: args{
+				providerMeta: pinned,
+				deployment: NewRuntimeManifestBuilder(providerRevision, namespace, RuntimeManifestBuilderWithRuntimeConfig(&v1beta1.DeploymentRuntimeConfig{
+					Spec: v1beta1.DeploymentRuntimeConfigSpec{
+						DeploymentTemplate: &v1beta1.DeploymentTemplate{
+							Spec: &appsv1.DeploymentSpec{
+								Template: corev1.PodTemplateSpec{
+									Spec: corev1.PodSpec{
+										Containers: []corev1.Container{
+											{Name: "sidecar", Image: "example.org/sidecar:v1.0.0"},
+											{Name: runtimeContainerName, Image: "crossplane/provider-foo@" + testDigest},
+										},
+									},
+								},
+							},
+						},
+					},
+				})).Deployment(providerRevisionName, providerDeploymentOverrides(pinned, providerRevision, providerImage)...),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := verifyProviderImageDigest(tc.args.providerMeta, tc.args.deployment, "registry.default.io")
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nverifyProviderImageDigest(): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -24,6 +24,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
 
 	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
@@ -167,6 +168,76 @@ func TestRuntimeManifestBuilderDeployment(t *testing.T) {
 				}),
 			},
 		},
+		"ProviderDeploymentWithControllerConfigDNS": {
+			reason: "The DNS policy and config from the controller config should be applied to the deployment",
+			args: args{
+				builder: &RuntimeManifestBuilder{
+					revision:  providerRevision,
+					namespace: namespace,
+					controllerConfig: &v1alpha1.ControllerConfig{
+						Spec: v1alpha1.ControllerConfigSpec{
+							DNSPolicy: ptr.To(corev1.DNSNone),
+							DNSConfig: &corev1.PodDNSConfig{
+								Nameservers: []string{"10.0.0.10"},
+								Searches:    []string{"corp.example.org"},
+							},
+						},
+					},
+				},
+				serviceAccountName: providerRevisionName,
+				overrides:          providerDeploymentOverrides(&pkgmetav1.Provider{ObjectMeta: metav1.ObjectMeta{Name: providerMetaName}}, providerRevision, providerImage),
+			},
+			want: want{
+				want: deploymentProvider(providerName, providerRevisionName, providerImage, DeploymentWithSelectors(map[string]string{
+					"pkg.crossplane.io/provider": providerMetaName,
+					"pkg.crossplane.io/revision": providerRevisionName,
+				}), func(deployment *appsv1.Deployment) {
+					deployment.Spec.Template.Spec.DNSPolicy = corev1.DNSNone
+					deployment.Spec.Template.Spec.DNSConfig = &corev1.PodDNSConfig{
+						Nameservers: []string{"10.0.0.10"},
+						Searches:    []string{"corp.example.org"},
+					}
+				}),
+			},
+		},
+		"ProviderDeploymentWithRuntimeConfigDNS": {
+			reason: "The DNS policy and config from the runtime config's deployment template should be applied to the deployment",
+			args: args{
+				builder: &RuntimeManifestBuilder{
+					revision:  providerRevision,
+					namespace: namespace,
+					runtimeConfig: &v1beta1.DeploymentRuntimeConfig{
+						Spec: v1beta1.DeploymentRuntimeConfigSpec{
+							DeploymentTemplate: &v1beta1.DeploymentTemplate{
+								Spec: &appsv1.DeploymentSpec{
+									Template: corev1.PodTemplateSpec{
+										Spec: corev1.PodSpec{
+											DNSPolicy: corev1.DNSNone,
+											DNSConfig: &corev1.PodDNSConfig{
+												Nameservers: []string{"10.0.0.10"},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				serviceAccountName: providerRevisionName,
+				overrides:          providerDeploymentOverrides(&pkgmetav1.Provider{ObjectMeta: metav1.ObjectMeta{Name: providerMetaName}}, providerRevision, providerImage),
+			},
+			want: want{
+				want: deploymentProvider(providerName, providerRevisionName, providerImage, DeploymentWithSelectors(map[string]string{
+					"pkg.crossplane.io/provider": providerMetaName,
+					"pkg.crossplane.io/revision": providerRevisionName,
+				}), func(deployment *appsv1.Deployment) {
+					deployment.Spec.Template.Spec.DNSPolicy = corev1.DNSNone
+					deployment.Spec.Template.Spec.DNSConfig = &corev1.PodDNSConfig{
+						Nameservers: []string{"10.0.0.10"},
+					}
+				}),
+			},
+		},
 		"ProviderDeploymentWithRuntimeConfig": {
 			reason: "Baseline provided by the runtime config should be applied to the deployment",
 			args: args{
@@ -222,6 +293,70 @@ func TestRuntimeManifestBuilderDeployment(t *testing.T) {
 				}),
 			},
 		},
+		"ProviderDeploymentWithRuntimeConfigAdditionalArgs": {
+			reason: "Additional args from the runtime config should be appended to the runtime container's args, even if a controller config replaces them",
+			args: args{
+				builder: &RuntimeManifestBuilder{
+					revision:  providerRevision,
+					namespace: namespace,
+					runtimeConfig: &v1beta1.DeploymentRuntimeConfig{
+						Spec: v1beta1.DeploymentRuntimeConfigSpec{
+							AdditionalArgs: []string{"--poll-interval=5m", "--max-reconcile-rate=5"},
+						},
+					},
+					controllerConfig: &v1alpha1.ControllerConfig{
+						Spec: v1alpha1.ControllerConfigSpec{
+							Args: []string{"--debug"},
+						},
+					},
+				},
+				serviceAccountName: providerRevisionName,
+				overrides:          providerDeploymentOverrides(&pkgmetav1.Provider{ObjectMeta: metav1.ObjectMeta{Name: providerMetaName}}, providerRevision, providerImage),
+			},
+			want: want{
+				want: deploymentProvider(providerName, providerRevisionName, providerImage, DeploymentWithSelectors(map[string]string{
+					"pkg.crossplane.io/provider": providerMetaName,
+					"pkg.crossplane.io/revision": providerRevisionName,
+				}), func(deployment *appsv1.Deployment) {
+					deployment.Spec.Template.Spec.Containers[0].Args = []string{"--debug", "--poll-interval=5m", "--max-reconcile-rate=5"}
+				}),
+			},
+		},
+		"ProviderDeploymentWithRuntimeConfigKubeconfigSecret": {
+			reason: "The kubeconfig Secret referenced by the runtime config should be mounted into the runtime container, and KUBECONFIG should point to it",
+			args: args{
+				builder: &RuntimeManifestBuilder{
+					revision:  providerRevision,
+					namespace: namespace,
+					runtimeConfig: &v1beta1.DeploymentRuntimeConfig{
+						Spec: v1beta1.DeploymentRuntimeConfigSpec{
+							KubeconfigSecretRef: &v1beta1.SecretKeySelector{Name: "hosted-control-plane", Key: "value"},
+						},
+					},
+				},
+				serviceAccountName: providerRevisionName,
+				overrides:          providerDeploymentOverrides(&pkgmetav1.Provider{ObjectMeta: metav1.ObjectMeta{Name: providerMetaName}}, providerRevision, providerImage),
+			},
+			want: want{
+				want: deploymentProvider(providerName, providerRevisionName, providerImage, DeploymentWithSelectors(map[string]string{
+					"pkg.crossplane.io/provider": providerMetaName,
+					"pkg.crossplane.io/revision": providerRevisionName,
+				}), func(deployment *appsv1.Deployment) {
+					deployment.Spec.Template.Spec.Volumes = append(deployment.Spec.Template.Spec.Volumes, corev1.Volume{
+						Name: "kubeconfig",
+						VolumeSource: corev1.VolumeSource{
+							Secret: &corev1.SecretVolumeSource{
+								SecretName: "hosted-control-plane",
+								Items:      []corev1.KeyToPath{{Key: "value", Path: "kubeconfig"}},
+							},
+						},
+					})
+					c := &deployment.Spec.Template.Spec.Containers[0]
+					c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{Name: "kubeconfig", ReadOnly: true, MountPath: "/kubeconfig"})
+					c.Env = append(c.Env, corev1.EnvVar{Name: "KUBECONFIG", Value: "/kubeconfig/kubeconfig"})
+				}),
+			},
+		},
 		"ProviderDeploymentNoScrapeAnnotation": {
 			reason: "It should be possible to disable default scrape annotations",
 			args: args{
@@ -709,3 +844,112 @@ func (b *MockManifestBuilder) TLSClientSecret() *corev1.Secret {
 func (b *MockManifestBuilder) TLSServerSecret() *corev1.Secret {
 	return b.TLSServerSecretFn()
 }
+
+func TestRuntimeManifestBuilderService(t *testing.T) {
+	owner := []metav1.OwnerReference{{
+		APIVersion:         "pkg.crossplane.io/v1",
+		Kind:               "ProviderRevision",
+		Name:               providerRevisionName,
+		Controller:         ptr.To(true),
+		BlockOwnerDeletion: ptr.To(true),
+	}}
+	webhook := corev1.ServicePort{
+		Protocol:   corev1.ProtocolTCP,
+		Port:       servicePort,
+		TargetPort: intstr.FromInt32(servicePort),
+	}
+
+	type args struct {
+		builder ManifestBuilder
+	}
+	type want struct {
+		svc *corev1.Service
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoRuntimeConfig": {
+			reason: "The Service should be named for the package and expose the webhook port.",
+			args: args{
+				builder: &RuntimeManifestBuilder{
+					revision:  providerRevision,
+					namespace: namespace,
+				},
+			},
+			want: want{
+				svc: &corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            providerName,
+						Namespace:       namespace,
+						OwnerReferences: owner,
+					},
+					Spec: corev1.ServiceSpec{
+						Selector: map[string]string{
+							"pkg.crossplane.io/revision": providerRevisionName,
+							"pkg.crossplane.io/provider": providerName,
+						},
+						Ports: []corev1.ServicePort{webhook},
+					},
+				},
+			},
+		},
+		"RuntimeConfigPorts": {
+			reason: "The Service should expose the ports declared by the runtime config in addition to the webhook port.",
+			args: args{
+				builder: &RuntimeManifestBuilder{
+					revision:  providerRevision,
+					namespace: namespace,
+					runtimeConfig: &v1beta1.DeploymentRuntimeConfig{
+						Spec: v1beta1.DeploymentRuntimeConfigSpec{
+							ServiceTemplate: &v1beta1.ServiceTemplate{
+								Ports: []corev1.ServicePort{
+									{
+										Name:       "plugin",
+										Protocol:   corev1.ProtocolTCP,
+										Port:       50051,
+										TargetPort: intstr.FromInt32(50051),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				svc: &corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            providerName,
+						Namespace:       namespace,
+						OwnerReferences: owner,
+					},
+					Spec: corev1.ServiceSpec{
+						Selector: map[string]string{
+							"pkg.crossplane.io/revision": providerRevisionName,
+							"pkg.crossplane.io/provider": providerName,
+						},
+						Ports: []corev1.ServicePort{
+							{
+								Name:       "plugin",
+								Protocol:   corev1.ProtocolTCP,
+								Port:       50051,
+								TargetPort: intstr.FromInt32(50051),
+							},
+							webhook,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.args.builder.Service()
+			if diff := cmp.Diff(tc.want.svc, got); diff != "" {
+				t.Errorf("\n%s\nService(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
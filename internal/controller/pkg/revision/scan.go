@@ -0,0 +1,203 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+const (
+	errFmtUnsupportedScanProvider = "image config %q uses unsupported scanning provider %q"
+	errFmtNoHarborConfig          = "image config %q has no harbor configuration"
+	errFmtGetScanCredentials      = "cannot get credentials secret %s of image config %q"
+	errFmtScanImage               = "cannot scan %s using image config %q"
+	errFmtURLSourceUnscannable    = "cannot scan %s using image config %q: packages loaded from URLs can't be scanned"
+)
+
+// maxVulnerabilityFindings is the maximum number of vulnerabilities recorded
+// in a package revision's status.
+const maxVulnerabilityFindings = 20
+
+// A ScanResult is the result of scanning a package revision's image.
+type ScanResult struct {
+	// Vulnerabilities found in the image.
+	Vulnerabilities []xpkg.Vulnerability
+
+	// FailOnSeverity is the severity at or above which vulnerabilities block
+	// the revision's activation. None do if it's empty.
+	FailOnSeverity xpkg.Severity
+}
+
+// Blocking returns the vulnerabilities that block the revision's activation.
+func (r *ScanResult) Blocking() []xpkg.Vulnerability {
+	if r.FailOnSeverity == "" {
+		return nil
+	}
+	var blocking []xpkg.Vulnerability
+	for _, v := range r.Vulnerabilities {
+		if v.Severity.AtLeast(r.FailOnSeverity) {
+			blocking = append(blocking, v)
+		}
+	}
+	return blocking
+}
+
+// Summary of the vulnerabilities found, suitable for a revision's status.
+func (r *ScanResult) Summary() *v1.PackageVulnerabilities {
+	now := metav1.Now()
+	s := &v1.PackageVulnerabilities{ScanTime: &now}
+	for _, v := range r.Vulnerabilities {
+		switch v.Severity {
+		case xpkg.SeverityCritical:
+			s.Critical++
+		case xpkg.SeverityHigh:
+			s.High++
+		case xpkg.SeverityMedium:
+			s.Medium++
+		case xpkg.SeverityLow:
+			s.Low++
+		default:
+			s.Unknown++
+		}
+	}
+
+	vulns := make([]xpkg.Vulnerability, len(r.Vulnerabilities))
+	copy(vulns, r.Vulnerabilities)
+	sort.SliceStable(vulns, func(i, j int) bool {
+		return !vulns[j].Severity.AtLeast(vulns[i].Severity)
+	})
+	for i := 0; i < len(vulns) && i < maxVulnerabilityFindings; i++ {
+		s.Findings = append(s.Findings, v1.PackageVulnerability{
+			ID:         vulns[i].ID,
+			Severity:   string(vulns[i].Severity),
+			Package:    vulns[i].Package,
+			Version:    vulns[i].Version,
+			FixVersion: vulns[i].FixVersion,
+		})
+	}
+	return s
+}
+
+// A VulnerabilityScanner scans a package revision's image for
+// vulnerabilities.
+type VulnerabilityScanner interface {
+	// Scan the supplied package revision's image. Returns nil if the image
+	// doesn't need to be scanned.
+	Scan(ctx context.Context, pr v1.PackageRevision) (*ScanResult, error)
+}
+
+// A NopVulnerabilityScanner doesn't scan images.
+type NopVulnerabilityScanner struct{}
+
+// Scan does nothing.
+func (NopVulnerabilityScanner) Scan(_ context.Context, _ v1.PackageRevision) (*ScanResult, error) {
+	return nil, nil
+}
+
+// A HarborScanner scans images using a Harbor registry's vulnerability report.
+type HarborScanner interface {
+	Scan(ctx context.Context, ref name.Reference, api string, creds *xpkg.HarborCredentials) ([]xpkg.Vulnerability, error)
+}
+
+// An ImageConfigScanner scans package images using the ImageConfig with the
+// longest prefix that matches the image.
+type ImageConfigScanner struct {
+	client    client.Client
+	harbor    HarborScanner
+	namespace string
+	registry  string
+}
+
+// NewImageConfigScanner returns a VulnerabilityScanner that scans package
+// images using ImageConfigs. Credentials secrets are read from the supplied
+// namespace.
+func NewImageConfigScanner(c client.Client, h HarborScanner, namespace, registry string) *ImageConfigScanner {
+	return &ImageConfigScanner{client: c, harbor: h, namespace: namespace, registry: registry}
+}
+
+// Scan the supplied package revision's image.
+func (s *ImageConfigScanner) Scan(ctx context.Context, pr v1.PackageRevision) (*ScanResult, error) {
+	image, ref, err := imageName(pr.GetSource(), s.registry)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &v1beta1.ImageConfigList{}
+	if err := s.client.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, errListImageConfigs)
+	}
+	ic := matchImageConfig(l.Items, image, func(ic *v1beta1.ImageConfig) bool { return ic.Spec.Scanning != nil })
+	if ic == nil {
+		return nil, nil
+	}
+
+	// Harbor scans images in its registry. A package loaded from a URL has
+	// no registry, so it can't be scanned.
+	if ref == nil {
+		return nil, errors.Errorf(errFmtURLSourceUnscannable, image, ic.GetName())
+	}
+
+	sc := ic.Spec.Scanning
+	if sc.Provider != v1beta1.ImageScanningProviderHarbor {
+		return nil, errors.Errorf(errFmtUnsupportedScanProvider, ic.GetName(), sc.Provider)
+	}
+	if sc.Harbor == nil {
+		return nil, errors.Errorf(errFmtNoHarborConfig, ic.GetName())
+	}
+
+	api := ""
+	if sc.Harbor.URL != nil {
+		api = *sc.Harbor.URL
+	}
+
+	var creds *xpkg.HarborCredentials
+	if ref := sc.Harbor.CredentialsSecretRef; ref != nil {
+		sec := &corev1.Secret{}
+		nn := types.NamespacedName{Namespace: s.namespace, Name: ref.Name}
+		if err := s.client.Get(ctx, nn, sec); err != nil {
+			return nil, errors.Wrapf(err, errFmtGetScanCredentials, nn, ic.GetName())
+		}
+		creds = &xpkg.HarborCredentials{
+			Username: string(sec.Data[corev1.BasicAuthUsernameKey]),
+			Password: string(sec.Data[corev1.BasicAuthPasswordKey]),
+		}
+	}
+
+	vulns, err := s.harbor.Scan(ctx, ref, api, creds)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtScanImage, ref.Name(), ic.GetName())
+	}
+
+	res := &ScanResult{Vulnerabilities: vulns}
+	if sc.FailOnSeverity != nil {
+		res.FailOnSeverity = xpkg.Severity(*sc.FailOnSeverity)
+	}
+	return res, nil
+}
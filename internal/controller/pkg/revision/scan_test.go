@@ -0,0 +1,157 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+var _ VulnerabilityScanner = &ImageConfigScanner{}
+
+type MockHarborScanner func(ref name.Reference, api string, creds *xpkg.HarborCredentials) ([]xpkg.Vulnerability, error)
+
+func (fn MockHarborScanner) Scan(_ context.Context, ref name.Reference, api string, creds *xpkg.HarborCredentials) ([]xpkg.Vulnerability, error) {
+	return fn(ref, api, creds)
+}
+
+func TestImageConfigScannerScan(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	ic := func(name, prefix string) v1beta1.ImageConfig {
+		return v1beta1.ImageConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: v1beta1.ImageConfigSpec{
+				MatchImages: []v1beta1.ImageMatch{{Type: v1beta1.Prefix, Prefix: prefix}},
+				Scanning: &v1beta1.ImageScanning{
+					Provider: v1beta1.ImageScanningProviderHarbor,
+					Harbor: &v1beta1.HarborScanningConfig{
+						URL:                  ptr.To("https://harbor.example.org"),
+						CredentialsSecretRef: &corev1.LocalObjectReference{Name: "harbor"},
+					},
+					FailOnSeverity: ptr.To(v1beta1.VulnerabilitySeverityHigh),
+				},
+			},
+		}
+	}
+
+	list := func(ics ...v1beta1.ImageConfig) func(context.Context, client.ObjectList, ...client.ListOption) error {
+		return func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+			obj.(*v1beta1.ImageConfigList).Items = ics
+			return nil
+		}
+	}
+	get := test.NewMockGetFn(nil, func(obj client.Object) error {
+		obj.(*corev1.Secret).Data = map[string][]byte{
+			corev1.BasicAuthUsernameKey: []byte("robot"),
+			corev1.BasicAuthPasswordKey: []byte("secret"),
+		}
+		return nil
+	})
+
+	vulns := []xpkg.Vulnerability{{ID: "CVE-2024-0001", Severity: xpkg.SeverityCritical}}
+
+	type want struct {
+		res *ScanResult
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Client
+		harbor HarborScanner
+		source string
+		want   want
+	}{
+		"NoMatchingImageConfig": {
+			reason: "A package that doesn't match any ImageConfig doesn't need to be scanned.",
+			client: &test.MockClient{MockList: list(ic("other", "example.org/"))},
+			want:   want{},
+		},
+		"Scanned": {
+			reason: "A package should be scanned using the Harbor API and credentials configured by the matching ImageConfig.",
+			client: &test.MockClient{
+				MockList: list(ic("crossplane", "xpkg.upbound.io/crossplane/")),
+				MockGet:  get,
+			},
+			harbor: MockHarborScanner(func(_ name.Reference, api string, creds *xpkg.HarborCredentials) ([]xpkg.Vulnerability, error) {
+				if api != "https://harbor.example.org" || creds == nil || creds.Username != "robot" || creds.Password != "secret" {
+					return nil, errBoom
+				}
+				return vulns, nil
+			}),
+			want: want{res: &ScanResult{Vulnerabilities: vulns, FailOnSeverity: xpkg.SeverityHigh}},
+		},
+		"GetCredentialsError": {
+			reason: "We should return an error if we can't get the referenced credentials secret.",
+			client: &test.MockClient{
+				MockList: list(ic("crossplane", "xpkg.upbound.io/crossplane/")),
+				MockGet:  test.NewMockGetFn(errBoom),
+			},
+			want: want{err: errors.Wrapf(errBoom, errFmtGetScanCredentials, "crossplane-system/harbor", "crossplane")},
+		},
+		"LongestPrefixScanFailed": {
+			reason: "We should return an error naming the ImageConfig with the longest matching prefix if scanning fails.",
+			client: &test.MockClient{
+				MockList: list(ic("all", "xpkg.upbound.io/"), ic("crossplane", "xpkg.upbound.io/crossplane/")),
+				MockGet:  get,
+			},
+			harbor: MockHarborScanner(func(_ name.Reference, _ string, _ *xpkg.HarborCredentials) ([]xpkg.Vulnerability, error) {
+				return nil, errBoom
+			}),
+			want: want{err: errors.Wrapf(errBoom, errFmtScanImage, "xpkg.upbound.io/crossplane/configuration-nop:v0.1.0", "crossplane")},
+		},
+		"URLSourceUnscannable": {
+			reason: "We should return an error if an ImageConfig that matches a package's URL requires it to be scanned.",
+			client: &test.MockClient{MockList: list(ic("example", "https://example.org/"))},
+			source: "https://example.org/configuration-nop.xpkg",
+			want:   want{err: errors.Errorf(errFmtURLSourceUnscannable, "https://example.org/configuration-nop.xpkg", "example")},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			source := "xpkg.upbound.io/crossplane/configuration-nop:v0.1.0"
+			if tc.source != "" {
+				source = tc.source
+			}
+			pr := &v1.ConfigurationRevision{Spec: v1.ConfigurationRevisionSpec{PackageRevisionSpec: v1.PackageRevisionSpec{Package: source}}}
+			s := NewImageConfigScanner(tc.client, tc.harbor, "crossplane-system", "xpkg.upbound.io")
+			res, err := s.Scan(context.Background(), pr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nScan(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.res, res); diff != "" {
+				t.Errorf("\n%s\nScan(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
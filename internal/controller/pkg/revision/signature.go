@@ -0,0 +1,245 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"crypto/x509"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+const (
+	errListImageConfigs         = "cannot list image configs"
+	errFmtGetVerificationKey    = "cannot get secret %s for authority %q"
+	errFmtNoVerificationKey     = "secret %s has no key %q for authority %q"
+	errFmtParseVerificationKey  = "cannot parse key for authority %q"
+	errFmtUnsupportedProvider   = "image config %q uses unsupported verification provider %q"
+	errFmtNoCosignConfig        = "image config %q has no cosign configuration"
+	errFmtVerifyImage           = "cannot verify signature of %s using image config %q"
+	errFmtURLSourceUnverifiable = "cannot verify signature of %s using image config %q: packages loaded from URLs have no signatures"
+	errFmtNoVerificationConfig  = "cannot verify signature of %s: no image config configures its verification, and signatures are required"
+	errFmtUnpinned              = "cannot verify signature of %s: its source is not pinned to a digest"
+)
+
+// A SignatureVerifier verifies the signature of a package revision's image.
+type SignatureVerifier interface {
+	// Verify the signature of the supplied package revision's image. Returns
+	// false if the image doesn't need to be verified.
+	Verify(ctx context.Context, pr v1.PackageRevision) (bool, error)
+}
+
+// A NopSignatureVerifier doesn't verify signatures.
+type NopSignatureVerifier struct{}
+
+// Verify does nothing.
+func (NopSignatureVerifier) Verify(_ context.Context, _ v1.PackageRevision) (bool, error) {
+	return false, nil
+}
+
+// A CosignVerifier verifies cosign signatures.
+type CosignVerifier interface {
+	Verify(ctx context.Context, ref name.Reference, authorities []xpkg.CosignAuthority, secrets ...string) error
+}
+
+// An ImageConfigVerifier verifies package images using the ImageConfig with
+// the longest prefix that matches the image.
+type ImageConfigVerifier struct {
+	client    client.Client
+	cosign    CosignVerifier
+	namespace string
+	registry  string
+	require   bool
+}
+
+// An ImageConfigVerifierOption configures an ImageConfigVerifier.
+type ImageConfigVerifierOption func(v *ImageConfigVerifier)
+
+// WithRequireVerification configures an ImageConfigVerifier to return an
+// error for images that no ImageConfig configures verification for, rather
+// than leaving them unverified.
+func WithRequireVerification(require bool) ImageConfigVerifierOption {
+	return func(v *ImageConfigVerifier) {
+		v.require = require
+	}
+}
+
+// NewImageConfigVerifier returns a SignatureVerifier that verifies package
+// images using ImageConfigs. Verification secrets are read from the supplied
+// namespace.
+func NewImageConfigVerifier(c client.Client, v CosignVerifier, namespace, registry string, opts ...ImageConfigVerifierOption) *ImageConfigVerifier {
+	iv := &ImageConfigVerifier{client: c, cosign: v, namespace: namespace, registry: registry}
+	for _, fn := range opts {
+		fn(iv)
+	}
+	return iv
+}
+
+// Verify the signature of the supplied package revision's image.
+func (v *ImageConfigVerifier) Verify(ctx context.Context, pr v1.PackageRevision) (bool, error) {
+	image, ref, err := imageName(pr.GetSource(), v.registry)
+	if err != nil {
+		return false, err
+	}
+
+	l := &v1beta1.ImageConfigList{}
+	if err := v.client.List(ctx, l); err != nil {
+		return false, errors.Wrap(err, errListImageConfigs)
+	}
+	ic := matchImageConfig(l.Items, image, func(ic *v1beta1.ImageConfig) bool { return ic.Spec.Verification != nil })
+	if ic == nil && v.require {
+		return false, errors.Errorf(errFmtNoVerificationConfig, image)
+	}
+	if ic == nil {
+		return false, nil
+	}
+
+	// Signatures are stored alongside an image in its registry. A package
+	// loaded from a URL has no registry, so it can't be verified.
+	if ref == nil {
+		return false, errors.Errorf(errFmtURLSourceUnverifiable, image, ic.GetName())
+	}
+
+	vc := ic.Spec.Verification
+	if vc.Provider != v1beta1.ImageVerificationProviderCosign {
+		return false, errors.Errorf(errFmtUnsupportedProvider, ic.GetName(), vc.Provider)
+	}
+	if vc.Cosign == nil {
+		return false, errors.Errorf(errFmtNoCosignConfig, ic.GetName())
+	}
+
+	// A tag may be moved after it's verified, and an image source may serve
+	// different content than the package's registry. A revision's source is
+	// pinned to the digest of the image it will load, and the image backend
+	// refuses to load any other image, so verifying the pinned digest
+	// verifies the content that is actually loaded.
+	if _, ok := ref.(name.Digest); !ok {
+		return false, errors.Errorf(errFmtUnpinned, ref.Name())
+	}
+
+	authorities := make([]xpkg.CosignAuthority, 0, len(vc.Cosign.Authorities))
+	for _, a := range vc.Cosign.Authorities {
+		ca, err := v.authority(ctx, a)
+		if err != nil {
+			return false, err
+		}
+		authorities = append(authorities, ca)
+	}
+
+	if err := v.cosign.Verify(ctx, ref, authorities, v1.RefNames(pr.GetPackagePullSecrets())...); err != nil {
+		return false, errors.Wrapf(err, errFmtVerifyImage, ref.Name(), ic.GetName())
+	}
+	return true, nil
+}
+
+func (v *ImageConfigVerifier) authority(ctx context.Context, a v1beta1.CosignAuthority) (xpkg.CosignAuthority, error) {
+	ca := xpkg.CosignAuthority{Name: a.Name}
+
+	if a.Key != nil {
+		data, err := v.secretKey(ctx, a.Name, a.Key.SecretRef)
+		if err != nil {
+			return ca, err
+		}
+		ca.PublicKey, err = xpkg.ParsePublicKey(data)
+		return ca, errors.Wrapf(err, errFmtParseVerificationKey, a.Name)
+	}
+
+	if a.Keyless != nil {
+		data, err := v.secretKey(ctx, a.Name, a.Keyless.CertificateAuthoritySecretRef)
+		if err != nil {
+			return ca, err
+		}
+		certs, err := xpkg.ParseCertificates(data)
+		if err != nil {
+			return ca, errors.Wrapf(err, errFmtParseVerificationKey, a.Name)
+		}
+		ca.Roots = x509.NewCertPool()
+		for _, c := range certs {
+			ca.Roots.AddCert(c)
+		}
+		for _, id := range a.Keyless.Identities {
+			ca.Identities = append(ca.Identities, xpkg.CosignIdentity{Issuer: id.Issuer, Subject: id.Subject})
+		}
+		data, err = v.secretKey(ctx, a.Name, a.Keyless.TransparencyLogSecretRef)
+		if err != nil {
+			return ca, err
+		}
+		ca.TransparencyLogKey, err = xpkg.ParsePublicKey(data)
+		return ca, errors.Wrapf(err, errFmtParseVerificationKey, a.Name)
+	}
+
+	return ca, nil
+}
+
+func (v *ImageConfigVerifier) secretKey(ctx context.Context, authority string, ref v1beta1.SecretKeySelector) ([]byte, error) {
+	s := &corev1.Secret{}
+	nn := types.NamespacedName{Namespace: v.namespace, Name: ref.Name}
+	if err := v.client.Get(ctx, nn, s); err != nil {
+		return nil, errors.Wrapf(err, errFmtGetVerificationKey, nn, authority)
+	}
+	data, ok := s.Data[ref.Key]
+	if !ok {
+		return nil, errors.Errorf(errFmtNoVerificationKey, nn, ref.Key, authority)
+	}
+	return data, nil
+}
+
+// imageName returns the name ImageConfigs match the supplied package source
+// by, and its reference. ImageConfigs match a package whose source is a URL,
+// e.g. https://example.org/provider.xpkg, by its URL. Such a package has no
+// reference.
+func imageName(source, registry string) (string, name.Reference, error) {
+	if xpkg.IsURLSource(source) {
+		return source, nil, nil
+	}
+	ref, err := name.ParseReference(source, name.WithDefaultRegistry(registry))
+	if err != nil {
+		return "", nil, errors.Wrap(err, errBadReference)
+	}
+	return ref.Name(), ref, nil
+}
+
+// matchImageConfig returns the ImageConfig for which configured returns true
+// that has the longest prefix matching the supplied image, or nil if none
+// match.
+func matchImageConfig(ics []v1beta1.ImageConfig, image string, configured func(ic *v1beta1.ImageConfig) bool) *v1beta1.ImageConfig {
+	var match *v1beta1.ImageConfig
+	longest := -1
+	for i := range ics {
+		if !configured(&ics[i]) {
+			continue
+		}
+		for _, m := range ics[i].Spec.MatchImages {
+			if !strings.HasPrefix(image, m.Prefix) || len(m.Prefix) <= longest {
+				continue
+			}
+			match = &ics[i]
+			longest = len(m.Prefix)
+		}
+	}
+	return match
+}
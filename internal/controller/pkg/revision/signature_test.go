@@ -0,0 +1,211 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+var _ SignatureVerifier = &ImageConfigVerifier{}
+
+type MockCosignVerifier func(ref name.Reference, authorities []xpkg.CosignAuthority) error
+
+func (fn MockCosignVerifier) Verify(_ context.Context, ref name.Reference, authorities []xpkg.CosignAuthority, _ ...string) error {
+	return fn(ref, authorities)
+}
+
+func TestImageConfigVerifierVerify(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey(...): %v", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&k.PublicKey)
+	if err != nil {
+		t.Fatalf("x509.MarshalPKIXPublicKey(...): %v", err)
+	}
+	pub := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	ic := func(name, prefix string) v1beta1.ImageConfig {
+		return v1beta1.ImageConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: v1beta1.ImageConfigSpec{
+				MatchImages: []v1beta1.ImageMatch{{Type: v1beta1.Prefix, Prefix: prefix}},
+				Verification: &v1beta1.ImageVerification{
+					Provider: v1beta1.ImageVerificationProviderCosign,
+					Cosign: &v1beta1.CosignVerificationConfig{
+						Authorities: []v1beta1.CosignAuthority{{
+							Name: "key",
+							Key:  &v1beta1.KeyRef{SecretRef: v1beta1.SecretKeySelector{Name: "cosign", Key: "cosign.pub"}},
+						}},
+					},
+				},
+			},
+		}
+	}
+
+	list := func(ics ...v1beta1.ImageConfig) func(context.Context, client.ObjectList, ...client.ListOption) error {
+		return func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+			obj.(*v1beta1.ImageConfigList).Items = ics
+			return nil
+		}
+	}
+	get := func(data map[string][]byte) func(context.Context, client.ObjectKey, client.Object) error {
+		return test.NewMockGetFn(nil, func(obj client.Object) error {
+			obj.(*corev1.Secret).Data = data
+			return nil
+		})
+	}
+
+	type want struct {
+		verified bool
+		err      error
+	}
+
+	nop := "xpkg.upbound.io/crossplane/configuration-nop:v0.1.0"
+	pinned := nop + "@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d2d7d1c5d1d3abf"
+
+	cases := map[string]struct {
+		reason string
+		client client.Client
+		cosign CosignVerifier
+		opts   []ImageConfigVerifierOption
+		source string
+		is     *v1.ImageSource
+		want   want
+	}{
+		"NoMatchingImageConfig": {
+			reason: "A package that doesn't match any ImageConfig doesn't need to be verified.",
+			client: &test.MockClient{MockList: list(ic("other", "example.org/"))},
+			want:   want{verified: false},
+		},
+		"RequiredNoMatchingImageConfig": {
+			reason: "We should return an error if signatures are required, and no ImageConfig configures verification of a package.",
+			client: &test.MockClient{MockList: list(ic("other", "example.org/"))},
+			opts:   []ImageConfigVerifierOption{WithRequireVerification(true)},
+			want:   want{err: errors.Errorf(errFmtNoVerificationConfig, "xpkg.upbound.io/crossplane/configuration-nop@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d2d7d1c5d1d3abf")},
+		},
+		"Verified": {
+			reason: "A package should be verified using the public key referenced by the matching ImageConfig.",
+			client: &test.MockClient{
+				MockList: list(ic("crossplane", "xpkg.upbound.io/crossplane/")),
+				MockGet:  get(map[string][]byte{"cosign.pub": pub}),
+			},
+			cosign: MockCosignVerifier(func(_ name.Reference, authorities []xpkg.CosignAuthority) error {
+				if len(authorities) != 1 || !k.PublicKey.Equal(authorities[0].PublicKey) {
+					return errBoom
+				}
+				return nil
+			}),
+			want: want{verified: true},
+		},
+		"NoSecretKey": {
+			reason: "We should return an error if the referenced secret doesn't contain the key.",
+			client: &test.MockClient{
+				MockList: list(ic("crossplane", "xpkg.upbound.io/crossplane/")),
+				MockGet:  get(map[string][]byte{}),
+			},
+			want: want{err: errors.Errorf(errFmtNoVerificationKey, "crossplane-system/cosign", "cosign.pub", "key")},
+		},
+		"LongestPrefixVerificationFailed": {
+			reason: "We should return an error naming the ImageConfig with the longest matching prefix if verification fails.",
+			client: &test.MockClient{
+				MockList: list(ic("all", "xpkg.upbound.io/"), ic("crossplane", "xpkg.upbound.io/crossplane/")),
+				MockGet:  get(map[string][]byte{"cosign.pub": pub}),
+			},
+			cosign: MockCosignVerifier(func(_ name.Reference, _ []xpkg.CosignAuthority) error {
+				return errBoom
+			}),
+			want: want{err: errors.Wrapf(errBoom, errFmtVerifyImage, "xpkg.upbound.io/crossplane/configuration-nop@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d2d7d1c5d1d3abf", "crossplane")},
+		},
+		"URLSourceUnverifiable": {
+			reason: "We should return an error if an ImageConfig that matches a package's URL requires it to be verified.",
+			client: &test.MockClient{MockList: list(ic("example", "https://example.org/"))},
+			source: "https://example.org/configuration-nop.xpkg",
+			want:   want{err: errors.Errorf(errFmtURLSourceUnverifiable, "https://example.org/configuration-nop.xpkg", "example")},
+		},
+		"URLSourceNoMatchingImageConfig": {
+			reason: "A package whose URL doesn't match any ImageConfig doesn't need to be verified.",
+			client: &test.MockClient{MockList: list(ic("crossplane", "xpkg.upbound.io/crossplane/"))},
+			source: "https://example.org/configuration-nop.xpkg",
+			want:   want{verified: false},
+		},
+		"Unpinned": {
+			reason: "We should refuse to verify a package unless its source is pinned to a digest, which may not be moved once verified.",
+			client: &test.MockClient{
+				MockList: list(ic("crossplane", "xpkg.upbound.io/crossplane/")),
+				MockGet:  get(map[string][]byte{"cosign.pub": pub}),
+			},
+			source: nop,
+			want:   want{err: errors.Errorf(errFmtUnpinned, "xpkg.upbound.io/crossplane/configuration-nop:v0.1.0")},
+		},
+		"ImageSourcePinned": {
+			reason: "A package loaded from an image source should be verified using the digest its source is pinned to.",
+			client: &test.MockClient{
+				MockList: list(ic("crossplane", "xpkg.upbound.io/crossplane/")),
+				MockGet:  get(map[string][]byte{"cosign.pub": pub}),
+			},
+			cosign: MockCosignVerifier(func(ref name.Reference, _ []xpkg.CosignAuthority) error {
+				if _, ok := ref.(name.Digest); !ok {
+					return errBoom
+				}
+				return nil
+			}),
+			is:   &v1.ImageSource{Path: ptr.To("configuration-nop.xpkg")},
+			want: want{verified: true},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			source := pinned
+			if tc.source != "" {
+				source = tc.source
+			}
+			pr := &v1.ConfigurationRevision{Spec: v1.ConfigurationRevisionSpec{PackageRevisionSpec: v1.PackageRevisionSpec{Package: source, ImageSource: tc.is}}}
+			v := NewImageConfigVerifier(tc.client, tc.cosign, "crossplane-system", "xpkg.upbound.io", tc.opts...)
+			verified, err := v.Verify(context.Background(), pr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nVerify(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.verified, verified); diff != "" {
+				t.Errorf("\n%s\nVerify(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"strings"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+const (
+	errFmtRenderProviderConfig = "cannot render credentials of ProviderConfig %q"
+	errFmtParseTemplate        = "cannot parse template %q"
+	errFmtExecuteTemplate      = "cannot execute template %q"
+)
+
+// renderProviderConfigs renders the credentials of the supplied ProviderConfigs
+// in place. Each string field under a ProviderConfig's spec.credentials is
+// rendered as a Go template, with the supplied values available as .Values.
+// Templates that refer to a value that isn't supplied produce an error. Other
+// objects are left unchanged.
+func renderProviderConfigs(objs []runtime.Object, values map[string]string) error {
+	data := map[string]any{"Values": values}
+	for _, o := range objs {
+		u, ok := o.(*unstructured.Unstructured)
+		if !ok || u.GetKind() != xpkg.ProviderConfigKind {
+			continue
+		}
+		creds, found, err := unstructured.NestedFieldNoCopy(u.Object, "spec", "credentials")
+		if err != nil || !found {
+			continue
+		}
+		rendered, err := render(creds, data)
+		if err != nil {
+			return errors.Wrapf(err, errFmtRenderProviderConfig, u.GetName())
+		}
+		if err := unstructured.SetNestedField(u.Object, rendered, "spec", "credentials"); err != nil {
+			return errors.Wrapf(err, errFmtRenderProviderConfig, u.GetName())
+		}
+	}
+	return nil
+}
+
+// render every string within the supplied JSON value as a template.
+func render(v any, data map[string]any) (any, error) {
+	switch t := v.(type) {
+	case string:
+		if !strings.Contains(t, "{{") {
+			return t, nil
+		}
+		tmpl, err := template.New("").Option("missingkey=error").Parse(t)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtParseTemplate, t)
+		}
+		out := &strings.Builder{}
+		if err := tmpl.Execute(out, data); err != nil {
+			return nil, errors.Wrapf(err, errFmtExecuteTemplate, t)
+		}
+		return out.String(), nil
+	case map[string]any:
+		for k, e := range t {
+			r, err := render(e, data)
+			if err != nil {
+				return nil, err
+			}
+			t[k] = r
+		}
+		return t, nil
+	case []any:
+		for i, e := range t {
+			r, err := render(e, data)
+			if err != nil {
+				return nil, err
+			}
+			t[i] = r
+		}
+		return t, nil
+	default:
+		return v, nil
+	}
+}
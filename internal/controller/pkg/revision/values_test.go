@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestRenderProviderConfigs(t *testing.T) {
+	pc := func(kind string, creds map[string]any) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "nop.crossplane.io/v1alpha1",
+			"kind":       kind,
+			"metadata":   map[string]any{"name": "default"},
+			"spec":       map[string]any{"credentials": creds},
+		}}
+	}
+	templated := func() map[string]any {
+		return map[string]any{
+			"source": "Secret",
+			"secretRef": map[string]any{
+				"namespace": "{{ .Values.namespace }}",
+				"name":      "{{ .Values.name }}",
+				"key":       "credentials",
+			},
+		}
+	}
+
+	type args struct {
+		objs   []runtime.Object
+		values map[string]string
+	}
+	type want struct {
+		objs []runtime.Object
+		err  bool
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"RenderCredentials": {
+			reason: "We should render the templated credentials of a ProviderConfig.",
+			args: args{
+				objs:   []runtime.Object{pc("ProviderConfig", templated())},
+				values: map[string]string{"namespace": "crossplane-system", "name": "nop-creds"},
+			},
+			want: want{
+				objs: []runtime.Object{pc("ProviderConfig", map[string]any{
+					"source": "Secret",
+					"secretRef": map[string]any{
+						"namespace": "crossplane-system",
+						"name":      "nop-creds",
+						"key":       "credentials",
+					},
+				})},
+			},
+		},
+		"MissingValue": {
+			reason: "We should return an error if a template refers to a value that isn't supplied.",
+			args: args{
+				objs:   []runtime.Object{pc("ProviderConfig", templated())},
+				values: map[string]string{"namespace": "crossplane-system"},
+			},
+			want: want{
+				err: true,
+			},
+		},
+		"OtherObjects": {
+			reason: "We should not render objects that aren't ProviderConfigs.",
+			args: args{
+				objs: []runtime.Object{&v1.Composition{}, pc("NopResource", templated())},
+			},
+			want: want{
+				objs: []runtime.Object{&v1.Composition{}, pc("NopResource", templated())},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := renderProviderConfigs(tc.args.objs, tc.args.values)
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Errorf("\n%s\nrenderProviderConfigs(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if tc.want.err {
+				return
+			}
+			if diff := cmp.Diff(tc.want.objs, tc.args.objs, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nrenderProviderConfigs(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,549 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package upgrade implements the Crossplane PackageUpgrade controller.
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1alpha1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/internal/controller/pkg/controller"
+	"github.com/crossplane/crossplane/internal/dag"
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+const (
+	reconcileTimeout = 1 * time.Minute
+
+	lockName = "lock"
+
+	// upgradeWait is how long to wait before checking whether a staged
+	// revision exists, or an activated revision is healthy.
+	upgradeWait = 10 * time.Second
+
+	// defaultHealthTimeout is how long to wait for a revision to be staged
+	// or become healthy if the PackageUpgrade doesn't specify a timeout.
+	defaultHealthTimeout = 5 * time.Minute
+)
+
+const (
+	errGetUpgrade      = "cannot get package upgrade"
+	errUpdateStatus    = "cannot update package upgrade status"
+	errGetPackage      = "cannot get package"
+	errUpdatePackage   = "cannot update package"
+	errGetRevision     = "cannot get package revision"
+	errUpdateRevision  = "cannot update package revision"
+	errGetLock         = "cannot get package lock"
+	errInitDAG         = "cannot initialize dependency graph from the package lock"
+	errParseSource     = "cannot parse package source"
+	errDependencyCycle = "cannot order packages that depend on each other"
+	errFmtInvalidKind  = "invalid package kind %q"
+	errFmtStageTimeout = "%s %q did not stage a new revision within %s"
+	errFmtUnhealthy    = "%s %q revision %q did not become healthy within %s"
+)
+
+// Event reasons.
+const (
+	reasonStage    event.Reason = "StagePackages"
+	reasonActivate event.Reason = "ActivatePackage"
+	reasonRollback event.Reason = "RollbackPackages"
+)
+
+// ReconcilerOption is used to configure the Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithLogger specifies how the Reconciler should log messages.
+func WithLogger(log logging.Logger) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.log = log
+	}
+}
+
+// WithRecorder specifies how the Reconciler should record events.
+func WithRecorder(er event.Recorder) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.record = er
+	}
+}
+
+// Reconciler reconciles PackageUpgrades.
+type Reconciler struct {
+	client client.Client
+	log    logging.Logger
+	record event.Recorder
+}
+
+// Setup adds a controller that reconciles PackageUpgrades.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := "packages/" + strings.ToLower(v1alpha1.PackageUpgradeGroupKind)
+
+	r := NewReconciler(mgr,
+		WithLogger(o.Logger.WithValues("controller", name)),
+		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+	)
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha1.PackageUpgrade{}).
+		WithOptions(o.ForControllerRuntime()).
+		Complete(ratelimiter.NewReconciler(name, errors.WithSilentRequeueOnConflict(r), o.GlobalRateLimiter))
+}
+
+// NewReconciler creates a new PackageUpgrade reconciler.
+func NewReconciler(mgr manager.Manager, opts ...ReconcilerOption) *Reconciler {
+	r := &Reconciler{
+		client: mgr.GetClient(),
+		log:    logging.NewNopLogger(),
+		record: event.NewNopRecorder(),
+	}
+
+	for _, f := range opts {
+		f(r)
+	}
+
+	return r
+}
+
+// Reconcile a PackageUpgrade. An upgrade moves through its stages in order,
+// recording the current stage as the reason of its Upgraded condition: it
+// records each package's current state, stages new revisions of all packages,
+// then activates them one at a time. If any revision fails to stage
+// or become healthy every package is rolled back to its previous revision.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(ctx, reconcileTimeout)
+	defer cancel()
+
+	u := &v1alpha1.PackageUpgrade{}
+	if err := r.client.Get(ctx, req.NamespacedName, u); err != nil {
+		log.Debug(errGetUpgrade, "error", err)
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetUpgrade)
+	}
+
+	if meta.WasDeleted(u) {
+		return reconcile.Result{}, nil
+	}
+
+	var (
+		result reconcile.Result
+		err    error
+	)
+	stage := u.GetCondition(v1alpha1.TypeUpgraded).Reason
+	switch stage {
+	case v1alpha1.ReasonStaging:
+		result, err = r.stage(ctx, u)
+	case v1alpha1.ReasonActivating:
+		result, err = r.activate(ctx, u)
+	case v1alpha1.ReasonRollingBack:
+		result, err = r.rollback(ctx, u)
+	case v1alpha1.ReasonSucceeded, v1alpha1.ReasonRolledBack:
+		// The upgrade is done.
+		return reconcile.Result{}, nil
+	default:
+		result, err = r.start(ctx, u)
+	}
+	if err != nil {
+		if kerrors.IsConflict(err) {
+			return reconcile.Result{Requeue: true}, nil
+		}
+		log.Debug("Cannot upgrade packages", "stage", stage, "error", err)
+		u.SetConditions(xpv1.ReconcileError(err))
+		_ = r.client.Status().Update(ctx, u)
+		return reconcile.Result{}, err
+	}
+
+	u.SetConditions(xpv1.ReconcileSuccess())
+	return result, errors.Wrap(r.client.Status().Update(ctx, u), errUpdateStatus)
+}
+
+// start records the current state of each package, so it can be rolled back.
+func (r *Reconciler) start(ctx context.Context, u *v1alpha1.PackageUpgrade) (reconcile.Result, error) {
+	states := make([]v1alpha1.PackageUpgradeState, len(u.Spec.Packages))
+	for i, t := range u.Spec.Packages {
+		p, err := r.getPackage(ctx, t.Kind, t.Name)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		states[i] = v1alpha1.PackageUpgradeState{
+			Kind:             t.Kind,
+			Name:             t.Name,
+			PreviousPackage:  p.GetSource(),
+			PreviousRevision: p.GetCurrentRevision(),
+		}
+		if ap := p.GetActivationPolicy(); ap != nil {
+			states[i].PreviousActivationPolicy = string(*ap)
+		}
+	}
+
+	u.Status.Packages = states
+	u.SetConditions(v1alpha1.Staging(), xpv1.Creating())
+	return reconcile.Result{Requeue: true}, nil
+}
+
+// stage creates, but doesn't activate, a new revision of each package.
+// Switching a package to manual activation keeps its previous revision active
+// while the new revision is staged.
+func (r *Reconciler) stage(ctx context.Context, u *v1alpha1.PackageUpgrade) (reconcile.Result, error) {
+	staged := 0
+	for i, t := range u.Spec.Packages {
+		s := &u.Status.Packages[i]
+		if s.Revision != "" {
+			staged++
+			continue
+		}
+
+		p, err := r.getPackage(ctx, t.Kind, t.Name)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		if p.GetSource() != t.Package || ptr.Deref(p.GetActivationPolicy(), "") != v1.ManualActivation {
+			p.SetSource(t.Package)
+			p.SetActivationPolicy(&v1.ManualActivation)
+			if err := r.client.Update(ctx, p); err != nil {
+				return reconcile.Result{}, errors.Wrap(err, errUpdatePackage)
+			}
+			r.record.Event(u, event.Normal(reasonStage, fmt.Sprintf("Staging %s %q at %s", t.Kind, t.Name, t.Package)))
+			continue
+		}
+
+		// The package's current revision changes once the package manager
+		// has created a revision for the new source. It doesn't change if
+		// the package's source didn't.
+		if cur := p.GetCurrentRevision(); cur != "" && (cur != s.PreviousRevision || t.Package == s.PreviousPackage) {
+			s.Revision = cur
+			staged++
+		}
+	}
+
+	if staged == len(u.Spec.Packages) {
+		u.SetConditions(v1alpha1.Activating())
+		return reconcile.Result{Requeue: true}, nil
+	}
+
+	if timeout := healthTimeout(u); timedOut(u.GetCondition(v1alpha1.TypeUpgraded).LastTransitionTime, timeout) {
+		for i, t := range u.Spec.Packages {
+			if u.Status.Packages[i].Revision == "" {
+				r.fail(u, errors.Errorf(errFmtStageTimeout, t.Kind, t.Name, timeout))
+				break
+			}
+		}
+		return reconcile.Result{Requeue: true}, nil
+	}
+
+	return reconcile.Result{RequeueAfter: upgradeWait}, nil
+}
+
+// activate activates each staged revision in dependency order, waiting for
+// each to become healthy before activating the next.
+func (r *Reconciler) activate(ctx context.Context, u *v1alpha1.PackageUpgrade) (reconcile.Result, error) {
+	order, err := r.activationOrder(ctx, u.Spec.Packages)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	for _, i := range order {
+		t := u.Spec.Packages[i]
+		s := &u.Status.Packages[i]
+		if s.Healthy {
+			continue
+		}
+
+		pr, err := r.getRevision(ctx, t.Kind, s.Revision)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		if pr.GetDesiredState() != v1.PackageRevisionActive || s.ActivationTime == nil {
+			pr.SetDesiredState(v1.PackageRevisionActive)
+			if err := r.client.Update(ctx, pr); err != nil {
+				return reconcile.Result{}, errors.Wrap(err, errUpdateRevision)
+			}
+			now := metav1.Now()
+			s.ActivationTime = &now
+			r.record.Event(u, event.Normal(reasonActivate, fmt.Sprintf("Activated %s %q revision %q", t.Kind, t.Name, s.Revision)))
+			return reconcile.Result{RequeueAfter: upgradeWait}, nil
+		}
+
+		if !healthy(pr) {
+			if timeout := healthTimeout(u); timedOut(ptr.Deref(s.ActivationTime, metav1.Time{}), timeout) {
+				r.fail(u, errors.Errorf(errFmtUnhealthy, t.Kind, t.Name, s.Revision, timeout))
+				return reconcile.Result{Requeue: true}, nil
+			}
+			return reconcile.Result{RequeueAfter: upgradeWait}, nil
+		}
+
+		s.Healthy = true
+	}
+
+	// Every revision is active and healthy. Return each package to the
+	// activation policy it had before the upgrade.
+	for i, t := range u.Spec.Packages {
+		if err := r.restorePackage(ctx, t.Kind, t.Name, t.Package, u.Status.Packages[i].PreviousActivationPolicy); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	u.SetConditions(v1alpha1.Succeeded(), xpv1.Available())
+	return reconcile.Result{}, nil
+}
+
+// rollback returns each package to the source, activation policy, and revision
+// it had before the upgrade, in the reverse of the order they were activated.
+// It's complete once every package's current revision is its previous
+// revision, and that revision is active.
+func (r *Reconciler) rollback(ctx context.Context, u *v1alpha1.PackageUpgrade) (reconcile.Result, error) {
+	order, err := r.activationOrder(ctx, u.Spec.Packages)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	done := true
+	for j := len(order) - 1; j >= 0; j-- {
+		s := u.Status.Packages[order[j]]
+		if err := r.restorePackage(ctx, s.Kind, s.Name, s.PreviousPackage, s.PreviousActivationPolicy); err != nil {
+			return reconcile.Result{}, err
+		}
+
+		// The package didn't have a revision before the upgrade.
+		if s.PreviousRevision == "" {
+			continue
+		}
+
+		pr, err := r.getRevision(ctx, s.Kind, s.PreviousRevision)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if pr.GetDesiredState() != v1.PackageRevisionActive {
+			pr.SetDesiredState(v1.PackageRevisionActive)
+			if err := r.client.Update(ctx, pr); err != nil {
+				return reconcile.Result{}, errors.Wrap(err, errUpdateRevision)
+			}
+			done = false
+		}
+
+		p, err := r.getPackage(ctx, s.Kind, s.Name)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if p.GetCurrentRevision() != s.PreviousRevision {
+			done = false
+		}
+	}
+
+	if !done {
+		return reconcile.Result{RequeueAfter: upgradeWait}, nil
+	}
+
+	r.record.Event(u, event.Normal(reasonRollback, "Rolled back all packages to their previous revisions"))
+	u.SetConditions(v1alpha1.RolledBack())
+	return reconcile.Result{}, nil
+}
+
+// fail starts rolling back the upgrade. The failure is recorded as the
+// upgrade's Ready condition, which is left as is once the rollback completes.
+func (r *Reconciler) fail(u *v1alpha1.PackageUpgrade, err error) {
+	r.record.Event(u, event.Warning(reasonRollback, err))
+	u.SetConditions(v1alpha1.RollingBack(), xpv1.Unavailable().WithMessage(err.Error()))
+}
+
+// restorePackage sets a package's source and activation policy, if they
+// differ from the supplied values.
+func (r *Reconciler) restorePackage(ctx context.Context, kind, name, source, policy string) error {
+	p, err := r.getPackage(ctx, kind, name)
+	if err != nil {
+		return err
+	}
+
+	var ap *v1.RevisionActivationPolicy
+	if policy != "" {
+		ap = (*v1.RevisionActivationPolicy)(&policy)
+	}
+	if p.GetSource() == source && ptr.Deref(p.GetActivationPolicy(), "") == ptr.Deref(ap, "") {
+		return nil
+	}
+
+	p.SetSource(source)
+	p.SetActivationPolicy(ap)
+	return errors.Wrap(r.client.Update(ctx, p), errUpdatePackage)
+}
+
+func (r *Reconciler) getPackage(ctx context.Context, kind, name string) (v1.Package, error) {
+	var p v1.Package
+	switch kind {
+	case v1.ProviderKind:
+		p = &v1.Provider{}
+	case v1.ConfigurationKind:
+		p = &v1.Configuration{}
+	case v1.FunctionKind:
+		p = &v1.Function{}
+	default:
+		return nil, errors.Errorf(errFmtInvalidKind, kind)
+	}
+	return p, errors.Wrap(r.client.Get(ctx, types.NamespacedName{Name: name}, p), errGetPackage)
+}
+
+func (r *Reconciler) getRevision(ctx context.Context, kind, name string) (v1.PackageRevision, error) {
+	var pr v1.PackageRevision
+	switch kind {
+	case v1.ProviderKind:
+		pr = &v1.ProviderRevision{}
+	case v1.ConfigurationKind:
+		pr = &v1.ConfigurationRevision{}
+	case v1.FunctionKind:
+		pr = &v1.FunctionRevision{}
+	default:
+		return nil, errors.Errorf(errFmtInvalidKind, kind)
+	}
+	return pr, errors.Wrap(r.client.Get(ctx, types.NamespacedName{Name: name}, pr), errGetRevision)
+}
+
+// activationOrder returns the indices of the supplied packages in the order
+// they should be activated. A package is activated after any of the supplied
+// packages it depends on, directly or transitively, according to the
+// dependencies recorded in the package Lock. Otherwise packages are activated
+// in the order they're listed.
+func (r *Reconciler) activationOrder(ctx context.Context, pkgs []v1alpha1.PackageUpgradeTarget) ([]int, error) {
+	lock := &v1beta1.Lock{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: lockName}, lock); resource.IgnoreNotFound(err) != nil {
+		return nil, errors.Wrap(err, errGetLock)
+	}
+
+	d := dag.NewMapDag()
+	if _, err := d.Init(v1beta1.ToNodes(lock.Packages...)); err != nil {
+		return nil, errors.Wrap(err, errInitDAG)
+	}
+
+	sources := make([]string, len(pkgs))
+	for i, t := range pkgs {
+		src, err := lockSource(t.Package)
+		if err != nil {
+			return nil, err
+		}
+		sources[i] = src
+	}
+
+	// deps[i] contains the indices of the supplied packages package i
+	// depends on.
+	deps := make([]map[int]bool, len(pkgs))
+	for i := range pkgs {
+		deps[i] = map[int]bool{}
+		if !d.NodeExists(sources[i]) {
+			continue
+		}
+		tree, err := d.TraceNode(sources[i])
+		if err != nil {
+			return nil, errors.Wrap(err, errInitDAG)
+		}
+		for j := range pkgs {
+			if _, ok := tree[sources[j]]; ok && j != i {
+				deps[i][j] = true
+			}
+		}
+	}
+
+	order := make([]int, 0, len(pkgs))
+	added := make([]bool, len(pkgs))
+	for len(order) < len(pkgs) {
+		next := -1
+		for i := range pkgs {
+			if !added[i] && satisfied(deps[i], added) {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			return nil, errors.New(errDependencyCycle)
+		}
+		order = append(order, next)
+		added[next] = true
+	}
+	return order, nil
+}
+
+// satisfied returns true if all of the supplied dependencies have been added.
+func satisfied(deps map[int]bool, added []bool) bool {
+	for j := range deps {
+		if !added[j] {
+			return false
+		}
+	}
+	return true
+}
+
+// lockSource returns the source the package Lock records for the supplied
+// package, i.e. its OCI image name without a tag or digest.
+func lockSource(pkg string) (string, error) {
+	if xpkg.IsURLSource(pkg) {
+		return pkg, nil
+	}
+	ref, err := name.ParseReference(xpkg.UnpinnedSource(pkg), name.WithDefaultRegistry(""))
+	if err != nil {
+		return "", errors.Wrap(err, errParseSource)
+	}
+	return xpkg.ParsePackageSourceFromReference(ref), nil
+}
+
+// healthy returns true if the supplied revision is healthy, and its runtime is
+// healthy if it has one.
+func healthy(pr v1.PackageRevision) bool {
+	if pr.GetCondition(v1.TypeHealthy).Status != corev1.ConditionTrue {
+		return false
+	}
+	if _, ok := pr.(v1.PackageRevisionWithRuntime); ok {
+		return pr.GetCondition(v1.TypeRuntimeHealthy).Status == corev1.ConditionTrue
+	}
+	return true
+}
+
+func healthTimeout(u *v1alpha1.PackageUpgrade) time.Duration {
+	if u.Spec.HealthTimeout == nil {
+		return defaultHealthTimeout
+	}
+	return u.Spec.HealthTimeout.Duration
+}
+
+func timedOut(since metav1.Time, timeout time.Duration) bool {
+	return !since.IsZero() && time.Since(since.Time) > timeout
+}
@@ -0,0 +1,459 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1alpha1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+func TestReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+	longAgo := &metav1.Time{Time: time.Now().Add(-time.Hour)}
+
+	targets := []v1alpha1.PackageUpgradeTarget{
+		{Kind: v1.ConfigurationKind, Name: "platform", Package: "xpkg.example.org/platform:v2"},
+		{Kind: v1.ProviderKind, Name: "provider-nop", Package: "xpkg.example.org/provider-nop:v2"},
+	}
+
+	// The Configuration depends on the Provider.
+	lock := &v1beta1.Lock{
+		Packages: []v1beta1.LockPackage{
+			{Source: "xpkg.example.org/platform", Dependencies: []v1beta1.Dependency{{Package: "xpkg.example.org/provider-nop"}}},
+			{Source: "xpkg.example.org/provider-nop"},
+		},
+	}
+
+	// upgrade returns a PackageUpgrade of the above targets at the supplied
+	// stage, with the supplied package states.
+	upgrade := func(stage *xpv1.Condition, states ...v1alpha1.PackageUpgradeState) *v1alpha1.PackageUpgrade {
+		u := &v1alpha1.PackageUpgrade{
+			Spec:   v1alpha1.PackageUpgradeSpec{Packages: targets},
+			Status: v1alpha1.PackageUpgradeStatus{Packages: states},
+		}
+		if stage != nil {
+			u.SetConditions(*stage)
+		}
+		return u
+	}
+	since := func(c xpv1.Condition, t *metav1.Time) *xpv1.Condition {
+		c.LastTransitionTime = *t
+		return &c
+	}
+	previous := func(revCfg, revPrv string, healthy ...bool) []v1alpha1.PackageUpgradeState {
+		s := []v1alpha1.PackageUpgradeState{
+			{Kind: v1.ConfigurationKind, Name: "platform", PreviousPackage: "xpkg.example.org/platform:v1", PreviousRevision: "platform-v1", Revision: revCfg},
+			{Kind: v1.ProviderKind, Name: "provider-nop", PreviousPackage: "xpkg.example.org/provider-nop:v1", PreviousRevision: "provider-nop-v1", Revision: revPrv},
+		}
+		for i, h := range healthy {
+			s[i].Healthy = h
+		}
+		return s
+	}
+
+	// kube returns a client that gets the supplied upgrade, configuration,
+	// and provider, and a revision in the supplied state. It records the
+	// upgrade's status when it's updated.
+	type objects struct {
+		u   *v1alpha1.PackageUpgrade
+		cfg *v1.Configuration
+		prv *v1.Provider
+		rev func(name string) v1.PackageRevision
+	}
+	kube := func(o objects, status **v1alpha1.PackageUpgrade) *test.MockClient {
+		return &test.MockClient{
+			MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+				switch out := obj.(type) {
+				case *v1alpha1.PackageUpgrade:
+					o.u.DeepCopyInto(out)
+				case *v1beta1.Lock:
+					lock.DeepCopyInto(out)
+				case *v1.Configuration:
+					o.cfg.DeepCopyInto(out)
+				case *v1.Provider:
+					o.prv.DeepCopyInto(out)
+				case *v1.ConfigurationRevision:
+					o.rev(key.Name).(*v1.ConfigurationRevision).DeepCopyInto(out)
+				case *v1.ProviderRevision:
+					o.rev(key.Name).(*v1.ProviderRevision).DeepCopyInto(out)
+				}
+				return nil
+			},
+			MockUpdate: test.NewMockUpdateFn(nil),
+			MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(obj client.Object) error {
+				*status = obj.(*v1alpha1.PackageUpgrade).DeepCopy()
+				return nil
+			}),
+		}
+	}
+
+	cfg := func(source, rev string, ap *v1.RevisionActivationPolicy) *v1.Configuration {
+		c := &v1.Configuration{}
+		c.SetName("platform")
+		c.SetSource(source)
+		c.SetActivationPolicy(ap)
+		c.SetCurrentRevision(rev)
+		return c
+	}
+	prv := func(source, rev string, ap *v1.RevisionActivationPolicy) *v1.Provider {
+		p := &v1.Provider{}
+		p.SetName("provider-nop")
+		p.SetSource(source)
+		p.SetActivationPolicy(ap)
+		p.SetCurrentRevision(rev)
+		return p
+	}
+	revision := func(state v1.PackageRevisionDesiredState, c ...xpv1.Condition) func(name string) v1.PackageRevision {
+		return func(name string) v1.PackageRevision {
+			var pr v1.PackageRevision = &v1.ConfigurationRevision{}
+			if strings.HasPrefix(name, "provider") {
+				pr = &v1.ProviderRevision{}
+			}
+			pr.SetName(name)
+			pr.SetDesiredState(state)
+			pr.SetConditions(c...)
+			return pr
+		}
+	}
+
+	type args struct {
+		objects objects
+		kube    client.Client
+	}
+	type want struct {
+		u   *v1alpha1.PackageUpgrade
+		r   reconcile.Result
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"GetUpgradeError": {
+			reason: "We should return an error if we can't get the upgrade.",
+			args: args{
+				kube: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetUpgrade),
+			},
+		},
+		"Start": {
+			reason: "We should record each package's previous state and start staging.",
+			args: args{
+				objects: objects{
+					u:   upgrade(nil),
+					cfg: cfg("xpkg.example.org/platform:v1", "platform-v1", nil),
+					prv: prv("xpkg.example.org/provider-nop:v1", "provider-nop-v1", &v1.AutomaticActivation),
+				},
+			},
+			want: want{
+				u: func() *v1alpha1.PackageUpgrade {
+					u := upgrade(ptr.To(v1alpha1.Staging()), previous("", "")...)
+					u.Status.Packages[1].PreviousActivationPolicy = string(v1.AutomaticActivation)
+					u.SetConditions(xpv1.Creating(), xpv1.ReconcileSuccess())
+					return u
+				}(),
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"StageRevisions": {
+			reason: "We should switch packages to manual activation and their new source, and wait for them to be staged.",
+			args: args{
+				objects: objects{
+					u:   upgrade(ptr.To(v1alpha1.Staging()), previous("", "")...),
+					cfg: cfg("xpkg.example.org/platform:v1", "platform-v1", nil),
+					prv: prv("xpkg.example.org/provider-nop:v2", "provider-nop-v2", &v1.ManualActivation),
+				},
+			},
+			want: want{
+				u: func() *v1alpha1.PackageUpgrade {
+					u := upgrade(ptr.To(v1alpha1.Staging()), previous("", "provider-nop-v2")...)
+					u.SetConditions(xpv1.ReconcileSuccess())
+					return u
+				}(),
+				r: reconcile.Result{RequeueAfter: upgradeWait},
+			},
+		},
+		"StagingTimedOut": {
+			reason: "We should roll back if a package doesn't stage a new revision in time.",
+			args: args{
+				objects: objects{
+					u:   upgrade(since(v1alpha1.Staging(), longAgo), previous("", "")...),
+					cfg: cfg("xpkg.example.org/platform:v2", "platform-v1", &v1.ManualActivation),
+					prv: prv("xpkg.example.org/provider-nop:v2", "provider-nop-v2", &v1.ManualActivation),
+				},
+			},
+			want: want{
+				u: func() *v1alpha1.PackageUpgrade {
+					u := upgrade(ptr.To(v1alpha1.RollingBack()), previous("", "provider-nop-v2")...)
+					u.SetConditions(xpv1.Unavailable().WithMessage(errors.Errorf(errFmtStageTimeout, v1.ConfigurationKind, "platform", defaultHealthTimeout).Error()), xpv1.ReconcileSuccess())
+					return u
+				}(),
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"Staged": {
+			reason: "We should start activating once every package has staged a new revision.",
+			args: args{
+				objects: objects{
+					u:   upgrade(ptr.To(v1alpha1.Staging()), previous("", "provider-nop-v2")...),
+					cfg: cfg("xpkg.example.org/platform:v2", "platform-v2", &v1.ManualActivation),
+				},
+			},
+			want: want{
+				u: func() *v1alpha1.PackageUpgrade {
+					u := upgrade(ptr.To(v1alpha1.Activating()), previous("platform-v2", "provider-nop-v2")...)
+					u.SetConditions(xpv1.ReconcileSuccess())
+					return u
+				}(),
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"ActivateProviderFirst": {
+			reason: "We should activate the Provider before the Configuration that the Lock records as depending on it.",
+			args: args{
+				objects: objects{
+					u:   upgrade(ptr.To(v1alpha1.Activating()), previous("platform-v2", "provider-nop-v2")...),
+					rev: revision(v1.PackageRevisionInactive),
+				},
+			},
+			want: want{
+				u: func() *v1alpha1.PackageUpgrade {
+					u := upgrade(ptr.To(v1alpha1.Activating()), previous("platform-v2", "provider-nop-v2")...)
+					u.Status.Packages[1].ActivationTime = &metav1.Time{}
+					u.SetConditions(xpv1.ReconcileSuccess())
+					return u
+				}(),
+				r: reconcile.Result{RequeueAfter: upgradeWait},
+			},
+		},
+		"UnhealthyTimedOut": {
+			reason: "We should roll back if an activated revision doesn't become healthy in time.",
+			args: args{
+				objects: objects{
+					u: func() *v1alpha1.PackageUpgrade {
+						u := upgrade(ptr.To(v1alpha1.Activating()), previous("platform-v2", "provider-nop-v2")...)
+						u.Status.Packages[1].ActivationTime = longAgo
+						return u
+					}(),
+					rev: revision(v1.PackageRevisionActive, v1.Healthy(), v1.RuntimeUnhealthy()),
+				},
+			},
+			want: want{
+				u: func() *v1alpha1.PackageUpgrade {
+					u := upgrade(ptr.To(v1alpha1.RollingBack()), previous("platform-v2", "provider-nop-v2")...)
+					u.Status.Packages[1].ActivationTime = longAgo
+					u.SetConditions(xpv1.Unavailable().WithMessage(errors.Errorf(errFmtUnhealthy, v1.ProviderKind, "provider-nop", "provider-nop-v2", defaultHealthTimeout).Error()), xpv1.ReconcileSuccess())
+					return u
+				}(),
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"Succeeded": {
+			reason: "We should restore each package's activation policy once every revision is healthy.",
+			args: args{
+				objects: objects{
+					u: func() *v1alpha1.PackageUpgrade {
+						u := upgrade(ptr.To(v1alpha1.Activating()), previous("platform-v2", "provider-nop-v2", false, true)...)
+						u.Status.Packages[0].ActivationTime = longAgo
+						return u
+					}(),
+					cfg: cfg("xpkg.example.org/platform:v2", "platform-v2", &v1.ManualActivation),
+					prv: prv("xpkg.example.org/provider-nop:v2", "provider-nop-v2", &v1.ManualActivation),
+					rev: revision(v1.PackageRevisionActive, v1.Healthy()),
+				},
+			},
+			want: want{
+				u: func() *v1alpha1.PackageUpgrade {
+					u := upgrade(ptr.To(v1alpha1.Succeeded()), previous("platform-v2", "provider-nop-v2", true, true)...)
+					u.Status.Packages[0].ActivationTime = longAgo
+					u.SetConditions(xpv1.Available(), xpv1.ReconcileSuccess())
+					return u
+				}(),
+			},
+		},
+		"RollingBack": {
+			reason: "We should wait for each package to return to its previous revision.",
+			args: args{
+				objects: objects{
+					u:   upgrade(ptr.To(v1alpha1.RollingBack()), previous("platform-v2", "provider-nop-v2")...),
+					cfg: cfg("xpkg.example.org/platform:v2", "platform-v2", &v1.ManualActivation),
+					prv: prv("xpkg.example.org/provider-nop:v2", "provider-nop-v2", &v1.ManualActivation),
+					rev: revision(v1.PackageRevisionInactive),
+				},
+			},
+			want: want{
+				u: func() *v1alpha1.PackageUpgrade {
+					u := upgrade(ptr.To(v1alpha1.RollingBack()), previous("platform-v2", "provider-nop-v2")...)
+					u.SetConditions(xpv1.ReconcileSuccess())
+					return u
+				}(),
+				r: reconcile.Result{RequeueAfter: upgradeWait},
+			},
+		},
+		"RolledBack": {
+			reason: "We should finish rolling back once every package's previous revision is current and active.",
+			args: args{
+				objects: objects{
+					u:   upgrade(ptr.To(v1alpha1.RollingBack()), previous("platform-v2", "provider-nop-v2")...),
+					cfg: cfg("xpkg.example.org/platform:v1", "platform-v1", nil),
+					prv: prv("xpkg.example.org/provider-nop:v1", "provider-nop-v1", nil),
+					rev: revision(v1.PackageRevisionActive),
+				},
+			},
+			want: want{
+				u: func() *v1alpha1.PackageUpgrade {
+					u := upgrade(ptr.To(v1alpha1.RolledBack()), previous("platform-v2", "provider-nop-v2")...)
+					u.SetConditions(xpv1.ReconcileSuccess())
+					return u
+				}(),
+			},
+		},
+		"Done": {
+			reason: "We should do nothing once an upgrade is done.",
+			args: args{
+				objects: objects{
+					u: upgrade(ptr.To(v1alpha1.Succeeded())),
+				},
+			},
+			want: want{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got *v1alpha1.PackageUpgrade
+			c := tc.args.kube
+			if c == nil {
+				c = kube(tc.args.objects, &got)
+			}
+			r := &Reconciler{client: c, log: logging.NewNopLogger(), record: event.NewNopRecorder()}
+
+			res, err := r.Reconcile(context.Background(), reconcile.Request{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.r, res); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want result, +got result:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.u, got, test.EquateConditions(), cmp.Comparer(func(a, b *metav1.Time) bool { return (a == nil) == (b == nil) })); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want status, +got status:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestActivationOrder(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		kube client.Client
+		pkgs []v1alpha1.PackageUpgradeTarget
+	}
+	type want struct {
+		order []int
+		err   error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"GetLockError": {
+			reason: "We should return an error if we can't get the Lock.",
+			args: args{
+				kube: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetLock),
+			},
+		},
+		"NoLock": {
+			reason: "Packages should be activated in the order they're listed if there's no Lock.",
+			args: args{
+				kube: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, lockName))},
+				pkgs: []v1alpha1.PackageUpgradeTarget{
+					{Kind: v1.ConfigurationKind, Name: "platform", Package: "xpkg.example.org/platform:v2"},
+					{Kind: v1.ProviderKind, Name: "provider-nop", Package: "xpkg.example.org/provider-nop:v2"},
+				},
+			},
+			want: want{
+				order: []int{0, 1},
+			},
+		},
+		"DependencyOrder": {
+			reason: "Packages should be activated after the packages they transitively depend on, otherwise in the order they're listed.",
+			args: args{
+				kube: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					l := obj.(*v1beta1.Lock)
+					l.Packages = []v1beta1.LockPackage{
+						{Source: "xpkg.example.org/app", Dependencies: []v1beta1.Dependency{{Package: "xpkg.example.org/platform"}}},
+						{Source: "xpkg.example.org/platform", Dependencies: []v1beta1.Dependency{{Package: "xpkg.example.org/provider-nop"}, {Package: "xpkg.example.org/function-nop"}}},
+						{Source: "xpkg.example.org/provider-nop"},
+						{Source: "xpkg.example.org/function-nop"},
+						{Source: "xpkg.example.org/provider-other"},
+					}
+					return nil
+				})},
+				pkgs: []v1alpha1.PackageUpgradeTarget{
+					{Kind: v1.ConfigurationKind, Name: "app", Package: "xpkg.example.org/app:v2"},
+					{Kind: v1.FunctionKind, Name: "function-nop", Package: "xpkg.example.org/function-nop:v2"},
+					{Kind: v1.ProviderKind, Name: "provider-nop", Package: "xpkg.example.org/provider-nop:v2@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d4e1a0d0b0c8c7a"},
+					{Kind: v1.ProviderKind, Name: "provider-other", Package: "xpkg.example.org/provider-other:v2"},
+				},
+			},
+			want: want{
+				order: []int{1, 2, 0, 3},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &Reconciler{client: tc.args.kube}
+			got, err := r.activationOrder(context.Background(), tc.args.pkgs)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.activationOrder(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.order, got); diff != "" {
+				t.Errorf("\n%s\nr.activationOrder(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
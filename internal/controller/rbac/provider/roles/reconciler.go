@@ -263,7 +263,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{Requeue: false}, nil
 	}
 
-	resources := DefinedResources(pr.Status.ObjectRefs)
+	resources := DefinedResources(pr.GetObjects())
 
 	// If this revision is part of a provider family we consider it to 'own' all
 	// of the family's CRDs (despite it not actually being an owner reference).
@@ -306,7 +306,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 				continue
 			}
 
-			resources = append(resources, DefinedResources(member.Status.ObjectRefs)...)
+			resources = append(resources, DefinedResources(member.GetObjects())...)
 		}
 	}
 
@@ -18,9 +18,24 @@ limitations under the License.
 package dag
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 )
 
+// A CycleError indicates that a graph contains a cycle.
+type CycleError struct {
+	// Cycle is the identifiers of the nodes in the cycle, in order. The first
+	// node is repeated at the end.
+	Cycle []string
+}
+
+// Error returns a description of the cycle.
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("detected cycle: %s", strings.Join(e.Cycle, " -> "))
+}
+
 // Node is a node in DAG.
 type Node interface {
 	Identifier() string
@@ -199,7 +214,7 @@ func (d *MapDag) Sort() ([]string, error) {
 	results := make([]string, len(d.nodes))
 	for n, node := range d.nodes {
 		if !visited[n] {
-			stack := map[string]bool{}
+			var stack []string
 			if err := d.visit(n, node.Neighbors(), stack, visited, results); err != nil {
 				return nil, err
 			}
@@ -208,9 +223,9 @@ func (d *MapDag) Sort() ([]string, error) {
 	return results, nil
 }
 
-func (d *MapDag) visit(name string, neighbors []Node, stack map[string]bool, visited map[string]bool, results []string) error {
+func (d *MapDag) visit(name string, neighbors []Node, stack []string, visited map[string]bool, results []string) error {
 	visited[name] = true
-	stack[name] = true
+	stack = append(stack, name)
 	for _, n := range neighbors {
 		if !visited[n.Identifier()] {
 			if _, ok := d.nodes[n.Identifier()]; !ok {
@@ -219,8 +234,12 @@ func (d *MapDag) visit(name string, neighbors []Node, stack map[string]bool, vis
 			if err := d.visit(n.Identifier(), d.nodes[n.Identifier()].Neighbors(), stack, visited, results); err != nil {
 				return err
 			}
-		} else if stack[n.Identifier()] {
-			return errors.Errorf("detected cycle on: %s", n.Identifier())
+			continue
+		}
+		for i, s := range stack {
+			if s == n.Identifier() {
+				return &CycleError{Cycle: append(append([]string{}, stack[i:]...), s)}
+			}
 		}
 	}
 	for i, r := range results {
@@ -229,6 +248,32 @@ func (d *MapDag) visit(name string, neighbors []Node, stack map[string]bool, vis
 			break
 		}
 	}
-	stack[name] = false
 	return nil
 }
+
+// FindCycle returns the identifiers of the nodes in a cycle that starts and
+// ends at the supplied node, or nil if the node isn't part of a cycle.
+func FindCycle(d DAG, identifier string) []string {
+	visited := map[string]bool{}
+	var find func(id string, path []string) []string
+	find = func(id string, path []string) []string {
+		n, err := d.GetNode(id)
+		if err != nil || n == nil {
+			return nil
+		}
+		for _, nb := range n.Neighbors() {
+			if nb.Identifier() == identifier {
+				return append(append([]string{}, path...), identifier)
+			}
+			if visited[nb.Identifier()] {
+				continue
+			}
+			visited[nb.Identifier()] = true
+			if c := find(nb.Identifier(), append(path, nb.Identifier())); c != nil {
+				return c
+			}
+		}
+		return nil
+	}
+	return find(identifier, []string{identifier})
+}
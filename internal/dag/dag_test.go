@@ -242,3 +242,43 @@ func TestDag(_ *testing.T) {
 	d := NewMapDag()
 	d.AddNode(&simpleNode{identifier: "hi"})
 }
+
+func TestCycle(t *testing.T) {
+	one := "crossplane/one"
+	two := "crossplane/two"
+	three := "crossplane/three"
+	four := "crossplane/four"
+
+	// One depends on two, which depends on three, which depends on one. Four
+	// depends on one, but isn't part of the cycle.
+	nodes := []simpleNode{
+		{identifier: one, neighbors: map[string]simpleNode{two: {identifier: two}}},
+		{identifier: two, neighbors: map[string]simpleNode{three: {identifier: three}}},
+		{identifier: three, neighbors: map[string]simpleNode{one: {identifier: one}}},
+		{identifier: four, neighbors: map[string]simpleNode{one: {identifier: one}}},
+	}
+
+	d := NewMapDag()
+	if _, err := d.Init(toNodes(nodes)); err != nil {
+		t.Fatalf("Init(...): %v", err)
+	}
+
+	_, err := d.Sort()
+	ce := &CycleError{}
+	if !errors.As(err, &ce) {
+		t.Fatalf("Sort(): want *CycleError, got %v", err)
+	}
+	if diff := cmp.Diff(ce.Cycle[0], ce.Cycle[len(ce.Cycle)-1]); diff != "" {
+		t.Errorf("Sort(): cycle should start and end at the same node: -first, +last:\n%s", diff)
+	}
+	if diff := cmp.Diff(4, len(ce.Cycle)); diff != "" {
+		t.Errorf("Sort(): -want cycle length, +got cycle length:\n%s", diff)
+	}
+
+	if diff := cmp.Diff([]string{two, three, one, two}, FindCycle(d, two)); diff != "" {
+		t.Errorf("FindCycle(...): -want, +got:\n%s", diff)
+	}
+	if got := FindCycle(d, four); got != nil {
+		t.Errorf("FindCycle(...): want nil for a node outside the cycle, got %v", got)
+	}
+}
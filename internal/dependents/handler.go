@@ -0,0 +1,285 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dependents contains the Handler for the package dependents webhook.
+package dependents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+const (
+	lockName = "lock"
+
+	errFmtUnexpectedOp   = "unexpected operation %q, expected \"DELETE\""
+	errFmtUnexpectedKind = "unexpected kind %q"
+	errGetLock           = "cannot get package lock"
+	errGetRevision       = "cannot get package revision to check the APIs it defines"
+	errGetCRD            = "cannot get CustomResourceDefinition to check the APIs it defines"
+
+	kindCRD = "CustomResourceDefinition"
+)
+
+// SetupWebhookWithManager sets up the webhook with the manager.
+func SetupWebhookWithManager(mgr ctrl.Manager, options controller.Options, opts ...HandlerOption) error {
+	opts = append([]HandlerOption{WithLogger(options.Logger.WithValues("webhook", "package-dependents"))}, opts...)
+	mgr.GetWebhookServer().Register("/validate-package-dependents",
+		&webhook.Admission{Handler: NewHandler(mgr.GetClient(), opts...)})
+	return nil
+}
+
+// Handler implements the admission Handler for package dependents.
+type Handler struct {
+	client client.Reader
+	log    logging.Logger
+}
+
+// HandlerOption is used to configure the Handler.
+type HandlerOption func(*Handler)
+
+// WithLogger configures the logger for the Handler.
+func WithLogger(l logging.Logger) HandlerOption {
+	return func(h *Handler) {
+		h.log = l
+	}
+}
+
+// NewHandler returns a new Handler.
+func NewHandler(c client.Reader, opts ...HandlerOption) *Handler {
+	h := &Handler{
+		client: c,
+		log:    logging.NewNopLogger(),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Handle handles the admission request, validating that no other package
+// depends on the package being deleted.
+func (h *Handler) Handle(ctx context.Context, request admission.Request) admission.Response {
+	if request.Operation != admissionv1.Delete {
+		return admission.Errored(http.StatusBadRequest, errors.Errorf(errFmtUnexpectedOp, request.Operation))
+	}
+
+	var p v1.Package
+	switch request.Kind.Kind {
+	case v1.ProviderKind:
+		p = &v1.Provider{}
+	case v1.ConfigurationKind:
+		p = &v1.Configuration{}
+	case v1.FunctionKind:
+		p = &v1.Function{}
+	default:
+		return admission.Errored(http.StatusBadRequest, errors.Errorf(errFmtUnexpectedKind, request.Kind.Kind))
+	}
+	if err := json.Unmarshal(request.OldObject.Raw, p); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if p.GetAnnotations()[v1.AnnotationForceDelete] == "true" {
+		h.log.Debug("Deletion forced, not checking for dependents", "kind", request.Kind.Kind, "name", p.GetName())
+		return admission.Allowed("")
+	}
+
+	dependents, err := h.dependents(ctx, p.GetCurrentRevision())
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if len(dependents) == 0 {
+		return admission.Allowed("")
+	}
+
+	h.log.Debug("Package has dependents, deletion not allowed", "kind", request.Kind.Kind, "name", p.GetName(), "dependents", dependents)
+	return admission.Denied(fmt.Sprintf("Cannot delete %s %q because %d package(s) depend on it: %s. Delete the dependent packages first, or set the %s annotation to \"true\" to delete it anyway.",
+		request.Kind.Kind, p.GetName(), len(dependents), strings.Join(dependents, ", "), v1.AnnotationForceDelete))
+}
+
+// dependents returns the packages in the lock that depend on the package added
+// to the lock by the supplied revision.
+func (h *Handler) dependents(ctx context.Context, revision string) ([]string, error) {
+	// A package with no current revision was never added to the lock.
+	if revision == "" {
+		return nil, nil
+	}
+
+	l := &v1beta1.Lock{}
+	if err := h.client.Get(ctx, types.NamespacedName{Name: lockName}, l); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, errGetLock)
+	}
+
+	var self *v1beta1.LockPackage
+	for i := range l.Packages {
+		if l.Packages[i].Name == revision {
+			self = &l.Packages[i]
+			break
+		}
+	}
+	if self == nil {
+		return nil, nil
+	}
+
+	apis := &definedAPIs{client: h.client, cache: map[string]map[string]bool{}}
+
+	var dependents []string
+	for _, lp := range l.Packages {
+		if lp.Name == revision {
+			continue
+		}
+		ok, err := dependsOn(ctx, lp, *self, l.Packages, apis)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			dependents = append(dependents, fmt.Sprintf("%s %s", lp.Type, lp.Source))
+		}
+	}
+	return dependents, nil
+}
+
+// dependsOn returns true if the supplied lock package would have an
+// unsatisfied dependency without the supplied dependency. It depends on a
+// package it, or one of its alternatives, names unless another of those
+// alternatives is installed. It depends on the APIs the package defines unless
+// another installed package defines them too.
+func dependsOn(ctx context.Context, lp, dep v1beta1.LockPackage, pkgs []v1beta1.LockPackage, apis *definedAPIs) (bool, error) {
+	for _, d := range lp.Dependencies {
+		cs := d.Candidates()
+		if !slices.ContainsFunc(cs, func(c v1beta1.DependencyAlternative) bool { return c.Package == dep.Source }) {
+			continue
+		}
+		other := slices.ContainsFunc(cs, func(c v1beta1.DependencyAlternative) bool {
+			return c.Package != dep.Source && slices.ContainsFunc(pkgs, func(p v1beta1.LockPackage) bool { return p.Source == c.Package && p.Type == c.Type })
+		})
+		if !other {
+			return true, nil
+		}
+	}
+
+	for _, api := range lp.APIs {
+		defined, err := apis.Get(ctx, dep)
+		if err != nil {
+			return false, err
+		}
+		if !defined[api] {
+			continue
+		}
+		satisfied := false
+		for _, p := range pkgs {
+			if p.Name == dep.Name || p.Name == lp.Name {
+				continue
+			}
+			defined, err := apis.Get(ctx, p)
+			if err != nil {
+				return false, err
+			}
+			if defined[api] {
+				satisfied = true
+				break
+			}
+		}
+		if !satisfied {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// definedAPIs gets the APIs the package revisions in the lock define, caching
+// them by revision name.
+type definedAPIs struct {
+	client client.Reader
+	cache  map[string]map[string]bool
+}
+
+// Get returns the APIs, in the form group/version, that the supplied lock
+// package's revision defines. A revision defines the versions its CRDs, and
+// the CRDs its XRDs defined, serve.
+func (a *definedAPIs) Get(ctx context.Context, lp v1beta1.LockPackage) (map[string]bool, error) {
+	if apis, ok := a.cache[lp.Name]; ok {
+		return apis, nil
+	}
+
+	var pr v1.PackageRevision
+	switch lp.Type {
+	case v1beta1.ProviderPackageType:
+		pr = &v1.ProviderRevision{}
+	case v1beta1.ConfigurationPackageType:
+		pr = &v1.ConfigurationRevision{}
+	case v1beta1.FunctionPackageType:
+		pr = &v1.FunctionRevision{}
+	default:
+		return nil, errors.Errorf(errFmtUnexpectedKind, lp.Type)
+	}
+
+	apis := map[string]bool{}
+	if err := a.client.Get(ctx, types.NamespacedName{Name: lp.Name}, pr); err != nil {
+		if kerrors.IsNotFound(err) {
+			a.cache[lp.Name] = apis
+			return apis, nil
+		}
+		return nil, errors.Wrap(err, errGetRevision)
+	}
+
+	for _, ref := range pr.GetObjects() {
+		if ref.Kind != kindCRD && ref.Kind != apiextensionsv1.CompositeResourceDefinitionKind {
+			continue
+		}
+		// An XRD's CRD has the same name as the XRD.
+		crd := &extv1.CustomResourceDefinition{}
+		if err := a.client.Get(ctx, types.NamespacedName{Name: ref.Name}, crd); err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return nil, errors.Wrap(err, errGetCRD)
+		}
+		for _, v := range crd.Spec.Versions {
+			if v.Served {
+				apis[crd.Spec.Group+"/"+v.Name] = true
+			}
+		}
+	}
+
+	a.cache[lp.Name] = apis
+	return apis, nil
+}
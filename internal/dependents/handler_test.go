@@ -0,0 +1,272 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dependents
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionv1 "k8s.io/api/admission/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+var _ admission.Handler = &Handler{}
+
+func TestHandle(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	// provider-nop is depended on by config-nop.
+	lock := test.NewMockGetFn(nil, func(obj client.Object) error {
+		l := obj.(*v1beta1.Lock)
+		l.Packages = []v1beta1.LockPackage{
+			{
+				Name:   "provider-nop-abc123",
+				Type:   v1beta1.ProviderPackageType,
+				Source: "xpkg.upbound.io/acme/provider-nop",
+			},
+			{
+				Name:   "config-nop-abc123",
+				Type:   v1beta1.ConfigurationPackageType,
+				Source: "xpkg.upbound.io/acme/config-nop",
+				Dependencies: []v1beta1.Dependency{
+					{
+						Package: "xpkg.upbound.io/acme/provider-nop",
+						Type:    v1beta1.ProviderPackageType,
+					},
+				},
+			},
+		}
+		return nil
+	})
+
+	// kube returns a client that gets a lock of the supplied packages, and
+	// provider revisions that define CRDs of the supplied API group versions.
+	// Each CRD is named after its revision.
+	kube := func(pkgs []v1beta1.LockPackage, defines map[string]string) *test.MockClient {
+		return &test.MockClient{
+			MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+				switch o := obj.(type) {
+				case *v1beta1.Lock:
+					o.Packages = pkgs
+				case *v1.ProviderRevision:
+					if _, ok := defines[key.Name]; ok {
+						o.SetObjects([]xpv1.TypedReference{{Kind: kindCRD, Name: key.Name}})
+					}
+				case *extv1.CustomResourceDefinition:
+					gv, _ := schema.ParseGroupVersion(defines[key.Name])
+					o.Spec.Group = gv.Group
+					o.Spec.Versions = []extv1.CustomResourceDefinitionVersion{{Name: gv.Version, Served: true}}
+				}
+				return nil
+			},
+		}
+	}
+	providers := func(names ...string) []v1beta1.LockPackage {
+		pkgs := make([]v1beta1.LockPackage, len(names))
+		for i, n := range names {
+			pkgs[i] = v1beta1.LockPackage{Name: n + "-abc123", Type: v1beta1.ProviderPackageType, Source: "xpkg.upbound.io/acme/" + n}
+		}
+		return pkgs
+	}
+	config := func(apis []string, deps ...v1beta1.Dependency) v1beta1.LockPackage {
+		return v1beta1.LockPackage{
+			Name:         "config-nop-abc123",
+			Type:         v1beta1.ConfigurationPackageType,
+			Source:       "xpkg.upbound.io/acme/config-nop",
+			Dependencies: deps,
+			APIs:         apis,
+		}
+	}
+	alternatives := func(pkg string, alts ...string) v1beta1.Dependency {
+		d := v1beta1.Dependency{Package: "xpkg.upbound.io/acme/" + pkg, Type: v1beta1.ProviderPackageType}
+		for _, a := range alts {
+			d.Alternatives = append(d.Alternatives, v1beta1.DependencyAlternative{Package: "xpkg.upbound.io/acme/" + a, Type: v1beta1.ProviderPackageType})
+		}
+		return d
+	}
+
+	provider := func(annotations map[string]string) *v1.Provider {
+		p := &v1.Provider{ObjectMeta: metav1.ObjectMeta{Name: "provider-nop", Annotations: annotations}}
+		p.SetCurrentRevision("provider-nop-abc123")
+		return p
+	}
+	del := func(kind string, obj runtime.Object) admission.Request {
+		raw, _ := json.Marshal(obj)
+		return admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Delete,
+				Kind:      metav1.GroupVersionKind{Group: v1.Group, Version: v1.Version, Kind: kind},
+				OldObject: runtime.RawExtension{Raw: raw},
+			},
+		}
+	}
+
+	type args struct {
+		client  client.Reader
+		request admission.Request
+	}
+	type want struct {
+		resp admission.Response
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"UnexpectedCreate": {
+			reason: "We should return an error if the request is a create (not a delete).",
+			args: args{
+				request: admission.Request{
+					AdmissionRequest: admissionv1.AdmissionRequest{
+						Operation: admissionv1.Create,
+					},
+				},
+			},
+			want: want{
+				resp: admission.Errored(http.StatusBadRequest, errors.Errorf(errFmtUnexpectedOp, admissionv1.Create)),
+			},
+		},
+		"UnexpectedKind": {
+			reason: "We should return an error if the request is for a kind that isn't a package.",
+			args: args{
+				request: del(v1.ProviderRevisionKind, &v1.ProviderRevision{}),
+			},
+			want: want{
+				resp: admission.Errored(http.StatusBadRequest, errors.Errorf(errFmtUnexpectedKind, v1.ProviderRevisionKind)),
+			},
+		},
+		"HasDependents": {
+			reason: "We should deny deleting a package that other packages depend on, and list them.",
+			args: args{
+				client:  &test.MockClient{MockGet: lock},
+				request: del(v1.ProviderKind, provider(nil)),
+			},
+			want: want{
+				resp: admission.Denied(`Cannot delete Provider "provider-nop" because 1 package(s) depend on it: Configuration xpkg.upbound.io/acme/config-nop. Delete the dependent packages first, or set the pkg.crossplane.io/force-delete annotation to "true" to delete it anyway.`),
+			},
+		},
+		"AlternativeInstalled": {
+			reason: "We should allow deleting a package if another alternative that satisfies the dependency is installed.",
+			args: args{
+				client:  kube(append(providers("provider-nop", "provider-alt"), config(nil, alternatives("provider-nop", "provider-alt"))), nil),
+				request: del(v1.ProviderKind, provider(nil)),
+			},
+			want: want{
+				resp: admission.Allowed(""),
+			},
+		},
+		"OnlyAlternativeInstalled": {
+			reason: "We should deny deleting a package that is the only installed alternative that satisfies a dependency.",
+			args: args{
+				client:  kube(append(providers("provider-nop"), config(nil, alternatives("provider-other", "provider-nop"))), nil),
+				request: del(v1.ProviderKind, provider(nil)),
+			},
+			want: want{
+				resp: admission.Denied(`Cannot delete Provider "provider-nop" because 1 package(s) depend on it: Configuration xpkg.upbound.io/acme/config-nop. Delete the dependent packages first, or set the pkg.crossplane.io/force-delete annotation to "true" to delete it anyway.`),
+			},
+		},
+		"OnlyPackageDefiningAPI": {
+			reason: "We should deny deleting the only package that defines an API another package depends on.",
+			args: args{
+				client:  kube(append(providers("provider-nop"), config([]string{"nop.example.org/v1"})), map[string]string{"provider-nop-abc123": "nop.example.org/v1"}),
+				request: del(v1.ProviderKind, provider(nil)),
+			},
+			want: want{
+				resp: admission.Denied(`Cannot delete Provider "provider-nop" because 1 package(s) depend on it: Configuration xpkg.upbound.io/acme/config-nop. Delete the dependent packages first, or set the pkg.crossplane.io/force-delete annotation to "true" to delete it anyway.`),
+			},
+		},
+		"APIDefinedElsewhere": {
+			reason: "We should allow deleting a package that defines an API another package depends on if another package defines it too.",
+			args: args{
+				client: kube(append(providers("provider-nop", "provider-alt"), config([]string{"nop.example.org/v1"})), map[string]string{
+					"provider-nop-abc123": "nop.example.org/v1",
+					"provider-alt-abc123": "nop.example.org/v1",
+				}),
+				request: del(v1.ProviderKind, provider(nil)),
+			},
+			want: want{
+				resp: admission.Allowed(""),
+			},
+		},
+		"ForceDelete": {
+			reason: "We should allow deleting a package that other packages depend on if the force annotation is set.",
+			args: args{
+				request: del(v1.ProviderKind, provider(map[string]string{v1.AnnotationForceDelete: "true"})),
+			},
+			want: want{
+				resp: admission.Allowed(""),
+			},
+		},
+		"NoDependents": {
+			reason: "We should allow deleting a package that no other package depends on.",
+			args: args{
+				client: &test.MockClient{MockGet: lock},
+				request: del(v1.ConfigurationKind, func() *v1.Configuration {
+					c := &v1.Configuration{ObjectMeta: metav1.ObjectMeta{Name: "config-nop"}}
+					c.SetCurrentRevision("config-nop-abc123")
+					return c
+				}()),
+			},
+			want: want{
+				resp: admission.Allowed(""),
+			},
+		},
+		"NoCurrentRevision": {
+			reason: "We should allow deleting a package that was never added to the lock.",
+			args: args{
+				request: del(v1.FunctionKind, &v1.Function{}),
+			},
+			want: want{
+				resp: admission.Allowed(""),
+			},
+		},
+		"GetLockError": {
+			reason: "We should return an error if we can't get the lock.",
+			args: args{
+				client:  &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				request: del(v1.ProviderKind, provider(nil)),
+			},
+			want: want{
+				resp: admission.Errored(http.StatusInternalServerError, errors.Wrap(errBoom, errGetLock)),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := NewHandler(tc.args.client)
+			got := h.Handle(context.Background(), tc.args.request)
+			if diff := cmp.Diff(tc.want.resp, got); diff != "" {
+				t.Errorf("%s\nHandle(...): -want response, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -54,6 +54,13 @@ type ControllerEngine struct {
 	// the above TrackingInformers.
 	client client.Client
 
+	// The informers and client used to read and watch composed resources. By
+	// default these are the above informers and client. They may instead be
+	// backed by a cache that only contains composed resources, which uses far
+	// less memory than caching every resource of each composed kind.
+	composedInfs   TrackingInformers
+	composedClient client.Client
+
 	log logging.Logger
 
 	// Protects everything below.
@@ -72,11 +79,13 @@ type TrackingInformers interface {
 // New creates a new controller engine.
 func New(mgr manager.Manager, infs TrackingInformers, c client.Client, o ...ControllerEngineOption) *ControllerEngine {
 	e := &ControllerEngine{
-		mgr:         mgr,
-		infs:        infs,
-		client:      c,
-		log:         logging.NewNopLogger(),
-		controllers: make(map[string]*controller),
+		mgr:            mgr,
+		infs:           infs,
+		client:         c,
+		composedInfs:   infs,
+		composedClient: c,
+		log:            logging.NewNopLogger(),
+		controllers:    make(map[string]*controller),
 	}
 
 	for _, fn := range o {
@@ -96,6 +105,16 @@ func WithLogger(l logging.Logger) ControllerEngineOption {
 	}
 }
 
+// WithComposedResourceCache configures an Engine to read and watch composed
+// resources using the supplied informers and client. The client must be backed
+// by the supplied informers. The engine must have exclusive use of them.
+func WithComposedResourceCache(infs TrackingInformers, c client.Client) ControllerEngineOption {
+	return func(e *ControllerEngine) {
+		e.composedInfs = infs
+		e.composedClient = c
+	}
+}
+
 type controller struct {
 	// The running controller.
 	ctrl kcontroller.Controller
@@ -156,6 +175,13 @@ func (e *ControllerEngine) GetClient() client.Client {
 	return e.client
 }
 
+// GetComposedResourceClient gets a client that reads composed resources from
+// the controller engine's composed resource cache. The client may not be able
+// to read resources that aren't composed resources.
+func (e *ControllerEngine) GetComposedResourceClient() client.Client {
+	return e.composedClient
+}
+
 // GetFieldIndexer returns a FieldIndexer that can be used to add indexes to the
 // controller engine's cache.
 func (e *ControllerEngine) GetFieldIndexer() client.FieldIndexer {
@@ -341,10 +367,13 @@ func (e *ControllerEngine) StartWatches(name string, ws ...Watch) error {
 	// informer is active. We wouldn't start a watch when we should. If the
 	// controller calls StartWatches repeatedly (e.g. an XR controller) this
 	// will eventually self-correct.
-	a := e.infs.ActiveInformers()
-	activeInformer := make(map[schema.GroupVersionKind]bool, len(a))
-	for _, gvk := range a {
-		activeInformer[gvk] = true
+	active := activeInformers(e.infs)
+	activeComposed := activeInformers(e.composedInfs)
+	activeInformer := func(wid WatchID) bool {
+		if wid.Type == WatchTypeComposedResource {
+			return activeComposed[wid.GVK]
+		}
+		return active[wid.GVK]
 	}
 
 	// Some controllers will call StartWatches on every reconcile. Most calls
@@ -358,7 +387,7 @@ func (e *ControllerEngine) StartWatches(name string, ws ...Watch) error {
 		wid := WatchID{Type: w.wt, GVK: gvks[i]}
 		// We've already created this watch and the informer backing it is still
 		// running. We don't need to create a new watch.
-		if _, watchExists := c.sources[wid]; watchExists && activeInformer[wid.GVK] {
+		if _, watchExists := c.sources[wid]; watchExists && activeInformer(wid) {
 			e.log.Debug("Watch exists for GVK, not starting a new one", "controller", name, "watch-type", wid.Type, "watched-gvk", wid.GVK)
 			continue
 		}
@@ -387,7 +416,7 @@ func (e *ControllerEngine) StartWatches(name string, ws ...Watch) error {
 		// running. We don't need to create a new watch. We don't debug log this
 		// one - we'll have logged it above unless the watch was added between
 		// releasing the read lock and taking the write lock.
-		if _, watchExists := c.sources[wid]; watchExists && activeInformer[wid.GVK] {
+		if _, watchExists := c.sources[wid]; watchExists && activeInformer(wid) {
 			continue
 		}
 
@@ -399,7 +428,7 @@ func (e *ControllerEngine) StartWatches(name string, ws ...Watch) error {
 		// The watch will stop sending events when either the source is stopped,
 		// or its backing informer is stopped. The controller's work queue will
 		// stop processing events when the controller is stopped.
-		src := NewStoppableSource(e.infs, w.kind, w.handler, w.predicates...)
+		src := NewStoppableSource(e.informersFor(w.wt), w.kind, w.handler, w.predicates...)
 		if err := c.ctrl.Watch(src); err != nil {
 			return errors.Wrapf(err, "cannot start %q watch for %q", wid.Type, wid.GVK)
 		}
@@ -413,6 +442,24 @@ func (e *ControllerEngine) StartWatches(name string, ws ...Watch) error {
 	return nil
 }
 
+// informersFor returns the informers that back watches of the supplied type.
+func (e *ControllerEngine) informersFor(wt WatchType) TrackingInformers {
+	if wt == WatchTypeComposedResource {
+		return e.composedInfs
+	}
+	return e.infs
+}
+
+// activeInformers returns the GVKs of the supplied informers that are active.
+func activeInformers(infs TrackingInformers) map[schema.GroupVersionKind]bool {
+	a := infs.ActiveInformers()
+	active := make(map[schema.GroupVersionKind]bool, len(a))
+	for _, gvk := range a {
+		active[gvk] = true
+	}
+	return active
+}
+
 // GetWatches returns the active watches for the supplied controller.
 func (e *ControllerEngine) GetWatches(name string) ([]WatchID, error) {
 	e.mx.RLock()
@@ -520,6 +567,13 @@ func (e *ControllerEngine) GarbageCollectCustomResourceInformers(ctx context.Con
 					continue
 				}
 
+				if e.composedInfs != e.infs {
+					if err := e.composedInfs.RemoveInformer(ctx, u); err != nil {
+						e.log.Info("Cannot remove composed resource informer for type defined by deleted CustomResourceDefinition", "crd", crd.GetName(), "gvk", gvk)
+						continue
+					}
+				}
+
 				e.log.Debug("Removed informer for type defined by deleted CustomResourceDefinition", "crd", crd.GetName(), "gvk", gvk)
 			}
 		},
@@ -446,6 +446,10 @@ func TestStopController(t *testing.T) {
 }
 
 func TestStartWatches(t *testing.T) {
+	composedInfs := &MockTrackingInformers{
+		MockActiveInformers: func() []schema.GroupVersionKind { return nil },
+	}
+
 	type params struct {
 		mgr  manager.Manager
 		infs TrackingInformers
@@ -613,6 +617,86 @@ func TestStartWatches(t *testing.T) {
 				},
 			},
 		},
+		"SuccessfulStartComposedResourceWatches": {
+			reason: "StartWatches should use the composed resource informers to watch composed resources, and the engine's informers to watch everything else.",
+			params: params{
+				mgr: &MockManager{
+					MockElected: func() <-chan struct{} {
+						e := make(chan struct{})
+						close(e)
+						return e
+					},
+					MockGetScheme: runtime.NewScheme,
+				},
+				infs: &MockTrackingInformers{
+					MockActiveInformers: func() []schema.GroupVersionKind { return nil },
+				},
+				opts: []ControllerEngineOption{WithComposedResourceCache(composedInfs, nil)},
+			},
+			argsStart: argsStart{
+				name: "cool-controller",
+				opts: []ControllerOption{
+					WithNewControllerFn(func(_ string, _ manager.Manager, _ kcontroller.Options) (kcontroller.Controller, error) {
+						return &MockController{
+							MockStart: func(ctx context.Context) error {
+								<-ctx.Done()
+								return nil
+							},
+							MockWatch: func(src source.Source) error {
+								s, ok := src.(*StoppableSource)
+								if !ok {
+									return errors.New("source is not a StoppableSource")
+								}
+								// Only the composite resource kind is watched
+								// using the engine's informers.
+								if composed := s.infs == composedInfs; composed != (s.Type.GetObjectKind().GroupVersionKind().Kind == "Resource") {
+									return fmt.Errorf("watch of %s used the wrong informers", s.Type.GetObjectKind().GroupVersionKind())
+								}
+								return nil
+							},
+						}, nil
+					}),
+				},
+			},
+			args: args{
+				name: "cool-controller",
+				ws: []Watch{
+					func() Watch {
+						u := &unstructured.Unstructured{}
+						u.SetAPIVersion("test.crossplane.io/v1")
+						u.SetKind("Resource")
+						return WatchFor(u, WatchTypeComposedResource, nil)
+					}(),
+					func() Watch {
+						u := &unstructured.Unstructured{}
+						u.SetAPIVersion("test.crossplane.io/v1")
+						u.SetKind("Composite")
+						return WatchFor(u, WatchTypeCompositeResource, nil)
+					}(),
+				},
+			},
+			want: want{
+				err: nil,
+				watches: []WatchID{
+					{
+						Type: WatchTypeComposedResource,
+						GVK: schema.GroupVersionKind{
+							Group:   "test.crossplane.io",
+							Version: "v1",
+							Kind:    "Resource",
+						},
+					},
+					{
+						Type: WatchTypeCompositeResource,
+						GVK: schema.GroupVersionKind{
+							Group:   "test.crossplane.io",
+							Version: "v1",
+							Kind:    "Composite",
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -45,6 +45,28 @@ const (
 	// the claim controller. See the below issue for more details:
 	// https://github.com/crossplane/crossplane/issues/4581
 	EnableAlphaClaimSSA feature.Flag = "EnableAlphaClaimSSA"
+
+	// EnableAlphaSignatureVerification enables alpha support for verifying
+	// the cosign signatures of package images using ImageConfigs.
+	EnableAlphaSignatureVerification feature.Flag = "EnableAlphaSignatureVerification"
+
+	// EnableAlphaVulnerabilityScanning enables alpha support for scanning
+	// package images for vulnerabilities using ImageConfigs.
+	EnableAlphaVulnerabilityScanning feature.Flag = "EnableAlphaVulnerabilityScanning"
+
+	// EnableAlphaCompositionRevisionOverrides enables alpha support for
+	// pinning composite resources and claims to a CompositionRevision using
+	// CompositionRevisionOverrides.
+	EnableAlphaCompositionRevisionOverrides feature.Flag = "EnableAlphaCompositionRevisionOverrides"
+
+	// EnableAlphaPackageUpgrades enables alpha support for upgrading several
+	// packages together using PackageUpgrades, and rolling them all back if
+	// any fails to become healthy.
+	EnableAlphaPackageUpgrades feature.Flag = "EnableAlphaPackageUpgrades"
+
+	// EnableAlphaDependencyPlans enables alpha support for planning what
+	// installing a package would install using DependencyPlans.
+	EnableAlphaDependencyPlans feature.Flag = "EnableAlphaDependencyPlans"
 )
 
 // Beta Feature Flags.
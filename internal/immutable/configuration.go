@@ -0,0 +1,124 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immutable
+
+import (
+	"context"
+
+	admv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+const (
+	// coreWebhookConfiguration is the name of the ValidatingWebhookConfiguration
+	// Crossplane's init container applies. Its client configuration is used
+	// to reach the webhook server.
+	coreWebhookConfiguration = "crossplane"
+
+	// webhookName is the name of the webhook in each configuration.
+	webhookName = "immutable-fields.apiextensions.crossplane.io"
+
+	// namePrefix prefixes the name of a CompositeResourceDefinition's
+	// ValidatingWebhookConfiguration.
+	namePrefix = "crossplane-immutable-fields-"
+)
+
+const (
+	errGetCoreConfiguration = "cannot get Crossplane's validating webhook configuration"
+	errNoCoreWebhooks       = "Crossplane's validating webhook configuration has no webhooks"
+	errGetConfiguration     = "cannot get immutable fields validating webhook configuration"
+	errApplyConfiguration   = "cannot apply immutable fields validating webhook configuration"
+	errDeleteConfiguration  = "cannot delete immutable fields validating webhook configuration"
+)
+
+// A WebhookConfigurator configures a ValidatingWebhookConfiguration that
+// routes updates of a CompositeResourceDefinition's composite resources and
+// claims to the immutable fields webhook.
+type WebhookConfigurator struct {
+	client resource.ClientApplicator
+}
+
+// NewWebhookConfigurator returns a WebhookConfigurator.
+func NewWebhookConfigurator(c client.Client) *WebhookConfigurator {
+	return &WebhookConfigurator{client: resource.ClientApplicator{Client: c, Applicator: resource.NewAPIUpdatingApplicator(c)}}
+}
+
+// Configure the ValidatingWebhookConfiguration for the supplied
+// CompositeResourceDefinition. The configuration is deleted if the definition
+// has no immutable fields. It's controlled by the definition, so it's garbage
+// collected when the definition is deleted.
+func (c *WebhookConfigurator) Configure(ctx context.Context, d *v1.CompositeResourceDefinition) error {
+	vwc := &admv1.ValidatingWebhookConfiguration{}
+	vwc.SetName(namePrefix + d.GetName())
+
+	if len(d.Spec.ImmutableFields) == 0 {
+		err := c.client.Get(ctx, types.NamespacedName{Name: vwc.GetName()}, vwc)
+		if resource.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, errGetConfiguration)
+		}
+		if err != nil || !metav1.IsControlledBy(vwc, d) {
+			return nil
+		}
+		return errors.Wrap(resource.IgnoreNotFound(c.client.Delete(ctx, vwc)), errDeleteConfiguration)
+	}
+
+	core := &admv1.ValidatingWebhookConfiguration{}
+	if err := c.client.Get(ctx, types.NamespacedName{Name: coreWebhookConfiguration}, core); err != nil {
+		return errors.Wrap(err, errGetCoreConfiguration)
+	}
+	if len(core.Webhooks) == 0 {
+		return errors.New(errNoCoreWebhooks)
+	}
+
+	cc := *core.Webhooks[0].ClientConfig.DeepCopy()
+	if cc.Service != nil {
+		cc.Service.Path = ptr.To(Path)
+	}
+
+	resources := []string{d.Spec.Names.Plural}
+	if d.Spec.ClaimNames != nil {
+		resources = append(resources, d.Spec.ClaimNames.Plural)
+	}
+
+	meta.AddOwnerReference(vwc, meta.AsController(meta.TypedReferenceTo(d, v1.CompositeResourceDefinitionGroupVersionKind)))
+	vwc.Webhooks = []admv1.ValidatingWebhook{{
+		Name:                    webhookName,
+		AdmissionReviewVersions: []string{"v1"},
+		ClientConfig:            cc,
+		FailurePolicy:           ptr.To(admv1.Fail),
+		SideEffects:             ptr.To(admv1.SideEffectClassNone),
+		Rules: []admv1.RuleWithOperations{{
+			Operations: []admv1.OperationType{admv1.Update},
+			Rule: admv1.Rule{
+				APIGroups:   []string{d.Spec.Group},
+				APIVersions: []string{"*"},
+				Resources:   resources,
+			},
+		}},
+	}}
+
+	return errors.Wrap(c.client.Apply(ctx, vwc, resource.MustBeControllableBy(d.GetUID())), errApplyConfiguration)
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package immutable contains the Handler for the webhook that enforces the
+// immutable fields of composite resources and claims.
+package immutable
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+// Path at which the webhook is served.
+const Path = "/validate-immutable-fields"
+
+const (
+	errFmtUnexpectedOp = "unexpected operation %q, expected \"UPDATE\""
+	errListXRDs        = "cannot list composite resource definitions"
+	errDecodeObject    = "cannot decode object"
+	errDecodeOldObject = "cannot decode old object"
+	errFmtGetField     = "cannot get immutable field %q"
+)
+
+// SetupWebhookWithManager sets up the webhook with the manager.
+func SetupWebhookWithManager(mgr ctrl.Manager, options controller.Options) error {
+	mgr.GetWebhookServer().Register(Path,
+		&webhook.Admission{Handler: NewHandler(mgr.GetClient(), WithLogger(options.Logger.WithValues("webhook", "immutable-fields")))})
+	return nil
+}
+
+// Handler implements the admission Handler for immutable fields.
+type Handler struct {
+	client client.Reader
+	log    logging.Logger
+}
+
+// HandlerOption is used to configure the Handler.
+type HandlerOption func(*Handler)
+
+// WithLogger configures the logger for the Handler.
+func WithLogger(l logging.Logger) HandlerOption {
+	return func(h *Handler) {
+		h.log = l
+	}
+}
+
+// NewHandler returns a new Handler.
+func NewHandler(c client.Reader, opts ...HandlerOption) *Handler {
+	h := &Handler{
+		client: c,
+		log:    logging.NewNopLogger(),
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// Handle handles the admission request, validating that the update doesn't
+// change any of the immutable fields declared by the CompositeResourceDefinition
+// that defines the composite resource or claim.
+func (h *Handler) Handle(ctx context.Context, request admission.Request) admission.Response {
+	if request.Operation != admissionv1.Update {
+		return admission.Errored(http.StatusBadRequest, errors.Errorf(errFmtUnexpectedOp, request.Operation))
+	}
+
+	l := &v1.CompositeResourceDefinitionList{}
+	if err := h.client.List(ctx, l); err != nil {
+		return admission.Errored(http.StatusInternalServerError, errors.Wrap(err, errListXRDs))
+	}
+	d := definitionFor(l.Items, request.Resource.Group, request.Resource.Resource)
+	if d == nil || len(d.Spec.ImmutableFields) == 0 {
+		return admission.Allowed("")
+	}
+
+	obj := map[string]any{}
+	if err := json.Unmarshal(request.Object.Raw, &obj); err != nil {
+		return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errDecodeObject))
+	}
+	old := map[string]any{}
+	if err := json.Unmarshal(request.OldObject.Raw, &old); err != nil {
+		return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errDecodeOldObject))
+	}
+
+	changed, err := ChangedFields(d.Spec.ImmutableFields, old, obj)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if len(changed) > 0 {
+		h.log.Debug("Immutable fields changed, update not allowed", "kind", request.Kind.Kind, "name", request.Name, "namespace", request.Namespace, "fields", changed)
+		return admission.Denied(fmt.Sprintf("Cannot change immutable fields: %s", strings.Join(changed, ", ")))
+	}
+	return admission.Allowed("")
+}
+
+// ChangedFields returns the supplied field paths whose values differ between
+// the old and new objects. Setting or unsetting a field counts as a change.
+func ChangedFields(paths []string, old, obj map[string]any) ([]string, error) {
+	op := fieldpath.Pave(old)
+	np := fieldpath.Pave(obj)
+
+	var changed []string
+	for _, p := range paths {
+		ov, err := op.GetValue(p)
+		if err != nil && !fieldpath.IsNotFound(err) {
+			return nil, errors.Wrapf(err, errFmtGetField, p)
+		}
+		nv, err := np.GetValue(p)
+		if err != nil && !fieldpath.IsNotFound(err) {
+			return nil, errors.Wrapf(err, errFmtGetField, p)
+		}
+		if !reflect.DeepEqual(ov, nv) {
+			changed = append(changed, p)
+		}
+	}
+	return changed, nil
+}
+
+// definitionFor returns the CompositeResourceDefinition that defines the
+// supplied resource as a composite resource or claim, or nil if none does.
+func definitionFor(xrds []v1.CompositeResourceDefinition, group, resource string) *v1.CompositeResourceDefinition {
+	for i := range xrds {
+		d := &xrds[i]
+		if d.Spec.Group != group {
+			continue
+		}
+		if d.Spec.Names.Plural == resource {
+			return d
+		}
+		if d.Spec.ClaimNames != nil && d.Spec.ClaimNames.Plural == resource {
+			return d
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immutable
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionv1 "k8s.io/api/admission/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+var _ admission.Handler = &Handler{}
+
+func TestHandle(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	list := func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+		obj.(*v1.CompositeResourceDefinitionList).Items = []v1.CompositeResourceDefinition{
+			{
+				Spec: v1.CompositeResourceDefinitionSpec{
+					Group:           "example.org",
+					Names:           extv1.CustomResourceDefinitionNames{Plural: "xdatabases"},
+					ClaimNames:      &extv1.CustomResourceDefinitionNames{Plural: "databases"},
+					ImmutableFields: []string{"spec.parameters.region", "spec.parameters.engine"},
+				},
+			},
+			{
+				Spec: v1.CompositeResourceDefinitionSpec{
+					Group: "example.org",
+					Names: extv1.CustomResourceDefinitionNames{Plural: "xbuckets"},
+				},
+			},
+		}
+		return nil
+	}
+
+	update := func(resource, old, obj string) admission.Request {
+		return admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Resource:  metav1.GroupVersionResource{Group: "example.org", Version: "v1", Resource: resource},
+				Object:    runtime.RawExtension{Raw: []byte(obj)},
+				OldObject: runtime.RawExtension{Raw: []byte(old)},
+			},
+		}
+	}
+
+	type args struct {
+		client  client.Reader
+		request admission.Request
+	}
+	type want struct {
+		resp admission.Response
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"UnexpectedCreate": {
+			reason: "We should return an error if the request is a create (not an update).",
+			args: args{
+				request: admission.Request{
+					AdmissionRequest: admissionv1.AdmissionRequest{
+						Operation: admissionv1.Create,
+					},
+				},
+			},
+			want: want{
+				resp: admission.Errored(http.StatusBadRequest, errors.Errorf(errFmtUnexpectedOp, admissionv1.Create)),
+			},
+		},
+		"ListError": {
+			reason: "We should return an error if we can't list composite resource definitions.",
+			args: args{
+				client:  &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				request: update("xdatabases", `{}`, `{}`),
+			},
+			want: want{
+				resp: admission.Errored(http.StatusInternalServerError, errors.Wrap(errBoom, errListXRDs)),
+			},
+		},
+		"NoImmutableFields": {
+			reason: "We should allow updates to resources whose definition has no immutable fields.",
+			args: args{
+				client:  &test.MockClient{MockList: list},
+				request: update("xbuckets", `{"spec":{"region":"us-east-1"}}`, `{"spec":{"region":"us-west-2"}}`),
+			},
+			want: want{
+				resp: admission.Allowed(""),
+			},
+		},
+		"MutableFieldChanged": {
+			reason: "We should allow updates that only change mutable fields.",
+			args: args{
+				client:  &test.MockClient{MockList: list},
+				request: update("xdatabases", `{"spec":{"parameters":{"region":"us-east-1","size":1}}}`, `{"spec":{"parameters":{"region":"us-east-1","size":2}}}`),
+			},
+			want: want{
+				resp: admission.Allowed(""),
+			},
+		},
+		"ClaimImmutableFieldChanged": {
+			reason: "We should deny updates to a claim that change or set its definition's immutable fields.",
+			args: args{
+				client:  &test.MockClient{MockList: list},
+				request: update("databases", `{"spec":{"parameters":{"region":"us-east-1"}}}`, `{"spec":{"parameters":{"region":"us-west-2","engine":"postgres"}}}`),
+			},
+			want: want{
+				resp: admission.Denied("Cannot change immutable fields: spec.parameters.region, spec.parameters.engine"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := NewHandler(tc.args.client)
+			got := h.Handle(context.Background(), tc.args.request)
+			if diff := cmp.Diff(tc.want.resp, got); diff != "" {
+				t.Errorf("%s\nHandle(...): -want response, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
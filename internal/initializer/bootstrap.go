@@ -0,0 +1,198 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package initializer
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/parser"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/pkg/xclient"
+)
+
+const (
+	errInitBootstrapFs      = "cannot init bootstrap manifest filesystem"
+	errParseBootstrap       = "cannot parse bootstrap manifests"
+	errFmtNotBootstrapObj   = "bootstrap manifest of type %T is not a Kubernetes object"
+	errFmtApplyBootstrapObj = "cannot apply bootstrap manifest %s %q"
+	errFmtGetBootstrapPkg   = "cannot get bootstrap package %q"
+)
+
+// defaultBootstrapPollInterval is how often bootstrap manifests are applied
+// and checked by default, until they're all applied and healthy.
+const defaultBootstrapPollInterval = 10 * time.Second
+
+// BootstrapManifestsOption configures BootstrapManifests.
+type BootstrapManifestsOption func(*BootstrapManifests)
+
+// WithBootstrapFs is used to configure the filesystem the manifests will be
+// read from. Its default is afero.OsFs.
+func WithBootstrapFs(fs afero.Fs) BootstrapManifestsOption {
+	return func(b *BootstrapManifests) {
+		b.fs = fs
+	}
+}
+
+// WithBootstrapLogger specifies how BootstrapManifests should log.
+func WithBootstrapLogger(l logging.Logger) BootstrapManifestsOption {
+	return func(b *BootstrapManifests) {
+		b.log = l
+	}
+}
+
+// WithBootstrapPollInterval specifies how often BootstrapManifests retries
+// applying its manifests, and checks whether its packages are healthy.
+func WithBootstrapPollInterval(d time.Duration) BootstrapManifestsOption {
+	return func(b *BootstrapManifests) {
+		b.interval = d
+	}
+}
+
+// NewBootstrapManifests returns a new *BootstrapManifests.
+func NewBootstrapManifests(path string, s *runtime.Scheme, opts ...BootstrapManifestsOption) *BootstrapManifests {
+	b := &BootstrapManifests{
+		Path:     path,
+		Scheme:   s,
+		fs:       afero.NewOsFs(),
+		log:      logging.NewNopLogger(),
+		interval: defaultBootstrapPollInterval,
+	}
+	for _, f := range opts {
+		f(b)
+	}
+	return b
+}
+
+// BootstrapManifests makes sure the objects in a directory, for example a
+// mounted ConfigMap of Packages, StoreConfigs, and DeploymentRuntimeConfigs,
+// exist and that any packages among them are healthy. It allows a cluster to
+// be bootstrapped declaratively, without ordering the objects externally.
+type BootstrapManifests struct {
+	Path   string
+	Scheme *runtime.Scheme
+
+	fs       afero.Fs
+	log      logging.Logger
+	interval time.Duration
+}
+
+// Run applies all manifests in the given directory. Manifests that can't be
+// applied, for example because they reference a type that isn't established
+// yet, are retried until they're all applied and every package among them is
+// healthy, or the supplied context is done.
+func (b *BootstrapManifests) Run(ctx context.Context, kube client.Client) error {
+	objs, err := b.parse(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = wait.PollUntilContextCancel(ctx, b.interval, true, func(ctx context.Context) (bool, error) {
+		if err := b.apply(ctx, kube, objs); err != nil {
+			b.log.Info("Cannot apply bootstrap manifests, retrying", "error", err, "poll-interval", b.interval)
+			return false, nil
+		}
+		pending, err := unhealthy(ctx, kube, objs)
+		if err != nil {
+			b.log.Info("Cannot check bootstrap packages, retrying", "error", err, "poll-interval", b.interval)
+			return false, nil
+		}
+		if len(pending) > 0 {
+			b.log.Debug("Waiting for bootstrap packages to become healthy", "packages", pending, "poll-interval", b.interval)
+			return false, nil
+		}
+		return true, nil
+	})
+	// We only stop before we're done if Crossplane is shutting down.
+	if err != nil {
+		return nil
+	}
+	b.log.Info("Bootstrap manifests have been applied", "path", b.Path, "count", len(objs))
+	return nil
+}
+
+func (b *BootstrapManifests) parse(ctx context.Context) ([]client.Object, error) {
+	r, err := parser.NewFsBackend(b.fs,
+		parser.FsDir(b.Path),
+		parser.FsFilters(
+			parser.SkipDirs(),
+			parser.SkipNotYAML(),
+			parser.SkipEmpty(),
+			// A mounted ConfigMap's files are symlinks into a hidden,
+			// timestamped directory. Don't parse them twice.
+			parser.SkipPath(filepath.Join(b.Path, "..*", "*")),
+		),
+	).Init(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, errInitBootstrapFs)
+	}
+	defer func() { _ = r.Close() }()
+	pkg, err := parser.New(runtime.NewScheme(), b.Scheme).Parse(ctx, r)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseBootstrap)
+	}
+	objs := make([]client.Object, 0, len(pkg.GetObjects()))
+	for _, o := range pkg.GetObjects() {
+		co, ok := o.(client.Object)
+		if !ok {
+			return nil, errors.Errorf(errFmtNotBootstrapObj, o)
+		}
+		objs = append(objs, co)
+	}
+	return objs, nil
+}
+
+func (b *BootstrapManifests) apply(ctx context.Context, kube client.Client, objs []client.Object) error {
+	pa := resource.NewAPIPatchingApplicator(kube)
+	for _, o := range objs {
+		// Apply a copy, so that each attempt applies the manifest as it was
+		// written rather than as it was last read from the API server.
+		if err := pa.Apply(ctx, o.DeepCopyObject().(client.Object)); err != nil {
+			return errors.Wrapf(err, errFmtApplyBootstrapObj, o.GetObjectKind().GroupVersionKind().Kind, o.GetName())
+		}
+	}
+	return nil
+}
+
+// unhealthy returns the names of the supplied packages that aren't installed
+// and healthy. Objects that aren't packages are ignored.
+func unhealthy(ctx context.Context, kube client.Client, objs []client.Object) ([]string, error) {
+	var pending []string
+	for _, o := range objs {
+		p, ok := o.DeepCopyObject().(v1.Package)
+		if !ok {
+			continue
+		}
+		if err := kube.Get(ctx, client.ObjectKeyFromObject(p), p); err != nil {
+			return nil, errors.Wrapf(err, errFmtGetBootstrapPkg, p.GetName())
+		}
+		if !xclient.IsPackageHealthy(p) {
+			pending = append(pending, p.GetName())
+		}
+	}
+	return pending, nil
+}
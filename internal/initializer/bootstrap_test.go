@@ -0,0 +1,159 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package initializer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/spf13/afero"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+const bootstrapManifests = `
+apiVersion: pkg.crossplane.io/v1beta1
+kind: DeploymentRuntimeConfig
+metadata:
+  name: debug
+---
+apiVersion: pkg.crossplane.io/v1
+kind: Provider
+metadata:
+  name: provider-nop
+spec:
+  package: xpkg.upbound.io/crossplane-contrib/provider-nop:v0.2.1
+`
+
+func TestBootstrapManifests(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	s := runtime.NewScheme()
+	_ = v1.AddToScheme(s)
+	_ = v1beta1.AddToScheme(s)
+
+	fs := afero.NewMemMapFs()
+	_ = afero.WriteFile(fs, "/bootstrap/bootstrap.yaml", []byte(bootstrapManifests), 0o600)
+	// Mounted ConfigMaps contain a hidden copy of each file.
+	_ = afero.WriteFile(fs, "/bootstrap/..2024_01_01_00_00_00.000000000/bootstrap.yaml", []byte(bootstrapManifests), 0o600)
+
+	exists := map[string]bool{}
+
+	broken := afero.NewMemMapFs()
+	_ = afero.WriteFile(broken, "/bootstrap/broken.yaml", []byte("apiVersion: example.org/v1\nkind: Unknown\n"), 0o600)
+
+	type args struct {
+		fs   afero.Fs
+		kube *test.MockClient
+	}
+	type want struct {
+		err     error
+		applied int
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ParseError": {
+			reason: "We should return an error if the manifests can't be parsed.",
+			args: args{
+				fs:   broken,
+				kube: &test.MockClient{},
+			},
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
+		"RetryUntilCanceled": {
+			reason: "We should keep retrying, and return without error when the context is done, if the manifests can't be applied.",
+			args: args{
+				fs: fs,
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				},
+			},
+		},
+		"WaitUntilHealthy": {
+			reason: "We should apply each manifest once, ignoring hidden copies, and return once every package is healthy.",
+			args: args{
+				fs: fs,
+				kube: &test.MockClient{
+					MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+						if !exists[key.Name] {
+							return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+						}
+						if p, ok := obj.(*v1.Provider); ok {
+							p.SetConditions(v1.Active(), v1.Healthy())
+						}
+						return nil
+					},
+					MockCreate: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+						exists[obj.GetName()] = true
+						return nil
+					},
+					MockPatch: test.NewMockPatchFn(errBoom),
+				},
+			},
+			want: want{
+				applied: 2,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			applied := 0
+			if mc := tc.args.kube.MockCreate; mc != nil {
+				tc.args.kube.MockCreate = func(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+					applied++
+					return mc(ctx, obj, opts...)
+				}
+			}
+			if mp := tc.args.kube.MockPatch; mp != nil {
+				tc.args.kube.MockPatch = func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					applied++
+					return mp(ctx, obj, patch, opts...)
+				}
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			b := NewBootstrapManifests("/bootstrap", s, WithBootstrapFs(tc.args.fs), WithBootstrapPollInterval(10*time.Millisecond))
+			err := b.Run(ctx, tc.args.kube)
+			if diff := cmp.Diff(tc.want.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRun(...): -want err, +got err:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.applied, applied); diff != "" {
+				t.Errorf("\n%s\nRun(...): -want applied, +got applied:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
+)
+
+// QueueMetrics records the depth of each controller's work queue, and the age
+// of the oldest item in it, as Prometheus gauges labelled by controller name.
+// An item's age is how long it has been waiting to be processed. Items that
+// are waiting out a requeue delay aren't queued until the delay has passed.
+//
+// A nil *QueueMetrics is valid. It doesn't record anything.
+type QueueMetrics struct {
+	mu     sync.Mutex
+	queues map[string]*trackedQueue
+
+	depth *prometheus.Desc
+	age   *prometheus.Desc
+}
+
+// NewQueueMetrics creates metrics for controller work queues.
+func NewQueueMetrics() *QueueMetrics {
+	labels := []string{"controller"}
+	return &QueueMetrics{
+		queues: make(map[string]*trackedQueue),
+		depth: prometheus.NewDesc(
+			"controller_queue_depth",
+			"Number of items waiting in a controller's work queue.",
+			labels, nil),
+		age: prometheus.NewDesc(
+			"controller_queue_oldest_item_age_seconds",
+			"How long in seconds the oldest item in a controller's work queue has been waiting to be processed.",
+			labels, nil),
+	}
+}
+
+// NewQueueFn returns a function that builds a rate limited controller-runtime
+// work queue on top of the base queue returned by the supplied function. The
+// base queue is tracked by the QueueMetrics until it's shut down. A default
+// work queue is used if the supplied function is nil.
+func (m *QueueMetrics) NewQueueFn(base func(name string) workqueue.Interface) func(name string, rl ratelimiter.RateLimiter) workqueue.RateLimitingInterface {
+	if base == nil {
+		base = func(name string) workqueue.Interface {
+			return workqueue.NewWithConfig(workqueue.QueueConfig{Name: name})
+		}
+	}
+	return func(name string, rl ratelimiter.RateLimiter) workqueue.RateLimitingInterface {
+		return workqueue.NewRateLimitingQueueWithConfig(rl, workqueue.RateLimitingQueueConfig{
+			Name: name,
+			DelayingQueue: workqueue.NewDelayingQueueWithConfig(workqueue.DelayingQueueConfig{
+				Name:  name,
+				Queue: m.Track(name, base(name)),
+			}),
+		})
+	}
+}
+
+// Track the supplied controller's work queue until it's shut down. A queue
+// replaces any queue already tracked for the controller, e.g. because the
+// controller was restarted.
+func (m *QueueMetrics) Track(controller string, q workqueue.Interface) workqueue.Interface {
+	if m == nil {
+		return q
+	}
+	t := &trackedQueue{Interface: q, queued: make(map[any]time.Time), now: time.Now}
+	t.stop = func() { m.forget(controller, t) }
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.queues[controller] = t
+	return t
+}
+
+func (m *QueueMetrics) forget(controller string, q *trackedQueue) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.queues[controller] == q {
+		delete(m.queues, controller)
+	}
+}
+
+// Describe sends the super-set of all possible descriptors of metrics
+// collected by this Collector to the provided channel and returns once
+// the last descriptor has been sent.
+func (m *QueueMetrics) Describe(ch chan<- *prometheus.Desc) {
+	ch <- m.depth
+	ch <- m.age
+}
+
+// Collect is called by the Prometheus registry when collecting
+// metrics. The implementation sends each collected metric via the
+// provided channel and returns once the last metric has been sent.
+func (m *QueueMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for controller, q := range m.queues {
+		ch <- prometheus.MustNewConstMetric(m.depth, prometheus.GaugeValue, float64(q.Len()), controller)
+		ch <- prometheus.MustNewConstMetric(m.age, prometheus.GaugeValue, q.OldestAge().Seconds(), controller)
+	}
+}
+
+// A trackedQueue records when each of its items was queued.
+type trackedQueue struct {
+	workqueue.Interface
+
+	mu     sync.Mutex
+	queued map[any]time.Time
+	now    func() time.Time
+	stop   func()
+}
+
+// Add marks the supplied item as needing processing. An item that is already
+// queued keeps the time it was first queued.
+func (q *trackedQueue) Add(item any) {
+	q.mu.Lock()
+	if _, ok := q.queued[item]; !ok {
+		q.queued[item] = q.now()
+	}
+	q.mu.Unlock()
+	q.Interface.Add(item)
+}
+
+// Get blocks until it can return an item to be processed.
+func (q *trackedQueue) Get() (any, bool) {
+	item, shutdown := q.Interface.Get()
+	q.mu.Lock()
+	delete(q.queued, item)
+	q.mu.Unlock()
+	return item, shutdown
+}
+
+// ShutDown the queue, and stop tracking it.
+func (q *trackedQueue) ShutDown() {
+	q.stop()
+	q.Interface.ShutDown()
+}
+
+// ShutDownWithDrain shuts down the queue once all items being processed are
+// done, and stops tracking it.
+func (q *trackedQueue) ShutDownWithDrain() {
+	q.stop()
+	q.Interface.ShutDownWithDrain()
+}
+
+// OldestAge returns how long the oldest queued item has been waiting to be
+// processed, or zero if nothing is queued.
+func (q *trackedQueue) OldestAge() time.Duration {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	now := q.now()
+	var oldest time.Duration
+	for _, t := range q.queued {
+		if age := now.Sub(t); age > oldest {
+			oldest = age
+		}
+	}
+	return oldest
+}
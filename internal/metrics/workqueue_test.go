@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/client-go/util/workqueue"
+)
+
+func TestQueueMetrics(t *testing.T) {
+	m := NewQueueMetrics()
+	q := m.Track("cool-controller", workqueue.New()).(*trackedQueue)
+
+	now := time.Unix(0, 0)
+	q.now = func() time.Time { return now }
+
+	q.Add("a")
+	now = now.Add(10 * time.Second)
+	q.Add("b")
+	q.Add("a")
+	now = now.Add(5 * time.Second)
+
+	want := `
+# HELP controller_queue_depth Number of items waiting in a controller's work queue.
+# TYPE controller_queue_depth gauge
+controller_queue_depth{controller="cool-controller"} 2
+# HELP controller_queue_oldest_item_age_seconds How long in seconds the oldest item in a controller's work queue has been waiting to be processed.
+# TYPE controller_queue_oldest_item_age_seconds gauge
+controller_queue_oldest_item_age_seconds{controller="cool-controller"} 15
+`
+	if err := testutil.CollectAndCompare(m, strings.NewReader(want)); err != nil {
+		t.Errorf("CollectAndCompare(...): %v", err)
+	}
+
+	// Getting the oldest item should leave the next oldest.
+	item, _ := q.Get()
+	if diff := cmp.Diff("a", item); diff != "" {
+		t.Errorf("Get(...): -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(5*time.Second, q.OldestAge()); diff != "" {
+		t.Errorf("OldestAge(): -want, +got:\n%s", diff)
+	}
+
+	// Adding an item while it's being processed queues it again.
+	q.Add("a")
+	q.Done("a")
+	now = now.Add(5 * time.Second)
+	if diff := cmp.Diff(10*time.Second, q.OldestAge()); diff != "" {
+		t.Errorf("OldestAge(): -want, +got:\n%s", diff)
+	}
+
+	// A queue is no longer tracked once it's shut down.
+	q.ShutDown()
+	if diff := cmp.Diff(0, testutil.CollectAndCount(m)); diff != "" {
+		t.Errorf("CollectAndCount(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestNilQueueMetrics(t *testing.T) {
+	var m *QueueMetrics
+	q := workqueue.New()
+	if m.Track("cool-controller", q) != q {
+		t.Errorf("Track(...): a nil QueueMetrics should return the supplied queue")
+	}
+}
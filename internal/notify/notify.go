@@ -0,0 +1,252 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package notify sends notifications about the lifecycle of packages and
+// composite resource definitions to external systems.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+// HTTP headers sent with each notification.
+const (
+	// HeaderSignature is the hex encoded HMAC-SHA256 of the notification's
+	// body, prefixed with "sha256=". It's only sent if a signing key is
+	// configured.
+	HeaderSignature = "X-Crossplane-Signature"
+
+	// HeaderEvent is the type of the notification's event.
+	HeaderEvent = "X-Crossplane-Event"
+)
+
+const (
+	defaultTimeout   = 10 * time.Second
+	defaultQueueSize = 100
+
+	errMarshalEvent  = "cannot marshal notification event"
+	errNewRequest    = "cannot create notification request"
+	errPost          = "cannot post notification"
+	errFmtStatusCode = "notification endpoint returned unexpected status code %d"
+)
+
+// An EventType is a type of lifecycle event.
+type EventType string
+
+// Lifecycle event types.
+const (
+	// PackageInstalled events are sent when the first revision of a package
+	// becomes healthy.
+	PackageInstalled EventType = "PackageInstalled"
+
+	// PackageUpgraded events are sent when a later revision of a package
+	// becomes healthy.
+	PackageUpgraded EventType = "PackageUpgraded"
+
+	// PackageFailed events are sent when a package's current revision
+	// becomes unhealthy.
+	PackageFailed EventType = "PackageFailed"
+
+	// CompositeResourceDefinitionEstablished events are sent when the
+	// package manager starts serving the composite resource defined by a
+	// CompositeResourceDefinition.
+	CompositeResourceDefinitionEstablished EventType = "CompositeResourceDefinitionEstablished"
+)
+
+// An Event about the lifecycle of a Crossplane object.
+type Event struct {
+	// Type of the event.
+	Type EventType `json:"type"`
+
+	// Time at which the event occurred.
+	Time time.Time `json:"time"`
+
+	// APIVersion of the object the event is about.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the object the event is about.
+	Kind string `json:"kind"`
+
+	// Name of the object the event is about.
+	Name string `json:"name"`
+
+	// Package the object was installed from, if the object is a package.
+	Package string `json:"package,omitempty"`
+
+	// Revision of the package, if the object is a package.
+	Revision string `json:"revision,omitempty"`
+
+	// Message is a human readable description of the event.
+	Message string `json:"message,omitempty"`
+}
+
+// A Notifier notifies external systems of lifecycle events.
+type Notifier interface {
+	// Notify external systems of the supplied event. Notifications are best
+	// effort; they may be delivered asynchronously or not at all.
+	Notify(ctx context.Context, e Event)
+}
+
+// A NotifierFn is a function that satisfies the Notifier interface.
+type NotifierFn func(ctx context.Context, e Event)
+
+// Notify external systems of the supplied event.
+func (fn NotifierFn) Notify(ctx context.Context, e Event) {
+	fn(ctx, e)
+}
+
+// A NopNotifier does nothing.
+type NopNotifier struct{}
+
+// Notify does nothing.
+func (NopNotifier) Notify(_ context.Context, _ Event) {}
+
+// An HTTPNotifier posts lifecycle events as JSON to HTTP endpoints. Events are
+// queued and posted by a background worker, which runs until the context
+// passed to Start is cancelled. Events are dropped if the queue is full.
+type HTTPNotifier struct {
+	endpoints []string
+	key       []byte
+	client    *http.Client
+	log       logging.Logger
+	queue     chan Event
+}
+
+// An HTTPNotifierOption configures an HTTPNotifier.
+type HTTPNotifierOption func(n *HTTPNotifier)
+
+// WithSigningKey signs each notification's body with the supplied HMAC key.
+func WithSigningKey(key []byte) HTTPNotifierOption {
+	return func(n *HTTPNotifier) {
+		n.key = key
+	}
+}
+
+// WithHTTPClient specifies the HTTP client used to post notifications.
+func WithHTTPClient(c *http.Client) HTTPNotifierOption {
+	return func(n *HTTPNotifier) {
+		n.client = c
+	}
+}
+
+// WithLogger specifies how the HTTPNotifier should log messages.
+func WithLogger(l logging.Logger) HTTPNotifierOption {
+	return func(n *HTTPNotifier) {
+		n.log = l
+	}
+}
+
+// WithQueueSize specifies how many events may be waiting to be posted before
+// new events are dropped.
+func WithQueueSize(size int) HTTPNotifierOption {
+	return func(n *HTTPNotifier) {
+		n.queue = make(chan Event, size)
+	}
+}
+
+// NewHTTPNotifier returns a Notifier that posts events to the supplied
+// endpoints.
+func NewHTTPNotifier(endpoints []string, o ...HTTPNotifierOption) *HTTPNotifier {
+	n := &HTTPNotifier{
+		endpoints: endpoints,
+		client:    &http.Client{Timeout: defaultTimeout},
+		log:       logging.NewNopLogger(),
+		queue:     make(chan Event, defaultQueueSize),
+	}
+	for _, fn := range o {
+		fn(n)
+	}
+	return n
+}
+
+// Notify queues the supplied event to be posted.
+func (n *HTTPNotifier) Notify(_ context.Context, e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	select {
+	case n.queue <- e:
+	default:
+		n.log.Info("Dropped notification because the notification queue is full", "type", e.Type, "kind", e.Kind, "name", e.Name)
+	}
+}
+
+// Start posting queued events. Blocks until the supplied context is
+// cancelled.
+func (n *HTTPNotifier) Start(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e := <-n.queue:
+			for _, ep := range n.endpoints {
+				if err := n.Post(ctx, ep, e); err != nil {
+					n.log.Info("Cannot deliver notification", "type", e.Type, "kind", e.Kind, "name", e.Name, "error", err)
+				}
+			}
+		}
+	}
+}
+
+// Post the supplied event to the supplied endpoint.
+func (n *HTTPNotifier) Post(ctx context.Context, endpoint string, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return errors.Wrap(err, errMarshalEvent)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, errNewRequest)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(e.Type))
+	if len(n.key) > 0 {
+		req.Header.Set(HeaderSignature, Sign(n.key, body))
+	}
+
+	rsp, err := n.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, errPost)
+	}
+	defer rsp.Body.Close() //nolint:errcheck // Nothing useful to do if this fails.
+
+	if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+		return errors.Errorf(errFmtStatusCode, rsp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the signature of the supplied body, i.e. its hex encoded
+// HMAC-SHA256 using the supplied key, prefixed with "sha256=". Receivers
+// should compute the same signature and compare it to the HeaderSignature
+// using a constant time comparison.
+func Sign(key, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write(body)
+	return fmt.Sprintf("sha256=%s", hex.EncodeToString(mac.Sum(nil)))
+}
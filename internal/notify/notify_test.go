@@ -0,0 +1,174 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestPost(t *testing.T) {
+	e := Event{
+		Type:       PackageInstalled,
+		Time:       time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		APIVersion: "pkg.crossplane.io/v1",
+		Kind:       "Provider",
+		Name:       "provider-nop",
+		Package:    "xpkg.upbound.io/acme/provider-nop:v1.0.0",
+		Revision:   "provider-nop-abc123",
+	}
+
+	type request struct {
+		event     string
+		signature string
+		body      Event
+	}
+	type args struct {
+		key    []byte
+		status int
+	}
+	type want struct {
+		req request
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Signed": {
+			reason: "We should post the event, signed using the supplied key.",
+			args: args{
+				key:    []byte("secret"),
+				status: http.StatusOK,
+			},
+			want: want{
+				req: request{
+					event: string(PackageInstalled),
+					body:  e,
+				},
+			},
+		},
+		"Unsigned": {
+			reason: "We should not sign the event if there is no key.",
+			args: args{
+				status: http.StatusNoContent,
+			},
+			want: want{
+				req: request{
+					event: string(PackageInstalled),
+					body:  e,
+				},
+			},
+		},
+		"UnexpectedStatusCode": {
+			reason: "We should return an error if the endpoint doesn't return a 2xx status code.",
+			args: args{
+				status: http.StatusInternalServerError,
+			},
+			want: want{
+				req: request{
+					event: string(PackageInstalled),
+					body:  e,
+				},
+				err: errors.Errorf(errFmtStatusCode, http.StatusInternalServerError),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got request
+			var body []byte
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ = io.ReadAll(r.Body)
+				_ = json.Unmarshal(body, &got.body)
+				got.event = r.Header.Get(HeaderEvent)
+				got.signature = r.Header.Get(HeaderSignature)
+				w.WriteHeader(tc.args.status)
+			}))
+			defer srv.Close()
+
+			n := NewHTTPNotifier([]string{srv.URL}, WithSigningKey(tc.args.key))
+			err := n.Post(context.Background(), srv.URL, e)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nPost(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			// The signature can only be computed from the body we received.
+			if len(tc.args.key) > 0 {
+				tc.want.req.signature = Sign(tc.args.key, body)
+			}
+			if diff := cmp.Diff(tc.want.req, got, cmp.AllowUnexported(request{})); diff != "" {
+				t.Errorf("\n%s\nPost(...): -want request, +got request:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSign(t *testing.T) {
+	// echo -n '{"type":"PackageInstalled"}' | openssl dgst -sha256 -hmac secret
+	want := "sha256=9fbc686c2bf32ad9cd075f18d106128071356a4539250d94b06f665a924ea7ed"
+	got := Sign([]byte("secret"), []byte(`{"type":"PackageInstalled"}`))
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Sign(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestHTTPNotifier(t *testing.T) {
+	got := make(chan Event, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		e := Event{}
+		_ = json.NewDecoder(r.Body).Decode(&e)
+		got <- e
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	n := NewHTTPNotifier([]string{srv.URL}, WithQueueSize(1))
+	n.Notify(ctx, Event{Type: PackageFailed, Name: "a"})
+
+	// The queue is full, so this event should be dropped.
+	n.Notify(ctx, Event{Type: PackageFailed, Name: "b"})
+
+	go n.Start(ctx) //nolint:errcheck // Start only returns nil.
+
+	select {
+	case e := <-got:
+		if diff := cmp.Diff("a", e.Name); diff != "" {
+			t.Errorf("Notify(...): -want name, +got name:\n%s", diff)
+		}
+		if e.Time.IsZero() {
+			t.Errorf("Notify(...): want event time to be set")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Notify(...): timed out waiting for notification")
+	}
+}
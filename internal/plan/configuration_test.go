@@ -0,0 +1,211 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admv1 "k8s.io/api/admissionregistration/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestConfigure(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	xrd := &v1.CompositeResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "xnopresources.example.org", UID: "xrd-uid"},
+		Spec: v1.CompositeResourceDefinitionSpec{
+			Group:      "example.org",
+			Names:      extv1.CustomResourceDefinitionNames{Kind: "XNopResource", Plural: "xnopresources"},
+			ClaimNames: &extv1.CustomResourceDefinitionNames{Kind: "NopResource", Plural: "nopresources"},
+		},
+	}
+	noClaim := xrd.DeepCopy()
+	noClaim.Spec.ClaimNames = nil
+
+	core := admv1.ValidatingWebhookConfiguration{
+		Webhooks: []admv1.ValidatingWebhook{{
+			ClientConfig: admv1.WebhookClientConfig{
+				Service:  &admv1.ServiceReference{Namespace: "crossplane-system", Name: "crossplane-webhooks"},
+				CABundle: []byte("ca"),
+			},
+		}},
+	}
+
+	want := &admv1.ValidatingWebhookConfiguration{}
+	want.SetName("crossplane-claim-plan-xnopresources.example.org")
+	meta.AddOwnerReference(want, meta.AsController(meta.TypedReferenceTo(xrd, v1.CompositeResourceDefinitionGroupVersionKind)))
+	want.Webhooks = []admv1.ValidatingWebhook{{
+		Name:                    "plan.apiextensions.crossplane.io",
+		AdmissionReviewVersions: []string{"v1"},
+		ClientConfig: admv1.WebhookClientConfig{
+			Service:  &admv1.ServiceReference{Namespace: "crossplane-system", Name: "crossplane-webhooks", Path: ptr.To(Path)},
+			CABundle: []byte("ca"),
+		},
+		FailurePolicy: ptr.To(admv1.Ignore),
+		SideEffects:   ptr.To(admv1.SideEffectClassNone),
+		MatchConditions: []admv1.MatchCondition{{
+			Name:       "dry-run",
+			Expression: "request.dryRun == true",
+		}},
+		Rules: []admv1.RuleWithOperations{{
+			Operations: []admv1.OperationType{admv1.Create, admv1.Update},
+			Rule: admv1.Rule{
+				APIGroups:   []string{"example.org"},
+				APIVersions: []string{"*"},
+				Resources:   []string{"nopresources"},
+				Scope:       ptr.To(admv1.NamespacedScope),
+			},
+		}},
+	}}
+
+	type args struct {
+		client resource.ClientApplicator
+		xrd    *v1.CompositeResourceDefinition
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"NoClaimNotFound": {
+			reason: "We should do nothing if the XRD doesn't offer a claim and there's no configuration to delete.",
+			args: args{
+				client: resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+					},
+				},
+				xrd: noClaim,
+			},
+		},
+		"NoClaimNotControlled": {
+			reason: "We shouldn't delete a configuration the XRD doesn't control.",
+			args: args{
+				client: resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+					},
+				},
+				xrd: noClaim,
+			},
+		},
+		"NoClaimDeleteError": {
+			reason: "We should return any error encountered deleting the configuration of an XRD that doesn't offer a claim.",
+			args: args{
+				client: resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							meta.AddOwnerReference(obj, meta.AsController(meta.TypedReferenceTo(xrd, v1.CompositeResourceDefinitionGroupVersionKind)))
+							return nil
+						}),
+						MockDelete: test.NewMockDeleteFn(errBoom),
+					},
+				},
+				xrd: noClaim,
+			},
+			want: errors.Wrap(errBoom, errDeleteConfiguration),
+		},
+		"GetCoreConfigurationError": {
+			reason: "We should return any error encountered getting Crossplane's configuration.",
+			args: args{
+				client: resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(errBoom),
+					},
+				},
+				xrd: xrd,
+			},
+			want: errors.Wrap(errBoom, errGetCoreConfiguration),
+		},
+		"NoCoreWebhooks": {
+			reason: "We should return an error if Crossplane's configuration has no webhooks.",
+			args: args{
+				client: resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+					},
+				},
+				xrd: xrd,
+			},
+			want: errors.New(errNoCoreWebhooks),
+		},
+		"Apply": {
+			reason: "We should apply a configuration that only matches the XRD's claims.",
+			args: args{
+				client: resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							core.DeepCopyInto(obj.(*admv1.ValidatingWebhookConfiguration))
+							return nil
+						}),
+					},
+					Applicator: resource.ApplyFn(func(_ context.Context, obj client.Object, _ ...resource.ApplyOption) error {
+						if diff := cmp.Diff(want, obj); diff != "" {
+							t.Errorf("Apply(...): -want, +got:\n%s", diff)
+						}
+						return nil
+					}),
+				},
+				xrd: xrd,
+			},
+		},
+		"ApplyError": {
+			reason: "We should return any error encountered applying the configuration.",
+			args: args{
+				client: resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							core.DeepCopyInto(obj.(*admv1.ValidatingWebhookConfiguration))
+							return nil
+						}),
+					},
+					Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+						return errBoom
+					}),
+				},
+				xrd: xrd,
+			},
+			want: errors.Wrap(errBoom, errApplyConfiguration),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &WebhookConfigurator{client: tc.args.client}
+			err := c.Configure(context.Background(), tc.args.xrd)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nConfigure(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,269 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package plan contains the Handler for the claim plan webhook. It previews
+// what a claim would compose when the claim is created or updated with a
+// server-side dry run, e.g. kubectl apply --dry-run=server.
+package plan
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/claim"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+// Path at which the webhook is served.
+const Path = "/validate-claim-plan"
+
+const (
+	errFmtUnexpectedOp  = "unexpected operation %q, expected \"CREATE\" or \"UPDATE\""
+	errListXRDs         = "cannot list CompositeResourceDefinitions"
+	errGetComposition   = "cannot get Composition"
+	errListCompositions = "cannot list Compositions"
+	errParseSelector    = "cannot parse Composition selector"
+)
+
+// Plan summaries are returned as warnings. Each should be short enough for
+// kubectl to print on one line.
+const (
+	fmtCannotPlan    = "plan: cannot preview what %s %q would compose: %s"
+	fmtNoComposition = "plan: no Composition would compose %s %q"
+	fmtComposite     = "plan: %s %q would %s using Composition %q"
+	fmtCreate        = "create a new %s"
+	fmtUpdate        = "update %s %q"
+	fmtRandom        = "plan: %d Compositions match the claim's Composition selector; one is chosen at random"
+	fmtPipeline      = "plan: composed resources are determined by the function pipeline: %s"
+	fmtResource      = "plan: + %s %q"
+	fmtInvalidBase   = "plan: + resource %d: cannot parse base: %s"
+)
+
+// SetupWebhookWithManager sets up the webhook with the manager.
+func SetupWebhookWithManager(mgr ctrl.Manager, options controller.Options, opts ...HandlerOption) error {
+	opts = append([]HandlerOption{WithLogger(options.Logger.WithValues("webhook", "claim-plan"))}, opts...)
+	mgr.GetWebhookServer().Register(Path,
+		&webhook.Admission{Handler: NewHandler(mgr.GetClient(), opts...)})
+	return nil
+}
+
+// Handler implements the admission Handler for claim plans.
+type Handler struct {
+	client client.Reader
+	log    logging.Logger
+}
+
+// HandlerOption is used to configure the Handler.
+type HandlerOption func(*Handler)
+
+// WithLogger configures the logger for the Handler.
+func WithLogger(l logging.Logger) HandlerOption {
+	return func(h *Handler) {
+		h.log = l
+	}
+}
+
+// NewHandler returns a new Handler.
+func NewHandler(c client.Reader, opts ...HandlerOption) *Handler {
+	h := &Handler{
+		client: c,
+		log:    logging.NewNopLogger(),
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// Handle handles the admission request. It always allows the request. If the
+// request is a dry run of a claim it returns a summary of what the claim would
+// compose as warnings.
+func (h *Handler) Handle(ctx context.Context, request admission.Request) admission.Response {
+	if request.Operation != admissionv1.Create && request.Operation != admissionv1.Update {
+		return admission.Errored(http.StatusBadRequest, errors.Errorf(errFmtUnexpectedOp, request.Operation))
+	}
+
+	// Only dry runs are planned. The webhook configuration should only send
+	// us dry runs, but we double check.
+	if !ptr.Deref(request.DryRun, false) {
+		return admission.Allowed("")
+	}
+
+	gvk := schema.GroupVersionKind(request.Kind)
+	xrd, err := h.claimDefinition(ctx, gvk)
+	if err != nil {
+		return admission.Allowed("").WithWarnings(fmt.Sprintf(fmtCannotPlan, gvk.Kind, request.Name, err))
+	}
+	if xrd == nil {
+		// Not a claim.
+		return admission.Allowed("")
+	}
+
+	cm := claim.New(claim.WithGroupVersionKind(gvk))
+	if err := cm.UnmarshalJSON(request.Object.Raw); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	warnings, err := h.plan(ctx, xrd, cm)
+	if err != nil {
+		h.log.Debug("Cannot plan claim", "kind", gvk.Kind, "namespace", cm.GetNamespace(), "name", cm.GetName(), "error", err)
+		return admission.Allowed("").WithWarnings(fmt.Sprintf(fmtCannotPlan, gvk.Kind, cm.GetName(), err))
+	}
+	return admission.Allowed("").WithWarnings(warnings...)
+}
+
+// claimDefinition returns the XRD that offers the supplied kind of claim, or
+// nil if no XRD does.
+func (h *Handler) claimDefinition(ctx context.Context, gvk schema.GroupVersionKind) (*v1.CompositeResourceDefinition, error) {
+	l := &v1.CompositeResourceDefinitionList{}
+	if err := h.client.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, errListXRDs)
+	}
+	for i := range l.Items {
+		xrd := &l.Items[i]
+		if xrd.Spec.Group == gvk.Group && xrd.Spec.ClaimNames != nil && xrd.Spec.ClaimNames.Kind == gvk.Kind {
+			return xrd, nil
+		}
+	}
+	return nil, nil
+}
+
+// plan returns a human readable summary of what the supplied claim would
+// compose.
+func (h *Handler) plan(ctx context.Context, xrd *v1.CompositeResourceDefinition, cm *claim.Unstructured) ([]string, error) {
+	verb := fmt.Sprintf(fmtCreate, xrd.Spec.Names.Kind)
+	if ref := cm.GetResourceReference(); ref != nil {
+		verb = fmt.Sprintf(fmtUpdate, xrd.Spec.Names.Kind, ref.Name)
+	}
+
+	comps, err := h.compositions(ctx, xrd, cm)
+	if err != nil {
+		return nil, err
+	}
+	if len(comps) == 0 {
+		return []string{fmt.Sprintf(fmtNoComposition, cm.GetKind(), cm.GetName())}, nil
+	}
+
+	comp := comps[0]
+	warnings := []string{fmt.Sprintf(fmtComposite, cm.GetKind(), cm.GetName(), verb, comp.GetName())}
+	if len(comps) > 1 {
+		warnings = append(warnings, fmt.Sprintf(fmtRandom, len(comps)))
+	}
+
+	return append(warnings, summarize(comp)...), nil
+}
+
+// compositions returns the Compositions that could compose the supplied
+// claim's composite resource, sorted by name. A Composition reference,
+// including an XRD's enforced or default Composition, returns at most one
+// Composition. Otherwise every Composition matching the claim's Composition
+// selector is returned, because Crossplane chooses one of them at random.
+func (h *Handler) compositions(ctx context.Context, xrd *v1.CompositeResourceDefinition, cm *claim.Unstructured) ([]v1.Composition, error) {
+	name := ""
+	if ref := cm.GetCompositionReference(); ref != nil {
+		name = ref.Name
+	}
+	if name == "" && cm.GetCompositionSelector() == nil && xrd.Spec.DefaultCompositionRef != nil {
+		name = xrd.Spec.DefaultCompositionRef.Name
+	}
+	if xrd.Spec.EnforcedCompositionRef != nil {
+		name = xrd.Spec.EnforcedCompositionRef.Name
+	}
+
+	if name != "" {
+		comp := &v1.Composition{}
+		err := h.client.Get(ctx, types.NamespacedName{Name: name}, comp)
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errGetComposition)
+		}
+		return []v1.Composition{*comp}, nil
+	}
+
+	var opts []client.ListOption
+	if s := cm.GetCompositionSelector(); s != nil {
+		sel, err := metav1.LabelSelectorAsSelector(s)
+		if err != nil {
+			return nil, errors.Wrap(err, errParseSelector)
+		}
+		opts = append(opts, client.MatchingLabelsSelector{Selector: sel})
+	}
+
+	l := &v1.CompositionList{}
+	if err := h.client.List(ctx, l, opts...); err != nil {
+		return nil, errors.Wrap(err, errListCompositions)
+	}
+
+	comps := make([]v1.Composition, 0, len(l.Items))
+	for _, c := range l.Items {
+		gv, err := schema.ParseGroupVersion(c.Spec.CompositeTypeRef.APIVersion)
+		if err != nil || gv.Group != xrd.Spec.Group || c.Spec.CompositeTypeRef.Kind != xrd.Spec.Names.Kind {
+			continue
+		}
+		comps = append(comps, c)
+	}
+	sort.Slice(comps, func(i, j int) bool { return comps[i].GetName() < comps[j].GetName() })
+	return comps, nil
+}
+
+// summarize returns a human readable summary of what the supplied Composition
+// composes. Resources composed using patch and transform are listed. Resources
+// composed by a function pipeline can't be known without running the
+// pipeline, so its steps are listed instead.
+func summarize(comp v1.Composition) []string {
+	if ptr.Deref(comp.Spec.Mode, v1.CompositionModeResources) == v1.CompositionModePipeline {
+		steps := make([]string, len(comp.Spec.Pipeline))
+		for i, s := range comp.Spec.Pipeline {
+			steps[i] = fmt.Sprintf("%s (%s)", s.Step, s.FunctionRef.Name)
+		}
+		return []string{fmt.Sprintf(fmtPipeline, strings.Join(steps, ", "))}
+	}
+
+	out := make([]string, 0, len(comp.Spec.Resources))
+	for i, t := range comp.Spec.Resources {
+		u := &unstructured.Unstructured{}
+		if err := u.UnmarshalJSON(t.Base.Raw); err != nil {
+			out = append(out, fmt.Sprintf(fmtInvalidBase, i, err))
+			continue
+		}
+		name := ptr.Deref(t.Name, fmt.Sprintf("resource %d", i))
+		out = append(out, fmt.Sprintf(fmtResource, u.GroupVersionKind().GroupKind(), name))
+	}
+	return out
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionv1 "k8s.io/api/admission/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+var _ admission.Handler = &Handler{}
+
+func TestHandle(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	xrd := v1.CompositeResourceDefinition{
+		Spec: v1.CompositeResourceDefinitionSpec{
+			Group:                 "example.org",
+			Names:                 extv1.CustomResourceDefinitionNames{Kind: "XNopResource"},
+			ClaimNames:            &extv1.CustomResourceDefinitionNames{Kind: "NopResource"},
+			DefaultCompositionRef: &v1.CompositionReference{Name: "pt"},
+		},
+	}
+	typeRef := v1.TypeReference{APIVersion: "example.org/v1", Kind: "XNopResource"}
+	pt := v1.Composition{
+		ObjectMeta: metav1.ObjectMeta{Name: "pt"},
+		Spec: v1.CompositionSpec{
+			CompositeTypeRef: typeRef,
+			Resources: []v1.ComposedTemplate{
+				{Name: ptr.To("a"), Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"nop.crossplane.io/v1alpha1","kind":"NopResource"}`)}},
+				{Name: ptr.To("b"), Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"nop.crossplane.io/v1alpha1","kind":"NopResource"}`)}},
+			},
+		},
+	}
+	fn := v1.Composition{
+		ObjectMeta: metav1.ObjectMeta{Name: "fn"},
+		Spec: v1.CompositionSpec{
+			CompositeTypeRef: typeRef,
+			Mode:             ptr.To(v1.CompositionModePipeline),
+			Pipeline: []v1.PipelineStep{
+				{Step: "compose", FunctionRef: v1.FunctionReference{Name: "function-patch-and-transform"}},
+				{Step: "ready", FunctionRef: v1.FunctionReference{Name: "function-auto-ready"}},
+			},
+		},
+	}
+	other := v1.Composition{
+		ObjectMeta: metav1.ObjectMeta{Name: "other"},
+		Spec: v1.CompositionSpec{
+			CompositeTypeRef: v1.TypeReference{APIVersion: "example.org/v1", Kind: "XOtherResource"},
+		},
+	}
+
+	kube := func(comps ...v1.Composition) *test.MockClient {
+		return &test.MockClient{
+			MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+				switch l := obj.(type) {
+				case *v1.CompositeResourceDefinitionList:
+					l.Items = []v1.CompositeResourceDefinition{xrd}
+				case *v1.CompositionList:
+					l.Items = comps
+				}
+				return nil
+			},
+			MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+				for _, c := range comps {
+					if c.GetName() == key.Name {
+						c.DeepCopyInto(obj.(*v1.Composition))
+						return nil
+					}
+				}
+				return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+			},
+		}
+	}
+
+	dryRun := func(op admissionv1.Operation, kind string, spec map[string]any) admission.Request {
+		raw, _ := json.Marshal(map[string]any{
+			"apiVersion": "example.org/v1",
+			"kind":       kind,
+			"metadata":   map[string]any{"name": "cool-claim", "namespace": "default"},
+			"spec":       spec,
+		})
+		return admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: op,
+				DryRun:    ptr.To(true),
+				Name:      "cool-claim",
+				Kind:      metav1.GroupVersionKind{Group: "example.org", Version: "v1", Kind: kind},
+				Object:    runtime.RawExtension{Raw: raw},
+			},
+		}
+	}
+
+	type args struct {
+		client  client.Reader
+		request admission.Request
+	}
+	type want struct {
+		resp admission.Response
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"UnexpectedDelete": {
+			reason: "We should return an error if the request is a delete.",
+			args: args{
+				request: admission.Request{
+					AdmissionRequest: admissionv1.AdmissionRequest{
+						Operation: admissionv1.Delete,
+					},
+				},
+			},
+			want: want{
+				resp: admission.Errored(http.StatusBadRequest, errors.Errorf(errFmtUnexpectedOp, admissionv1.Delete)),
+			},
+		},
+		"NotDryRun": {
+			reason: "We should allow a request that isn't a dry run without a plan.",
+			args: args{
+				request: admission.Request{
+					AdmissionRequest: admissionv1.AdmissionRequest{
+						Operation: admissionv1.Create,
+					},
+				},
+			},
+			want: want{
+				resp: admission.Allowed(""),
+			},
+		},
+		"NotAClaim": {
+			reason: "We should allow a dry run of a resource that isn't a claim without a plan.",
+			args: args{
+				client:  kube(),
+				request: dryRun(admissionv1.Create, "XNopResource", nil),
+			},
+			want: want{
+				resp: admission.Allowed(""),
+			},
+		},
+		"ListXRDsError": {
+			reason: "We should allow a dry run, and warn that we can't plan it, if we can't list XRDs.",
+			args: args{
+				client:  &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				request: dryRun(admissionv1.Create, "NopResource", nil),
+			},
+			want: want{
+				resp: admission.Allowed("").WithWarnings(`plan: cannot preview what NopResource "cool-claim" would compose: cannot list CompositeResourceDefinitions: boom`),
+			},
+		},
+		"DefaultComposition": {
+			reason: "We should list the resources the XRD's default Composition would compose for a new claim.",
+			args: args{
+				client:  kube(pt, fn),
+				request: dryRun(admissionv1.Create, "NopResource", nil),
+			},
+			want: want{
+				resp: admission.Allowed("").WithWarnings(
+					`plan: NopResource "cool-claim" would create a new XNopResource using Composition "pt"`,
+					`plan: + NopResource.nop.crossplane.io "a"`,
+					`plan: + NopResource.nop.crossplane.io "b"`,
+				),
+			},
+		},
+		"PipelineCompositionRef": {
+			reason: "We should list the function pipeline steps of a referenced Composition for a bound claim.",
+			args: args{
+				client: kube(pt, fn),
+				request: dryRun(admissionv1.Update, "NopResource", map[string]any{
+					"compositionRef": map[string]any{"name": "fn"},
+					"resourceRef":    map[string]any{"apiVersion": "example.org/v1", "kind": "XNopResource", "name": "cool-claim-abcde"},
+				}),
+			},
+			want: want{
+				resp: admission.Allowed("").WithWarnings(
+					`plan: NopResource "cool-claim" would update XNopResource "cool-claim-abcde" using Composition "fn"`,
+					`plan: composed resources are determined by the function pipeline: compose (function-patch-and-transform), ready (function-auto-ready)`,
+				),
+			},
+		},
+		"SelectorMatchesMany": {
+			reason: "We should warn that a Composition is chosen at random if several match the claim's Composition selector.",
+			args: args{
+				client: kube(pt, fn, other),
+				request: dryRun(admissionv1.Create, "NopResource", map[string]any{
+					"compositionSelector": map[string]any{"matchLabels": map[string]any{"cool": "true"}},
+				}),
+			},
+			want: want{
+				resp: admission.Allowed("").WithWarnings(
+					`plan: NopResource "cool-claim" would create a new XNopResource using Composition "fn"`,
+					`plan: 2 Compositions match the claim's Composition selector; one is chosen at random`,
+					`plan: composed resources are determined by the function pipeline: compose (function-patch-and-transform), ready (function-auto-ready)`,
+				),
+			},
+		},
+		"NoComposition": {
+			reason: "We should warn if no Composition would compose the claim.",
+			args: args{
+				client: kube(pt, fn),
+				request: dryRun(admissionv1.Create, "NopResource", map[string]any{
+					"compositionRef": map[string]any{"name": "missing"},
+				}),
+			},
+			want: want{
+				resp: admission.Allowed("").WithWarnings(`plan: no Composition would compose NopResource "cool-claim"`),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := NewHandler(tc.args.client)
+			got := h.Handle(context.Background(), tc.args.request)
+			if diff := cmp.Diff(tc.want.resp, got); diff != "" {
+				t.Errorf("\n%s\nHandle(...): -want response, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package quota contains the Handler for the package quota webhook.
+package quota
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+const (
+	errFmtUnexpectedOp   = "unexpected operation %q, expected \"CREATE\""
+	errFmtUnexpectedKind = "unexpected kind %q"
+	errListPackages      = "cannot list packages"
+	errListRevisions     = "cannot list package revisions"
+)
+
+// SetupWebhookWithManager sets up the webhook with the manager.
+func SetupWebhookWithManager(mgr ctrl.Manager, options controller.Options, opts ...HandlerOption) error {
+	opts = append([]HandlerOption{WithLogger(options.Logger.WithValues("webhook", "package-quota"))}, opts...)
+	mgr.GetWebhookServer().Register("/validate-package-quota",
+		&webhook.Admission{Handler: NewHandler(mgr.GetClient(), opts...)})
+	return nil
+}
+
+// Handler implements the admission Handler for package quotas.
+type Handler struct {
+	client client.Reader
+	log    logging.Logger
+
+	maxPackages  int
+	maxRevisions int
+}
+
+// HandlerOption is used to configure the Handler.
+type HandlerOption func(*Handler)
+
+// WithLogger configures the logger for the Handler.
+func WithLogger(l logging.Logger) HandlerOption {
+	return func(h *Handler) {
+		h.log = l
+	}
+}
+
+// WithMaxPackages configures the maximum number of Providers, Configurations,
+// and Functions that may be installed. Zero means no limit.
+func WithMaxPackages(n int) HandlerOption {
+	return func(h *Handler) {
+		h.maxPackages = n
+	}
+}
+
+// WithMaxRevisions configures the maximum number of Provider, Configuration,
+// and Function revisions that may exist. Zero means no limit.
+func WithMaxRevisions(n int) HandlerOption {
+	return func(h *Handler) {
+		h.maxRevisions = n
+	}
+}
+
+// NewHandler returns a new Handler.
+func NewHandler(c client.Reader, opts ...HandlerOption) *Handler {
+	h := &Handler{
+		client: c,
+		log:    logging.NewNopLogger(),
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// Handle handles the admission request, validating that creating the package
+// or package revision won't exceed its quota.
+func (h *Handler) Handle(ctx context.Context, request admission.Request) admission.Response {
+	if request.Operation != admissionv1.Create {
+		return admission.Errored(http.StatusBadRequest, errors.Errorf(errFmtUnexpectedOp, request.Operation))
+	}
+
+	switch request.Kind.Kind {
+	case v1.ProviderKind, v1.ConfigurationKind, v1.FunctionKind:
+		return h.validate(ctx, "packages", h.maxPackages, errListPackages, &v1.ProviderList{}, &v1.ConfigurationList{}, &v1.FunctionList{})
+	case v1.ProviderRevisionKind, v1.ConfigurationRevisionKind, v1.FunctionRevisionKind:
+		return h.validate(ctx, "package revisions", h.maxRevisions, errListRevisions, &v1.ProviderRevisionList{}, &v1.ConfigurationRevisionList{}, &v1.FunctionRevisionList{})
+	default:
+		return admission.Errored(http.StatusBadRequest, errors.Errorf(errFmtUnexpectedKind, request.Kind.Kind))
+	}
+}
+
+func (h *Handler) validate(ctx context.Context, what string, limit int, errList string, lists ...client.ObjectList) admission.Response {
+	if limit <= 0 {
+		return admission.Allowed("")
+	}
+
+	n := 0
+	for _, l := range lists {
+		if err := h.client.List(ctx, l); err != nil {
+			return admission.Errored(http.StatusInternalServerError, errors.Wrap(err, errList))
+		}
+		n += meta.LenList(l)
+	}
+
+	if n >= limit {
+		h.log.Debug("Quota exceeded, creation not allowed", "quota", what, "count", n, "limit", limit)
+		return admission.Denied(fmt.Sprintf("Cannot create more than %d %s. There are already %d.", limit, what, n))
+	}
+	return admission.Allowed("")
+}
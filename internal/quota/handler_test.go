@@ -0,0 +1,168 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+var _ admission.Handler = &Handler{}
+
+func TestHandle(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	// Two packages and two revisions of each kind.
+	list := func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+		switch l := obj.(type) {
+		case *v1.ProviderList:
+			l.Items = make([]v1.Provider, 2)
+		case *v1.ConfigurationList:
+			l.Items = make([]v1.Configuration, 2)
+		case *v1.FunctionList:
+			l.Items = make([]v1.Function, 2)
+		case *v1.ProviderRevisionList:
+			l.Items = make([]v1.ProviderRevision, 2)
+		case *v1.ConfigurationRevisionList:
+			l.Items = make([]v1.ConfigurationRevision, 2)
+		case *v1.FunctionRevisionList:
+			l.Items = make([]v1.FunctionRevision, 2)
+		}
+		return nil
+	}
+
+	create := func(kind string) admission.Request {
+		return admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Kind:      metav1.GroupVersionKind{Group: v1.Group, Version: v1.Version, Kind: kind},
+			},
+		}
+	}
+
+	type args struct {
+		client  client.Reader
+		opts    []HandlerOption
+		request admission.Request
+	}
+	type want struct {
+		resp admission.Response
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"UnexpectedUpdate": {
+			reason: "We should return an error if the request is an update (not a create).",
+			args: args{
+				request: admission.Request{
+					AdmissionRequest: admissionv1.AdmissionRequest{
+						Operation: admissionv1.Update,
+					},
+				},
+			},
+			want: want{
+				resp: admission.Errored(http.StatusBadRequest, errors.Errorf(errFmtUnexpectedOp, admissionv1.Update)),
+			},
+		},
+		"UnexpectedKind": {
+			reason: "We should return an error if the request is for a kind that isn't a package or package revision.",
+			args: args{
+				request: create("ImageConfig"),
+			},
+			want: want{
+				resp: admission.Errored(http.StatusBadRequest, errors.Errorf(errFmtUnexpectedKind, "ImageConfig")),
+			},
+		},
+		"NoLimit": {
+			reason: "We should allow creating a package if there is no package limit.",
+			args: args{
+				opts:    []HandlerOption{WithMaxRevisions(1)},
+				request: create(v1.ProviderKind),
+			},
+			want: want{
+				resp: admission.Allowed(""),
+			},
+		},
+		"PackagesWithinLimit": {
+			reason: "We should allow creating a package if fewer than the maximum number of packages are installed.",
+			args: args{
+				client:  &test.MockClient{MockList: list},
+				opts:    []HandlerOption{WithMaxPackages(7)},
+				request: create(v1.FunctionKind),
+			},
+			want: want{
+				resp: admission.Allowed(""),
+			},
+		},
+		"PackagesExceedLimit": {
+			reason: "We should deny creating a package if the maximum number of packages, of any kind, are installed.",
+			args: args{
+				client:  &test.MockClient{MockList: list},
+				opts:    []HandlerOption{WithMaxPackages(6)},
+				request: create(v1.ConfigurationKind),
+			},
+			want: want{
+				resp: admission.Denied("Cannot create more than 6 packages. There are already 6."),
+			},
+		},
+		"RevisionsExceedLimit": {
+			reason: "We should deny creating a package revision if the maximum number of package revisions, of any kind, exist.",
+			args: args{
+				client:  &test.MockClient{MockList: list},
+				opts:    []HandlerOption{WithMaxPackages(100), WithMaxRevisions(5)},
+				request: create(v1.ProviderRevisionKind),
+			},
+			want: want{
+				resp: admission.Denied("Cannot create more than 5 package revisions. There are already 6."),
+			},
+		},
+		"ListError": {
+			reason: "We should return an error if we can't list packages.",
+			args: args{
+				client:  &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				opts:    []HandlerOption{WithMaxPackages(1)},
+				request: create(v1.ProviderKind),
+			},
+			want: want{
+				resp: admission.Errored(http.StatusInternalServerError, errors.Wrap(errBoom, errListPackages)),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := NewHandler(tc.args.client, tc.args.opts...)
+			got := h.Handle(context.Background(), tc.args.request)
+			if diff := cmp.Diff(tc.want.resp, got); diff != "" {
+				t.Errorf("%s\nHandle(...): -want response, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package useragent identifies Crossplane's controller groups to the API
+// server.
+//
+// Each group of controllers sends a distinct User-Agent header, so that
+// requests from each group can be told apart in API server audit logs and
+// metrics, and when tuning API Priority and Fairness. Note that FlowSchemas
+// match requests by user, group, or service account - not User-Agent. To apply
+// a distinct APF policy to a controller group, run it with its own service
+// account.
+package useragent
+
+import (
+	"fmt"
+	"runtime"
+
+	"k8s.io/client-go/rest"
+
+	"github.com/crossplane/crossplane/internal/version"
+)
+
+// Controller groups.
+const (
+	// Core controllers, including the package manager.
+	Core = "core"
+
+	// APIExtensions controllers, which reconcile composite resources and
+	// claims.
+	APIExtensions = "apiextensions"
+
+	// RBAC manager controllers.
+	RBAC = "rbac"
+)
+
+const unknownVersion = "unknown"
+
+// For returns the User-Agent the supplied controller group sends to the API
+// server, e.g. crossplane-apiextensions/v1.16.0 (linux/amd64).
+func For(group string) string {
+	return userAgent(group, version.New().GetVersionString())
+}
+
+// Config returns a copy of the supplied REST config that sends the supplied
+// controller group's User-Agent.
+func Config(cfg *rest.Config, group string) *rest.Config {
+	c := rest.CopyConfig(cfg)
+	c.UserAgent = For(group)
+	return c
+}
+
+func userAgent(group, version string) string {
+	if version == "" {
+		version = unknownVersion
+	}
+	return fmt.Sprintf("crossplane-%s/%s (%s/%s)", group, version, runtime.GOOS, runtime.GOARCH)
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package useragent
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUserAgent(t *testing.T) {
+	platform := fmt.Sprintf("(%s/%s)", runtime.GOOS, runtime.GOARCH)
+
+	type args struct {
+		group   string
+		version string
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   string
+	}{
+		"Versioned": {
+			reason: "We should identify the controller group and Crossplane version.",
+			args: args{
+				group:   APIExtensions,
+				version: "v1.16.0",
+			},
+			want: "crossplane-apiextensions/v1.16.0 " + platform,
+		},
+		"Unversioned": {
+			reason: "We should identify development builds as an unknown version.",
+			args: args{
+				group: Core,
+			},
+			want: "crossplane-core/unknown " + platform,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := userAgent(tc.args.group, tc.args.version)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nuserAgent(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@ package composition
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
@@ -26,12 +27,14 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	"github.com/crossplane/crossplane/internal/features"
@@ -51,10 +54,26 @@ const (
 
 	errFmtTooManyCRDs = "more than one CRD found for %s.%s: %v"
 	errFmtGetCRDs     = "cannot get the needed CRDs: %v"
+
+	errFmtForbiddenPatchTarget = "patching %q is forbidden by cluster policy"
+	errFmtForbiddenBaseField   = "setting %q is forbidden by cluster policy"
 )
 
+// A WebhookOption configures the Composition validating webhook.
+type WebhookOption func(v *validator)
+
+// WithForbiddenPatchTargets configures the webhook to reject Compositions that
+// patch any of the supplied composed resource field paths, or any field nested
+// beneath them. This lets cluster admins prevent Compositions from, for
+// example, disabling deletion protection or overwriting provider credentials.
+func WithForbiddenPatchTargets(paths ...string) WebhookOption {
+	return func(v *validator) {
+		v.forbiddenPatchTargets = paths
+	}
+}
+
 // SetupWebhookWithManager sets up the webhook with the manager.
-func SetupWebhookWithManager(mgr ctrl.Manager, options controller.Options) error {
+func SetupWebhookWithManager(mgr ctrl.Manager, options controller.Options, opts ...WebhookOption) error {
 	if options.Features.Enabled(features.EnableBetaCompositionWebhookSchemaValidation) {
 		// Setup an index on CRDs so we can retrieve them by group and kind.
 		// The index is used by the getCRD function below.
@@ -67,6 +86,9 @@ func SetupWebhookWithManager(mgr ctrl.Manager, options controller.Options) error
 	}
 
 	v := &validator{reader: mgr.GetClient(), options: options}
+	for _, o := range opts {
+		o(v)
+	}
 	return ctrl.NewWebhookManagedBy(mgr).
 		WithValidator(v).
 		For(&v1.Composition{}).
@@ -76,6 +98,8 @@ func SetupWebhookWithManager(mgr ctrl.Manager, options controller.Options) error
 type validator struct {
 	reader  client.Reader
 	options controller.Options
+
+	forbiddenPatchTargets []string
 }
 
 // ValidateCreate validates a Composition.
@@ -91,6 +115,10 @@ func (v *validator) ValidateCreate(ctx context.Context, obj runtime.Object) (adm
 		return warns, kerrors.NewInvalid(comp.GroupVersionKind().GroupKind(), comp.GetName(), validationErrs)
 	}
 
+	if errs := validatePatchTargets(comp, v.forbiddenPatchTargets); len(errs) != 0 {
+		return warns, kerrors.NewInvalid(comp.GroupVersionKind().GroupKind(), comp.GetName(), errs)
+	}
+
 	if !v.options.Features.Enabled(features.EnableBetaCompositionWebhookSchemaValidation) {
 		return warns, nil
 	}
@@ -154,6 +182,142 @@ func (v *validator) ValidateDelete(_ context.Context, _ runtime.Object) (admissi
 	return nil, nil
 }
 
+// validatePatchTargets returns an error for each base that sets, and each
+// patch, including patches in referenced patch sets, that patches a forbidden
+// composed resource field path. Only Compositions that use patch and transform
+// resource templates are validated; the resources composed by a Function
+// pipeline aren't known until it runs.
+func validatePatchTargets(comp *v1.Composition, forbidden []string) field.ErrorList {
+	if len(forbidden) == 0 {
+		return nil
+	}
+
+	patchSets := make(map[string][]v1.Patch, len(comp.Spec.PatchSets))
+	for _, ps := range comp.Spec.PatchSets {
+		patchSets[ps.Name] = ps.Patches
+	}
+
+	var errs field.ErrorList
+	for i, res := range comp.Spec.Resources {
+		errs = append(errs, validateBase(field.NewPath("spec", "resources").Index(i).Child("base"), res.Base.Raw, forbidden)...)
+		for j, p := range res.Patches {
+			path := field.NewPath("spec", "resources").Index(i).Child("patches").Index(j)
+			if p.GetType() != v1.PatchTypePatchSet {
+				errs = append(errs, validatePatchTarget(path, p, forbidden)...)
+				continue
+			}
+			if p.PatchSetName == nil {
+				continue
+			}
+			for _, sp := range patchSets[*p.PatchSetName] {
+				errs = append(errs, validatePatchTarget(path, sp, forbidden)...)
+			}
+		}
+	}
+	return errs
+}
+
+func validatePatchTarget(path *field.Path, p v1.Patch, forbidden []string) field.ErrorList {
+	switch p.GetType() {
+	case v1.PatchTypeFromCompositeFieldPath, v1.PatchTypeFromEnvironmentFieldPath, v1.PatchTypeCombineFromComposite, v1.PatchTypeCombineFromEnvironment:
+	case v1.PatchTypePatchSet, v1.PatchTypeToCompositeFieldPath, v1.PatchTypeToEnvironmentFieldPath, v1.PatchTypeCombineToComposite, v1.PatchTypeCombineToEnvironment:
+		// These patches don't write to the composed resource.
+		return nil
+	}
+
+	to := p.GetToFieldPath()
+	if to == "" {
+		// From field path patches default to patching the same field.
+		to = p.GetFromFieldPath()
+	}
+	for _, f := range forbidden {
+		if overlaps(to, f) {
+			return field.ErrorList{field.Forbidden(path.Child("toFieldPath"), fmt.Sprintf(errFmtForbiddenPatchTarget, to))}
+		}
+	}
+	return nil
+}
+
+func validateBase(path *field.Path, base []byte, forbidden []string) field.ErrorList {
+	obj := map[string]any{}
+	if err := json.Unmarshal(base, &obj); err != nil {
+		// An invalid base is rejected when the Composition is validated.
+		return nil
+	}
+	for _, f := range forbidden {
+		s, err := fieldpath.Parse(f)
+		if err != nil {
+			continue
+		}
+		if sets(obj, s) {
+			return field.ErrorList{field.Forbidden(path, fmt.Sprintf(errFmtForbiddenBaseField, f))}
+		}
+	}
+	return nil
+}
+
+// sets returns true if the supplied value sets the field path described by
+// the supplied segments, or a field nested beneath it. A wildcard segment
+// matches any field or index.
+func sets(v any, s fieldpath.Segments) bool {
+	if len(s) == 0 {
+		return true
+	}
+	wildcard := s[0].Type == fieldpath.SegmentField && s[0].Field == "*"
+	switch t := v.(type) {
+	case map[string]any:
+		if wildcard {
+			for _, e := range t {
+				if sets(e, s[1:]) {
+					return true
+				}
+			}
+			return false
+		}
+		e, ok := t[s[0].Field]
+		return ok && s[0].Type == fieldpath.SegmentField && sets(e, s[1:])
+	case []any:
+		if wildcard {
+			for _, e := range t {
+				if sets(e, s[1:]) {
+					return true
+				}
+			}
+			return false
+		}
+		return s[0].Type == fieldpath.SegmentIndex && int(s[0].Index) < len(t) && sets(t[s[0].Index], s[1:])
+	}
+	return false
+}
+
+// overlaps returns true if patching field path a could change field path b,
+// i.e. if either path is, or is a parent of, the other. Paths are compared
+// segment by segment, so spec.a.b and spec[a][b] are the same path. A wildcard
+// segment matches any segment.
+func overlaps(a, b string) bool {
+	as, err := fieldpath.Parse(a)
+	if err != nil {
+		return a == b
+	}
+	bs, err := fieldpath.Parse(b)
+	if err != nil {
+		return a == b
+	}
+	for i := range min(len(as), len(bs)) {
+		if !sameSegment(as[i], bs[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameSegment(a, b fieldpath.Segment) bool {
+	if a.Field == "*" || b.Field == "*" {
+		return true
+	}
+	return a.Type == b.Type && a.Field == b.Field && a.Index == b.Index
+}
+
 // containsOtherThanNotFound returns true if the given slice of errors contains
 // any error other than a not found error.
 func containsOtherThanNotFound(errs []error) bool {
@@ -16,6 +16,179 @@ limitations under the License.
 
 package composition
 
-import "sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
 
 var _ admission.CustomValidator = &validator{}
+
+func TestValidatePatchTargets(t *testing.T) {
+	forbidden := []string{"spec.forProvider.deletionProtection", "spec.providerConfigRef"}
+
+	cases := map[string]struct {
+		reason string
+		comp   *v1.Composition
+		want   field.ErrorList
+	}{
+		"NoForbiddenPatches": {
+			reason: "Should allow patches that don't target forbidden fields.",
+			comp: &v1.Composition{
+				Spec: v1.CompositionSpec{
+					Resources: []v1.ComposedTemplate{{
+						Patches: []v1.Patch{
+							{FromFieldPath: ptr.To("spec.region"), ToFieldPath: ptr.To("spec.forProvider.region")},
+							{Type: v1.PatchTypeToCompositeFieldPath, FromFieldPath: ptr.To("status.id"), ToFieldPath: ptr.To("spec.providerConfigRef")},
+						},
+					}},
+				},
+			},
+		},
+		"ForbiddenToFieldPath": {
+			reason: "Should reject a patch that targets a forbidden field.",
+			comp: &v1.Composition{
+				Spec: v1.CompositionSpec{
+					Resources: []v1.ComposedTemplate{{
+						Patches: []v1.Patch{
+							{FromFieldPath: ptr.To("spec.protect"), ToFieldPath: ptr.To("spec.forProvider.deletionProtection")},
+						},
+					}},
+				},
+			},
+			want: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "resources").Index(0).Child("patches").Index(0).Child("toFieldPath"), fmt.Sprintf(errFmtForbiddenPatchTarget, "spec.forProvider.deletionProtection")),
+			},
+		},
+		"ForbiddenNestedDefaultToFieldPath": {
+			reason: "Should reject a patch that implicitly targets a field nested beneath a forbidden field.",
+			comp: &v1.Composition{
+				Spec: v1.CompositionSpec{
+					Resources: []v1.ComposedTemplate{{
+						Patches: []v1.Patch{
+							{FromFieldPath: ptr.To("spec.providerConfigRef.name")},
+						},
+					}},
+				},
+			},
+			want: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "resources").Index(0).Child("patches").Index(0).Child("toFieldPath"), fmt.Sprintf(errFmtForbiddenPatchTarget, "spec.providerConfigRef.name")),
+			},
+		},
+		"ForbiddenParentToFieldPath": {
+			reason: "Should reject a patch that targets the parent of a forbidden field, since it could set the forbidden field.",
+			comp: &v1.Composition{
+				Spec: v1.CompositionSpec{
+					Resources: []v1.ComposedTemplate{{
+						Patches: []v1.Patch{
+							{FromFieldPath: ptr.To("spec.parameters"), ToFieldPath: ptr.To("spec.forProvider")},
+						},
+					}},
+				},
+			},
+			want: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "resources").Index(0).Child("patches").Index(0).Child("toFieldPath"), fmt.Sprintf(errFmtForbiddenPatchTarget, "spec.forProvider")),
+			},
+		},
+		"ForbiddenBracketToFieldPath": {
+			reason: "Should reject a patch that targets a forbidden field using bracket syntax.",
+			comp: &v1.Composition{
+				Spec: v1.CompositionSpec{
+					Resources: []v1.ComposedTemplate{{
+						Patches: []v1.Patch{
+							{FromFieldPath: ptr.To("spec.protect"), ToFieldPath: ptr.To("spec[forProvider][deletionProtection]")},
+						},
+					}},
+				},
+			},
+			want: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "resources").Index(0).Child("patches").Index(0).Child("toFieldPath"), fmt.Sprintf(errFmtForbiddenPatchTarget, "spec[forProvider][deletionProtection]")),
+			},
+		},
+		"SiblingWithForbiddenPrefix": {
+			reason: "Should allow a patch that targets a field whose name merely starts with the name of a forbidden field.",
+			comp: &v1.Composition{
+				Spec: v1.CompositionSpec{
+					Resources: []v1.ComposedTemplate{{
+						Patches: []v1.Patch{
+							{FromFieldPath: ptr.To("spec.ref"), ToFieldPath: ptr.To("spec.providerConfigRefs")},
+						},
+					}},
+				},
+			},
+		},
+		"ForbiddenInPatchSet": {
+			reason: "Should reject a patch set that targets a forbidden field.",
+			comp: &v1.Composition{
+				Spec: v1.CompositionSpec{
+					PatchSets: []v1.PatchSet{{
+						Name: "common",
+						Patches: []v1.Patch{
+							{Type: v1.PatchTypeCombineFromComposite, ToFieldPath: ptr.To("spec.forProvider.deletionProtection")},
+						},
+					}},
+					Resources: []v1.ComposedTemplate{{
+						Patches: []v1.Patch{
+							{Type: v1.PatchTypePatchSet, PatchSetName: ptr.To("common")},
+						},
+					}},
+				},
+			},
+			want: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "resources").Index(0).Child("patches").Index(0).Child("toFieldPath"), fmt.Sprintf(errFmtForbiddenPatchTarget, "spec.forProvider.deletionProtection")),
+			},
+		},
+		"ForbiddenBaseField": {
+			reason: "Should reject a base that sets a forbidden field without patching it.",
+			comp: &v1.Composition{
+				Spec: v1.CompositionSpec{
+					Resources: []v1.ComposedTemplate{{
+						Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"Database","spec":{"forProvider":{"region":"us-east-1","deletionProtection":false}}}`)},
+					}},
+				},
+			},
+			want: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "resources").Index(0).Child("base"), fmt.Sprintf(errFmtForbiddenBaseField, "spec.forProvider.deletionProtection")),
+			},
+		},
+		"ForbiddenNestedBaseField": {
+			reason: "Should reject a base that sets a field nested beneath a forbidden field.",
+			comp: &v1.Composition{
+				Spec: v1.CompositionSpec{
+					Resources: []v1.ComposedTemplate{{
+						Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"Database","spec":{"providerConfigRef":{"name":"other"}}}`)},
+					}},
+				},
+			},
+			want: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "resources").Index(0).Child("base"), fmt.Sprintf(errFmtForbiddenBaseField, "spec.providerConfigRef")),
+			},
+		},
+		"AllowedBaseFields": {
+			reason: "Should allow a base that sets the parent of a forbidden field, but not the forbidden field itself.",
+			comp: &v1.Composition{
+				Spec: v1.CompositionSpec{
+					Resources: []v1.ComposedTemplate{{
+						Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"Database","spec":{"forProvider":{"region":"us-east-1"}}}`)},
+					}},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := validatePatchTargets(tc.comp, forbidden)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nvalidatePatchTargets(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -23,6 +23,29 @@ import (
 
 var version string
 
+// A Skew is a combination of crossplane-runtime and Crossplane versions.
+type Skew struct {
+	// Runtime constrains the version of crossplane-runtime a package was
+	// built with.
+	Runtime string
+
+	// Crossplane constrains the version of Crossplane.
+	Crossplane string
+
+	// Reason the combination doesn't work.
+	Reason string
+}
+
+// KnownBrokenSkews are combinations of crossplane-runtime and Crossplane
+// versions that are known not to work together.
+var KnownBrokenSkews = []Skew{
+	{
+		Runtime:    ">=v1.14.0-0",
+		Crossplane: "<v1.14.0",
+		Reason:     "crossplane-runtime v1.14 replaced the managementPolicy field with managementPolicies, which this version of Crossplane can't compose",
+	},
+}
+
 // Operations provides semantic version operations.
 type Operations interface {
 	GetVersionString() string
@@ -53,7 +76,10 @@ func (v *Versioner) GetSemVer() (*semver.Version, error) {
 }
 
 // InConstraints is a helper function that checks if the current Crossplane
-// version is in the semantic version constraints.
+// version is in the semantic version constraints. A pre-release version, e.g.
+// v1.15.0-rc.1, is in the constraints if either it or the release it precedes
+// (v1.15.0) is. Without this pre-release and development builds of Crossplane
+// would never satisfy a constraint like >=v1.14.0.
 func (v *Versioner) InConstraints(c string) (bool, error) {
 	ver, err := v.GetSemVer()
 	if err != nil {
@@ -63,5 +89,15 @@ func (v *Versioner) InConstraints(c string) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	return constraint.Check(ver), nil
+	if constraint.Check(ver) {
+		return true, nil
+	}
+	if ver.Prerelease() == "" {
+		return false, nil
+	}
+	rel, err := ver.SetPrerelease("")
+	if err != nil {
+		return false, err
+	}
+	return constraint.Check(&rel), nil
 }
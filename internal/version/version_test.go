@@ -20,6 +20,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/Masterminds/semver"
 	"github.com/google/go-cmp/cmp"
 
 	"github.com/crossplane/crossplane-runtime/pkg/test"
@@ -59,6 +60,36 @@ func TestInRange(t *testing.T) {
 				is: false,
 			},
 		},
+		"PreReleaseInRange": {
+			reason: "Should return true when a pre-release version is in a range that doesn't include pre-releases.",
+			args: args{
+				version: "v1.15.0-rc.0.123.gabcdef",
+				r:       ">=v1.14.0",
+			},
+			want: want{
+				is: true,
+			},
+		},
+		"PreReleaseOfReleaseInRange": {
+			reason: "Should return true when the release a pre-release version precedes is in range.",
+			args: args{
+				version: "v1.15.0-rc.1",
+				r:       ">=v1.15.0",
+			},
+			want: want{
+				is: true,
+			},
+		},
+		"PreReleaseNotInRange": {
+			reason: "Should return false when neither a pre-release version nor its release are in range.",
+			args: args{
+				version: "v1.15.0-rc.1",
+				r:       ">=v1.16.0",
+			},
+			want: want{
+				is: false,
+			},
+		},
 		"InvalidVersion": {
 			reason: "Should return error when version is invalid.",
 			args: args{
@@ -94,3 +125,14 @@ func TestInRange(t *testing.T) {
 		})
 	}
 }
+
+func TestKnownBrokenSkews(t *testing.T) {
+	for _, s := range KnownBrokenSkews {
+		if _, err := semver.NewConstraint(s.Runtime); err != nil {
+			t.Errorf("KnownBrokenSkews: invalid crossplane-runtime constraint %q: %v", s.Runtime, err)
+		}
+		if _, err := semver.NewConstraint(s.Crossplane); err != nil {
+			t.Errorf("KnownBrokenSkews: invalid Crossplane constraint %q: %v", s.Crossplane, err)
+		}
+	}
+}
@@ -209,6 +209,9 @@ func genCrdVersion(vr v1.CompositeResourceDefinitionVersion, maxNameLength int64
 	for k, v := range CompositeResourceStatusProps() {
 		cStatus.Properties[k] = v
 	}
+	for k, v := range CompositeResourceQueueStatusProps() {
+		cStatus.Properties[k] = v
+	}
 	crdv.Schema.OpenAPIV3Schema.Properties["status"] = cStatus
 	return &crdv, nil
 }
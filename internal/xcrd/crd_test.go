@@ -466,6 +466,14 @@ func TestForCompositeResource(t *testing.T) {
 														},
 													},
 												},
+												"queuePosition": {
+													Description: "Position of the resource in the queue of resources waiting to be composed.",
+													Type:        "integer",
+												},
+												"estimatedWait": {
+													Description: "How long the resource is estimated to wait before it is composed.",
+													Type:        "string",
+												},
 												"claimConditionTypes": {
 													Type:      "array",
 													XListType: ptr.To("set"),
@@ -475,6 +483,22 @@ func TestForCompositeResource(t *testing.T) {
 														},
 													},
 												},
+												"compositionRef": {
+													Description: "The composition that was last used to compose the resource.",
+													Type:        "object",
+													Required:    []string{"name"},
+													Properties: map[string]extv1.JSONSchemaProps{
+														"name": {Type: "string"},
+													},
+												},
+												"compositionRevisionRef": {
+													Description: "The composition revision that was last used to compose the resource.",
+													Type:        "object",
+													Required:    []string{"name"},
+													Properties: map[string]extv1.JSONSchemaProps{
+														"name": {Type: "string"},
+													},
+												},
 												"connectionDetails": {
 													Type: "object",
 													Properties: map[string]extv1.JSONSchemaProps{
@@ -792,6 +816,14 @@ func TestForCompositeResource(t *testing.T) {
 														},
 													},
 												},
+												"queuePosition": {
+													Description: "Position of the resource in the queue of resources waiting to be composed.",
+													Type:        "integer",
+												},
+												"estimatedWait": {
+													Description: "How long the resource is estimated to wait before it is composed.",
+													Type:        "string",
+												},
 												"claimConditionTypes": {
 													Type:      "array",
 													XListType: ptr.To("set"),
@@ -801,6 +833,22 @@ func TestForCompositeResource(t *testing.T) {
 														},
 													},
 												},
+												"compositionRef": {
+													Description: "The composition that was last used to compose the resource.",
+													Type:        "object",
+													Required:    []string{"name"},
+													Properties: map[string]extv1.JSONSchemaProps{
+														"name": {Type: "string"},
+													},
+												},
+												"compositionRevisionRef": {
+													Description: "The composition revision that was last used to compose the resource.",
+													Type:        "object",
+													Required:    []string{"name"},
+													Properties: map[string]extv1.JSONSchemaProps{
+														"name": {Type: "string"},
+													},
+												},
 												"connectionDetails": {
 													Type: "object",
 													Properties: map[string]extv1.JSONSchemaProps{
@@ -1068,6 +1116,14 @@ func TestForCompositeResource(t *testing.T) {
 														},
 													},
 												},
+												"queuePosition": {
+													Description: "Position of the resource in the queue of resources waiting to be composed.",
+													Type:        "integer",
+												},
+												"estimatedWait": {
+													Description: "How long the resource is estimated to wait before it is composed.",
+													Type:        "string",
+												},
 												"claimConditionTypes": {
 													Type:      "array",
 													XListType: ptr.To("set"),
@@ -1077,6 +1133,22 @@ func TestForCompositeResource(t *testing.T) {
 														},
 													},
 												},
+												"compositionRef": {
+													Description: "The composition that was last used to compose the resource.",
+													Type:        "object",
+													Required:    []string{"name"},
+													Properties: map[string]extv1.JSONSchemaProps{
+														"name": {Type: "string"},
+													},
+												},
+												"compositionRevisionRef": {
+													Description: "The composition revision that was last used to compose the resource.",
+													Type:        "object",
+													Required:    []string{"name"},
+													Properties: map[string]extv1.JSONSchemaProps{
+														"name": {Type: "string"},
+													},
+												},
 												"connectionDetails": {
 													Type: "object",
 													Properties: map[string]extv1.JSONSchemaProps{
@@ -1360,6 +1432,14 @@ func TestForCompositeResource(t *testing.T) {
 														},
 													},
 												},
+												"queuePosition": {
+													Description: "Position of the resource in the queue of resources waiting to be composed.",
+													Type:        "integer",
+												},
+												"estimatedWait": {
+													Description: "How long the resource is estimated to wait before it is composed.",
+													Type:        "string",
+												},
 												"claimConditionTypes": {
 													Type:      "array",
 													XListType: ptr.To("set"),
@@ -1369,6 +1449,22 @@ func TestForCompositeResource(t *testing.T) {
 														},
 													},
 												},
+												"compositionRef": {
+													Description: "The composition that was last used to compose the resource.",
+													Type:        "object",
+													Required:    []string{"name"},
+													Properties: map[string]extv1.JSONSchemaProps{
+														"name": {Type: "string"},
+													},
+												},
+												"compositionRevisionRef": {
+													Description: "The composition revision that was last used to compose the resource.",
+													Type:        "object",
+													Required:    []string{"name"},
+													Properties: map[string]extv1.JSONSchemaProps{
+														"name": {Type: "string"},
+													},
+												},
 												"connectionDetails": {
 													Type: "object",
 													Properties: map[string]extv1.JSONSchemaProps{
@@ -1662,6 +1758,14 @@ func TestForCompositeResource(t *testing.T) {
 														},
 													},
 												},
+												"queuePosition": {
+													Description: "Position of the resource in the queue of resources waiting to be composed.",
+													Type:        "integer",
+												},
+												"estimatedWait": {
+													Description: "How long the resource is estimated to wait before it is composed.",
+													Type:        "string",
+												},
 												"claimConditionTypes": {
 													Type:      "array",
 													XListType: ptr.To("set"),
@@ -1671,6 +1775,22 @@ func TestForCompositeResource(t *testing.T) {
 														},
 													},
 												},
+												"compositionRef": {
+													Description: "The composition that was last used to compose the resource.",
+													Type:        "object",
+													Required:    []string{"name"},
+													Properties: map[string]extv1.JSONSchemaProps{
+														"name": {Type: "string"},
+													},
+												},
+												"compositionRevisionRef": {
+													Description: "The composition revision that was last used to compose the resource.",
+													Type:        "object",
+													Required:    []string{"name"},
+													Properties: map[string]extv1.JSONSchemaProps{
+														"name": {Type: "string"},
+													},
+												},
 												"connectionDetails": {
 													Type: "object",
 													Properties: map[string]extv1.JSONSchemaProps{
@@ -1975,6 +2095,14 @@ func TestForCompositeResource(t *testing.T) {
 														},
 													},
 												},
+												"queuePosition": {
+													Description: "Position of the resource in the queue of resources waiting to be composed.",
+													Type:        "integer",
+												},
+												"estimatedWait": {
+													Description: "How long the resource is estimated to wait before it is composed.",
+													Type:        "string",
+												},
 												"claimConditionTypes": {
 													Type:      "array",
 													XListType: ptr.To("set"),
@@ -1984,6 +2112,22 @@ func TestForCompositeResource(t *testing.T) {
 														},
 													},
 												},
+												"compositionRef": {
+													Description: "The composition that was last used to compose the resource.",
+													Type:        "object",
+													Required:    []string{"name"},
+													Properties: map[string]extv1.JSONSchemaProps{
+														"name": {Type: "string"},
+													},
+												},
+												"compositionRevisionRef": {
+													Description: "The composition revision that was last used to compose the resource.",
+													Type:        "object",
+													Required:    []string{"name"},
+													Properties: map[string]extv1.JSONSchemaProps{
+														"name": {Type: "string"},
+													},
+												},
 												"connectionDetails": {
 													Type: "object",
 													Properties: map[string]extv1.JSONSchemaProps{
@@ -2467,6 +2611,14 @@ func TestForCompositeResourceClaim(t *testing.T) {
 														},
 													},
 												},
+												"queuePosition": {
+													Description: "Position of the resource in the queue of resources waiting to be composed.",
+													Type:        "integer",
+												},
+												"estimatedWait": {
+													Description: "How long the resource is estimated to wait before it is composed.",
+													Type:        "string",
+												},
 												"claimConditionTypes": {
 													Type:      "array",
 													XListType: ptr.To("set"),
@@ -2476,6 +2628,22 @@ func TestForCompositeResourceClaim(t *testing.T) {
 														},
 													},
 												},
+												"compositionRef": {
+													Description: "The composition that was last used to compose the resource.",
+													Type:        "object",
+													Required:    []string{"name"},
+													Properties: map[string]extv1.JSONSchemaProps{
+														"name": {Type: "string"},
+													},
+												},
+												"compositionRevisionRef": {
+													Description: "The composition revision that was last used to compose the resource.",
+													Type:        "object",
+													Required:    []string{"name"},
+													Properties: map[string]extv1.JSONSchemaProps{
+														"name": {Type: "string"},
+													},
+												},
 												"connectionDetails": {
 													Type: "object",
 													Properties: map[string]extv1.JSONSchemaProps{
@@ -2762,6 +2930,14 @@ func TestForCompositeResourceClaim(t *testing.T) {
 														},
 													},
 												},
+												"queuePosition": {
+													Description: "Position of the resource in the queue of resources waiting to be composed.",
+													Type:        "integer",
+												},
+												"estimatedWait": {
+													Description: "How long the resource is estimated to wait before it is composed.",
+													Type:        "string",
+												},
 												"claimConditionTypes": {
 													Type:      "array",
 													XListType: ptr.To("set"),
@@ -2771,6 +2947,22 @@ func TestForCompositeResourceClaim(t *testing.T) {
 														},
 													},
 												},
+												"compositionRef": {
+													Description: "The composition that was last used to compose the resource.",
+													Type:        "object",
+													Required:    []string{"name"},
+													Properties: map[string]extv1.JSONSchemaProps{
+														"name": {Type: "string"},
+													},
+												},
+												"compositionRevisionRef": {
+													Description: "The composition revision that was last used to compose the resource.",
+													Type:        "object",
+													Required:    []string{"name"},
+													Properties: map[string]extv1.JSONSchemaProps{
+														"name": {Type: "string"},
+													},
+												},
 												"connectionDetails": {
 													Type: "object",
 													Properties: map[string]extv1.JSONSchemaProps{
@@ -3071,6 +3263,14 @@ func TestForCompositeResourceClaimEmptyXrd(t *testing.T) {
 												},
 											},
 										},
+										"queuePosition": {
+											Description: "Position of the resource in the queue of resources waiting to be composed.",
+											Type:        "integer",
+										},
+										"estimatedWait": {
+											Description: "How long the resource is estimated to wait before it is composed.",
+											Type:        "string",
+										},
 										"claimConditionTypes": {
 											Type:      "array",
 											XListType: ptr.To("set"),
@@ -3080,6 +3280,22 @@ func TestForCompositeResourceClaimEmptyXrd(t *testing.T) {
 												},
 											},
 										},
+										"compositionRef": {
+											Description: "The composition that was last used to compose the resource.",
+											Type:        "object",
+											Required:    []string{"name"},
+											Properties: map[string]extv1.JSONSchemaProps{
+												"name": {Type: "string"},
+											},
+										},
+										"compositionRevisionRef": {
+											Description: "The composition revision that was last used to compose the resource.",
+											Type:        "object",
+											Required:    []string{"name"},
+											Properties: map[string]extv1.JSONSchemaProps{
+												"name": {Type: "string"},
+											},
+										},
 										"connectionDetails": {
 											Type: "object",
 											Properties: map[string]extv1.JSONSchemaProps{
@@ -365,6 +365,39 @@ func CompositeResourceStatusProps() map[string]extv1.JSONSchemaProps {
 				},
 			},
 		},
+		"compositionRef": {
+			Description: "The composition that was last used to compose the resource.",
+			Type:        "object",
+			Required:    []string{"name"},
+			Properties: map[string]extv1.JSONSchemaProps{
+				"name": {Type: "string"},
+			},
+		},
+		"compositionRevisionRef": {
+			Description: "The composition revision that was last used to compose the resource.",
+			Type:        "object",
+			Required:    []string{"name"},
+			Properties: map[string]extv1.JSONSchemaProps{
+				"name": {Type: "string"},
+			},
+		},
+	}
+}
+
+// CompositeResourceQueueStatusProps returns the properties that describe a
+// composite resource's position in the queue of composite resources waiting to
+// be composed. Unlike the other status properties these are propagated from a
+// composite resource to its claim.
+func CompositeResourceQueueStatusProps() map[string]extv1.JSONSchemaProps {
+	return map[string]extv1.JSONSchemaProps{
+		"queuePosition": {
+			Description: "Position of the resource in the queue of resources waiting to be composed.",
+			Type:        "integer",
+		},
+		"estimatedWait": {
+			Description: "How long the resource is estimated to wait before it is composed.",
+			Type:        "string",
+		},
 	}
 }
 
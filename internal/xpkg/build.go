@@ -110,7 +110,8 @@ func New(packageSource, exampleSource parser.Backend, packageParser parser.Parse
 }
 
 type buildOpts struct {
-	base v1.Image
+	base   v1.Image
+	labels map[string]string
 }
 
 // A BuildOpt modifies how a package is built.
@@ -123,6 +124,14 @@ func WithBase(img v1.Image) BuildOpt {
 	}
 }
 
+// WithLabels adds the supplied labels to the package image config, e.g. to
+// record the version control revision the package was built from.
+func WithLabels(l map[string]string) BuildOpt {
+	return func(o *buildOpts) {
+		o.labels = l
+	}
+}
+
 // Build compiles a Crossplane package from an on-disk package.
 func (b *Builder) Build(ctx context.Context, opts ...BuildOpt) (v1.Image, runtime.Object, error) {
 	bOpts := &buildOpts{
@@ -184,7 +193,10 @@ func (b *Builder) Build(ctx context.Context, opts ...BuildOpt) (v1.Image, runtim
 	}
 
 	cfg := cfgFile.Config
-	cfg.Labels = make(map[string]string)
+	cfg.Labels = make(map[string]string, len(bOpts.labels))
+	for k, v := range bOpts.labels {
+		cfg.Labels[k] = v
+	}
 
 	pkgBytes, err := encode(pkg)
 	if err != nil {
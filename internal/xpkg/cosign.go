@@ -0,0 +1,477 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errFetchSignatures        = "cannot fetch cosign signatures"
+	errNoSignatures           = "no cosign signatures found"
+	errFmtNoValidSignature    = "no signature could be verified by any authority: %s"
+	errParsePublicKey         = "cannot parse PEM encoded public key"
+	errParseCertificates      = "cannot parse PEM encoded certificates"
+	errUnsupportedPublicKey   = "unsupported public key type"
+	errFmtPayloadDigest       = "signature is for image %s, not %s"
+	errDecodeSignature        = "cannot decode signature"
+	errBadSignature           = "signature does not match public key"
+	errNoCertificate          = "signature has no certificate"
+	errVerifyCertificate      = "cannot verify signing certificate"
+	errFmtNoMatchingIdentity  = "signing certificate identity %q issued by %q does not match any trusted identity"
+	errFmtReadSignatureLayer  = "cannot read signature layer %s"
+	errFmtParseSignatureLayer = "cannot parse signature payload %s"
+	errFmtAuthority           = "authority %q: %s"
+
+	errNoTransparencyLogKey       = "keyless authority has no transparency log public key"
+	errNoTransparencyLogEntry     = "signature has no transparency log entry"
+	errParseTransparencyLogEntry  = "cannot parse transparency log entry"
+	errTransparencyLogKeyMismatch = "transparency log entry was not recorded by the trusted transparency log"
+	errBadTransparencyLogEntry    = "transparency log entry's signed entry timestamp does not match the transparency log's public key"
+	errFmtTransparencyLogMismatch = "transparency log entry does not record this signature: %s"
+)
+
+// Cosign signature annotations and media types.
+const (
+	cosignSignatureAnnotation   = "dev.cosignproject.cosign/signature"
+	cosignCertificateAnnotation = "dev.sigstore.cosign/certificate"
+	cosignChainAnnotation       = "dev.sigstore.cosign/chain"
+	cosignBundleAnnotation      = "dev.sigstore.cosign/bundle"
+
+	cosignSignatureTagSuffix = ".sig"
+)
+
+// Fulcio certificate extensions that record the OIDC issuer of a keyless
+// signing certificate.
+var (
+	oidFulcioIssuer   = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+	oidFulcioIssuerV2 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+)
+
+// A CosignIdentity is a trusted keyless signing identity.
+type CosignIdentity struct {
+	// Issuer is the OIDC issuer that authenticated the signer.
+	Issuer string
+
+	// Subject is the signer's identity, i.e. an email address or URI.
+	Subject string
+}
+
+// A CosignAuthority may verify cosign signatures. An authority has either a
+// public key, or a set of root certificates and trusted identities.
+type CosignAuthority struct {
+	// Name of the authority, used in error messages.
+	Name string
+
+	// PublicKey that verifies keyed signatures.
+	PublicKey crypto.PublicKey
+
+	// Roots that keyless signing certificates must chain to.
+	Roots *x509.CertPool
+
+	// Identities that keyless signing certificates must match.
+	Identities []CosignIdentity
+
+	// TransparencyLogKey is the public key of the Rekor transparency log
+	// that keyless signatures must be recorded in.
+	TransparencyLogKey crypto.PublicKey
+}
+
+// ParsePublicKey parses a PEM encoded public key.
+func ParsePublicKey(data []byte) (crypto.PublicKey, error) {
+	b, _ := pem.Decode(data)
+	if b == nil {
+		return nil, errors.New(errParsePublicKey)
+	}
+	k, err := x509.ParsePKIXPublicKey(b.Bytes)
+	return k, errors.Wrap(err, errParsePublicKey)
+}
+
+// ParseCertificates parses a bundle of PEM encoded certificates.
+func ParseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var b *pem.Block
+		b, data = pem.Decode(data)
+		if b == nil {
+			break
+		}
+		c, err := x509.ParseCertificate(b.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, errParseCertificates)
+		}
+		certs = append(certs, c)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New(errParseCertificates)
+	}
+	return certs, nil
+}
+
+// A CosignVerifier verifies the cosign signatures of package images. Keyless
+// signatures are verified against the supplied root certificates, and must be
+// recorded in a transparency log.
+type CosignVerifier struct {
+	fetcher Fetcher
+}
+
+// NewCosignVerifier returns a CosignVerifier that fetches signatures using the
+// supplied Fetcher.
+func NewCosignVerifier(f Fetcher) *CosignVerifier {
+	return &CosignVerifier{fetcher: f}
+}
+
+// Verify that the supplied image has at least one signature that can be
+// verified by at least one of the supplied authorities. Callers should supply
+// a digest, and fetch the image by that digest once it's verified; a tag may
+// be moved to a different image after it's verified.
+func (v *CosignVerifier) Verify(ctx context.Context, ref name.Reference, authorities []CosignAuthority, secrets ...string) error {
+	digest, err := v.digest(ctx, ref, secrets...)
+	if err != nil {
+		return errors.Wrap(err, errFetchSignatures)
+	}
+	sigRef := ref.Context().Tag(strings.Replace(digest, ":", "-", 1) + cosignSignatureTagSuffix)
+	img, err := v.fetcher.Fetch(ctx, sigRef, secrets...)
+	if err != nil {
+		return errors.Wrap(err, errFetchSignatures)
+	}
+	m, err := img.Manifest()
+	if err != nil {
+		return errors.Wrap(err, errFetchSignatures)
+	}
+	if len(m.Layers) == 0 {
+		return errors.New(errNoSignatures)
+	}
+
+	var errs []string
+	for _, l := range m.Layers {
+		layer, err := img.LayerByDigest(l.Digest)
+		if err != nil {
+			return errors.Wrapf(err, errFmtReadSignatureLayer, l.Digest)
+		}
+		rc, err := layer.Compressed()
+		if err != nil {
+			return errors.Wrapf(err, errFmtReadSignatureLayer, l.Digest)
+		}
+		payload, err := io.ReadAll(io.LimitReader(rc, maxSignaturePayloadSize))
+		_ = rc.Close()
+		if err != nil {
+			return errors.Wrapf(err, errFmtReadSignatureLayer, l.Digest)
+		}
+		sig := cosignSignature{
+			payload:     payload,
+			signature:   l.Annotations[cosignSignatureAnnotation],
+			certificate: l.Annotations[cosignCertificateAnnotation],
+			chain:       l.Annotations[cosignChainAnnotation],
+			bundle:      l.Annotations[cosignBundleAnnotation],
+		}
+		if err := sig.verifyPayload(digest); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		for _, a := range authorities {
+			err := sig.verify(a)
+			if err == nil {
+				return nil
+			}
+			errs = append(errs, fmt.Sprintf(errFmtAuthority, a.Name, err))
+		}
+	}
+	return errors.Errorf(errFmtNoValidSignature, strings.Join(errs, "; "))
+}
+
+// digest returns the digest of the supplied reference, resolving it if it's a
+// tag.
+func (v *CosignVerifier) digest(ctx context.Context, ref name.Reference, secrets ...string) (string, error) {
+	if d, ok := ref.(name.Digest); ok {
+		return d.DigestStr(), nil
+	}
+	d, err := v.fetcher.Head(ctx, ref, secrets...)
+	if err != nil {
+		return "", err
+	}
+	if d == nil {
+		return "", errors.New(errNoDescriptor)
+	}
+	return d.Digest.String(), nil
+}
+
+// Signature payloads are small JSON documents.
+const maxSignaturePayloadSize = 1 << 20
+
+// A cosignSignature is a cosign signature of a simple signing payload.
+type cosignSignature struct {
+	payload     []byte
+	signature   string
+	certificate string
+	chain       string
+	bundle      string
+}
+
+// verifyPayload verifies that the signed payload is for the supplied digest.
+func (s cosignSignature) verifyPayload(digest string) error {
+	p := struct {
+		Critical struct {
+			Image struct {
+				DockerManifestDigest string `json:"docker-manifest-digest"`
+			} `json:"image"`
+		} `json:"critical"`
+	}{}
+	if err := json.Unmarshal(s.payload, &p); err != nil {
+		return errors.Wrapf(err, errFmtParseSignatureLayer, digest)
+	}
+	if p.Critical.Image.DockerManifestDigest != digest {
+		return errors.Errorf(errFmtPayloadDigest, p.Critical.Image.DockerManifestDigest, digest)
+	}
+	return nil
+}
+
+// verify the signature using the supplied authority.
+func (s cosignSignature) verify(a CosignAuthority) error {
+	if a.PublicKey != nil {
+		return s.verifySignature(a.PublicKey)
+	}
+	if a.TransparencyLogKey == nil {
+		return errors.New(errNoTransparencyLogKey)
+	}
+
+	if s.certificate == "" {
+		return errors.New(errNoCertificate)
+	}
+	certs, err := ParseCertificates([]byte(s.certificate))
+	if err != nil {
+		return err
+	}
+	leaf := certs[0]
+	intermediates := x509.NewCertPool()
+	if s.chain != "" {
+		chain, err := ParseCertificates([]byte(s.chain))
+		if err != nil {
+			return err
+		}
+		for _, c := range chain {
+			intermediates.AddCert(c)
+		}
+	}
+
+	// Keyless signing certificates are only valid for a few minutes. The
+	// transparency log records when the signature was made, so we verify the
+	// chain as of then.
+	signed, err := s.verifyBundle(a.TransparencyLogKey, leaf)
+	if err != nil {
+		return err
+	}
+	if _, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         a.Roots,
+		Intermediates: intermediates,
+		CurrentTime:   signed,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}); err != nil {
+		return errors.Wrap(err, errVerifyCertificate)
+	}
+
+	issuer, subject := certificateIdentity(leaf)
+	matched := false
+	for _, id := range a.Identities {
+		if id.Issuer == issuer && id.Subject == subject {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return errors.Errorf(errFmtNoMatchingIdentity, subject, issuer)
+	}
+
+	return s.verifySignature(leaf.PublicKey)
+}
+
+// verifySignature verifies the signature of the payload using the supplied
+// public key.
+func (s cosignSignature) verifySignature(pk crypto.PublicKey) error {
+	sig, err := base64.StdEncoding.DecodeString(s.signature)
+	if err != nil {
+		return errors.Wrap(err, errDecodeSignature)
+	}
+	ok, err := verifySignature(pk, s.payload, sig)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New(errBadSignature)
+	}
+	return nil
+}
+
+// A rekorBundle proves that a signature was recorded in a Rekor transparency
+// log. It's the offline equivalent of an inclusion proof: the log promises
+// that it recorded the entry at the integrated time.
+type rekorBundle struct {
+	SignedEntryTimestamp []byte       `json:"SignedEntryTimestamp"`
+	Payload              rekorPayload `json:"Payload"`
+}
+
+// A rekorPayload is the part of a rekorBundle that the log signs. Its fields
+// are in lexical order, so that it marshals to canonical JSON.
+type rekorPayload struct {
+	Body           string `json:"body"`
+	IntegratedTime int64  `json:"integratedTime"`
+	LogID          string `json:"logID"`
+	LogIndex       int64  `json:"logIndex"`
+}
+
+// A hashedRekord is a transparency log entry that records a signature of an
+// artifact's hash.
+type hashedRekord struct {
+	Kind string `json:"kind"`
+	Spec struct {
+		Data struct {
+			Hash struct {
+				Algorithm string `json:"algorithm"`
+				Value     string `json:"value"`
+			} `json:"hash"`
+		} `json:"data"`
+		Signature struct {
+			Content   string `json:"content"`
+			PublicKey struct {
+				Content string `json:"content"`
+			} `json:"publicKey"`
+		} `json:"signature"`
+	} `json:"spec"`
+}
+
+// verifyBundle verifies that the signature was recorded, along with the
+// supplied signing certificate, in the transparency log with the supplied
+// public key. It returns the time the signature was recorded.
+func (s cosignSignature) verifyBundle(logKey crypto.PublicKey, leaf *x509.Certificate) (time.Time, error) {
+	if s.bundle == "" {
+		return time.Time{}, errors.New(errNoTransparencyLogEntry)
+	}
+	b := &rekorBundle{}
+	if err := json.Unmarshal([]byte(s.bundle), b); err != nil {
+		return time.Time{}, errors.Wrap(err, errParseTransparencyLogEntry)
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(logKey)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, errUnsupportedPublicKey)
+	}
+	id := sha256.Sum256(der)
+	if b.Payload.LogID != hex.EncodeToString(id[:]) {
+		return time.Time{}, errors.New(errTransparencyLogKeyMismatch)
+	}
+
+	payload, err := json.Marshal(b.Payload)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, errParseTransparencyLogEntry)
+	}
+	ok, err := verifySignature(logKey, payload, b.SignedEntryTimestamp)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !ok {
+		return time.Time{}, errors.New(errBadTransparencyLogEntry)
+	}
+
+	body, err := base64.StdEncoding.DecodeString(b.Payload.Body)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, errParseTransparencyLogEntry)
+	}
+	e := &hashedRekord{}
+	if err := json.Unmarshal(body, e); err != nil {
+		return time.Time{}, errors.Wrap(err, errParseTransparencyLogEntry)
+	}
+	h := sha256.Sum256(s.payload)
+	switch {
+	case e.Kind != "hashedrekord":
+		return time.Time{}, errors.Errorf(errFmtTransparencyLogMismatch, "unsupported entry kind "+e.Kind)
+	case e.Spec.Data.Hash.Algorithm != "sha256" || e.Spec.Data.Hash.Value != hex.EncodeToString(h[:]):
+		return time.Time{}, errors.Errorf(errFmtTransparencyLogMismatch, "payload hash differs")
+	case e.Spec.Signature.Content != s.signature:
+		return time.Time{}, errors.Errorf(errFmtTransparencyLogMismatch, "signature differs")
+	}
+	pemCert, err := base64.StdEncoding.DecodeString(e.Spec.Signature.PublicKey.Content)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, errParseTransparencyLogEntry)
+	}
+	certs, err := ParseCertificates(pemCert)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !certs[0].Equal(leaf) {
+		return time.Time{}, errors.Errorf(errFmtTransparencyLogMismatch, "signing certificate differs")
+	}
+
+	return time.Unix(b.Payload.IntegratedTime, 0), nil
+}
+
+// verifySignature returns true if the supplied signature of the supplied
+// payload was made by the supplied public key.
+func verifySignature(pk crypto.PublicKey, payload, sig []byte) (bool, error) {
+	h := sha256.Sum256(payload)
+	switch k := pk.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(k, h[:], sig), nil
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, h[:], sig) == nil, nil
+	case ed25519.PublicKey:
+		return ed25519.Verify(k, payload, sig), nil
+	default:
+		return false, errors.New(errUnsupportedPublicKey)
+	}
+}
+
+// certificateIdentity returns the OIDC issuer and subject of a keyless signing
+// certificate.
+func certificateIdentity(c *x509.Certificate) (issuer, subject string) {
+	for _, e := range c.Extensions {
+		switch {
+		case e.Id.Equal(oidFulcioIssuerV2):
+			var s string
+			if _, err := asn1.Unmarshal(e.Value, &s); err == nil {
+				issuer = s
+			}
+		case e.Id.Equal(oidFulcioIssuer) && issuer == "":
+			issuer = string(e.Value)
+		}
+	}
+	switch {
+	case len(c.EmailAddresses) > 0:
+		subject = c.EmailAddresses[0]
+	case len(c.URIs) > 0:
+		subject = c.URIs[0].String()
+	}
+	return issuer, subject
+}
@@ -0,0 +1,335 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+const testDigest = "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d6f1a9e4a1b9f4b"
+
+// signatureFetcher serves a package image with the supplied digest, and its
+// cosign signature image.
+type signatureFetcher struct {
+	NopFetcher
+
+	sig     v1.Image
+	headErr error
+}
+
+func (f *signatureFetcher) Head(_ context.Context, _ name.Reference, _ ...string) (*v1.Descriptor, error) {
+	if f.headErr != nil {
+		return nil, f.headErr
+	}
+	h, err := v1.NewHash(testDigest)
+	return &v1.Descriptor{Digest: h}, err
+}
+
+func (f *signatureFetcher) Fetch(_ context.Context, ref name.Reference, _ ...string) (v1.Image, error) {
+	if ref.Identifier() != strings.Replace(testDigest, ":", "-", 1)+".sig" {
+		return nil, errors.Errorf("unexpected reference %s", ref)
+	}
+	return f.sig, nil
+}
+
+func signatureImage(t *testing.T, digest string, sign func(payload []byte) []byte, annotations map[string]string) v1.Image {
+	t.Helper()
+	return keylessSignatureImage(t, digest, sign, func(_, _ []byte) map[string]string { return annotations })
+}
+
+// keylessSignatureImage returns a signature image annotated with the
+// annotations returned by annotate, which is passed the signed payload and its
+// signature.
+func keylessSignatureImage(t *testing.T, digest string, sign func(payload []byte) []byte, annotate func(payload, sig []byte) map[string]string) v1.Image {
+	t.Helper()
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"xpkg.upbound.io/crossplane/provider-nop"},"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"},"optional":null}`, digest))
+	sig := sign(payload)
+	a := map[string]string{cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(sig)}
+	for k, v := range annotate(payload, sig) {
+		a[k] = v
+	}
+	img, err := mutate.Append(empty.Image, mutate.Addendum{
+		Layer:       static.NewLayer(payload, "application/vnd.dev.cosign.simplesigning.v1+json"),
+		Annotations: a,
+	})
+	if err != nil {
+		t.Fatalf("mutate.Append(...): %v", err)
+	}
+	return img
+}
+
+func signer(t *testing.T, k *ecdsa.PrivateKey) func(payload []byte) []byte {
+	t.Helper()
+	return func(payload []byte) []byte {
+		h := sha256.Sum256(payload)
+		sig, err := ecdsa.SignASN1(rand.Reader, k, h[:])
+		if err != nil {
+			t.Fatalf("ecdsa.SignASN1(...): %v", err)
+		}
+		return sig
+	}
+}
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey(...): %v", err)
+	}
+	return k
+}
+
+// recorded returns annotations recording a keyless signature made using the
+// supplied PEM encoded certificate in the transparency log with the supplied
+// key at the supplied time.
+func recorded(t *testing.T, logKey *ecdsa.PrivateKey, cert string, at time.Time) func(payload, sig []byte) map[string]string {
+	t.Helper()
+	return func(payload, sig []byte) map[string]string {
+		h := sha256.Sum256(payload)
+		e := &hashedRekord{Kind: "hashedrekord"}
+		e.Spec.Data.Hash.Algorithm = "sha256"
+		e.Spec.Data.Hash.Value = hex.EncodeToString(h[:])
+		e.Spec.Signature.Content = base64.StdEncoding.EncodeToString(sig)
+		e.Spec.Signature.PublicKey.Content = base64.StdEncoding.EncodeToString([]byte(cert))
+		body, err := json.Marshal(e)
+		if err != nil {
+			t.Fatalf("json.Marshal(...): %v", err)
+		}
+		der, err := x509.MarshalPKIXPublicKey(&logKey.PublicKey)
+		if err != nil {
+			t.Fatalf("x509.MarshalPKIXPublicKey(...): %v", err)
+		}
+		id := sha256.Sum256(der)
+		b := rekorBundle{Payload: rekorPayload{
+			Body:           base64.StdEncoding.EncodeToString(body),
+			IntegratedTime: at.Unix(),
+			LogID:          hex.EncodeToString(id[:]),
+			LogIndex:       42,
+		}}
+		p, err := json.Marshal(b.Payload)
+		if err != nil {
+			t.Fatalf("json.Marshal(...): %v", err)
+		}
+		b.SignedEntryTimestamp = signer(t, logKey)(p)
+		bundle, err := json.Marshal(b)
+		if err != nil {
+			t.Fatalf("json.Marshal(...): %v", err)
+		}
+		return map[string]string{cosignCertificateAnnotation: cert, cosignBundleAnnotation: string(bundle)}
+	}
+}
+
+func pemCert(t *testing.T, tmpl, parent *x509.Certificate, pub, priv any) (*x509.Certificate, string) {
+	t.Helper()
+	if parent == nil {
+		parent = tmpl
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, pub, priv)
+	if err != nil {
+		t.Fatalf("x509.CreateCertificate(...): %v", err)
+	}
+	c, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("x509.ParseCertificate(...): %v", err)
+	}
+	return c, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestCosignVerifierVerify(t *testing.T) {
+	key := newKey(t)
+	other := newKey(t)
+
+	// A root CA, and a keyless signing certificate it issued.
+	caKey := newKey(t)
+	ca, _ := pemCert(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "fulcio"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil, &caKey.PublicKey, caKey)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+
+	issuer, _ := asn1.Marshal("https://token.actions.githubusercontent.com")
+	leafKey := newKey(t)
+	_, leaf := pemCert(t, &x509.Certificate{
+		SerialNumber:    big.NewInt(2),
+		NotBefore:       time.Now().Add(-time.Minute),
+		NotAfter:        time.Now().Add(-time.Second),
+		EmailAddresses:  []string{"negz@example.org"},
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		ExtraExtensions: []pkix.Extension{{Id: oidFulcioIssuerV2, Value: issuer}},
+	}, ca, &leafKey.PublicKey, caKey)
+
+	logKey := newKey(t)
+	signed := time.Now().Add(-30 * time.Second)
+
+	keyless := CosignAuthority{
+		Name:  "keyless",
+		Roots: roots,
+		Identities: []CosignIdentity{{
+			Issuer:  "https://token.actions.githubusercontent.com",
+			Subject: "negz@example.org",
+		}},
+		TransparencyLogKey: &logKey.PublicKey,
+	}
+
+	cases := map[string]struct {
+		reason      string
+		ref         string
+		headErr     error
+		sig         v1.Image
+		authorities []CosignAuthority
+		want        error
+	}{
+		"KeyedSignature": {
+			reason:      "A signature made by a trusted key should be verified.",
+			sig:         signatureImage(t, testDigest, signer(t, key), nil),
+			authorities: []CosignAuthority{{Name: "key", PublicKey: &key.PublicKey}},
+		},
+		"AnyAuthority": {
+			reason: "A signature should be verified if any authority can verify it.",
+			sig:    signatureImage(t, testDigest, signer(t, key), nil),
+			authorities: []CosignAuthority{
+				{Name: "other", PublicKey: &other.PublicKey},
+				{Name: "key", PublicKey: &key.PublicKey},
+			},
+		},
+		"UntrustedKey": {
+			reason:      "A signature made by an untrusted key should not be verified.",
+			sig:         signatureImage(t, testDigest, signer(t, other), nil),
+			authorities: []CosignAuthority{{Name: "key", PublicKey: &key.PublicKey}},
+			want:        errors.Errorf(errFmtNoValidSignature, fmt.Sprintf(errFmtAuthority, "key", errBadSignature)),
+		},
+		"WrongDigest": {
+			reason:      "A signature of a different image should not be verified.",
+			sig:         signatureImage(t, "sha256:0000000000000000000000000000000000000000000000000000000000000000", signer(t, key), nil),
+			authorities: []CosignAuthority{{Name: "key", PublicKey: &key.PublicKey}},
+			want:        errors.Errorf(errFmtNoValidSignature, errors.Errorf(errFmtPayloadDigest, "sha256:0000000000000000000000000000000000000000000000000000000000000000", testDigest)),
+		},
+		"PinnedDigest": {
+			reason:      "The signature of the digest a reference is pinned to should be verified, without resolving the reference.",
+			ref:         "xpkg.upbound.io/crossplane/provider-nop:v0.1.0@" + testDigest,
+			headErr:     errors.New("the reference should not be resolved"),
+			sig:         signatureImage(t, testDigest, signer(t, key), nil),
+			authorities: []CosignAuthority{{Name: "key", PublicKey: &key.PublicKey}},
+		},
+		"KeylessSignature": {
+			reason:      "A signature made using a certificate issued to a trusted identity, and recorded in the transparency log, should be verified.",
+			sig:         keylessSignatureImage(t, testDigest, signer(t, leafKey), recorded(t, logKey, leaf, signed)),
+			authorities: []CosignAuthority{keyless},
+		},
+		"KeylessNotRecorded": {
+			reason:      "A keyless signature that wasn't recorded in the transparency log should not be verified.",
+			sig:         signatureImage(t, testDigest, signer(t, leafKey), map[string]string{cosignCertificateAnnotation: leaf}),
+			authorities: []CosignAuthority{keyless},
+			want:        errors.Errorf(errFmtNoValidSignature, fmt.Sprintf(errFmtAuthority, "keyless", errNoTransparencyLogEntry)),
+		},
+		"KeylessUntrustedTransparencyLog": {
+			reason:      "A keyless signature recorded in an untrusted transparency log should not be verified.",
+			sig:         keylessSignatureImage(t, testDigest, signer(t, leafKey), recorded(t, other, leaf, signed)),
+			authorities: []CosignAuthority{keyless},
+			want:        errors.Errorf(errFmtNoValidSignature, fmt.Sprintf(errFmtAuthority, "keyless", errTransparencyLogKeyMismatch)),
+		},
+		"KeylessNoTransparencyLogKey": {
+			reason: "A keyless authority without a transparency log key should not verify signatures.",
+			sig:    keylessSignatureImage(t, testDigest, signer(t, leafKey), recorded(t, logKey, leaf, signed)),
+			authorities: []CosignAuthority{{
+				Name:       "keyless",
+				Roots:      roots,
+				Identities: keyless.Identities,
+			}},
+			want: errors.Errorf(errFmtNoValidSignature, fmt.Sprintf(errFmtAuthority, "keyless", errNoTransparencyLogKey)),
+		},
+		"KeylessUntrustedIdentity": {
+			reason: "A signature made using a certificate issued to an untrusted identity should not be verified.",
+			sig:    keylessSignatureImage(t, testDigest, signer(t, leafKey), recorded(t, logKey, leaf, signed)),
+			authorities: []CosignAuthority{{
+				Name:               "keyless",
+				Roots:              roots,
+				Identities:         []CosignIdentity{{Issuer: "https://accounts.google.com", Subject: "negz@example.org"}},
+				TransparencyLogKey: &logKey.PublicKey,
+			}},
+			want: errors.Errorf(errFmtNoValidSignature, fmt.Sprintf(errFmtAuthority, "keyless", errors.Errorf(errFmtNoMatchingIdentity, "negz@example.org", "https://token.actions.githubusercontent.com"))),
+		},
+		"KeylessNoCertificate": {
+			reason:      "A keyless authority should not verify a signature without a certificate.",
+			sig:         signatureImage(t, testDigest, signer(t, leafKey), nil),
+			authorities: []CosignAuthority{keyless},
+			want:        errors.Errorf(errFmtNoValidSignature, fmt.Sprintf(errFmtAuthority, "keyless", errNoCertificate)),
+		},
+		"KeylessUntrustedRoot": {
+			reason: "A signature made using a certificate that doesn't chain to a trusted root should not be verified.",
+			sig:    keylessSignatureImage(t, testDigest, signer(t, leafKey), recorded(t, logKey, leaf, signed)),
+			authorities: []CosignAuthority{{
+				Name:               "keyless",
+				Roots:              x509.NewCertPool(),
+				Identities:         keyless.Identities,
+				TransparencyLogKey: &logKey.PublicKey,
+			}},
+			want: errors.Errorf(errFmtNoValidSignature, fmt.Sprintf(errFmtAuthority, "keyless", errors.Wrap(errors.New("x509: certificate signed by unknown authority"), errVerifyCertificate))),
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			r := "xpkg.upbound.io/crossplane/provider-nop:v0.1.0"
+			if tc.ref != "" {
+				r = tc.ref
+			}
+			ref, err := name.ParseReference(r)
+			if err != nil {
+				t.Fatalf("name.ParseReference(...): %v", err)
+			}
+			v := NewCosignVerifier(&signatureFetcher{sig: tc.sig, headErr: tc.headErr})
+			err = v.Verify(context.Background(), ref, tc.authorities)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nVerify(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errFmtRunCredentialHelper   = "cannot get credentials from credential helper %q"
+	errFmtParseCredentialHelper = "cannot parse credentials from credential helper %q"
+)
+
+// credentialHelperPrefix is prepended to a credential helper's name to find
+// its binary, per the Docker credential helper protocol.
+const credentialHelperPrefix = "docker-credential-"
+
+// credentialHelperTimeout is how long a credential helper may run. A helper
+// that hangs, e.g. waiting on an unreachable token endpoint, would otherwise
+// block the package fetch that needed credentials forever.
+const credentialHelperTimeout = 30 * time.Second
+
+// A runFn runs the supplied command, writing stdin to its standard input and
+// returning its standard output.
+type runFn func(ctx context.Context, name string, stdin []byte, args ...string) ([]byte, error)
+
+func run(ctx context.Context, name string, stdin []byte, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...) //nolint:gosec // Credential helpers are configured by the Crossplane operator.
+	cmd.Stdin = bytes.NewReader(stdin)
+	return cmd.Output()
+}
+
+// A CredentialHelper gets registry credentials by running a Docker credential
+// helper, for example docker-credential-ecr-login.
+//
+// The package manager's default keychain already exchanges workload identity
+// for registry tokens for ECR, Google Artifact Registry and GCR, and ACR.
+// Credential helpers are for registries it doesn't support, for example a
+// self-hosted registry that issues short-lived tokens, or to use a newer
+// helper than the one built into Crossplane.
+type CredentialHelper struct {
+	name string
+	run  runFn
+}
+
+// NewCredentialHelper returns a CredentialHelper that runs the credential helper
+// with the supplied name. The name doesn't include the docker-credential- prefix.
+func NewCredentialHelper(name string) *CredentialHelper {
+	return &CredentialHelper{name: name, run: run}
+}
+
+// Get the username and secret for the supplied registry server URL.
+func (h *CredentialHelper) Get(serverURL string) (string, string, error) {
+	// The credential helper protocol has no way to pass a context, so we
+	// bound how long the helper may run ourselves.
+	ctx, cancel := context.WithTimeout(context.Background(), credentialHelperTimeout)
+	defer cancel()
+
+	out, err := h.run(ctx, credentialHelperPrefix+h.name, []byte(serverURL), "get")
+	if err != nil {
+		return "", "", errors.Wrapf(err, errFmtRunCredentialHelper, h.name)
+	}
+	creds := struct {
+		Username string `json:"Username"`
+		Secret   string `json:"Secret"`
+	}{}
+	if err := json.Unmarshal(out, &creds); err != nil {
+		return "", "", errors.Wrapf(err, errFmtParseCredentialHelper, h.name)
+	}
+	return creds.Username, creds.Secret, nil
+}
+
+// A CredentialHelperKeychain resolves registry credentials using the credential
+// helper configured for each registry. Registries without a configured
+// credential helper resolve to anonymous credentials.
+type CredentialHelperKeychain struct {
+	helpers map[string]authn.Keychain
+}
+
+// NewCredentialHelperKeychain returns a keychain that uses the supplied
+// credential helpers, keyed by registry, e.g. 123456789012.dkr.ecr.us-east-1.amazonaws.com.
+func NewCredentialHelperKeychain(helpers map[string]string) *CredentialHelperKeychain {
+	k := &CredentialHelperKeychain{helpers: make(map[string]authn.Keychain, len(helpers))}
+	for registry, helper := range helpers {
+		k.helpers[strings.TrimSuffix(registry, "/")] = authn.NewKeychainFromHelper(NewCredentialHelper(helper))
+	}
+	return k
+}
+
+// Resolve the credentials for the supplied registry resource.
+func (k *CredentialHelperKeychain) Resolve(r authn.Resource) (authn.Authenticator, error) {
+	kc, ok := k.helpers[r.RegistryStr()]
+	if !ok {
+		return authn.Anonymous, nil
+	}
+	return kc.Resolve(r)
+}
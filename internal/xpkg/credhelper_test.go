@@ -0,0 +1,143 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var (
+	_ authn.Helper   = &CredentialHelper{}
+	_ authn.Keychain = &CredentialHelperKeychain{}
+)
+
+func TestCredentialHelperGet(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		username string
+		secret   string
+		err      error
+	}
+
+	cases := map[string]struct {
+		reason string
+		run    runFn
+		want   want
+	}{
+		"Success": {
+			reason: "Should return the username and secret returned by the credential helper.",
+			run: func(_ context.Context, name string, stdin []byte, _ ...string) ([]byte, error) {
+				if name != "docker-credential-ecr-login" || string(stdin) != "example.org" {
+					return nil, errBoom
+				}
+				return []byte(`{"ServerURL":"example.org","Username":"AWS","Secret":"s3cr3t"}`), nil
+			},
+			want: want{
+				username: "AWS",
+				secret:   "s3cr3t",
+			},
+		},
+		"RunError": {
+			reason: "Should return an error if the credential helper fails.",
+			run: func(_ context.Context, _ string, _ []byte, _ ...string) ([]byte, error) {
+				return nil, errBoom
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtRunCredentialHelper, "ecr-login"),
+			},
+		},
+		"ParseError": {
+			reason: "Should return an error if the credential helper's output can't be parsed.",
+			run: func(_ context.Context, _ string, _ []byte, _ ...string) ([]byte, error) {
+				return []byte("nope"), nil
+			},
+			want: want{
+				err: errors.Wrapf(errors.New("invalid character 'o' in literal null (expecting 'u')"), errFmtParseCredentialHelper, "ecr-login"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := &CredentialHelper{name: "ecr-login", run: tc.run}
+			username, secret, err := h.Get("example.org")
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nGet(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.username, username); diff != "" {
+				t.Errorf("\n%s\nGet(...): -want username, +got username:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.secret, secret); diff != "" {
+				t.Errorf("\n%s\nGet(...): -want secret, +got secret:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCredentialHelperKeychainResolve(t *testing.T) {
+	helper := &CredentialHelper{name: "gcr", run: func(_ context.Context, _ string, _ []byte, _ ...string) ([]byte, error) {
+		return []byte(`{"Username":"oauth2accesstoken","Secret":"t0k3n"}`), nil
+	}}
+	k := &CredentialHelperKeychain{helpers: map[string]authn.Keychain{
+		"us-docker.pkg.dev": authn.NewKeychainFromHelper(helper),
+	}}
+
+	cases := map[string]struct {
+		reason string
+		ref    string
+		want   authn.Authenticator
+	}{
+		"ConfiguredRegistry": {
+			reason: "A registry with a configured credential helper should use its credentials.",
+			ref:    "us-docker.pkg.dev/example/crossplane/provider-nop:v0.1.0",
+			want:   &authn.Basic{Username: "oauth2accesstoken", Password: "t0k3n"},
+		},
+		"UnconfiguredRegistry": {
+			reason: "A registry without a configured credential helper should be anonymous.",
+			ref:    "xpkg.upbound.io/crossplane/provider-nop:v0.1.0",
+			want:   authn.Anonymous,
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			ref, err := name.ParseReference(tc.ref)
+			if err != nil {
+				t.Fatalf("name.ParseReference(...): %v", err)
+			}
+			got, err := k.Resolve(ref.Context())
+			if err != nil {
+				t.Fatalf("Resolve(...): %v", err)
+			}
+			wantCfg, _ := tc.want.Authorization()
+			gotCfg, _ := got.Authorization()
+			if diff := cmp.Diff(wantCfg, gotCfg, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nResolve(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"slices"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+const (
+	errNoDependencyKind          = "dependency must specify both apiVersion and kind"
+	errNoDependencyPackage       = "dependency must specify a package"
+	errFmtUnsupportedDependency  = "unsupported dependency kind %q in API group %q"
+	errFmtParseDependencyVersion = "cannot parse dependency apiVersion %q"
+	errFmtParseDependencyAPI     = "cannot parse dependency api %q"
+	errFmtDependencyAPIVersion   = "dependency api %q must specify an API group and version"
+	errFmtInvalidAlternative     = "invalid alternative %d"
+)
+
+// DependencyAPI returns the API group and version of the supplied dependency,
+// and true, if it's satisfied by any package that defines an API rather than
+// by a particular package image.
+func DependencyAPI(d pkgmetav1.Dependency) (schema.GroupVersion, bool, error) {
+	if d.API == nil {
+		return schema.GroupVersion{}, false, nil
+	}
+	gv, err := schema.ParseGroupVersion(*d.API)
+	if err != nil {
+		return schema.GroupVersion{}, true, errors.Wrapf(err, errFmtParseDependencyAPI, *d.API)
+	}
+	if gv.Group == "" || gv.Version == "" {
+		return schema.GroupVersion{}, true, errors.Errorf(errFmtDependencyAPIVersion, *d.API)
+	}
+	return gv, true, nil
+}
+
+// DependencyPackage returns the type and package image of the supplied
+// dependency. A dependency may either specify an apiVersion, kind, and package,
+// or one of the provider, configuration, and function fields.
+func DependencyPackage(d pkgmetav1.Dependency) (v1beta1.PackageType, string, error) {
+	if d.APIVersion == nil && d.Kind == nil {
+		switch {
+		case d.Configuration != nil:
+			return v1beta1.ConfigurationPackageType, *d.Configuration, nil
+		case d.Provider != nil:
+			return v1beta1.ProviderPackageType, *d.Provider, nil
+		case d.Function != nil:
+			return v1beta1.FunctionPackageType, *d.Function, nil
+		}
+		return "", "", errors.New(errNoDependencyPackage)
+	}
+
+	if d.APIVersion == nil || d.Kind == nil {
+		return "", "", errors.New(errNoDependencyKind)
+	}
+	if d.Package == nil {
+		return "", "", errors.New(errNoDependencyPackage)
+	}
+
+	gv, err := schema.ParseGroupVersion(*d.APIVersion)
+	if err != nil {
+		return "", "", errors.Wrapf(err, errFmtParseDependencyVersion, *d.APIVersion)
+	}
+	if gv.Group != v1.Group {
+		return "", "", errors.Errorf(errFmtUnsupportedDependency, *d.Kind, gv.Group)
+	}
+
+	switch *d.Kind {
+	case v1.ConfigurationKind:
+		return v1beta1.ConfigurationPackageType, *d.Package, nil
+	case v1.ProviderKind:
+		return v1beta1.ProviderPackageType, *d.Package, nil
+	case v1.FunctionKind:
+		return v1beta1.FunctionPackageType, *d.Package, nil
+	}
+	return "", "", errors.Errorf(errFmtUnsupportedDependency, *d.Kind, gv.Group)
+}
+
+// LockDependency returns the lock dependency that records the supplied package
+// dependency. A dependency with alternatives is recorded as a dependency on
+// its first alternative, with the rest as the lock dependency's alternatives.
+func LockDependency(d pkgmetav1.Dependency) (v1beta1.Dependency, error) {
+	if len(d.AnyOf) == 0 {
+		t, pkg, err := DependencyPackage(d)
+		if err != nil {
+			return v1beta1.Dependency{}, err
+		}
+		return v1beta1.Dependency{Package: pkg, Type: t, Constraints: d.Version, Channel: ptr.Deref(d.Channel, "")}, nil
+	}
+
+	cs := make([]v1beta1.DependencyAlternative, len(d.AnyOf))
+	for i, a := range d.AnyOf {
+		t, pkg, err := DependencyPackage(pkgmetav1.Dependency{
+			APIVersion:    a.APIVersion,
+			Kind:          a.Kind,
+			Package:       a.Package,
+			Provider:      a.Provider,
+			Configuration: a.Configuration,
+			Function:      a.Function,
+		})
+		if err != nil {
+			return v1beta1.Dependency{}, errors.Wrapf(err, errFmtInvalidAlternative, i)
+		}
+		cs[i] = v1beta1.DependencyAlternative{Package: pkg, Type: t, Constraints: a.Version}
+	}
+	dep := v1beta1.Dependency{Package: cs[0].Package, Type: cs[0].Type, Constraints: cs[0].Constraints}
+	if len(cs) > 1 {
+		dep.Alternatives = cs[1:]
+	}
+	return dep, nil
+}
+
+// PreferInstalled returns the supplied lock dependency, satisfied by the first
+// of its package and its alternatives that is in the supplied lock packages.
+// The dependency is returned unchanged if none is.
+func PreferInstalled(dep v1beta1.Dependency, pkgs []v1beta1.LockPackage) v1beta1.Dependency {
+	if len(dep.Alternatives) == 0 {
+		return dep
+	}
+	cs := dep.Candidates()
+	for i, c := range cs {
+		if !slices.ContainsFunc(pkgs, func(lp v1beta1.LockPackage) bool { return lp.Source == c.Package && lp.Type == c.Type }) {
+			continue
+		}
+		if i == 0 {
+			return dep
+		}
+		return v1beta1.Dependency{
+			Package:      c.Package,
+			Type:         c.Type,
+			Constraints:  c.Constraints,
+			Alternatives: append(slices.Clone(cs[:i]), cs[i+1:]...),
+		}
+	}
+	return dep
+}
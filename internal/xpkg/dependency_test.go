@@ -0,0 +1,288 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+func TestDependencyPackage(t *testing.T) {
+	type want struct {
+		t   v1beta1.PackageType
+		pkg string
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		dep    pkgmetav1.Dependency
+		want   want
+	}{
+		"Provider": {
+			reason: "A dependency using the provider field should be a provider.",
+			dep:    pkgmetav1.Dependency{Provider: ptr.To("xpkg.upbound.io/crossplane/provider-nop")},
+			want: want{
+				t:   v1beta1.ProviderPackageType,
+				pkg: "xpkg.upbound.io/crossplane/provider-nop",
+			},
+		},
+		"Configuration": {
+			reason: "A dependency using the configuration field should be a configuration.",
+			dep:    pkgmetav1.Dependency{Configuration: ptr.To("xpkg.upbound.io/crossplane/configuration-nop")},
+			want: want{
+				t:   v1beta1.ConfigurationPackageType,
+				pkg: "xpkg.upbound.io/crossplane/configuration-nop",
+			},
+		},
+		"APIVersionAndKind": {
+			reason: "A dependency using apiVersion and kind should use the supplied package.",
+			dep: pkgmetav1.Dependency{
+				APIVersion: ptr.To("pkg.crossplane.io/v1beta1"),
+				Kind:       ptr.To("Function"),
+				Package:    ptr.To("xpkg.upbound.io/crossplane/function-nop"),
+			},
+			want: want{
+				t:   v1beta1.FunctionPackageType,
+				pkg: "xpkg.upbound.io/crossplane/function-nop",
+			},
+		},
+		"NoPackage": {
+			reason: "A dependency must specify a package.",
+			dep:    pkgmetav1.Dependency{Version: "v0.1.0"},
+			want: want{
+				err: errors.New(errNoDependencyPackage),
+			},
+		},
+		"MissingKind": {
+			reason: "A dependency that specifies an apiVersion must also specify a kind.",
+			dep: pkgmetav1.Dependency{
+				APIVersion: ptr.To("pkg.crossplane.io/v1"),
+				Package:    ptr.To("xpkg.upbound.io/crossplane/provider-nop"),
+			},
+			want: want{
+				err: errors.New(errNoDependencyKind),
+			},
+		},
+		"UnsupportedGroup": {
+			reason: "Dependencies outside the pkg.crossplane.io group are not supported.",
+			dep: pkgmetav1.Dependency{
+				APIVersion: ptr.To("example.org/v1"),
+				Kind:       ptr.To("Provider"),
+				Package:    ptr.To("xpkg.upbound.io/crossplane/provider-nop"),
+			},
+			want: want{
+				err: errors.Errorf(errFmtUnsupportedDependency, "Provider", "example.org"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pt, pkg, err := DependencyPackage(tc.dep)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nDependencyPackage(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.t, pt); diff != "" {
+				t.Errorf("\n%s\nDependencyPackage(...): -want type, +got type:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.pkg, pkg); diff != "" {
+				t.Errorf("\n%s\nDependencyPackage(...): -want package, +got package:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDependencyAPI(t *testing.T) {
+	type want struct {
+		gv  schema.GroupVersion
+		ok  bool
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		dep    pkgmetav1.Dependency
+		want   want
+	}{
+		"PackageDependency": {
+			reason: "A dependency without an api should not be an API dependency.",
+			dep:    pkgmetav1.Dependency{Provider: ptr.To("xpkg.upbound.io/crossplane/provider-nop")},
+			want:   want{},
+		},
+		"API": {
+			reason: "A dependency with an api should be an API dependency.",
+			dep:    pkgmetav1.Dependency{API: ptr.To("databases.example.org/v1alpha1")},
+			want: want{
+				gv: schema.GroupVersion{Group: "databases.example.org", Version: "v1alpha1"},
+				ok: true,
+			},
+		},
+		"NoVersion": {
+			reason: "A dependency with an api that doesn't specify a version should be invalid.",
+			dep:    pkgmetav1.Dependency{API: ptr.To("databases.example.org")},
+			want: want{
+				ok:  true,
+				err: errors.Errorf(errFmtDependencyAPIVersion, "databases.example.org"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			gv, ok, err := DependencyAPI(tc.dep)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nDependencyAPI(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.gv, gv); diff != "" {
+				t.Errorf("\n%s\nDependencyAPI(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ok, ok); diff != "" {
+				t.Errorf("\n%s\nDependencyAPI(...): -want ok, +got ok:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestLockDependency(t *testing.T) {
+	type want struct {
+		dep v1beta1.Dependency
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		dep    pkgmetav1.Dependency
+		want   want
+	}{
+		"Package": {
+			reason: "A dependency without alternatives should be recorded as a dependency on its package.",
+			dep: pkgmetav1.Dependency{
+				Provider: ptr.To("xpkg.upbound.io/crossplane/provider-nop"),
+				Version:  ">=v1.0.0",
+				Channel:  ptr.To("stable"),
+			},
+			want: want{
+				dep: v1beta1.Dependency{
+					Package:     "xpkg.upbound.io/crossplane/provider-nop",
+					Type:        v1beta1.ProviderPackageType,
+					Constraints: ">=v1.0.0",
+					Channel:     "stable",
+				},
+			},
+		},
+		"AnyOf": {
+			reason: "A dependency with alternatives should be recorded as a dependency on the first, with the rest as alternatives.",
+			dep: pkgmetav1.Dependency{
+				AnyOf: []pkgmetav1.AlternativeDependency{
+					{Provider: ptr.To("xpkg.upbound.io/upbound/provider-aws"), Version: ">=v1.0.0"},
+					{Provider: ptr.To("xpkg.upbound.io/upbound/provider-gcp"), Version: ">=v1.1.0"},
+				},
+			},
+			want: want{
+				dep: v1beta1.Dependency{
+					Package:     "xpkg.upbound.io/upbound/provider-aws",
+					Type:        v1beta1.ProviderPackageType,
+					Constraints: ">=v1.0.0",
+					Alternatives: []v1beta1.DependencyAlternative{
+						{Package: "xpkg.upbound.io/upbound/provider-gcp", Type: v1beta1.ProviderPackageType, Constraints: ">=v1.1.0"},
+					},
+				},
+			},
+		},
+		"InvalidAlternative": {
+			reason: "A dependency with an alternative that doesn't specify a package should be invalid.",
+			dep: pkgmetav1.Dependency{
+				AnyOf: []pkgmetav1.AlternativeDependency{
+					{Provider: ptr.To("xpkg.upbound.io/upbound/provider-aws"), Version: ">=v1.0.0"},
+					{Version: ">=v1.1.0"},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errors.New(errNoDependencyPackage), errFmtInvalidAlternative, 1),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dep, err := LockDependency(tc.dep)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nLockDependency(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.dep, dep); diff != "" {
+				t.Errorf("\n%s\nLockDependency(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPreferInstalled(t *testing.T) {
+	aws := v1beta1.DependencyAlternative{Package: "xpkg.upbound.io/upbound/provider-aws", Type: v1beta1.ProviderPackageType, Constraints: ">=v1.0.0"}
+	gcp := v1beta1.DependencyAlternative{Package: "xpkg.upbound.io/upbound/provider-gcp", Type: v1beta1.ProviderPackageType, Constraints: ">=v1.0.0"}
+	azure := v1beta1.DependencyAlternative{Package: "xpkg.upbound.io/upbound/provider-azure", Type: v1beta1.ProviderPackageType, Constraints: ">=v1.0.0"}
+	dep := v1beta1.Dependency{Package: aws.Package, Type: aws.Type, Constraints: aws.Constraints, Alternatives: []v1beta1.DependencyAlternative{gcp, azure}}
+
+	cases := map[string]struct {
+		reason string
+		dep    v1beta1.Dependency
+		pkgs   []v1beta1.LockPackage
+		want   v1beta1.Dependency
+	}{
+		"NoneInstalled": {
+			reason: "A dependency none of whose alternatives is installed should be unchanged.",
+			dep:    dep,
+			want:   dep,
+		},
+		"FirstInstalled": {
+			reason: "A dependency whose package is installed should be unchanged.",
+			dep:    dep,
+			pkgs:   []v1beta1.LockPackage{{Source: aws.Package, Type: aws.Type}, {Source: gcp.Package, Type: gcp.Type}},
+			want:   dep,
+		},
+		"AlternativeInstalled": {
+			reason: "A dependency with an installed alternative should be satisfied by it, with the rest as alternatives.",
+			dep:    dep,
+			pkgs:   []v1beta1.LockPackage{{Source: gcp.Package, Type: gcp.Type}},
+			want:   v1beta1.Dependency{Package: gcp.Package, Type: gcp.Type, Constraints: gcp.Constraints, Alternatives: []v1beta1.DependencyAlternative{aws, azure}},
+		},
+		"WrongType": {
+			reason: "An installed package of a different type shouldn't satisfy an alternative.",
+			dep:    dep,
+			pkgs:   []v1beta1.LockPackage{{Source: gcp.Package, Type: v1beta1.ConfigurationPackageType}},
+			want:   dep,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := PreferInstalled(tc.dep, tc.pkgs)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nPreferInstalled(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -20,18 +20,38 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/authn/k8schain"
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/empty"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
 	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+const (
+	errFmtGetClientCertSecret = "cannot get registry client certificate Secret %q"
+	errFmtParseClientCert     = "cannot parse registry client certificate from Secret %q"
+	errResolveRepository      = "cannot resolve package repository"
+	errNoDescriptor           = "package registry returned no descriptor"
+	errFmtUnexpectedDigest    = "package image has digest %s, and is not a variant of the requested index"
 )
 
 func init() { //nolint:gochecknoinits // See comment below.
@@ -45,8 +65,11 @@ func init() { //nolint:gochecknoinits // See comment below.
 
 // Fetcher fetches package images.
 type Fetcher interface {
+	// Fetch fetches a package image.
 	Fetch(ctx context.Context, ref name.Reference, secrets ...string) (v1.Image, error)
+	// Head fetches a package descriptor.
 	Head(ctx context.Context, ref name.Reference, secrets ...string) (*v1.Descriptor, error)
+	// Tags fetches a package's tags.
 	Tags(ctx context.Context, ref name.Reference, secrets ...string) ([]string, error)
 }
 
@@ -57,6 +80,20 @@ type K8sFetcher struct {
 	serviceAccount string
 	transport      http.RoundTripper
 	userAgent      string
+	keychains      []authn.Keychain
+	repositories   RepositoryResolver
+	limiter        *PullLimiter
+	platform       v1.Platform
+
+	mu         sync.Mutex
+	transports map[string]repositoryTransport
+}
+
+// A repositoryTransport is the transport used to connect to a repository with
+// its own TLS configuration.
+type repositoryTransport struct {
+	version   string
+	transport *http.Transport
 }
 
 // FetcherOpt can be used to add optional parameters to NewK8sFetcher.
@@ -70,11 +107,52 @@ func WithCustomCA(rootCAs *x509.CertPool) FetcherOpt {
 			return errors.New("Fetcher transport is not an HTTP transport")
 		}
 
-		t.TLSClientConfig = &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12}
+		tlsConfig(t).RootCAs = rootCAs
+		return nil
+	}
+}
+
+// WithClientCertSecret is a FetcherOpt that presents the client certificate in
+// the named kubernetes.io/tls Secret to registries that request one, i.e.
+// registries that require mutual TLS. The Secret is read from the fetcher's
+// namespace whenever a connection is established, so the certificate can be
+// rotated without restarting Crossplane.
+func WithClientCertSecret(secret string) FetcherOpt {
+	return func(k *K8sFetcher) error {
+		t, ok := k.transport.(*http.Transport)
+		if !ok {
+			return errors.New("Fetcher transport is not an HTTP transport")
+		}
+
+		tlsConfig(t).GetClientCertificate = func(cri *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return k.clientCertificate(cri.Context(), secret)
+		}
 		return nil
 	}
 }
 
+// tlsConfig returns the supplied transport's TLS config, creating it if
+// necessary.
+func tlsConfig(t *http.Transport) *tls.Config {
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	return t.TLSClientConfig
+}
+
+// clientCertificate loads a client certificate from the named Secret.
+func (i *K8sFetcher) clientCertificate(ctx context.Context, secret string) (*tls.Certificate, error) {
+	s, err := i.client.CoreV1().Secrets(i.namespace).Get(ctx, secret, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtGetClientCertSecret, secret)
+	}
+	c, err := tls.X509KeyPair(s.Data[corev1.TLSCertKey], s.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtParseClientCert, secret)
+	}
+	return &c, nil
+}
+
 // WithUserAgent is a FetcherOpt that can be used to set the user agent on all HTTP requests.
 func WithUserAgent(userAgent string) FetcherOpt {
 	return func(k *K8sFetcher) error {
@@ -106,6 +184,50 @@ func WithServiceAccount(sa string) FetcherOpt {
 	}
 }
 
+// WithCredentialHelpers is a FetcherOpt that resolves credentials for the
+// supplied registries using Docker credential helpers, keyed by registry. This
+// allows packages to be pulled from cloud registries using workload identity
+// rather than static pull secrets. Pull secrets take precedence over credential
+// helpers.
+func WithCredentialHelpers(helpers map[string]string) FetcherOpt {
+	return func(k *K8sFetcher) error {
+		if len(helpers) > 0 {
+			k.keychains = append(k.keychains, NewCredentialHelperKeychain(helpers))
+		}
+		return nil
+	}
+}
+
+// WithRepositories is a FetcherOpt that fetches packages according to the
+// Repository they belong to, if any. Packages that belong to a Repository are
+// fetched from its mirror, using its pull secrets and TLS configuration.
+func WithRepositories(r RepositoryResolver) FetcherOpt {
+	return func(k *K8sFetcher) error {
+		k.repositories = r
+		return nil
+	}
+}
+
+// WithPullLimiter is a FetcherOpt that limits the requests the fetcher makes
+// to package registries using the supplied PullLimiter. The limiter may be
+// shared by many fetchers.
+func WithPullLimiter(l *PullLimiter) FetcherOpt {
+	return func(k *K8sFetcher) error {
+		k.limiter = l
+		return nil
+	}
+}
+
+// WithPlatform is a FetcherOpt that fetches the variant of multi-platform
+// package images built for the supplied platform. By default the variant built
+// for the platform Crossplane is running on is fetched.
+func WithPlatform(p v1.Platform) FetcherOpt {
+	return func(k *K8sFetcher) error {
+		k.platform = p
+		return nil
+	}
+}
+
 // NewK8sFetcher creates a new K8sFetcher.
 func NewK8sFetcher(client kubernetes.Interface, opts ...FetcherOpt) (*K8sFetcher, error) {
 	dt, ok := remote.DefaultTransport.(*http.Transport)
@@ -113,8 +235,14 @@ func NewK8sFetcher(client kubernetes.Interface, opts ...FetcherOpt) (*K8sFetcher
 		return nil, errors.Errorf("default transport was not a %T", &http.Transport{})
 	}
 	k := &K8sFetcher{
-		client:    client,
-		transport: dt.Clone(),
+		client:     client,
+		transport:  dt.Clone(),
+		transports: make(map[string]repositoryTransport),
+
+		// Crossplane most likely runs on the same platform as the package
+		// runtimes it deploys. Without a platform go-containerregistry
+		// would fetch the linux/amd64 variant of multi-platform images.
+		platform: v1.Platform{OS: runtime.GOOS, Architecture: runtime.GOARCH},
 	}
 
 	for _, o := range opts {
@@ -126,44 +254,52 @@ func NewK8sFetcher(client kubernetes.Interface, opts ...FetcherOpt) (*K8sFetcher
 	return k, nil
 }
 
+// HTTPClient returns an HTTP client that uses the fetcher's transport, i.e. its
+// TLS configuration and proxy, and that gives up on requests that take longer
+// than the supplied timeout.
+func (i *K8sFetcher) HTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{Transport: i.transport, Timeout: timeout}
+}
+
 // Fetch fetches a package image.
 func (i *K8sFetcher) Fetch(ctx context.Context, ref name.Reference, secrets ...string) (v1.Image, error) {
-	auth, err := k8schain.New(ctx, i.client, k8schain.Options{
-		Namespace:          i.namespace,
-		ServiceAccountName: i.serviceAccount,
-		ImagePullSecrets:   secrets,
-	})
+	ref, t, secrets, err := i.repository(ctx, ref, secrets...)
+	if err != nil {
+		return nil, err
+	}
+	auth, err := i.keychain(ctx, secrets...)
 	if err != nil {
 		return nil, err
 	}
 	return remote.Image(ref,
 		remote.WithAuthFromKeychain(auth),
-		remote.WithTransport(i.transport),
+		remote.WithTransport(i.limiter.RoundTripper(t)),
 		remote.WithContext(ctx),
 		remote.WithUserAgent(i.userAgent),
+		remote.WithPlatform(i.platform),
 	)
 }
 
 // Head fetches a package descriptor.
 func (i *K8sFetcher) Head(ctx context.Context, ref name.Reference, secrets ...string) (*v1.Descriptor, error) {
-	auth, err := k8schain.New(ctx, i.client, k8schain.Options{
-		Namespace:          i.namespace,
-		ServiceAccountName: i.serviceAccount,
-		ImagePullSecrets:   secrets,
-	})
+	ref, t, secrets, err := i.repository(ctx, ref, secrets...)
+	if err != nil {
+		return nil, err
+	}
+	auth, err := i.keychain(ctx, secrets...)
 	if err != nil {
 		return nil, err
 	}
 	d, err := remote.Head(ref,
 		remote.WithAuthFromKeychain(auth),
-		remote.WithTransport(i.transport),
+		remote.WithTransport(i.limiter.RoundTripper(t)),
 		remote.WithContext(ctx),
 		remote.WithUserAgent(i.userAgent),
 	)
 	if err != nil || d == nil {
 		rd, gErr := remote.Get(ref,
 			remote.WithAuthFromKeychain(auth),
-			remote.WithTransport(i.transport),
+			remote.WithTransport(i.limiter.RoundTripper(t)),
 			remote.WithContext(ctx),
 			remote.WithUserAgent(i.userAgent),
 		)
@@ -175,8 +311,55 @@ func (i *K8sFetcher) Head(ctx context.Context, ref name.Reference, secrets ...st
 	return d, nil
 }
 
-// Tags fetches a package's tags.
-func (i *K8sFetcher) Tags(ctx context.Context, ref name.Reference, secrets ...string) ([]string, error) {
+// repository returns the reference, transport, and pull secrets that should
+// be used to fetch the supplied reference, according to the Repository it
+// belongs to. It returns them unchanged if it doesn't belong to a Repository.
+func (i *K8sFetcher) repository(ctx context.Context, ref name.Reference, secrets ...string) (name.Reference, http.RoundTripper, []string, error) {
+	if i.repositories == nil {
+		return ref, i.transport, secrets, nil
+	}
+	repo, err := i.repositories.ResolveRepository(ctx, ref)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, errResolveRepository)
+	}
+	if repo == nil {
+		return ref, i.transport, secrets, nil
+	}
+	mref, err := repo.Reference(ref)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	// Clip the supplied secrets so we don't modify the caller's array.
+	secrets = append(slices.Clip(secrets), repo.PullSecrets...)
+	if repo.TLS == nil {
+		return mref, i.transport, secrets, nil
+	}
+
+	// Transports are reused until the repository's configuration changes,
+	// so that connections to it can be pooled.
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	rt, ok := i.transports[repo.Name]
+	if ok && rt.version == repo.Version {
+		return mref, rt.transport, secrets, nil
+	}
+	if ok {
+		rt.transport.CloseIdleConnections()
+	}
+	t, ok := i.transport.(*http.Transport)
+	if !ok {
+		return nil, nil, nil, errors.New("Fetcher transport is not an HTTP transport")
+	}
+	t = t.Clone()
+	t.TLSClientConfig = repo.TLS
+	i.transports[repo.Name] = repositoryTransport{version: repo.Version, transport: t}
+	return mref, t, secrets, nil
+}
+
+// keychain returns a keychain that resolves credentials using the supplied
+// pull secrets, the service account's pull secrets, and any configured
+// credential helpers.
+func (i *K8sFetcher) keychain(ctx context.Context, secrets ...string) (authn.Keychain, error) {
 	auth, err := k8schain.New(ctx, i.client, k8schain.Options{
 		Namespace:          i.namespace,
 		ServiceAccountName: i.serviceAccount,
@@ -185,14 +368,223 @@ func (i *K8sFetcher) Tags(ctx context.Context, ref name.Reference, secrets ...st
 	if err != nil {
 		return nil, err
 	}
+	if len(i.keychains) == 0 {
+		return auth, nil
+	}
+	return authn.NewMultiKeychain(append([]authn.Keychain{auth}, i.keychains...)...), nil
+}
+
+// Tags fetches a package's tags.
+func (i *K8sFetcher) Tags(ctx context.Context, ref name.Reference, secrets ...string) ([]string, error) {
+	ref, t, secrets, err := i.repository(ctx, ref, secrets...)
+	if err != nil {
+		return nil, err
+	}
+	auth, err := i.keychain(ctx, secrets...)
+	if err != nil {
+		return nil, err
+	}
 	return remote.List(ref.Context(),
 		remote.WithAuthFromKeychain(auth),
-		remote.WithTransport(i.transport),
+		remote.WithTransport(i.limiter.RoundTripper(t)),
 		remote.WithContext(ctx),
 		remote.WithUserAgent(i.userAgent),
 	)
 }
 
+// IsTransientFetchError returns true if the supplied error fetching a package
+// is likely to be transient, for example because the registry rate limited the
+// request or was briefly unavailable.
+func IsTransientFetchError(err error) bool {
+	te := &transport.Error{}
+	if errors.As(err, &te) {
+		// Registries don't always explain why they rate limited a request,
+		// in which case transport.Error doesn't consider it temporary.
+		return te.StatusCode == http.StatusTooManyRequests || te.Temporary()
+	}
+	var ne net.Error
+	if errors.As(err, &ne) {
+		return ne.Timeout()
+	}
+	return errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// IsSourceUnavailableError returns true if the supplied error fetching a
+// package indicates that the registry no longer has the package image, for
+// example because its tag or digest was deleted.
+func IsSourceUnavailableError(err error) bool {
+	te := &transport.Error{}
+	if !errors.As(err, &te) {
+		return false
+	}
+	if te.StatusCode == http.StatusNotFound {
+		return true
+	}
+	for _, d := range te.Errors {
+		switch d.Code { //nolint:exhaustive // Only these codes indicate the image is gone.
+		case transport.ManifestUnknownErrorCode, transport.ManifestBlobUnknownErrorCode, transport.BlobUnknownErrorCode, transport.NameUnknownErrorCode:
+			return true
+		}
+	}
+	return false
+}
+
+// A RemoteCacheFetcher fetches package images via a shared, cluster-local
+// pull-through registry cache before falling back to the upstream registry.
+// This allows multiple control planes, and restarts of the same control plane,
+// to avoid re-downloading identical packages.
+//
+// Images are looked up in the cache registry at a repository that is prefixed
+// with the upstream registry. For example xpkg.upbound.io/crossplane/provider
+// is fetched from cache.example.org/xpkg.upbound.io/crossplane/provider. Head
+// and Tags always consult the upstream registry so that tags resolve to the
+// latest digest. Images are always fetched from the cache registry by digest,
+// and an image whose digest doesn't match is ignored, so that a stale or
+// compromised cache can't substitute a different package.
+type RemoteCacheFetcher struct {
+	Fetcher
+
+	cache *RemoteCache
+	log   logging.Logger
+}
+
+// A RemoteCacheFetcherOption configures a RemoteCacheFetcher.
+type RemoteCacheFetcherOption func(f *RemoteCacheFetcher)
+
+// WithRemoteCacheLogger specifies how the RemoteCacheFetcher should log.
+func WithRemoteCacheLogger(l logging.Logger) RemoteCacheFetcherOption {
+	return func(f *RemoteCacheFetcher) {
+		f.log = l
+	}
+}
+
+// A RemoteCache is a pull-through registry cache. Its registry may be changed
+// while it's in use.
+type RemoteCache struct {
+	mu       sync.RWMutex
+	registry string
+}
+
+// NewRemoteCache returns a pull-through registry cache at the supplied
+// registry. The registry may include a path, e.g. cache.example.org/proxy. The
+// cache is disabled if the registry is empty.
+func NewRemoteCache(registry string) *RemoteCache {
+	c := &RemoteCache{}
+	c.SetRegistry(registry)
+	return c
+}
+
+// Registry returns the cache's registry.
+func (c *RemoteCache) Registry() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.registry
+}
+
+// SetRegistry changes the cache's registry. The cache is disabled if the
+// registry is empty.
+func (c *RemoteCache) SetRegistry(registry string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.registry = strings.TrimSuffix(registry, "/")
+}
+
+// NewRemoteCacheFetcher returns a Fetcher that fetches package images via the
+// supplied cache, falling back to the wrapped Fetcher.
+func NewRemoteCacheFetcher(f Fetcher, c *RemoteCache, o ...RemoteCacheFetcherOption) *RemoteCacheFetcher {
+	rf := &RemoteCacheFetcher{Fetcher: f, cache: c, log: logging.NewNopLogger()}
+	for _, fn := range o {
+		fn(rf)
+	}
+	return rf
+}
+
+// Fetch fetches a package image from the cache registry by digest. A tag is
+// first resolved to a digest using the upstream registry. The package image is
+// fetched from its upstream registry if it cannot be fetched from the cache,
+// or if the cached image doesn't have the expected digest.
+func (c *RemoteCacheFetcher) Fetch(ctx context.Context, ref name.Reference, secrets ...string) (v1.Image, error) {
+	registry := c.cache.Registry()
+	if registry == "" {
+		return c.Fetcher.Fetch(ctx, ref, secrets...)
+	}
+
+	h, err := c.digest(ctx, ref, secrets...)
+	if err != nil {
+		c.log.Info("Cannot resolve package digest, not using remote cache", "ref", ref.String(), "error", err)
+		return c.Fetcher.Fetch(ctx, ref, secrets...)
+	}
+
+	cached, err := mirrored(registry, ref.Context().Digest(h.String()))
+	if err != nil {
+		c.log.Info("Cannot determine package reference in remote cache", "registry", registry, "ref", ref.String(), "error", err)
+		return c.Fetcher.Fetch(ctx, ref, secrets...)
+	}
+
+	img, err := c.Fetcher.Fetch(ctx, cached, secrets...)
+	if err != nil {
+		c.log.Info("Cannot fetch package from remote cache, falling back to upstream registry", "cached-ref", cached.String(), "error", err)
+		return c.Fetcher.Fetch(ctx, ref, secrets...)
+	}
+
+	if err := c.verify(ctx, cached, h, img, secrets...); err != nil {
+		c.log.Info("Package fetched from remote cache has unexpected digest, falling back to upstream registry", "cached-ref", cached.String(), "want-digest", h.String(), "error", err)
+		return c.Fetcher.Fetch(ctx, ref, secrets...)
+	}
+
+	return img, nil
+}
+
+// verify returns an error if the supplied image, fetched from the cache
+// registry, isn't the image with the supplied digest or a platform variant of
+// the multi-platform index with the supplied digest. The image is a platform
+// variant if the cache serves an index with the supplied digest. When fetching
+// by digest go-containerregistry verifies that the index it pulls has that
+// digest, and pulls the variant by the digest the index records.
+func (c *RemoteCacheFetcher) verify(ctx context.Context, cached name.Reference, h v1.Hash, img v1.Image, secrets ...string) error {
+	got, err := img.Digest()
+	if err != nil {
+		return err
+	}
+	if got == h {
+		return nil
+	}
+	desc, err := c.Fetcher.Head(ctx, cached, secrets...)
+	if err != nil {
+		return err
+	}
+	if desc == nil || desc.Digest != h || !desc.MediaType.IsIndex() {
+		return errors.Errorf(errFmtUnexpectedDigest, got)
+	}
+	return nil
+}
+
+// digest returns the digest of the supplied reference, resolving a tag using
+// the upstream registry.
+func (c *RemoteCacheFetcher) digest(ctx context.Context, ref name.Reference, secrets ...string) (v1.Hash, error) {
+	if d, ok := ref.(name.Digest); ok {
+		return v1.NewHash(d.DigestStr())
+	}
+	desc, err := c.Fetcher.Head(ctx, ref, secrets...)
+	if err != nil {
+		return v1.Hash{}, err
+	}
+	if desc == nil {
+		return v1.Hash{}, errors.New(errNoDescriptor)
+	}
+	return desc.Digest, nil
+}
+
+// mirrored returns the reference at which the supplied reference may be found
+// in a registry that mirrors its upstream registry, e.g. a cache or proxy.
+func mirrored(registry string, ref name.Reference) (name.Reference, error) {
+	sep := ":"
+	if _, ok := ref.(name.Digest); ok {
+		sep = "@"
+	}
+	return name.ParseReference(fmt.Sprintf("%s/%s/%s%s%s", registry, ref.Context().RegistryStr(), ref.Context().RepositoryStr(), sep, ref.Identifier()))
+}
+
 // NopFetcher always returns an empty image and never returns error.
 type NopFetcher struct{}
 
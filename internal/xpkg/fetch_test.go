@@ -0,0 +1,450 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ Fetcher = &RemoteCacheFetcher{}
+
+// recordingFetcher records the references it was asked to fetch, and fails to
+// fetch from the supplied registry. It serves a different image from the
+// supplied tampered registry. Its descriptors describe an index if index is
+// true, and are nil if noDescriptor is true.
+type recordingFetcher struct {
+	NopFetcher
+
+	failRegistry     string
+	tamperedRegistry string
+	index            bool
+	noDescriptor     bool
+	fetched          []string
+}
+
+func (f *recordingFetcher) Fetch(_ context.Context, ref name.Reference, _ ...string) (v1.Image, error) {
+	f.fetched = append(f.fetched, ref.String())
+	switch ref.Context().RegistryStr() {
+	case f.failRegistry:
+		return nil, errors.New("boom")
+	case f.tamperedRegistry:
+		return mutate.Annotations(empty.Image, map[string]string{"tampered": "true"}).(v1.Image), nil
+	}
+	return empty.Image, nil
+}
+
+func (f *recordingFetcher) Head(_ context.Context, _ name.Reference, _ ...string) (*v1.Descriptor, error) {
+	if f.noDescriptor {
+		return nil, nil
+	}
+	h, err := empty.Image.Digest()
+	if err != nil {
+		return nil, err
+	}
+	d := &v1.Descriptor{Digest: h, MediaType: types.OCIManifestSchema1}
+	if f.index {
+		d.MediaType = types.OCIImageIndex
+	}
+	return d, nil
+}
+
+func TestRemoteCacheFetcherFetch(t *testing.T) {
+	h, err := empty.Image.Digest()
+	if err != nil {
+		t.Fatalf("empty.Image.Digest(): %v", err)
+	}
+	digest := h.String()
+
+	type args struct {
+		registry         string
+		failRegistry     string
+		tamperedRegistry string
+		index            bool
+		noDescriptor     bool
+		ref              string
+	}
+	type want struct {
+		fetched []string
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"CacheHitTag": {
+			reason: "Should resolve a tag to a digest, then fetch the image by digest from the cache registry, prefixed with the upstream registry.",
+			args: args{
+				registry: "cache.example.org/proxy/",
+				ref:      "xpkg.upbound.io/crossplane/provider-nop:v0.1.0",
+			},
+			want: want{
+				fetched: []string{"cache.example.org/proxy/xpkg.upbound.io/crossplane/provider-nop@" + digest},
+			},
+		},
+		"CacheHitDigest": {
+			reason: "Should fetch an image by digest from the cache registry.",
+			args: args{
+				registry: "cache.example.org",
+				ref:      "xpkg.upbound.io/crossplane/provider-nop@" + digest,
+			},
+			want: want{
+				fetched: []string{"cache.example.org/xpkg.upbound.io/crossplane/provider-nop@" + digest},
+			},
+		},
+		"CacheMiss": {
+			reason: "Should fall back to the upstream registry if the image cannot be fetched from the cache registry.",
+			args: args{
+				registry:     "cache.example.org",
+				failRegistry: "cache.example.org",
+				ref:          "xpkg.upbound.io/crossplane/provider-nop:v0.1.0",
+			},
+			want: want{
+				fetched: []string{
+					"cache.example.org/xpkg.upbound.io/crossplane/provider-nop@" + digest,
+					"xpkg.upbound.io/crossplane/provider-nop:v0.1.0",
+				},
+			},
+		},
+		"CacheDigestMismatch": {
+			reason: "Should fall back to the upstream registry if the image fetched from the cache registry doesn't have the expected digest.",
+			args: args{
+				registry:         "cache.example.org",
+				tamperedRegistry: "cache.example.org",
+				ref:              "xpkg.upbound.io/crossplane/provider-nop:v0.1.0",
+			},
+			want: want{
+				fetched: []string{
+					"cache.example.org/xpkg.upbound.io/crossplane/provider-nop@" + digest,
+					"xpkg.upbound.io/crossplane/provider-nop:v0.1.0",
+				},
+			},
+		},
+		"CacheHitMultiPlatform": {
+			reason: "Should use the platform variant fetched from the cache registry if the requested digest is that of an index.",
+			args: args{
+				registry:         "cache.example.org",
+				tamperedRegistry: "cache.example.org",
+				index:            true,
+				ref:              "xpkg.upbound.io/crossplane/provider-nop:v0.1.0",
+			},
+			want: want{
+				fetched: []string{"cache.example.org/xpkg.upbound.io/crossplane/provider-nop@" + digest},
+			},
+		},
+		"NoDescriptor": {
+			reason: "Should fetch from the upstream registry if a tag can't be resolved to a descriptor.",
+			args: args{
+				registry:     "cache.example.org",
+				noDescriptor: true,
+				ref:          "xpkg.upbound.io/crossplane/provider-nop:v0.1.0",
+			},
+			want: want{
+				fetched: []string{"xpkg.upbound.io/crossplane/provider-nop:v0.1.0"},
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			f := &recordingFetcher{failRegistry: tc.args.failRegistry, tamperedRegistry: tc.args.tamperedRegistry, index: tc.args.index, noDescriptor: tc.args.noDescriptor}
+			ref, err := name.ParseReference(tc.args.ref)
+			if err != nil {
+				t.Fatalf("name.ParseReference(...): %v", err)
+			}
+
+			_, err = NewRemoteCacheFetcher(f, NewRemoteCache(tc.args.registry)).Fetch(context.Background(), ref)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nFetch(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.fetched, f.fetched); diff != "" {
+				t.Errorf("\n%s\nFetch(...): -want fetched, +got fetched:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestK8sFetcherClientCertificate(t *testing.T) {
+	certPEM, keyPEM := selfSignedCert(t)
+
+	secret := func(data map[string][]byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "registry-client-cert", Namespace: "crossplane-system"},
+			Type:       corev1.SecretTypeTLS,
+			Data:       data,
+		}
+	}
+
+	type want struct {
+		cert bool
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason  string
+		objects []runtime.Object
+		want    want
+	}{
+		"Success": {
+			reason:  "We should load the client certificate from the Secret.",
+			objects: []runtime.Object{secret(map[string][]byte{corev1.TLSCertKey: certPEM, corev1.TLSPrivateKeyKey: keyPEM})},
+			want:    want{cert: true},
+		},
+		"SecretNotFound": {
+			reason: "We should return an error if the Secret doesn't exist.",
+			want: want{
+				err: errors.Wrapf(kerrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "registry-client-cert"), errFmtGetClientCertSecret, "registry-client-cert"),
+			},
+		},
+		"InvalidCertificate": {
+			reason:  "We should return an error if the Secret doesn't contain a valid certificate and key.",
+			objects: []runtime.Object{secret(map[string][]byte{corev1.TLSCertKey: certPEM})},
+			want: want{
+				err: errors.Wrapf(errors.New("tls: failed to find any PEM data in key input"), errFmtParseClientCert, "registry-client-cert"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			k, err := NewK8sFetcher(fake.NewSimpleClientset(tc.objects...), WithClientCertSecret("registry-client-cert"), WithNamespace("crossplane-system"))
+			if err != nil {
+				t.Fatalf("NewK8sFetcher(...): %v", err)
+			}
+
+			tr := k.transport.(*http.Transport)
+			if tr.TLSClientConfig == nil || tr.TLSClientConfig.GetClientCertificate == nil {
+				t.Fatalf("\n%s\nWithClientCertSecret(...): expected transport to present a client certificate", tc.reason)
+			}
+
+			c, err := k.clientCertificate(context.Background(), "registry-client-cert")
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nclientCertificate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cert, c != nil); diff != "" {
+				t.Errorf("\n%s\nclientCertificate(...): -want certificate, +got certificate:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func selfSignedCert(t *testing.T) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey(...): %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "crossplane"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("x509.CreateCertificate(...): %v", err)
+	}
+	kder, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("x509.MarshalECPrivateKey(...): %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: kder})
+}
+
+func TestIsTransientFetchError(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   bool
+	}{
+		"TooManyRequests": {
+			reason: "A registry rate limiting a request is transient.",
+			err:    errors.Wrap(&transport.Error{StatusCode: http.StatusTooManyRequests}, "boom"),
+			want:   true,
+		},
+		"ServiceUnavailable": {
+			reason: "A registry being unavailable is transient.",
+			err:    &transport.Error{StatusCode: http.StatusServiceUnavailable},
+			want:   true,
+		},
+		"NotFound": {
+			reason: "A package not existing is not transient.",
+			err:    &transport.Error{StatusCode: http.StatusNotFound},
+			want:   false,
+		},
+		"DeadlineExceeded": {
+			reason: "A fetch timing out is transient.",
+			err:    errors.Wrap(context.DeadlineExceeded, "boom"),
+			want:   true,
+		},
+		"Other": {
+			reason: "Other errors are not transient.",
+			err:    errors.New("boom"),
+			want:   false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IsTransientFetchError(tc.err)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nIsTransientFetchError(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestIsSourceUnavailableError(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   bool
+	}{
+		"NotFound": {
+			reason: "A registry not finding a package means it's unavailable.",
+			err:    errors.Wrap(&transport.Error{StatusCode: http.StatusNotFound}, "boom"),
+			want:   true,
+		},
+		"ManifestUnknown": {
+			reason: "A registry not knowing a package's manifest means it's unavailable.",
+			err:    &transport.Error{StatusCode: http.StatusBadRequest, Errors: []transport.Diagnostic{{Code: transport.ManifestUnknownErrorCode}}},
+			want:   true,
+		},
+		"TooManyRequests": {
+			reason: "A registry rate limiting a request doesn't mean a package is unavailable.",
+			err:    &transport.Error{StatusCode: http.StatusTooManyRequests},
+			want:   false,
+		},
+		"Other": {
+			reason: "Other errors don't mean a package is unavailable.",
+			err:    errors.New("boom"),
+			want:   false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IsSourceUnavailableError(tc.err)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nIsSourceUnavailableError(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestK8sFetcherFetchPlatform(t *testing.T) {
+	srv := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	defer srv.Close()
+
+	amd64, _ := random.Image(64, 1)
+	arm64, _ := random.Image(64, 1)
+	idx := mutate.AppendManifests(empty.Index,
+		mutate.IndexAddendum{Add: amd64, Descriptor: v1.Descriptor{Platform: &v1.Platform{OS: "linux", Architecture: "amd64"}}},
+		mutate.IndexAddendum{Add: arm64, Descriptor: v1.Descriptor{Platform: &v1.Platform{OS: "linux", Architecture: "arm64"}}},
+	)
+	ref, _ := name.ParseReference(strings.TrimPrefix(srv.URL, "http://")+"/provider-nop:v0.1.0", name.Insecure)
+	if err := remote.WriteIndex(ref, idx); err != nil {
+		t.Fatalf("remote.WriteIndex(...): %v", err)
+	}
+
+	type args struct {
+		opts []FetcherOpt
+	}
+	type want struct {
+		img v1.Image
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"AMD64": {
+			reason: "We should fetch the variant of a multi-platform image built for the supplied platform.",
+			args: args{
+				opts: []FetcherOpt{WithPlatform(v1.Platform{OS: "linux", Architecture: "amd64"})},
+			},
+			want: want{
+				img: amd64,
+			},
+		},
+		"ARM64": {
+			reason: "We should fetch the variant of a multi-platform image built for the supplied platform.",
+			args: args{
+				opts: []FetcherOpt{WithPlatform(v1.Platform{OS: "linux", Architecture: "arm64"})},
+			},
+			want: want{
+				img: arm64,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			f, err := NewK8sFetcher(fake.NewSimpleClientset(), tc.args.opts...)
+			if err != nil {
+				t.Fatalf("NewK8sFetcher(...): %v", err)
+			}
+			img, err := f.Fetch(context.Background(), ref)
+			if err != nil {
+				t.Fatalf("\n%s\nFetch(...): %v", tc.reason, err)
+			}
+			want, _ := tc.want.img.Digest()
+			got, _ := img.Digest()
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("\n%s\nFetch(...): -want digest, +got digest:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
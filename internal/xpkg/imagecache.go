@@ -0,0 +1,368 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"container/list"
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errNotCached           = "image is not cached"
+	errLoadImageCache      = "cannot load image cache"
+	errWriteCachedImage    = "cannot write image to cache"
+	errFmtImageTooLarge    = "image of %d bytes exceeds image cache size of %d bytes"
+	errFmtEvictCachedImage = "cannot evict image %s from cache"
+)
+
+const tmpImagePrefix = ".tmp-"
+
+// An ImageCache caches package images by the digest they were requested by.
+type ImageCache interface {
+	Get(h v1.Hash) (v1.Image, error)
+	Store(h v1.Hash, img v1.Image) error
+}
+
+type cachedImage struct {
+	key  string
+	size int64
+}
+
+// FsImageCache stores package images on disk, keyed by digest. Each image is
+// stored as an OCI image layout. Once the cache exceeds its maximum size the
+// least recently used images are evicted. Access times are persisted, so that
+// eviction order survives restarts when the cache is backed by a persistent
+// volume.
+type FsImageCache struct {
+	dir     string
+	maxSize int64
+
+	mu     sync.Mutex
+	loaded bool
+	size   int64
+	lru    *list.List
+	images map[string]*list.Element
+}
+
+// NewFsImageCache returns an image cache that stores up to maxSize bytes of
+// images in the supplied directory.
+func NewFsImageCache(dir string, maxSize int64) *FsImageCache {
+	return &FsImageCache{dir: dir, maxSize: maxSize, lru: list.New(), images: make(map[string]*list.Element)}
+}
+
+// Get the image with the supplied digest from the cache.
+func (c *FsImageCache) Get(h v1.Hash) (v1.Image, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.load(); err != nil {
+		return nil, errors.Wrap(err, errLoadImageCache)
+	}
+
+	key := imageKey(h)
+	e, ok := c.images[key]
+	if !ok {
+		return nil, errors.New(errNotCached)
+	}
+	c.lru.MoveToFront(e)
+	now := time.Now()
+	_ = os.Chtimes(filepath.Join(c.dir, key), now, now)
+
+	return layoutImage(layout.Path(filepath.Join(c.dir, key)))
+}
+
+// Store the supplied image in the cache, evicting the least recently used
+// images if the cache exceeds its maximum size. The supplied digest needn't be
+// the image's digest. For example a multi-platform image is stored under the
+// digest of its index.
+func (c *FsImageCache) Store(h v1.Hash, img v1.Image) error {
+	key := imageKey(h)
+
+	c.mu.Lock()
+	err := c.load()
+	_, ok := c.images[key]
+	maxSize := c.maxSize
+	c.mu.Unlock()
+	if err != nil {
+		return errors.Wrap(err, errLoadImageCache)
+	}
+	if ok {
+		return nil
+	}
+
+	// Write to a temporary directory first, so that a partially written image
+	// is never read from the cache. Writing the image pulls its layers, so we
+	// don't hold the lock while we do it.
+	tmp, size, err := writeLayout(c.dir, key, img)
+	if err != nil {
+		return errors.Wrap(err, errWriteCachedImage)
+	}
+	if size > maxSize {
+		_ = os.RemoveAll(tmp)
+		return errors.Errorf(errFmtImageTooLarge, size, maxSize)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Another fetch may have stored the image while we were writing it.
+	if _, ok := c.images[key]; ok {
+		_ = os.RemoveAll(tmp)
+		return nil
+	}
+	if err := os.Rename(tmp, filepath.Join(c.dir, key)); err != nil {
+		_ = os.RemoveAll(tmp)
+		return errors.Wrap(err, errWriteCachedImage)
+	}
+
+	c.images[key] = c.lru.PushFront(&cachedImage{key: key, size: size})
+	c.size += size
+
+	return c.evict()
+}
+
+// Size returns the number of bytes of images in the cache.
+func (c *FsImageCache) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+// SetMaxSize changes the maximum size of the cache, evicting the least
+// recently used images if the cache exceeds it.
+func (c *FsImageCache) SetMaxSize(maxSize int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.maxSize = maxSize
+	if !c.loaded {
+		// Images are evicted when the cache is loaded.
+		return nil
+	}
+	return c.evict()
+}
+
+// evict the least recently used images until the cache is within its maximum
+// size.
+func (c *FsImageCache) evict() error {
+	for c.size > c.maxSize {
+		e := c.lru.Back()
+		if e == nil {
+			return nil
+		}
+		ci := e.Value.(*cachedImage) //nolint:forcetypeassert // We only store *cachedImage.
+		if err := os.RemoveAll(filepath.Join(c.dir, ci.key)); err != nil {
+			return errors.Wrapf(err, errFmtEvictCachedImage, ci.key)
+		}
+		c.lru.Remove(e)
+		delete(c.images, ci.key)
+		c.size -= ci.size
+	}
+	return nil
+}
+
+// load the images that are already in the cache directory, e.g. because it's
+// backed by a persistent volume.
+func (c *FsImageCache) load() error {
+	if c.loaded {
+		return nil
+	}
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+
+	type found struct {
+		cachedImage
+		used time.Time
+	}
+	images := make([]found, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		path := filepath.Join(c.dir, e.Name())
+		// Remove images we were interrupted while writing.
+		if strings.HasPrefix(e.Name(), tmpImagePrefix) {
+			_ = os.RemoveAll(path)
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return err
+		}
+		size, err := dirSize(path)
+		if err != nil {
+			return err
+		}
+		images = append(images, found{cachedImage: cachedImage{key: e.Name(), size: size}, used: info.ModTime()})
+	}
+
+	// Most recently used first.
+	sort.Slice(images, func(i, j int) bool { return images[i].used.After(images[j].used) })
+	for i := range images {
+		ci := images[i].cachedImage
+		c.images[ci.key] = c.lru.PushBack(&ci)
+		c.size += ci.size
+	}
+
+	c.loaded = true
+	return c.evict()
+}
+
+// writeLayout writes the supplied image to a new temporary OCI image layout in
+// the supplied directory. It returns the layout's path and size.
+func writeLayout(dir, key string, img v1.Image) (string, int64, error) {
+	tmp, err := os.MkdirTemp(dir, tmpImagePrefix+key+"-")
+	if err != nil {
+		return "", 0, err
+	}
+	p, err := layout.Write(tmp, empty.Index)
+	if err != nil {
+		_ = os.RemoveAll(tmp)
+		return "", 0, err
+	}
+	if err := p.AppendImage(img); err != nil {
+		_ = os.RemoveAll(tmp)
+		return "", 0, err
+	}
+	size, err := dirSize(tmp)
+	if err != nil {
+		_ = os.RemoveAll(tmp)
+		return "", 0, err
+	}
+	return tmp, size, nil
+}
+
+// layoutImage returns the only image in the supplied OCI image layout.
+func layoutImage(p layout.Path) (v1.Image, error) {
+	idx, err := p.ImageIndex()
+	if err != nil {
+		return nil, err
+	}
+	m, err := idx.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+	if len(m.Manifests) != 1 {
+		return nil, errors.New(errNotCached)
+	}
+	return p.Image(m.Manifests[0].Digest)
+}
+
+func imageKey(h v1.Hash) string {
+	return h.Algorithm + "-" + h.Hex
+}
+
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
+
+// An ImageCacheFetcher fetches package images from an ImageCache, falling back
+// to the wrapped Fetcher. Tags are resolved to a digest using the wrapped
+// Fetcher, which is much cheaper than pulling the image.
+type ImageCacheFetcher struct {
+	Fetcher
+
+	cache ImageCache
+}
+
+// NewImageCacheFetcher returns a Fetcher that caches the images it fetches.
+func NewImageCacheFetcher(f Fetcher, c ImageCache) *ImageCacheFetcher {
+	return &ImageCacheFetcher{Fetcher: f, cache: c}
+}
+
+// Fetch a package image from the cache, or from its registry if it isn't
+// cached. Images fetched from their registry are stored in the cache.
+func (c *ImageCacheFetcher) Fetch(ctx context.Context, ref name.Reference, secrets ...string) (v1.Image, error) {
+	h, err := c.digest(ctx, ref, secrets...)
+	if err != nil {
+		// We can't cache an image we can't resolve to a digest.
+		return c.Fetcher.Fetch(ctx, ref, secrets...)
+	}
+	if img, err := c.cache.Get(h); err == nil {
+		return img, nil
+	}
+
+	// Images are cached under the digest they're requested by. That's the
+	// digest of the index for multi-platform images, not the digest of the
+	// platform variant we fetch. We fetch by digest in case the tag moved
+	// since we resolved it.
+	img, err := c.Fetcher.Fetch(ctx, ref.Context().Digest(h.String()), secrets...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Failing to cache the image isn't fatal - we'll just fetch it again next
+	// time.
+	if err := c.cache.Store(h, img); err != nil {
+		return img, nil //nolint:nilerr // See above.
+	}
+
+	// Return the cached image, so that its layers are read from disk rather
+	// than pulled from the registry again.
+	if cached, err := c.cache.Get(h); err == nil {
+		return cached, nil
+	}
+	return img, nil
+}
+
+func (c *ImageCacheFetcher) digest(ctx context.Context, ref name.Reference, secrets ...string) (v1.Hash, error) {
+	if d, ok := ref.(name.Digest); ok {
+		return v1.NewHash(d.DigestStr())
+	}
+	desc, err := c.Fetcher.Head(ctx, ref, secrets...)
+	if err != nil {
+		return v1.Hash{}, err
+	}
+	if desc == nil {
+		return v1.Hash{}, errors.New(errNoDescriptor)
+	}
+	return desc.Digest, nil
+}
@@ -0,0 +1,324 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var (
+	_ Fetcher    = &ImageCacheFetcher{}
+	_ ImageCache = &FsImageCache{}
+)
+
+func randomImage(t *testing.T) (v1.Image, v1.Hash) {
+	t.Helper()
+	img, err := random.Image(1024, 1)
+	if err != nil {
+		t.Fatalf("random.Image(...): %v", err)
+	}
+	h, err := img.Digest()
+	if err != nil {
+		t.Fatalf("img.Digest(): %v", err)
+	}
+	return img, h
+}
+
+func TestFsImageCache(t *testing.T) {
+	a, ha := randomImage(t)
+	b, hb := randomImage(t)
+	c, hc := randomImage(t)
+
+	// Measure roughly how much space an image takes up in the cache. Random
+	// images differ slightly in size, so maximum sizes leave some slack.
+	probe := NewFsImageCache(t.TempDir(), 1<<30)
+	if err := probe.Store(ha, a); err != nil {
+		t.Fatalf("Store(...): %v", err)
+	}
+	size := probe.Size()
+
+	type want struct {
+		cached []v1.Hash
+		evict  []v1.Hash
+	}
+
+	cases := map[string]struct {
+		reason  string
+		maxSize int64
+		do      func(t *testing.T, c *FsImageCache)
+		want    want
+	}{
+		"WithinSize": {
+			reason:  "All images should be cached when the cache is within its maximum size.",
+			maxSize: 3*size + size/2,
+			do: func(t *testing.T, ic *FsImageCache) {
+				t.Helper()
+				store(t, ic, ha, a)
+				store(t, ic, hb, b)
+				store(t, ic, hc, c)
+			},
+			want: want{cached: []v1.Hash{ha, hb, hc}},
+		},
+		"EvictLeastRecentlyStored": {
+			reason:  "The least recently stored image should be evicted when the cache exceeds its maximum size.",
+			maxSize: 2*size + size/2,
+			do: func(t *testing.T, ic *FsImageCache) {
+				t.Helper()
+				store(t, ic, ha, a)
+				store(t, ic, hb, b)
+				store(t, ic, hc, c)
+			},
+			want: want{cached: []v1.Hash{hb, hc}, evict: []v1.Hash{ha}},
+		},
+		"EvictLeastRecentlyUsed": {
+			reason:  "Getting an image should prevent it from being evicted before less recently used images.",
+			maxSize: 2*size + size/2,
+			do: func(t *testing.T, ic *FsImageCache) {
+				t.Helper()
+				store(t, ic, ha, a)
+				store(t, ic, hb, b)
+				if _, err := ic.Get(ha); err != nil {
+					t.Fatalf("Get(...): %v", err)
+				}
+				store(t, ic, hc, c)
+			},
+			want: want{cached: []v1.Hash{ha, hc}, evict: []v1.Hash{hb}},
+		},
+		"Shrink": {
+			reason:  "Shrinking the cache should evict the least recently used images that no longer fit.",
+			maxSize: 2*size + size/2,
+			do: func(t *testing.T, ic *FsImageCache) {
+				t.Helper()
+				store(t, ic, ha, a)
+				store(t, ic, hb, b)
+				if err := ic.SetMaxSize(size + size/2); err != nil {
+					t.Fatalf("SetMaxSize(...): %v", err)
+				}
+			},
+			want: want{cached: []v1.Hash{hb}, evict: []v1.Hash{ha}},
+		},
+		"ImageTooLarge": {
+			reason:  "An image larger than the cache should not be cached.",
+			maxSize: size - 1,
+			do: func(t *testing.T, ic *FsImageCache) {
+				t.Helper()
+				if err := ic.Store(ha, a); err == nil {
+					t.Errorf("Store(...): expected error storing image larger than cache")
+				}
+			},
+			want: want{evict: []v1.Hash{ha}},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			ic := NewFsImageCache(t.TempDir(), tc.maxSize)
+			tc.do(t, ic)
+
+			for _, h := range tc.want.cached {
+				img, err := ic.Get(h)
+				if err != nil {
+					t.Errorf("\n%s\nGet(%s): %v", tc.reason, h, err)
+					continue
+				}
+				got, err := img.Digest()
+				if err != nil {
+					t.Fatalf("img.Digest(): %v", err)
+				}
+				if diff := cmp.Diff(h, got); diff != "" {
+					t.Errorf("\n%s\nGet(%s): -want digest, +got digest:\n%s", tc.reason, h, diff)
+				}
+			}
+			for _, h := range tc.want.evict {
+				if _, err := ic.Get(h); err == nil {
+					t.Errorf("\n%s\nGet(%s): expected image to be evicted", tc.reason, h)
+				}
+			}
+			if ic.Size() > tc.maxSize {
+				t.Errorf("\n%s\nSize(): %d exceeds maximum size %d", tc.reason, ic.Size(), tc.maxSize)
+			}
+		})
+	}
+}
+
+func TestFsImageCacheLoad(t *testing.T) {
+	a, ha := randomImage(t)
+	b, hb := randomImage(t)
+
+	dir := t.TempDir()
+	store(t, NewFsImageCache(dir, 1<<30), ha, a)
+	store(t, NewFsImageCache(dir, 1<<30), hb, b)
+
+	// A new cache should find the images that were previously stored.
+	ic := NewFsImageCache(dir, 1<<30)
+	for _, h := range []v1.Hash{ha, hb} {
+		if _, err := ic.Get(h); err != nil {
+			t.Errorf("Get(%s): %v", h, err)
+		}
+	}
+}
+
+func store(t *testing.T, c *FsImageCache, h v1.Hash, img v1.Image) {
+	t.Helper()
+	if err := c.Store(h, img); err != nil {
+		t.Fatalf("Store(...): %v", err)
+	}
+}
+
+// imageFetcher fetches the supplied image, and counts how many times it did.
+type imageFetcher struct {
+	NopFetcher
+
+	img          v1.Image
+	head         *v1.Hash
+	noDescriptor bool
+	err          error
+	fetches      int
+}
+
+func (f *imageFetcher) Fetch(_ context.Context, _ name.Reference, _ ...string) (v1.Image, error) {
+	f.fetches++
+	return f.img, f.err
+}
+
+func (f *imageFetcher) Head(_ context.Context, _ name.Reference, _ ...string) (*v1.Descriptor, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.noDescriptor {
+		return nil, nil
+	}
+	if f.head != nil {
+		return &v1.Descriptor{Digest: *f.head}, nil
+	}
+	h, err := f.img.Digest()
+	return &v1.Descriptor{Digest: h}, err
+}
+
+func TestImageCacheFetcherFetch(t *testing.T) {
+	errBoom := errors.New("boom")
+	img, h := randomImage(t)
+
+	// The digest of a multi-platform index, of which img is a variant.
+	idx := v1.Hash{Algorithm: "sha256", Hex: "ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d2d7d1c5d1d3abf"}
+
+	type want struct {
+		digest  v1.Hash
+		fetches int
+		cached  []v1.Hash
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason  string
+		ref     string
+		cached  *v1.Hash
+		fetcher *imageFetcher
+		want    want
+	}{
+		"CacheMissMultiPlatform": {
+			reason:  "A multi-platform image that isn't cached should be fetched and cached under the digest of its index.",
+			ref:     "xpkg.upbound.io/crossplane/provider-nop:v0.1.0",
+			fetcher: &imageFetcher{img: img, head: &idx},
+			want:    want{digest: h, fetches: 1, cached: []v1.Hash{idx}},
+		},
+		"CacheHitMultiPlatform": {
+			reason:  "A multi-platform image that is cached under the digest of its index should not be fetched from its registry.",
+			ref:     "xpkg.upbound.io/crossplane/provider-nop@" + idx.String(),
+			cached:  &idx,
+			fetcher: &imageFetcher{img: img, err: errBoom},
+			want:    want{digest: h, fetches: 0},
+		},
+		"CacheMissTag": {
+			reason:  "An image that isn't cached should be fetched from its registry.",
+			ref:     "xpkg.upbound.io/crossplane/provider-nop:v0.1.0",
+			fetcher: &imageFetcher{img: img},
+			want:    want{digest: h, fetches: 1, cached: []v1.Hash{h}},
+		},
+		"CacheHitTag": {
+			reason:  "A tagged image that is cached should not be fetched from its registry.",
+			ref:     "xpkg.upbound.io/crossplane/provider-nop:v0.1.0",
+			cached:  &h,
+			fetcher: &imageFetcher{img: img},
+			want:    want{digest: h, fetches: 0},
+		},
+		"CacheHitDigest": {
+			reason:  "An image referenced by digest that is cached should not be fetched from its registry.",
+			ref:     "xpkg.upbound.io/crossplane/provider-nop@" + h.String(),
+			cached:  &h,
+			fetcher: &imageFetcher{img: img, err: errBoom},
+			want:    want{digest: h, fetches: 0},
+		},
+		"NoDescriptor": {
+			reason:  "An image whose tag can't be resolved to a descriptor should be fetched from its registry.",
+			ref:     "xpkg.upbound.io/crossplane/provider-nop:v0.1.0",
+			fetcher: &imageFetcher{img: img, noDescriptor: true},
+			want:    want{digest: h, fetches: 1},
+		},
+		"FetchError": {
+			reason:  "Errors fetching an image that isn't cached should be returned.",
+			ref:     "xpkg.upbound.io/crossplane/provider-nop:v0.1.0",
+			fetcher: &imageFetcher{err: errBoom},
+			want:    want{fetches: 1, err: errBoom},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			ic := NewFsImageCache(t.TempDir(), 1<<30)
+			if tc.cached != nil {
+				store(t, ic, *tc.cached, img)
+			}
+			ref, err := name.ParseReference(tc.ref)
+			if err != nil {
+				t.Fatalf("name.ParseReference(...): %v", err)
+			}
+
+			got, err := NewImageCacheFetcher(tc.fetcher, ic).Fetch(context.Background(), ref)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nFetch(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.fetches, tc.fetcher.fetches); diff != "" {
+				t.Errorf("\n%s\nFetch(...): -want fetches, +got fetches:\n%s", tc.reason, diff)
+			}
+			for _, c := range tc.want.cached {
+				if _, err := ic.Get(c); err != nil {
+					t.Errorf("\n%s\nGet(%s): %v", tc.reason, c, err)
+				}
+			}
+			if got == nil {
+				return
+			}
+			d, err := got.Digest()
+			if err != nil {
+				t.Fatalf("got.Digest(): %v", err)
+			}
+			if diff := cmp.Diff(tc.want.digest, d); diff != "" {
+				t.Errorf("\n%s\nFetch(...): -want digest, +got digest:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
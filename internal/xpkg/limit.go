@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"io"
+	"net/http"
+	"sync"
+
+	"golang.org/x/sync/semaphore"
+	"golang.org/x/time/rate"
+)
+
+// A PullLimiter limits how many requests may be made to package registries at
+// once, and how often. A single PullLimiter is intended to be shared by all of
+// the Fetchers that pull packages, so that installing many packages at once
+// doesn't exceed a registry's rate limits. Its limits may be changed while
+// it's in use.
+type PullLimiter struct {
+	mu         sync.RWMutex
+	concurrent *semaphore.Weighted
+	rate       *rate.Limiter
+}
+
+// NewPullLimiter returns a PullLimiter that allows at most the supplied number
+// of concurrent requests, made at the supplied rate per second with the
+// supplied burst. A concurrency or rate of zero means no limit.
+func NewPullLimiter(concurrency int, perSecond float64, burst int) *PullLimiter {
+	l := &PullLimiter{}
+	l.SetLimits(concurrency, perSecond, burst)
+	return l
+}
+
+// SetLimits changes the limiter's limits. A concurrency or rate of zero means
+// no limit. Requests that are already in flight count toward the limits they
+// were allowed by.
+func (l *PullLimiter) SetLimits(concurrency int, perSecond float64, burst int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.concurrent = nil
+	if concurrency > 0 {
+		l.concurrent = semaphore.NewWeighted(int64(concurrency))
+	}
+	l.rate = rate.NewLimiter(rate.Inf, 0)
+	if perSecond > 0 {
+		l.rate = rate.NewLimiter(rate.Limit(perSecond), max(burst, 1))
+	}
+}
+
+// limits returns the limiter's rate limiter, and the semaphore that limits
+// concurrent requests, or nil if they're unlimited.
+func (l *PullLimiter) limits() (*rate.Limiter, *semaphore.Weighted) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.rate, l.concurrent
+}
+
+// RoundTripper returns a RoundTripper that limits the requests made using the
+// supplied RoundTripper. It returns the supplied RoundTripper if the limiter
+// is nil.
+func (l *PullLimiter) RoundTripper(rt http.RoundTripper) http.RoundTripper {
+	if l == nil {
+		return rt
+	}
+	return &limitedRoundTripper{wrapped: rt, limiter: l}
+}
+
+type limitedRoundTripper struct {
+	wrapped http.RoundTripper
+	limiter *PullLimiter
+}
+
+// RoundTrip waits until the request is allowed, then makes it. A request
+// counts toward the concurrency limit until its response body is closed,
+// because layers are pulled while their response body is read.
+func (t *limitedRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rl, sem := t.limiter.limits()
+	if err := rl.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	if sem == nil {
+		return t.wrapped.RoundTrip(req)
+	}
+	if err := sem.Acquire(req.Context(), 1); err != nil {
+		return nil, err
+	}
+	rsp, err := t.wrapped.RoundTrip(req)
+	if err != nil {
+		sem.Release(1)
+		return nil, err
+	}
+	rsp.Body = &releasingBody{ReadCloser: rsp.Body, release: func() { sem.Release(1) }}
+	return rsp, nil
+}
+
+// A releasingBody releases its slot of a PullLimiter when it's closed.
+type releasingBody struct {
+	io.ReadCloser
+
+	once    sync.Once
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
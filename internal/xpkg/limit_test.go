@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPullLimiterRoundTripper(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("layer"))
+	}))
+	defer srv.Close()
+
+	type args struct {
+		l *PullLimiter
+	}
+	type want struct {
+		limited bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoLimiter": {
+			reason: "A nil limiter should not limit requests.",
+			args: args{
+				l: nil,
+			},
+		},
+		"Unlimited": {
+			reason: "A limiter with no concurrency or rate limit should not limit requests.",
+			args: args{
+				l: NewPullLimiter(0, 0, 0),
+			},
+		},
+		"ConcurrencyLimited": {
+			reason: "A request should wait until an earlier request's response body is closed if the concurrency limit is reached.",
+			args: args{
+				l: NewPullLimiter(1, 0, 0),
+			},
+			want: want{
+				limited: true,
+			},
+		},
+		"LimitsRemoved": {
+			reason: "A limiter whose limits were removed should not limit requests.",
+			args: args{
+				l: func() *PullLimiter {
+					l := NewPullLimiter(1, 0.01, 1)
+					l.SetLimits(0, 0, 0)
+					return l
+				}(),
+			},
+		},
+		"LimitsAdded": {
+			reason: "A limiter whose limits were added should limit requests.",
+			args: args{
+				l: func() *PullLimiter {
+					l := NewPullLimiter(0, 0, 0)
+					l.SetLimits(1, 0, 0)
+					return l
+				}(),
+			},
+			want: want{
+				limited: true,
+			},
+		},
+		"RateLimited": {
+			reason: "A request should wait for the rate limiter if the burst is exhausted.",
+			args: args{
+				l: NewPullLimiter(0, 0.01, 1),
+			},
+			want: want{
+				limited: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &http.Client{Transport: tc.args.l.RoundTripper(http.DefaultTransport)}
+
+			// The first request's response body stays open, holding its
+			// slot of the concurrency limit and consuming the burst.
+			req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, nil)
+			first, err := c.Do(req)
+			if err != nil {
+				t.Fatalf("\n%s\nDo(...): %v", tc.reason, err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			req, _ = http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+			second, err := c.Do(req)
+			if second != nil {
+				_, _ = io.Copy(io.Discard, second.Body)
+				_ = second.Body.Close()
+			}
+			// A limited request fails because its context is cancelled
+			// before it's allowed.
+			if diff := cmp.Diff(tc.want.limited, err != nil); diff != "" {
+				t.Errorf("\n%s\nDo(...): -want limited, +got limited:\n%s\nerror: %v", tc.reason, diff, err)
+			}
+
+			_ = first.Body.Close()
+		})
+	}
+}
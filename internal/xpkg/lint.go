@@ -17,13 +17,17 @@ limitations under the License.
 package xpkg
 
 import (
+	"strings"
+
 	"github.com/Masterminds/semver"
 	admv1 "k8s.io/api/admissionregistration/v1"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	extv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/feature"
 	"github.com/crossplane/crossplane-runtime/pkg/parser"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
@@ -42,8 +46,14 @@ const (
 	errNotMutatingWebhookConfiguration   = "object is not a MutatingWebhookConfiguration"
 	errNotValidatingWebhookConfiguration = "object is not an ValidatingWebhookConfiguration"
 	errNotComposition                    = "object is not a Composition"
+	errNotProviderConfig                 = "object is not a ProviderConfig"
 	errBadConstraints                    = "package version constraints are poorly formatted"
 	errFmtCrossplaneIncompatible         = "package is not compatible with Crossplane version (%s)"
+	errFmtCrossplaneConstraintInvalid    = "package Crossplane version constraint %q is invalid"
+	errFmtCrossplaneVersionUnsatisfied   = "Crossplane version %s does not satisfy the package's Crossplane version constraint %q"
+	errFmtRuntimeVersionInvalid          = "package crossplane-runtime version %q is invalid"
+	errFmtRuntimeSkew                    = "crossplane-runtime version %s is known not to work with Crossplane version %s: %s"
+	errFmtFeaturesDisabled               = "package requires Crossplane features that are not enabled: %s"
 )
 
 // NewProviderLinter is a convenience function for creating a package linter for
@@ -60,7 +70,7 @@ func NewProviderLinter() parser.Linter {
 // NewConfigurationLinter is a convenience function for creating a package linter for
 // configurations.
 func NewConfigurationLinter() parser.Linter {
-	return parser.NewPackageLinter(parser.PackageLinterFns(OneMeta), parser.ObjectLinterFns(IsConfiguration, PackageValidSemver), parser.ObjectLinterFns(parser.Or(IsXRD, IsComposition)))
+	return parser.NewPackageLinter(parser.PackageLinterFns(OneMeta), parser.ObjectLinterFns(IsConfiguration, PackageValidSemver), parser.ObjectLinterFns(parser.Or(IsXRD, IsComposition, IsProviderConfig)))
 }
 
 // NewFunctionLinter is a convenience function for creating a package linter for
@@ -116,12 +126,73 @@ func PackageCrossplaneCompatible(v version.Operations) parser.ObjectLinterFn {
 		if p.GetCrossplaneConstraints() == nil {
 			return nil
 		}
-		in, err := v.InConstraints(p.GetCrossplaneConstraints().Version)
+		c := p.GetCrossplaneConstraints().Version
+		if _, err := semver.NewConstraint(c); err != nil {
+			return errors.Wrapf(err, errFmtCrossplaneConstraintInvalid, c)
+		}
+		in, err := v.InConstraints(c)
 		if err != nil {
 			return errors.Wrapf(err, errFmtCrossplaneIncompatible, v.GetVersionString())
 		}
 		if !in {
-			return errors.Errorf(errFmtCrossplaneIncompatible, v.GetVersionString())
+			return errors.Errorf(errFmtCrossplaneVersionUnsatisfied, v.GetVersionString(), c)
+		}
+		return nil
+	}
+}
+
+// PackageRuntimeCompatible checks that the version of crossplane-runtime the
+// package was built with isn't known not to work with the current Crossplane
+// version, per the supplied skews.
+func PackageRuntimeCompatible(v version.Operations, skews []version.Skew) parser.ObjectLinterFn {
+	return func(o runtime.Object) error {
+		p, ok := TryConvertToPkg(o, &pkgmetav1.Provider{}, &pkgmetav1.Configuration{}, &pkgmetav1.Function{})
+		if !ok {
+			return errors.New(errNotMeta)
+		}
+
+		if p.GetCrossplaneConstraints() == nil || p.GetCrossplaneConstraints().Runtime == "" {
+			return nil
+		}
+		rv := p.GetCrossplaneConstraints().Runtime
+		rt, err := semver.NewVersion(rv)
+		if err != nil {
+			return errors.Wrapf(err, errFmtRuntimeVersionInvalid, rv)
+		}
+		for _, s := range skews {
+			rc, err := semver.NewConstraint(s.Runtime)
+			if err != nil || !rc.Check(rt) {
+				continue
+			}
+			in, err := v.InConstraints(s.Crossplane)
+			if err != nil {
+				return errors.Wrapf(err, errFmtCrossplaneIncompatible, v.GetVersionString())
+			}
+			if in {
+				return errors.Errorf(errFmtRuntimeSkew, rv, v.GetVersionString(), s.Reason)
+			}
+		}
+		return nil
+	}
+}
+
+// PackageFeaturesEnabled checks that the Crossplane feature flags the package
+// requires are enabled.
+func PackageFeaturesEnabled(f *feature.Flags) parser.ObjectLinterFn {
+	return func(o runtime.Object) error {
+		p, ok := TryConvertToPkg(o, &pkgmetav1.Provider{}, &pkgmetav1.Configuration{}, &pkgmetav1.Function{})
+		if !ok {
+			return errors.New(errNotMeta)
+		}
+
+		var disabled []string
+		for _, name := range p.GetRequiredFeatures() {
+			if !f.Enabled(feature.Flag(name)) {
+				disabled = append(disabled, name)
+			}
+		}
+		if len(disabled) > 0 {
+			return errors.Errorf(errFmtFeaturesDisabled, strings.Join(disabled, ", "))
 		}
 		return nil
 	}
@@ -184,3 +255,11 @@ func IsComposition(o runtime.Object) error {
 	}
 	return nil
 }
+
+// IsProviderConfig checks that an object is a ProviderConfig of any API group.
+func IsProviderConfig(o runtime.Object) error {
+	if _, ok := o.(*unstructured.Unstructured); !ok || o.GetObjectKind().GroupVersionKind().Kind != ProviderConfigKind {
+		return errors.New(errNotProviderConfig)
+	}
+	return nil
+}
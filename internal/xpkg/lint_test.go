@@ -23,12 +23,15 @@ import (
 	"io"
 	"testing"
 
+	"github.com/Masterminds/semver"
 	"github.com/google/go-cmp/cmp"
 	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/yaml"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/feature"
 	"github.com/crossplane/crossplane-runtime/pkg/parser"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
@@ -327,7 +330,22 @@ func TestPackageCrossplaneCompatible(t *testing.T) {
 					MockGetVersionString: fake.NewMockGetVersionStringFn("v0.12.0"),
 				},
 			},
-			err: errors.Errorf(errFmtCrossplaneIncompatible, "v0.12.0"),
+			err: errors.Errorf(errFmtCrossplaneVersionUnsatisfied, "v0.12.0", crossplaneConstraint),
+		},
+		"ErrUnparseableConstraints": {
+			reason: "Should return error if constraints can't be parsed.",
+			args: args{
+				obj: &pkgmetav1.Configuration{
+					Spec: pkgmetav1.ConfigurationSpec{
+						MetaSpec: pkgmetav1.MetaSpec{
+							Crossplane: &pkgmetav1.CrossplaneConstraints{
+								Version: ">a0.13.0",
+							},
+						},
+					},
+				},
+			},
+			err: errors.Wrapf(errors.New("improper constraint: >a0.13.0"), errFmtCrossplaneConstraintInvalid, ">a0.13.0"),
 		},
 		"ErrNotMeta": {
 			reason: "Should return error if object is not a meta package type.",
@@ -349,6 +367,168 @@ func TestPackageCrossplaneCompatible(t *testing.T) {
 	}
 }
 
+func TestPackageRuntimeCompatible(t *testing.T) {
+	errBoom := errors.New("boom")
+	skews := []version.Skew{{Runtime: ">=v1.14.0", Crossplane: "<v1.14.0", Reason: "broken"}}
+
+	withRuntime := func(rv string) *pkgmetav1.Provider {
+		return &pkgmetav1.Provider{
+			Spec: pkgmetav1.ProviderSpec{
+				MetaSpec: pkgmetav1.MetaSpec{
+					Crossplane: &pkgmetav1.CrossplaneConstraints{
+						Version: ">=v1.0.0",
+						Runtime: rv,
+					},
+				},
+			},
+		}
+	}
+
+	type args struct {
+		obj runtime.Object
+		ver version.Operations
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		err    error
+	}{
+		"SuccessfulNoRuntime": {
+			reason: "Should not return error if the package doesn't declare a crossplane-runtime version.",
+			args: args{
+				obj: v1ProvMeta,
+			},
+		},
+		"SuccessfulUnaffectedRuntime": {
+			reason: "Should not return error if no skew applies to the package's crossplane-runtime version.",
+			args: args{
+				obj: withRuntime("v1.13.0"),
+			},
+		},
+		"SuccessfulUnaffectedCrossplane": {
+			reason: "Should not return error if no skew applies to the current Crossplane version.",
+			args: args{
+				obj: withRuntime("v1.14.0"),
+				ver: &fake.MockVersioner{
+					MockInConstraints: fake.NewMockInConstraintsFn(false, nil),
+				},
+			},
+		},
+		"ErrInvalidRuntime": {
+			reason: "Should return error if the package's crossplane-runtime version is invalid.",
+			args: args{
+				obj: withRuntime("latest"),
+			},
+			err: errors.Wrapf(semver.ErrInvalidSemVer, errFmtRuntimeVersionInvalid, "latest"),
+		},
+		"ErrInConstraints": {
+			reason: "Should return error if we can't check the current Crossplane version.",
+			args: args{
+				obj: withRuntime("v1.14.0"),
+				ver: &fake.MockVersioner{
+					MockInConstraints:    fake.NewMockInConstraintsFn(false, errBoom),
+					MockGetVersionString: fake.NewMockGetVersionStringFn("v1.13.0"),
+				},
+			},
+			err: errors.Wrapf(errBoom, errFmtCrossplaneIncompatible, "v1.13.0"),
+		},
+		"ErrKnownBrokenSkew": {
+			reason: "Should return error if the package's crossplane-runtime version is known not to work with the current Crossplane version.",
+			args: args{
+				obj: withRuntime("v1.14.0"),
+				ver: &fake.MockVersioner{
+					MockInConstraints:    fake.NewMockInConstraintsFn(true, nil),
+					MockGetVersionString: fake.NewMockGetVersionStringFn("v1.13.0"),
+				},
+			},
+			err: errors.Errorf(errFmtRuntimeSkew, "v1.14.0", "v1.13.0", "broken"),
+		},
+		"ErrNotMeta": {
+			reason: "Should return error if object is not a meta package type.",
+			args: args{
+				obj: v1crd,
+			},
+			err: errors.New(errNotMeta),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := PackageRuntimeCompatible(tc.args.ver, skews)(tc.args.obj)
+
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nPackageRuntimeCompatible(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPackageFeaturesEnabled(t *testing.T) {
+	enabled := &feature.Flags{}
+	enabled.Enable(feature.Flag("EnableAlphaA"))
+
+	type args struct {
+		obj runtime.Object
+		f   *feature.Flags
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		err    error
+	}{
+		"SuccessfulNoRequiredFeatures": {
+			reason: "Should not return error if the package requires no features.",
+			args: args{
+				obj: v1ProvMeta,
+			},
+		},
+		"SuccessfulFeaturesEnabled": {
+			reason: "Should not return error if all required features are enabled.",
+			args: args{
+				obj: &pkgmetav1.Configuration{
+					Spec: pkgmetav1.ConfigurationSpec{
+						MetaSpec: pkgmetav1.MetaSpec{
+							RequiredFeatures: []string{"EnableAlphaA"},
+						},
+					},
+				},
+				f: enabled,
+			},
+		},
+		"ErrFeaturesDisabled": {
+			reason: "Should return error if any required feature is not enabled.",
+			args: args{
+				obj: &pkgmetav1.Configuration{
+					Spec: pkgmetav1.ConfigurationSpec{
+						MetaSpec: pkgmetav1.MetaSpec{
+							RequiredFeatures: []string{"EnableAlphaA", "EnableAlphaB", "EnableAlphaC"},
+						},
+					},
+				},
+				f: enabled,
+			},
+			err: errors.Errorf(errFmtFeaturesDisabled, "EnableAlphaB, EnableAlphaC"),
+		},
+		"ErrNotMeta": {
+			reason: "Should return error if object is not a meta package type.",
+			args: args{
+				obj: v1crd,
+			},
+			err: errors.New(errNotMeta),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := PackageFeaturesEnabled(tc.args.f)(tc.args.obj)
+
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nPackageFeaturesEnabled(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestPackageValidSemver(t *testing.T) {
 	validConstraint := ">v0.13.0"
 	invalidConstraint := ">a0.13.0"
@@ -490,3 +670,44 @@ func TestIsComposition(t *testing.T) {
 		})
 	}
 }
+
+func TestIsProviderConfig(t *testing.T) {
+	pc := &unstructured.Unstructured{}
+	pc.SetAPIVersion("nop.crossplane.io/v1alpha1")
+	pc.SetKind(ProviderConfigKind)
+
+	other := &unstructured.Unstructured{}
+	other.SetAPIVersion("nop.crossplane.io/v1alpha1")
+	other.SetKind("NopResource")
+
+	cases := map[string]struct {
+		reason string
+		obj    runtime.Object
+		err    error
+	}{
+		"ProviderConfig": {
+			reason: "Should not return error if object is a ProviderConfig.",
+			obj:    pc,
+		},
+		"ErrNotProviderConfig": {
+			reason: "Should return error if object is an unstructured object of another kind.",
+			obj:    other,
+			err:    errors.New(errNotProviderConfig),
+		},
+		"ErrNotUnstructured": {
+			reason: "Should return error if object is not unstructured.",
+			obj:    v1Comp,
+			err:    errors.New(errNotProviderConfig),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := IsProviderConfig(tc.obj)
+
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nIsProviderConfig(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
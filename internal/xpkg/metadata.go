@@ -0,0 +1,55 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+// Package image config labels that describe how a package was built. The OCI
+// labels are typically set by a CI pipeline, e.g. using the --label flag of
+// crossplane xpkg build.
+const (
+	LabelBuilderVersion = AnnotationKey + ".builder.version"
+	LabelCreated        = "org.opencontainers.image.created"
+	LabelSource         = "org.opencontainers.image.source"
+	LabelRevision       = "org.opencontainers.image.revision"
+	LabelVersion        = "org.opencontainers.image.version"
+)
+
+// ParseBuildMetadata returns the build metadata recorded in the labels of the
+// supplied package image config. It returns nil if the config has none of the
+// build metadata labels.
+func ParseBuildMetadata(cfg *v1.ConfigFile) *pkgv1.PackageBuild {
+	if cfg == nil {
+		return nil
+	}
+	l := cfg.Config.Labels
+	b := &pkgv1.PackageBuild{
+		BuilderVersion: l[LabelBuilderVersion],
+		Created:        l[LabelCreated],
+		Source:         l[LabelSource],
+		Revision:       l[LabelRevision],
+		Version:        l[LabelVersion],
+	}
+	if *b == (pkgv1.PackageBuild{}) {
+		return nil
+	}
+	return b
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+func TestParseBuildMetadata(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		cfg    *v1.ConfigFile
+		want   *pkgv1.PackageBuild
+	}{
+		"NilConfig": {
+			reason: "We should return nil if there's no config.",
+		},
+		"NoLabels": {
+			reason: "We should return nil if the config has no build metadata labels.",
+			cfg: &v1.ConfigFile{Config: v1.Config{Labels: map[string]string{
+				Label("sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904"): PackageAnnotation,
+			}}},
+		},
+		"Labels": {
+			reason: "We should return the build metadata recorded in the config's labels.",
+			cfg: &v1.ConfigFile{Config: v1.Config{Labels: map[string]string{
+				LabelBuilderVersion: "v1.16.0",
+				LabelCreated:        "2024-06-01T12:00:00Z",
+				LabelSource:         "https://github.com/crossplane-contrib/provider-nop",
+				LabelRevision:       "0123456789abcdef",
+				LabelVersion:        "v0.1.0",
+			}}},
+			want: &pkgv1.PackageBuild{
+				BuilderVersion: "v1.16.0",
+				Created:        "2024-06-01T12:00:00Z",
+				Source:         "https://github.com/crossplane-contrib/provider-nop",
+				Revision:       "0123456789abcdef",
+				Version:        "v0.1.0",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ParseBuildMetadata(tc.cfg)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nParseBuildMetadata(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"strings"
 
 	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/spf13/afero"
 	"sigs.k8s.io/yaml"
 )
@@ -133,6 +134,29 @@ func ParsePackageSourceFromReference(ref name.Reference) string {
 	return strings.TrimRight(strings.TrimSuffix(ref.String(), ref.Identifier()), identifierDelimeters)
 }
 
+// PinnedSource returns the supplied package source pinned to the supplied
+// digest, e.g. registry/org/repo:v1.0.0@sha256:... Any tag is kept, so that a
+// pinned source's version can still be determined. The digest takes precedence
+// when the package is pulled.
+func PinnedSource(source string, h v1.Hash) string {
+	return source + "@" + h.String()
+}
+
+// UnpinnedSource returns the supplied package source with its digest removed,
+// if it has both a tag and a digest. It returns other sources unchanged.
+func UnpinnedSource(source string) string {
+	base, _, ok := strings.Cut(source, "@")
+	if !ok {
+		return source
+	}
+	// The last path element has a tag if it contains a colon. A colon
+	// elsewhere (e.g. registry:5000/repo) is a port.
+	if strings.LastIndex(base, ":") > strings.LastIndex(base, "/") {
+		return base
+	}
+	return source
+}
+
 type metaPkg struct {
 	Metadata struct {
 		Name string `json:"name"`
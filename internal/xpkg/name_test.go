@@ -21,6 +21,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 )
 
 func TestFriendlyID(t *testing.T) {
@@ -180,6 +181,47 @@ func TestSourceFromReference(t *testing.T) {
 	}
 }
 
+func TestUnpinnedSource(t *testing.T) {
+	digest := "sha256:c88b938d6e7b2ed43d40b71e5a55df9c60fa653bea0c0961f3294fac46d5b56e"
+	h, _ := v1.NewHash(digest)
+
+	cases := map[string]struct {
+		reason string
+		source string
+		want   string
+	}{
+		"Tag": {
+			reason: "A source with only a tag should be returned unchanged.",
+			source: "xpkg.upbound.io/crossplane/provider-nop:v0.1.0",
+			want:   "xpkg.upbound.io/crossplane/provider-nop:v0.1.0",
+		},
+		"Digest": {
+			reason: "A source with only a digest should be returned unchanged.",
+			source: "xpkg.upbound.io/crossplane/provider-nop@" + digest,
+			want:   "xpkg.upbound.io/crossplane/provider-nop@" + digest,
+		},
+		"DigestWithPort": {
+			reason: "A registry port should not be mistaken for a tag.",
+			source: "registry:5000/crossplane/provider-nop@" + digest,
+			want:   "registry:5000/crossplane/provider-nop@" + digest,
+		},
+		"Pinned": {
+			reason: "A source pinned to a digest should have its digest removed.",
+			source: PinnedSource("registry:5000/crossplane/provider-nop:v0.1.0", h),
+			want:   "registry:5000/crossplane/provider-nop:v0.1.0",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := UnpinnedSource(tc.source)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nUnpinnedSource(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestBuildPath(t *testing.T) {
 	type args struct {
 		path string
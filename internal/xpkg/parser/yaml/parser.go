@@ -31,7 +31,7 @@ const (
 )
 
 // New returns a new PackageParser that targets yaml files.
-func New() (*parser.PackageParser, error) {
+func New() (parser.Parser, error) {
 	metaScheme, err := xpkg.BuildMetaScheme()
 	if err != nil {
 		return nil, errors.New(errBuildMetaScheme)
@@ -41,5 +41,5 @@ func New() (*parser.PackageParser, error) {
 		return nil, errors.New(errBuildObjectScheme)
 	}
 
-	return parser.New(metaScheme, objScheme), nil
+	return xpkg.NewParser(metaScheme, objScheme), nil
 }
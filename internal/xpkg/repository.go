@@ -0,0 +1,187 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+const (
+	errListPackageRepositories = "cannot list package repositories"
+	errFmtMirrorReference      = "cannot parse reference %q mirrored by package repository %q"
+	errFmtGetCABundleSecret    = "cannot get CA bundle Secret %q of package repository %q"
+	errFmtNoCABundleKey        = "CA bundle Secret %q of package repository %q has no key %q"
+	errFmtParseCABundle        = "cannot parse CA bundle of package repository %q"
+)
+
+// A Repository configures how packages whose references match its prefix are
+// fetched.
+type Repository struct {
+	// Name of the repository.
+	Name string
+
+	// Version identifies the repository's configuration. It changes when the
+	// repository's TLS configuration changes.
+	Version string
+
+	// Prefix of the package references that belong to the repository.
+	Prefix string
+
+	// Mirror replaces the prefix of package references that belong to the
+	// repository, if it's not empty.
+	Mirror string
+
+	// PullSecrets used to fetch packages from the repository.
+	PullSecrets []string
+
+	// TLS configuration used to connect to the repository. The fetcher's own
+	// TLS configuration is used if it's nil.
+	TLS *tls.Config
+}
+
+// Reference returns the reference the supplied package reference should be
+// fetched from. This is the package reference with the repository's prefix
+// replaced by its mirror, if it has one.
+func (r *Repository) Reference(ref name.Reference) (name.Reference, error) {
+	if r.Mirror == "" {
+		return ref, nil
+	}
+	m := strings.TrimSuffix(r.Mirror, "/") + strings.TrimPrefix(ref.Name(), r.Prefix)
+	mref, err := name.ParseReference(m, name.StrictValidation)
+	return mref, errors.Wrapf(err, errFmtMirrorReference, m, r.Name)
+}
+
+// A RepositoryResolver resolves the Repository a package reference belongs to.
+type RepositoryResolver interface {
+	// ResolveRepository returns the Repository the supplied reference belongs
+	// to, or nil if it doesn't belong to one.
+	ResolveRepository(ctx context.Context, ref name.Reference) (*Repository, error)
+}
+
+// A PackageRepositoryResolver resolves Repositories from PackageRepositories.
+type PackageRepositoryResolver struct {
+	client    client.Reader
+	namespace string
+}
+
+// NewPackageRepositoryResolver returns a RepositoryResolver that resolves
+// Repositories from PackageRepositories. Secrets are read from the supplied
+// namespace.
+func NewPackageRepositoryResolver(c client.Reader, namespace string) *PackageRepositoryResolver {
+	return &PackageRepositoryResolver{client: c, namespace: namespace}
+}
+
+// ResolveRepository returns the Repository configured by the
+// PackageRepository with the longest prefix that matches the supplied
+// reference, or nil if none match.
+func (r *PackageRepositoryResolver) ResolveRepository(ctx context.Context, ref name.Reference) (*Repository, error) {
+	l := &v1beta1.PackageRepositoryList{}
+	if err := r.client.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, errListPackageRepositories)
+	}
+	pr := matchPackageRepository(l.Items, ref.Name())
+	if pr == nil {
+		return nil, nil
+	}
+
+	repo := &Repository{
+		Name:    pr.GetName(),
+		Version: pr.GetResourceVersion(),
+		Prefix:  pr.Spec.Prefix,
+	}
+	if pr.Spec.Mirror != nil {
+		repo.Mirror = *pr.Spec.Mirror
+	}
+	for _, s := range pr.Spec.PullSecrets {
+		repo.PullSecrets = append(repo.PullSecrets, s.Name)
+	}
+
+	if pr.Spec.TLS == nil {
+		return repo, nil
+	}
+	repo.TLS = &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: pr.Spec.TLS.InsecureSkipVerify, //nolint:gosec // Users must explicitly opt in, e.g. for testing.
+	}
+
+	if sel := pr.Spec.TLS.CABundleSecretRef; sel != nil {
+		s := &corev1.Secret{}
+		if err := r.client.Get(ctx, types.NamespacedName{Namespace: r.namespace, Name: sel.Name}, s); err != nil {
+			return nil, errors.Wrapf(err, errFmtGetCABundleSecret, sel.Name, pr.GetName())
+		}
+		data, ok := s.Data[sel.Key]
+		if !ok {
+			return nil, errors.Errorf(errFmtNoCABundleKey, sel.Name, pr.GetName(), sel.Key)
+		}
+		certs, err := ParseCertificates(data)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtParseCABundle, pr.GetName())
+		}
+		repo.TLS.RootCAs = x509.NewCertPool()
+		for _, c := range certs {
+			repo.TLS.RootCAs.AddCert(c)
+		}
+		// The CA bundle is only read when the repository is resolved, so a
+		// new bundle must produce a new version.
+		repo.Version += "/" + s.GetResourceVersion()
+	}
+
+	if sel := pr.Spec.TLS.ClientCertSecretRef; sel != nil {
+		// Like WithClientCertSecret, the certificate is read whenever a
+		// connection is established so that it can be rotated.
+		repo.TLS.GetClientCertificate = func(cri *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			s := &corev1.Secret{}
+			if err := r.client.Get(cri.Context(), types.NamespacedName{Namespace: r.namespace, Name: sel.Name}, s); err != nil {
+				return nil, errors.Wrapf(err, errFmtGetClientCertSecret, sel.Name)
+			}
+			c, err := tls.X509KeyPair(s.Data[corev1.TLSCertKey], s.Data[corev1.TLSPrivateKeyKey])
+			if err != nil {
+				return nil, errors.Wrapf(err, errFmtParseClientCert, sel.Name)
+			}
+			return &c, nil
+		}
+	}
+
+	return repo, nil
+}
+
+// matchPackageRepository returns the PackageRepository with the longest prefix
+// matching the supplied image, or nil if none match.
+func matchPackageRepository(prs []v1beta1.PackageRepository, image string) *v1beta1.PackageRepository {
+	var match *v1beta1.PackageRepository
+	for i := range prs {
+		if !strings.HasPrefix(image, prs[i].Spec.Prefix) {
+			continue
+		}
+		if match == nil || len(prs[i].Spec.Prefix) > len(match.Spec.Prefix) {
+			match = &prs[i]
+		}
+	}
+	return match
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/go-containerregistry/pkg/name"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+var _ RepositoryResolver = &PackageRepositoryResolver{}
+
+type RepositoryResolverFn func(ctx context.Context, ref name.Reference) (*Repository, error)
+
+func (fn RepositoryResolverFn) ResolveRepository(ctx context.Context, ref name.Reference) (*Repository, error) {
+	return fn(ctx, ref)
+}
+
+func TestPackageRepositoryResolver(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	repos := []v1beta1.PackageRepository{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "upbound", ResourceVersion: "1"},
+			Spec:       v1beta1.PackageRepositorySpec{Prefix: "xpkg.upbound.io/"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "contrib", ResourceVersion: "2"},
+			Spec: v1beta1.PackageRepositorySpec{
+				Prefix:      "xpkg.upbound.io/crossplane-contrib",
+				Mirror:      ptr.To("registry.example.org/contrib"),
+				PullSecrets: []corev1.LocalObjectReference{{Name: "contrib-creds"}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "internal", ResourceVersion: "3"},
+			Spec: v1beta1.PackageRepositorySpec{
+				Prefix: "registry.internal/",
+				TLS: &v1beta1.PackageRepositoryTLS{
+					CABundleSecretRef: &v1beta1.SecretKeySelector{Name: "ca", Key: "ca.crt"},
+				},
+			},
+		},
+	}
+	list := func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+		obj.(*v1beta1.PackageRepositoryList).Items = repos
+		return nil
+	}
+
+	type args struct {
+		c   client.Reader
+		ref name.Reference
+	}
+	type want struct {
+		repo *Repository
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoMatch": {
+			reason: "We should return a nil Repository if no PackageRepository matches the reference.",
+			args: args{
+				c:   &test.MockClient{MockList: list},
+				ref: name.MustParseReference("index.docker.io/crossplane/provider-nop:v0.1.0"),
+			},
+			want: want{},
+		},
+		"LongestPrefix": {
+			reason: "We should return the Repository configured by the PackageRepository with the longest matching prefix.",
+			args: args{
+				c:   &test.MockClient{MockList: list},
+				ref: name.MustParseReference("xpkg.upbound.io/crossplane-contrib/provider-nop:v0.1.0"),
+			},
+			want: want{
+				repo: &Repository{
+					Name:        "contrib",
+					Version:     "2",
+					Prefix:      "xpkg.upbound.io/crossplane-contrib",
+					Mirror:      "registry.example.org/contrib",
+					PullSecrets: []string{"contrib-creds"},
+				},
+			},
+		},
+		"ListError": {
+			reason: "We should return an error if we can't list PackageRepositories.",
+			args: args{
+				c:   &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				ref: name.MustParseReference("xpkg.upbound.io/crossplane-contrib/provider-nop:v0.1.0"),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errListPackageRepositories),
+			},
+		},
+		"GetCABundleError": {
+			reason: "We should return an error if we can't get a PackageRepository's CA bundle.",
+			args: args{
+				c:   &test.MockClient{MockList: list, MockGet: test.NewMockGetFn(errBoom)},
+				ref: name.MustParseReference("registry.internal/provider-nop:v0.1.0"),
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtGetCABundleSecret, "ca", "internal"),
+			},
+		},
+		"NoCABundleKey": {
+			reason: "We should return an error if a PackageRepository's CA bundle Secret doesn't have the referenced key.",
+			args: args{
+				c:   &test.MockClient{MockList: list, MockGet: test.NewMockGetFn(nil)},
+				ref: name.MustParseReference("registry.internal/provider-nop:v0.1.0"),
+			},
+			want: want{
+				err: errors.Errorf(errFmtNoCABundleKey, "ca", "internal", "ca.crt"),
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			r := NewPackageRepositoryResolver(tc.args.c, "crossplane-system")
+			got, err := r.ResolveRepository(context.Background(), tc.args.ref)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nResolveRepository(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.repo, got, cmpopts.IgnoreFields(Repository{}, "TLS")); diff != "" {
+				t.Errorf("\n%s\nResolveRepository(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestK8sFetcherRepository(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		r       RepositoryResolver
+		ref     name.Reference
+		secrets []string
+	}
+	type want struct {
+		ref     string
+		secrets []string
+		tls     bool
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoRepository": {
+			reason: "References that don't belong to a Repository should be fetched unchanged.",
+			args: args{
+				r:       RepositoryResolverFn(func(_ context.Context, _ name.Reference) (*Repository, error) { return nil, nil }),
+				ref:     name.MustParseReference("xpkg.upbound.io/crossplane/provider-nop:v0.1.0"),
+				secrets: []string{"package-creds"},
+			},
+			want: want{
+				ref:     "xpkg.upbound.io/crossplane/provider-nop:v0.1.0",
+				secrets: []string{"package-creds"},
+			},
+		},
+		"Mirror": {
+			reason: "References that belong to a Repository should be fetched from its mirror, using its pull secrets and TLS configuration.",
+			args: args{
+				r: RepositoryResolverFn(func(_ context.Context, _ name.Reference) (*Repository, error) {
+					return &Repository{
+						Name:        "contrib",
+						Prefix:      "xpkg.upbound.io/crossplane-contrib",
+						Mirror:      "registry.example.org/contrib/",
+						PullSecrets: []string{"contrib-creds"},
+						TLS:         &tls.Config{MinVersion: tls.VersionTLS12},
+					}, nil
+				}),
+				ref:     name.MustParseReference("xpkg.upbound.io/crossplane-contrib/provider-nop:v0.1.0"),
+				secrets: []string{"package-creds"},
+			},
+			want: want{
+				ref:     "registry.example.org/contrib/provider-nop:v0.1.0",
+				secrets: []string{"package-creds", "contrib-creds"},
+				tls:     true,
+			},
+		},
+		"ResolveError": {
+			reason: "We should return an error if we can't resolve a reference's Repository.",
+			args: args{
+				r:   RepositoryResolverFn(func(_ context.Context, _ name.Reference) (*Repository, error) { return nil, errBoom }),
+				ref: name.MustParseReference("xpkg.upbound.io/crossplane/provider-nop:v0.1.0"),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errResolveRepository),
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			f, err := NewK8sFetcher(fake.NewSimpleClientset(), WithRepositories(tc.args.r))
+			if err != nil {
+				t.Fatalf("NewK8sFetcher(...): %v", err)
+			}
+			ref, rt, secrets, err := f.repository(context.Background(), tc.args.ref, tc.args.secrets...)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nrepository(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.ref, ref.Name()); diff != "" {
+				t.Errorf("\n%s\nrepository(...): -want reference, +got reference:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.secrets, secrets); diff != "" {
+				t.Errorf("\n%s\nrepository(...): -want secrets, +got secrets:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.tls, rt != f.transport); diff != "" {
+				t.Errorf("\n%s\nrepository(...): -want repository transport, +got repository transport:\n%s", tc.reason, diff)
+			}
+			if tc.want.tls && rt.(*http.Transport).TLSClientConfig == nil {
+				t.Errorf("\n%s\nrepository(...): want repository TLS config, got none", tc.reason)
+			}
+		})
+	}
+}
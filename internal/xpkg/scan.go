@@ -0,0 +1,204 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errFmtHarborProject      = "cannot determine Harbor project of repository %q"
+	errNewScanRequest        = "cannot create vulnerability report request"
+	errGetVulnerabilities    = "cannot get vulnerability report"
+	errFmtScanStatus         = "cannot get vulnerability report: unexpected status %q"
+	errDecodeVulnerabilities = "cannot decode vulnerability report"
+	errNoVulnerabilityReport = "image has not been scanned"
+)
+
+// harborReportMIMEType is the MIME type of the Harbor vulnerability report
+// format that a HarborScanner requests.
+const harborReportMIMEType = "application/vnd.security.vulnerability.report; version=1.1"
+
+// maxScanResponseBytes caps how much of a vulnerability report we'll read.
+const maxScanResponseBytes = 32 << 20
+
+// A Severity is the severity of a vulnerability.
+type Severity string
+
+// Vulnerability severities, from least to most severe.
+const (
+	SeverityUnknown  Severity = "Unknown"
+	SeverityLow      Severity = "Low"
+	SeverityMedium   Severity = "Medium"
+	SeverityHigh     Severity = "High"
+	SeverityCritical Severity = "Critical"
+)
+
+// rank orders severities. Severities we don't recognize rank as unknown.
+func (s Severity) rank() int {
+	switch s {
+	case SeverityLow:
+		return 1
+	case SeverityMedium:
+		return 2
+	case SeverityHigh:
+		return 3
+	case SeverityCritical:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// AtLeast returns true if the severity is as or more severe than the supplied
+// severity.
+func (s Severity) AtLeast(o Severity) bool {
+	return s.rank() >= o.rank()
+}
+
+// A Vulnerability found in an image.
+type Vulnerability struct {
+	// ID of the vulnerability, e.g. a CVE ID.
+	ID string
+
+	// Severity of the vulnerability.
+	Severity Severity
+
+	// Package that is vulnerable, and its version.
+	Package string
+	Version string
+
+	// FixVersion of the package, if any.
+	FixVersion string
+}
+
+// HarborCredentials authenticate requests to the Harbor API.
+type HarborCredentials struct {
+	Username string
+	Password string
+}
+
+// A HarborScanner reads the vulnerability report produced when a Harbor
+// registry scanned an image. It doesn't trigger scans; Harbor should be
+// configured to scan images when they're pushed.
+type HarborScanner struct {
+	client *http.Client
+}
+
+// A HarborScannerOption configures a HarborScanner.
+type HarborScannerOption func(s *HarborScanner)
+
+// WithScanHTTPClient configures the HTTP client a HarborScanner uses to call
+// the Harbor API.
+func WithScanHTTPClient(c *http.Client) HarborScannerOption {
+	return func(s *HarborScanner) {
+		s.client = c
+	}
+}
+
+// NewHarborScanner returns a HarborScanner.
+func NewHarborScanner(o ...HarborScannerOption) *HarborScanner {
+	s := &HarborScanner{client: http.DefaultClient}
+	for _, fn := range o {
+		fn(s)
+	}
+	return s
+}
+
+type harborReport struct {
+	Vulnerabilities []harborVulnerability `json:"vulnerabilities"`
+}
+
+type harborVulnerability struct {
+	ID         string `json:"id"`
+	Package    string `json:"package"`
+	Version    string `json:"version"`
+	FixVersion string `json:"fix_version"`
+	Severity   string `json:"severity"`
+}
+
+// Scan returns the vulnerabilities Harbor found in the supplied image. Harbor
+// is called at the supplied URL, or at the image's registry if the URL is
+// empty. Credentials are optional.
+func (s *HarborScanner) Scan(ctx context.Context, ref name.Reference, api string, creds *HarborCredentials) ([]Vulnerability, error) {
+	// Harbor repositories are named <project>/<repository>, where the
+	// repository may itself contain slashes.
+	project, repo, ok := strings.Cut(ref.Context().RepositoryStr(), "/")
+	if !ok {
+		return nil, errors.Errorf(errFmtHarborProject, ref.Context().RepositoryStr())
+	}
+	if api == "" {
+		api = "https://" + ref.Context().RegistryStr()
+	}
+
+	// Harbor requires slashes in repository names to be double escaped.
+	u := fmt.Sprintf("%s/api/v2.0/projects/%s/repositories/%s/artifacts/%s/additions/vulnerabilities",
+		strings.TrimSuffix(api, "/"),
+		url.PathEscape(project),
+		url.PathEscape(url.PathEscape(repo)),
+		url.PathEscape(ref.Identifier()))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewScanRequest)
+	}
+	req.Header.Set("X-Accept-Vulnerabilities", harborReportMIMEType)
+	if creds != nil {
+		req.SetBasicAuth(creds.Username, creds.Password)
+	}
+
+	rsp, err := s.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetVulnerabilities)
+	}
+	defer rsp.Body.Close() //nolint:errcheck // Only reading the body.
+
+	if rsp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf(errFmtScanStatus, rsp.Status)
+	}
+
+	reports := map[string]harborReport{}
+	if err := json.NewDecoder(io.LimitReader(rsp.Body, maxScanResponseBytes)).Decode(&reports); err != nil {
+		return nil, errors.Wrap(err, errDecodeVulnerabilities)
+	}
+	report, ok := reports[harborReportMIMEType]
+	if !ok {
+		return nil, errors.New(errNoVulnerabilityReport)
+	}
+
+	vulns := make([]Vulnerability, len(report.Vulnerabilities))
+	for i, v := range report.Vulnerabilities {
+		vulns[i] = Vulnerability{
+			ID:         v.ID,
+			Severity:   Severity(v.Severity),
+			Package:    v.Package,
+			Version:    v.Version,
+			FixVersion: v.FixVersion,
+		}
+	}
+	return vulns, nil
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestHarborScannerScan(t *testing.T) {
+	report := `{"application/vnd.security.vulnerability.report; version=1.1": {
+		"vulnerabilities": [
+			{"id": "CVE-2024-0001", "package": "openssl", "version": "3.0.0", "fix_version": "3.0.1", "severity": "Critical"}
+		]
+	}}`
+
+	type args struct {
+		handler http.HandlerFunc
+		ref     name.Reference
+		creds   *HarborCredentials
+	}
+	type want struct {
+		vulns []Vulnerability
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Vulnerabilities": {
+			reason: "We should request the artifact's vulnerability report, double escaping slashes in the repository name.",
+			args: args{
+				handler: func(w http.ResponseWriter, r *http.Request) {
+					u, p, _ := r.BasicAuth()
+					if r.URL.EscapedPath() != "/api/v2.0/projects/crossplane/repositories/contrib%252Fprovider-nop/artifacts/v0.1.0/additions/vulnerabilities" || u != "robot" || p != "secret" {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+					_, _ = w.Write([]byte(report))
+				},
+				ref:   name.MustParseReference("harbor.example.org/crossplane/contrib/provider-nop:v0.1.0"),
+				creds: &HarborCredentials{Username: "robot", Password: "secret"},
+			},
+			want: want{
+				vulns: []Vulnerability{{ID: "CVE-2024-0001", Severity: SeverityCritical, Package: "openssl", Version: "3.0.0", FixVersion: "3.0.1"}},
+			},
+		},
+		"NotScanned": {
+			reason: "We should return an error if Harbor hasn't scanned the artifact.",
+			args: args{
+				handler: func(w http.ResponseWriter, _ *http.Request) {
+					_, _ = w.Write([]byte(`{}`))
+				},
+				ref: name.MustParseReference("harbor.example.org/crossplane/provider-nop:v0.1.0"),
+			},
+			want: want{
+				err: errors.New(errNoVulnerabilityReport),
+			},
+		},
+		"NoProject": {
+			reason: "We should return an error if the repository isn't in a Harbor project.",
+			args: args{
+				ref: name.MustParseReference("harbor.example.org/provider-nop:v0.1.0"),
+			},
+			want: want{
+				err: errors.Errorf(errFmtHarborProject, "provider-nop"),
+			},
+		},
+		"Unauthorized": {
+			reason: "We should return an error if Harbor returns an unexpected status.",
+			args: args{
+				handler: func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusUnauthorized)
+				},
+				ref: name.MustParseReference("harbor.example.org/crossplane/provider-nop:v0.1.0"),
+			},
+			want: want{
+				err: errors.Errorf(errFmtScanStatus, "401 Unauthorized"),
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			srv := httptest.NewServer(tc.args.handler)
+			defer srv.Close()
+
+			s := NewHarborScanner(WithScanHTTPClient(srv.Client()))
+			vulns, err := s.Scan(context.Background(), tc.args.ref, srv.URL, tc.args.creds)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nScan(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.vulns, vulns); diff != "" {
+				t.Errorf("\n%s\nScan(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSeverityAtLeast(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		s      Severity
+		o      Severity
+		want   bool
+	}{
+		"MoreSevere": {
+			reason: "A critical vulnerability is at least high severity.",
+			s:      SeverityCritical,
+			o:      SeverityHigh,
+			want:   true,
+		},
+		"Equal": {
+			reason: "A high vulnerability is at least high severity.",
+			s:      SeverityHigh,
+			o:      SeverityHigh,
+			want:   true,
+		},
+		"LessSevere": {
+			reason: "A medium vulnerability is not at least high severity.",
+			s:      SeverityMedium,
+			o:      SeverityHigh,
+			want:   false,
+		},
+		"Unrecognized": {
+			reason: "An unrecognized severity is treated as unknown.",
+			s:      Severity("Negligible"),
+			o:      SeverityLow,
+			want:   false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.s.AtLeast(tc.o)); diff != "" {
+				t.Errorf("\n%s\nAtLeast(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
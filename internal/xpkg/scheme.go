@@ -17,18 +17,35 @@ limitations under the License.
 package xpkg
 
 import (
+	"context"
+	"io"
+
 	admv1 "k8s.io/api/admissionregistration/v1"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	extv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/parser"
+
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
 	pkgmetav1alpha1 "github.com/crossplane/crossplane/apis/pkg/meta/v1alpha1"
 	pkgmetav1beta1 "github.com/crossplane/crossplane/apis/pkg/meta/v1beta1"
 )
 
+// ProviderConfigKind is the kind of a provider's ProviderConfig. Configuration
+// packages may include ProviderConfigs of the API groups their provider
+// dependencies define.
+const ProviderConfigKind = "ProviderConfig"
+
+const (
+	errFmtUnsupportedMetaVersion = "package metadata apiVersion %q is not supported by this version of Crossplane"
+)
+
 // BuildMetaScheme builds the default scheme used for identifying metadata in a
 // Crossplane package.
 func BuildMetaScheme() (*runtime.Scheme, error) {
@@ -91,3 +108,82 @@ func TryConvertToPkg(obj runtime.Object, candidates ...conversion.Hub) (pkgmetav
 	m, ok := po.(pkgmetav1.Pkg)
 	return m, ok
 }
+
+// NewParser returns a package parser that parses metadata using the supplied
+// meta scheme and objects using the supplied object scheme. Metadata of any
+// supported version is converted to the hub (i.e. latest) version of its kind,
+// so that packages built with older metadata versions keep working. Metadata
+// of an unsupported version produces a descriptive error. ProviderConfigs that
+// aren't known to the object scheme are parsed as unstructured objects.
+func NewParser(metaScheme, objScheme parser.ObjectCreaterTyper) *MetaConvertingParser {
+	return &MetaConvertingParser{wrapped: parser.New(&metaVersionCheckingScheme{ObjectCreaterTyper: metaScheme}, &providerConfigScheme{ObjectCreaterTyper: objScheme})}
+}
+
+// A MetaConvertingParser parses packages, converting their metadata to the hub
+// version of its kind.
+type MetaConvertingParser struct {
+	wrapped parser.Parser
+}
+
+// Parse the supplied package.
+func (p *MetaConvertingParser) Parse(ctx context.Context, rc io.ReadCloser) (*parser.Package, error) {
+	pkg, err := p.wrapped.Parse(ctx, rc)
+	if err != nil {
+		return pkg, err
+	}
+
+	// GetMeta returns the package's underlying slice, so we can replace its
+	// metadata in place.
+	metas := pkg.GetMeta()
+	for i := range metas {
+		metas[i] = ConvertToHub(metas[i])
+	}
+	return pkg, nil
+}
+
+// ConvertToHub converts the supplied package metadata to the hub version of
+// its kind. Metadata that is already the hub version, or that can't be
+// converted, is returned unchanged.
+func ConvertToHub(obj runtime.Object) runtime.Object {
+	hub, ok := TryConvert(obj, &pkgmetav1.Provider{}, &pkgmetav1.Configuration{}, &pkgmetav1.Function{})
+	if !ok {
+		return obj
+	}
+	// Conversion copies the TypeMeta of the converted object, so we must
+	// update it to reflect the hub version.
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	hub.GetObjectKind().SetGroupVersionKind(pkgmetav1.SchemeGroupVersion.WithKind(gvk.Kind))
+	return hub
+}
+
+// A metaVersionCheckingScheme returns a descriptive error when asked to create
+// package metadata of a version it doesn't know about. Metadata of unknown
+// versions would otherwise be reported as an unknown kind of object.
+type metaVersionCheckingScheme struct {
+	parser.ObjectCreaterTyper
+}
+
+func (s *metaVersionCheckingScheme) New(gvk schema.GroupVersionKind) (runtime.Object, error) {
+	o, err := s.ObjectCreaterTyper.New(gvk)
+	if runtime.IsNotRegisteredError(err) && gvk.Group == pkgmetav1.Group {
+		return nil, errors.Errorf(errFmtUnsupportedMetaVersion, gvk.GroupVersion().String())
+	}
+	return o, err
+}
+
+// A providerConfigScheme creates unstructured objects for ProviderConfigs of
+// any API group. A ProviderConfig's type is defined by its provider, so it
+// can't be known to the object scheme.
+type providerConfigScheme struct {
+	parser.ObjectCreaterTyper
+}
+
+func (s *providerConfigScheme) New(gvk schema.GroupVersionKind) (runtime.Object, error) {
+	o, err := s.ObjectCreaterTyper.New(gvk)
+	if runtime.IsNotRegisteredError(err) && gvk.Kind == ProviderConfigKind {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+		return u, nil
+	}
+	return o, err
+}
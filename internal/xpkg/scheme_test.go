@@ -17,13 +17,22 @@ limitations under the License.
 package xpkg
 
 import (
+	"context"
+	"io"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
 )
 
 type mockHub struct{ runtime.Object }
@@ -104,3 +113,128 @@ func TestTryConvert(t *testing.T) {
 		})
 	}
 }
+
+func TestMetaConvertingParser(t *testing.T) {
+	type args struct {
+		pkg string
+	}
+
+	type want struct {
+		meta []runtime.Object
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"HubVersion": {
+			reason: "We should return metadata that is already the hub version unchanged.",
+			args: args{
+				pkg: `
+apiVersion: meta.pkg.crossplane.io/v1
+kind: Provider
+metadata:
+  name: provider-nop
+spec:
+  controller:
+    image: crossplane/provider-nop:v0.1.0
+`,
+			},
+			want: want{
+				meta: []runtime.Object{&pkgmetav1.Provider{
+					TypeMeta:   metav1.TypeMeta{APIVersion: "meta.pkg.crossplane.io/v1", Kind: pkgmetav1.ProviderKind},
+					ObjectMeta: metav1.ObjectMeta{Name: "provider-nop"},
+					Spec: pkgmetav1.ProviderSpec{
+						Controller: pkgmetav1.ControllerSpec{Image: ptr.To("crossplane/provider-nop:v0.1.0")},
+					},
+				}},
+			},
+		},
+		"OlderVersion": {
+			reason: "We should convert metadata of an older version to the hub version.",
+			args: args{
+				pkg: `
+apiVersion: meta.pkg.crossplane.io/v1alpha1
+kind: Configuration
+metadata:
+  name: getting-started
+spec:
+  crossplane:
+    version: ">=v1.0.0"
+`,
+			},
+			want: want{
+				meta: []runtime.Object{&pkgmetav1.Configuration{
+					TypeMeta:   metav1.TypeMeta{APIVersion: "meta.pkg.crossplane.io/v1", Kind: pkgmetav1.ConfigurationKind},
+					ObjectMeta: metav1.ObjectMeta{Name: "getting-started"},
+					Spec: pkgmetav1.ConfigurationSpec{
+						MetaSpec: pkgmetav1.MetaSpec{Crossplane: &pkgmetav1.CrossplaneConstraints{Version: ">=v1.0.0"}},
+					},
+				}},
+			},
+		},
+		"UnsupportedVersion": {
+			reason: "We should return a descriptive error if metadata is of an unsupported version.",
+			args: args{
+				pkg: `
+apiVersion: meta.pkg.crossplane.io/v2
+kind: Provider
+metadata:
+  name: provider-nop
+`,
+			},
+			want: want{
+				err: errors.Errorf(errFmtUnsupportedMetaVersion, "meta.pkg.crossplane.io/v2"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			metaScheme, _ := BuildMetaScheme()
+			objScheme, _ := BuildObjectScheme()
+			pkg, err := NewParser(metaScheme, objScheme).Parse(context.Background(), io.NopCloser(strings.NewReader(tc.args.pkg)))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nParse(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.meta, pkg.GetMeta()); diff != "" {
+				t.Errorf("\n%s\nParse(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestParseProviderConfig(t *testing.T) {
+	pkg := `
+apiVersion: nop.crossplane.io/v1alpha1
+kind: ProviderConfig
+metadata:
+  name: default
+spec:
+  credentials:
+    source: Secret
+`
+	want := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "nop.crossplane.io/v1alpha1",
+		"kind":       ProviderConfigKind,
+		"metadata":   map[string]any{"name": "default"},
+		"spec": map[string]any{
+			"credentials": map[string]any{"source": "Secret"},
+		},
+	}}
+
+	metaScheme, _ := BuildMetaScheme()
+	objScheme, _ := BuildObjectScheme()
+	p, err := NewParser(metaScheme, objScheme).Parse(context.Background(), io.NopCloser(strings.NewReader(pkg)))
+	if err != nil {
+		t.Fatalf("Parse(...): %v", err)
+	}
+	if diff := cmp.Diff([]runtime.Object{want}, p.GetObjects()); diff != "" {
+		t.Errorf("Parse(...): -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,464 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+const (
+	errNoImageSource            = "image source must specify one of secret, proxy, or path"
+	errSecretSourcesUnsupported = "cannot load package images from Secrets"
+	errPathSourcesUnsupported   = "cannot load package images from paths - no package image directory is configured"
+	errNoTags                   = "cannot list tags of a package image loaded from a tarball"
+	errDownloadImage            = "cannot download package image"
+
+	errFmtGetImageSecret       = "cannot get package image Secret %q"
+	errFmtNoImageSecretKey     = "package image Secret %q has no key %q"
+	errFmtLoadImageTarball     = "cannot load package image tarball from %q"
+	errFmtDescribeImage        = "cannot describe package image loaded from %q"
+	errFmtParseURLSource       = "cannot parse package URL %q"
+	errFmtURLSchemeUnsupported = "cannot load package images from %s:// URLs"
+	errFmtDownloadImageStatus  = "cannot download package image: %s"
+	errFmtDownloadImageTooBig  = "cannot download package image: it is larger than %d bytes"
+)
+
+// DefaultImageSecretKey is the Secret data key a package image is loaded from
+// if an ImageSource doesn't specify one.
+const DefaultImageSecretKey = "package.xpkg"
+
+// maxHTTPImageSize is the largest package image tarball that will be
+// downloaded from a URL. Tarballs are spooled to disk.
+const maxHTTPImageSize = 1 << 30
+
+// URL schemes package images can be loaded from, in addition to OCI
+// references.
+const (
+	SchemeHTTPS = "https"
+	SchemeFile  = "file"
+)
+
+// An ImageSourceFetcher returns Fetchers that fetch package images from an
+// alternative ImageSource, for clusters that can't pull packages from their
+// registry.
+type ImageSourceFetcher struct {
+	fetcher   Fetcher
+	client    kubernetes.Interface
+	namespace string
+	dir       string
+	schemes   map[string]URLFetcherFn
+}
+
+// A URLFetcherFn returns a Fetcher that fetches the package image at the
+// supplied URL.
+type URLFetcherFn func(u *url.URL) (Fetcher, error)
+
+// An ImageSourceFetcherOption configures an ImageSourceFetcher.
+type ImageSourceFetcherOption func(s *ImageSourceFetcher)
+
+// WithSecretImageSources allows package images to be loaded from Secrets in
+// the supplied namespace.
+func WithSecretImageSources(c kubernetes.Interface, namespace string) ImageSourceFetcherOption {
+	return func(s *ImageSourceFetcher) {
+		s.client = c
+		s.namespace = namespace
+	}
+}
+
+// WithPathImageSources allows package images to be loaded from tarballs in
+// the supplied directory, e.g. a pre-loaded volume. It also allows packages
+// whose source is a file:// URL to be loaded from the directory.
+func WithPathImageSources(dir string) ImageSourceFetcherOption {
+	return func(s *ImageSourceFetcher) {
+		s.dir = dir
+		if dir == "" {
+			return
+		}
+		s.schemes[SchemeFile] = func(u *url.URL) (Fetcher, error) {
+			// File URLs are relative to the package image directory, so
+			// file://provider.xpkg and file:///provider.xpkg are the same.
+			return &TarballFetcher{path: s.path(u.Host + u.Path)}, nil
+		}
+	}
+}
+
+// WithHTTPSSources allows packages whose source is an https:// URL to be
+// downloaded using the supplied client. Downloaded package images are spooled
+// to the supplied directory, or to the default temporary directory if it's
+// empty.
+func WithHTTPSSources(c *http.Client, dir string) ImageSourceFetcherOption {
+	descriptors := &httpDescriptors{byURL: map[string]httpDescriptor{}}
+	return WithURLSources(SchemeHTTPS, func(u *url.URL) (Fetcher, error) {
+		return &HTTPFetcher{client: c, url: u.String(), dir: dir, descriptors: descriptors}, nil
+	})
+}
+
+// WithURLSources allows packages whose source is a URL with the supplied
+// scheme to be fetched by the Fetcher the supplied function returns.
+func WithURLSources(scheme string, fn URLFetcherFn) ImageSourceFetcherOption {
+	return func(s *ImageSourceFetcher) {
+		s.schemes[scheme] = fn
+	}
+}
+
+// NewImageSourceFetcher returns an ImageSourceFetcher. Packages without an
+// ImageSource, and packages with a proxy ImageSource, are fetched using the
+// supplied Fetcher.
+func NewImageSourceFetcher(f Fetcher, opts ...ImageSourceFetcherOption) *ImageSourceFetcher {
+	s := &ImageSourceFetcher{fetcher: f, schemes: map[string]URLFetcherFn{}}
+	for _, fn := range opts {
+		fn(s)
+	}
+	return s
+}
+
+// For returns a Fetcher that fetches package images from the supplied source.
+// It returns the underlying Fetcher if the source is nil.
+func (s *ImageSourceFetcher) For(src *pkgv1.ImageSource) (Fetcher, error) {
+	switch {
+	case src == nil:
+		return s.fetcher, nil
+	case src.Secret != nil:
+		if s.client == nil {
+			return nil, errors.New(errSecretSourcesUnsupported)
+		}
+		key := src.Secret.Key
+		if key == "" {
+			key = DefaultImageSecretKey
+		}
+		return &SecretFetcher{client: s.client, namespace: s.namespace, name: src.Secret.Name, key: key}, nil
+	case src.Proxy != nil:
+		return NewProxyFetcher(s.fetcher, *src.Proxy), nil
+	case src.Path != nil:
+		if s.dir == "" {
+			return nil, errors.New(errPathSourcesUnsupported)
+		}
+		return &TarballFetcher{path: s.path(*src.Path)}, nil
+	}
+	return nil, errors.New(errNoImageSource)
+}
+
+// ForURL returns a Fetcher that fetches the package image at the supplied URL
+// source. The Fetcher ignores the reference it's asked to fetch, so callers
+// may pass a nil reference.
+func (s *ImageSourceFetcher) ForURL(source string) (Fetcher, error) {
+	u, err := url.Parse(source)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtParseURLSource, source)
+	}
+	fn, ok := s.schemes[u.Scheme]
+	if !ok {
+		return nil, errors.Errorf(errFmtURLSchemeUnsupported, u.Scheme)
+	}
+	return fn(u)
+}
+
+// path returns the supplied path within the package image directory.
+func (s *ImageSourceFetcher) path(p string) string {
+	// Cleaning the path as if it were absolute stops it escaping the
+	// package image directory.
+	return filepath.Join(s.dir, filepath.Clean("/"+p))
+}
+
+// IsURLSource returns true if the supplied package source is a URL, e.g.
+// https://example.org/provider.xpkg, rather than an OCI reference.
+func IsURLSource(source string) bool {
+	return strings.Contains(source, "://")
+}
+
+// A ProxyFetcher fetches package images from an in-cluster registry that
+// mirrors their upstream registry. Unlike a RemoteCacheFetcher it never
+// consults the upstream registry.
+type ProxyFetcher struct {
+	fetcher  Fetcher
+	registry string
+}
+
+// NewProxyFetcher returns a Fetcher that fetches package images from the
+// supplied proxy registry, using the supplied Fetcher.
+func NewProxyFetcher(f Fetcher, registry string) *ProxyFetcher {
+	return &ProxyFetcher{fetcher: f, registry: strings.TrimSuffix(registry, "/")}
+}
+
+// Fetch fetches a package image from the proxy registry.
+func (p *ProxyFetcher) Fetch(ctx context.Context, ref name.Reference, secrets ...string) (v1.Image, error) {
+	m, err := mirrored(p.registry, ref)
+	if err != nil {
+		return nil, err
+	}
+	return p.fetcher.Fetch(ctx, m, secrets...)
+}
+
+// Head fetches a package descriptor from the proxy registry.
+func (p *ProxyFetcher) Head(ctx context.Context, ref name.Reference, secrets ...string) (*v1.Descriptor, error) {
+	m, err := mirrored(p.registry, ref)
+	if err != nil {
+		return nil, err
+	}
+	return p.fetcher.Head(ctx, m, secrets...)
+}
+
+// Tags fetches a package's tags from the proxy registry.
+func (p *ProxyFetcher) Tags(ctx context.Context, ref name.Reference, secrets ...string) ([]string, error) {
+	m, err := mirrored(p.registry, ref)
+	if err != nil {
+		return nil, err
+	}
+	return p.fetcher.Tags(ctx, m, secrets...)
+}
+
+// A SecretFetcher loads a package image from a tarball stored in a Secret. It
+// ignores the reference and pull secrets of the package.
+type SecretFetcher struct {
+	client    kubernetes.Interface
+	namespace string
+	name      string
+	key       string
+}
+
+// Fetch loads the package image from the Secret.
+func (f *SecretFetcher) Fetch(ctx context.Context, _ name.Reference, _ ...string) (v1.Image, error) {
+	s, err := f.client.CoreV1().Secrets(f.namespace).Get(ctx, f.name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtGetImageSecret, f.name)
+	}
+	b, ok := s.Data[f.key]
+	if !ok {
+		return nil, errors.Errorf(errFmtNoImageSecretKey, f.name, f.key)
+	}
+	img, err := tarball.Image(func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(b)), nil }, nil)
+	return img, errors.Wrapf(err, errFmtLoadImageTarball, "secret/"+f.name)
+}
+
+// Head describes the package image loaded from the Secret.
+func (f *SecretFetcher) Head(ctx context.Context, ref name.Reference, secrets ...string) (*v1.Descriptor, error) {
+	img, err := f.Fetch(ctx, ref, secrets...)
+	if err != nil {
+		return nil, err
+	}
+	d, err := partial.Descriptor(img)
+	return d, errors.Wrapf(err, errFmtDescribeImage, "secret/"+f.name)
+}
+
+// Tags returns an error. A tarball contains a single image.
+func (f *SecretFetcher) Tags(_ context.Context, _ name.Reference, _ ...string) ([]string, error) {
+	return nil, errors.New(errNoTags)
+}
+
+// An HTTPFetcher downloads a package image tarball from a URL. It ignores the
+// reference of the package. The URL must not require credentials, so it also
+// ignores the package's pull secrets.
+type HTTPFetcher struct {
+	client      *http.Client
+	url         string
+	dir         string
+	descriptors *httpDescriptors
+}
+
+// Fetch downloads the package image.
+func (f *HTTPFetcher) Fetch(ctx context.Context, _ name.Reference, _ ...string) (v1.Image, error) {
+	img, _, err := f.fetch(ctx)
+	return img, err
+}
+
+// fetch downloads the package image, and returns the version of it that was
+// downloaded, if any.
+func (f *HTTPFetcher) fetch(ctx context.Context) (v1.Image, string, error) {
+	rsp, err := f.do(ctx, http.MethodGet)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rsp.Body.Close() //nolint:errcheck // Only error is that the body was already closed.
+
+	if f.dir != "" {
+		if err := os.MkdirAll(f.dir, 0o700); err != nil {
+			return nil, "", errors.Wrap(err, errDownloadImage)
+		}
+	}
+	tmp, err := os.CreateTemp(f.dir, "download-*.xpkg")
+	if err != nil {
+		return nil, "", errors.Wrap(err, errDownloadImage)
+	}
+
+	// The tarball is read more than once, e.g. to find its manifest and then
+	// its layers, so it's spooled to disk. We remove the file straight away.
+	// Its space is reclaimed once the image, which holds it open, is garbage
+	// collected. Read one byte more than we allow so that we can tell whether
+	// the tarball is too big.
+	_ = os.Remove(tmp.Name())
+	n, err := io.Copy(tmp, io.LimitReader(rsp.Body, maxHTTPImageSize+1))
+	if err != nil {
+		_ = tmp.Close()
+		return nil, "", errors.Wrap(err, errDownloadImage)
+	}
+	if n > maxHTTPImageSize {
+		_ = tmp.Close()
+		return nil, "", errors.Errorf(errFmtDownloadImageTooBig, maxHTTPImageSize)
+	}
+	img, err := tarball.Image(func() (io.ReadCloser, error) { return io.NopCloser(io.NewSectionReader(tmp, 0, n)), nil }, nil)
+	if err != nil {
+		_ = tmp.Close()
+		return nil, "", errors.Wrapf(err, errFmtLoadImageTarball, f.url)
+	}
+	return img, httpVersion(rsp.Header), nil
+}
+
+// Head describes the package image. It only downloads the image if it has
+// changed since it was last described, according to its ETag, or according to
+// its Last-Modified time and Content-Length if it has no strong ETag.
+func (f *HTTPFetcher) Head(ctx context.Context, _ name.Reference, _ ...string) (*v1.Descriptor, error) {
+	rsp, err := f.do(ctx, http.MethodHead)
+	if err != nil {
+		return nil, err
+	}
+	_ = rsp.Body.Close()
+	if d, ok := f.descriptors.Get(f.url, httpVersion(rsp.Header)); ok {
+		return d, nil
+	}
+
+	img, v, err := f.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	d, err := partial.Descriptor(img)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtDescribeImage, f.url)
+	}
+	f.descriptors.Set(f.url, v, d)
+	return d, nil
+}
+
+// Tags returns an error. A tarball contains a single image.
+func (f *HTTPFetcher) Tags(_ context.Context, _ name.Reference, _ ...string) ([]string, error) {
+	return nil, errors.New(errNoTags)
+}
+
+func (f *HTTPFetcher) do(ctx context.Context, method string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, f.url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, errDownloadImage)
+	}
+	rsp, err := f.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, errDownloadImage)
+	}
+	if rsp.StatusCode != http.StatusOK {
+		_ = rsp.Body.Close()
+		return nil, errors.Errorf(errFmtDownloadImageStatus, rsp.Status)
+	}
+	return rsp, nil
+}
+
+// httpVersion returns the version of a package image served with the supplied
+// HTTP response headers. Only a strong ETag, or a Last-Modified time together
+// with a Content-Length, identifies a version. It returns an empty string if
+// the version can't be identified.
+func httpVersion(h http.Header) string {
+	if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	lm, cl := h.Get("Last-Modified"), h.Get("Content-Length")
+	if lm == "" || cl == "" {
+		return ""
+	}
+	return lm + "/" + cl
+}
+
+// An httpDescriptor is the descriptor of a version of the package image that
+// was served at a URL.
+type httpDescriptor struct {
+	version    string
+	descriptor *v1.Descriptor
+}
+
+// httpDescriptors caches the descriptors of package images downloaded from
+// URLs, so that they needn't be downloaded again to be described.
+type httpDescriptors struct {
+	mx    sync.RWMutex
+	byURL map[string]httpDescriptor
+}
+
+// Get the cached descriptor of the package image at the supplied URL, if it
+// was cached with the supplied version. An empty version never matches.
+func (c *httpDescriptors) Get(url, version string) (*v1.Descriptor, bool) {
+	if version == "" {
+		return nil, false
+	}
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+	d, ok := c.byURL[url]
+	if !ok || d.version != version {
+		return nil, false
+	}
+	return d.descriptor, true
+}
+
+// Set the cached descriptor of the package image at the supplied URL.
+func (c *httpDescriptors) Set(url, version string, d *v1.Descriptor) {
+	if version == "" {
+		return
+	}
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.byURL[url] = httpDescriptor{version: version, descriptor: d}
+}
+
+// A TarballFetcher loads a package image from a tarball on disk. It ignores
+// the reference and pull secrets of the package.
+type TarballFetcher struct {
+	path string
+}
+
+// Fetch loads the package image from the tarball.
+func (f *TarballFetcher) Fetch(_ context.Context, _ name.Reference, _ ...string) (v1.Image, error) {
+	img, err := tarball.ImageFromPath(f.path, nil)
+	return img, errors.Wrapf(err, errFmtLoadImageTarball, f.path)
+}
+
+// Head describes the package image loaded from the tarball.
+func (f *TarballFetcher) Head(ctx context.Context, ref name.Reference, secrets ...string) (*v1.Descriptor, error) {
+	img, err := f.Fetch(ctx, ref, secrets...)
+	if err != nil {
+		return nil, err
+	}
+	d, err := partial.Descriptor(img)
+	return d, errors.Wrapf(err, errFmtDescribeImage, f.path)
+}
+
+// Tags returns an error. A tarball contains a single image.
+func (f *TarballFetcher) Tags(_ context.Context, _ name.Reference, _ ...string) ([]string, error) {
+	return nil, errors.New(errNoTags)
+}
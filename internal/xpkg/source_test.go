@@ -0,0 +1,437 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+var (
+	_ Fetcher = &ProxyFetcher{}
+	_ Fetcher = &SecretFetcher{}
+	_ Fetcher = &TarballFetcher{}
+	_ Fetcher = &HTTPFetcher{}
+)
+
+func TestImageSourceFetcherFor(t *testing.T) {
+	img, h := randomImage(t)
+
+	b := &bytes.Buffer{}
+	if err := tarball.Write(name.MustParseReference("xpkg.upbound.io/crossplane/provider-nop:v0.1.0"), img, b); err != nil {
+		t.Fatalf("tarball.Write(...): %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "provider-nop.xpkg"), b.Bytes(), 0o600); err != nil {
+		t.Fatalf("os.WriteFile(...): %v", err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "crossplane-system", Name: "provider-nop"},
+		Data:       map[string][]byte{DefaultImageSecretKey: b.Bytes()},
+	}
+
+	const ref = "xpkg.upbound.io/crossplane/provider-nop:v0.1.0"
+
+	type args struct {
+		src     *pkgv1.ImageSource
+		objects []runtime.Object
+		dir     string
+	}
+	type want struct {
+		digest  v1.Hash
+		fetched []string
+		err     bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoSource": {
+			reason: "Packages without an image source should be fetched from their registry.",
+			args:   args{},
+			want:   want{fetched: []string{ref}},
+		},
+		"Proxy": {
+			reason: "Packages with a proxy image source should be fetched from the proxy, prefixed with their upstream registry.",
+			args: args{
+				src: &pkgv1.ImageSource{Proxy: ptr.To("registry.crossplane-system:5000")},
+			},
+			want: want{fetched: []string{"registry.crossplane-system:5000/xpkg.upbound.io/crossplane/provider-nop:v0.1.0"}},
+		},
+		"Secret": {
+			reason: "Packages with a Secret image source should be loaded from the Secret.",
+			args: args{
+				src:     &pkgv1.ImageSource{Secret: &pkgv1.ImageSourceSecret{Name: "provider-nop"}},
+				objects: []runtime.Object{secret},
+			},
+			want: want{digest: h},
+		},
+		"SecretMissingKey": {
+			reason: "We should return an error if the Secret doesn't contain the image source key.",
+			args: args{
+				src:     &pkgv1.ImageSource{Secret: &pkgv1.ImageSourceSecret{Name: "provider-nop", Key: "nope"}},
+				objects: []runtime.Object{secret},
+			},
+			want: want{err: true},
+		},
+		"SecretNotFound": {
+			reason: "We should return an error if the Secret doesn't exist.",
+			args: args{
+				src: &pkgv1.ImageSource{Secret: &pkgv1.ImageSourceSecret{Name: "provider-nop"}},
+			},
+			want: want{err: true},
+		},
+		"Path": {
+			reason: "Packages with a path image source should be loaded from the package image directory.",
+			args: args{
+				src: &pkgv1.ImageSource{Path: ptr.To("provider-nop.xpkg")},
+				dir: dir,
+			},
+			want: want{digest: h},
+		},
+		"PathEscapesDir": {
+			reason: "A path image source shouldn't be able to escape the package image directory.",
+			args: args{
+				src: &pkgv1.ImageSource{Path: ptr.To("../../" + filepath.Base(dir) + "/provider-nop.xpkg")},
+				dir: filepath.Join(dir, "empty"),
+			},
+			want: want{err: true},
+		},
+		"PathUnsupported": {
+			reason: "We should return an error if a path image source is used without a package image directory.",
+			args: args{
+				src: &pkgv1.ImageSource{Path: ptr.To("provider-nop.xpkg")},
+			},
+			want: want{err: true},
+		},
+		"EmptySource": {
+			reason: "We should return an error if an image source doesn't specify a source.",
+			args: args{
+				src: &pkgv1.ImageSource{},
+			},
+			want: want{err: true},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			rf := &recordingFetcher{}
+			s := NewImageSourceFetcher(rf, WithSecretImageSources(fake.NewSimpleClientset(tc.args.objects...), "crossplane-system"), WithPathImageSources(tc.args.dir))
+
+			var got v1.Image
+			f, err := s.For(tc.args.src)
+			if err == nil {
+				got, err = f.Fetch(context.Background(), name.MustParseReference(ref))
+			}
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Errorf("\n%s\nFetch(...): -want error, +got error (%v):\n%s", tc.reason, err, diff)
+			}
+			if diff := cmp.Diff(tc.want.fetched, rf.fetched); diff != "" {
+				t.Errorf("\n%s\nFetch(...): -want fetched, +got fetched:\n%s", tc.reason, diff)
+			}
+			if tc.want.digest == (v1.Hash{}) {
+				return
+			}
+			d, err := got.Digest()
+			if err != nil {
+				t.Fatalf("got.Digest(): %v", err)
+			}
+			if diff := cmp.Diff(tc.want.digest, d); diff != "" {
+				t.Errorf("\n%s\nFetch(...): -want digest, +got digest:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestImageSourceFetcherForURL(t *testing.T) {
+	img, h := randomImage(t)
+
+	b := &bytes.Buffer{}
+	if err := tarball.Write(name.MustParseReference("xpkg.upbound.io/crossplane/provider-nop:v0.1.0"), img, b); err != nil {
+		t.Fatalf("tarball.Write(...): %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "provider-nop.xpkg"), b.Bytes(), 0o600); err != nil {
+		t.Fatalf("os.WriteFile(...): %v", err)
+	}
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/provider-nop.xpkg" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(b.Bytes())
+	}))
+	t.Cleanup(srv.Close)
+
+	type args struct {
+		source string
+		dir    string
+	}
+	type want struct {
+		digest v1.Hash
+		err    bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"HTTPS": {
+			reason: "Packages whose source is an https:// URL should be downloaded.",
+			args: args{
+				source: srv.URL + "/provider-nop.xpkg",
+			},
+			want: want{digest: h},
+		},
+		"HTTPSNotFound": {
+			reason: "We should return an error if a package can't be downloaded.",
+			args: args{
+				source: srv.URL + "/nope.xpkg",
+			},
+			want: want{err: true},
+		},
+		"File": {
+			reason: "Packages whose source is a file:// URL should be loaded from the package image directory.",
+			args: args{
+				source: "file:///provider-nop.xpkg",
+				dir:    dir,
+			},
+			want: want{digest: h},
+		},
+		"FileWithoutRoot": {
+			reason: "File URLs should be relative to the package image directory even if their path isn't absolute.",
+			args: args{
+				source: "file://provider-nop.xpkg",
+				dir:    dir,
+			},
+			want: want{digest: h},
+		},
+		"FileEscapesDir": {
+			reason: "A file:// URL shouldn't be able to escape the package image directory.",
+			args: args{
+				source: "file:///../" + filepath.Base(dir) + "/provider-nop.xpkg",
+				dir:    filepath.Join(dir, "empty"),
+			},
+			want: want{err: true},
+		},
+		"FileUnsupported": {
+			reason: "We should return an error if a file:// URL is used without a package image directory.",
+			args: args{
+				source: "file:///provider-nop.xpkg",
+			},
+			want: want{err: true},
+		},
+		"UnsupportedScheme": {
+			reason: "We should return an error if no Fetcher is registered for a URL's scheme.",
+			args: args{
+				source: "ftp://example.org/provider-nop.xpkg",
+				dir:    dir,
+			},
+			want: want{err: true},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			s := NewImageSourceFetcher(&NopFetcher{}, WithPathImageSources(tc.args.dir), WithHTTPSSources(srv.Client(), t.TempDir()))
+
+			var got *v1.Descriptor
+			f, err := s.ForURL(tc.args.source)
+			if err == nil {
+				got, err = f.Head(context.Background(), nil)
+			}
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Errorf("\n%s\nHead(...): -want error, +got error (%v):\n%s", tc.reason, err, diff)
+			}
+			if tc.want.digest == (v1.Hash{}) {
+				return
+			}
+			if diff := cmp.Diff(tc.want.digest, got.Digest); diff != "" {
+				t.Errorf("\n%s\nHead(...): -want digest, +got digest:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestHTTPFetcherHead(t *testing.T) {
+	img, h := randomImage(t)
+
+	b := &bytes.Buffer{}
+	if err := tarball.Write(name.MustParseReference("xpkg.upbound.io/crossplane/provider-nop:v0.1.0"), img, b); err != nil {
+		t.Fatalf("tarball.Write(...): %v", err)
+	}
+
+	type want struct {
+		digest v1.Hash
+		gets   int
+	}
+
+	cases := map[string]struct {
+		reason       string
+		etag         string
+		lastModified string
+		want         want
+	}{
+		"StrongETag": {
+			reason: "A package image with a strong ETag should only be downloaded once to be described.",
+			etag:   `"v1"`,
+			want:   want{digest: h, gets: 1},
+		},
+		"WeakETag": {
+			reason: "A package image with a weak ETag should be downloaded every time it's described.",
+			etag:   `W/"v1"`,
+			want:   want{digest: h, gets: 2},
+		},
+		"WeakETagLastModified": {
+			reason:       "A package image with a weak ETag and a Last-Modified time should only be downloaded once to be described.",
+			etag:         `W/"v1"`,
+			lastModified: "Wed, 21 Oct 2015 07:28:00 GMT",
+			want:         want{digest: h, gets: 1},
+		},
+		"LastModified": {
+			reason:       "A package image with a Last-Modified time should only be downloaded once to be described.",
+			lastModified: "Wed, 21 Oct 2015 07:28:00 GMT",
+			want:         want{digest: h, gets: 1},
+		},
+		"NoVersion": {
+			reason: "A package image without an ETag or Last-Modified time should be downloaded every time it's described.",
+			want:   want{digest: h, gets: 2},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			gets := 0
+			srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.etag != "" {
+					w.Header().Set("ETag", tc.etag)
+				}
+				if tc.lastModified != "" {
+					w.Header().Set("Last-Modified", tc.lastModified)
+				}
+				w.Header().Set("Content-Length", strconv.Itoa(b.Len()))
+				if r.Method == http.MethodGet {
+					gets++
+					_, _ = w.Write(b.Bytes())
+				}
+			}))
+			t.Cleanup(srv.Close)
+
+			dir := t.TempDir()
+			f, err := NewImageSourceFetcher(&NopFetcher{}, WithHTTPSSources(srv.Client(), dir)).ForURL(srv.URL + "/provider-nop.xpkg")
+			if err != nil {
+				t.Fatalf("ForURL(...): %v", err)
+			}
+			for range 2 {
+				d, err := f.Head(context.Background(), nil)
+				if err != nil {
+					t.Fatalf("Head(...): %v", err)
+				}
+				if diff := cmp.Diff(tc.want.digest, d.Digest); diff != "" {
+					t.Errorf("\n%s\nHead(...): -want digest, +got digest:\n%s", tc.reason, diff)
+				}
+			}
+			if diff := cmp.Diff(tc.want.gets, gets); diff != "" {
+				t.Errorf("\n%s\nHead(...): -want downloads, +got downloads:\n%s", tc.reason, diff)
+			}
+			if files, _ := os.ReadDir(dir); len(files) != 0 {
+				t.Errorf("\n%s\nHead(...): downloads should not be left in the download directory, found %d", tc.reason, len(files))
+			}
+		})
+	}
+}
+
+func TestIsURLSource(t *testing.T) {
+	cases := map[string]struct {
+		source string
+		want   bool
+	}{
+		"Reference":         {source: "xpkg.upbound.io/crossplane/provider-nop:v0.1.0", want: false},
+		"ReferenceWithPort": {source: "registry:5000/crossplane/provider-nop@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d1bf7b83e4e7e9b", want: false},
+		"HTTPS":             {source: "https://example.org/provider-nop.xpkg", want: true},
+		"File":              {source: "file:///provider-nop.xpkg", want: true},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, IsURLSource(tc.source)); diff != "" {
+				t.Errorf("IsURLSource(%q): -want, +got:\n%s", tc.source, diff)
+			}
+		})
+	}
+}
+
+func TestSecretFetcherHead(t *testing.T) {
+	img, h := randomImage(t)
+
+	b := &bytes.Buffer{}
+	if err := tarball.Write(name.MustParseReference("xpkg.upbound.io/crossplane/provider-nop:v0.1.0"), img, b); err != nil {
+		t.Fatalf("tarball.Write(...): %v", err)
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "crossplane-system", Name: "provider-nop"},
+		Data:       map[string][]byte{DefaultImageSecretKey: b.Bytes()},
+	}
+
+	f, err := NewImageSourceFetcher(&NopFetcher{}, WithSecretImageSources(fake.NewSimpleClientset(secret), "crossplane-system")).For(&pkgv1.ImageSource{Secret: &pkgv1.ImageSourceSecret{Name: "provider-nop"}})
+	if err != nil {
+		t.Fatalf("For(...): %v", err)
+	}
+
+	// The digest of an image loaded from a tarball should match the digest
+	// it had in its registry, so that it's revisioned the same way.
+	d, err := f.Head(context.Background(), name.MustParseReference("xpkg.upbound.io/crossplane/provider-nop:v0.1.0"))
+	if err != nil {
+		t.Fatalf("Head(...): %v", err)
+	}
+	if diff := cmp.Diff(h, d.Digest); diff != "" {
+		t.Errorf("Head(...): -want digest, +got digest:\n%s", diff)
+	}
+
+	_, err = f.Tags(context.Background(), name.MustParseReference("xpkg.upbound.io/crossplane/provider-nop:v0.1.0"))
+	if diff := cmp.Diff(errors.New(errNoTags), err, test.EquateErrors()); diff != "" {
+		t.Errorf("Tags(...): -want error, +got error:\n%s", diff)
+	}
+}
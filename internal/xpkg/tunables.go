@@ -0,0 +1,244 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+const (
+	errReadTunables      = "cannot read tunables file"
+	errParseTunables     = "cannot parse tunables file"
+	errFmtParseCacheSize = "cannot parse image cache max size %q"
+	errResizeImageCache  = "cannot resize image cache"
+)
+
+// defaultTunablesPollInterval is how often the tunables file is checked for
+// changes by default.
+const defaultTunablesPollInterval = 10 * time.Second
+
+// Tunables are package manager settings that may be changed while Crossplane
+// is running.
+type Tunables struct {
+	// ImageCacheMaxSize is the maximum size of the package image cache, e.g.
+	// 1Gi.
+	ImageCacheMaxSize *string `json:"imageCacheMaxSize,omitempty"`
+
+	// MaxConcurrentPackagePulls is the maximum number of requests to package
+	// registries that may be in flight at once. Zero means no limit.
+	MaxConcurrentPackagePulls *int `json:"maxConcurrentPackagePulls,omitempty"`
+
+	// MaxPackagePullRate is the maximum rate per second at which requests may
+	// be made to package registries. Zero means no limit.
+	MaxPackagePullRate *float64 `json:"maxPackagePullRate,omitempty"`
+
+	// PackagePullBurst is how many requests to package registries may be made
+	// at once in excess of MaxPackagePullRate.
+	PackagePullBurst *int `json:"packagePullBurst,omitempty"`
+
+	// RemoteCacheRegistry is a pull-through registry cache used to fetch
+	// packages before falling back to their upstream registry. An empty
+	// string disables the cache.
+	RemoteCacheRegistry *string `json:"remoteCacheRegistry,omitempty"`
+}
+
+// merge returns the tunables, with any that are unset taken from the supplied
+// defaults.
+func (t Tunables) merge(defaults Tunables) Tunables {
+	if t.ImageCacheMaxSize == nil {
+		t.ImageCacheMaxSize = defaults.ImageCacheMaxSize
+	}
+	if t.MaxConcurrentPackagePulls == nil {
+		t.MaxConcurrentPackagePulls = defaults.MaxConcurrentPackagePulls
+	}
+	if t.MaxPackagePullRate == nil {
+		t.MaxPackagePullRate = defaults.MaxPackagePullRate
+	}
+	if t.PackagePullBurst == nil {
+		t.PackagePullBurst = defaults.PackagePullBurst
+	}
+	if t.RemoteCacheRegistry == nil {
+		t.RemoteCacheRegistry = defaults.RemoteCacheRegistry
+	}
+	return t
+}
+
+// A TunablesReloader applies tunables read from a file, e.g. a mounted
+// ConfigMap, whenever the file changes or Crossplane receives a SIGHUP. This
+// allows a busy control plane to be tuned without restarting Crossplane, and
+// thus without a leader election.
+type TunablesReloader struct {
+	path     string
+	defaults Tunables
+	interval time.Duration
+	log      logging.Logger
+
+	cache   *FsImageCache
+	limiter *PullLimiter
+	remote  *RemoteCache
+
+	mu   sync.Mutex
+	last []byte
+}
+
+// A TunablesReloaderOption configures a TunablesReloader.
+type TunablesReloaderOption func(r *TunablesReloader)
+
+// WithTunablesLogger specifies how the TunablesReloader should log.
+func WithTunablesLogger(l logging.Logger) TunablesReloaderOption {
+	return func(r *TunablesReloader) {
+		r.log = l
+	}
+}
+
+// WithTunablesPollInterval specifies how often the TunablesReloader checks
+// whether its file has changed.
+func WithTunablesPollInterval(d time.Duration) TunablesReloaderOption {
+	return func(r *TunablesReloader) {
+		r.interval = d
+	}
+}
+
+// WithTunableImageCache specifies the image cache whose maximum size is
+// tunable.
+func WithTunableImageCache(c *FsImageCache) TunablesReloaderOption {
+	return func(r *TunablesReloader) {
+		r.cache = c
+	}
+}
+
+// WithTunablePullLimiter specifies the pull limiter whose limits are tunable.
+func WithTunablePullLimiter(l *PullLimiter) TunablesReloaderOption {
+	return func(r *TunablesReloader) {
+		r.limiter = l
+	}
+}
+
+// WithTunableRemoteCache specifies the remote cache whose registry is
+// tunable.
+func WithTunableRemoteCache(c *RemoteCache) TunablesReloaderOption {
+	return func(r *TunablesReloader) {
+		r.remote = c
+	}
+}
+
+// NewTunablesReloader returns a TunablesReloader that reads tunables from the
+// supplied file. Tunables the file doesn't set take the supplied defaults.
+func NewTunablesReloader(path string, defaults Tunables, o ...TunablesReloaderOption) *TunablesReloader {
+	r := &TunablesReloader{
+		path:     path,
+		defaults: defaults,
+		interval: defaultTunablesPollInterval,
+		log:      logging.NewNopLogger(),
+	}
+	for _, fn := range o {
+		fn(r)
+	}
+	return r
+}
+
+// Reload reads the tunables file and applies it if it has changed since it
+// was last applied.
+func (r *TunablesReloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, err := os.ReadFile(r.path)
+	if err != nil {
+		return errors.Wrap(err, errReadTunables)
+	}
+	if r.last != nil && bytes.Equal(b, r.last) {
+		return nil
+	}
+
+	t := Tunables{}
+	if err := yaml.UnmarshalStrict(b, &t); err != nil {
+		return errors.Wrap(err, errParseTunables)
+	}
+	if err := r.apply(t.merge(r.defaults)); err != nil {
+		return err
+	}
+	r.last = b
+	return nil
+}
+
+func (r *TunablesReloader) apply(t Tunables) error {
+	if r.cache != nil && t.ImageCacheMaxSize != nil {
+		q, err := resource.ParseQuantity(*t.ImageCacheMaxSize)
+		if err != nil {
+			return errors.Wrapf(err, errFmtParseCacheSize, *t.ImageCacheMaxSize)
+		}
+		if err := r.cache.SetMaxSize(q.Value()); err != nil {
+			return errors.Wrap(err, errResizeImageCache)
+		}
+		r.log.Info("Image cache resized", "max-size", q.String())
+	}
+	if r.limiter != nil {
+		concurrency, perSecond, burst := ptr.Deref(t.MaxConcurrentPackagePulls, 0), ptr.Deref(t.MaxPackagePullRate, 0), ptr.Deref(t.PackagePullBurst, 0)
+		r.limiter.SetLimits(concurrency, perSecond, burst)
+		r.log.Info("Package pull limits changed", "max-concurrent-pulls", concurrency, "max-pull-rate", perSecond, "burst", burst)
+	}
+	if r.remote != nil && t.RemoteCacheRegistry != nil {
+		r.remote.SetRegistry(*t.RemoteCacheRegistry)
+		r.log.Info("Remote cache registry changed", "registry", *t.RemoteCacheRegistry)
+	}
+	return nil
+}
+
+// Start reloading tunables whenever the file changes or a SIGHUP is received,
+// until the supplied context is done. Errors are logged, and the previously
+// applied tunables remain in effect.
+func (r *TunablesReloader) Start(ctx context.Context) error {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	t := time.NewTicker(r.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-hup:
+			r.log.Info("Received SIGHUP, reloading tunables", "path", r.path)
+		case <-t.C:
+		}
+		if err := r.Reload(); err != nil {
+			r.log.Info("Cannot reload tunables", "path", r.path, "error", err)
+		}
+	}
+}
+
+// NeedLeaderElection returns false, because every Crossplane replica pulls
+// packages and must apply the tunables.
+func (r *TunablesReloader) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/time/rate"
+	"k8s.io/utils/ptr"
+)
+
+func TestTunablesReloaderReload(t *testing.T) {
+	defaults := Tunables{
+		MaxConcurrentPackagePulls: ptr.To(0),
+		MaxPackagePullRate:        ptr.To(0.0),
+		PackagePullBurst:          ptr.To(10),
+		RemoteCacheRegistry:       ptr.To("cache.example.org"),
+	}
+
+	type want struct {
+		err        bool
+		registry   string
+		concurrent bool
+		rate       rate.Limit
+	}
+
+	cases := map[string]struct {
+		reason string
+		file   *string
+		want   want
+	}{
+		"NoFile": {
+			reason: "We should return an error, and leave the tunables as they were, if the file can't be read.",
+			want: want{
+				err:      true,
+				registry: "cache.example.org",
+				rate:     rate.Inf,
+			},
+		},
+		"UnknownTunable": {
+			reason: "We should return an error, and leave the tunables as they were, if the file sets an unknown tunable.",
+			file:   ptr.To("maxConcurrentReconciles: 10\n"),
+			want: want{
+				err:      true,
+				registry: "cache.example.org",
+				rate:     rate.Inf,
+			},
+		},
+		"Empty": {
+			reason: "Tunables the file doesn't set should take their defaults.",
+			file:   ptr.To(""),
+			want: want{
+				registry: "cache.example.org",
+				rate:     rate.Inf,
+			},
+		},
+		"Override": {
+			reason: "Tunables the file sets should override their defaults.",
+			file:   ptr.To("maxConcurrentPackagePulls: 5\nmaxPackagePullRate: 2.5\nremoteCacheRegistry: \"\"\n"),
+			want: want{
+				concurrent: true,
+				rate:       2.5,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "tunables.yaml")
+			if tc.file != nil {
+				if err := os.WriteFile(path, []byte(*tc.file), 0o600); err != nil {
+					t.Fatalf("os.WriteFile(...): %v", err)
+				}
+			}
+
+			rc := NewRemoteCache("cache.example.org")
+			pl := NewPullLimiter(0, 0, 10)
+			r := NewTunablesReloader(path, defaults, WithTunableRemoteCache(rc), WithTunablePullLimiter(pl))
+
+			err := r.Reload()
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Errorf("\n%s\nr.Reload(): -want error, +got error:\n%s\nerror: %v", tc.reason, diff, err)
+			}
+			if diff := cmp.Diff(tc.want.registry, rc.Registry()); diff != "" {
+				t.Errorf("\n%s\nr.Reload(): -want registry, +got registry:\n%s", tc.reason, diff)
+			}
+			rl, sem := pl.limits()
+			if diff := cmp.Diff(tc.want.concurrent, sem != nil); diff != "" {
+				t.Errorf("\n%s\nr.Reload(): -want concurrency limited, +got concurrency limited:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.rate, rl.Limit()); diff != "" {
+				t.Errorf("\n%s\nr.Reload(): -want rate, +got rate:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTunablesReloaderReloadUnchanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tunables.yaml")
+	if err := os.WriteFile(path, []byte("remoteCacheRegistry: cache.example.org\n"), 0o600); err != nil {
+		t.Fatalf("os.WriteFile(...): %v", err)
+	}
+
+	rc := NewRemoteCache("")
+	r := NewTunablesReloader(path, Tunables{}, WithTunableRemoteCache(rc))
+	if err := r.Reload(); err != nil {
+		t.Fatalf("r.Reload(): %v", err)
+	}
+
+	// Tunables shouldn't be applied again unless the file changes.
+	rc.SetRegistry("other.example.org")
+	if err := r.Reload(); err != nil {
+		t.Fatalf("r.Reload(): %v", err)
+	}
+	if diff := cmp.Diff("other.example.org", rc.Registry()); diff != "" {
+		t.Errorf("r.Reload(): unchanged file: -want registry, +got registry:\n%s", diff)
+	}
+
+	if err := os.WriteFile(path, []byte("remoteCacheRegistry: new.example.org\n"), 0o600); err != nil {
+		t.Fatalf("os.WriteFile(...): %v", err)
+	}
+	if err := r.Reload(); err != nil {
+		t.Fatalf("r.Reload(): %v", err)
+	}
+	if diff := cmp.Diff("new.example.org", rc.Registry()); diff != "" {
+		t.Errorf("r.Reload(): changed file: -want registry, +got registry:\n%s", diff)
+	}
+}
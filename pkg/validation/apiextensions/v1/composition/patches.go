@@ -506,6 +506,8 @@ func IsValidInputForTransform(t *v1.Transform, fromType v1.TransformIOType) erro
 		if _, err := composite.GetConversionFunc(t.Convert, fromType); err != nil {
 			return err
 		}
+	case v1.TransformTypeTemplate:
+		// any input type is valid
 	default:
 		return errors.Errorf("unknown transform type %s", t.Type)
 	}
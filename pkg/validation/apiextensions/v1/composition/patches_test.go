@@ -1031,6 +1031,16 @@ func TestIsValidInputForTransform(t *testing.T) {
 				},
 			},
 		},
+		"ValidTemplateTransformInputObject": {
+			reason: "Valid Template transformType should not return an error with input object",
+			args: args{
+				fromType: v1.TransformIOTypeObject,
+				t: &v1.Transform{
+					Type:     v1.TransformTypeTemplate,
+					Template: &v1.TemplateTransform{Text: "{{ .name }}"},
+				},
+			},
+		},
 		"ValidStringTransformInputObjectToJson": {
 			reason: "Valid String transformType should not return an error with input object if toJson",
 			args: args{
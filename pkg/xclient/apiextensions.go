@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xclient
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+const (
+	errListCompositionRevisions = "cannot list composition revisions"
+	errFmtNoCompositionRevision = "composition %s has no revisions"
+	errFmtXRDNotEstablished     = "composite resource definition %s did not become established"
+)
+
+// GetLatestCompositionRevision returns the latest revision of the supplied
+// Composition. It returns an error if the Composition has no revisions, e.g.
+// because it was just created.
+func GetLatestCompositionRevision(ctx context.Context, c client.Reader, comp *v1.Composition) (*v1.CompositionRevision, error) {
+	l := &v1.CompositionRevisionList{}
+	if err := c.List(ctx, l, client.MatchingLabels{v1.LabelCompositionName: comp.GetName()}); err != nil {
+		return nil, errors.Wrap(err, errListCompositionRevisions)
+	}
+	rev := v1.LatestRevision(comp, l.Items)
+	if rev == nil {
+		return nil, errors.Errorf(errFmtNoCompositionRevision, comp.GetName())
+	}
+	return rev, nil
+}
+
+// IsXRDEstablished returns true if the supplied CompositeResourceDefinition
+// has established the composite resource CRD it defines, and its composite
+// resources are being reconciled.
+func IsXRDEstablished(xrd *v1.CompositeResourceDefinition) bool {
+	return xrd.Status.GetCondition(v1.TypeEstablished).Status == corev1.ConditionTrue
+}
+
+// WaitForXRDEstablished gets the supplied CompositeResourceDefinition every
+// interval until it's established, or the supplied context is done. The
+// CompositeResourceDefinition is updated with the last state read from the
+// API server.
+func WaitForXRDEstablished(ctx context.Context, c client.Reader, xrd *v1.CompositeResourceDefinition, interval time.Duration) error {
+	err := waitFor(ctx, c, xrd, interval, func() bool { return IsXRDEstablished(xrd) })
+	return errors.Wrapf(err, errFmtXRDNotEstablished, xrd.GetName())
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestGetLatestCompositionRevision(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	comp := &v1.Composition{ObjectMeta: metav1.ObjectMeta{Name: "comp", UID: "comp-uid"}}
+	owner := []metav1.OwnerReference{{UID: comp.GetUID(), Controller: ptr.To(true)}}
+	rev1 := v1.CompositionRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: "comp-1", OwnerReferences: owner},
+		Spec:       v1.CompositionRevisionSpec{Revision: 1},
+	}
+	rev2 := v1.CompositionRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: "comp-2", OwnerReferences: owner},
+		Spec:       v1.CompositionRevisionSpec{Revision: 2},
+	}
+
+	type want struct {
+		rev *v1.CompositionRevision
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      client.Reader
+		want   want
+	}{
+		"ListError": {
+			reason: "We should return an error if we can't list revisions.",
+			c:      &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			want: want{
+				err: errors.Wrap(errBoom, errListCompositionRevisions),
+			},
+		},
+		"NoRevisions": {
+			reason: "We should return an error if the composition has no revisions.",
+			c:      &test.MockClient{MockList: test.NewMockListFn(nil)},
+			want: want{
+				err: errors.Errorf(errFmtNoCompositionRevision, "comp"),
+			},
+		},
+		"Success": {
+			reason: "We should return the revision with the highest revision number.",
+			c: &test.MockClient{MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+				o.(*v1.CompositionRevisionList).Items = []v1.CompositionRevision{rev2, rev1}
+				return nil
+			})},
+			want: want{
+				rev: &rev2,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rev, err := GetLatestCompositionRevision(context.Background(), tc.c, comp)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nGetLatestCompositionRevision(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.rev, rev); diff != "" {
+				t.Errorf("\n%s\nGetLatestCompositionRevision(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWaitForXRDEstablished(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		reason string
+		c      client.Reader
+		want   error
+	}{
+		"GetError": {
+			reason: "We should return an error if we can't get the XRD.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want:   errors.Wrapf(errors.Wrap(errBoom, errGetObject), errFmtXRDNotEstablished, "xrd"),
+		},
+		"NotEstablished": {
+			reason: "We should keep polling until the context is done if the XRD isn't established.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+			want:   errors.Wrapf(context.DeadlineExceeded, errFmtXRDNotEstablished, "xrd"),
+		},
+		"Established": {
+			reason: "We should return without error once the XRD is established.",
+			c: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+				o.(*v1.CompositeResourceDefinition).Status.SetConditions(v1.WatchingComposite())
+				return nil
+			})},
+			want: nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			xrd := &v1.CompositeResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "xrd"}}
+			err := WaitForXRDEstablished(ctx, tc.c, xrd, 10*time.Millisecond)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nWaitForXRDEstablished(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
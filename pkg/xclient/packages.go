@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xclient
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+const (
+	errListRevisions        = "cannot list package revisions"
+	errFmtUnknownPackage    = "unknown package type %T"
+	errFmtNoActiveRevision  = "package %s has no active revision"
+	errFmtPackageNotHealthy = "package %s did not become healthy"
+)
+
+// NewPackageRevisionList returns an empty list of the supplied package's
+// revisions, e.g. a ProviderRevisionList for a Provider.
+func NewPackageRevisionList(p v1.Package) (v1.PackageRevisionList, error) {
+	switch p.(type) {
+	case *v1.Provider:
+		return &v1.ProviderRevisionList{}, nil
+	case *v1.Configuration:
+		return &v1.ConfigurationRevisionList{}, nil
+	case *v1.Function:
+		return &v1.FunctionRevisionList{}, nil
+	default:
+		return nil, errors.Errorf(errFmtUnknownPackage, p)
+	}
+}
+
+// GetActiveRevision returns the active revision of the supplied package. A
+// package has at most one active revision. It returns an error if the package
+// has none, e.g. because it's still being installed.
+func GetActiveRevision(ctx context.Context, c client.Reader, p v1.Package) (v1.PackageRevision, error) {
+	l, err := NewPackageRevisionList(p)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.List(ctx, l, client.MatchingLabels{v1.LabelParentPackage: p.GetName()}); err != nil {
+		return nil, errors.Wrap(err, errListRevisions)
+	}
+	for _, r := range l.GetRevisions() {
+		if r.GetDesiredState() == v1.PackageRevisionActive {
+			return r, nil
+		}
+	}
+	return nil, errors.Errorf(errFmtNoActiveRevision, p.GetName())
+}
+
+// IsPackageHealthy returns true if the supplied package is installed and
+// healthy.
+func IsPackageHealthy(p v1.Package) bool {
+	return p.GetCondition(v1.TypeInstalled).Status == corev1.ConditionTrue &&
+		p.GetCondition(v1.TypeHealthy).Status == corev1.ConditionTrue
+}
+
+// WaitForPackageHealthy gets the supplied package every interval until it's
+// installed and healthy, or the supplied context is done. The package is
+// updated with the last state read from the API server.
+func WaitForPackageHealthy(ctx context.Context, c client.Reader, p v1.Package, interval time.Duration) error {
+	err := waitFor(ctx, c, p, interval, func() bool { return IsPackageHealthy(p) })
+	return errors.Wrapf(err, errFmtPackageNotHealthy, p.GetName())
+}
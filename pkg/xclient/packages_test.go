@@ -0,0 +1,179 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+func TestGetActiveRevision(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	inactive := v1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{Name: "inactive"}}
+	inactive.SetDesiredState(v1.PackageRevisionInactive)
+	active := v1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{Name: "active"}}
+	active.SetDesiredState(v1.PackageRevisionActive)
+
+	type args struct {
+		c client.Reader
+		p v1.Package
+	}
+	type want struct {
+		rev v1.PackageRevision
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"UnknownPackage": {
+			reason: "We should return an error if the package isn't a known type.",
+			args: args{
+				c: &test.MockClient{},
+				p: nil,
+			},
+			want: want{
+				err: errors.Errorf(errFmtUnknownPackage, nil),
+			},
+		},
+		"ListError": {
+			reason: "We should return an error if we can't list revisions.",
+			args: args{
+				c: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				p: &v1.Provider{ObjectMeta: metav1.ObjectMeta{Name: "p"}},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errListRevisions),
+			},
+		},
+		"NoActiveRevision": {
+			reason: "We should return an error if no revision is active.",
+			args: args{
+				c: &test.MockClient{MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+					o.(*v1.ProviderRevisionList).Items = []v1.ProviderRevision{inactive}
+					return nil
+				})},
+				p: &v1.Provider{ObjectMeta: metav1.ObjectMeta{Name: "p"}},
+			},
+			want: want{
+				err: errors.Errorf(errFmtNoActiveRevision, "p"),
+			},
+		},
+		"Success": {
+			reason: "We should return the active revision.",
+			args: args{
+				c: &test.MockClient{MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+					o.(*v1.ProviderRevisionList).Items = []v1.ProviderRevision{inactive, active}
+					return nil
+				})},
+				p: &v1.Provider{ObjectMeta: metav1.ObjectMeta{Name: "p"}},
+			},
+			want: want{
+				rev: &active,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rev, err := GetActiveRevision(context.Background(), tc.args.c, tc.args.p)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nGetActiveRevision(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.rev, rev); diff != "" {
+				t.Errorf("\n%s\nGetActiveRevision(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWaitForPackageHealthy(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	healthy := func(o client.Object) error {
+		p := o.(*v1.Provider)
+		p.SetConditions(v1.Active(), v1.Healthy())
+		return nil
+	}
+
+	type args struct {
+		c client.Reader
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"GetError": {
+			reason: "We should return an error if we can't get the package.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			},
+			want: errors.Wrapf(errors.Wrap(errBoom, errGetObject), errFmtPackageNotHealthy, "p"),
+		},
+		"NotFound": {
+			reason: "We should keep polling until the context is done if the package doesn't exist yet.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "p"))},
+			},
+			want: errors.Wrapf(context.DeadlineExceeded, errFmtPackageNotHealthy, "p"),
+		},
+		"NotHealthy": {
+			reason: "We should keep polling until the context is done if the package isn't healthy.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+			},
+			want: errors.Wrapf(context.DeadlineExceeded, errFmtPackageNotHealthy, "p"),
+		},
+		"Healthy": {
+			reason: "We should return without error once the package is healthy.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(nil, healthy)},
+			},
+			want: nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			p := &v1.Provider{ObjectMeta: metav1.ObjectMeta{Name: "p"}}
+			err := WaitForPackageHealthy(ctx, tc.args.c, p, 10*time.Millisecond)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nWaitForPackageHealthy(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
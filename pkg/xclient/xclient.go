@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package xclient provides a client, and typed helpers, for Go programs that
+// use Crossplane's APIs. The helpers accept any controller-runtime client, so
+// they work with cached clients too.
+package xclient
+
+import (
+	"context"
+	"time"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/crossplane/apis"
+)
+
+const (
+	errAddKubernetesTypes = "cannot add Kubernetes API types to scheme"
+	errAddExtensionsTypes = "cannot add CustomResourceDefinition API types to scheme"
+	errAddCrossplaneTypes = "cannot add Crossplane API types to scheme"
+	errBuildScheme        = "cannot build scheme"
+	errNewClient          = "cannot create client"
+	errGetObject          = "cannot get object"
+)
+
+// DefaultPollInterval is how often the Wait helpers check an object if they're
+// passed a zero interval.
+const DefaultPollInterval = 2 * time.Second
+
+// NewScheme returns a scheme that includes Crossplane's API types, and the
+// Kubernetes API types Crossplane uses.
+func NewScheme() (*runtime.Scheme, error) {
+	s := runtime.NewScheme()
+	if err := kscheme.AddToScheme(s); err != nil {
+		return nil, errors.Wrap(err, errAddKubernetesTypes)
+	}
+	if err := extv1.AddToScheme(s); err != nil {
+		return nil, errors.Wrap(err, errAddExtensionsTypes)
+	}
+	if err := apis.AddToScheme(s); err != nil {
+		return nil, errors.Wrap(err, errAddCrossplaneTypes)
+	}
+	return s, nil
+}
+
+// New returns a client for the API server the supplied config points to. The
+// client can read and write Crossplane's API types.
+func New(cfg *rest.Config) (client.Client, error) {
+	s, err := NewScheme()
+	if err != nil {
+		return nil, errors.Wrap(err, errBuildScheme)
+	}
+	c, err := client.New(cfg, client.Options{Scheme: s})
+	return c, errors.Wrap(err, errNewClient)
+}
+
+// waitFor gets the supplied object every interval until the supplied
+// condition is true, or the supplied context is done. An object that doesn't
+// exist yet is polled again; it may not have reached the client's cache.
+func waitFor(ctx context.Context, c client.Reader, o client.Object, interval time.Duration, done func() bool) error {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	return wait.PollUntilContextCancel(ctx, interval, true, func(ctx context.Context) (bool, error) {
+		if err := c.Get(ctx, client.ObjectKeyFromObject(o), o); err != nil {
+			return false, errors.Wrap(resource.IgnoreNotFound(err), errGetObject)
+		}
+		return done(), nil
+	})
+}